@@ -22,6 +22,12 @@ const (
 	Activate     ActivationState = "y"
 	Deactivate   ActivationState = "n"
 	AutoActivate ActivationState = "ay"
+	// ActivateExclusive activates logical volumes of shared volume groups on this host only,
+	// locked with an exclusive lock by lvmlockd. For local volume groups it is the same as Activate.
+	ActivateExclusive ActivationState = "ey"
+	// ActivateShared activates logical volumes of shared volume groups with a shared lock by lvmlockd,
+	// so that other hosts can activate them as well, e.g. for cluster file systems.
+	ActivateShared ActivationState = "sy"
 )
 
 func (opt ActivationState) ApplyToLVCreateOptions(opts *LVCreateOptions) {
@@ -32,6 +38,10 @@ func (opt ActivationState) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.ActivationState = opt
 }
 
+func (opt ActivationState) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.ActivationState = opt
+}
+
 func (opt ActivationState) ApplyToArgs(args Arguments) error {
 	if opt == "" {
 		return nil
@@ -20,6 +20,9 @@ import (
 	"fmt"
 )
 
+// ActivationMode determines which logical volumes are activated if physical volumes are missing.
+// ActivationModeDegraded activates raid logical volumes that are still usable with the remaining images,
+// ActivationModePartial activates any logical volume with missing parts replaced by error targets.
 type ActivationMode string
 
 const (
@@ -29,8 +32,12 @@ const (
 )
 
 func (opt ActivationMode) ApplyToArgs(args Arguments) error {
-	if opt == "" {
+	switch opt {
+	case "":
 		return nil
+	case ActivationModePartial, ActivationModeDegraded, ActivationModeComplete:
+	default:
+		return fmt.Errorf("invalid activation mode %q", string(opt))
 	}
 	args.AddOrReplace(fmt.Sprintf("--activationmode=%s", string(opt)))
 	return nil
@@ -39,3 +46,7 @@ func (opt ActivationMode) ApplyToArgs(args Arguments) error {
 func (opt ActivationMode) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.ActivationMode = opt
 }
+
+func (opt ActivationMode) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.ActivationMode = opt
+}
@@ -0,0 +1,47 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// ActivationSkip sets or clears the flag of a logical volume to be skipped during activation with --setactivationskip.
+// Logical volumes with the flag are only activated together with IgnoreActivationSkip,
+// which lvm2 sets on thin snapshots by default.
+type ActivationSkip string
+
+const (
+	SetActivationSkip   ActivationSkip = "y"
+	ClearActivationSkip ActivationSkip = "n"
+)
+
+func (opt ActivationSkip) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--setactivationskip=%s", string(opt)))
+	return nil
+}
+
+func (opt ActivationSkip) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.ActivationSkip = opt
+}
+
+func (opt ActivationSkip) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.ActivationSkip = opt
+}
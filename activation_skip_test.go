@@ -0,0 +1,52 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestActivationOptions(t *testing.T) {
+	t.Parallel()
+
+	args, err := VGChangeOptionsList{
+		VolumeGroupName("vg"), Activate, ActivationModeDegraded, IgnoreActivationSkip(true),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--activate y --activationmode=degraded --ignoreactivationskip") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+
+	args, err = LVChangeOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("snap"), ClearActivationSkip,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--setactivationskip=n") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+
+	if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), Activate, ActivationMode("forced")}).AsArgs(); err == nil {
+		t.Fatal("expected error for invalid activation mode")
+	}
+}
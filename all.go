@@ -0,0 +1,44 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// All applies the command to all volume groups instead of the given ones, e.g. with VGExport and VGImport.
+// With LVs and LVScan, All includes hidden logical volumes.
+type All bool
+
+func (opt All) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.All = opt
+}
+
+func (opt All) ApplyToLVScanOptions(opts *LVScanOptions) {
+	opts.All = opt
+}
+
+func (opt All) ApplyToVGExportOptions(opts *VGExportOptions) {
+	opts.All = opt
+}
+
+func (opt All) ApplyToVGImportOptions(opts *VGImportOptions) {
+	opts.All = opt
+}
+
+func (opt All) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--all"})
+	}
+	return nil
+}
@@ -43,6 +43,10 @@ func (opt AllocationPolicy) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 func (opt AllocationPolicy) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.AllocationPolicy = opt
 }
+func (opt AllocationPolicy) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.AllocationPolicy = opt
+}
+
 func (opt AllocationPolicy) ApplyToPVMoveOptions(opts *PVMoveOptions) {
 	opts.AllocationPolicy = opt
 }
@@ -44,15 +44,17 @@ type args struct {
 type ArgsType int8
 
 const (
-	ArgsTypeGeneric  ArgsType = iota
-	ArgsTypeLVs      ArgsType = iota
-	ArgsTypePVs      ArgsType = iota
-	ArgsTypeVGs      ArgsType = iota
-	ArgsTypeLVCreate ArgsType = iota
-	ArgsTypeLVChange ArgsType = iota
-	ArgsTypeVGCreate ArgsType = iota
-	ArgsTypeVGChange ArgsType = iota
-	ArgsTypeLVRename ArgsType = iota
+	ArgsTypeGeneric   ArgsType = iota
+	ArgsTypeLVs       ArgsType = iota
+	ArgsTypePVs       ArgsType = iota
+	ArgsTypeVGs       ArgsType = iota
+	ArgsTypeLVCreate  ArgsType = iota
+	ArgsTypeLVChange  ArgsType = iota
+	ArgsTypeVGCreate  ArgsType = iota
+	ArgsTypeVGChange  ArgsType = iota
+	ArgsTypeLVRename  ArgsType = iota
+	ArgsTypeLVConvert ArgsType = iota
+	ArgsTypePVChange  ArgsType = iota
 )
 
 func NewArgs(typ ArgsType) Arguments {
@@ -42,3 +42,7 @@ func (opt AutoActivation) ApplyToArgs(args Arguments) error {
 func (opt AutoActivation) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 	opts.AutoActivation = opt
 }
+
+func (opt AutoActivation) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.AutoActivation = opt
+}
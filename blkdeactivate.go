@@ -0,0 +1,225 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// ErrNoBlkDeactivateDevices is returned by BlkDeactivate if no devices are given,
+// as blkdeactivate would otherwise tear down all block devices of the host.
+var ErrNoBlkDeactivateDevices = errors.New("no devices given to deactivate")
+
+type (
+	BlkDeactivateOptions struct {
+		BlkDeactivateDevices
+		BlkDeactivateUmount
+		BlkDeactivateDMOptions
+		BlkDeactivateLVMOptions
+	}
+	BlkDeactivateOption interface {
+		ApplyToBlkDeactivateOptions(opts *BlkDeactivateOptions)
+	}
+	BlkDeactivateOptionsList []BlkDeactivateOption
+)
+
+// BlkDeactivateDevices are the devices to deactivate together with all devices stacked on top of them,
+// e.g. /dev/sdb or /dev/vg/lv.
+type BlkDeactivateDevices []string
+
+// BlkDeactivateUmount unmounts mounted devices before deactivating them.
+// Without it, mounted devices and the devices below them are skipped.
+type BlkDeactivateUmount bool
+
+// BlkDeactivateDMOption controls the deactivation of device-mapper devices.
+type BlkDeactivateDMOption string
+
+const (
+	// BlkDeactivateDMRetry retries the removal of device-mapper devices that are still in use for a few seconds.
+	BlkDeactivateDMRetry BlkDeactivateDMOption = "retry"
+	// BlkDeactivateDMForce replaces the table of device-mapper devices that cannot be removed with one that fails all I/O.
+	BlkDeactivateDMForce BlkDeactivateDMOption = "force"
+)
+
+// BlkDeactivateDMOptions are passed to blkdeactivate with -d.
+type BlkDeactivateDMOptions []BlkDeactivateDMOption
+
+// BlkDeactivateLVMOption controls the deactivation of logical volumes.
+type BlkDeactivateLVMOption string
+
+const (
+	// BlkDeactivateLVMRetry retries the deactivation of logical volumes that are still in use for a few seconds.
+	BlkDeactivateLVMRetry BlkDeactivateLVMOption = "retry"
+	// BlkDeactivateLVMWholeVG deactivates the whole volume group of a logical volume at once.
+	BlkDeactivateLVMWholeVG BlkDeactivateLVMOption = "wholevg"
+)
+
+// BlkDeactivateLVMOptions are passed to blkdeactivate with -l.
+type BlkDeactivateLVMOptions []BlkDeactivateLVMOption
+
+func (opt BlkDeactivateDevices) ApplyToBlkDeactivateOptions(opts *BlkDeactivateOptions) {
+	opts.BlkDeactivateDevices = opt
+}
+func (opt BlkDeactivateUmount) ApplyToBlkDeactivateOptions(opts *BlkDeactivateOptions) {
+	opts.BlkDeactivateUmount = opt
+}
+func (opt BlkDeactivateDMOptions) ApplyToBlkDeactivateOptions(opts *BlkDeactivateOptions) {
+	opts.BlkDeactivateDMOptions = opt
+}
+func (opt BlkDeactivateLVMOptions) ApplyToBlkDeactivateOptions(opts *BlkDeactivateOptions) {
+	opts.BlkDeactivateLVMOptions = opt
+}
+
+// BlkDeactivateStatus is the outcome of a step of blkdeactivate.
+type BlkDeactivateStatus string
+
+const (
+	BlkDeactivateStatusDone    BlkDeactivateStatus = "done"
+	BlkDeactivateStatusFailed  BlkDeactivateStatus = "failed"
+	BlkDeactivateStatusSkipped BlkDeactivateStatus = "skipping"
+)
+
+// BlkDeactivateStep is a single unmount or deactivation reported by blkdeactivate.
+type BlkDeactivateStep struct {
+	// Kind is the subsystem of the step, e.g. UMOUNT, LVM, DM, MD or VDO.
+	Kind string
+	// Description describes the step, e.g. "deactivating Volume Group vg".
+	Description string
+	Status      BlkDeactivateStatus
+}
+
+// BlkDeactivateError is returned by BlkDeactivate for every step that failed.
+type BlkDeactivateError struct {
+	Step BlkDeactivateStep
+}
+
+func (e *BlkDeactivateError) Error() string {
+	return fmt.Sprintf("blkdeactivate [%s]: %s failed", e.Step.Kind, e.Step.Description)
+}
+
+// blkDeactivateStepPattern matches the lines of blkdeactivate for a step, e.g.
+// "  [LVM]: deactivating Volume Group vg... done" or "  [SKIP]: unmount of vg-lv (dm-1) mounted on /mnt".
+var blkDeactivateStepPattern = regexp.MustCompile(`^\s*\[(\w+)]: (.*?)(?:\.\.\. (done|failed|skipping))?\s*$`)
+
+// BlkDeactivate unmounts and deactivates the given devices and all devices stacked on top of them,
+// such as logical volumes, device-mapper and MD devices, e.g. before a node is drained or a disk is removed.
+// The steps reported by blkdeactivate are returned in order, every failed step also
+// results in a BlkDeactivateError that can be inspected with errors.As.
+// The command is executed through the exec layer of the client, which has to implement RawCommandRunner,
+// with the context and lock of wrapped clients, e.g. without nsenter for clients created with WithNoNsenter.
+//
+// See man blkdeactivate for more information.
+func BlkDeactivate(ctx context.Context, client Client, opts ...BlkDeactivateOption) ([]BlkDeactivateStep, error) {
+	args, err := BlkDeactivateOptionsList(opts).Args()
+	if err != nil {
+		return nil, err
+	}
+	runner, ok := ClientImplements[RawCommandRunner](client)
+	if !ok {
+		return nil, fmt.Errorf("%w: client cannot run raw commands", errors.ErrUnsupported)
+	}
+
+	var steps []BlkDeactivateStep
+	runErr := runner.RunRaw(ctx, func(out io.Reader) error {
+		steps, err = parseBlkDeactivateOutput(out)
+		return err
+	}, append([]string{"blkdeactivate"}, args...)...)
+
+	var errs []error
+	for _, step := range steps {
+		if step.Status == BlkDeactivateStatusFailed {
+			errs = append(errs, &BlkDeactivateError{Step: step})
+		}
+	}
+	return steps, errors.Join(append(errs, runErr)...)
+}
+
+func parseBlkDeactivateOutput(out io.Reader) ([]BlkDeactivateStep, error) {
+	var steps []BlkDeactivateStep
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		match := blkDeactivateStepPattern.FindStringSubmatch(scanner.Text())
+		if match == nil {
+			continue
+		}
+		step := BlkDeactivateStep{Kind: match[1], Description: match[2], Status: BlkDeactivateStatus(match[3])}
+		if step.Kind == "SKIP" {
+			step.Status = BlkDeactivateStatusSkipped
+		}
+		steps = append(steps, step)
+	}
+	return steps, scanner.Err()
+}
+
+func (opts *BlkDeactivateOptions) ApplyToBlkDeactivateOptions(new *BlkDeactivateOptions) {
+	*new = *opts
+}
+
+// Args returns the arguments of blkdeactivate for the options.
+// They are not built with Arguments, as -d and -l take the same values, e.g. retry,
+// which Arguments would deduplicate.
+func (list BlkDeactivateOptionsList) Args() ([]string, error) {
+	options := BlkDeactivateOptions{}
+	for _, opt := range list {
+		opt.ApplyToBlkDeactivateOptions(&options)
+	}
+
+	if len(options.BlkDeactivateDevices) == 0 {
+		return nil, ErrNoBlkDeactivateDevices
+	}
+
+	var args []string
+	if options.BlkDeactivateUmount {
+		args = append(args, "-u")
+	}
+	if len(options.BlkDeactivateDMOptions) > 0 {
+		values := make([]string, 0, len(options.BlkDeactivateDMOptions))
+		for _, opt := range options.BlkDeactivateDMOptions {
+			if opt != BlkDeactivateDMRetry && opt != BlkDeactivateDMForce {
+				return nil, fmt.Errorf("unknown device-mapper option %q", string(opt))
+			}
+			values = append(values, string(opt))
+		}
+		args = append(args, "-d", strings.Join(values, ","))
+	}
+	if len(options.BlkDeactivateLVMOptions) > 0 {
+		values := make([]string, 0, len(options.BlkDeactivateLVMOptions))
+		for _, opt := range options.BlkDeactivateLVMOptions {
+			if opt != BlkDeactivateLVMRetry && opt != BlkDeactivateLVMWholeVG {
+				return nil, fmt.Errorf("unknown lvm option %q", string(opt))
+			}
+			values = append(values, string(opt))
+		}
+		args = append(args, "-l", strings.Join(values, ","))
+	}
+
+	// the devices are positional and come last
+	for _, device := range options.BlkDeactivateDevices {
+		if strings.HasPrefix(device, "-") {
+			return nil, fmt.Errorf("invalid device %q", device)
+		}
+		args = append(args, device)
+	}
+
+	return args, nil
+}
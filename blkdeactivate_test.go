@@ -0,0 +1,100 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestBlkDeactivate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	opts := []BlkDeactivateOption{
+		BlkDeactivateDevices{"/dev/sdb", "/dev/sdc"},
+		BlkDeactivateUmount(true),
+		BlkDeactivateDMOptions{BlkDeactivateDMRetry},
+		BlkDeactivateLVMOptions{BlkDeactivateLVMRetry, BlkDeactivateLVMWholeVG},
+	}
+	args, err := BlkDeactivateOptionsList(opts).Args()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"-u", "-d", "retry", "-l", "retry,wholevg", "/dev/sdb", "/dev/sdc"}
+	if !slices.Equal(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+
+	if _, err := BlkDeactivate(ctx, NewClient()); !errors.Is(err, ErrNoBlkDeactivateDevices) {
+		t.Fatalf("expected %v, got %v", ErrNoBlkDeactivateDevices, err)
+	}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "blkdeactivate",
+		Args:    expected,
+		Stdout: "Deactivating block devices:\n" +
+			"  [UMOUNT]: unmounting vg-lv (dm-1) mounted on /mnt... done\n" +
+			"  [SKIP]: unmount of vg-data (dm-2) mounted on /\n" +
+			"  [DM]: deactivating crypt device luks (dm-3)... failed\n" +
+			"  [LVM]: deactivating Volume Group vg... done\n",
+		ExitCode: 1,
+	}}))
+	steps, err := BlkDeactivate(ctx, clnt, opts...)
+	if err == nil {
+		t.Fatal("expected error for a failed step")
+	}
+	expectedSteps := []BlkDeactivateStep{
+		{Kind: "UMOUNT", Description: "unmounting vg-lv (dm-1) mounted on /mnt", Status: BlkDeactivateStatusDone},
+		{Kind: "SKIP", Description: "unmount of vg-data (dm-2) mounted on /", Status: BlkDeactivateStatusSkipped},
+		{Kind: "DM", Description: "deactivating crypt device luks (dm-3)", Status: BlkDeactivateStatusFailed},
+		{Kind: "LVM", Description: "deactivating Volume Group vg", Status: BlkDeactivateStatusDone},
+	}
+	if !slices.Equal(steps, expectedSteps) {
+		t.Fatalf("expected %+v, got %+v", expectedSteps, steps)
+	}
+	var deactivateErr *BlkDeactivateError
+	if !errors.As(err, &deactivateErr) || deactivateErr.Step != expectedSteps[2] {
+		t.Fatalf("expected BlkDeactivateError for %+v, got %v", expectedSteps[2], err)
+	}
+}
+
+func TestBlkDeactivateWithNoNsenter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var commands [][]string
+	clnt := NewLockingClient(WithNoNsenter(NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		commands = append(commands, cmd.Args)
+		return io.NopCloser(strings.NewReader("  [LVM]: deactivating Volume Group vg... done\n")), nil
+	}))))
+
+	if _, err := BlkDeactivate(ctx, clnt, BlkDeactivateDevices{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"blkdeactivate", "/dev/sdb"}}
+	if !slices.EqualFunc(commands, expected, slices.Equal) {
+		t.Fatalf("expected blkdeactivate to run without nsenter, got %v", commands)
+	}
+}
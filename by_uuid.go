@@ -0,0 +1,98 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/azalio/lvm2go/lvmerrors"
+)
+
+var ErrUUIDRequired = errors.New("UUID is required")
+
+// ByUUID limits a report to the volume group, logical volume or physical volume with the UUID.
+// Unlike the names of devices, UUIDs are stable across reboots and renames.
+// It is passed to lvm as selection on lv_uuid, vg_uuid or pv_uuid, combined with Select if both are set.
+type ByUUID string
+
+func (opt ByUUID) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.ByUUID = opt
+}
+func (opt ByUUID) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.ByUUID = opt
+}
+func (opt ByUUID) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.ByUUID = opt
+}
+
+// selectOn combines the selection of the UUID on the field with the selection of the options.
+func (opt ByUUID) selectOn(field ReportField, sel Select) Select {
+	if opt == "" {
+		return sel
+	}
+	if sel == "" {
+		return field.Eq(string(opt))
+	}
+	return sel.And(field.Eq(string(opt)))
+}
+
+// LVByUUID returns the logical volume with the UUID.
+func LVByUUID(ctx context.Context, client Client, uuid string, opts ...LVsOption) (*LogicalVolume, error) {
+	if uuid == "" {
+		return nil, ErrUUIDRequired
+	}
+	lvs, err := client.LVs(ctx, append(opts, ByUUID(uuid))...)
+	if err != nil {
+		return nil, err
+	}
+	if len(lvs) == 0 {
+		return nil, fmt.Errorf("%w: no logical volume with UUID %s", ErrLogicalVolumeNotFound, uuid)
+	}
+	return lvs[0], nil
+}
+
+// VGByUUID returns the volume group with the UUID.
+func VGByUUID(ctx context.Context, client Client, uuid string, opts ...VGsOption) (*VolumeGroup, error) {
+	if uuid == "" {
+		return nil, ErrUUIDRequired
+	}
+	vgs, err := client.VGs(ctx, append(opts, ByUUID(uuid))...)
+	if err != nil {
+		return nil, err
+	}
+	if len(vgs) == 0 {
+		return nil, fmt.Errorf("%w: no volume group with UUID %s", ErrVolumeGroupNotFound, uuid)
+	}
+	return vgs[0], nil
+}
+
+// PVByUUID returns the physical volume with the UUID.
+func PVByUUID(ctx context.Context, client Client, uuid string, opts ...PVsOption) (*PhysicalVolume, error) {
+	if uuid == "" {
+		return nil, ErrUUIDRequired
+	}
+	pvs, err := client.PVs(ctx, append(opts, ByUUID(uuid))...)
+	if err != nil {
+		return nil, err
+	}
+	if len(pvs) == 0 {
+		return nil, fmt.Errorf("%w: no physical volume with UUID %s", lvmerrors.ErrDeviceNotFound, uuid)
+	}
+	return pvs[0], nil
+}
@@ -0,0 +1,84 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestByUUID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var args []string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		args = cmd.Args
+		return io.NopCloser(strings.NewReader(`{"report":[{}]}`)), nil
+	}))
+	if _, err := clnt.LVs(ctx, ByUUID("abc-123")); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args, "--select=lv_uuid=abc-123") {
+		t.Fatalf("expected selection of the UUID in %v", args)
+	}
+	if _, err := clnt.VGs(ctx, ByUUID("abc-123"), VGFree.Gt("1G")); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(args, func(arg string) bool {
+		return strings.HasPrefix(arg, "--select=") && strings.Contains(arg, "vg_uuid=abc-123") && strings.Contains(arg, "vg_free")
+	}) {
+		t.Fatalf("expected the selection of the UUID to be combined with Select in %v", args)
+	}
+	if _, err := PVByUUID(ctx, clnt, "abc-123"); !IsNotFound(err) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	fakeClient := fake.NewClient()
+	fakeClient.SetDevice("/dev/sdb", MustParseSize("1G"))
+	if err := fakeClient.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := fakeClient.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	lv, err := fakeClient.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	byUUID, err := LVByUUID(ctx, fakeClient, lv.UUID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if byUUID.Name != "lv" {
+		t.Fatalf("unexpected logical volume %s", byUUID.Name)
+	}
+	if _, err := LVByUUID(ctx, fakeClient, "unknown"); !errors.Is(err, ErrLogicalVolumeNotFound) {
+		t.Fatalf("expected %v, got %v", ErrLogicalVolumeNotFound, err)
+	}
+	if _, err := VGByUUID(ctx, fakeClient, ""); !errors.Is(err, ErrUUIDRequired) {
+		t.Fatalf("expected %v, got %v", ErrUUIDRequired, err)
+	}
+}
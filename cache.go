@@ -0,0 +1,178 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// CachePool is the cache pool used by LVConvert to cache a logical volume with TypeCache.
+type CachePool FQLogicalVolumeName
+
+func (opt *CachePool) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.CachePool = opt
+}
+
+func (opt *CachePool) ApplyToArgs(args Arguments) error {
+	if opt == nil {
+		return nil
+	}
+
+	if err := (*FQLogicalVolumeName)(opt).Validate(); err != nil {
+		return err
+	}
+
+	args.AddOrReplace(fmt.Sprintf("--cachepool=%s/%s", opt.VolumeGroupName, opt.LogicalVolumeName))
+
+	return nil
+}
+
+func NewCachePool(vg VolumeGroupName, lv LogicalVolumeName) (*CachePool, error) {
+	fq, err := NewFQLogicalVolumeName(vg, lv)
+	if err != nil {
+		return nil, err
+	}
+	return (*CachePool)(fq), fq.Validate()
+}
+
+// CacheVolume is the fast logical volume used by LVConvert to cache a logical volume
+// with TypeCache or TypeWriteCache without creating a cache pool first.
+type CacheVolume FQLogicalVolumeName
+
+func (opt *CacheVolume) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.CacheVolume = opt
+}
+
+func (opt *CacheVolume) ApplyToArgs(args Arguments) error {
+	if opt == nil {
+		return nil
+	}
+
+	if err := (*FQLogicalVolumeName)(opt).Validate(); err != nil {
+		return err
+	}
+
+	args.AddOrReplace(fmt.Sprintf("--cachevol=%s/%s", opt.VolumeGroupName, opt.LogicalVolumeName))
+
+	return nil
+}
+
+func NewCacheVolume(vg VolumeGroupName, lv LogicalVolumeName) (*CacheVolume, error) {
+	fq, err := NewFQLogicalVolumeName(vg, lv)
+	if err != nil {
+		return nil, err
+	}
+	return (*CacheVolume)(fq), fq.Validate()
+}
+
+type CacheMode string
+
+const (
+	CacheModeWriteThrough CacheMode = "writethrough"
+	CacheModeWriteBack    CacheMode = "writeback"
+	CacheModePassThrough  CacheMode = "passthrough"
+)
+
+func (opt CacheMode) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.CacheMode = opt
+}
+
+func (opt CacheMode) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--cachemode=%s", string(opt)))
+	return nil
+}
+
+// CachePolicy is the policy of the dm-cache target deciding which blocks are promoted to the cache.
+type CachePolicy string
+
+const (
+	CachePolicySMQ     CachePolicy = "smq"
+	CachePolicyMQ      CachePolicy = "mq"
+	CachePolicyCleaner CachePolicy = "cleaner"
+)
+
+func (opt CachePolicy) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.CachePolicy = opt
+}
+
+func (opt CachePolicy) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--cachepolicy=%s", string(opt)))
+	return nil
+}
+
+// CacheSettings are the tunables of the cache policy (TypeCache) or of dm-writecache (TypeWriteCache),
+// e.g. migration_threshold or high_watermark.
+type CacheSettings map[string]string
+
+func (opt CacheSettings) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.CacheSettings = opt
+}
+
+func (opt CacheSettings) ApplyToArgs(args Arguments) error {
+	if len(opt) == 0 {
+		return nil
+	}
+	settings := make([]string, 0, len(opt))
+	for key, value := range opt {
+		if key == "" || strings.ContainsAny(key, "= ") || strings.ContainsAny(value, "= ") {
+			return fmt.Errorf("invalid cache setting %q=%q", key, value)
+		}
+		settings = append(settings, fmt.Sprintf("%s=%s", key, value))
+	}
+	// sorted for stable arguments, as map iteration is random
+	slices.Sort(settings)
+	args.AddOrReplace(fmt.Sprintf("--cachesettings=%s", strings.Join(settings, " ")))
+	return nil
+}
+
+// SplitCache detaches the cache pool or cache volume from a cached logical volume with LVConvert.
+// Dirty blocks are written back to the origin first, the cache pool or cache volume is kept for reuse.
+type SplitCache bool
+
+func (opt SplitCache) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.SplitCache = opt
+}
+
+func (opt SplitCache) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--splitcache"})
+	}
+	return nil
+}
+
+// Uncache detaches the cache from a cached logical volume with LVConvert like SplitCache,
+// but removes the cache pool or cache volume afterward.
+type Uncache bool
+
+func (opt Uncache) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Uncache = opt
+}
+
+func (opt Uncache) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--uncache"})
+	}
+	return nil
+}
@@ -18,21 +18,52 @@ package lvm2go
 
 import (
 	"context"
-	"errors"
 	"io"
+
+	"github.com/azalio/lvm2go/lvmerrors"
 )
 
 var (
-	ErrVolumeGroupNotFound   = errors.New("volume group not found")
-	ErrLogicalVolumeNotFound = errors.New("logical volume not found")
+	ErrVolumeGroupNotFound   = lvmerrors.ErrVolumeGroupNotFound
+	ErrLogicalVolumeNotFound = lvmerrors.ErrLogicalVolumeNotFound
 )
 
-type client struct{}
+type client struct {
+	opts ClientOptions
+
+	// strategy is selected from the ExecutionStrategies of the options, nil without them.
+	strategy    ExecutionStrategy
+	strategyErr error
+}
 
 var _ Client = (*client)(nil)
 
-func NewClient() Client {
-	return &client{}
+// NewClient returns a new Client configured with the given options.
+// Without options, the client uses the package level defaults.
+//
+// Example usage:
+//
+//	client := lvm2go.NewClient(
+//		lvm2go.LVMPath("/usr/sbin/lvm"),
+//		lvm2go.StandardLocale(true),
+//		lvm2go.ProcessCancelWaitDelay(5*time.Second),
+//		lvm2go.NsenterPolicyNever,
+//	)
+func NewClient(opts ...ClientOption) Client {
+	c := &client{}
+	ClientOptionList(opts).ApplyToClientOptions(&c.opts)
+	if c.opts.ExecutionStrategies != nil {
+		c.strategy, c.strategyErr = SelectExecutionStrategy(context.Background(), c.lvmPath(), c.opts.ExecutionStrategies...)
+		if c.strategyErr != nil {
+			c.logger().Warn("no execution strategy is available", "error", c.strategyErr)
+		} else {
+			c.logger().Debug("selected execution strategy", "strategy", c.strategy.Name())
+		}
+	}
+	if c.opts.ManagedTag != "" {
+		return NewTagGuardClient(c, string(c.opts.ManagedTag))
+	}
+	return c
 }
 
 // WithNoNsenter returns a new client that will force all operations to not use nsenter,
@@ -80,6 +111,13 @@ type MetaClient interface {
 	// See man lvm config for more information.
 	RawConfig(ctx context.Context, opts ...ConfigOption) (RawConfig, error)
 
+	// FullReport returns the volume groups, physical volumes, logical volumes and their segments
+	// matching the given options from a single report, correlated into a tree.
+	// Compared to calling PVs, VGs and LVs separately, it runs a single command and reports a consistent state.
+	//
+	// See man lvm fullreport for more information.
+	FullReport(ctx context.Context, opts ...FullReportOption) (*FullReport, error)
+
 	// ReadAndDecodeConfig requests and decodes configuration values from lvm2 formatted files.
 	// The configuration values are decoded into the given value v.
 	// If the configuration cannot be determined, an error is returned.
@@ -194,6 +232,20 @@ type MetaClient interface {
 	//
 	// See man lvm and man lvmconfig for more information.
 	GetProfileDirectory(ctx context.Context) (string, error)
+
+	// Defaults returns the effective settings used for commands run with the given context.
+	// It resolves the precedence between values stored in the context, the ClientOptions
+	// of the client and the package level defaults.
+	// This is useful for debugging purposes to verify which settings are used for a command.
+	//
+	// See Defaults for more information on the precedence.
+	Defaults(ctx context.Context) Defaults
+
+	// LVMDump collects diagnostics of the lvm2 subsystem with the given options and returns the path
+	// of the created tarball, or of the dump directory with LVMDumpDirectory.
+	//
+	// See man lvmdump for more information.
+	LVMDump(ctx context.Context, opts ...LVMDumpOption) (string, error)
 }
 
 // VolumeGroupClient is a client that provides operations on lvm2 volume groups.
@@ -241,6 +293,55 @@ type VolumeGroupClient interface {
 	// See man lvm vgrename for more information.
 	VGRename(ctx context.Context, opts ...VGRenameOption) error
 
+	// VGSplit moves physical volumes and the logical volumes on them from one volume group
+	// into a new or existing volume group with the given options.
+	//
+	// See man lvm vgsplit for more information.
+	VGSplit(ctx context.Context, opts ...VGSplitOption) error
+
+	// VGMerge merges an inactive volume group into another volume group with the given options.
+	// The first VolumeGroupName is the source, the second the destination.
+	//
+	// See man lvm vgmerge for more information.
+	VGMerge(ctx context.Context, opts ...VGMergeOption) error
+
+	// VGExport exports volume groups with the given options, so that their disks can be moved to another host.
+	//
+	// See man lvm vgexport for more information.
+	VGExport(ctx context.Context, opts ...VGExportOption) error
+
+	// VGImport imports exported volume groups with the given options.
+	//
+	// See man lvm vgimport for more information.
+	VGImport(ctx context.Context, opts ...VGImportOption) error
+
+	// VGImportClone imports a volume group from duplicated physical volumes with the given options,
+	// giving it new UUIDs and a new name.
+	//
+	// See man lvm vgimportclone for more information.
+	VGImportClone(ctx context.Context, opts ...VGImportCloneOption) error
+
+	// VGCfgBackup backs up the metadata of volume groups with the given options.
+	//
+	// See man lvm vgcfgbackup for more information.
+	VGCfgBackup(ctx context.Context, opts ...VGCfgBackupOption) error
+
+	// VGCfgRestore restores the metadata of a volume group from a backup with the given options.
+	//
+	// See man lvm vgcfgrestore for more information.
+	VGCfgRestore(ctx context.Context, opts ...VGCfgRestoreOption) error
+
+	// VGCheck checks the consistency of the metadata of volume groups with the given options.
+	// If vgck fails, the problems it reported are returned together with the error.
+	//
+	// See man lvm vgck for more information.
+	VGCheck(ctx context.Context, opts ...VGCheckOption) (*VGCheckResult, error)
+
+	// VGScan scans all devices for volume groups with the given options.
+	//
+	// See man lvm vgscan for more information.
+	VGScan(ctx context.Context, opts ...VGScanOption) error
+
 	// VGChange changes a volume group with the given options.
 	//
 	// See man lvm vgchange for more information.
@@ -268,6 +369,14 @@ type LogicalVolumeClient interface {
 	// See man lvm lvs for more information.
 	LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalVolume, error)
 
+	// LVSegments return a list of the segments of the logical volumes that match the given options.
+	// Each segment maps a range of logical extents to its layout and the physical extents it occupies.
+	//
+	// If no segments are found, an empty slice is returned.
+	//
+	// See man lvm lvs and the --segments option for more information.
+	LVSegments(ctx context.Context, opts ...LVsOption) ([]*LogicalVolumeSegment, error)
+
 	// LVCreate creates a new logical volume with the given options.
 	//
 	// See man lvm lvcreate for more information.
@@ -302,6 +411,17 @@ type LogicalVolumeClient interface {
 	//
 	// See man lvm lvchange for more information.
 	LVChange(ctx context.Context, opts ...LVChangeOption) error
+
+	// LVConvert converts a logical volume to a different type, e.g. to a thin pool, cache or raid1,
+	// merges a snapshot into its origin or repairs a logical volume with the given options.
+	//
+	// See man lvm lvconvert for more information.
+	LVConvert(ctx context.Context, opts ...LVConvertOption) error
+
+	// LVScan scans all volume groups for logical volumes with the given options.
+	//
+	// See man lvm lvscan for more information.
+	LVScan(ctx context.Context, opts ...LVScanOption) error
 }
 
 // PhysicalVolumeClient is a client that provides operations on lvm2 physical volumes.
@@ -315,6 +435,15 @@ type PhysicalVolumeClient interface {
 	// See man lvm pvs for more information.
 	PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume, error)
 
+	// PVSegments return a list of physical volume segments that match the given options.
+	// Each segment maps a range of physical extents to the logical extents of a logical volume
+	// or marks them as free, equivalent to the output of pvdisplay --maps.
+	//
+	// If no segments are found, an empty slice is returned.
+	//
+	// See man lvm pvs and the --segments option for more information.
+	PVSegments(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolumeSegment, error)
+
 	// PVCreate creates a new physical volume with the given options.
 	//
 	// See man lvm pvcreate for more information.
@@ -339,6 +468,18 @@ type PhysicalVolumeClient interface {
 	//
 	// see man lvm pvmove for more information.
 	PVMove(ctx context.Context, opts ...PVMoveOption) error
+
+	// PVCheck checks, dumps or repairs the lvm2 headers and metadata of a physical volume with the given options.
+	// If pvck fails, the problems it reported are returned together with the error.
+	//
+	// See man lvm pvck for more information.
+	PVCheck(ctx context.Context, opts ...PVCheckOption) (*PVCheckResult, error)
+
+	// PVScan scans devices for physical volumes with the given options,
+	// e.g. to autoactivate volume groups on newly appeared devices.
+	//
+	// See man lvm pvscan for more information.
+	PVScan(ctx context.Context, opts ...PVScanOption) error
 }
 
 // DevicesClient is a client that provides operations on lvm2 device files.
@@ -356,10 +497,11 @@ type DevicesClient interface {
 	DevCheck(ctx context.Context, opts ...DevCheckOption) error
 
 	// DevUpdate updates the device files through attempted automatic corrections.
+	// It returns a summary of the entries that were updated, removed or had their device name corrected.
 	//
 	// Replicates lvmdevices --update
 	// See man lvmdevices for more information.
-	DevUpdate(ctx context.Context, opts ...DevUpdateOption) error
+	DevUpdate(ctx context.Context, opts ...DevUpdateOption) (*DevUpdateResult, error)
 
 	// DevModify adds and removes devices in device files with the given options.
 	//
@@ -0,0 +1,284 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os/exec"
+	"time"
+)
+
+type (
+	// ClientOptions configure the behavior of a Client created with NewClient in one place.
+	// Unset options fall back to the package level defaults, e.g. GetLVMPath or DefaultWaitDelay.
+	ClientOptions struct {
+		LVMPath
+		CommandRunner
+		Environment
+		StandardLocale
+		ProcessCancelWaitDelay
+		NsenterPolicy
+		ExecutionStrategies
+		*ClientLogger
+		MetricsRecorder
+		KernelMessages
+		StrictDecoding
+		ReportLocking
+		DevicesFile
+		LVMEnvironment
+		*CommandHistory
+		ManagedTag
+	}
+	ClientOption interface {
+		ApplyToClientOptions(opts *ClientOptions)
+	}
+	ClientOptionList []ClientOption
+)
+
+var _ ClientOption = (*ClientOptions)(nil)
+
+func (list ClientOptionList) ApplyToClientOptions(opts *ClientOptions) {
+	for _, opt := range list {
+		opt.ApplyToClientOptions(opts)
+	}
+}
+
+func (opts *ClientOptions) ApplyToClientOptions(new *ClientOptions) {
+	*new = *opts
+}
+
+// LVMPath is the path to the lvm binary used by the client instead of GetLVMPath.
+type LVMPath string
+
+func (opt LVMPath) ApplyToClientOptions(opts *ClientOptions) {
+	opts.LVMPath = opt
+}
+
+// CommandRunner starts the given command and returns its stdout.
+// Closing the returned reader must wait for the command to finish and return any error of the command.
+// The default CommandRunner is StreamedCommand.
+type CommandRunner func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error)
+
+func (opt CommandRunner) ApplyToClientOptions(opts *ClientOptions) {
+	opts.CommandRunner = opt
+}
+
+// Environment is a set of environment variables passed to every command of the client.
+// It is equivalent to calling WithCustomEnvironment on every context passed to the client.
+type Environment map[string]string
+
+func (opt Environment) ApplyToClientOptions(opts *ClientOptions) {
+	opts.Environment = opt
+}
+
+// StandardLocale forces the C locale (LC_ALL=C) for every command of the client
+// independent of SetUseStandardLocale.
+type StandardLocale bool
+
+func (opt StandardLocale) ApplyToClientOptions(opts *ClientOptions) {
+	opts.StandardLocale = opt
+}
+
+// ProcessCancelWaitDelay is the exec.Cmd WaitDelay used for every command of the client.
+// It is equivalent to calling SetProcessCancelWaitDelay on every context passed to the client.
+type ProcessCancelWaitDelay time.Duration
+
+func (opt ProcessCancelWaitDelay) ApplyToClientOptions(opts *ClientOptions) {
+	opts.ProcessCancelWaitDelay = opt
+}
+
+// NsenterPolicy determines whether commands are run in the host namespaces with nsenter.
+type NsenterPolicy string
+
+const (
+	// NsenterPolicyAuto uses nsenter if IsContainerized reports a containerized environment.
+	NsenterPolicyAuto NsenterPolicy = ""
+	// NsenterPolicyNever never uses nsenter, equivalent to WithNoNsenter.
+	NsenterPolicyNever NsenterPolicy = "never"
+	// NsenterPolicyAlways always uses nsenter, even if no containerized environment is detected.
+	NsenterPolicyAlways NsenterPolicy = "always"
+)
+
+func (opt NsenterPolicy) ApplyToClientOptions(opts *ClientOptions) {
+	opts.NsenterPolicy = opt
+}
+
+// ClientLogger is the logger used by the client to log command output.
+type ClientLogger struct {
+	*slog.Logger
+}
+
+func NewClientLogger(logger *slog.Logger) *ClientLogger {
+	return &ClientLogger{Logger: logger}
+}
+
+func (opt *ClientLogger) ApplyToClientOptions(opts *ClientOptions) {
+	opts.ClientLogger = opt
+}
+
+// CommandMetrics describe a single command run by the client.
+type CommandMetrics struct {
+	Args     []string
+	Duration time.Duration
+	Err      error
+}
+
+// MetricsRecorder is called after every command run by the client.
+type MetricsRecorder func(ctx context.Context, metrics CommandMetrics)
+
+func (opt MetricsRecorder) ApplyToClientOptions(opts *ClientOptions) {
+	opts.MetricsRecorder = opt
+}
+
+func (c *client) lvmPath() string {
+	if c.opts.LVMPath != "" {
+		return string(c.opts.LVMPath)
+	}
+	return GetLVMPath()
+}
+
+func (c *client) logger() *slog.Logger {
+	if c.opts.ClientLogger != nil && c.opts.ClientLogger.Logger != nil {
+		return c.opts.ClientLogger.Logger
+	}
+	return slog.Default()
+}
+
+// clientLogger returns the logger of the client configured with NewClientLogger, also for wrapped clients,
+// or the default logger if the client has none.
+func clientLogger(client Client) *slog.Logger {
+	if c, ok := ClientImplements[interface{ logger() *slog.Logger }](client); ok {
+		return c.logger()
+	}
+	return slog.Default()
+}
+
+// command creates the command for the given args with the client options applied to the context
+// and starts it with the configured CommandRunner.
+// The returned function must be called with the final error of the command to record metrics.
+func (c *client) command(ctx context.Context, cmd string, args ...string) (io.ReadCloser, func(err error), error) {
+	ctx = c.contextWithOptions(ctx)
+
+	runner := c.opts.CommandRunner
+	if runner == nil {
+		runner = StreamedCommand
+	}
+
+	start := time.Now()
+	done := func(err error) {
+		metrics := CommandMetrics{
+			Args:     append([]string{cmd}, args...),
+			Duration: time.Since(start),
+			Err:      err,
+		}
+		if c.opts.MetricsRecorder != nil {
+			c.opts.MetricsRecorder(ctx, metrics)
+		}
+		if c.opts.CommandHistory != nil {
+			c.opts.CommandHistory.Record(metrics)
+		}
+	}
+
+	if err := c.opts.LVMEnvironment.Validate(); err != nil {
+		done(err)
+		return nil, nil, err
+	}
+
+	execCmd, err := c.hostCommand(ctx, cmd, args...)
+	if err != nil {
+		done(err)
+		return nil, nil, err
+	}
+
+	output, err := runner(ctx, execCmd)
+	if err != nil {
+		done(err)
+		return nil, nil, err
+	}
+
+	return output, done, nil
+}
+
+// hostCommand creates the command with the selected ExecutionStrategy, or with nsenter according to the context.
+func (c *client) hostCommand(ctx context.Context, cmd string, args ...string) (*exec.Cmd, error) {
+	if c.opts.ExecutionStrategies != nil && !shouldForceNoNsenter(ctx) {
+		if c.strategyErr != nil {
+			return nil, c.strategyErr
+		}
+		cmd, args = c.strategy.Wrap(cmd, args...)
+		return hostCommand(ctx, cmd, args...), nil
+	}
+	// custom runners, e.g. for replays, do not necessarily execute the command
+	if c.opts.CommandRunner == nil && WillUseNsenter(ctx) {
+		if err := CheckNsenter(); err != nil {
+			return nil, err
+		}
+	}
+	return CommandContext(ctx, cmd, args...), nil
+}
+
+// contextWithOptions applies the client options to the context.
+// Values already present in the context take precedence over the client options.
+func (c *client) contextWithOptions(ctx context.Context) context.Context {
+	lvmEnv := c.opts.LVMEnvironment.Variables()
+	// the custom environment is applied after the default volume group of the context, so it is passed as such
+	if vg, ok := lvmEnv[DefaultVolumeGroupEnv]; ok {
+		delete(lvmEnv, DefaultVolumeGroupEnv)
+		if DefaultVolumeGroup(ctx) == "" {
+			ctx = WithDefaultVolumeGroup(ctx, vg)
+		}
+	}
+	if c.opts.Environment != nil || c.opts.StandardLocale || len(lvmEnv) > 0 {
+		env := make(map[string]string, len(c.opts.Environment)+len(lvmEnv)+1)
+		if c.opts.StandardLocale {
+			env["LC_ALL"] = "C"
+		}
+		for k, v := range lvmEnv {
+			env[k] = v
+		}
+		for k, v := range c.opts.Environment {
+			env[k] = v
+		}
+		for k, v := range GetCustomEnvironment(ctx) {
+			env[k] = v
+		}
+		ctx = WithCustomEnvironment(ctx, env)
+	}
+
+	if _, ok := contextWaitDelay(ctx); !ok && c.opts.ProcessCancelWaitDelay > 0 {
+		ctx = SetProcessCancelWaitDelay(ctx, time.Duration(c.opts.ProcessCancelWaitDelay))
+	}
+
+	// commands are wrapped with the selected strategy unless they are forced to run directly, see hostCommand
+	if c.strategy != nil && !shouldForceNoNsenter(ctx) {
+		ctx = withExecutionStrategy(ctx, c.strategy)
+	}
+
+	// the NsenterPolicy is replaced by ExecutionStrategies
+	if _, ok := contextForceNoNsenter(ctx); !ok && c.opts.ExecutionStrategies == nil {
+		switch c.opts.NsenterPolicy {
+		case NsenterPolicyNever:
+			ctx = WithForceNoNsenter(ctx, true)
+		case NsenterPolicyAlways:
+			ctx = withForceNsenter(ctx)
+		}
+	}
+
+	return ctx
+}
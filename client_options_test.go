@@ -0,0 +1,186 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"maps"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestNewClientWithOptions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var cmd *exec.Cmd
+	var metrics []CommandMetrics
+
+	clnt := NewClient(
+		LVMPath("/custom/lvm"),
+		NsenterPolicyNever,
+		Environment{"LVM_TEST": "1"},
+		StandardLocale(true),
+		ProcessCancelWaitDelay(5*time.Second),
+		CommandRunner(func(ctx context.Context, c *exec.Cmd) (io.ReadCloser, error) {
+			cmd = c
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"lv","vg_name":"vg"}]}]}`)), nil
+		}),
+		MetricsRecorder(func(ctx context.Context, m CommandMetrics) {
+			metrics = append(metrics, m)
+		}),
+	)
+
+	lvs, err := clnt.LVs(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 1 || lvs[0].Name != "lv" {
+		t.Fatalf("unexpected lvs: %v", lvs)
+	}
+
+	if cmd.Path != "/custom/lvm" {
+		t.Fatalf("expected custom lvm path, got %q", cmd.Path)
+	}
+	for _, env := range []string{"LVM_TEST=1", "LC_ALL=C"} {
+		if !slices.Contains(cmd.Env, env) {
+			t.Fatalf("expected %q in command environment %v", env, cmd.Env)
+		}
+	}
+	if cmd.WaitDelay != 5*time.Second {
+		t.Fatalf("expected wait delay of 5s, got %s", cmd.WaitDelay)
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("expected 1 recorded command, got %d", len(metrics))
+	}
+	if metrics[0].Err != nil || metrics[0].Args[0] != "/custom/lvm" || metrics[0].Args[1] != "lvs" {
+		t.Fatalf("unexpected metrics: %+v", metrics[0])
+	}
+}
+
+func TestClientDefaults(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := NewClient(
+		LVMPath("/custom/lvm"),
+		Environment{"A": "client", "B": "client"},
+		ProcessCancelWaitDelay(5*time.Second),
+	)
+
+	defaults := clnt.Defaults(ctx)
+	if defaults.LVMPath.Value != "/custom/lvm" || defaults.LVMPath.Source != SettingSourceClient {
+		t.Fatalf("unexpected lvm path: %+v", defaults.LVMPath)
+	}
+	if defaults.WaitDelay.Value != 5*time.Second || defaults.WaitDelay.Source != SettingSourceClient {
+		t.Fatalf("unexpected wait delay: %+v", defaults.WaitDelay)
+	}
+
+	ctx = SetProcessCancelWaitDelay(ctx, time.Second)
+	ctx = WithCustomEnvironment(ctx, map[string]string{"B": "context"})
+	defaults = WithNoNsenter(clnt).Defaults(ctx)
+	if defaults.WaitDelay.Value != time.Second || defaults.WaitDelay.Source != SettingSourceContext {
+		t.Fatalf("expected context wait delay to take precedence: %+v", defaults.WaitDelay)
+	}
+	if defaults.Environment.Value["A"] != "client" || defaults.Environment.Value["B"] != "context" {
+		t.Fatalf("expected merged environment: %+v", defaults.Environment)
+	}
+	if defaults.UseNsenter.Value || defaults.UseNsenter.Source != SettingSourceContext {
+		t.Fatalf("expected nsenter to be disabled through the context: %+v", defaults.UseNsenter)
+	}
+
+	// the typed lvm environment is reported like the environment of the client
+	clnt = NewClient(
+		LVMSystemDirectory("/etc/custom-lvm"),
+		LVMProfileDirectory("/etc/custom-lvm/profile"),
+		CommandProfile("thin-performance.profile"),
+		DefaultVolumeGroupName("vg"),
+		DisableUdev(true),
+		Environment{LVMSystemDirEnv: "/etc/override"},
+	)
+	defaults = clnt.Defaults(context.Background())
+	expected := map[string]string{
+		LVMSystemDirEnv:      "/etc/override",
+		LVMProfileDirEnv:     "/etc/custom-lvm/profile",
+		LVMCommandProfileEnv: "thin-performance",
+		DMDisableUdevEnv:     "1",
+	}
+	if !maps.Equal(defaults.Environment.Value, expected) || defaults.Environment.Source != SettingSourceClient {
+		t.Fatalf("expected lvm environment of the client in %+v", defaults.Environment)
+	}
+	if defaults.DefaultVolumeGroup.Value != "vg" || defaults.DefaultVolumeGroup.Source != SettingSourceClient {
+		t.Fatalf("expected default volume group of the client: %+v", defaults.DefaultVolumeGroup)
+	}
+	defaults = clnt.Defaults(WithDefaultVolumeGroup(context.Background(), "other"))
+	if defaults.DefaultVolumeGroup.Value != "other" || defaults.DefaultVolumeGroup.Source != SettingSourceContext {
+		t.Fatalf("expected context default volume group to take precedence: %+v", defaults.DefaultVolumeGroup)
+	}
+}
+
+func TestKernelMessages(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := NewClient(
+		NsenterPolicyNever,
+		KernelMessages(true),
+		CommandRunner(func(ctx context.Context, c *exec.Cmd) (io.ReadCloser, error) {
+			switch c.Args[0] {
+			case "dmesg":
+				return io.NopCloser(strings.NewReader(strings.Join([]string{
+					"[  100.000000] loop10: detected capacity change from 0 to 2048",
+					"[  101.000000] I/O error, dev loop1, sector 0 op 0x0:(READ) flags 0x0 phys_seg 1 prio class 2",
+					"[  102.000000] EXT4-fs (sda1): mounted filesystem",
+				}, "\n"))), nil
+			default:
+				return &errorReadCloser{NewLVMStdErr([]byte("  Error reading device /dev/loop1 at 0 length 512."))}, nil
+			}
+		}),
+	)
+
+	_, err := clnt.LVs(ctx)
+	if !IsIOError(err) {
+		t.Fatalf("expected IO error, got %v", err)
+	}
+	kernelMessagesErr, ok := AsKernelMessagesError(err)
+	if !ok {
+		t.Fatalf("expected kernel messages to be attached, got %v", err)
+	}
+	messages := kernelMessagesErr.Messages["/dev/loop1"]
+	if len(messages) != 1 || !strings.Contains(messages[0], "I/O error, dev loop1") {
+		t.Fatalf("unexpected kernel messages: %v", messages)
+	}
+}
+
+type errorReadCloser struct {
+	err error
+}
+
+func (r *errorReadCloser) Read([]byte) (int, error) {
+	return 0, io.EOF
+}
+
+func (r *errorReadCloser) Close() error {
+	return r.err
+}
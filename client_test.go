@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"testing"
 
 	. "github.com/azalio/lvm2go"
@@ -153,3 +154,61 @@ func TestLVs(t *testing.T) {
 		})
 	}
 }
+
+func TestReportScope(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		opts     ArgumentGenerator
+		expected []string
+	}{
+		"lv of vg": {
+			opts:     LVsOptionsList{VolumeGroupName("vg"), LogicalVolumeName("lv")},
+			expected: []string{"vg/lv"},
+		},
+		"vgs and lvs": {
+			opts:     LVsOptionsList{VolumeGroupNames{"a", "b"}, FQLogicalVolumeNames{MustNewFQLogicalVolumeName("c", "lv")}},
+			expected: []string{"a", "b", "c/lv"},
+		},
+		"vgs": {
+			opts:     VGsOptionsList{VolumeGroupName("a"), VolumeGroupNames{"b"}},
+			expected: []string{"a", "b"},
+		},
+		"pvs": {
+			opts:     PVsOptionsList{PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")},
+			expected: []string{"/dev/sdb", "/dev/sdc"},
+		},
+	} {
+		args, err := tc.opts.AsArgs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, arg := range tc.expected {
+			if !slices.Contains(args.GetRaw(), arg) {
+				t.Errorf("%s: expected %q in %v", name, arg, args.GetRaw())
+			}
+		}
+	}
+
+	// names that are not found do not hide the report of the other names
+	opts := VGsOptionsList{VolumeGroupNames{"vg", "missing"}}
+	args, err := opts.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command:  "lvm",
+		Args:     append([]string{"vgs", "--reportformat", "json"}, args.GetRaw()...),
+		Stdout:   `{"report":[{"vg":[{"vg_name":"vg","vg_attr":"wz--n-"}]}]}`,
+		Stderr:   `Volume group "missing" not found`,
+		ExitCode: 5,
+	}}))
+	vgs, err := clnt.VGs(ctx, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vgs) != 1 || vgs[0].Name != "vg" {
+		t.Fatalf("unexpected vgs %v", vgs)
+	}
+}
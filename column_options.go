@@ -17,6 +17,8 @@
 package lvm2go
 
 import (
+	"encoding/json"
+	"slices"
 	"strings"
 )
 
@@ -42,20 +44,93 @@ func (opt ColumnOptions) ApplyToVGsOptions(opts *VGsOptions) {
 	opts.ColumnOptions = opt
 }
 
+func (opt ColumnOptions) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.ColumnOptions = opt
+}
+
 func (opt ColumnOptions) ApplyToArgs(args Arguments) error {
-	var optionsString string
-	if len(opt) > 0 {
-		optionsString = strings.Join(opt, ",")
-	} else {
+	return opt.withExtra(nil).ApplyToArgs(args)
+}
+
+// withExtra returns the columns together with the given extra columns as a single --options argument.
+func (opt ColumnOptions) withExtra(extra ExtraColumns) Argument {
+	return extendedColumnOptions{columns: opt, extra: extra}
+}
+
+// ExtraColumns add report fields to the columns of ColumnOptions, or to the default columns if no ColumnOptions
+// are given, e.g. ExtraColumns{"vg_free"} to report the free space of the volume group with each logical volume.
+// Fields that are not covered by the typed structs are decoded into the ExtraFields of the reported objects.
+type ExtraColumns []string
+
+func (opt ExtraColumns) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.ExtraColumns = append(opts.ExtraColumns, opt...)
+}
+
+func (opt ExtraColumns) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.ExtraColumns = append(opts.ExtraColumns, opt...)
+}
+
+func (opt ExtraColumns) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.ExtraColumns = append(opts.ExtraColumns, opt...)
+}
+
+type extendedColumnOptions struct {
+	columns ColumnOptions
+	extra   ExtraColumns
+}
+
+func (opt extendedColumnOptions) ApplyToArgs(args Arguments) error {
+	columns := opt.columns
+	if len(columns) == 0 {
 		switch args.GetType() {
 		case ArgsTypeVGs:
-			optionsString = strings.Join(DefaultVGsColumnOptions, ",")
+			columns = DefaultVGsColumnOptions
 		case ArgsTypeLVs:
-			optionsString = strings.Join(DefaultLVsColumnOptions, ",")
+			columns = DefaultLVsColumnOptions
 		case ArgsTypePVs:
-			optionsString = strings.Join(DefaultPVsColumnOptions, ",")
+			columns = DefaultPVsColumnOptions
+		}
+	}
+	columns = slices.Clone(columns)
+	for _, column := range opt.extra {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
 		}
 	}
-	args.AddOrReplaceAll([]string{"--options", optionsString})
+	args.AddOrReplaceAll([]string{"--options", strings.Join(columns, ",")})
 	return nil
 }
+
+// ExtraFields are the fields of a reported object that are not covered by its typed struct,
+// e.g. fields requested with ExtraColumns, keyed by the name of the field.
+type ExtraFields map[string]string
+
+// Get returns the value of the given field and whether it was reported.
+func (fields ExtraFields) Get(field string) (string, bool) {
+	val, ok := fields[field]
+	return val, ok
+}
+
+// extraReportFields decodes the fields of the raw JSON of a reported object that are not part of the typed fields.
+// If all fields are typed, nil is returned.
+func extraReportFields(raw RawJSON, typed ColumnOptions) (ExtraFields, error) {
+	var reported map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &reported); err != nil {
+		return nil, err
+	}
+	var extra ExtraFields
+	for field, val := range reported {
+		if slices.Contains(typed, field) {
+			continue
+		}
+		var str string
+		if err := json.Unmarshal(val, &str); err != nil {
+			return nil, newReportFieldError(field, val, err)
+		}
+		if extra == nil {
+			extra = make(ExtraFields)
+		}
+		extra[field] = str
+	}
+	return extra, nil
+}
@@ -31,19 +31,19 @@ const (
 	DefaultVolumeGroupEnv = "LVM_VG_NAME"
 )
 
-var waitDelayKey = struct{}{}
-
 // DefaultWaitDelay for Commands
 // If WaitDelay is zero (the default), I/ O pipes will be read until EOF, which might not occur until orphaned subprocesses of the command have also closed their descriptors for the pipes
 // see exec.Cmd.Wait for more information
 var DefaultWaitDelay = time.Duration(0)
 
+// SetProcessCancelWaitDelay creates a context with the WaitDelay of commands run with it, overriding DefaultWaitDelay.
 func SetProcessCancelWaitDelay(ctx context.Context, delay time.Duration) context.Context {
 	return context.WithValue(ctx, waitDelayKey, delay)
 }
 
+// GetProcessCancelWaitDelay returns the WaitDelay of commands run with the context.
 func GetProcessCancelWaitDelay(ctx context.Context) time.Duration {
-	if delay, ok := ctx.Value(waitDelayKey).(time.Duration); ok {
+	if delay, ok := contextWaitDelay(ctx); ok {
 		return delay
 	}
 	return DefaultWaitDelay
@@ -53,14 +53,16 @@ func GetProcessCancelWaitDelay(ctx context.Context) time.Duration {
 // When containerized, it calls nsenter with the provided command and args, unless ForceNoNsenter is set in the context
 // using WithForceNoNsenter.
 func CommandContext(ctx context.Context, cmd string, args ...string) *exec.Cmd {
-	var c *exec.Cmd
-
-	if IsContainerized(ctx) && !shouldForceNoNsenter(ctx) {
-		args = append([]string{"-m", "-u", "-i", "-n", "-p", "-t", "1", cmd}, args...)
-		c = exec.CommandContext(ctx, nsenter, args...)
-	} else {
-		c = exec.CommandContext(ctx, cmd, args...)
+	if WillUseNsenter(ctx) {
+		cmd, args = NsenterStrategy{}.Wrap(cmd, args...)
 	}
+	return hostCommand(ctx, cmd, args...)
+}
+
+// hostCommand creates exec.Cmd for a command that is already wrapped for execution on the host,
+// applying the wait delay and environment of the context.
+func hostCommand(ctx context.Context, cmd string, args ...string) *exec.Cmd {
+	c := exec.CommandContext(ctx, cmd, args...)
 	c.WaitDelay = GetProcessCancelWaitDelay(ctx)
 
 	if DefaultVolumeGroup(ctx) != "" {
@@ -70,12 +72,13 @@ func CommandContext(ctx context.Context, cmd string, args ...string) *exec.Cmd {
 	return CommandWithCustomEnvironment(ctx, c)
 }
 
-var defaultVolumeGroupKey = struct{}{}
-
+// WithDefaultVolumeGroup creates a context in which commands run with LVM_VG_NAME set to vg,
+// the volume group lvm2 uses for logical volume names without a volume group.
 func WithDefaultVolumeGroup(ctx context.Context, vg string) context.Context {
 	return context.WithValue(ctx, defaultVolumeGroupKey, vg)
 }
 
+// DefaultVolumeGroup returns the volume group set with WithDefaultVolumeGroup, empty if unset.
 func DefaultVolumeGroup(ctx context.Context) string {
 	if vg, ok := ctx.Value(defaultVolumeGroupKey).(string); ok {
 		return vg
@@ -106,11 +109,9 @@ func IsContainerized(ctx context.Context) bool {
 	return isContainerized
 }
 
-var envContextKey = struct{}{}
-var forceNoNsenterKey = struct{}{}
-
+// WithCustomEnvironment creates a context in which commands run with the given additional environment variables.
 func WithCustomEnvironment(ctx context.Context, env map[string]string) context.Context {
-	return context.WithValue(ctx, envContextKey, env)
+	return context.WithValue(ctx, environmentKey, env)
 }
 
 // WithForceNoNsenter creates a context that forces CommandContext to not use nsenter
@@ -119,25 +120,36 @@ func WithForceNoNsenter(ctx context.Context, force bool) context.Context {
 	return context.WithValue(ctx, forceNoNsenterKey, force)
 }
 
+// GetCustomEnvironment returns the environment set with WithCustomEnvironment, nil if unset.
 func GetCustomEnvironment(ctx context.Context) map[string]string {
-	if env, ok := ctx.Value(envContextKey).(map[string]string); ok {
+	if env, ok := ctx.Value(environmentKey).(map[string]string); ok {
 		return env
 	}
 	return nil
 }
 
 func shouldForceNoNsenter(ctx context.Context) bool {
-	if force, ok := ctx.Value(forceNoNsenterKey).(bool); ok {
-		return force
-	}
-	return false
+	force, _ := contextForceNoNsenter(ctx)
+	return force
+}
+
+// withForceNsenter creates a context that forces CommandContext to use nsenter
+// even if IsContainerized returns false. ForceNoNsenter still takes precedence.
+func withForceNsenter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, forceNsenterKey, true)
+}
+
+func shouldForceNsenter(ctx context.Context) bool {
+	force, _ := ctx.Value(forceNsenterKey).(bool)
+	return force
 }
 
 // WillUseNsenter returns whether nsenter will be used for the given context.
 // This is useful for debugging purposes to verify the behavior of CommandContext.
-// It returns true if the context indicates a containerized environment and ForceNoNsenter is not set.
+// It returns true if the context indicates a containerized environment (or nsenter is forced
+// through NsenterPolicyAlways) and ForceNoNsenter is not set.
 func WillUseNsenter(ctx context.Context) bool {
-	return IsContainerized(ctx) && !shouldForceNoNsenter(ctx)
+	return (IsContainerized(ctx) || shouldForceNsenter(ctx)) && !shouldForceNoNsenter(ctx)
 }
 
 func CommandWithCustomEnvironment(ctx context.Context, cmd *exec.Cmd) *exec.Cmd {
@@ -0,0 +1,145 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCommandHistoryStderrLimit is the number of bytes of stderr kept per command by a CommandHistory.
+const DefaultCommandHistoryStderrLimit = 1024
+
+// CommandHistoryEntry is a command run by a client, recorded by a CommandHistory.
+type CommandHistoryEntry struct {
+	Time     time.Time
+	Args     []string
+	Duration time.Duration
+	// ExitCode is the exit code of the command, -1 if the command failed without one,
+	// e.g. because it could not be started or was canceled.
+	ExitCode int
+	// Stderr is the stderr of a failed command, truncated to the StderrLimit of the history.
+	Stderr string
+	// Err is the error of the command, empty on success.
+	Err string
+}
+
+// CommandHistory records the last commands of a client in a ring buffer,
+// so that the commands leading to a sporadic failure can be inspected afterwards.
+// It is safe for concurrent use and can be shared between clients.
+//
+// Example usage:
+//
+//	history := lvm2go.NewCommandHistory(100)
+//	client := lvm2go.NewClient(history)
+//	...
+//	if err != nil {
+//		_ = history.Dump(os.Stderr)
+//	}
+type CommandHistory struct {
+	// StderrLimit is the number of bytes of stderr kept per command.
+	// If zero, DefaultCommandHistoryStderrLimit is used.
+	StderrLimit int
+
+	mu      sync.Mutex
+	entries []CommandHistoryEntry
+	next    int
+	full    bool
+}
+
+// NewCommandHistory creates a history of the last size commands.
+func NewCommandHistory(size int) *CommandHistory {
+	return &CommandHistory{entries: make([]CommandHistoryEntry, max(size, 1))}
+}
+
+func (opt *CommandHistory) ApplyToClientOptions(opts *ClientOptions) {
+	opts.CommandHistory = opt
+}
+
+// Record adds the metrics of a command to the history, overwriting the oldest entry if the history is full.
+func (h *CommandHistory) Record(metrics CommandMetrics) {
+	entry := CommandHistoryEntry{
+		Time:     time.Now().Add(-metrics.Duration),
+		Args:     metrics.Args,
+		Duration: metrics.Duration,
+	}
+	if metrics.Err != nil {
+		entry.Err = metrics.Err.Error()
+		entry.ExitCode = -1
+		if exitCodeErr, ok := AsExitCodeError(metrics.Err); ok {
+			entry.ExitCode = exitCodeErr.ExitCode()
+		}
+		if stderr, ok := AsLVMStdErr(metrics.Err); ok {
+			entry.Stderr = h.truncate(string(stderr.Bytes()))
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.entries[h.next] = entry
+	h.next = (h.next + 1) % len(h.entries)
+	h.full = h.full || h.next == 0
+}
+
+func (h *CommandHistory) truncate(stderr string) string {
+	limit := h.StderrLimit
+	if limit == 0 {
+		limit = DefaultCommandHistoryStderrLimit
+	}
+	if len(stderr) <= limit {
+		return stderr
+	}
+	return stderr[:limit] + "..."
+}
+
+// Entries returns the recorded commands from the oldest to the newest.
+func (h *CommandHistory) Entries() []CommandHistoryEntry {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if !h.full {
+		return append([]CommandHistoryEntry(nil), h.entries[:h.next]...)
+	}
+	return append(append([]CommandHistoryEntry(nil), h.entries[h.next:]...), h.entries[:h.next]...)
+}
+
+// Dump writes the recorded commands from the oldest to the newest to w, one command per line
+// followed by the indented stderr of failed commands.
+func (h *CommandHistory) Dump(w io.Writer) error {
+	for _, entry := range h.Entries() {
+		status := "ok"
+		if entry.Err != "" {
+			status = fmt.Sprintf("exit %d: %s", entry.ExitCode, strings.ReplaceAll(entry.Err, "\n", "; "))
+		}
+		if _, err := fmt.Fprintf(w, "%s %s (%s) %s\n",
+			entry.Time.Format(time.RFC3339Nano), strings.Join(entry.Args, " "), entry.Duration, status,
+		); err != nil {
+			return err
+		}
+		for _, line := range strings.Split(strings.TrimSpace(entry.Stderr), "\n") {
+			if line == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "\t%s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestCommandHistory(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	history := NewCommandHistory(2)
+	history.StderrLimit = 20
+	clnt := NewClient(history, ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    []string{"vgs", "--reportformat", "json", "--yes", "--options", "vg_all"},
+		Stdout:  `{"report":[{"vg":[]}]}`,
+	}, {
+		Command:  "lvm",
+		Args:     []string{"vgs", "--reportformat", "json", "vg1", "--yes", "--options", "vg_all"},
+		Stderr:   "  Volume group \"vg1\" not found\n  Cannot process volume group vg1\n",
+		ExitCode: 5,
+	}, {
+		Command: "lvm",
+		Args:    []string{"vgs", "--reportformat", "json", "vg2", "--yes", "--options", "vg_all"},
+		Stdout:  `{"report":[{"vg":[]}]}`,
+	}}))
+
+	for _, opts := range [][]VGsOption{nil, {VolumeGroupName("vg1")}, {VolumeGroupName("vg2")}} {
+		_, _ = clnt.VGs(ctx, opts...)
+	}
+
+	entries := history.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected the last 2 commands, got %d", len(entries))
+	}
+	failed, last := entries[0], entries[1]
+	if failed.ExitCode != 5 || failed.Err == "" || len(failed.Stderr) != 23 || !strings.HasSuffix(failed.Stderr, "...") {
+		t.Fatalf("unexpected failed command %+v", failed)
+	}
+	if last.ExitCode != 0 || last.Err != "" || !slices.Contains(last.Args, "vg2") {
+		t.Fatalf("unexpected last command %+v", last)
+	}
+
+	var dump bytes.Buffer
+	if err := history.Dump(&dump); err != nil {
+		t.Fatal(err)
+	}
+	if lines := strings.Split(strings.TrimSpace(dump.String()), "\n"); len(lines) != 3 ||
+		!strings.Contains(lines[0], "exit 5") || !strings.HasPrefix(lines[1], "\t") || !strings.HasSuffix(lines[2], " ok") {
+		t.Fatalf("unexpected dump:\n%s", dump.String())
+	}
+}
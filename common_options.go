@@ -22,6 +22,10 @@ type CommonOptions struct {
 	Profile
 	Verbose
 	RequestConfirm
+	NoLocking
+	LockOpt
+	NoUdevSync
+	NoUdevRules
 }
 
 func (opts CommonOptions) ApplyToArgs(args Arguments) error {
@@ -30,6 +34,10 @@ func (opts CommonOptions) ApplyToArgs(args Arguments) error {
 		opts.DevicesFile,
 		opts.Verbose,
 		opts.RequestConfirm,
+		opts.NoLocking,
+		opts.LockOpt,
+		opts.NoUdevSync,
+		opts.NoUdevRules,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
 			return err
@@ -48,6 +56,20 @@ func (opt RequestConfirm) ApplyToArgs(args Arguments) error {
 	return nil
 }
 
+// Test runs the command in test mode, in which lvm2 validates the command but does not update any metadata.
+type Test bool
+
+func (opt Test) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplace("--test")
+	}
+	return nil
+}
+
+func (opt Test) ApplyToVGMergeOptions(opts *VGMergeOptions) {
+	opts.Test = opt
+}
+
 type Verbose bool
 
 func (opt Verbose) ApplyToArgs(args Arguments) error {
@@ -0,0 +1,120 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+)
+
+// contextKey is the type of all context keys of lvm2go. As it is unexported,
+// the keys cannot collide with context keys of other packages.
+type contextKey int
+
+const (
+	waitDelayKey contextKey = iota
+	defaultVolumeGroupKey
+	environmentKey
+	forceNoNsenterKey
+	forceNsenterKey
+	logFieldsKey
+	executionStrategyKey
+)
+
+func contextWaitDelay(ctx context.Context) (time.Duration, bool) {
+	delay, ok := ctx.Value(waitDelayKey).(time.Duration)
+	return delay, ok
+}
+
+func contextForceNoNsenter(ctx context.Context) (bool, bool) {
+	force, ok := ctx.Value(forceNoNsenterKey).(bool)
+	return force, ok
+}
+
+// ContextConfiguration is the configuration of lvm2go stored in a context, see ContextConfig.
+// Unlike Defaults, it does not include settings of a Client.
+type ContextConfiguration struct {
+	// Environment is set with WithCustomEnvironment.
+	Environment map[string]string
+	// WaitDelay is set with SetProcessCancelWaitDelay, nil if DefaultWaitDelay is used.
+	WaitDelay *time.Duration
+	// ForceNoNsenter is set with WithForceNoNsenter, nil if unset.
+	ForceNoNsenter *bool
+	// ForceNsenter is set by clients with NsenterPolicyAlways.
+	ForceNsenter bool
+	// UseNsenter is whether CommandContext uses nsenter with the context, see WillUseNsenter.
+	UseNsenter bool
+	// DefaultVolumeGroup is set with WithDefaultVolumeGroup.
+	DefaultVolumeGroup VolumeGroupName
+	// LogFields are the fields added to log records with WithValue.
+	LogFields map[string]any
+}
+
+// ContextConfig returns the configuration of lvm2go stored in the context.
+// It is intended for debugging behavior derived from the context, e.g. by logging it.
+func ContextConfig(ctx context.Context) ContextConfiguration {
+	config := ContextConfiguration{
+		Environment:        maps.Clone(GetCustomEnvironment(ctx)),
+		ForceNsenter:       shouldForceNsenter(ctx),
+		UseNsenter:         WillUseNsenter(ctx),
+		DefaultVolumeGroup: VolumeGroupName(DefaultVolumeGroup(ctx)),
+	}
+	if delay, ok := contextWaitDelay(ctx); ok {
+		config.WaitDelay = &delay
+	}
+	if force, ok := contextForceNoNsenter(ctx); ok {
+		config.ForceNoNsenter = &force
+	}
+	if fields, ok := ctx.Value(logFieldsKey).(*sync.Map); ok {
+		config.LogFields = map[string]any{}
+		fields.Range(func(key, val any) bool {
+			if keyString, ok := key.(string); ok {
+				config.LogFields[keyString] = val
+			}
+			return true
+		})
+	}
+	return config
+}
+
+// LogValue implements slog.LogValuer, omitting unset values.
+func (c ContextConfiguration) LogValue() slog.Value {
+	var attrs []slog.Attr
+	if c.Environment != nil {
+		attrs = append(attrs, slog.Any("environment", c.Environment))
+	}
+	if c.WaitDelay != nil {
+		attrs = append(attrs, slog.Duration("waitDelay", *c.WaitDelay))
+	}
+	if c.ForceNoNsenter != nil {
+		attrs = append(attrs, slog.Bool("forceNoNsenter", *c.ForceNoNsenter))
+	}
+	if c.ForceNsenter {
+		attrs = append(attrs, slog.Bool("forceNsenter", true))
+	}
+	attrs = append(attrs, slog.Bool("useNsenter", c.UseNsenter))
+	if c.DefaultVolumeGroup != "" {
+		attrs = append(attrs, slog.String("defaultVolumeGroup", string(c.DefaultVolumeGroup)))
+	}
+	if c.LogFields != nil {
+		attrs = append(attrs, slog.Any("logFields", c.LogFields))
+	}
+	return slog.GroupValue(attrs...)
+}
@@ -0,0 +1,61 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestContextConfig(t *testing.T) {
+	t.Parallel()
+
+	empty := ContextConfig(context.Background())
+	if empty.Environment != nil || empty.WaitDelay != nil || empty.ForceNoNsenter != nil || empty.LogFields != nil {
+		t.Fatalf("expected empty configuration, got %+v", empty)
+	}
+
+	ctx := WithCustomEnvironment(context.Background(), map[string]string{"LVM_SUPPRESS_FD_WARNINGS": "1"})
+	ctx = SetProcessCancelWaitDelay(ctx, time.Second)
+	ctx = WithForceNoNsenter(ctx, true)
+	ctx = WithDefaultVolumeGroup(ctx, "vg")
+	ctx = WithValue(ctx, "request", "42")
+	// a foreign key of the same underlying type must not shadow lvm2go values
+	ctx = context.WithValue(ctx, 0, "foreign")
+
+	config := ContextConfig(ctx)
+	if config.Environment["LVM_SUPPRESS_FD_WARNINGS"] != "1" {
+		t.Fatalf("unexpected environment %v", config.Environment)
+	}
+	if config.WaitDelay == nil || *config.WaitDelay != time.Second {
+		t.Fatalf("unexpected wait delay %v", config.WaitDelay)
+	}
+	if config.ForceNoNsenter == nil || !*config.ForceNoNsenter || config.UseNsenter {
+		t.Fatalf("expected nsenter to be disabled, got %+v", config)
+	}
+	if config.DefaultVolumeGroup != "vg" || config.LogFields["request"] != "42" {
+		t.Fatalf("unexpected configuration %+v", config)
+	}
+
+	if logged := config.LogValue().String(); !strings.Contains(logged, "defaultVolumeGroup=vg") {
+		t.Fatalf("expected default volume group in log value, got %s", logged)
+	}
+}
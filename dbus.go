@@ -0,0 +1,260 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	lvmDBusService       = "com.redhat.lvmdbus1"
+	lvmDBusRootPath      = "/com/redhat/lvmdbus1"
+	lvmDBusManagerPath   = "/com/redhat/lvmdbus1/Manager"
+	lvmDBusInterface     = "com.redhat.lvmdbus1"
+	lvmDBusManager       = lvmDBusInterface + ".Manager"
+	lvmDBusPv            = lvmDBusInterface + ".Pv"
+	lvmDBusVg            = lvmDBusInterface + ".Vg"
+	lvmDBusLvCommon      = lvmDBusInterface + ".LvCommon"
+	lvmDBusLv            = lvmDBusInterface + ".Lv"
+	lvmDBusThinPool      = lvmDBusInterface + ".ThinPool"
+	lvmDBusSnapshot      = lvmDBusInterface + ".Snapshot"
+	lvmDBusJob           = lvmDBusInterface + ".Job"
+	lvmDBusObjectManager = "org.freedesktop.DBus.ObjectManager"
+	lvmDBusProperties    = "org.freedesktop.DBus.Properties"
+	lvmDBusNoObject      = "/"
+)
+
+var (
+	// DefaultDBusCallTimeout is the time lvmdbusd waits for an operation to finish before
+	// it returns a job object for the operation instead. The job is then awaited by the client.
+	DefaultDBusCallTimeout = 15 * time.Second
+	// DefaultDBusJobPollInterval is the time the client waits for a job in a single call to lvmdbusd.
+	// The context passed to the client is checked between these calls.
+	DefaultDBusJobPollInterval = 1 * time.Second
+)
+
+// dbusConn calls a method of an lvmdbusd object and returns the values of the reply.
+// The method is qualified by its interface, e.g. "com.redhat.lvmdbus1.Vg.Remove".
+type dbusConn func(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error)
+
+// systemBusConn calls lvmdbusd over the shared connection to the system bus.
+// The connection is established on the first call and reused afterward.
+func systemBusConn(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error) {
+	conn, err := dbus.SystemBus()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the system bus: %w", err)
+	}
+	call := conn.Object(lvmDBusService, path).CallWithContext(ctx, method, 0, args...)
+	return call.Body, call.Err
+}
+
+// dbusVariant is a property value of an lvmdbusd object.
+type dbusVariant dbus.Variant
+
+// dbusObjects are the managed objects of lvmdbusd keyed by object path, interface and property.
+type dbusObjects map[string]map[string]map[string]dbusVariant
+
+// dbusRange is a range of physical extents on a physical volume, 0, 0 means the whole physical volume.
+type dbusRange struct {
+	PV    dbus.ObjectPath
+	First uint64
+	Last  uint64
+}
+
+// dbusDeviceNumber is the major and minor number of a device.
+type dbusDeviceNumber struct {
+	Major int32
+	Minor int32
+}
+
+// call calls a method of lvmdbusd and stores the values of the reply in dest.
+func (c *dbusClient) call(ctx context.Context, dest []any, path, iface, method string, args ...any) error {
+	reply, err := c.conn(ctx, dbus.ObjectPath(path), iface+"."+method, args...)
+	if err != nil {
+		return fmt.Errorf("failed to call %s.%s on %s: %w", iface, method, path, err)
+	}
+	if len(dest) == 0 {
+		return nil
+	}
+	if err := dbus.Store(reply, dest...); err != nil {
+		return fmt.Errorf("unexpected reply of %s.%s: %w", iface, method, err)
+	}
+	return nil
+}
+
+// property reads a single property of an lvmdbusd object into v.
+func (c *dbusClient) property(ctx context.Context, v any, path, iface, name string) error {
+	var value dbus.Variant
+	if err := c.call(ctx, []any{&value}, path, lvmDBusProperties, "Get", iface, name); err != nil {
+		return fmt.Errorf("failed to get property %s.%s of %s: %w", iface, name, path, err)
+	}
+	if err := dbus.Store([]any{value.Value()}, v); err != nil {
+		return fmt.Errorf("unexpected value of property %s.%s: %w", iface, name, err)
+	}
+	return nil
+}
+
+// objects returns all objects managed by lvmdbusd.
+func (c *dbusClient) objects(ctx context.Context) (dbusObjects, error) {
+	var reply map[dbus.ObjectPath]map[string]map[string]dbus.Variant
+	if err := c.call(ctx, []any{&reply}, lvmDBusRootPath, lvmDBusObjectManager, "GetManagedObjects"); err != nil {
+		return nil, err
+	}
+	objs := make(dbusObjects, len(reply))
+	for path, ifaces := range reply {
+		objs[string(path)] = make(map[string]map[string]dbusVariant, len(ifaces))
+		for iface, props := range ifaces {
+			objs[string(path)][iface] = make(map[string]dbusVariant, len(props))
+			for name, value := range props {
+				objs[string(path)][iface][name] = dbusVariant(value)
+			}
+		}
+	}
+	return objs, nil
+}
+
+// jobArgs appends the timeout and empty options that every method of lvmdbusd returning a job takes.
+func jobArgs(args ...any) []any {
+	return append(args, int32(DefaultDBusCallTimeout.Seconds()), map[string]dbus.Variant{})
+}
+
+// callJob calls a method of lvmdbusd that returns an object and a job.
+// If lvmdbusd returns a job, the job is awaited and its result is returned.
+func (c *dbusClient) callJob(ctx context.Context, path, iface, method string, args ...any) (string, error) {
+	var result, job dbus.ObjectPath
+	if err := c.call(ctx, []any{&result, &job}, path, iface, method, jobArgs(args...)...); err != nil {
+		return "", err
+	}
+	if job == lvmDBusNoObject {
+		return string(result), nil
+	}
+	return c.awaitJob(ctx, string(job))
+}
+
+// callVoidJob is callJob for methods that only return a job.
+func (c *dbusClient) callVoidJob(ctx context.Context, path, iface, method string, args ...any) error {
+	var job dbus.ObjectPath
+	if err := c.call(ctx, []any{&job}, path, iface, method, jobArgs(args...)...); err != nil {
+		return err
+	}
+	if job != lvmDBusNoObject {
+		_, err := c.awaitJob(ctx, string(job))
+		return err
+	}
+	return nil
+}
+
+// awaitJob waits for a job of lvmdbusd to complete and returns its result.
+// The job is removed from lvmdbusd afterward.
+func (c *dbusClient) awaitJob(ctx context.Context, job string) (result string, err error) {
+	defer func() {
+		removeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultDBusCallTimeout)
+		defer cancel()
+		if removeErr := c.call(removeCtx, nil, job, lvmDBusJob, "Remove"); removeErr != nil {
+			err = errors.Join(err, removeErr)
+		}
+	}()
+
+	wait := int32(DefaultDBusJobPollInterval.Seconds())
+	for {
+		if err := ctx.Err(); err != nil {
+			return "", fmt.Errorf("aborted waiting for job %s: %w", job, err)
+		}
+		var complete bool
+		if err := c.call(ctx, []any{&complete}, job, lvmDBusJob, "Wait", wait); err != nil {
+			return "", err
+		}
+		if complete {
+			break
+		}
+	}
+
+	var jobErr struct {
+		Code    int32
+		Message string
+	}
+	if err := c.property(ctx, &jobErr, job, lvmDBusJob, "GetError"); err != nil {
+		return "", err
+	}
+	if jobErr.Code != 0 {
+		return "", fmt.Errorf("job %s failed with code %d: %s", job, jobErr.Code, jobErr.Message)
+	}
+
+	var path dbus.ObjectPath
+	if err := c.property(ctx, &path, job, lvmDBusJob, "Result"); err != nil {
+		return "", err
+	}
+	return string(path), nil
+}
+
+// objectPaths converts object paths to a D-Bus array of objects.
+func objectPaths(paths []string) []dbus.ObjectPath {
+	objs := make([]dbus.ObjectPath, 0, len(paths))
+	for _, path := range paths {
+		objs = append(objs, dbus.ObjectPath(path))
+	}
+	return objs
+}
+
+func (v dbusVariant) value() any {
+	return dbus.Variant(v).Value()
+}
+
+func (v dbusVariant) string() string {
+	switch s := v.value().(type) {
+	case string:
+		return s
+	case dbus.ObjectPath:
+		return string(s)
+	}
+	return ""
+}
+
+func (v dbusVariant) strings() []string {
+	s, _ := v.value().([]string)
+	return s
+}
+
+// int64 converts any integer property, lvmdbusd uses different integer types for its properties.
+func (v dbusVariant) int64() int64 {
+	val := reflect.ValueOf(v.value())
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(val.Uint())
+	}
+	return 0
+}
+
+func (v dbusVariant) float64() float64 {
+	val := reflect.ValueOf(v.value())
+	if val.Kind() == reflect.Float32 || val.Kind() == reflect.Float64 {
+		return val.Float()
+	}
+	return float64(v.int64())
+}
+
+func (v dbusVariant) bytes() Size {
+	return NewSize(v.float64(), UnitBytes)
+}
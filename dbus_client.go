@@ -0,0 +1,1066 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// ErrUnsupportedByDBusClient is returned by the client returned from NewDBusClient
+// for operations and options that lvmdbusd does not provide.
+var ErrUnsupportedByDBusClient = fmt.Errorf("not supported by lvmdbusd: %w", errors.ErrUnsupported)
+
+type dbusClient struct {
+	exec *client
+	conn dbusConn
+}
+
+var _ Client = (*dbusClient)(nil)
+
+// NewDBusClient returns a Client that talks to lvmdbusd over the system D-Bus instead of running the lvm2 CLI.
+// This avoids parsing of lvm2 CLI output and works in containers that have access to the system bus
+// but are not allowed to use nsenter.
+// Long-running operations are tracked through the job objects of lvmdbusd, see DefaultDBusCallTimeout.
+//
+// The client connects to the system bus on its first call and shares the connection with other clients.
+// It does not run external commands for operations of lvmdbusd; the given options only configure
+// the few helpers that still run commands in the namespaces of the caller, such as the device-mapper targets
+// of Version, in the same way as for NewClient, except for NsenterPolicy. With a ManagedTag, the client is wrapped in NewTagGuardClient.
+//
+// lvmdbusd does not cover the full lvm2 CLI. Operations or options that cannot be mapped to lvmdbusd,
+// such as the DevicesClient, configuration handling or selection with Select,
+// return an error wrapping ErrUnsupportedByDBusClient.
+// Sizes reported by the client are always in bytes, independent of the requested Unit.
+//
+// Example usage:
+//
+//	client := lvm2go.NewDBusClient()
+//	vgs, err := client.VGs(ctx)
+func NewDBusClient(opts ...ClientOption) Client {
+	return newDBusClient(systemBusConn, opts...)
+}
+
+func newDBusClient(conn dbusConn, opts ...ClientOption) Client {
+	c := &dbusClient{exec: &client{}, conn: conn}
+	ClientOptionList(opts).ApplyToClientOptions(&c.exec.opts)
+	c.exec.opts.NsenterPolicy = NsenterPolicyNever
+	if c.exec.opts.ManagedTag != "" {
+		return NewTagGuardClient(c, string(c.exec.opts.ManagedTag))
+	}
+	return c
+}
+
+// dbusUnsupportedOptions returns an error if any option in opts other than the supported ones is set.
+func dbusUnsupportedOptions(opts any, supported ...string) error {
+	val := reflect.Indirect(reflect.ValueOf(opts))
+	for i := range val.NumField() {
+		name := val.Type().Field(i).Name
+		if name == "CommonOptions" && slices.Contains(supported, name) {
+			// lvmdbusd runs the commands itself, so locking and udev cannot be changed per call
+			if err := dbusUnsupportedOptions(val.Field(i).Addr().Interface(),
+				"Devices", "DevicesFile", "Profile", "Verbose", "RequestConfirm"); err != nil {
+				return err
+			}
+			continue
+		}
+		if slices.Contains(supported, name) || val.Field(i).IsZero() {
+			continue
+		}
+		return fmt.Errorf("%w: option %s", ErrUnsupportedByDBusClient, name)
+	}
+	return nil
+}
+
+// lookup returns the object path of a physical volume, volume group or logical volume by its lvm2 id,
+// e.g. "/dev/sdb", "vg" or "vg/lv". If no object is found, "/" is returned.
+func (c *dbusClient) lookup(ctx context.Context, id string) (string, error) {
+	var path dbus.ObjectPath
+	if err := c.call(ctx, []any{&path}, lvmDBusManagerPath, lvmDBusManager, "LookUpByLvmId", id); err != nil {
+		return "", err
+	}
+	return string(path), nil
+}
+
+func (c *dbusClient) vgPath(ctx context.Context, vg VolumeGroupName) (string, error) {
+	if vg == "" {
+		return "", ErrVolumeGroupNameRequired
+	}
+	path, err := c.lookup(ctx, string(vg))
+	if err != nil {
+		return "", err
+	}
+	if path == lvmDBusNoObject {
+		return "", fmt.Errorf("%w: %s", ErrVolumeGroupNotFound, vg)
+	}
+	return path, nil
+}
+
+func (c *dbusClient) lvPath(ctx context.Context, vg VolumeGroupName, lv LogicalVolumeName) (string, error) {
+	fq, err := NewFQLogicalVolumeName(vg, lv)
+	if err != nil {
+		return "", err
+	}
+	if err := fq.Validate(); err != nil {
+		return "", err
+	}
+	path, err := c.lookup(ctx, fq.String())
+	if err != nil {
+		return "", err
+	}
+	if path == lvmDBusNoObject {
+		return "", fmt.Errorf("%w: %s", ErrLogicalVolumeNotFound, fq)
+	}
+	return path, nil
+}
+
+// pvPaths returns the object paths of the given physical volumes.
+// If create is set, devices that are not yet physical volumes are initialized, like vgcreate and vgextend do.
+func (c *dbusClient) pvPaths(ctx context.Context, names PhysicalVolumeNames, create bool) ([]string, error) {
+	paths := make([]string, 0, len(names))
+	for _, name := range names {
+		path, err := c.lookup(ctx, string(name))
+		if err != nil {
+			return nil, err
+		}
+		if path == lvmDBusNoObject {
+			if !create {
+				return nil, fmt.Errorf("physical volume %s not found", name)
+			}
+			if path, err = c.callJob(ctx, lvmDBusManagerPath, lvmDBusManager, "PvCreate", string(name)); err != nil {
+				return nil, err
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+func (c *dbusClient) addTags(ctx context.Context, path, iface string, tags Tags) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return c.callVoidJob(ctx, path, iface, "TagsAdd", []string(tags))
+}
+
+func (c *dbusClient) delTags(ctx context.Context, path, iface string, tags DelTags) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	return c.callVoidJob(ctx, path, iface, "TagsDel", []string(tags))
+}
+
+func (objs dbusObjects) name(path, iface string) string {
+	if path == "" || path == lvmDBusNoObject {
+		return ""
+	}
+	return objs[path][iface]["Name"].string()
+}
+
+func (objs dbusObjects) physicalVolume(props map[string]dbusVariant) *PhysicalVolume {
+	return &PhysicalVolume{
+		UUID:    props["Uuid"].string(),
+		Name:    PhysicalVolumeName(props["Name"].string()),
+		DevSize: props["DevSizeBytes"].bytes(),
+		MdaFree: props["MdaFreeBytes"].bytes(),
+		MdaSize: props["MdaSizeBytes"].bytes(),
+		PeStart: props["PeStart"].bytes(),
+		Size:    props["SizeBytes"].bytes(),
+		Free:    props["FreeBytes"].bytes(),
+		Used:    props["UsedBytes"].bytes(),
+		Tags:    props["Tags"].strings(),
+		VGName:  VolumeGroupName(objs.name(props["Vg"].string(), lvmDBusVg)),
+	}
+}
+
+func (objs dbusObjects) volumeGroup(props map[string]dbusVariant) *VolumeGroup {
+	return &VolumeGroup{
+		UUID:         props["Uuid"].string(),
+		Name:         VolumeGroupName(props["Name"].string()),
+		SysID:        props["SysId"].string(),
+		SystemID:     props["SysId"].string(),
+		Tags:         props["Tags"].strings(),
+		ExtentSize:   props["ExtentSizeBytes"].bytes(),
+		ExtentCount:  props["ExtentCount"].int64(),
+		SeqNo:        props["SeqNo"].int64(),
+		Size:         props["SizeBytes"].bytes(),
+		Free:         props["FreeBytes"].bytes(),
+		FreeCount:    props["FreeCount"].int64(),
+		PvCount:      props["PvCount"].int64(),
+		MaxPv:        props["MaxPv"].int64(),
+		LvCount:      props["LvCount"].int64(),
+		MaxLv:        props["MaxLv"].int64(),
+		SnapCount:    props["SnapCount"].int64(),
+		MDACount:     props["MdaCount"].int64(),
+		MDAUsedCount: props["MdaUsedCount"].int64(),
+		MDAFree:      props["MdaFreeBytes"].bytes(),
+		MDASize:      props["MdaSizeBytes"].bytes(),
+	}
+}
+
+func (objs dbusObjects) logicalVolume(props map[string]dbusVariant) (*LogicalVolume, error) {
+	lv := &LogicalVolume{
+		UUID:              props["Uuid"].string(),
+		Name:              LogicalVolumeName(props["Name"].string()),
+		Path:              props["Path"].string(),
+		Tags:              props["Tags"].strings(),
+		Size:              props["SizeBytes"].bytes(),
+		MetadataSize:      props["MetaDataSizeBytes"].bytes(),
+		Origin:            objs.name(props["OriginLv"].string(), lvmDBusLvCommon),
+		PoolLogicalVolume: objs.name(props["PoolLv"].string(), lvmDBusLvCommon),
+		VolumeGroupName:   VolumeGroupName(objs.name(props["Vg"].string(), lvmDBusVg)),
+		DataPercent:       props["DataPercent"].float64(),
+		MetadataPercent:   props["MetaDataPercent"].float64(),
+		SnapPercent:       props["SnapPercent"].float64(),
+		SyncPercent:       props["SyncPercent"].float64(),
+		MovePV:            PhysicalVolumeName(objs.name(props["MovePv"].string(), lvmDBusPv)),
+		CopyPercent:       props["CopyPercent"].float64(),
+	}
+	lv.FullName = fmt.Sprintf("%s/%s", lv.VolumeGroupName, lv.Name)
+	lv.Internal = internalTypeOf(lv.Name)
+	if attr := props["Attr"].string(); attr != "" {
+		var err error
+		if lv.Attr, err = ParseLVAttributes(attr); err != nil {
+			return nil, err
+		}
+	}
+	return lv, nil
+}
+
+// sortedPaths returns the object paths implementing iface in a stable order.
+func (objs dbusObjects) sortedPaths(iface string) []string {
+	var paths []string
+	for path, ifaces := range objs {
+		if _, ok := ifaces[iface]; ok {
+			paths = append(paths, path)
+		}
+	}
+	slices.Sort(paths)
+	return paths
+}
+
+func matchesTags(tags Tags, filter Tags) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, tag := range filter {
+		if slices.Contains(tags, strings.TrimPrefix(tag, TagSymbol)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *dbusClient) LV(ctx context.Context, opts ...LVsOption) (*LogicalVolume, error) {
+	options := LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, ErrVolumeGroupNameRequired
+	}
+	if options.LogicalVolumeName == "" {
+		return nil, ErrLogicalVolumeNameRequired
+	}
+	lvs, err := c.LVs(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(lvs) == 0 {
+		return nil, ErrLogicalVolumeNotFound
+	}
+	return lvs[0], nil
+}
+
+func (c *dbusClient) LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalVolume, error) {
+	options := LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "LogicalVolumeName", "VolumeGroupNames", "FQLogicalVolumeNames", "Tags", "All", "ByUUID", "Unit", "ColumnOptions", "CommonOptions"); err != nil {
+		return nil, err
+	}
+
+	objs, err := c.objects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var lvs []*LogicalVolume
+	for _, path := range objs.sortedPaths(lvmDBusLvCommon) {
+		lv, err := objs.logicalVolume(objs[path][lvmDBusLvCommon])
+		if err != nil {
+			return nil, err
+		}
+		// hidden logical volumes are not reported by lvs without --all
+		if lv.IsInternal() && !bool(options.All) {
+			continue
+		}
+		if !options.InScope(lv.VolumeGroupName, lv.Name) {
+			continue
+		}
+		if !matchesTags(lv.Tags, options.Tags) {
+			continue
+		}
+		if options.ByUUID != "" && lv.UUID != string(options.ByUUID) {
+			continue
+		}
+		lvs = append(lvs, lv)
+	}
+	return lvs, nil
+}
+
+func (c *dbusClient) LVSegments(context.Context, ...LVsOption) ([]*LogicalVolumeSegment, error) {
+	return nil, fmt.Errorf("LVSegments: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) LVCreate(ctx context.Context, opts ...LVCreateOption) error {
+	options := LVCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVCreateOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options,
+		"VolumeGroupName", "LogicalVolumeName", "Tags", "Size", "Extents", "VirtualSize",
+		"Type", "Thin", "ThinPool", "SnapshotOf", "Stripes", "StripeSize", "CommonOptions",
+	); err != nil {
+		return err
+	}
+	if options.Type != "" && options.Type != TypeLinear && options.Type != TypeStriped {
+		return fmt.Errorf("%w: type %s", ErrUnsupportedByDBusClient, options.Type)
+	}
+
+	vg := options.VolumeGroupName
+	switch {
+	case options.ThinPool != nil:
+		vg = options.ThinPool.VolumeGroupName
+	case options.SnapshotOf != nil:
+		vg = options.SnapshotOf.VolumeGroupName
+	}
+	vgPath, err := c.vgPath(ctx, vg)
+	if err != nil {
+		return err
+	}
+
+	size, err := c.sizeInBytes(ctx, vgPath, options.Size, options.Extents)
+	if err != nil {
+		return err
+	}
+	name := string(options.LogicalVolumeName)
+
+	var lvPath string
+	switch {
+	case options.ThinPool != nil:
+		poolPath, err := c.lvPath(ctx, options.ThinPool.VolumeGroupName, options.ThinPool.LogicalVolumeName)
+		if err != nil {
+			return err
+		}
+		virtual, err := Size(options.VirtualSize).ToUnit(UnitBytes)
+		if err != nil {
+			return err
+		}
+		lvPath, err = c.callJob(ctx, poolPath, lvmDBusThinPool, "LvCreate", name, uint64(virtual.Val))
+		if err != nil {
+			return err
+		}
+	case options.SnapshotOf != nil:
+		originPath, err := c.lvPath(ctx, options.SnapshotOf.VolumeGroupName, options.SnapshotOf.LogicalVolumeName)
+		if err != nil {
+			return err
+		}
+		lvPath, err = c.callJob(ctx, originPath, lvmDBusLv, "Snapshot", name, uint64(size))
+		if err != nil {
+			return err
+		}
+	case options.Stripes > 0 || options.Type == TypeStriped:
+		// a stripe size of 0 lets lvm2 choose the default stripe size
+		stripeSize := Size{Unit: UnitKiB}
+		if options.StripeSize.Val > 0 {
+			if stripeSize, err = Size(options.StripeSize).ToUnit(UnitKiB); err != nil {
+				return err
+			}
+		}
+		lvPath, err = c.callJob(ctx, vgPath, lvmDBusVg, "LvCreateStriped",
+			name, uint64(size), uint32(options.Stripes), uint32(stripeSize.Val), bool(options.Thin))
+		if err != nil {
+			return err
+		}
+	default:
+		lvPath, err = c.callJob(ctx, vgPath, lvmDBusVg, "LvCreateLinear",
+			name, uint64(size), bool(options.Thin))
+		if err != nil {
+			return err
+		}
+	}
+
+	return c.addTags(ctx, lvPath, lvmDBusLv, options.Tags)
+}
+
+// sizeInBytes returns the size in bytes from either size or extents, using the extent size of the volume group.
+func (c *dbusClient) sizeInBytes(ctx context.Context, vgPath string, size Size, extents Extents) (float64, error) {
+	if size.Val > 0 {
+		bytes, err := size.ToUnit(UnitBytes)
+		if err != nil {
+			return 0, err
+		}
+		return bytes.Val, nil
+	}
+	if extents.Val == 0 {
+		return 0, nil
+	}
+	if extents.ExtentPercent != "" {
+		return 0, fmt.Errorf("%w: extents relative to %s", ErrUnsupportedByDBusClient, extents.ExtentPercent)
+	}
+	var extentSize uint64
+	if err := c.property(ctx, &extentSize, vgPath, lvmDBusVg, "ExtentSizeBytes"); err != nil {
+		return 0, err
+	}
+	return float64(extents.Val * extentSize), nil
+}
+
+func (c *dbusClient) LVRemove(ctx context.Context, opts ...LVRemoveOption) error {
+	options := LVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRemoveOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "LogicalVolumeName", "VolumeGroupName", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.lvPath(ctx, options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusLv, "Remove")
+}
+
+// resize resizes a logical volume to its current size changed by the given prefixed size.
+func (c *dbusClient) resize(ctx context.Context, vg VolumeGroupName, lv LogicalVolumeName, size PrefixedSize) error {
+	path, err := c.lvPath(ctx, vg, lv)
+	if err != nil {
+		return err
+	}
+	bytes, err := size.Size.ToUnit(UnitBytes)
+	if err != nil {
+		return err
+	}
+	var current uint64
+	if err := c.property(ctx, &current, path, lvmDBusLvCommon, "SizeBytes"); err != nil {
+		return err
+	}
+	target := bytes.Val
+	switch size.SizePrefix {
+	case SizePrefixPlus:
+		target = float64(current) + bytes.Val
+	case SizePrefixMinus:
+		target = float64(current) - bytes.Val
+	}
+	if target <= 0 {
+		return fmt.Errorf("cannot resize %s/%s to %v bytes: %w", vg, lv, target, ErrInvalidSizeGEZero)
+	}
+	return c.callVoidJob(ctx, path, lvmDBusLv, "Resize", uint64(target), []dbusRange{})
+}
+
+func (c *dbusClient) LVResize(ctx context.Context, opts ...LVResizeOption) error {
+	options := LVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVResizeOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "LogicalVolumeName", "VolumeGroupName", "PrefixedSize", "CommonOptions"); err != nil {
+		return err
+	}
+	return c.resize(ctx, options.VolumeGroupName, options.LogicalVolumeName, options.PrefixedSize)
+}
+
+func (c *dbusClient) LVExtend(ctx context.Context, opts ...LVExtendOption) error {
+	options := LVExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVExtendOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "LogicalVolumeName", "PrefixedSize", "PrefixedExtents", "CommonOptions"); err != nil {
+		return err
+	}
+	if options.PrefixedSize.SizePrefix == SizePrefixMinus || options.PrefixedExtents.SizePrefix == SizePrefixMinus {
+		return fmt.Errorf("size prefix must be positive")
+	}
+
+	size := options.PrefixedSize
+	if size.Val == 0 {
+		vgPath, err := c.vgPath(ctx, options.VolumeGroupName)
+		if err != nil {
+			return err
+		}
+		bytes, err := c.sizeInBytes(ctx, vgPath, Size{}, options.PrefixedExtents.Extents)
+		if err != nil {
+			return err
+		}
+		size = NewPrefixedSize(options.PrefixedExtents.SizePrefix, NewSize(bytes, UnitBytes))
+	}
+	if size.Val == 0 {
+		return errors.New("size or extents must be specified")
+	}
+	return c.resize(ctx, options.VolumeGroupName, options.LogicalVolumeName, size)
+}
+
+func (c *dbusClient) LVReduce(context.Context, ...LVReduceOption) error {
+	return fmt.Errorf("LVReduce: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) LVRename(ctx context.Context, opts ...LVRenameOption) error {
+	options := LVRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRenameOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "Old", "New", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.lvPath(ctx, options.VolumeGroupName, options.Old)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusLv, "Rename", string(options.New))
+}
+
+func (c *dbusClient) LVChange(ctx context.Context, opts ...LVChangeOption) error {
+	options := LVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVChangeOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "LogicalVolumeName", "Tags", "DelTags", "ActivationState", "RequestConfirm", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.lvPath(ctx, options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if err := c.activate(ctx, path, lvmDBusLv, options.ActivationState); err != nil {
+		return err
+	}
+	if err := c.addTags(ctx, path, lvmDBusLv, options.Tags); err != nil {
+		return err
+	}
+	return c.delTags(ctx, path, lvmDBusLv, options.DelTags)
+}
+
+// LVConvert only supports merging snapshots with MergeSnapshot.
+func (c *dbusClient) LVConvert(ctx context.Context, opts ...LVConvertOption) error {
+	options := LVConvertOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVConvertOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "LogicalVolumeName", "MergeSnapshot", "CommonOptions"); err != nil {
+		return err
+	}
+	if !options.MergeSnapshot {
+		return fmt.Errorf("LVConvert: %w", ErrUnsupportedByDBusClient)
+	}
+	path, err := c.lvPath(ctx, options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusSnapshot, "Merge")
+}
+
+// activate activates or deactivates a logical volume.
+func (c *dbusClient) activate(ctx context.Context, path, iface string, state ActivationState) error {
+	switch state {
+	case "":
+		return nil
+	case Activate:
+		return c.callVoidJob(ctx, path, iface, "Activate", uint64(0))
+	case Deactivate:
+		return c.callVoidJob(ctx, path, iface, "Deactivate", uint64(0))
+	default:
+		return fmt.Errorf("%w: activation state %s", ErrUnsupportedByDBusClient, state)
+	}
+}
+
+func (c *dbusClient) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error) {
+	options := VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, ErrVolumeGroupNameRequired
+	}
+	vgs, err := c.VGs(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(vgs) == 0 {
+		return nil, ErrVolumeGroupNotFound
+	}
+	return vgs[0], nil
+}
+
+func (c *dbusClient) VGs(ctx context.Context, opts ...VGsOption) ([]*VolumeGroup, error) {
+	options := VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "VolumeGroupNames", "Tags", "ByUUID", "Unit", "ColumnOptions", "CommonOptions"); err != nil {
+		return nil, err
+	}
+
+	objs, err := c.objects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var vgs []*VolumeGroup
+	for _, path := range objs.sortedPaths(lvmDBusVg) {
+		vg := objs.volumeGroup(objs[path][lvmDBusVg])
+		if !options.InScope(vg.Name) {
+			continue
+		}
+		if !matchesTags(vg.Tags, options.Tags) {
+			continue
+		}
+		if options.ByUUID != "" && vg.UUID != string(options.ByUUID) {
+			continue
+		}
+		vgs = append(vgs, vg)
+	}
+	return vgs, nil
+}
+
+func (c *dbusClient) VGCreate(ctx context.Context, opts ...VGCreateOption) error {
+	options := VGCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCreateOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "Tags", "PhysicalVolumeNames", "CommonOptions"); err != nil {
+		return err
+	}
+	if options.VolumeGroupName == "" {
+		return ErrVolumeGroupNameRequired
+	}
+	pvs, err := c.pvPaths(ctx, options.PhysicalVolumeNames, true)
+	if err != nil {
+		return err
+	}
+	path, err := c.callJob(ctx, lvmDBusManagerPath, lvmDBusManager, "VgCreate",
+		string(options.VolumeGroupName), objectPaths(pvs))
+	if err != nil {
+		return err
+	}
+	return c.addTags(ctx, path, lvmDBusVg, options.Tags)
+}
+
+func (c *dbusClient) VGRemove(ctx context.Context, opts ...VGRemoveOption) error {
+	options := VGRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRemoveOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.vgPath(ctx, options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusVg, "Remove")
+}
+
+func (c *dbusClient) VGExtend(ctx context.Context, opts ...VGExtendOption) error {
+	options := VGExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGExtendOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "PhysicalVolumeNames", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.vgPath(ctx, options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	pvs, err := c.pvPaths(ctx, options.PhysicalVolumeNames, true)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusVg, "Extend", objectPaths(pvs))
+}
+
+func (c *dbusClient) VGReduce(ctx context.Context, opts ...VGReduceOption) error {
+	options := VGReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGReduceOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "PhysicalVolumeNames", "RemoveMissing", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.vgPath(ctx, options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	pvs, err := c.pvPaths(ctx, options.PhysicalVolumeNames, false)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusVg, "Reduce",
+		bool(options.RemoveMissing), objectPaths(pvs))
+}
+
+func (c *dbusClient) VGRename(ctx context.Context, opts ...VGRenameOption) error {
+	options := VGRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRenameOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "Old", "New", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.vgPath(ctx, options.Old)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, path, lvmDBusVg, "Rename", string(options.New))
+}
+
+func (c *dbusClient) VGSplit(context.Context, ...VGSplitOption) error {
+	return fmt.Errorf("VGSplit: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGMerge(context.Context, ...VGMergeOption) error {
+	return fmt.Errorf("VGMerge: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGExport(context.Context, ...VGExportOption) error {
+	return fmt.Errorf("VGExport: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGImport(context.Context, ...VGImportOption) error {
+	return fmt.Errorf("VGImport: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGImportClone(context.Context, ...VGImportCloneOption) error {
+	return fmt.Errorf("VGImportClone: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGCfgBackup(context.Context, ...VGCfgBackupOption) error {
+	return fmt.Errorf("VGCfgBackup: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGCfgRestore(context.Context, ...VGCfgRestoreOption) error {
+	return fmt.Errorf("VGCfgRestore: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGCheck(context.Context, ...VGCheckOption) (*VGCheckResult, error) {
+	return nil, fmt.Errorf("VGCheck: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) PVCheck(context.Context, ...PVCheckOption) (*PVCheckResult, error) {
+	return nil, fmt.Errorf("PVCheck: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) VGScan(context.Context, ...VGScanOption) error {
+	return fmt.Errorf("VGScan: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) LVScan(context.Context, ...LVScanOption) error {
+	return fmt.Errorf("LVScan: %w", ErrUnsupportedByDBusClient)
+}
+
+// PVScan calls Manager.PvScan of lvmdbusd, which runs pvscan --cache, optionally with autoactivation.
+func (c *dbusClient) PVScan(ctx context.Context, opts ...PVScanOption) error {
+	options := PVScanOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVScanOptions(&options)
+	}
+	if _, err := PVScanOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+	if err := dbusUnsupportedOptions(&options, "Cache", "ActivationState", "PhysicalVolumeNames", "DeviceNumbers", "CommonOptions"); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(options.PhysicalVolumeNames))
+	for _, name := range options.PhysicalVolumeNames {
+		names = append(names, string(name))
+	}
+	devs := make([]dbusDeviceNumber, 0, len(options.DeviceNumbers))
+	for _, dev := range options.DeviceNumbers {
+		devs = append(devs, dbusDeviceNumber{Major: int32(dev.Major), Minor: int32(dev.Minor)})
+	}
+	return c.callVoidJob(ctx, lvmDBusManagerPath, lvmDBusManager, "PvScan",
+		options.ActivationState == AutoActivate, bool(options.Cache), names, devs)
+}
+
+func (c *dbusClient) VGChange(ctx context.Context, opts ...VGChangeOption) error {
+	options := VGChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGChangeOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "VolumeGroupName", "Tags", "DelTags", "CommonOptions"); err != nil {
+		return err
+	}
+	path, err := c.vgPath(ctx, options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	if err := c.addTags(ctx, path, lvmDBusVg, options.Tags); err != nil {
+		return err
+	}
+	return c.delTags(ctx, path, lvmDBusVg, options.DelTags)
+}
+
+func (c *dbusClient) PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume, error) {
+	options := PVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVsOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "PhysicalVolumeNames", "Tags", "ByUUID", "Unit", "ColumnOptions", "CommonOptions"); err != nil {
+		return nil, err
+	}
+
+	objs, err := c.objects(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var pvs []*PhysicalVolume
+	for _, path := range objs.sortedPaths(lvmDBusPv) {
+		pv := objs.physicalVolume(objs[path][lvmDBusPv])
+		if !options.InScope(pv.Name) {
+			continue
+		}
+		if !matchesTags(pv.Tags, options.Tags) {
+			continue
+		}
+		if options.ByUUID != "" && pv.UUID != string(options.ByUUID) {
+			continue
+		}
+		pvs = append(pvs, pv)
+	}
+	return pvs, nil
+}
+
+func (c *dbusClient) PVSegments(context.Context, ...PVsOption) ([]*PhysicalVolumeSegment, error) {
+	return nil, fmt.Errorf("PVSegments: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) PVCreate(ctx context.Context, opts ...PVCreateOption) error {
+	options := PVCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVCreateOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "PhysicalVolumeName", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	if options.PhysicalVolumeName == "" {
+		return ErrPhysicalVolumeNameRequired
+	}
+	_, err := c.callJob(ctx, lvmDBusManagerPath, lvmDBusManager, "PvCreate", "s", string(options.PhysicalVolumeName))
+	return err
+}
+
+func (c *dbusClient) PVRemove(ctx context.Context, opts ...PVRemoveOption) error {
+	options := PVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVRemoveOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "PhysicalVolumeName", "Force", "CommonOptions"); err != nil {
+		return err
+	}
+	pvs, err := c.pvPaths(ctx, PhysicalVolumeNames{options.PhysicalVolumeName}, false)
+	if err != nil {
+		return err
+	}
+	return c.callVoidJob(ctx, pvs[0], lvmDBusPv, "Remove")
+}
+
+func (c *dbusClient) PVResize(ctx context.Context, opts ...PVResizeOption) error {
+	options := PVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVResizeOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "PhysicalVolumeName", "CommonOptions"); err != nil {
+		return err
+	}
+	pvs, err := c.pvPaths(ctx, PhysicalVolumeNames{options.PhysicalVolumeName}, false)
+	if err != nil {
+		return err
+	}
+	// a size of 0 resizes the physical volume to the size of its device, like pvresize without --setphysicalvolumesize
+	return c.callVoidJob(ctx, pvs[0], lvmDBusPv, "ReSize", uint64(0))
+}
+
+func (c *dbusClient) PVChange(ctx context.Context, opts ...PVChangeOption) error {
+	options := PVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVChangeOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "PhysicalVolumeName", "Tags", "DelTags", "CommonOptions"); err != nil {
+		return err
+	}
+	objs, err := c.objects(ctx)
+	if err != nil {
+		return err
+	}
+	pvs, err := c.pvPaths(ctx, PhysicalVolumeNames{options.PhysicalVolumeName}, false)
+	if err != nil {
+		return err
+	}
+	// tags of physical volumes are changed through their volume group
+	vgPath := objs[pvs[0]][lvmDBusPv]["Vg"].string()
+	if vgPath == "" || vgPath == lvmDBusNoObject {
+		return fmt.Errorf("%w: tags of physical volumes without volume group", ErrUnsupportedByDBusClient)
+	}
+	if len(options.Tags) > 0 {
+		if err := c.callVoidJob(ctx, vgPath, lvmDBusVg, "PvTagsAdd",
+			objectPaths(pvs), []string(options.Tags)); err != nil {
+			return err
+		}
+	}
+	if len(options.DelTags) > 0 {
+		if err := c.callVoidJob(ctx, vgPath, lvmDBusVg, "PvTagsDel",
+			objectPaths(pvs), []string(options.DelTags)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *dbusClient) PVMove(ctx context.Context, opts ...PVMoveOption) error {
+	options := PVMoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVMoveOptions(&options)
+	}
+	if err := dbusUnsupportedOptions(&options, "From", "To", "CommonOptions"); err != nil {
+		return err
+	}
+	from, err := c.pvPaths(ctx, PhysicalVolumeNames{options.From}, false)
+	if err != nil {
+		return err
+	}
+	to, err := c.pvPaths(ctx, options.To, false)
+	if err != nil {
+		return err
+	}
+	var vgPath dbus.ObjectPath
+	if err := c.property(ctx, &vgPath, from[0], lvmDBusPv, "Vg"); err != nil {
+		return err
+	}
+	// the source is encoded as object and extent range, the destinations as ranges of whole physical volumes
+	dests := make([]dbusRange, 0, len(to))
+	for _, path := range to {
+		dests = append(dests, dbusRange{PV: dbus.ObjectPath(path)})
+	}
+	return c.callVoidJob(ctx, string(vgPath), lvmDBusVg, "Move",
+		dbus.ObjectPath(from[0]), struct{ First, Last uint64 }{}, dests)
+}
+
+func (c *dbusClient) DevList(context.Context, ...DevListOption) ([]DeviceListEntry, error) {
+	return nil, fmt.Errorf("DevList: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) DevCheck(context.Context, ...DevCheckOption) error {
+	return fmt.Errorf("DevCheck: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) DevUpdate(context.Context, ...DevUpdateOption) (*DevUpdateResult, error) {
+	return nil, fmt.Errorf("DevUpdate: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) DevModify(context.Context, ...DevModifyOption) error {
+	return fmt.Errorf("DevModify: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) Version(ctx context.Context, opts ...VersionOption) (Version, error) {
+	var version string
+	if err := c.property(ctx, &version, lvmDBusManagerPath, lvmDBusManager, "Version"); err != nil {
+		return Version{}, err
+	}
+	lvmVersion, err := ParseVersionNumber(version)
+	if err != nil {
+		return Version{}, err
+	}
+	result := Version{LVMVersion: lvmVersion}
+
+	options := VersionOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVersionOptions(&options)
+	}
+	// the targets are a property of the kernel, which is shared with the host
+	if options.TargetVersions {
+		if result.Targets, err = deviceMapperTargets(ctx, c.exec); err != nil {
+			return Version{}, err
+		}
+	}
+	return result, nil
+}
+
+func (c *dbusClient) RawConfig(context.Context, ...ConfigOption) (RawConfig, error) {
+	return nil, fmt.Errorf("RawConfig: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) ReadAndDecodeConfig(context.Context, any, ...ConfigOption) error {
+	return fmt.Errorf("ReadAndDecodeConfig: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) WriteAndEncodeConfig(ctx context.Context, v any, writer io.Writer) error {
+	return c.exec.WriteAndEncodeConfig(ctx, v, writer)
+}
+
+func (c *dbusClient) UpdateGlobalConfig(context.Context, any) error {
+	return fmt.Errorf("UpdateGlobalConfig: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) UpdateLocalConfig(context.Context, any) error {
+	return fmt.Errorf("UpdateLocalConfig: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) UpdateProfileConfig(context.Context, any, Profile) error {
+	return fmt.Errorf("UpdateProfileConfig: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) CreateProfile(context.Context, any, Profile) (string, error) {
+	return "", fmt.Errorf("CreateProfile: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) RemoveProfile(context.Context, Profile) error {
+	return fmt.Errorf("RemoveProfile: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) GetProfilePath(context.Context, Profile) (string, error) {
+	return "", fmt.Errorf("GetProfilePath: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) GetProfileDirectory(context.Context) (string, error) {
+	return "", fmt.Errorf("GetProfileDirectory: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) FullReport(context.Context, ...FullReportOption) (*FullReport, error) {
+	return nil, fmt.Errorf("FullReport: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) LVMDump(context.Context, ...LVMDumpOption) (string, error) {
+	return "", fmt.Errorf("LVMDump: %w", ErrUnsupportedByDBusClient)
+}
+
+func (c *dbusClient) logger() *slog.Logger {
+	return c.exec.logger()
+}
+
+func (c *dbusClient) Defaults(ctx context.Context) Defaults {
+	return c.exec.Defaults(ctx)
+}
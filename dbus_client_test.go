@@ -0,0 +1,259 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// fakeDBusCall is a method call received by a fake lvmdbusd.
+type fakeDBusCall struct {
+	path   dbus.ObjectPath
+	method string
+	args   []any
+}
+
+// fakeDBusObjects returns the reply of GetManagedObjects for the given objects.
+func fakeDBusObjects(objs map[dbus.ObjectPath]map[string]map[string]any) []any {
+	reply := map[dbus.ObjectPath]map[string]map[string]dbus.Variant{}
+	for path, ifaces := range objs {
+		reply[path] = map[string]map[string]dbus.Variant{}
+		for iface, props := range ifaces {
+			reply[path][iface] = map[string]dbus.Variant{}
+			for name, value := range props {
+				reply[path][iface][name] = dbus.MakeVariant(value)
+			}
+		}
+	}
+	return []any{reply}
+}
+
+func TestDBusClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var calls []fakeDBusCall
+	clnt := newDBusClient(func(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error) {
+		calls = append(calls, fakeDBusCall{path, method, args})
+		switch method {
+		case lvmDBusObjectManager + ".GetManagedObjects":
+			return fakeDBusObjects(map[dbus.ObjectPath]map[string]map[string]any{
+				"/com/redhat/lvmdbus1/Vg/0": {lvmDBusVg: {
+					"Name":      "vg",
+					"SizeBytes": uint64(1073741824),
+					"Tags":      []string{"a"},
+				}},
+				"/com/redhat/lvmdbus1/Lv/0": {lvmDBusLvCommon: {
+					"Name":      "lv",
+					"Vg":        dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+					"SizeBytes": uint64(4194304),
+					"Attr":      "-wi-a-----",
+				}},
+				"/com/redhat/lvmdbus1/Lv/1": {lvmDBusLvCommon: {
+					"Name": "[lvol0_pmspare]",
+					"Vg":   dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+				}},
+			}), nil
+		case lvmDBusManager + ".LookUpByLvmId":
+			if args[0] == "vg/lv" {
+				return []any{dbus.ObjectPath("/com/redhat/lvmdbus1/Lv/0")}, nil
+			}
+			return []any{dbus.ObjectPath(lvmDBusNoObject)}, nil
+		case lvmDBusLv + ".Rename":
+			return []any{dbus.ObjectPath("/com/redhat/lvmdbus1/Job/0")}, nil
+		case lvmDBusJob + ".Wait":
+			return []any{true}, nil
+		case lvmDBusProperties + ".Get":
+			switch args[1] {
+			case "GetError":
+				return []any{dbus.MakeVariant([]any{int32(0), ""})}, nil
+			case "Result":
+				return []any{dbus.MakeVariant(dbus.ObjectPath(lvmDBusNoObject))}, nil
+			}
+		case lvmDBusJob + ".Remove", lvmDBusManager + ".PvScan":
+			return []any{dbus.ObjectPath(lvmDBusNoObject)}, nil
+		}
+		t.Fatalf("unexpected call %v", method)
+		return nil, nil
+	})
+
+	vgs, err := clnt.VGs(ctx, Tags{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vgs) != 1 || vgs[0].Name != "vg" || vgs[0].Size != NewSize(1073741824, UnitBytes) {
+		t.Fatalf("unexpected vgs: %v", vgs)
+	}
+
+	lvs, err := clnt.LVs(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 1 || lvs[0].Name != "lv" || lvs[0].VolumeGroupName != "vg" || lvs[0].Attr.State != StateActive {
+		t.Fatalf("unexpected lvs: %v", lvs)
+	}
+
+	calls = nil
+	if err := clnt.LVRename(ctx, &LVRenameOptions{VolumeGroupName: "vg", Old: "lv", New: "lv2"}); err != nil {
+		t.Fatal(err)
+	}
+	rename := calls[1]
+	if rename.path != "/com/redhat/lvmdbus1/Lv/0" || rename.method != lvmDBusLv+".Rename" ||
+		!reflect.DeepEqual(rename.args, []any{"lv2", int32(15), map[string]dbus.Variant{}}) {
+		t.Fatalf("unexpected rename call %v", rename)
+	}
+	if last := calls[len(calls)-1]; last.path != "/com/redhat/lvmdbus1/Job/0" || last.method != lvmDBusJob+".Remove" {
+		t.Fatalf("expected the job to be removed, got %v", last)
+	}
+
+	if err := clnt.PVScan(ctx, Cache(true), AutoActivate, DeviceNumber{Major: 8, Minor: 16}); err != nil {
+		t.Fatal(err)
+	}
+	scan := calls[len(calls)-1]
+	if !reflect.DeepEqual(scan.args, []any{true, true, []string{}, []dbusDeviceNumber{{Major: 8, Minor: 16}}, int32(15), map[string]dbus.Variant{}}) {
+		t.Fatalf("unexpected scan call %v", scan)
+	}
+	if sig := dbus.SignatureOf(scan.args...).String(); sig != "bbasa(ii)ia{sv}" {
+		t.Fatalf("unexpected signature of scan call %s", sig)
+	}
+
+	if err := clnt.VGRemove(ctx, VolumeGroupName("missing")); !errors.Is(err, ErrVolumeGroupNotFound) {
+		t.Fatalf("expected volume group not found, got %v", err)
+	}
+
+	if _, err := clnt.LVs(ctx, Select("lv_name=lv")); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected unsupported error for select, got %v", err)
+	}
+	if _, err := clnt.LVs(ctx, LockOptNoDelay); !errors.Is(err, ErrUnsupportedByDBusClient) {
+		t.Fatalf("expected unsupported error for lock options, got %v", err)
+	}
+}
+
+func TestDBusClientJobError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := newDBusClient(func(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error) {
+		switch method {
+		case lvmDBusManager + ".LookUpByLvmId":
+			return []any{dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0")}, nil
+		case lvmDBusVg + ".Remove":
+			return []any{dbus.ObjectPath("/com/redhat/lvmdbus1/Job/0")}, nil
+		case lvmDBusJob + ".Wait":
+			return []any{true}, nil
+		case lvmDBusProperties + ".Get":
+			return []any{dbus.MakeVariant([]any{int32(5), "volume group in use"})}, nil
+		case lvmDBusJob + ".Remove":
+			return nil, nil
+		}
+		t.Fatalf("unexpected call %v", method)
+		return nil, nil
+	})
+
+	err := clnt.VGRemove(ctx, VolumeGroupName("vg"))
+	if err == nil || err.Error() != "job /com/redhat/lvmdbus1/Job/0 failed with code 5: volume group in use" {
+		t.Fatalf("expected the error of the job, got %v", err)
+	}
+}
+
+func TestDBusClientManagedTag(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := newDBusClient(func(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error) {
+		if method != lvmDBusObjectManager+".GetManagedObjects" {
+			t.Fatalf("unexpected call %v", method)
+		}
+		return fakeDBusObjects(map[dbus.ObjectPath]map[string]map[string]any{
+			"/com/redhat/lvmdbus1/Vg/0": {lvmDBusVg: {"Name": "vg"}},
+			"/com/redhat/lvmdbus1/Lv/0": {lvmDBusLvCommon: {
+				"Name": "lv",
+				"Vg":   dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+				"Attr": "-wi-a-----",
+			}},
+		}), nil
+	}, ManagedTag("managed"))
+
+	if _, ok := clnt.(*tagGuardClient); !ok {
+		t.Fatalf("expected a tag guard client, got %T", clnt)
+	}
+	if err := clnt.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv")); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected the untagged logical volume to be refused, got %v", err)
+	}
+}
+
+func TestDBusClientByUUID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := newDBusClient(func(ctx context.Context, path dbus.ObjectPath, method string, args ...any) ([]any, error) {
+		if method != lvmDBusObjectManager+".GetManagedObjects" {
+			t.Fatalf("unexpected call %v", method)
+		}
+		return fakeDBusObjects(map[dbus.ObjectPath]map[string]map[string]any{
+			"/com/redhat/lvmdbus1/Vg/0": {lvmDBusVg: {"Name": "vg", "Uuid": "vg-uuid"}},
+			"/com/redhat/lvmdbus1/Vg/1": {lvmDBusVg: {"Name": "other", "Uuid": "other-uuid"}},
+			"/com/redhat/lvmdbus1/Lv/0": {lvmDBusLvCommon: {
+				"Name": "lv",
+				"Uuid": "lv-uuid",
+				"Vg":   dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+				"Attr": "-wi-a-----",
+			}},
+			"/com/redhat/lvmdbus1/Lv/1": {lvmDBusLvCommon: {
+				"Name": "lv2",
+				"Uuid": "lv2-uuid",
+				"Vg":   dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+				"Attr": "-wi-a-----",
+			}},
+			"/com/redhat/lvmdbus1/Pv/0": {lvmDBusPv: {
+				"Name": "/dev/sdb",
+				"Uuid": "pv-uuid",
+				"Vg":   dbus.ObjectPath("/com/redhat/lvmdbus1/Vg/0"),
+			}},
+		}), nil
+	})
+
+	lv, err := LVByUUID(ctx, clnt, "lv2-uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Name != "lv2" || lv.VolumeGroupName != "vg" {
+		t.Fatalf("unexpected logical volume %+v", lv)
+	}
+	vg, err := VGByUUID(ctx, clnt, "other-uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.Name != "other" {
+		t.Fatalf("unexpected volume group %+v", vg)
+	}
+	pv, err := PVByUUID(ctx, clnt, "pv-uuid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pv.Name != "/dev/sdb" || pv.VGName != "vg" {
+		t.Fatalf("unexpected physical volume %+v", pv)
+	}
+	if _, err := LVByUUID(ctx, clnt, "missing"); err == nil {
+		t.Fatal("expected an error for an unknown UUID")
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"maps"
+	"time"
+)
+
+// SettingSource describes where an effective setting of a Client originates from.
+type SettingSource string
+
+const (
+	// SettingSourceContext is used for settings stored in the context passed to the Client.
+	SettingSourceContext SettingSource = "context"
+	// SettingSourceClient is used for settings configured with ClientOptions in NewClient.
+	SettingSourceClient SettingSource = "client"
+	// SettingSourcePackage is used for package level defaults.
+	SettingSourcePackage SettingSource = "package"
+)
+
+// Setting is an effective value of a Client setting together with its source.
+type Setting[T any] struct {
+	Value  T
+	Source SettingSource
+}
+
+// Defaults are the effective settings a Client uses for commands run with a specific context.
+//
+// Settings are resolved with the following precedence, highest first:
+//  1. values stored in the context, see WithCustomEnvironment, SetProcessCancelWaitDelay,
+//     WithForceNoNsenter and WithDefaultVolumeGroup
+//  2. ClientOptions passed to NewClient, including the variables of the LVMEnvironment
+//  3. package level defaults, see GetLVMPath, DefaultWaitDelay, UseStandardLocale and IsContainerized
+//
+// The Environment is merged instead of replaced: variables from the context override
+// variables of the client with the same name. Its Source is the highest source that contributed to it.
+// LVM_VG_NAME of the LVMEnvironment is not part of the Environment but reported as DefaultVolumeGroup.
+type Defaults struct {
+	LVMPath            Setting[string]
+	Environment        Setting[map[string]string]
+	StandardLocale     Setting[bool]
+	WaitDelay          Setting[time.Duration]
+	UseNsenter         Setting[bool]
+	DefaultVolumeGroup Setting[VolumeGroupName]
+	// ExecutionStrategy is the name of the ExecutionStrategy used for commands, empty if none of the
+	// ExecutionStrategies of the client is available.
+	ExecutionStrategy Setting[string]
+}
+
+func (c *client) Defaults(ctx context.Context) Defaults {
+	var defaults Defaults
+
+	if c.opts.LVMPath != "" {
+		defaults.LVMPath = Setting[string]{string(c.opts.LVMPath), SettingSourceClient}
+	} else {
+		defaults.LVMPath = Setting[string]{GetLVMPath(), SettingSourcePackage}
+	}
+
+	defaults.Environment = Setting[map[string]string]{map[string]string{}, SettingSourcePackage}
+	// the default volume group of the LVMEnvironment is reported as DefaultVolumeGroup
+	lvmEnv := c.opts.LVMEnvironment.Variables()
+	delete(lvmEnv, DefaultVolumeGroupEnv)
+	if c.opts.Environment != nil || len(lvmEnv) > 0 {
+		maps.Copy(defaults.Environment.Value, lvmEnv)
+		maps.Copy(defaults.Environment.Value, c.opts.Environment)
+		defaults.Environment.Source = SettingSourceClient
+	}
+	if env := GetCustomEnvironment(ctx); env != nil {
+		maps.Copy(defaults.Environment.Value, env)
+		defaults.Environment.Source = SettingSourceContext
+	}
+
+	if c.opts.StandardLocale {
+		defaults.StandardLocale = Setting[bool]{true, SettingSourceClient}
+	} else {
+		defaults.StandardLocale = Setting[bool]{UseStandardLocale(), SettingSourcePackage}
+	}
+
+	if delay, ok := contextWaitDelay(ctx); ok {
+		defaults.WaitDelay = Setting[time.Duration]{delay, SettingSourceContext}
+	} else if c.opts.ProcessCancelWaitDelay > 0 {
+		defaults.WaitDelay = Setting[time.Duration]{time.Duration(c.opts.ProcessCancelWaitDelay), SettingSourceClient}
+	} else {
+		defaults.WaitDelay = Setting[time.Duration]{DefaultWaitDelay, SettingSourcePackage}
+	}
+
+	useNsenter := WillUseNsenter(c.contextWithOptions(ctx))
+	if c.opts.ExecutionStrategies != nil && !shouldForceNoNsenter(ctx) {
+		useNsenter = c.strategy != nil && c.strategy.Name() == NsenterStrategy{}.Name()
+	}
+	if _, ok := contextForceNoNsenter(ctx); ok {
+		defaults.UseNsenter = Setting[bool]{useNsenter, SettingSourceContext}
+	} else if c.opts.NsenterPolicy != NsenterPolicyAuto || c.opts.ExecutionStrategies != nil {
+		defaults.UseNsenter = Setting[bool]{useNsenter, SettingSourceClient}
+	} else {
+		defaults.UseNsenter = Setting[bool]{useNsenter, SettingSourcePackage}
+	}
+
+	switch {
+	case shouldForceNoNsenter(ctx):
+		defaults.ExecutionStrategy = Setting[string]{DirectStrategy{}.Name(), SettingSourceContext}
+	case c.opts.ExecutionStrategies != nil && c.strategy != nil:
+		defaults.ExecutionStrategy = Setting[string]{c.strategy.Name(), SettingSourceClient}
+	case c.opts.ExecutionStrategies != nil:
+		defaults.ExecutionStrategy = Setting[string]{"", SettingSourceClient}
+	case useNsenter:
+		defaults.ExecutionStrategy = Setting[string]{NsenterStrategy{}.Name(), defaults.UseNsenter.Source}
+	default:
+		defaults.ExecutionStrategy = Setting[string]{DirectStrategy{}.Name(), defaults.UseNsenter.Source}
+	}
+
+	if vg := DefaultVolumeGroup(ctx); vg != "" {
+		defaults.DefaultVolumeGroup = Setting[VolumeGroupName]{VolumeGroupName(vg), SettingSourceContext}
+	} else if c.opts.DefaultVolumeGroupName != "" {
+		defaults.DefaultVolumeGroup = Setting[VolumeGroupName]{VolumeGroupName(c.opts.DefaultVolumeGroupName), SettingSourceClient}
+	} else {
+		defaults.DefaultVolumeGroup = Setting[VolumeGroupName]{"", SettingSourcePackage}
+	}
+
+	return defaults
+}
@@ -0,0 +1,96 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/lvmtest"
+)
+
+func TestDegradedVolumeGroupSpec(t *testing.T) {
+	SkipOrFailTestIfNotRoot(t)
+	ctx := context.Background()
+	clnt := GetTestClient(ctx)
+
+	for _, tc := range []struct {
+		degradation lvmtest.Degradation
+		verify      func(t *testing.T, vg *lvmtest.DegradedVolumeGroup)
+	}{
+		{
+			degradation: &lvmtest.MissingPhysicalVolume{Index: 1},
+			verify: func(t *testing.T, vg *lvmtest.DegradedVolumeGroup) {
+				pvs, err := clnt.PVs(ctx, vg.Name)
+				if err != nil {
+					t.Fatal(err)
+				}
+				missing := 0
+				for _, pv := range pvs {
+					if pv.Attr.Missing == MissingTrue {
+						missing++
+					}
+				}
+				if missing != 1 {
+					t.Fatalf("expected a single missing physical volume, got %v", pvs)
+				}
+			},
+		},
+		{
+			degradation: &lvmtest.FullThinPool{Name: "pool", Size: MustParseSize("16M")},
+			verify: func(t *testing.T, vg *lvmtest.DegradedVolumeGroup) {
+				pool, err := clnt.LV(ctx, vg.Name, LogicalVolumeName("pool"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if pool.DataPercent < 100 {
+					t.Fatalf("expected thin pool to be full, got %.2f%%", pool.DataPercent)
+				}
+			},
+		},
+		{
+			degradation: &lvmtest.SuspendedLogicalVolume{Name: "lv", Size: MustParseSize("16M")},
+			verify: func(t *testing.T, vg *lvmtest.DegradedVolumeGroup) {
+				lv, err := clnt.LV(ctx, vg.Name, LogicalVolumeName("lv"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if lv.Attr.State != StateSuspended {
+					t.Fatalf("expected logical volume to be suspended, got %s", lv.Attr)
+				}
+			},
+		},
+	} {
+		t.Run(tc.degradation.String(), func(t *testing.T) {
+			spec := lvmtest.DegradedVolumeGroupSpec{
+				PhysicalVolumes: []Size{MustParseSize("64M"), MustParseSize("64M")},
+				Degradations:    []lvmtest.Degradation{tc.degradation},
+			}
+			vg, err := spec.Build(ctx, clnt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() {
+				if err := vg.Teardown(ctx); err != nil {
+					t.Error(err)
+				}
+			})
+			tc.verify(t, vg)
+		})
+	}
+}
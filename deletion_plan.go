@@ -0,0 +1,182 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrDeletionConflict is returned when a LogicalVolumeDeletionPlan with conflicts is executed.
+var ErrDeletionConflict = errors.New("deletion plan has conflicts")
+
+// LogicalVolumeDeletionStep is the removal of a single logical volume in a LogicalVolumeDeletionPlan.
+type LogicalVolumeDeletionStep struct {
+	LV *LogicalVolume
+	// Implied is set if the logical volume was not a target, but is removed because the pool it is
+	// allocated from is removed, e.g. a thin volume of a thin pool.
+	Implied bool
+	// RemovedWith is set if lvm2 removes the logical volume together with another one of the plan,
+	// e.g. a cache pool with the logical volume it caches. The step is then skipped by Execute.
+	RemovedWith *LogicalVolume
+}
+
+// LogicalVolumeDeletionConflict is a logical volume that prevents the removal of a target.
+type LogicalVolumeDeletionConflict struct {
+	// Target is the logical volume of the plan that cannot be removed.
+	Target FQLogicalVolumeName
+	// Dependent is the logical volume that is not part of the plan, but depends on Target.
+	// It is nil if Target does not exist.
+	Dependent *LogicalVolume
+	Relation  LogicalVolumeRelation
+}
+
+func (c LogicalVolumeDeletionConflict) String() string {
+	if c.Dependent == nil {
+		return fmt.Sprintf("%s does not exist", c.Target.String())
+	}
+	return fmt.Sprintf("%s is needed by %s/%s (%s)", c.Target.String(), c.Dependent.VolumeGroupName, c.Dependent.Name, c.Relation)
+}
+
+// LogicalVolumeDeletionPlan is an order in which targeted logical volumes can be removed one after another
+// without failing on dependencies, see DeletionPlan.
+type LogicalVolumeDeletionPlan struct {
+	Steps     []LogicalVolumeDeletionStep
+	Conflicts []LogicalVolumeDeletionConflict
+}
+
+// DeletionPlan plans the removal of the targeted logical volumes, based on the LogicalVolumeGraph of all
+// logical volumes reported by the client.
+//
+// Snapshots are removed before their origins and thin volumes before their thin pools. Removing a pool implies
+// removing the logical volumes allocated from it, which are added to the plan. Snapshots of a removed origin or logical
+// volumes cached by a removed cache pool are never removed implicitly, they are reported as conflicts instead,
+// as are targets that do not exist.
+func DeletionPlan(ctx context.Context, client Client, targets ...*FQLogicalVolumeName) (*LogicalVolumeDeletionPlan, error) {
+	g, err := BuildLogicalVolumeGraph(ctx, client)
+	if err != nil {
+		return nil, err
+	}
+	return g.DeletionPlan(targets...), nil
+}
+
+// DeletionPlan plans the removal of the targeted logical volumes of the graph, see DeletionPlan.
+func (g *LogicalVolumeGraph) DeletionPlan(targets ...*FQLogicalVolumeName) *LogicalVolumeDeletionPlan {
+	plan := &LogicalVolumeDeletionPlan{}
+
+	removed := make(map[*LogicalVolume]bool)
+	implied := make(map[*LogicalVolume]bool)
+	var roots []*LogicalVolume
+	for _, target := range targets {
+		lv := g.LV(target)
+		if lv == nil {
+			plan.Conflicts = append(plan.Conflicts, LogicalVolumeDeletionConflict{Target: *target})
+			continue
+		}
+		if !removed[lv] {
+			removed[lv] = true
+			roots = append(roots, lv)
+		}
+	}
+	// logical volumes allocated from removed pools are removed with them
+	for queue := roots; len(queue) > 0; queue = queue[1:] {
+		for _, edge := range g.childEdges(queue[0]) {
+			if removed[edge.Child] || (edge.Relation != LogicalVolumeRelationThin && edge.Relation != LogicalVolumeRelationPool) {
+				continue
+			}
+			removed[edge.Child], implied[edge.Child] = true, true
+			queue = append(queue, edge.Child)
+		}
+	}
+
+	for _, edge := range g.edges {
+		if removed[edge.Parent] && !removed[edge.Child] {
+			plan.Conflicts = append(plan.Conflicts, LogicalVolumeDeletionConflict{
+				Target:    *graphKeyOf(edge.Parent),
+				Dependent: edge.Child,
+				Relation:  edge.Relation,
+			})
+		}
+	}
+
+	visited := make(map[*LogicalVolume]bool)
+	var visit func(lv *LogicalVolume)
+	visit = func(lv *LogicalVolume) {
+		if visited[lv] || !removed[lv] {
+			return
+		}
+		visited[lv] = true
+		for _, child := range g.children[lv] {
+			visit(child)
+		}
+		step := LogicalVolumeDeletionStep{LV: lv, Implied: implied[lv]}
+		for _, edge := range g.childEdges(lv) {
+			if edge.Relation == LogicalVolumeRelationCache && removed[edge.Child] {
+				step.RemovedWith = edge.Child
+			}
+		}
+		plan.Steps = append(plan.Steps, step)
+	}
+	for _, lv := range roots {
+		visit(lv)
+	}
+	for _, lv := range g.lvs {
+		visit(lv)
+	}
+	return plan
+}
+
+// childEdges returns the edges of the graph from the given parent.
+func (g *LogicalVolumeGraph) childEdges(parent *LogicalVolume) []LogicalVolumeEdge {
+	var edges []LogicalVolumeEdge
+	for _, edge := range g.edges {
+		if edge.Parent == parent {
+			edges = append(edges, edge)
+		}
+	}
+	return edges
+}
+
+func graphKeyOf(lv *LogicalVolume) *FQLogicalVolumeName {
+	key := graphKey(lv.VolumeGroupName, lv.Name)
+	return &key
+}
+
+// Execute removes the logical volumes of the plan in order with the given options.
+// It returns ErrDeletionConflict without removing anything if the plan has conflicts.
+func (p *LogicalVolumeDeletionPlan) Execute(ctx context.Context, client Client, opts ...LVRemoveOption) error {
+	if len(p.Conflicts) > 0 {
+		conflicts := make([]string, len(p.Conflicts))
+		for i, conflict := range p.Conflicts {
+			conflicts[i] = conflict.String()
+		}
+		return fmt.Errorf("%w: %s", ErrDeletionConflict, strings.Join(conflicts, ", "))
+	}
+
+	for _, step := range p.Steps {
+		if step.RemovedWith != nil {
+			continue
+		}
+		name := graphKeyOf(step.LV)
+		if err := client.LVRemove(ctx, append([]LVRemoveOption{name}, opts...)...); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", name.String(), err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestLogicalVolumeGraph_DeletionPlan(t *testing.T) {
+	t.Parallel()
+
+	lv := func(name, attr, origin, pool string) *LogicalVolume {
+		parsed, err := ParseLVAttributes(attr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &LogicalVolume{Name: LogicalVolumeName(name), VolumeGroupName: "vg", Attr: parsed, Origin: origin, PoolLogicalVolume: pool}
+	}
+	graph := NewLogicalVolumeGraph([]*LogicalVolume{
+		lv("pool", "twi-aotz--", "", ""),
+		lv("thin", "Vwi-aotz--", "", "pool"),
+		lv("thin-snap", "Vwi---tz-k", "thin", "pool"),
+		lv("linear", "owi-a-----", "", ""),
+		lv("cow", "swi-a-s---", "linear", ""),
+		lv("cached", "Cwi-a-C---", "", "[fast_cvol]"),
+		lv("fast_cvol", "Cwi-aoC---", "", ""),
+	})
+	fq := MustNewFQLogicalVolumeName
+
+	steps := func(plan *LogicalVolumeDeletionPlan) []string {
+		var steps []string
+		for _, step := range plan.Steps {
+			name := string(step.LV.Name)
+			if step.Implied {
+				name += "(implied)"
+			}
+			if step.RemovedWith != nil {
+				name += "(with " + string(step.RemovedWith.Name) + ")"
+			}
+			steps = append(steps, name)
+		}
+		return steps
+	}
+
+	plan := graph.DeletionPlan(fq("vg", "pool"))
+	if len(plan.Conflicts) > 0 {
+		t.Fatalf("unexpected conflicts %v", plan.Conflicts)
+	}
+	if got := steps(plan); !slices.Equal(got, []string{"thin-snap(implied)", "thin(implied)", "pool"}) {
+		t.Fatalf("unexpected steps %v", got)
+	}
+
+	plan = graph.DeletionPlan(fq("vg", "fast_cvol"), fq("vg", "cached"), fq("vg", "cow"))
+	if len(plan.Conflicts) > 0 {
+		t.Fatalf("unexpected conflicts %v", plan.Conflicts)
+	}
+	if got := steps(plan); !slices.Equal(got, []string{"cached", "fast_cvol(with cached)", "cow"}) {
+		t.Fatalf("unexpected steps %v", got)
+	}
+
+	plan = graph.DeletionPlan(fq("vg", "linear"), fq("vg", "thin"), fq("vg", "missing"))
+	if len(plan.Conflicts) != 3 {
+		t.Fatalf("expected 3 conflicts, got %v", plan.Conflicts)
+	}
+	if got := plan.Conflicts[0].String(); got != "vg/missing does not exist" {
+		t.Fatalf("unexpected conflict %q", got)
+	}
+	if err := plan.Execute(context.Background(), nil); !errors.Is(err, ErrDeletionConflict) {
+		t.Fatalf("expected conflict error, got %v", err)
+	}
+}
+
+func TestDeletionPlan_Execute(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := fake.NewClient()
+	if err := client.SetDevice("/dev/sdb", MustParseSize("101M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("40M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewThinPool("vg", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, pool, LogicalVolumeName("thin"), MustParseSize("1G").Virtual()); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := NewSnapshotOf("vg", "thin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, origin, LogicalVolumeName("snap")); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := DeletionPlan(ctx, client, MustNewFQLogicalVolumeName("vg", "pool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Steps) != 3 {
+		t.Fatalf("expected 3 steps, got %v", plan.Steps)
+	}
+	if err := plan.Execute(ctx, client); err != nil {
+		t.Fatal(err)
+	}
+	if lvs, err := client.LVs(ctx, VolumeGroupName("vg")); err != nil {
+		t.Fatal(err)
+	} else if len(lvs) != 0 {
+		t.Fatalf("expected all logical volumes to be removed, got %d", len(lvs))
+	}
+}
@@ -0,0 +1,153 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DeviceMapperDir is the directory of the device nodes of device-mapper devices.
+	DeviceMapperDir = "/dev/mapper"
+	// DevDir is the directory of the device nodes and of the /dev/vg/lv symlinks created by lvm2.
+	DevDir = "/dev"
+	// SysBlockDir is the directory of the block devices in sysfs, with the device-mapper name
+	// of a device in SysBlockDir/dm-N/dm/name.
+	SysBlockDir = "/sys/block"
+)
+
+// DeviceMapperName returns the device-mapper name of the logical volume, e.g. vg--name-lv--name for vg-name/lv-name.
+// Dashes in the volume group and logical volume names are escaped by doubling them and a single dash
+// separates both names. Other characters need no escaping, as lvm2 only allows [a-zA-Z0-9+_.-] in names
+// and names cannot start with a dash.
+func (opt *FQLogicalVolumeName) DeviceMapperName() string {
+	return fmt.Sprintf("%s-%s", escapeDeviceMapperName(string(opt.VolumeGroupName)), escapeDeviceMapperName(string(opt.LogicalVolumeName)))
+}
+
+// DeviceMapperPath returns the path of the device-mapper device node of the logical volume,
+// e.g. /dev/mapper/vg--name-lv--name.
+func (opt *FQLogicalVolumeName) DeviceMapperPath() string {
+	return filepath.Join(DeviceMapperDir, opt.DeviceMapperName())
+}
+
+// DevicePath returns the path of the symlink to the device node created by lvm2 for active logical volumes,
+// e.g. /dev/vg-name/lv-name. Unlike the device-mapper name, the path needs no escaping.
+func (opt *FQLogicalVolumeName) DevicePath() string {
+	return filepath.Join(DevDir, string(opt.VolumeGroupName), string(opt.LogicalVolumeName))
+}
+
+// ParseDeviceMapperName splits a device-mapper name created by lvm2 into the logical volume and the layer,
+// reverting the escaping of DeviceMapperName. The name may be given as path in DeviceMapperDir.
+// The layer is the suffix of internal devices, e.g. tpool for vg-pool-tpool, and empty for logical volumes.
+func ParseDeviceMapperName(name string) (*FQLogicalVolumeName, string, error) {
+	name = strings.TrimPrefix(name, DeviceMapperDir+"/")
+	vg, rest, ok := splitDeviceMapperName(name)
+	if !ok {
+		return nil, "", fmt.Errorf("%q is not a device-mapper name of a logical volume", name)
+	}
+	lv, layer, _ := splitDeviceMapperName(rest)
+	fq, err := NewFQLogicalVolumeName(VolumeGroupName(vg), LogicalVolumeName(lv))
+	if err != nil {
+		return nil, "", fmt.Errorf("%q is not a device-mapper name of a logical volume: %w", name, err)
+	}
+	return fq, layer, nil
+}
+
+func escapeDeviceMapperName(name string) string {
+	return strings.ReplaceAll(name, "-", "--")
+}
+
+// splitDeviceMapperName splits name at the first single dash and unescapes the part before it.
+func splitDeviceMapperName(name string) (string, string, bool) {
+	for i := 0; i < len(name); i++ {
+		if name[i] != '-' {
+			continue
+		}
+		if i+1 < len(name) && name[i+1] == '-' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(name[:i], "--", "-"), name[i+1:], true
+	}
+	return strings.ReplaceAll(name, "--", "-"), "", false
+}
+
+// ResolveDevicePath resolves the path of a block device of an active logical volume, e.g. /dev/dm-3,
+// /dev/mapper/vg-lv or /dev/vg/lv, to the logical volume, following symlinks such as the ones in /dev/disk.
+// Device-mapper names are unmangled, so that e.g. /dev/mapper/vg--name-lv--name resolves to vg-name/lv-name.
+// The layer of internal devices, e.g. tpool for /dev/mapper/vg-pool-tpool, is returned as with ParseDeviceMapperName.
+// Paths that do not exist are resolved by their name, so that the paths of inactive logical volumes can be resolved.
+func ResolveDevicePath(path string) (*FQLogicalVolumeName, string, error) {
+	path = filepath.Clean(path)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	dir, name := filepath.Split(path)
+	dir = filepath.Clean(dir)
+	switch {
+	case dir == DeviceMapperDir:
+		return ParseDeviceMapperName(unmangleDeviceMapperName(name))
+	case dir == DevDir && strings.HasPrefix(name, "dm-"):
+		dmName, err := os.ReadFile(filepath.Join(SysBlockDir, name, "dm", "name"))
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to read the device-mapper name of %s: %w", path, err)
+		}
+		return ParseDeviceMapperName(strings.TrimSpace(string(dmName)))
+	case filepath.Dir(dir) == DevDir:
+		fq, err := NewFQLogicalVolumeName(VolumeGroupName(filepath.Base(dir)), LogicalVolumeName(name))
+		if err != nil {
+			return nil, "", fmt.Errorf("%s is not the path of a logical volume: %w", path, err)
+		}
+		return fq, "", fq.Validate()
+	}
+	return nil, "", fmt.Errorf("%s is not the path of a logical volume", path)
+}
+
+// LVByDevicePath returns the logical volume of the block device at path, see ResolveDevicePath.
+// Internal devices, e.g. the tpool device of a thin pool, resolve to the logical volume they belong to.
+func LVByDevicePath(ctx context.Context, client Client, path string) (*LogicalVolume, error) {
+	fq, _, err := ResolveDevicePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return client.LV(ctx, fq.VolumeGroupName, fq.LogicalVolumeName)
+}
+
+// unmangleDeviceMapperName reverts the hex mangling of characters, e.g. \x20, in device-mapper names as used by udev.
+func unmangleDeviceMapperName(name string) string {
+	if !strings.Contains(name, `\x`) {
+		return name
+	}
+	var unmangled strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+3 < len(name) && name[i+1] == 'x' {
+			if b, err := strconv.ParseUint(name[i+2:i+4], 16, 8); err == nil {
+				unmangled.WriteByte(byte(b))
+				i += 3
+				continue
+			}
+		}
+		unmangled.WriteByte(name[i])
+	}
+	return unmangled.String()
+}
@@ -0,0 +1,118 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestDeviceMapperName(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		vg     VolumeGroupName
+		lv     LogicalVolumeName
+		dmName string
+	}{
+		{"vg", "lv", "vg-lv"},
+		{"vg-name", "lv-name", "vg--name-lv--name"},
+		{"my--vg", "lv", "my----vg-lv"},
+		{"vg", "lv-", "vg-lv--"},
+		{"vg_0.a+b", "lv", "vg_0.a+b-lv"},
+	} {
+		fq := MustNewFQLogicalVolumeName(tc.vg, tc.lv)
+		if name := fq.DeviceMapperName(); name != tc.dmName {
+			t.Errorf("expected device-mapper name %s for %s, got %s", tc.dmName, fq, name)
+		}
+		if path := fq.DeviceMapperPath(); path != "/dev/mapper/"+tc.dmName {
+			t.Errorf("unexpected device-mapper path %s for %s", path, fq)
+		}
+		if path := fq.DevicePath(); path != "/dev/"+string(tc.vg)+"/"+string(tc.lv) {
+			t.Errorf("unexpected device path %s for %s", path, fq)
+		}
+
+		parsed, layer, err := ParseDeviceMapperName(fq.DeviceMapperPath())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if *parsed != *fq || layer != "" {
+			t.Errorf("expected %s to be parsed as %s, got %s with layer %q", tc.dmName, fq, parsed, layer)
+		}
+	}
+
+	parsed, layer, err := ParseDeviceMapperName("vg--name-thin--pool-tpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != "vg-name/thin-pool" || layer != "tpool" {
+		t.Fatalf("unexpected parsed name %s with layer %q", parsed, layer)
+	}
+
+	for _, invalid := range []string{"vg", "vg--lv", "-lv", "vg-"} {
+		if _, _, err := ParseDeviceMapperName(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestResolveDevicePath(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for path, expected := range map[string]string{
+		"/dev/mapper/vg--name-lv--name":    "vg-name/lv-name",
+		"/dev/mapper/vg-lv\\x2bmangled":    "vg/lv+mangled",
+		"/dev/mapper/vg-thin--pool-tpool":  "vg/thin-pool",
+		"/dev/vg-name/lv-name":             "vg-name/lv-name",
+		"/dev/vg-name/../vg-name/lv-name/": "vg-name/lv-name",
+	} {
+		fq, _, err := ResolveDevicePath(path)
+		if err != nil {
+			t.Errorf("failed to resolve %s: %v", path, err)
+			continue
+		}
+		if fq.String() != expected {
+			t.Errorf("expected %s to resolve to %s, got %s", path, expected, fq)
+		}
+	}
+
+	for _, invalid := range []string{"/dev/mapper/control-", "/var/lib/lv", "/dev/sda"} {
+		if _, _, err := ResolveDevicePath(invalid); err == nil {
+			t.Errorf("expected error for %s", invalid)
+		}
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("1G"))
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg-name"), PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg-name"), LogicalVolumeName("lv-name"), MustParseSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	lv, err := LVByDevicePath(ctx, clnt, "/dev/mapper/vg--name-lv--name")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Name != "lv-name" || lv.VolumeGroupName != "vg-name" {
+		t.Fatalf("unexpected logical volume %s/%s", lv.VolumeGroupName, lv.Name)
+	}
+}
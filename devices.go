@@ -70,6 +70,9 @@ func (opt Devices) ApplyToLVRenameOptions(opts *LVRenameOptions) {
 func (opt Devices) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.Devices = opt
 }
+func (opt Devices) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Devices = opt
+}
 
 func (opt Devices) ApplyToPVsOptions(opts *PVsOptions) {
 	opts.Devices = opt
@@ -153,6 +156,9 @@ func (opt DevicesFile) ApplyToLVRenameOptions(opts *LVRenameOptions) {
 func (opt DevicesFile) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.DevicesFile = opt
 }
+func (opt DevicesFile) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.DevicesFile = opt
+}
 
 func (opt DevicesFile) ApplyToPVsOptions(opts *PVsOptions) {
 	opts.DevicesFile = opt
@@ -173,6 +179,12 @@ func (opt DevicesFile) ApplyToPVMoveOptions(opts *PVMoveOptions) {
 	opts.DevicesFile = opt
 }
 
+// ApplyToClientOptions binds the client to the devices file, so that every lvm command of the client
+// only sees the devices listed in it unless a command sets its own DevicesFile.
+func (opt DevicesFile) ApplyToClientOptions(opts *ClientOptions) {
+	opts.DevicesFile = opt
+}
+
 func (opt DevicesFile) ApplyToArgs(args Arguments) error {
 	if opt == "" {
 		return nil
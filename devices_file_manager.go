@@ -0,0 +1,122 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// ErrInvalidTenantName is returned by DevicesFileManager for tenant names that cannot be used as devices file name.
+var ErrInvalidTenantName = errors.New("invalid tenant name")
+
+// tenantNamePattern limits tenant names to characters that are valid in a devices file name,
+// which lvm expects as a plain file name in the devices directory, e.g. /etc/lvm/devices.
+var tenantNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_+][a-zA-Z0-9_.+-]*$`)
+
+// DevicesFileManager maintains a separate devices file per tenant.
+// A tenant only sees the devices imported into its devices file, so that the Client of a tenant
+// cannot touch the disks of other tenants or of the host, even if it runs arbitrary lvm commands.
+type DevicesFileManager struct {
+	client Client
+	opts   []ClientOption
+}
+
+// NewDevicesFileManager creates a manager that modifies devices files with the given client.
+// The client options are used to create the clients of the tenants.
+func NewDevicesFileManager(client Client, opts ...ClientOption) *DevicesFileManager {
+	return &DevicesFileManager{client: client, opts: opts}
+}
+
+// DevicesFile returns the devices file of the tenant.
+func (m *DevicesFileManager) DevicesFile(tenant string) (DevicesFile, error) {
+	if !tenantNamePattern.MatchString(tenant) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidTenantName, tenant)
+	}
+	devicesFile := DevicesFile(tenant + ".devices")
+	if devicesFile == SystemDevices {
+		return "", fmt.Errorf("%w: %q is reserved for the system devices file", ErrInvalidTenantName, tenant)
+	}
+	return devicesFile, nil
+}
+
+// Import adds the devices to the devices file of the tenant.
+// The devices file is created by lvm with the first device.
+func (m *DevicesFileManager) Import(ctx context.Context, tenant string, devices ...string) error {
+	devicesFile, err := m.DevicesFile(tenant)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if err := m.client.DevModify(ctx, devicesFile, AddDevice(device)); err != nil {
+			return fmt.Errorf("failed to import %s for tenant %s: %w", device, tenant, err)
+		}
+	}
+	return nil
+}
+
+// ImportVolumeGroup adds the physical volumes of the volume group, as seen by the client of the manager,
+// to the devices file of the tenant.
+func (m *DevicesFileManager) ImportVolumeGroup(ctx context.Context, tenant string, vg VolumeGroupName) error {
+	pvs, err := m.client.PVs(ctx, vg)
+	if err != nil {
+		return fmt.Errorf("failed to list physical volumes of %s: %w", vg, err)
+	}
+	if len(pvs) == 0 {
+		return fmt.Errorf("%w: %s", ErrVolumeGroupNotFound, vg)
+	}
+	devices := make([]string, 0, len(pvs))
+	for _, pv := range pvs {
+		devices = append(devices, string(pv.Name))
+	}
+	return m.Import(ctx, tenant, devices...)
+}
+
+// Release removes the devices from the devices file of the tenant.
+func (m *DevicesFileManager) Release(ctx context.Context, tenant string, devices ...string) error {
+	devicesFile, err := m.DevicesFile(tenant)
+	if err != nil {
+		return err
+	}
+	for _, device := range devices {
+		if err := m.client.DevModify(ctx, devicesFile, DelDevice(device)); err != nil {
+			return fmt.Errorf("failed to release %s of tenant %s: %w", device, tenant, err)
+		}
+	}
+	return nil
+}
+
+// Devices lists the entries of the devices file of the tenant.
+func (m *DevicesFileManager) Devices(ctx context.Context, tenant string) ([]DeviceListEntry, error) {
+	devicesFile, err := m.DevicesFile(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return m.client.DevList(ctx, devicesFile)
+}
+
+// Client creates a client of the tenant that is bound to the devices file of the tenant.
+func (m *DevicesFileManager) Client(tenant string) (Client, error) {
+	devicesFile, err := m.DevicesFile(tenant)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(append(slices.Clone(m.opts), devicesFile)...), nil
+}
@@ -0,0 +1,106 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestDevicesFileManager(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var commands [][]string
+	runner := CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Args)
+		if slices.Contains(cmd.Args, "lvmdevices") {
+			return io.NopCloser(strings.NewReader("")), nil
+		}
+		return io.NopCloser(strings.NewReader(`{"report":[{}]}`)), nil
+	})
+	lastCommand := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return commands[len(commands)-1]
+	}
+	devicesFileAfter := func(command []string, subcommand string) string {
+		i := slices.Index(command, subcommand)
+		if i < 0 || i+2 >= len(command) || command[i+1] != "--devicesfile" {
+			return ""
+		}
+		return command[i+2]
+	}
+
+	manager := NewDevicesFileManager(NewClient(runner), runner)
+
+	if err := manager.Import(ctx, "tenant-a", "/dev/sdb"); err != nil {
+		t.Fatal(err)
+	}
+	if command := lastCommand(); !slices.Contains(command, "tenant-a.devices") || !slices.Contains(command, "/dev/sdb") {
+		t.Fatalf("expected import into tenant-a.devices, got %v", command)
+	}
+
+	tenant, err := manager.Client("tenant-a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tenant.VGs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if file := devicesFileAfter(lastCommand(), "vgs"); file != "tenant-a.devices" {
+		t.Fatalf("expected vgs to be bound to tenant-a.devices, got %v", lastCommand())
+	}
+
+	// the devices file itself is modified within the devices file of the tenant
+	if err := tenant.DevModify(ctx, AddDevice("/dev/sdx")); err != nil {
+		t.Fatal(err)
+	}
+	if file := devicesFileAfter(lastCommand(), "lvmdevices"); file != "tenant-a.devices" {
+		t.Fatalf("expected lvmdevices --adddev to be bound to tenant-a.devices, got %v", lastCommand())
+	}
+	if _, err := tenant.DevUpdate(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if file := devicesFileAfter(lastCommand(), "lvmdevices"); file != "tenant-a.devices" || !slices.Contains(lastCommand(), "--update") {
+		t.Fatalf("expected lvmdevices --update to be bound to tenant-a.devices, got %v", lastCommand())
+	}
+
+	if _, err := tenant.LVs(ctx, DevicesFile("other.devices")); err != nil {
+		t.Fatal(err)
+	}
+	if command := lastCommand(); slices.Contains(command, "tenant-a.devices") || !slices.Contains(command, "other.devices") {
+		t.Fatalf("expected the devices file of the command to take precedence, got %v", command)
+	}
+
+	for _, invalid := range []string{"", "system", "../etc", "a/b", ".hidden", "-flag"} {
+		if _, err := manager.Client(invalid); !errors.Is(err, ErrInvalidTenantName) {
+			t.Errorf("expected %v for %q, got %v", ErrInvalidTenantName, invalid, err)
+		}
+	}
+}
@@ -16,6 +16,12 @@
 
 package lvm2go
 
+import (
+	"fmt"
+)
+
+// Discards sets how a thin pool handles discards of its thin volumes: passdown frees the blocks in the pool
+// and passes the discards to the underlying devices, nopassdown only frees the blocks and ignore does neither.
 type Discards string
 
 const (
@@ -25,8 +31,12 @@ const (
 )
 
 func (opt Discards) ApplyToArgs(args Arguments) error {
-	if opt == "" {
+	switch opt {
+	case "":
 		return nil
+	case DiscardsPassdown, DiscardsNoPassdown, DiscardsIgnore:
+	default:
+		return fmt.Errorf("invalid discards %q", string(opt))
 	}
 	args.AddOrReplaceAll([]string{"--discards", string(opt)})
 	return nil
@@ -0,0 +1,88 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Names of device-mapper targets used by lvm2, as registered in the kernel.
+const (
+	DeviceMapperTargetLinear     = "linear"
+	DeviceMapperTargetStriped    = "striped"
+	DeviceMapperTargetMirror     = "mirror"
+	DeviceMapperTargetSnapshot   = "snapshot"
+	DeviceMapperTargetThinPool   = "thin-pool"
+	DeviceMapperTargetThin       = "thin"
+	DeviceMapperTargetCache      = "cache"
+	DeviceMapperTargetWritecache = "writecache"
+	DeviceMapperTargetRaid       = "raid"
+	DeviceMapperTargetIntegrity  = "integrity"
+	DeviceMapperTargetVDO        = "vdo"
+)
+
+// DeviceMapperTarget is a device-mapper target type registered in the kernel together with its version.
+// The version of a target is independent of the version of lvm2 and the device-mapper driver,
+// e.g. features of thin pools depend on the version of the thin-pool target of the running kernel.
+type DeviceMapperTarget struct {
+	Name    string
+	Version VersionNumber
+}
+
+// DeviceMapperTargets are the device-mapper targets registered in the kernel as reported by dmsetup targets.
+// Targets of kernel modules that are not loaded yet are not registered.
+type DeviceMapperTargets []DeviceMapperTarget
+
+// Get returns the version of the target with the given name.
+func (targets DeviceMapperTargets) Get(name string) (VersionNumber, bool) {
+	for _, target := range targets {
+		if target.Name == name {
+			return target.Version, true
+		}
+	}
+	return VersionNumber{}, false
+}
+
+// AtLeast reports whether the target with the given name is registered with the given version or newer.
+func (targets DeviceMapperTargets) AtLeast(name string, version VersionNumber) bool {
+	v, ok := targets.Get(name)
+	return ok && v.AtLeast(version)
+}
+
+// ParseDeviceMapperTargets parses the output of dmsetup targets, e.g. "thin-pool        v1.22.0".
+func ParseDeviceMapperTargets(out io.Reader) (DeviceMapperTargets, error) {
+	var targets DeviceMapperTargets
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid target line: %q", scanner.Text())
+		}
+		version, err := ParseVersionNumber(strings.TrimPrefix(fields[1], "v"))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse version of target %s: %w", fields[0], err)
+		}
+		targets = append(targets, DeviceMapperTarget{Name: fields[0], Version: version})
+	}
+	return targets, scanner.Err()
+}
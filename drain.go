@@ -0,0 +1,263 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrLogicalVolumeStillActive is reported by DrainNode for a logical volume that is still active after the drain.
+	ErrLogicalVolumeStillActive = errors.New("logical volume is still active")
+	// ErrLogicalVolumeStillOpen is reported by DrainNode for a logical volume whose device is still open after the drain.
+	ErrLogicalVolumeStillOpen = errors.New("logical volume is still open")
+)
+
+type (
+	// DrainNodeOptions select the logical volumes that are released by DrainNode.
+	// Without VolumeGroupNames, the logical volumes of all volume groups are drained.
+	// With Tags, only logical volumes with one of the tags are drained, e.g. the tag of the managing controller.
+	DrainNodeOptions struct {
+		VolumeGroupNames
+		Tags
+		DrainUnmount
+	}
+	DrainNodeOption interface {
+		ApplyToDrainNodeOptions(opts *DrainNodeOptions)
+	}
+	DrainNodeOptionsList []DrainNodeOption
+)
+
+// DrainUnmount unmounts all mount points of a logical volume before it is deactivated.
+// Mount points are looked up with findmnt and unmounted with umount, so the client has to implement RawCommandRunner.
+// The commands honor the context and locks of wrapped clients, and clients with a ManagedTag only unmount
+// logical volumes carrying the tag.
+type DrainUnmount bool
+
+func (opt DrainUnmount) ApplyToDrainNodeOptions(opts *DrainNodeOptions) {
+	opts.DrainUnmount = opt
+}
+
+func (opts *DrainNodeOptions) ApplyToDrainNodeOptions(new *DrainNodeOptions) {
+	*new = *opts
+}
+
+// DrainStep is the step of DrainNode that failed to release a logical volume.
+type DrainStep string
+
+const (
+	DrainStepUnmount    DrainStep = "unmount"
+	DrainStepMonitor    DrainStep = "monitor"
+	DrainStepDeactivate DrainStep = "deactivate"
+	DrainStepVerify     DrainStep = "verify"
+)
+
+// DrainFailure is a logical volume that DrainNode could not release.
+type DrainFailure struct {
+	LogicalVolume *FQLogicalVolumeName
+	Step          DrainStep
+	Err           error
+}
+
+func (f *DrainFailure) Error() string {
+	return fmt.Sprintf("failed to %s %s: %v", f.Step, f.LogicalVolume, f.Err)
+}
+
+func (f *DrainFailure) Unwrap() error {
+	return f.Err
+}
+
+// DrainReport is the result of DrainNode.
+type DrainReport struct {
+	// Unmounted are the mount points that were unmounted.
+	Unmounted []string
+	// Deactivated are the logical volumes that were deactivated.
+	Deactivated []*FQLogicalVolumeName
+	// Failures are the logical volumes that could not be released, in the order they were encountered.
+	Failures []*DrainFailure
+}
+
+// Err returns the failures of the report joined into a single error, or nil if the drain was complete.
+func (r *DrainReport) Err() error {
+	errs := make([]error, 0, len(r.Failures))
+	for _, failure := range r.Failures {
+		errs = append(errs, failure)
+	}
+	return errors.Join(errs...)
+}
+
+func (r *DrainReport) failed(lv *FQLogicalVolumeName, step DrainStep, err error) {
+	r.Failures = append(r.Failures, &DrainFailure{LogicalVolume: lv, Step: step, Err: err})
+}
+
+func (r *DrainReport) hasFailed(lv *FQLogicalVolumeName) bool {
+	return slices.ContainsFunc(r.Failures, func(failure *DrainFailure) bool {
+		return *failure.LogicalVolume == *lv
+	})
+}
+
+// DrainNode releases the active logical volumes of the node before it is rebooted or taken into maintenance:
+// mount points are unmounted with DrainUnmount, monitoring by dmeventd is stopped and the logical volumes are
+// deactivated, snapshots and thin volumes before their origins and pools. Afterward the logical volumes are
+// reported again to verify that none of them remains active or open.
+//
+// A logical volume that cannot be released does not stop the drain of the others. It is recorded in the
+// Failures of the report, which are also returned as error. Logical volumes that fail to unmount are not deactivated.
+// See BlkDeactivate for the equivalent on the level of block devices.
+func DrainNode(ctx context.Context, client Client, opts ...DrainNodeOption) (*DrainReport, error) {
+	options := DrainNodeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToDrainNodeOptions(&options)
+	}
+
+	var runner RawCommandRunner
+	if options.DrainUnmount {
+		var ok bool
+		if runner, ok = ClientImplements[RawCommandRunner](client); !ok {
+			return nil, fmt.Errorf("%w: client cannot run raw commands to unmount", errors.ErrUnsupported)
+		}
+	}
+
+	lvs, err := drainNodeLogicalVolumes(ctx, client, options)
+	if err != nil {
+		return nil, err
+	}
+	// snapshots and thin volumes keep their origins and pools open, so they are released first
+	slices.SortStableFunc(lvs, func(a, b *LogicalVolume) int {
+		return cmp.Compare(drainOrder(a), drainOrder(b))
+	})
+
+	report := &DrainReport{}
+	for _, lv := range lvs {
+		if lv.Attr.State == StateNone {
+			continue
+		}
+		id, err := NewFQLogicalVolumeName(lv.VolumeGroupName, lv.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		if options.DrainUnmount {
+			// the unmount is not guarded by the client like the deactivation, see guardLogicalVolume
+			if err := guardLogicalVolume(ctx, client, id); err != nil {
+				report.failed(id, DrainStepUnmount, err)
+				continue
+			}
+			unmounted, err := unmountLogicalVolume(ctx, runner, id)
+			report.Unmounted = append(report.Unmounted, unmounted...)
+			if err != nil {
+				report.failed(id, DrainStepUnmount, err)
+				continue
+			}
+		}
+
+		if err := client.LVChange(ctx, lv.VolumeGroupName, lv.Name, MonitorStop); err != nil {
+			report.failed(id, DrainStepMonitor, err)
+		}
+
+		if err := client.LVChange(ctx, lv.VolumeGroupName, lv.Name, Deactivate); err != nil {
+			report.failed(id, DrainStepDeactivate, err)
+			continue
+		}
+		report.Deactivated = append(report.Deactivated, id)
+	}
+
+	remaining, err := drainNodeLogicalVolumes(ctx, client, options)
+	if err != nil {
+		return report, errors.Join(report.Err(), fmt.Errorf("failed to verify drain: %w", err))
+	}
+	for _, lv := range remaining {
+		id := &FQLogicalVolumeName{VolumeGroupName: lv.VolumeGroupName, LogicalVolumeName: lv.Name}
+		if report.hasFailed(id) {
+			continue
+		}
+		switch {
+		case lv.Attr.Open == OpenTrue:
+			report.failed(id, DrainStepVerify, ErrLogicalVolumeStillOpen)
+		case lv.Attr.State != StateNone:
+			report.failed(id, DrainStepVerify, ErrLogicalVolumeStillActive)
+		}
+	}
+
+	return report, report.Err()
+}
+
+// drainNodeLogicalVolumes reports the logical volumes in the scope of the options.
+func drainNodeLogicalVolumes(ctx context.Context, client Client, options DrainNodeOptions) ([]*LogicalVolume, error) {
+	lvsOpts := []LVsOption{options.VolumeGroupNames}
+	if len(options.Tags) > 0 {
+		// positional tags would select logical volumes in addition to the volume groups instead of within them
+		tags := make([]string, 0, len(options.Tags))
+		for _, tag := range options.Tags {
+			tags = append(tags, strings.TrimPrefix(tag, TagSymbol))
+		}
+		lvsOpts = append(lvsOpts, LVTags.ContainsAny(tags...))
+	}
+	lvs, err := client.LVs(ctx, lvsOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logical volumes to drain: %w", err)
+	}
+	return lvs, nil
+}
+
+func drainOrder(lv *LogicalVolume) int {
+	switch {
+	case lv.Origin != "":
+		return 0
+	case lv.PoolLogicalVolume != "":
+		return 1
+	default:
+		return 2
+	}
+}
+
+// unmountLogicalVolume unmounts all mount points of the logical volume, nested mount points first,
+// and returns the mount points that were unmounted.
+func unmountLogicalVolume(ctx context.Context, runner RawCommandRunner, lv *FQLogicalVolumeName) ([]string, error) {
+	output, err := runRawOutput(ctx, runner, "findmnt", "--raw", "--noheadings", "--output", "TARGET", "--source", lv.DevicePath())
+	// findmnt exits with 1 if the device is not mounted
+	if exitErr, ok := AsExitCodeError(err); ok && exitErr.ExitCode() == 1 {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find mount points: %w", err)
+	}
+
+	var targets []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			targets = append(targets, unescapeRawOutput(line))
+		}
+	}
+	slices.SortFunc(targets, func(a, b string) int {
+		return cmp.Compare(len(b), len(a))
+	})
+
+	var unmounted []string
+	for _, target := range targets {
+		if _, err := runRawOutput(ctx, runner, "umount", target); err != nil {
+			return unmounted, fmt.Errorf("failed to unmount %s: %w", target, err)
+		}
+		unmounted = append(unmounted, target)
+	}
+	return unmounted, nil
+}
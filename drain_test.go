@@ -0,0 +1,119 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestDrainNode(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	if err := clnt.SetDevice("/dev/sdb", MustParseSize("1G")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	for name, tags := range map[LogicalVolumeName]Tags{
+		"managed1":  {"managed"},
+		"managed2":  {"managed"},
+		"unmanaged": nil,
+	} {
+		if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), name, MustParseSize("64M"), tags); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := DrainNode(ctx, clnt, Tags{"managed"}, DrainUnmount(true)); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected unmount to be unsupported by the fake client, got %v", err)
+	}
+
+	report, err := DrainNode(ctx, clnt, VolumeGroupNames{"vg"}, Tags{"managed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deactivated) != 2 || len(report.Failures) != 0 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	lvs, err := clnt.LVs(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, lv := range lvs {
+		if active := lv.Attr.State != StateNone; active != (lv.Name == "unmanaged") {
+			t.Errorf("unexpected activation state %c of %s", lv.Attr.State, lv.Name)
+		}
+	}
+
+	// a drained node has nothing left to release
+	report, err = DrainNode(ctx, clnt, Tags{"managed"})
+	if err != nil || len(report.Deactivated) != 0 {
+		t.Fatalf("expected nothing to drain, got %+v: %v", report, err)
+	}
+}
+
+func TestDrainNodeUnmountWithWrappedClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var commands [][]string
+	reports := 0
+	base := NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Args)
+		switch {
+		case slices.Contains(cmd.Args, "lvs"):
+			// the logical volume is active until it is deactivated
+			attr := "-wi-ao----"
+			if reports++; reports > 1 {
+				attr = "-wi-------"
+			}
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"lv","vg_name":"vg","lv_attr":"` + attr + `"}]}]}`)), nil
+		case cmd.Args[0] == "findmnt":
+			return io.NopCloser(strings.NewReader("/mnt/data\n")), nil
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+
+	report, err := DrainNode(ctx, WithNoNsenter(NewLockingClient(base)), VolumeGroupNames{"vg"}, DrainUnmount(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(report.Unmounted, []string{"/mnt/data"}) || len(report.Deactivated) != 1 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	for _, command := range commands {
+		if command[0] == "/usr/bin/nsenter" {
+			t.Fatalf("expected commands to run without nsenter, got %v", command)
+		}
+	}
+}
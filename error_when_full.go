@@ -16,6 +16,13 @@
 
 package lvm2go
 
+import (
+	"fmt"
+)
+
+// ErrorWhenFull makes a thin pool return errors immediately when it runs out of data space,
+// instead of queueing I/O until the pool is extended or the queue times out.
+// Overcommitted thin pools are hardened by erroring early, so that filesystems notice full pools before data is lost in queues.
 type ErrorWhenFull bool
 
 func (opt *ErrorWhenFull) ApplyToArgs(args Arguments) error {
@@ -23,10 +30,19 @@ func (opt *ErrorWhenFull) ApplyToArgs(args Arguments) error {
 		return nil
 	}
 
-	args.AddOrReplaceAll([]string{"--errorwhenfull", map[bool]string{true: "y", false: "n"}[bool(*opt)]})
+	args.AddOrReplace(fmt.Sprintf("--errorwhenfull=%s", map[bool]string{true: "y", false: "n"}[bool(*opt)]))
 	return nil
 }
 
 func (opt *ErrorWhenFull) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.ErrorWhenFull = opt
 }
+
+// WhenFull is the behavior of a thin pool running out of data space as reported in lv_when_full,
+// empty for other logical volumes. See ErrorWhenFull.
+type WhenFull string
+
+const (
+	WhenFullError WhenFull = "error"
+	WhenFullQueue WhenFull = "queue"
+)
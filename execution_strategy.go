@@ -0,0 +1,175 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+)
+
+// ErrNoExecutionStrategy is returned by commands of a client for which none of its ExecutionStrategies passed its probe.
+var ErrNoExecutionStrategy = errors.New("no execution strategy is available")
+
+// DefaultHostRoot is the directory the root filesystem of the host is usually mounted to in containers.
+const DefaultHostRoot = "/host"
+
+// ExecutionStrategy is a way to run commands on the host, see ExecutionStrategies.
+type ExecutionStrategy interface {
+	// Name identifies the strategy, e.g. in logs and Defaults.
+	Name() string
+	// Probe checks whether the lvm binary at lvmPath can be run with the strategy in the current environment.
+	Probe(ctx context.Context, lvmPath string) error
+	// Wrap returns the command and arguments that run cmd with args with the strategy.
+	Wrap(cmd string, args ...string) (string, []string)
+}
+
+// NsenterStrategy runs commands in the namespaces of PID 1 with nsenter, which requires hostPID, see CheckNsenter.
+type NsenterStrategy struct{}
+
+func (NsenterStrategy) Name() string {
+	return "nsenter"
+}
+
+func (NsenterStrategy) Probe(context.Context, string) error {
+	return CheckNsenter()
+}
+
+func (NsenterStrategy) Wrap(cmd string, args ...string) (string, []string) {
+	return nsenter, append([]string{"-m", "-u", "-i", "-n", "-p", "-t", "1", cmd}, args...)
+}
+
+// ChrootStrategy runs commands with chroot in Root, the root filesystem of the host mounted into the container.
+// It works without hostPID, but requires the host devices, e.g. with a /dev mount, and CAP_SYS_CHROOT.
+type ChrootStrategy struct {
+	// Root is the mount of the host root filesystem, DefaultHostRoot if empty.
+	Root string
+}
+
+func (s ChrootStrategy) root() string {
+	if s.Root == "" {
+		return DefaultHostRoot
+	}
+	return s.Root
+}
+
+func (s ChrootStrategy) Name() string {
+	return "chroot"
+}
+
+func (s ChrootStrategy) Probe(_ context.Context, lvmPath string) error {
+	if _, err := exec.LookPath("chroot"); err != nil {
+		return err
+	}
+	return probeExecutable(filepath.Join(s.root(), lvmPath))
+}
+
+func (s ChrootStrategy) Wrap(cmd string, args ...string) (string, []string) {
+	return "chroot", append([]string{s.root(), cmd}, args...)
+}
+
+// DirectStrategy runs commands directly, e.g. on the host itself or in privileged containers that ship lvm2.
+type DirectStrategy struct{}
+
+func (DirectStrategy) Name() string {
+	return "direct"
+}
+
+func (DirectStrategy) Probe(_ context.Context, lvmPath string) error {
+	return probeExecutable(lvmPath)
+}
+
+func (DirectStrategy) Wrap(cmd string, args ...string) (string, []string) {
+	return cmd, args
+}
+
+func probeExecutable(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() || info.Mode().Perm()&0o111 == 0 {
+		return fmt.Errorf("%s is not executable", path)
+	}
+	return nil
+}
+
+// ExecutionStrategies is a chain of strategies to run commands on the host. NewClient probes the strategies in order
+// and selects the first one that passes, so that the same binary works across different privilege models.
+// Contexts with WithForceNoNsenter, e.g. of clients created with WithNoNsenter, bypass the strategy and
+// run commands directly. ExecutionStrategies replace the NsenterPolicy of the client.
+type ExecutionStrategies []ExecutionStrategy
+
+// DefaultExecutionStrategies tries nsenter, chroot into DefaultHostRoot and direct execution, in this order.
+var DefaultExecutionStrategies = ExecutionStrategies{NsenterStrategy{}, ChrootStrategy{}, DirectStrategy{}}
+
+func (opt ExecutionStrategies) ApplyToClientOptions(opts *ClientOptions) {
+	opts.ExecutionStrategies = opt
+}
+
+// SelectExecutionStrategy returns the first strategy whose probe passes for the lvm binary at lvmPath.
+// If none passes, the error wraps ErrNoExecutionStrategy and the errors of all probes.
+func SelectExecutionStrategy(ctx context.Context, lvmPath string, strategies ...ExecutionStrategy) (ExecutionStrategy, error) {
+	errs := []error{ErrNoExecutionStrategy}
+	for _, strategy := range strategies {
+		err := strategy.Probe(ctx, lvmPath)
+		if err == nil {
+			return strategy, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", strategy.Name(), err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// withExecutionStrategy creates a context in which commands are wrapped with the given strategy.
+func withExecutionStrategy(ctx context.Context, strategy ExecutionStrategy) context.Context {
+	return context.WithValue(ctx, executionStrategyKey, strategy)
+}
+
+// commandExecutionStrategy returns the strategy commands run with the context are wrapped with:
+// the strategy selected by the client, nsenter if WillUseNsenter, or direct execution otherwise.
+func commandExecutionStrategy(ctx context.Context) ExecutionStrategy {
+	if strategy, ok := ctx.Value(executionStrategyKey).(ExecutionStrategy); ok {
+		return strategy
+	}
+	if WillUseNsenter(ctx) {
+		return NsenterStrategy{}
+	}
+	return DirectStrategy{}
+}
+
+// unwrapArgs removes the binary and arguments added by the strategy in Wrap from args, the arguments
+// of an exec.Cmd including the binary, so that the wrapped command and its arguments remain.
+// args that were not wrapped by the strategy are returned unchanged.
+func unwrapArgs(strategy ExecutionStrategy, args []string) []string {
+	// wrap a placeholder to find the arguments the strategy adds in front of the command
+	const placeholder = "\x00"
+	wrapper, wrapperArgs := strategy.Wrap(placeholder)
+	i := slices.Index(wrapperArgs, placeholder)
+	if wrapper == placeholder || i < 0 {
+		return args
+	}
+	prefix := append([]string{wrapper}, wrapperArgs[:i]...)
+	if len(args) <= len(prefix) || !slices.Equal(args[:len(prefix)], prefix) {
+		return args
+	}
+	return args[len(prefix):]
+}
@@ -0,0 +1,99 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+type stubStrategy struct {
+	DirectStrategy
+	err error
+}
+
+func (s stubStrategy) Probe(context.Context, string) error {
+	return s.err
+}
+
+func TestExecutionStrategies(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	if _, err := exec.LookPath("chroot"); err != nil {
+		t.Skip("chroot is not available")
+	}
+
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "sbin"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "sbin", "lvm"), nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	unavailable := stubStrategy{err: errors.New("unavailable")}
+	strategy, err := SelectExecutionStrategy(ctx, "/sbin/lvm", unavailable, ChrootStrategy{Root: root}, DirectStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strategy.Name() != "chroot" {
+		t.Fatalf("expected chroot strategy, got %s", strategy.Name())
+	}
+	if _, err := SelectExecutionStrategy(ctx, "/sbin/lvm", unavailable); !errors.Is(err, ErrNoExecutionStrategy) || !strings.Contains(err.Error(), "unavailable") {
+		t.Fatalf("expected ErrNoExecutionStrategy with probe errors, got %v", err)
+	}
+
+	var args []string
+	clnt := NewClient(
+		LVMPath("/sbin/lvm"),
+		ExecutionStrategies{unavailable, ChrootStrategy{Root: root}},
+		CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+			args = cmd.Args
+			return io.NopCloser(strings.NewReader("")), nil
+		}),
+	)
+	if err := clnt.VGScan(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"chroot", root, "/sbin/lvm", "vgscan", "--yes"}; !slices.Equal(args, expected) {
+		t.Fatalf("expected %v, got %v", expected, args)
+	}
+	if defaults := clnt.Defaults(ctx); defaults.ExecutionStrategy.Value != "chroot" || defaults.UseNsenter.Value {
+		t.Fatalf("unexpected defaults %+v", defaults)
+	}
+
+	if err := WithNoNsenter(clnt).VGScan(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if args[0] != "/sbin/lvm" {
+		t.Fatalf("expected direct execution without nsenter, got %v", args)
+	}
+
+	clnt = NewClient(ExecutionStrategies{unavailable})
+	if err := clnt.VGScan(ctx); !errors.Is(err, ErrNoExecutionStrategy) {
+		t.Fatalf("expected ErrNoExecutionStrategy, got %v", err)
+	}
+}
@@ -53,6 +53,8 @@ var percentCandidates = []ExtentPercent{
 	ExtentPercentVG,
 }
 
+// Extents is a number of extents, or a percentage of the space given by ExtentPercent, e.g. 10%FREE.
+// It sets the size of a logical volume with LVCreate, LVExtend and LVResize. See PrefixedExtents for relative changes.
 type Extents struct {
 	Val uint64
 	ExtentPercent
@@ -145,6 +147,17 @@ func (opt Extents) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Extents = opt
 }
 
+func (opt Extents) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.PrefixedExtents = NewPrefixedExtents(SizePrefixNone, opt)
+}
+
+func (opt Extents) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.PrefixedExtents = NewPrefixedExtents(SizePrefixNone, opt)
+}
+
+// PrefixedExtents changes the size of a logical volume by the Extents with a SizePrefixPlus or SizePrefixMinus,
+// e.g. +10%FREE to grow by a tenth of the free space of the volume group, or sets it without a prefix.
+// Only LVResize and LVReduce accept SizePrefixMinus.
 type PrefixedExtents struct {
 	SizePrefix
 	Extents
@@ -226,3 +239,7 @@ func (opt PrefixedExtents) ApplyToArgs(args Arguments) error {
 func (opt PrefixedExtents) ApplyToLVExtendOptions(opts *LVExtendOptions) {
 	opts.PrefixedExtents = opt
 }
+
+func (opt PrefixedExtents) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.PrefixedExtents = opt
+}
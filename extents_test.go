@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"testing"
 )
 
@@ -196,6 +197,42 @@ func Test_Extents(t *testing.T) {
 		}
 	})
 
+	t.Run("LVExtendAndLVResize", func(t *testing.T) {
+		args, err := LVExtendOptionsList{
+			VolumeGroupName("vg"), LogicalVolumeName("lv"), NewExtents(50, ExtentPercentVG),
+		}.AsArgs()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if raw := args.GetRaw(); raw[len(raw)-1] != "--extents=50%VG" {
+			t.Errorf("unexpected args: %v", raw)
+		}
+
+		args, err = LVResizeOptionsList{
+			VolumeGroupName("vg"), LogicalVolumeName("lv"), NewPrefixedExtents(SizePrefixMinus, NewExtents(10, ExtentPercentFree)),
+		}.AsArgs()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if raw := args.GetRaw(); raw[len(raw)-1] != "--extents=-10%FREE" {
+			t.Errorf("unexpected args: %v", raw)
+		}
+
+		args, err = LVResizeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("lv"), UsePolicies(true)}.AsArgs()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !slices.Contains(args.GetRaw(), "--use-policies") {
+			t.Errorf("unexpected args: %v", args.GetRaw())
+		}
+
+		if _, err := (LVResizeOptionsList{
+			VolumeGroupName("vg"), LogicalVolumeName("lv"), UsePolicies(true), NewExtents(10, ExtentPercentFree),
+		}).AsArgs(); err == nil {
+			t.Error("expected error for extents together with use policies")
+		}
+	})
+
 	t.Run("Validate", func(t *testing.T) {
 		if err := NewExtents(0, ExtentPercentOrigin).Validate(); !errors.Is(err, ErrInvalidExtentsGTZero) {
 			t.Errorf("unexpected error: %v", err)
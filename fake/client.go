@@ -0,0 +1,609 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package fake provides an in-memory implementation of lvm2go.Client for unit tests.
+//
+// The fake models block devices, physical volumes, volume groups and logical volumes
+// including extent based size accounting, so that code under test observes the same
+// free space, name collisions and error messages as with lvm2 on a real host.
+// Errors are reported as lvm2go.LVMStdErr with the messages of lvm2, so that helpers
+// such as lvm2go.IsVolumeGroupNotFound or lvm2go.IsLogicalVolumeNotFound work as expected.
+//
+// Example usage:
+//
+//	client := fake.NewClient()
+//	client.SetDevice("/dev/sdb", lvm2go.MustParseSize("10G"))
+//	if err := client.VGCreate(ctx, lvm2go.VolumeGroupName("vg"), lvm2go.PhysicalVolumeName("/dev/sdb")); err != nil {
+//		t.Fatal(err)
+//	}
+//	controller := NewController(client)
+package fake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/azalio/lvm2go"
+)
+
+const (
+	// DefaultExtentSize is the extent size of volume groups created without PhysicalExtentSize.
+	DefaultExtentSize = 4 * 1024 * 1024
+	// DefaultPeStart is the space reserved for metadata at the start of every physical volume.
+	DefaultPeStart = 1024 * 1024
+	// DefaultThinPoolMetadataSize is the metadata size of newly created thin pools.
+	// The metadata is not allocated from the volume group.
+	DefaultThinPoolMetadataSize = 4 * 1024 * 1024
+)
+
+// DefaultVersion is the version reported by a Client unless changed with SetVersion.
+var DefaultVersion = lvm2go.Version{
+	LVMVersion:     lvm2go.MustParseVersionNumber("2.03.16(2)"),
+	LibraryVersion: lvm2go.MustParseVersionNumber("1.02.185"),
+	DriverVersion:  lvm2go.MustParseVersionNumber("4.48.0"),
+	Targets: lvm2go.DeviceMapperTargets{
+		{Name: lvm2go.DeviceMapperTargetLinear, Version: lvm2go.MustParseVersionNumber("1.4.0")},
+		{Name: lvm2go.DeviceMapperTargetStriped, Version: lvm2go.MustParseVersionNumber("1.6.0")},
+		{Name: lvm2go.DeviceMapperTargetSnapshot, Version: lvm2go.MustParseVersionNumber("1.16.0")},
+		{Name: lvm2go.DeviceMapperTargetThinPool, Version: lvm2go.MustParseVersionNumber("1.23.0")},
+		{Name: lvm2go.DeviceMapperTargetThin, Version: lvm2go.MustParseVersionNumber("1.23.0")},
+	},
+}
+
+var ErrUnsupported = fmt.Errorf("not supported by the fake client: %w", errors.ErrUnsupported)
+
+// Client is an in-memory implementation of lvm2go.Client.
+// It is safe for concurrent use. Objects returned by the client are copies and can be modified freely.
+//
+// Block devices have to be registered with SetDevice before they can be used as physical volumes.
+// Sizes are reported in the Unit requested through the options, or in bytes if no Unit is requested.
+// Configuration and profile handling is not modeled and returns ErrUnsupported, as do Select and RetainRawJSON options.
+// The only exception is the selection of logical volumes by tags with lvm2go.LVTags.ContainsAny.
+// Like lvm, positional names and tags of reports are a union: objects are reported if they are named
+// or carry one of the tags.
+type Client struct {
+	mu sync.Mutex
+
+	devices map[lvm2go.PhysicalVolumeName]uint64
+	pvs     map[lvm2go.PhysicalVolumeName]*physicalVolume
+	vgs     map[lvm2go.VolumeGroupName]*volumeGroup
+	// devicesFile is the in-memory equivalent of the lvm2 devices file.
+	devicesFile []lvm2go.DeviceListEntry
+	// backups are the metadata backups taken by VGCfgBackup, oldest first.
+	backups []metadataBackup
+	version lvm2go.Version
+	seq     int
+}
+
+var _ lvm2go.Client = (*Client)(nil)
+
+type physicalVolume struct {
+	uuid string
+	name lvm2go.PhysicalVolumeName
+	// size is the size of the device in bytes when the physical volume was created or last resized.
+	size uint64
+	vg   lvm2go.VolumeGroupName
+	tags lvm2go.Tags
+	// metadataCopies are the metadata areas requested by PVCreate, zero for the default of one.
+	metadataCopies lvm2go.MetadataCopies
+	metadataIgnore bool
+	// notAllocatable prevents the allocation of new extents.
+	notAllocatable bool
+}
+
+type volumeGroup struct {
+	uuid       string
+	name       lvm2go.VolumeGroupName
+	extentSize uint64
+	pvs        []lvm2go.PhysicalVolumeName
+	lvs        []*logicalVolume
+	tags       lvm2go.Tags
+	maxLv      int
+	maxPv      int
+	seqNo      int64
+	exported   bool
+	// noAutoActivation disables autoactivation, which is enabled by default.
+	noAutoActivation bool
+	// notResizeable prevents adding and removing physical volumes.
+	notResizeable bool
+	systemID      lvm2go.SystemID
+}
+
+type logicalVolume struct {
+	uuid       string
+	name       lvm2go.LogicalVolumeName
+	volumeType lvm2go.VolumeType
+	// segments are the extents allocated on physical volumes, empty for thin volumes.
+	segments []segment
+	// virtualSize is the size in bytes of thin volumes.
+	virtualSize  uint64
+	metadataSize uint64
+	pool         lvm2go.LogicalVolumeName
+	origin       lvm2go.LogicalVolumeName
+	tags         lvm2go.Tags
+	active       bool
+	skip         bool
+	readOnly     bool
+	// noZero and errorWhenFull are the zeroing and the behavior of thin pools running out of data space.
+	noZero        bool
+	errorWhenFull bool
+	// persistent is the persistent device number set with PersistentDeviceNumber.
+	persistent *lvm2go.DeviceNumber
+	// noAutoActivation disables autoactivation, which is enabled by default.
+	noAutoActivation bool
+	created          time.Time
+}
+
+type segment struct {
+	pv      lvm2go.PhysicalVolumeName
+	extents uint64
+}
+
+// NewClient returns an empty Client without any devices.
+func NewClient() *Client {
+	return &Client{
+		devices: map[lvm2go.PhysicalVolumeName]uint64{},
+		pvs:     map[lvm2go.PhysicalVolumeName]*physicalVolume{},
+		vgs:     map[lvm2go.VolumeGroupName]*volumeGroup{},
+		version: DefaultVersion,
+	}
+}
+
+// SetDevice adds a block device of the given size or changes the size of an existing device,
+// e.g. to simulate growing a disk before calling PVResize.
+func (c *Client) SetDevice(name string, size lvm2go.Size) error {
+	bytes, err := size.ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return err
+	}
+	if bytes.Val < DefaultPeStart {
+		return fmt.Errorf("device %s is too small: %s", name, size)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.devices[lvm2go.PhysicalVolumeName(name)] = uint64(bytes.Val)
+	return nil
+}
+
+// RemoveDevice removes a block device that is not used as a physical volume.
+func (c *Client) RemoveDevice(name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.pvs[lvm2go.PhysicalVolumeName(name)]; ok {
+		return fmt.Errorf("device %s is still used as a physical volume", name)
+	}
+	delete(c.devices, lvm2go.PhysicalVolumeName(name))
+	return nil
+}
+
+// SetVersion changes the version reported by Version.
+func (c *Client) SetVersion(version lvm2go.Version) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.version = version
+}
+
+// lvmError returns an error formatted like the output of lvm2 on stderr.
+func lvmError(format string, args ...any) error {
+	return lvm2go.NewLVMStdErr([]byte(fmt.Sprintf(format, args...)))
+}
+
+// errLockdUnavailable is returned for shared volume groups, as the fake client does not simulate lvmlockd.
+func errLockdUnavailable() error {
+	return lvmError("Using a shared lock type requires lvmlockd.")
+}
+
+func errVolumeGroupNotFound(vg lvm2go.VolumeGroupName) error {
+	return lvmError("Volume group %q not found", vg)
+}
+
+func errLogicalVolumeNotFound(vg lvm2go.VolumeGroupName, lv lvm2go.LogicalVolumeName) error {
+	return lvmError("Failed to find logical volume \"%s/%s\"", vg, lv)
+}
+
+func errInsufficientFreeSpace(vg lvm2go.VolumeGroupName, free, required uint64) error {
+	return lvmError("Volume group %q has insufficient free space (%d extents): %d required.", vg, free, required)
+}
+
+func errSelectUnsupported(sel lvm2go.Select) error {
+	if sel == "" {
+		return nil
+	}
+	return fmt.Errorf("select %q: %w", sel, ErrUnsupported)
+}
+
+func errSortUnsupported(fields lvm2go.SortFields) error {
+	if len(fields) == 0 {
+		return nil
+	}
+	return fmt.Errorf("sort %v: %w", fields, ErrUnsupported)
+}
+
+func errRetainRawJSONUnsupported(retain lvm2go.RetainRawJSON) error {
+	if !retain {
+		return nil
+	}
+	return fmt.Errorf("raw JSON: %w", ErrUnsupported)
+}
+
+func (c *Client) nextUUID() string {
+	c.seq++
+	id := fmt.Sprintf("%032d", c.seq)
+	return strings.Join([]string{id[0:6], id[6:10], id[10:14], id[14:18], id[18:22], id[22:26], id[26:32]}, "-")
+}
+
+func (c *Client) volumeGroup(name lvm2go.VolumeGroupName) (*volumeGroup, error) {
+	if name == "" {
+		return nil, lvm2go.ErrVolumeGroupNameRequired
+	}
+	vg, ok := c.vgs[name]
+	if !ok {
+		return nil, errVolumeGroupNotFound(name)
+	}
+	return vg, nil
+}
+
+func (c *Client) logicalVolume(vgName lvm2go.VolumeGroupName, lvName lvm2go.LogicalVolumeName) (*volumeGroup, *logicalVolume, error) {
+	if lvName == "" {
+		return nil, nil, lvm2go.ErrLogicalVolumeNameRequired
+	}
+	vg, err := c.volumeGroup(vgName)
+	if err != nil {
+		return nil, nil, err
+	}
+	lv := vg.lv(lvName)
+	if lv == nil {
+		return nil, nil, errLogicalVolumeNotFound(vgName, lvName)
+	}
+	return vg, lv, nil
+}
+
+func (vg *volumeGroup) lv(name lvm2go.LogicalVolumeName) *logicalVolume {
+	for _, lv := range vg.lvs {
+		if lv.name == name {
+			return lv
+		}
+	}
+	return nil
+}
+
+func (vg *volumeGroup) removeLV(name lvm2go.LogicalVolumeName) {
+	vg.lvs = slices.DeleteFunc(vg.lvs, func(lv *logicalVolume) bool {
+		return lv.name == name
+	})
+}
+
+// dependents returns the snapshots of an origin and the thin volumes of a thin pool.
+func (vg *volumeGroup) dependents(name lvm2go.LogicalVolumeName) []*logicalVolume {
+	var dependents []*logicalVolume
+	for _, lv := range vg.lvs {
+		if lv.origin == name || lv.pool == name {
+			dependents = append(dependents, lv)
+		}
+	}
+	return dependents
+}
+
+// pvExtents returns the number of extents of a physical volume in the volume group.
+func (c *Client) pvExtents(vg *volumeGroup, pv lvm2go.PhysicalVolumeName) uint64 {
+	return (c.pvs[pv].size - DefaultPeStart) / vg.extentSize
+}
+
+// pvAllocated returns the number of extents allocated on a physical volume in the volume group.
+func (vg *volumeGroup) pvAllocated(pv lvm2go.PhysicalVolumeName) uint64 {
+	var allocated uint64
+	for _, lv := range vg.lvs {
+		for _, seg := range lv.segments {
+			if seg.pv == pv {
+				allocated += seg.extents
+			}
+		}
+	}
+	return allocated
+}
+
+func (c *Client) extentCount(vg *volumeGroup) uint64 {
+	var extents uint64
+	for _, pv := range vg.pvs {
+		extents += c.pvExtents(vg, pv)
+	}
+	return extents
+}
+
+func (c *Client) freeCount(vg *volumeGroup) uint64 {
+	var free uint64
+	for _, pv := range vg.pvs {
+		// like lvm2, free extents of physical volumes that are not allocatable are not counted
+		if c.pvs[pv].notAllocatable {
+			continue
+		}
+		free += c.pvExtents(vg, pv) - vg.pvAllocated(pv)
+	}
+	return free
+}
+
+// allocate allocates extents on the physical volumes of the volume group in order, skipping the excluded ones.
+func (c *Client) allocate(vg *volumeGroup, extents uint64, exclude ...lvm2go.PhysicalVolumeName) ([]segment, error) {
+	var segments []segment
+	var free uint64
+	exclude = slices.Clone(exclude)
+	for _, pv := range vg.pvs {
+		if c.pvs[pv].notAllocatable {
+			exclude = append(exclude, pv)
+		}
+	}
+	for _, pv := range vg.pvs {
+		if slices.Contains(exclude, pv) {
+			continue
+		}
+		free += c.pvExtents(vg, pv) - vg.pvAllocated(pv)
+	}
+	if free < extents {
+		return nil, errInsufficientFreeSpace(vg.name, free, extents)
+	}
+	for _, pv := range vg.pvs {
+		if extents == 0 {
+			break
+		}
+		if slices.Contains(exclude, pv) {
+			continue
+		}
+		if n := min(extents, c.pvExtents(vg, pv)-vg.pvAllocated(pv)); n > 0 {
+			segments = append(segments, segment{pv: pv, extents: n})
+			extents -= n
+		}
+	}
+	return segments, nil
+}
+
+// unselected returns the physical volumes of the volume group not selected by the selectors, to exclude them
+// from allocate. Without selectors, no physical volume is excluded. The extent ranges of the selectors are not modeled.
+func (vg *volumeGroup) unselected(selectors lvm2go.PhysicalExtentSelectors) ([]lvm2go.PhysicalVolumeName, error) {
+	if len(selectors) == 0 {
+		return nil, nil
+	}
+	for _, selector := range selectors {
+		if err := selector.Validate(); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(vg.pvs, selector.PhysicalVolumeName) {
+			return nil, lvmError("Physical Volume %q not found in Volume Group %q.", selector.PhysicalVolumeName, vg.name)
+		}
+	}
+	var exclude []lvm2go.PhysicalVolumeName
+	for _, pv := range vg.pvs {
+		if !slices.ContainsFunc(selectors, func(selector lvm2go.PhysicalExtentSelector) bool {
+			return selector.PhysicalVolumeName == pv
+		}) {
+			exclude = append(exclude, pv)
+		}
+	}
+	return exclude, nil
+}
+
+func (lv *logicalVolume) extents() uint64 {
+	var extents uint64
+	for _, seg := range lv.segments {
+		extents += seg.extents
+	}
+	return extents
+}
+
+// size returns the size of the logical volume in bytes.
+func (lv *logicalVolume) size(vg *volumeGroup) uint64 {
+	if lv.volumeType == lvm2go.VolumeTypeThinVolume {
+		return lv.virtualSize
+	}
+	return lv.extents() * vg.extentSize
+}
+
+// resize changes the allocation of the logical volume to the given number of extents.
+// Extents are freed from the end of the logical volume and allocated on physical volumes that are not excluded.
+func (c *Client) resize(vg *volumeGroup, lv *logicalVolume, extents uint64, exclude ...lvm2go.PhysicalVolumeName) error {
+	current := lv.extents()
+	if extents > current {
+		segments, err := c.allocate(vg, extents-current, exclude...)
+		if err != nil {
+			return err
+		}
+		lv.segments = append(lv.segments, segments...)
+		return nil
+	}
+	for remove := current - extents; remove > 0; {
+		last := &lv.segments[len(lv.segments)-1]
+		n := min(remove, last.extents)
+		last.extents -= n
+		remove -= n
+		if last.extents == 0 {
+			lv.segments = lv.segments[:len(lv.segments)-1]
+		}
+	}
+	return nil
+}
+
+// toExtents converts a size to extents, rounding up to full extents like lvm2.
+func toExtents(size lvm2go.Size, extentSize uint64) (uint64, error) {
+	bytes, err := size.ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(math.Ceil(bytes.Val / float64(extentSize))), nil
+}
+
+// align aligns a requested size to the extent size with the alignment of the options,
+// like lvm2go.Client does before building the arguments.
+func align(alignment lvm2go.SizeAlignment, size lvm2go.Size, extentSize uint64) (lvm2go.Size, error) {
+	if size.Val <= 0 {
+		return size, nil
+	}
+	return alignment.Align(size, lvm2go.NewSize(float64(extentSize), lvm2go.UnitBytes))
+}
+
+// percentExtents resolves extents relative to the volume group or an origin.
+func (c *Client) percentExtents(vg *volumeGroup, extents lvm2go.Extents, origin *logicalVolume) (uint64, error) {
+	var base uint64
+	switch extents.ExtentPercent {
+	case "":
+		return extents.Val, nil
+	case lvm2go.ExtentPercentFree:
+		base = c.freeCount(vg)
+	case lvm2go.ExtentPercentVG, lvm2go.ExtentPercentPVS:
+		base = c.extentCount(vg)
+	case lvm2go.ExtentPercentOrigin:
+		if origin == nil {
+			return 0, lvmError("Please express size as %%FREE, %%PVS or %%VG.")
+		}
+		base = origin.size(vg) / vg.extentSize
+	default:
+		return 0, lvm2go.ErrInvalidPercentDefinition
+	}
+	return base * extents.Val / 100, nil
+}
+
+func sizeIn(bytes uint64, unit lvm2go.Unit) lvm2go.Size {
+	size := lvm2go.NewSize(float64(bytes), lvm2go.UnitBytes)
+	if unit == lvm2go.UnitUnknown {
+		return size
+	}
+	if converted, err := size.ToUnit(unit); err == nil {
+		return converted
+	}
+	return size
+}
+
+// selectedTags returns the tags of a select built with field.ContainsAny, the only select modeled by the client.
+func selectedTags(field lvm2go.ReportField, sel lvm2go.Select) (lvm2go.Tags, error) {
+	if sel == "" {
+		return nil, nil
+	}
+	list, ok := strings.CutPrefix(string(sel), string(field)+string(lvm2go.Match)+string(lvm2go.ListSubsetStart))
+	if !ok {
+		return nil, errSelectUnsupported(sel)
+	}
+	if list, ok = strings.CutSuffix(list, string(lvm2go.ListSubsetEnd)); !ok {
+		return nil, errSelectUnsupported(sel)
+	}
+	var tags lvm2go.Tags
+	for _, item := range strings.Split(list, " "+string(lvm2go.AtLeastOneFieldMatches)+" ") {
+		tags = append(tags, strings.Trim(item, `"'`))
+	}
+	return tags, nil
+}
+
+// inPositionalScope reports whether an object is selected by the positional names and tags of a report.
+// Without names and tags all objects are selected, otherwise the objects that are named or carry one of the tags.
+func inPositionalScope(named, inScope bool, tags lvm2go.Tags, filter lvm2go.Tags) bool {
+	if !named && len(filter) == 0 {
+		return true
+	}
+	return (named && inScope) || (len(filter) > 0 && matchesTags(tags, filter))
+}
+
+func matchesTags(tags lvm2go.Tags, filter lvm2go.Tags) bool {
+	if len(filter) == 0 {
+		return true
+	}
+	for _, tag := range filter {
+		if slices.Contains(tags, strings.TrimPrefix(tag, lvm2go.TagSymbol)) {
+			return true
+		}
+	}
+	return false
+}
+
+func addTags(tags lvm2go.Tags, add lvm2go.Tags, del lvm2go.DelTags) lvm2go.Tags {
+	for _, tag := range add {
+		if tag = strings.TrimPrefix(tag, lvm2go.TagSymbol); !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return slices.DeleteFunc(tags, func(tag string) bool {
+		return slices.Contains(del, tag) || slices.Contains(del, lvm2go.SymboledTag(tag))
+	})
+}
+
+// Version reports the version set with SetVersion, including its targets only with TargetVersions.
+func (c *Client) Version(_ context.Context, opts ...lvm2go.VersionOption) (lvm2go.Version, error) {
+	options := lvm2go.VersionOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVersionOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	version := c.version
+	if !options.TargetVersions {
+		version.Targets = nil
+	}
+	return version, nil
+}
+
+func (c *Client) RawConfig(context.Context, ...lvm2go.ConfigOption) (lvm2go.RawConfig, error) {
+	return nil, fmt.Errorf("RawConfig: %w", ErrUnsupported)
+}
+
+func (c *Client) ReadAndDecodeConfig(context.Context, any, ...lvm2go.ConfigOption) error {
+	return fmt.Errorf("ReadAndDecodeConfig: %w", ErrUnsupported)
+}
+
+// WriteAndEncodeConfig encodes v like lvm2go.Client, as encoding does not require lvm2.
+func (c *Client) WriteAndEncodeConfig(ctx context.Context, v any, writer io.Writer) error {
+	return lvm2go.NewClient().WriteAndEncodeConfig(ctx, v, writer)
+}
+
+func (c *Client) UpdateGlobalConfig(context.Context, any) error {
+	return fmt.Errorf("UpdateGlobalConfig: %w", ErrUnsupported)
+}
+
+func (c *Client) UpdateLocalConfig(context.Context, any) error {
+	return fmt.Errorf("UpdateLocalConfig: %w", ErrUnsupported)
+}
+
+func (c *Client) UpdateProfileConfig(context.Context, any, lvm2go.Profile) error {
+	return fmt.Errorf("UpdateProfileConfig: %w", ErrUnsupported)
+}
+
+func (c *Client) CreateProfile(context.Context, any, lvm2go.Profile) (string, error) {
+	return "", fmt.Errorf("CreateProfile: %w", ErrUnsupported)
+}
+
+func (c *Client) RemoveProfile(context.Context, lvm2go.Profile) error {
+	return fmt.Errorf("RemoveProfile: %w", ErrUnsupported)
+}
+
+func (c *Client) GetProfilePath(context.Context, lvm2go.Profile) (string, error) {
+	return "", fmt.Errorf("GetProfilePath: %w", ErrUnsupported)
+}
+
+func (c *Client) GetProfileDirectory(context.Context) (string, error) {
+	return "", fmt.Errorf("GetProfileDirectory: %w", ErrUnsupported)
+}
+
+// Defaults returns the defaults of an lvm2go.Client without options, as the fake does not run any commands.
+func (c *Client) Defaults(ctx context.Context) lvm2go.Defaults {
+	return lvm2go.NewClient().Defaults(ctx)
+}
+
+func (c *Client) LVMDump(context.Context, ...lvm2go.LVMDumpOption) (string, error) {
+	return "", fmt.Errorf("lvmdump: %w", ErrUnsupported)
+}
@@ -0,0 +1,674 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func newClient(t *testing.T, devices ...string) *fake.Client {
+	t.Helper()
+	client := fake.NewClient()
+	for _, device := range devices {
+		// 1 MiB of metadata plus 25 extents of 4 MiB
+		if err := client.SetDevice(device, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return client
+}
+
+func TestClient_SizeAccounting(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb", "/dev/sdc")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err == nil {
+		t.Fatal("expected error for existing volume group")
+	}
+
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("exact"), MustParseSize("30M"), SizeAlignmentExact); !errors.Is(err, ErrSizeNotAligned) {
+		t.Fatalf("expected unaligned size error, got %v", err)
+	}
+
+	// 30 MiB are rounded up to 8 extents
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("30M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("4M")); err == nil {
+		t.Fatal("expected error for existing logical volume")
+	}
+
+	vg, err := client.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.ExtentCount != 50 || vg.FreeCount != 42 || vg.LvCount != 1 {
+		t.Fatalf("unexpected volume group accounting: %d extents, %d free, %d lvs", vg.ExtentCount, vg.FreeCount, vg.LvCount)
+	}
+
+	lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), UnitMiB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Size != NewSize(32, UnitMiB) || lv.Attr.State != StateActive {
+		t.Fatalf("unexpected logical volume: %v", lv)
+	}
+
+	// the remaining 42 extents span both physical volumes
+	if err := client.LVExtend(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedExtents("+100%FREE")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("full"), MustParseSize("4M")); err == nil {
+		t.Fatal("expected error for full volume group")
+	}
+	pvs, err := client.PVs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pv := range pvs {
+		if pv.Free.Val != 0 {
+			t.Fatalf("expected no free space on %s, got %s", pv.Name, pv.Free)
+		}
+	}
+
+	if err := client.LVResize(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVReduce(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("+4M")); err == nil {
+		t.Fatal("expected error for positive reduction")
+	}
+	if err := client.LVReduce(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("-36M")); err != nil {
+		t.Fatal(err)
+	}
+	if lv, err = client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), UnitMiB); err != nil {
+		t.Fatal(err)
+	} else if lv.Size != NewSize(64, UnitMiB) {
+		t.Fatalf("unexpected size after reduction: %s", lv.Size)
+	}
+	if err := client.PVMove(ctx, &PVMoveOptions{From: "/dev/sdc"}); !IsNoDataToMove(err) {
+		t.Fatalf("expected no data to move, got %v", err)
+	}
+	if err := client.VGReduce(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PVRemove(ctx, PhysicalVolumeName("/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClient_LVResizeExtents(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), NewExtents(5, "")); err != nil {
+		t.Fatal(err)
+	}
+	size := func() float64 {
+		t.Helper()
+		lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), UnitMiB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return lv.Size.Val
+	}
+
+	// 20 extents are free, half of them are added
+	if err := client.LVResize(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedExtents("+50%FREE")); err != nil {
+		t.Fatal(err)
+	}
+	if got := size(); got != 60 {
+		t.Fatalf("expected 60MiB after growing by 50%%FREE, got %v", got)
+	}
+	if err := client.LVResize(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedExtents("-20%VG")); err != nil {
+		t.Fatal(err)
+	}
+	if got := size(); got != 40 {
+		t.Fatalf("expected 40MiB after shrinking by 20%%VG, got %v", got)
+	}
+	if err := client.LVExtend(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), NewExtents(60, ExtentPercentVG)); err != nil {
+		t.Fatal(err)
+	}
+	if got := size(); got != 60 {
+		t.Fatalf("expected 60MiB after extending to 60%%VG, got %v", got)
+	}
+	if err := client.LVExtend(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), UsePolicies(true)); err != nil {
+		t.Fatal(err)
+	}
+	if got := size(); got != 60 {
+		t.Fatalf("expected the size to be unchanged by the default policies, got %v", got)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGRemove(ctx, VolumeGroupName("missing")); !IsVolumeGroupNotFound(err) {
+		t.Fatalf("expected volume group not found, got %v", err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb"), MaximumLogicalVolumes(1)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("missing")); !IsLogicalVolumeNotFound(err) {
+		t.Fatalf("expected logical volume not found, got %v", err)
+	}
+	if lvs, err := client.LVs(ctx, VolumeGroupName("vg"), LogicalVolumeName("missing")); err != nil || len(lvs) != 0 {
+		t.Fatalf("expected no logical volumes, got %v, %v", lvs, err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("4M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv2"), MustParseSize("4M")); !IsMaximumLogicalVolumesReached(err) {
+		t.Fatalf("expected maximum logical volumes reached, got %v", err)
+	}
+	if err := client.PVRemove(ctx, PhysicalVolumeName("/dev/sdb")); err == nil {
+		t.Fatal("expected error for physical volume in use")
+	}
+}
+
+func TestClient_ThinAndSnapshots(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("40M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewThinPool("vg", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// thin volumes can be bigger than the volume group
+	if err := client.LVCreate(ctx, pool, LogicalVolumeName("thin"), MustParseSize("1G").Virtual()); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := NewSnapshotOf("vg", "thin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, origin, LogicalVolumeName("snap")); err != nil {
+		t.Fatal(err)
+	}
+
+	lvs, err := client.LVs(ctx, VolumeGroupName("vg"), UnitBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 3 {
+		t.Fatalf("expected 3 logical volumes, got %d", len(lvs))
+	}
+	for _, lv := range lvs {
+		switch lv.Name {
+		case "pool":
+			if lv.Attr.VolumeType != VolumeTypeThinPool {
+				t.Fatalf("expected thin pool, got %s", lv.Attr)
+			}
+		case "thin":
+			if lv.Attr.VolumeType != VolumeTypeThinVolume || lv.PoolLogicalVolume != "pool" || lv.Size != NewSize(1<<30, UnitBytes) {
+				t.Fatalf("unexpected thin volume %v", lv)
+			}
+		case "snap":
+			if lv.Origin != "thin" || lv.Attr.SkipActivation != SkipActivationTrue {
+				t.Fatalf("unexpected thin snapshot %v", lv)
+			}
+		}
+	}
+
+	scoped, err := client.LVs(ctx, FQLogicalVolumeNames{MustNewFQLogicalVolumeName("vg", "thin"), MustNewFQLogicalVolumeName("vg", "snap")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(scoped) != 2 || scoped[0].Name != "thin" || scoped[1].Name != "snap" {
+		t.Fatalf("expected only the named logical volumes, got %v", scoped)
+	}
+
+	vg, err := client.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.FreeCount != 15 {
+		t.Fatalf("expected only the thin pool to allocate extents, got %d free", vg.FreeCount)
+	}
+
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool")); err == nil {
+		t.Fatal("expected error for thin pool with thin volumes")
+	}
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), Force(true)); err != nil {
+		t.Fatal(err)
+	}
+	if lvs, err := client.LVs(ctx, VolumeGroupName("vg")); err != nil || len(lvs) != 0 {
+		t.Fatalf("expected dependent volumes to be removed, got %v, %v", lvs, err)
+	}
+}
+
+func TestClient_ActivationSkip(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("8M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("skipped"), MustParseSize("8M"), SetActivationSkip); err != nil {
+		t.Fatal(err)
+	}
+	active := func() []LogicalVolumeName {
+		t.Helper()
+		lvs, err := client.LVs(ctx, VolumeGroupName("vg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []LogicalVolumeName
+		for _, lv := range lvs {
+			if lv.Attr.State == StateActive {
+				names = append(names, lv.Name)
+			}
+		}
+		return names
+	}
+	if names := active(); !slices.Equal(names, []LogicalVolumeName{"lv"}) {
+		t.Fatalf("expected logical volumes flagged to be skipped to be created inactive, got %v", names)
+	}
+
+	if err := client.VGChange(ctx, VolumeGroupName("vg"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if names := active(); len(names) != 0 {
+		t.Fatalf("expected all logical volumes to be deactivated, got %v", names)
+	}
+	if err := client.VGChange(ctx, VolumeGroupName("vg"), Activate); err != nil {
+		t.Fatal(err)
+	}
+	if names := active(); !slices.Equal(names, []LogicalVolumeName{"lv"}) {
+		t.Fatalf("expected logical volumes flagged to be skipped to stay inactive, got %v", names)
+	}
+	if err := client.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("skipped"), Activate, IgnoreActivationSkip(true)); err != nil {
+		t.Fatal(err)
+	}
+	if names := active(); len(names) != 2 {
+		t.Fatalf("expected IgnoreActivationSkip to activate the logical volume, got %v", names)
+	}
+
+	if err := client.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("skipped"), ClearActivationSkip); err != nil {
+		t.Fatal(err)
+	}
+	lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("skipped"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Attr.SkipActivation == SkipActivationTrue {
+		t.Fatalf("expected the activation skip flag to be cleared, got %s", lv.Attr)
+	}
+}
+
+func TestClient_LVConvert(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []LogicalVolumeName{"pool", "origin"} {
+		if err := client.LVCreate(ctx, VolumeGroupName("vg"), name, MustParseSize("8M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	snapshotOf, err := NewSnapshotOf("vg", "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, snapshotOf, LogicalVolumeName("snap"), MustParseSize("4M")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.LVConvert(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), TypeThinPool); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Attr.VolumeType != VolumeTypeThinPool {
+		t.Fatalf("expected thin pool, got %s", pool.Attr)
+	}
+
+	if err := client.LVConvert(ctx, VolumeGroupName("vg"), LogicalVolumeName("origin"), MergeSnapshot(true)); err == nil {
+		t.Fatal("expected error for merging a volume that is not a snapshot")
+	}
+	if err := client.LVConvert(ctx, VolumeGroupName("vg"), LogicalVolumeName("snap"), MergeSnapshot(true)); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("origin"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if origin.Attr.VolumeType != VolumeTypeNone {
+		t.Fatalf("expected merged snapshot to be removed from origin, got %s", origin.Attr)
+	}
+}
+
+func TestClient_VGChangeExtentSize(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("16M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("odd"), MustParseSize("12M")); err != nil {
+		t.Fatal(err)
+	}
+
+	var changeErr *ExtentSizeChangeError
+	if err := client.VGChange(ctx, VolumeGroupName("vg"), PhysicalExtentSize(MustParseSize("8M"))); !errors.As(err, &changeErr) {
+		t.Fatalf("expected extent size change error, got %v", err)
+	}
+	if len(changeErr.Blocking) != 1 || changeErr.Blocking[0] != "odd segment length" {
+		t.Fatalf("expected odd to block the change, got %v", changeErr.Blocking)
+	}
+
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("odd")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGChange(ctx, VolumeGroupName("vg"), PhysicalExtentSize(MustParseSize("8M"))); err != nil {
+		t.Fatal(err)
+	}
+	vg, err := client.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), UnitMiB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.ExtentCount != 12 || vg.FreeCount != 10 || lv.Size != NewSize(16, UnitMiB) {
+		t.Fatalf("unexpected accounting after extent size change: %d extents, %d free, lv of %s", vg.ExtentCount, vg.FreeCount, lv.Size)
+	}
+}
+
+func TestClient_LVsScope(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb", "/dev/sdc")
+
+	for vg, device := range map[VolumeGroupName]string{"vg1": "/dev/sdb", "vg2": "/dev/sdc"} {
+		if err := client.VGCreate(ctx, vg, PhysicalVolumesFrom(device)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, vg := range []VolumeGroupName{"vg1", "vg2"} {
+		if err := client.LVCreate(ctx, vg, LogicalVolumeName("tagged"), MustParseSize("8M"), Tags{"managed"}); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.LVCreate(ctx, vg, LogicalVolumeName("untagged"), MustParseSize("8M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	names := func(lvs []*LogicalVolume) []string {
+		var names []string
+		for _, lv := range lvs {
+			names = append(names, lv.FullName)
+		}
+		return names
+	}
+
+	// positional names and tags are a union like in lvm
+	lvs, err := client.LVs(ctx, VolumeGroupNames{"vg1"}, Tags{"managed"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(lvs); !slices.Equal(got, []string{"vg1/tagged", "vg1/untagged", "vg2/tagged"}) {
+		t.Fatalf("unexpected logical volumes %v", got)
+	}
+
+	// the tags of a select are applied within the positional scope
+	lvs, err = client.LVs(ctx, VolumeGroupNames{"vg1"}, LVTags.ContainsAny("managed", "other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := names(lvs); !slices.Equal(got, []string{"vg1/tagged"}) {
+		t.Fatalf("unexpected logical volumes %v", got)
+	}
+
+	if _, err := client.LVs(ctx, LVName.Eq("tagged")); !errors.Is(err, fake.ErrUnsupported) {
+		t.Fatalf("expected other selects to be unsupported, got %v", err)
+	}
+}
+
+func TestClient_VGSplit(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb", "/dev/sdc", "/dev/sdd")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc", "/dev/sdd")); err != nil {
+		t.Fatal(err)
+	}
+	// extents are allocated in order: a fills /dev/sdb, lv and spanned share /dev/sdc and spanned continues on /dev/sdd
+	for _, lv := range []struct {
+		name LogicalVolumeName
+		size string
+	}{{"a", "100M"}, {"lv", "8M"}, {"spanned", "100M"}} {
+		if err := client.LVCreate(ctx, VolumeGroupName("vg"), lv.name, MustParseSize(lv.size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.VGSplit(ctx, VolumeGroupName("vg"), VolumeGroupName("new"), PhysicalVolumeName("/dev/sdc")); err == nil {
+		t.Fatal("expected error when splitting a logical volume between two volume groups")
+	}
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("spanned")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGSplit(ctx, VolumeGroupName("vg"), VolumeGroupName("new"), LogicalVolumeName("lv")); err == nil {
+		t.Fatal("expected error when splitting an active logical volume")
+	}
+	if err := client.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGSplit(ctx, VolumeGroupName("vg"), VolumeGroupName("new"), LogicalVolumeName("lv")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.LV(ctx, VolumeGroupName("new"), LogicalVolumeName("lv")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv")); !IsNotFound(err) {
+		t.Fatalf("expected lv to be gone from vg, got %v", err)
+	}
+	pvs, err := client.PVs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pv := range pvs {
+		if expected := pv.Name == "/dev/sdc"; expected != (pv.VGName == "new") {
+			t.Fatalf("unexpected volume group %s of %s", pv.VGName, pv.Name)
+		}
+	}
+	vg, err := client.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.PvCount != 2 || vg.LvCount != 1 {
+		t.Fatalf("expected 2 physical and 1 logical volume left in vg, got %d and %d", vg.PvCount, vg.LvCount)
+	}
+}
+
+func TestClient_VGMerge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb", "/dev/sdc")
+
+	for vg, pv := range map[VolumeGroupName]string{"src": "/dev/sdb", "dst": "/dev/sdc"} {
+		if err := client.VGCreate(ctx, vg, PhysicalVolumesFrom(pv)); err != nil {
+			t.Fatal(err)
+		}
+		if err := client.LVCreate(ctx, vg, LogicalVolumeName(vg+"-lv"), MustParseSize("8M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := client.VGMerge(ctx, VolumeGroupName("src"), VolumeGroupName("dst")); err == nil {
+		t.Fatal("expected error when merging a volume group with active logical volumes")
+	}
+	if err := client.LVChange(ctx, VolumeGroupName("src"), LogicalVolumeName("src-lv"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGMerge(ctx, VolumeGroupName("src"), VolumeGroupName("dst"), Test(true)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.VG(ctx, VolumeGroupName("src")); err != nil {
+		t.Fatalf("expected src to be kept in test mode, got %v", err)
+	}
+	if err := client.VGMerge(ctx, VolumeGroupName("src"), VolumeGroupName("dst")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.VG(ctx, VolumeGroupName("src")); !IsNotFound(err) {
+		t.Fatalf("expected src to be removed, got %v", err)
+	}
+	vg, err := client.VG(ctx, VolumeGroupName("dst"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.PvCount != 2 || vg.LvCount != 2 {
+		t.Fatalf("expected 2 physical and 2 logical volumes in dst, got %d and %d", vg.PvCount, vg.LvCount)
+	}
+}
+
+func TestClient_VGCfgRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("8M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCfgBackup(ctx, VolumeGroupName("vg")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVRemove(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool")); err != nil {
+		t.Fatal(err)
+	}
+
+	backups, err := ListMetadataBackups(ctx, client, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 1 || backups[0].File != "/etc/lvm/backup/vg" || backups[0].VolumeGroupName != "vg" {
+		t.Fatalf("expected the backup of vg, got %+v", backups)
+	}
+
+	if err := client.VGCfgRestore(ctx, VolumeGroupName("vg")); err == nil {
+		t.Fatal("expected error when restoring thin pools without force")
+	}
+	if err := client.VGCfgRestore(ctx, VolumeGroupName("vg"), BackupFile("/tmp/missing")); err == nil {
+		t.Fatal("expected error for a missing backup file")
+	}
+	if err := client.VGCfgRestore(ctx, VolumeGroupName("vg"), Force(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Attr.State == StateActive {
+		t.Fatal("expected restored logical volume to be inactive")
+	}
+}
+
+func TestClient_FullReport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	client := newClient(t, "/dev/sdb", "/dev/sdc", "/dev/sdd")
+
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdd"}); err != nil {
+		t.Fatal(err)
+	}
+	// 30 extents span both physical volumes
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("120M")); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := client.FullReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanPhysicalVolumes) != 1 || report.OrphanPhysicalVolumes[0].Name != "/dev/sdd" {
+		t.Fatalf("expected /dev/sdd as orphan, got %v", report.OrphanPhysicalVolumes)
+	}
+	vg := report.VolumeGroup("vg")
+	if vg == nil || len(vg.PhysicalVolumes) != 2 {
+		t.Fatalf("expected vg with two physical volumes, got %v", vg)
+	}
+	lv := vg.LogicalVolume("lv")
+	if lv == nil || len(lv.Segments) != 2 {
+		t.Fatalf("expected lv with two segments, got %v", lv)
+	}
+	if lv.Segments[0].Start != 0 || lv.Segments[0].Size != 25 || lv.Segments[1].Start != 25 || lv.Segments[1].Size != 5 {
+		t.Fatalf("unexpected segments %+v %+v", lv.Segments[0], lv.Segments[1])
+	}
+	if pv := vg.PhysicalVolume("/dev/sdc"); pv == nil || len(pv.Segments) != 2 || !pv.Segments[1].IsFree() {
+		t.Fatalf("expected an allocated and a free segment on /dev/sdc, got %v", pv)
+	}
+
+	segments, err := client.LVSegments(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 || !slices.Equal(segments[1].PhysicalVolumes(), []PhysicalVolumeName{"/dev/sdc"}) {
+		t.Fatalf("expected the second segment on /dev/sdc, got %+v", segments)
+	}
+
+	if report, err = client.FullReport(ctx, VolumeGroupNames{"other"}); err != nil {
+		t.Fatal(err)
+	} else if len(report.VolumeGroups) != 0 || len(report.OrphanPhysicalVolumes) != 0 {
+		t.Fatalf("expected empty report, got %+v", report)
+	}
+}
@@ -0,0 +1,121 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azalio/lvm2go"
+)
+
+// The fake keeps a single devices file in which physical volumes are registered by their device name
+// when they are created. The DevicesFile options are ignored.
+
+func pvid(uuid string) string {
+	return strings.ReplaceAll(uuid, "-", "")
+}
+
+func (c *Client) addDevicesFileEntry(name lvm2go.PhysicalVolumeName) {
+	c.removeDevicesFileEntry(name)
+	entry := lvm2go.DeviceListEntry{
+		IDType:  lvm2go.DeviceIDTypeDevname,
+		IDName:  string(name),
+		DevName: string(name),
+	}
+	if pv, ok := c.pvs[name]; ok {
+		entry.PVID = pvid(pv.uuid)
+	}
+	c.devicesFile = append(c.devicesFile, entry)
+}
+
+func (c *Client) removeDevicesFileEntry(name lvm2go.PhysicalVolumeName) {
+	c.devicesFile = slices.DeleteFunc(c.devicesFile, func(entry lvm2go.DeviceListEntry) bool {
+		return entry.DevName == string(name)
+	})
+}
+
+func (c *Client) DevList(context.Context, ...lvm2go.DevListOption) ([]lvm2go.DeviceListEntry, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.devicesFile), nil
+}
+
+// DevCheck reports entries of the devices file whose device no longer exists.
+func (c *Client) DevCheck(context.Context, ...lvm2go.DevCheckOption) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.devicesFile {
+		if _, ok := c.devices[lvm2go.PhysicalVolumeName(entry.DevName)]; !ok {
+			return lvmError("Device not found for %s.", entry.IDName)
+		}
+	}
+	return nil
+}
+
+// DevUpdate removes entries of the devices file whose device no longer exists.
+func (c *Client) DevUpdate(context.Context, ...lvm2go.DevUpdateOption) (*lvm2go.DevUpdateResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result := &lvm2go.DevUpdateResult{}
+	c.devicesFile = slices.DeleteFunc(c.devicesFile, func(entry lvm2go.DeviceListEntry) bool {
+		if _, ok := c.devices[lvm2go.PhysicalVolumeName(entry.DevName)]; ok {
+			return false
+		}
+		result.Removed = append(result.Removed, lvm2go.DevUpdateChange{PVID: entry.PVID, DevName: entry.DevName})
+		return true
+	})
+	return result, nil
+}
+
+func (c *Client) DevModify(_ context.Context, opts ...lvm2go.DevModifyOption) error {
+	options := lvm2go.DevModifyOptions{}
+	for _, opt := range opts {
+		opt.ApplyToDevModifyOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	device := options.ModifyDevice.Device
+	switch options.ModifyDeviceType {
+	case lvm2go.AddDev:
+		if _, ok := c.devices[lvm2go.PhysicalVolumeName(device)]; !ok {
+			return lvmError("Device %s not found.", device)
+		}
+		c.addDevicesFileEntry(lvm2go.PhysicalVolumeName(device))
+	case lvm2go.DelDev:
+		c.removeDevicesFileEntry(lvm2go.PhysicalVolumeName(device))
+	case lvm2go.AddDevByPVID:
+		for _, pv := range c.pvs {
+			if pvid(pv.uuid) == device {
+				c.addDevicesFileEntry(pv.name)
+				return nil
+			}
+		}
+		return lvmError("PVID %s not found on any devices.", device)
+	case lvm2go.DelDevByPVID:
+		c.devicesFile = slices.DeleteFunc(c.devicesFile, func(entry lvm2go.DeviceListEntry) bool {
+			return entry.PVID == device
+		})
+	default:
+		return fmt.Errorf("unknown device modification %q", options.ModifyDeviceType)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+
+	"github.com/azalio/lvm2go"
+)
+
+// FullReport reports the fake state like PVs, VGs, LVs, PVSegments and LVSegments while holding the lock once,
+// so the report is consistent.
+func (c *Client) FullReport(_ context.Context, opts ...lvm2go.FullReportOption) (*lvm2go.FullReport, error) {
+	options := lvm2go.FullReportOptions{}
+	for _, opt := range opts {
+		opt.ApplyToFullReportOptions(&options)
+	}
+	if _, err := lvm2go.FullReportOptionsList(opts).AsArgs(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var vgs []*lvm2go.VolumeGroup
+	var lvs []*lvm2go.LogicalVolume
+	for _, name := range c.sortedVolumeGroups() {
+		vg := c.vgs[name]
+		if !options.InScope(vg.name) {
+			continue
+		}
+		vgs = append(vgs, c.reportVolumeGroup(vg, options.Unit))
+		for _, lv := range vg.lvs {
+			lvs = append(lvs, c.reportLogicalVolume(vg, lv, options.Unit))
+		}
+	}
+
+	var pvs []*lvm2go.PhysicalVolume
+	var pvSegments []*lvm2go.PhysicalVolumeSegment
+	var lvSegments []*lvm2go.LogicalVolumeSegment
+	for _, name := range c.sortedPhysicalVolumes() {
+		pv := c.pvs[name]
+		vg, ok := c.vgs[pv.vg]
+		if !ok {
+			// orphans are only reported without volume group names, as by lvm
+			if options.VolumeGroupName == "" && len(options.VolumeGroupNames) == 0 {
+				pvs = append(pvs, c.reportPhysicalVolume(pv, options.Unit))
+			}
+			continue
+		}
+		if !options.InScope(vg.name) {
+			continue
+		}
+		pvs = append(pvs, c.reportPhysicalVolume(pv, options.Unit))
+		pvSegments = append(pvSegments, c.physicalVolumeSegments(vg, pv)...)
+		lvSegments = append(lvSegments, c.logicalVolumeSegments(vg, pv)...)
+	}
+
+	return lvm2go.NewFullReport(vgs, pvs, lvs, pvSegments, lvSegments), nil
+}
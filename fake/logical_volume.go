@@ -0,0 +1,818 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/azalio/lvm2go"
+)
+
+func (c *Client) LV(ctx context.Context, opts ...lvm2go.LVsOption) (*lvm2go.LogicalVolume, error) {
+	options := lvm2go.LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, lvm2go.ErrVolumeGroupNameRequired
+	}
+	if options.LogicalVolumeName == "" {
+		return nil, lvm2go.ErrLogicalVolumeNameRequired
+	}
+	lvs, err := c.LVs(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(lvs) == 0 {
+		return nil, lvm2go.ErrLogicalVolumeNotFound
+	}
+	return lvs[0], nil
+}
+
+func (c *Client) LVs(_ context.Context, opts ...lvm2go.LVsOption) ([]*lvm2go.LogicalVolume, error) {
+	options := lvm2go.LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	selected, err := selectedTags(lvm2go.LVTags, options.Select)
+	if err != nil {
+		return nil, err
+	}
+	if err := errSortUnsupported(options.SortFields); err != nil {
+		return nil, err
+	}
+	if err := errRetainRawJSONUnsupported(options.RetainRawJSON); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var lvs []*lvm2go.LogicalVolume
+	for _, name := range c.sortedVolumeGroups() {
+		vg := c.vgs[name]
+		for _, lv := range vg.lvs {
+			if !lvInScope(&options, selected, vg, lv) {
+				continue
+			}
+			if options.ByUUID != "" && lv.uuid != string(options.ByUUID) {
+				continue
+			}
+			lvs = append(lvs, c.reportLogicalVolume(vg, lv, options.Unit))
+		}
+	}
+	return lvs, nil
+}
+
+// lvInScope reports whether the logical volume is named or tagged by the options and carries one of the selected tags.
+func lvInScope(options *lvm2go.LVsOptions, selected lvm2go.Tags, vg *volumeGroup, lv *logicalVolume) bool {
+	named := options.VolumeGroupName != "" || len(options.VolumeGroupNames) > 0 || len(options.FQLogicalVolumeNames) > 0
+	return inPositionalScope(named, options.InScope(vg.name, lv.name), lv.tags, options.Tags) &&
+		matchesTags(lv.tags, selected)
+}
+
+// LVSegments reports every physical volume segment of a logical volume as a linear segment, see PVSegments.
+func (c *Client) LVSegments(_ context.Context, opts ...lvm2go.LVsOption) ([]*lvm2go.LogicalVolumeSegment, error) {
+	options := lvm2go.LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	selected, err := selectedTags(lvm2go.LVTags, options.Select)
+	if err != nil {
+		return nil, err
+	}
+	if err := errSortUnsupported(options.SortFields); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var segments []*lvm2go.LogicalVolumeSegment
+	for _, name := range c.sortedVolumeGroups() {
+		vg := c.vgs[name]
+		var lvSegments []*lvm2go.LogicalVolumeSegment
+		for _, pv := range c.sortedPhysicalVolumes() {
+			if c.pvs[pv].vg != vg.name {
+				continue
+			}
+			lvSegments = append(lvSegments, c.logicalVolumeSegments(vg, c.pvs[pv])...)
+		}
+		for _, lv := range vg.lvs {
+			if !lvInScope(&options, selected, vg, lv) {
+				continue
+			}
+			var ofLV []*lvm2go.LogicalVolumeSegment
+			for _, seg := range lvSegments {
+				if seg.LogicalVolumeUUID == lv.uuid {
+					ofLV = append(ofLV, seg)
+				}
+			}
+			slices.SortFunc(ofLV, func(a, b *lvm2go.LogicalVolumeSegment) int {
+				return cmp.Compare(a.Start, b.Start)
+			})
+			segments = append(segments, ofLV...)
+		}
+	}
+	return segments, nil
+}
+
+// logicalVolumeSegments reports the segments of the logical volumes of the volume group on the physical volume.
+func (c *Client) logicalVolumeSegments(vg *volumeGroup, pv *physicalVolume) []*lvm2go.LogicalVolumeSegment {
+	var segments []*lvm2go.LogicalVolumeSegment
+	for _, seg := range c.physicalVolumeSegments(vg, pv) {
+		if seg.IsFree() {
+			continue
+		}
+		segments = append(segments, &lvm2go.LogicalVolumeSegment{
+			LogicalVolumeName: seg.LogicalVolumeName,
+			LogicalVolumeUUID: seg.LogicalVolumeUUID,
+			VolumeGroupName:   vg.name,
+			Start:             seg.LogicalExtentStart,
+			Size:              seg.Size,
+			SegmentType:       seg.SegmentType,
+			Stripes:           1,
+			PhysicalExtentRanges: []lvm2go.PhysicalExtentRange{{
+				PhysicalVolumeName: pv.name,
+				Start:              seg.Start,
+				End:                seg.End(),
+			}},
+			Devices: []string{fmt.Sprintf("%s(%d)", pv.name, seg.Start)},
+		})
+	}
+	return segments
+}
+
+func (c *Client) reportLogicalVolume(vg *volumeGroup, lv *logicalVolume, unit lvm2go.Unit) *lvm2go.LogicalVolume {
+	permission, minor, state, target, zero, skip := 'w', '-', lvm2go.StateNone, '-', '-', '-'
+	if lv.readOnly {
+		permission = 'r'
+	}
+	if lv.persistent != nil {
+		minor = 'm'
+	}
+	if lv.active {
+		state = lvm2go.StateActive
+	}
+	switch lv.volumeType {
+	case lvm2go.VolumeTypeThinPool, lvm2go.VolumeTypeThinVolume:
+		target, zero = 't', 'z'
+		if lv.noZero {
+			zero = '-'
+		}
+	case lvm2go.VolumeTypeSnapshot, lvm2go.VolumeTypeOrigin:
+		target = 's'
+	}
+	if lv.skip {
+		skip = 'k'
+	}
+	attr, _ := lvm2go.ParseLVAttributes(fmt.Sprintf("%c%ci%c%c-%c%c-%c", lv.volumeType, permission, minor, state, target, zero, skip))
+
+	report := &lvm2go.LogicalVolume{
+		UUID:              lv.uuid,
+		Name:              lv.name,
+		FullName:          fmt.Sprintf("%s/%s", vg.name, lv.name),
+		Path:              (&lvm2go.FQLogicalVolumeName{VolumeGroupName: vg.name, LogicalVolumeName: lv.name}).DevicePath(),
+		Tags:              slices.Clone(lv.tags),
+		Attr:              attr,
+		Size:              sizeIn(lv.size(vg), unit),
+		MetadataSize:      sizeIn(lv.metadataSize, unit),
+		Origin:            string(lv.origin),
+		PoolLogicalVolume: string(lv.pool),
+		VolumeGroupName:   vg.name,
+		CreationTime:      lv.created,
+	}
+	if lv.volumeType == lvm2go.VolumeTypeThinPool {
+		report.WhenFull = lvm2go.WhenFullQueue
+		if lv.errorWhenFull {
+			report.WhenFull = lvm2go.WhenFullError
+		}
+	}
+	if lv.persistent != nil && lv.active {
+		report.Major, report.Minor = lv.persistent.Major, lv.persistent.Minor
+	}
+	if !lv.noAutoActivation {
+		report.AutoActivation = lvm2go.AutoActivationFromReportEnabled
+	}
+	if origin := vg.lv(lv.origin); origin != nil {
+		report.OriginSize = sizeIn(origin.size(vg), unit)
+	}
+	return report
+}
+
+func (lv *logicalVolume) segmentType() string {
+	switch lv.volumeType {
+	case lvm2go.VolumeTypeThinPool:
+		return "thin-pool"
+	case lvm2go.VolumeTypeThinVolume:
+		return "thin"
+	case lvm2go.VolumeTypeSnapshot:
+		return "snapshot"
+	default:
+		return "linear"
+	}
+}
+
+// nextName returns the name lvm2 generates for logical volumes created without a name.
+func (vg *volumeGroup) nextName() lvm2go.LogicalVolumeName {
+	for i := 0; ; i++ {
+		if name := lvm2go.LogicalVolumeName(fmt.Sprintf("lvol%d", i)); vg.lv(name) == nil {
+			return name
+		}
+	}
+}
+
+func (c *Client) LVCreate(_ context.Context, opts ...lvm2go.LVCreateOption) error {
+	options := lvm2go.LVCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVCreateOptions(&options)
+	}
+	switch options.Type {
+	case "", lvm2go.TypeLinear, lvm2go.TypeStriped, lvm2go.TypeThin:
+	case lvm2go.TypeThinPool:
+		options.Thin = true
+	default:
+		return fmt.Errorf("type %s: %w", options.Type, ErrUnsupported)
+	}
+	if options.Mirrors > 0 {
+		return fmt.Errorf("mirrors: %w", ErrUnsupported)
+	}
+
+	vgName := options.VolumeGroupName
+	switch {
+	case options.ThinPool != nil:
+		vgName = options.ThinPool.VolumeGroupName
+	case options.SnapshotOf != nil:
+		vgName = options.SnapshotOf.VolumeGroupName
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, err := c.volumeGroup(vgName)
+	if err != nil {
+		return err
+	}
+	if options.Size, err = align(options.SizeAlignment, options.Size, vg.extentSize); err != nil {
+		return err
+	}
+	virtualSize, err := align(options.SizeAlignment, lvm2go.Size(options.VirtualSize), vg.extentSize)
+	if err != nil {
+		return err
+	}
+	options.VirtualSize = lvm2go.VirtualSize(virtualSize)
+	exclude, err := vg.unselected(options.PhysicalExtentSelectors)
+	if err != nil {
+		return err
+	}
+
+	name := options.LogicalVolumeName
+	if name == "" {
+		name = vg.nextName()
+	}
+	if vg.lv(name) != nil {
+		return lvmError("Logical Volume %q already exists in volume group %q", name, vg.name)
+	}
+	if vg.maxLv > 0 && len(vg.lvs) >= vg.maxLv {
+		return lvmError("Maximum number of logical volumes (%d) reached in volume group %s", vg.maxLv, vg.name)
+	}
+
+	lv := &logicalVolume{
+		uuid:       c.nextUUID(),
+		name:       name,
+		volumeType: lvm2go.VolumeTypeNone,
+		tags:       addTags(nil, options.Tags, nil),
+		active:     options.ActivationState != lvm2go.Deactivate,
+		created:    time.Now().Truncate(time.Second),
+	}
+
+	switch {
+	case options.ThinPool != nil:
+		pool := vg.lv(options.ThinPool.LogicalVolumeName)
+		if pool == nil {
+			return errLogicalVolumeNotFound(vg.name, options.ThinPool.LogicalVolumeName)
+		}
+		if pool.volumeType != lvm2go.VolumeTypeThinPool {
+			return lvmError("Logical volume %s/%s is not a thin pool.", vg.name, pool.name)
+		}
+		extents, err := toExtents(lvm2go.Size(options.VirtualSize), vg.extentSize)
+		if err != nil {
+			return err
+		}
+		if extents == 0 {
+			return lvmError("Please specify either size or extents.")
+		}
+		lv.volumeType, lv.pool, lv.virtualSize = lvm2go.VolumeTypeThinVolume, pool.name, extents*vg.extentSize
+	case options.SnapshotOf != nil:
+		origin := vg.lv(options.SnapshotOf.LogicalVolumeName)
+		if origin == nil {
+			return errLogicalVolumeNotFound(vg.name, options.SnapshotOf.LogicalVolumeName)
+		}
+		lv.origin = origin.name
+		if origin.volumeType == lvm2go.VolumeTypeThinVolume && options.Size.Val == 0 && options.Extents.Val == 0 {
+			// thin snapshots share the thin pool of their origin and are skipped on activation
+			lv.volumeType, lv.pool, lv.virtualSize = lvm2go.VolumeTypeThinVolume, origin.pool, origin.virtualSize
+			lv.skip = true
+			break
+		}
+		extents, err := c.createExtents(vg, options, origin)
+		if err != nil {
+			return err
+		}
+		if lv.segments, err = c.allocate(vg, extents, exclude...); err != nil {
+			return err
+		}
+		lv.volumeType = lvm2go.VolumeTypeSnapshot
+		if origin.volumeType == lvm2go.VolumeTypeNone {
+			origin.volumeType = lvm2go.VolumeTypeOrigin
+		}
+	default:
+		if options.Type == lvm2go.TypeThin {
+			return lvmError("Please specify a thin pool for a thin volume.")
+		}
+		extents, err := c.createExtents(vg, options, nil)
+		if err != nil {
+			return err
+		}
+		if lv.segments, err = c.allocate(vg, extents, exclude...); err != nil {
+			return err
+		}
+		if options.Thin {
+			lv.volumeType = lvm2go.VolumeTypeThinPool
+			lv.noZero = options.Zero == lvm2go.DoNotZeroVolume
+			if lv.metadataSize, err = metadataSize(options.PoolMetadataSize); err != nil {
+				return err
+			}
+		}
+	}
+
+	if options.ActivationSkip != "" {
+		lv.skip = options.ActivationSkip == lvm2go.SetActivationSkip
+	}
+	lv.readOnly = options.Permission == lvm2go.PermissionReadOnly
+	if options.PersistentDeviceNumber != nil {
+		dev := lvm2go.DeviceNumber(*options.PersistentDeviceNumber)
+		lv.persistent = &dev
+	}
+	if lv.skip && !bool(options.IgnoreActivationSkip) {
+		lv.active = false
+	}
+
+	vg.lvs = append(vg.lvs, lv)
+	vg.seqNo++
+	return nil
+}
+
+// activate activates the logical volume unless it is flagged to be skipped during activation
+// and the flag is not ignored, in which case lvm2 silently skips it.
+func (lv *logicalVolume) activate(ignoreSkip lvm2go.IgnoreActivationSkip) {
+	if !lv.skip || bool(ignoreSkip) {
+		lv.active = true
+	}
+}
+
+// createExtents returns the number of extents requested through either the size or the extents of the options.
+func (c *Client) createExtents(vg *volumeGroup, options lvm2go.LVCreateOptions, origin *logicalVolume) (uint64, error) {
+	var extents uint64
+	var err error
+	if options.Size.Val > 0 {
+		extents, err = toExtents(options.Size, vg.extentSize)
+	} else {
+		extents, err = c.percentExtents(vg, options.Extents, origin)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if extents == 0 {
+		return 0, lvmError("Please specify either size or extents.")
+	}
+	return extents, nil
+}
+
+func (c *Client) LVRemove(_ context.Context, opts ...lvm2go.LVRemoveOption) error {
+	options := lvm2go.LVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRemoveOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// without a name, all logical volumes with one of the tags are removed
+	if options.LogicalVolumeName == "" && len(options.Tags) > 0 {
+		for _, name := range c.sortedVolumeGroups() {
+			vg := c.vgs[name]
+			if options.VolumeGroupName != "" && vg.name != options.VolumeGroupName {
+				continue
+			}
+			for _, lv := range slices.Clone(vg.lvs) {
+				if vg.lv(lv.name) != nil && matchesTags(lv.tags, options.Tags) {
+					if err := c.removeLogicalVolume(vg, lv, bool(options.Force)); err != nil {
+						return err
+					}
+				}
+			}
+		}
+		return nil
+	}
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	return c.removeLogicalVolume(vg, lv, bool(options.Force))
+}
+
+// removeLogicalVolume removes a logical volume. Dependent snapshots and thin volumes are only removed with force.
+func (c *Client) removeLogicalVolume(vg *volumeGroup, lv *logicalVolume, force bool) error {
+	dependents := vg.dependents(lv.name)
+	if len(dependents) > 0 && !force {
+		return lvmError("Logical volume %s/%s has %d dependent volume(s).", vg.name, lv.name, len(dependents))
+	}
+	for _, dependent := range dependents {
+		if err := c.removeLogicalVolume(vg, dependent, force); err != nil {
+			return err
+		}
+	}
+	vg.removeLV(lv.name)
+	if origin := vg.lv(lv.origin); origin != nil && origin.volumeType == lvm2go.VolumeTypeOrigin && len(vg.dependents(origin.name)) == 0 {
+		origin.volumeType = lvm2go.VolumeTypeNone
+	}
+	vg.seqNo++
+	return nil
+}
+
+// resizeTo resizes a logical volume to the given size in bytes, rounded up to full extents.
+func (c *Client) resizeTo(vg *volumeGroup, lv *logicalVolume, bytes float64, exclude ...lvm2go.PhysicalVolumeName) error {
+	if bytes <= 0 {
+		return lvmError("Size of logical volume %s/%s would be zero or negative.", vg.name, lv.name)
+	}
+	extents, err := toExtents(lvm2go.NewSize(bytes, lvm2go.UnitBytes), vg.extentSize)
+	if err != nil {
+		return err
+	}
+	if lv.volumeType == lvm2go.VolumeTypeThinVolume {
+		lv.virtualSize = extents * vg.extentSize
+		vg.seqNo++
+		return nil
+	}
+	if lv.volumeType == lvm2go.VolumeTypeThinPool && extents < lv.extents() {
+		return lvmError("Thin pool volumes %s/%s cannot be reduced in size yet.", vg.name, lv.name)
+	}
+	if err := c.resize(vg, lv, extents, exclude...); err != nil {
+		return err
+	}
+	vg.seqNo++
+	return nil
+}
+
+func prefixed(current float64, size lvm2go.PrefixedSize) (float64, error) {
+	bytes, err := size.Size.ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return 0, err
+	}
+	switch size.SizePrefix {
+	case lvm2go.SizePrefixPlus:
+		return current + bytes.Val, nil
+	case lvm2go.SizePrefixMinus:
+		return current - bytes.Val, nil
+	default:
+		return bytes.Val, nil
+	}
+}
+
+func (c *Client) LVResize(_ context.Context, opts ...lvm2go.LVResizeOption) error {
+	options := lvm2go.LVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVResizeOptions(&options)
+	}
+	if _, err := lvm2go.LVResizeOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+	if options.SizeDelta.Val > 0 {
+		options.PrefixedSize = lvm2go.PrefixedSize(options.SizeDelta)
+	}
+	if options.UsePolicies {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	size := options.PrefixedSize
+	if options.PrefixedExtents.Val > 0 {
+		if size, err = c.prefixedExtentsSize(vg, lv, options.PrefixedExtents); err != nil {
+			return err
+		}
+	}
+	target, err := prefixed(float64(lv.size(vg)), size)
+	if err != nil {
+		return err
+	}
+	return c.resizeTo(vg, lv, target)
+}
+
+// prefixedExtentsSize converts the extents, which may be a percentage, to a size with the same prefix.
+func (c *Client) prefixedExtentsSize(vg *volumeGroup, lv *logicalVolume, opt lvm2go.PrefixedExtents) (lvm2go.PrefixedSize, error) {
+	extents, err := c.percentExtents(vg, opt.Extents, vg.lv(lv.origin))
+	if err != nil {
+		return lvm2go.PrefixedSize{}, err
+	}
+	return lvm2go.NewPrefixedSize(opt.SizePrefix, lvm2go.NewSize(float64(extents*vg.extentSize), lvm2go.UnitBytes)), nil
+}
+
+func (c *Client) LVExtend(_ context.Context, opts ...lvm2go.LVExtendOption) error {
+	options := lvm2go.LVExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVExtendOptions(&options)
+	}
+	// the autoextend policies are disabled by the default threshold of lvm2, so nothing is extended
+	if options.UsePolicies {
+		_, err := lvm2go.LVExtendOptionsList(opts).AsArgs()
+		return err
+	}
+	if options.SizeDelta.Val > 0 {
+		if _, err := lvm2go.LVExtendOptionsList(opts).AsArgs(); err != nil {
+			return err
+		}
+		options.PrefixedSize = lvm2go.PrefixedSize(options.SizeDelta)
+	}
+	if options.PrefixedSize.Val == 0 && options.PrefixedExtents.Val == 0 && options.PoolMetadataPrefixedSize.Val == 0 {
+		return fmt.Errorf("size, extents or pool metadata size must be specified")
+	}
+	for _, prefix := range []lvm2go.SizePrefix{
+		options.PrefixedSize.SizePrefix,
+		options.PrefixedExtents.SizePrefix,
+		options.PoolMetadataPrefixedSize.SizePrefix,
+	} {
+		if prefix == lvm2go.SizePrefixMinus {
+			return fmt.Errorf("size prefix must be positive")
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if options.PrefixedSize.Size, err = align(options.SizeAlignment, options.PrefixedSize.Size, vg.extentSize); err != nil {
+		return err
+	}
+	exclude, err := vg.unselected(options.PhysicalExtentSelectors)
+	if err != nil {
+		return err
+	}
+
+	if options.PoolMetadataPrefixedSize.Val > 0 {
+		if lv.volumeType != lvm2go.VolumeTypeThinPool {
+			return lvmError("Logical volume %s/%s is not a thin pool.", vg.name, lv.name)
+		}
+		metadata, err := prefixed(float64(lv.metadataSize), lvm2go.PrefixedSize(options.PoolMetadataPrefixedSize))
+		if err != nil {
+			return err
+		}
+		lv.metadataSize = uint64(metadata)
+		vg.seqNo++
+	}
+
+	current := float64(lv.size(vg))
+	target := current
+	switch {
+	case options.PrefixedSize.Val > 0:
+		if target, err = prefixed(current, options.PrefixedSize); err != nil {
+			return err
+		}
+	case options.PrefixedExtents.Val > 0:
+		size, err := c.prefixedExtentsSize(vg, lv, options.PrefixedExtents)
+		if err != nil {
+			return err
+		}
+		if target, err = prefixed(current, size); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+	if target <= current {
+		return lvmError("New size given (%d extents) not larger than existing size (%d extents)",
+			uint64(target)/vg.extentSize, uint64(current)/vg.extentSize)
+	}
+	return c.resizeTo(vg, lv, target, exclude...)
+}
+
+func (c *Client) LVReduce(_ context.Context, opts ...lvm2go.LVReduceOption) error {
+	options := lvm2go.LVReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVReduceOptions(&options)
+	}
+	if options.SizeDelta.Val > 0 {
+		if _, err := lvm2go.LVReduceOptionsList(opts).AsArgs(); err != nil {
+			return err
+		}
+		options.PrefixedSize = lvm2go.PrefixedSize(options.SizeDelta)
+	}
+	if options.PrefixedSize.Val <= 0 {
+		return fmt.Errorf("size must be specified")
+	}
+	if options.PrefixedSize.SizePrefix == lvm2go.SizePrefixPlus {
+		return fmt.Errorf("size prefix must be negative")
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	current := float64(lv.size(vg))
+	target, err := prefixed(current, options.PrefixedSize)
+	if err != nil {
+		return err
+	}
+	if target > current {
+		return lvmError("New size given (%.0f bytes) not less than existing size (%.0f bytes)", target, current)
+	}
+	return c.resizeTo(vg, lv, target)
+}
+
+func (c *Client) LVRename(_ context.Context, opts ...lvm2go.LVRenameOption) error {
+	options := lvm2go.LVRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRenameOptions(&options)
+	}
+	if options.New == "" {
+		return fmt.Errorf("new is empty: %w", lvm2go.ErrLogicalVolumeNameRequired)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.Old)
+	if err != nil {
+		return err
+	}
+	if vg.lv(options.New) != nil {
+		return lvmError("Logical Volume %q already exists in volume group %q", options.New, vg.name)
+	}
+	for _, dependent := range vg.dependents(lv.name) {
+		if dependent.origin == lv.name {
+			dependent.origin = options.New
+		}
+		if dependent.pool == lv.name {
+			dependent.pool = options.New
+		}
+	}
+	lv.name = options.New
+	vg.seqNo++
+	return nil
+}
+
+// LVChange changes the activation, activation skip flag, permission, persistent device number and tags of a logical volume
+// and the zeroing and behavior when full of thin pools. All other changes are accepted but not modeled.
+func (c *Client) LVChange(_ context.Context, opts ...lvm2go.LVChangeOption) error {
+	options := lvm2go.LVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVChangeOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return err
+	}
+	if _, err := lvm2go.LVChangeOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if options.ActivationSkip != "" {
+		lv.skip = options.ActivationSkip == lvm2go.SetActivationSkip
+	}
+	if options.Zero != "" || options.ErrorWhenFull != nil || options.Discards != "" {
+		if lv.volumeType != lvm2go.VolumeTypeThinPool {
+			return lvmError("Command on LV %s/%s uses options that require LV types thinpool.", vg.name, lv.name)
+		}
+		if options.Zero != "" {
+			lv.noZero = options.Zero == lvm2go.DoNotZeroVolume
+		}
+		if options.ErrorWhenFull != nil {
+			lv.errorWhenFull = bool(*options.ErrorWhenFull)
+		}
+	}
+	// the fake client has no raid logical volumes that could be synchronized
+	if options.MinRecoveryRate.Val > 0 || options.MaxRecoveryRate.Val > 0 || len(options.WriteMostly) > 0 || options.WriteBehind != 0 ||
+		len(options.Rebuild) > 0 || bool(options.Resync) {
+		return lvmError("Command on LV %s/%s uses options that require LV types raid.", vg.name, lv.name)
+	}
+	if options.Permission != "" {
+		lv.readOnly = options.Permission == lvm2go.PermissionReadOnly
+	}
+	if options.PersistentDeviceNumber != nil {
+		dev := lvm2go.DeviceNumber(*options.PersistentDeviceNumber)
+		lv.persistent = &dev
+	} else if options.RemovePersistentDeviceNumber {
+		lv.persistent = nil
+	}
+	switch options.ActivationState {
+	case lvm2go.Activate, lvm2go.AutoActivate, lvm2go.ActivateExclusive, lvm2go.ActivateShared:
+		lv.activate(options.IgnoreActivationSkip)
+	case lvm2go.Deactivate:
+		lv.active = false
+	}
+	if options.AutoActivation != "" {
+		lv.noAutoActivation = options.AutoActivation == lvm2go.SetNoAutoActivate
+	}
+	lv.tags = addTags(lv.tags, options.Tags, options.DelTags)
+	vg.seqNo++
+	return nil
+}
+
+// LVScan only validates the options, as the fake client has no devices to scan.
+func (c *Client) LVScan(_ context.Context, opts ...lvm2go.LVScanOption) error {
+	_, err := lvm2go.LVScanOptionsList(opts).AsArgs()
+	return err
+}
+
+// LVConvert converts linear logical volumes to thin pools and merges snapshots into their origin.
+// Other conversions return ErrUnsupported.
+func (c *Client) LVConvert(_ context.Context, opts ...lvm2go.LVConvertOption) error {
+	options := lvm2go.LVConvertOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVConvertOptions(&options)
+	}
+	if _, err := lvm2go.LVConvertOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, lv, err := c.logicalVolume(options.VolumeGroupName, options.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case bool(options.MergeSnapshot):
+		if lv.origin == "" {
+			return lvmError("Command on LV %s/%s uses options that require LV types snapshot or thin.", vg.name, lv.name)
+		}
+		// the data of the origin is not modeled, so merging only removes the snapshot
+		return c.removeLogicalVolume(vg, lv, false)
+	case options.Type == lvm2go.TypeThinPool && options.PoolMetadata == nil:
+		if lv.volumeType != lvm2go.VolumeTypeNone {
+			return lvmError("Command on LV %s/%s does not accept LV type %s.", vg.name, lv.name, lv.segmentType())
+		}
+		lv.volumeType = lvm2go.VolumeTypeThinPool
+		if lv.metadataSize, err = metadataSize(options.PoolMetadataSize); err != nil {
+			return err
+		}
+		vg.seqNo++
+		return nil
+	default:
+		return fmt.Errorf("LVConvert: %w", ErrUnsupported)
+	}
+}
+
+// metadataSize returns the metadata size in bytes of a new thin pool, DefaultThinPoolMetadataSize if size is not set.
+func metadataSize(size lvm2go.PoolMetadataSize) (uint64, error) {
+	if size.Val <= 0 {
+		return DefaultThinPoolMetadataSize, nil
+	}
+	bytes, err := lvm2go.Size(size).ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(bytes.Val), nil
+}
@@ -0,0 +1,208 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/azalio/lvm2go"
+)
+
+// DefaultBackupDirectory is the directory of metadata backups taken by VGCfgBackup without BackupFile.
+const DefaultBackupDirectory = "/etc/lvm/backup"
+
+// metadataBackup is a copy of a volume group as taken by VGCfgBackup.
+type metadataBackup struct {
+	file string
+	vg   *volumeGroup
+	// pvUUIDs are the UUIDs of the physical volumes of the volume group, which VGCfgRestore and
+	// PVCreate with RestoreFile match the physical volumes by.
+	pvUUIDs     map[lvm2go.PhysicalVolumeName]string
+	description string
+	time        time.Time
+}
+
+// clone returns a deep copy of the volume group.
+func (vg *volumeGroup) clone() *volumeGroup {
+	clone := *vg
+	clone.pvs = slices.Clone(vg.pvs)
+	clone.tags = slices.Clone(vg.tags)
+	clone.lvs = make([]*logicalVolume, len(vg.lvs))
+	for i, lv := range vg.lvs {
+		lvClone := *lv
+		lvClone.segments = slices.Clone(lv.segments)
+		lvClone.tags = slices.Clone(lv.tags)
+		clone.lvs[i] = &lvClone
+	}
+	return &clone
+}
+
+func backupFile(file lvm2go.BackupFile, vg lvm2go.VolumeGroupName) string {
+	if file == "" {
+		return fmt.Sprintf("%s/%s", DefaultBackupDirectory, vg)
+	}
+	if strings.Contains(string(file), "%s") {
+		return strings.ReplaceAll(string(file), "%s", string(vg))
+	}
+	return string(file)
+}
+
+// VGCfgBackup keeps a copy of the volume groups in memory, which can be restored with VGCfgRestore.
+// No files are written.
+func (c *Client) VGCfgBackup(_ context.Context, opts ...lvm2go.VGCfgBackupOption) error {
+	options := lvm2go.VGCfgBackupOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCfgBackupOptions(&options)
+	}
+	if _, err := lvm2go.VGCfgBackupOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vgs, err := c.selectVolumeGroups(options.VolumeGroupName, options.VolumeGroupName == "")
+	if err != nil {
+		return err
+	}
+	if len(vgs) > 1 && options.BackupFile != "" && !strings.Contains(string(options.BackupFile), "%s") {
+		return lvmError("VGs must be backed up into different files. Use %%s in filename for VG name.")
+	}
+	for _, vg := range vgs {
+		file := backupFile(options.BackupFile, vg.name)
+		c.backups = slices.DeleteFunc(c.backups, func(backup metadataBackup) bool {
+			return backup.file == file
+		})
+		pvUUIDs := make(map[lvm2go.PhysicalVolumeName]string, len(vg.pvs))
+		for _, name := range vg.pvs {
+			pvUUIDs[name] = c.pvs[name].uuid
+		}
+		c.backups = append(c.backups, metadataBackup{
+			file:        file,
+			vg:          vg.clone(),
+			pvUUIDs:     pvUUIDs,
+			description: "Created *after* executing 'vgcfgbackup'",
+			time:        time.Now().Truncate(time.Second),
+		})
+	}
+	return nil
+}
+
+// VGCfgRestore replaces a volume group with a copy taken by VGCfgBackup. The logical volumes of the volume group
+// have to be inactive and all physical volumes of the backup have to exist and may not be used by other volume groups.
+// Logical volumes are inactive after the restore.
+func (c *Client) VGCfgRestore(_ context.Context, opts ...lvm2go.VGCfgRestoreOption) error {
+	options := lvm2go.VGCfgRestoreOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCfgRestoreOptions(&options)
+	}
+	if _, err := lvm2go.VGCfgRestoreOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if options.List != nil {
+		return c.listBackups(options)
+	}
+
+	file := backupFile(options.BackupFile, options.VolumeGroupName)
+	i := slices.IndexFunc(c.backups, func(backup metadataBackup) bool {
+		return backup.file == file
+	})
+	if i < 0 {
+		return lvmError("Couldn't read volume group metadata from file %s.", file)
+	}
+	backup := c.backups[i]
+	if backup.vg.name != options.VolumeGroupName {
+		return lvmError("Couldn't read volume group metadata from file %s for VG %s.", file, options.VolumeGroupName)
+	}
+
+	var seqNo int64
+	current := c.vgs[options.VolumeGroupName]
+	if current != nil {
+		active := 0
+		for _, lv := range current.lvs {
+			if lv.active {
+				active++
+			}
+		}
+		if active > 0 {
+			return lvmError("Cannot restore Volume Group %s with %d active LV(s).", current.name, active)
+		}
+		seqNo = current.seqNo
+	}
+	if !options.Force {
+		for _, lv := range backup.vg.lvs {
+			if lv.volumeType == lvm2go.VolumeTypeThinPool {
+				return lvmError("Consider using option --force to restore Volume Group %s with thin volumes.", backup.vg.name)
+			}
+		}
+	}
+	for _, name := range backup.vg.pvs {
+		pv, ok := c.pvs[name]
+		if !ok || pv.uuid != backup.pvUUIDs[name] {
+			return lvmError("Couldn't find device %s with uuid %s for Volume Group %s.", name, backup.pvUUIDs[name], backup.vg.name)
+		}
+		if pv.vg != "" && pv.vg != backup.vg.name {
+			return lvmError("Physical volume %s belongs to Volume Group %s.", name, pv.vg)
+		}
+	}
+
+	if current != nil {
+		for _, name := range current.pvs {
+			c.pvs[name].vg = ""
+		}
+	}
+	vg := backup.vg.clone()
+	for _, name := range vg.pvs {
+		c.pvs[name].vg = vg.name
+	}
+	for _, lv := range vg.lvs {
+		lv.active = false
+	}
+	vg.seqNo = max(vg.seqNo, seqNo) + 1
+	c.vgs[vg.name] = vg
+	return nil
+}
+
+// listBackups writes the backups of a volume group in the format of vgcfgrestore --list. It has to be called with the lock held.
+func (c *Client) listBackups(options lvm2go.VGCfgRestoreOptions) error {
+	out := io.Discard
+	if options.List.Writer != nil {
+		out = options.List.Writer
+	}
+	for _, backup := range c.backups {
+		if options.VolumeGroupName != "" && backup.vg.name != options.VolumeGroupName {
+			continue
+		}
+		if options.BackupFile != "" && backup.file != string(options.BackupFile) {
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "   \n  File:\t\t%s\n  VG name:    \t%s\n  Description:\t%s\n  Backup Time:\t%s\n\n",
+			backup.file, backup.vg.name, backup.description, backup.time.Format(time.ANSIC)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,483 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/azalio/lvm2go"
+)
+
+func (c *Client) PVs(_ context.Context, opts ...lvm2go.PVsOption) ([]*lvm2go.PhysicalVolume, error) {
+	options := lvm2go.PVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVsOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return nil, err
+	}
+	if err := errSortUnsupported(options.SortFields); err != nil {
+		return nil, err
+	}
+	if err := errRetainRawJSONUnsupported(options.RetainRawJSON); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var pvs []*lvm2go.PhysicalVolume
+	for _, name := range c.sortedPhysicalVolumes() {
+		pv := c.pvs[name]
+		if !inPositionalScope(len(options.PhysicalVolumeNames) > 0, options.InScope(pv.name), pv.tags, options.Tags) {
+			continue
+		}
+		if options.ByUUID != "" && pv.uuid != string(options.ByUUID) {
+			continue
+		}
+		pvs = append(pvs, c.reportPhysicalVolume(pv, options.Unit))
+	}
+	return pvs, nil
+}
+
+func (c *Client) sortedPhysicalVolumes() []lvm2go.PhysicalVolumeName {
+	names := make([]lvm2go.PhysicalVolumeName, 0, len(c.pvs))
+	for name := range c.pvs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (pv *physicalVolume) mdaCount() int64 {
+	switch pv.metadataCopies {
+	case 0:
+		return 1
+	case lvm2go.NoMetadataCopies:
+		return 0
+	}
+	return int64(pv.metadataCopies)
+}
+
+func (pv *physicalVolume) mdaUsedCount() int64 {
+	if pv.metadataIgnore {
+		return 0
+	}
+	return pv.mdaCount()
+}
+
+func (c *Client) reportPhysicalVolume(pv *physicalVolume, unit lvm2go.Unit) *lvm2go.PhysicalVolume {
+	rawAttr := "---"
+	devSize := c.devices[pv.name]
+	size, used := pv.size-DefaultPeStart, uint64(0)
+	if vg, ok := c.vgs[pv.vg]; ok {
+		rawAttr = "a--"
+		if pv.notAllocatable {
+			rawAttr = "---"
+		}
+		size = c.pvExtents(vg, pv.name) * vg.extentSize
+		used = vg.pvAllocated(pv.name) * vg.extentSize
+	}
+	attr, _ := lvm2go.ParsePVAttributes(rawAttr)
+	return &lvm2go.PhysicalVolume{
+		UUID:         pv.uuid,
+		Name:         pv.name,
+		DevSize:      sizeIn(devSize, unit),
+		Attr:         attr,
+		PeStart:      sizeIn(DefaultPeStart, unit),
+		Size:         sizeIn(size, unit),
+		Free:         sizeIn(size-used, unit),
+		Used:         sizeIn(used, unit),
+		MdaCount:     pv.mdaCount(),
+		MdaUsedCount: pv.mdaUsedCount(),
+		Tags:         slices.Clone(pv.tags),
+		VGName:       pv.vg,
+		DeviceID:     string(pv.name),
+		DeviceIDType: string(lvm2go.DeviceIDTypeDevname),
+	}
+}
+
+// PVSegments reports the allocation of every logical volume as one segment per physical volume,
+// followed by a free segment for the remaining extents.
+func (c *Client) PVSegments(_ context.Context, opts ...lvm2go.PVsOption) ([]*lvm2go.PhysicalVolumeSegment, error) {
+	options := lvm2go.PVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVsOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return nil, err
+	}
+	if err := errSortUnsupported(options.SortFields); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var segments []*lvm2go.PhysicalVolumeSegment
+	for _, name := range c.sortedPhysicalVolumes() {
+		pv := c.pvs[name]
+		vg, ok := c.vgs[pv.vg]
+		if !ok || !matchesTags(pv.tags, options.Tags) {
+			continue
+		}
+		segments = append(segments, c.physicalVolumeSegments(vg, pv)...)
+	}
+	return segments, nil
+}
+
+// physicalVolumeSegments reports the segments of a physical volume of the volume group, see PVSegments.
+func (c *Client) physicalVolumeSegments(vg *volumeGroup, pv *physicalVolume) []*lvm2go.PhysicalVolumeSegment {
+	var segments []*lvm2go.PhysicalVolumeSegment
+	var start int64
+	for _, lv := range vg.lvs {
+		var logicalStart int64
+		for _, seg := range lv.segments {
+			if seg.pv == pv.name {
+				segments = append(segments, &lvm2go.PhysicalVolumeSegment{
+					PhysicalVolumeName: pv.name,
+					PhysicalVolumeUUID: pv.uuid,
+					VolumeGroupName:    vg.name,
+					Start:              start,
+					Size:               int64(seg.extents),
+					LogicalVolumeName:  lv.name,
+					LogicalVolumeUUID:  lv.uuid,
+					LogicalExtentStart: logicalStart,
+					SegmentType:        lv.segmentType(),
+				})
+				start += int64(seg.extents)
+			}
+			logicalStart += int64(seg.extents)
+		}
+	}
+	if free := int64(c.pvExtents(vg, pv.name)) - start; free > 0 {
+		segments = append(segments, &lvm2go.PhysicalVolumeSegment{
+			PhysicalVolumeName: pv.name,
+			PhysicalVolumeUUID: pv.uuid,
+			VolumeGroupName:    vg.name,
+			Start:              start,
+			Size:               free,
+			SegmentType:        "free",
+		})
+	}
+	return segments
+}
+
+func (c *Client) createPhysicalVolume(name lvm2go.PhysicalVolumeName) error {
+	if _, ok := c.devices[name]; !ok {
+		return lvmError("No device found for %s.", name)
+	}
+	if _, ok := c.pvs[name]; ok {
+		return lvmError("Can't initialize physical volume %q of volume group %q without -ff", name, c.pvs[name].vg)
+	}
+	c.pvs[name] = &physicalVolume{uuid: c.nextUUID(), name: name, size: c.devices[name]}
+	c.addDevicesFileEntry(name)
+	return nil
+}
+
+func (c *Client) PVCreate(_ context.Context, opts ...lvm2go.PVCreateOption) error {
+	options := lvm2go.PVCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVCreateOptions(&options)
+	}
+	if _, err := lvm2go.PVCreateOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if uuid := string(options.PhysicalVolumeUUID); uuid != "" {
+		if err := c.checkRestoredUUID(uuid, options); err != nil {
+			return err
+		}
+	}
+	if err := c.createPhysicalVolume(options.PhysicalVolumeName); err != nil {
+		return err
+	}
+	pv := c.pvs[options.PhysicalVolumeName]
+	if options.PhysicalVolumeUUID != "" {
+		pv.uuid = string(options.PhysicalVolumeUUID)
+	}
+	pv.metadataCopies = options.MetadataCopies
+	pv.metadataIgnore = options.MetadataIgnore == lvm2go.SetMetadataIgnore
+	return nil
+}
+
+// checkRestoredUUID checks that a physical volume can be created with the UUID,
+// which has to be unused and part of the RestoreFile if set.
+func (c *Client) checkRestoredUUID(uuid string, options lvm2go.PVCreateOptions) error {
+	for _, pv := range c.pvs {
+		if pv.uuid == uuid && pv.name != options.PhysicalVolumeName {
+			return lvmError("uuid %s already in use on %q", uuid, pv.name)
+		}
+	}
+	if options.RestoreFile != "" {
+		i := slices.IndexFunc(c.backups, func(backup metadataBackup) bool {
+			return backup.file == string(options.RestoreFile)
+		})
+		if i < 0 {
+			return lvmError("Couldn't read volume group metadata from file %s.", options.RestoreFile)
+		}
+		if !slices.ContainsFunc(c.backups[i].vg.pvs, func(name lvm2go.PhysicalVolumeName) bool {
+			return c.backups[i].pvUUIDs[name] == uuid
+		}) {
+			return lvmError("Can't find uuid %s in backup file %s", uuid, options.RestoreFile)
+		}
+	}
+	return nil
+}
+
+func (c *Client) physicalVolume(name lvm2go.PhysicalVolumeName) (*physicalVolume, error) {
+	if name == "" {
+		return nil, lvm2go.ErrPhysicalVolumeNameRequired
+	}
+	pv, ok := c.pvs[name]
+	if !ok {
+		return nil, lvmError("Failed to find physical volume %q.", name)
+	}
+	return pv, nil
+}
+
+func (c *Client) PVRemove(_ context.Context, opts ...lvm2go.PVRemoveOption) error {
+	options := lvm2go.PVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVRemoveOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pv, err := c.physicalVolume(options.PhysicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if pv.vg != "" {
+		return lvmError("PV %s is used by VG %s so please use vgreduce first.", pv.name, pv.vg)
+	}
+	delete(c.pvs, pv.name)
+	c.removeDevicesFileEntry(pv.name)
+	return nil
+}
+
+// PVResize resizes the physical volume to the current size of its device, see SetDevice.
+func (c *Client) PVResize(_ context.Context, opts ...lvm2go.PVResizeOption) error {
+	options := lvm2go.PVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVResizeOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pv, err := c.physicalVolume(options.PhysicalVolumeName)
+	if err != nil {
+		return err
+	}
+	size, ok := c.devices[pv.name]
+	if !ok {
+		return lvmError("No device found for %s.", pv.name)
+	}
+	if vg, ok := c.vgs[pv.vg]; ok {
+		if extents := (size - DefaultPeStart) / vg.extentSize; extents < vg.pvAllocated(pv.name) {
+			return lvmError("%s: cannot resize to %d extents as later ones are allocated.", pv.name, extents)
+		}
+		vg.seqNo++
+	}
+	pv.size = size
+	return nil
+}
+
+// PVCheck reports physical volumes as healthy, as the fake client cannot corrupt headers or metadata.
+// Dumps and repairs return ErrUnsupported.
+func (c *Client) PVCheck(_ context.Context, opts ...lvm2go.PVCheckOption) (*lvm2go.PVCheckResult, error) {
+	options := lvm2go.PVCheckOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVCheckOptions(&options)
+	}
+	if _, err := lvm2go.PVCheckOptionsList(opts).AsArgs(); err != nil {
+		return nil, err
+	}
+	if options.PVCheckDump != "" || options.Repair {
+		return nil, fmt.Errorf("dumps and repairs of physical volumes: %w", ErrUnsupported)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.physicalVolume(options.PhysicalVolumeName); err != nil {
+		return nil, err
+	}
+	return &lvm2go.PVCheckResult{Fields: map[string]string{}}, nil
+}
+
+// PVScan autoactivates the volume groups of the given physical volumes, or of all physical volumes if none are given,
+// if all of their devices are present. Logical volumes with activation skip and exported volume groups are not activated.
+// The fake client has no device numbers, so DeviceNumbers return ErrUnsupported.
+func (c *Client) PVScan(_ context.Context, opts ...lvm2go.PVScanOption) error {
+	options := lvm2go.PVScanOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVScanOptions(&options)
+	}
+	if _, err := lvm2go.PVScanOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+	if len(options.DeviceNumbers) > 0 {
+		return fmt.Errorf("scan by device number: %w", ErrUnsupported)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	names := options.PhysicalVolumeNames
+	if len(names) == 0 {
+		for name := range c.pvs {
+			names = append(names, name)
+		}
+	}
+	vgs := map[lvm2go.VolumeGroupName]bool{}
+	for _, name := range names {
+		pv, err := c.physicalVolume(name)
+		if err != nil {
+			return err
+		}
+		if pv.vg != "" {
+			vgs[pv.vg] = true
+		}
+	}
+	if options.ActivationState != lvm2go.AutoActivate {
+		return nil
+	}
+
+	for name := range vgs {
+		vg := c.vgs[name]
+		if vg.exported || slices.ContainsFunc(vg.pvs, func(pv lvm2go.PhysicalVolumeName) bool {
+			_, ok := c.devices[pv]
+			return !ok
+		}) {
+			continue
+		}
+		for _, lv := range vg.lvs {
+			if !lv.skip {
+				lv.active = true
+			}
+		}
+	}
+	return nil
+}
+
+func (c *Client) PVChange(_ context.Context, opts ...lvm2go.PVChangeOption) error {
+	options := lvm2go.PVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVChangeOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pv, err := c.physicalVolume(options.PhysicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if _, err := lvm2go.PVChangeOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+	vg := c.vgs[pv.vg]
+	if options.RegenerateUUID {
+		if vg != nil && slices.ContainsFunc(vg.lvs, func(lv *logicalVolume) bool { return lv.active }) {
+			return lvmError("Volume group containing %s has active logical volumes", pv.name)
+		}
+		pv.uuid = c.nextUUID()
+	}
+	if options.AllocatablePhysicalVolume != "" {
+		if vg == nil {
+			return lvmError("Allocatability not supported by orphan %s format PV %s", "lvm2", pv.name)
+		}
+		pv.notAllocatable = options.AllocatablePhysicalVolume == lvm2go.SetNotAllocatable
+	}
+	if options.MetadataIgnore != "" {
+		ignore := options.MetadataIgnore == lvm2go.SetMetadataIgnore
+		if vg != nil && ignore && !pv.metadataIgnore && c.mdaUsedCount(vg)-pv.mdaUsedCount() == 0 {
+			return lvmError("Cannot change metadataignore attribute for physical volume %q. "+
+				"At least one metadata area must be used in volume group %s.", pv.name, vg.name)
+		}
+		pv.metadataIgnore = ignore
+	}
+	pv.tags = addTags(pv.tags, options.Tags, options.DelTags)
+	return nil
+}
+
+// PVMove moves all extents of the source physical volume, or only those of LogicalVolumeName if set,
+// to the destination physical volumes or any other physical volume of the volume group.
+func (c *Client) PVMove(_ context.Context, opts ...lvm2go.PVMoveOption) error {
+	options := lvm2go.PVMoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVMoveOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pv, err := c.physicalVolume(options.From)
+	if err != nil {
+		return err
+	}
+	vg, ok := c.vgs[pv.vg]
+	if !ok {
+		return lvmError("Physical volume %s not in volume group.", pv.name)
+	}
+
+	// everything except the destinations is excluded from allocation
+	exclude := []lvm2go.PhysicalVolumeName{pv.name}
+	if len(options.To) > 0 {
+		for _, other := range vg.pvs {
+			if !slices.Contains(options.To, other) {
+				exclude = append(exclude, other)
+			}
+		}
+	}
+
+	moved := false
+	for _, lv := range vg.lvs {
+		if options.LogicalVolumeName != "" && lv.name != options.LogicalVolumeName {
+			continue
+		}
+		// segments are replaced one by one, so that every allocation sees the previous ones
+		for i := 0; i < len(lv.segments); i++ {
+			if lv.segments[i].pv != pv.name {
+				continue
+			}
+			allocated, err := c.allocate(vg, lv.segments[i].extents, exclude...)
+			if err != nil {
+				return err
+			}
+			lv.segments = slices.Replace(lv.segments, i, i+1, allocated...)
+			i += len(allocated) - 1
+			moved = true
+		}
+	}
+	if !moved {
+		if options.LogicalVolumeName != "" {
+			return fmt.Errorf("%w: %s is not located on %s", lvm2go.ErrLogicalVolumeNotOnPhysicalVolume, options.LogicalVolumeName, options.From)
+		}
+		return lvmError("No data to move for %s.", vg.name)
+	}
+	vg.seqNo++
+	return nil
+}
@@ -0,0 +1,438 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/azalio/lvm2go"
+)
+
+// Failure behaviors of a FailureProfile, which return the error lvm2 reports in the situation.
+// Other values of FailureProfile.Error are returned as the stderr of the command.
+const (
+	// FailureBusy fails as if the target device was opened exclusively by someone else, e.g. a mounted filesystem.
+	FailureBusy = "busy"
+	// FailureIOError fails as if reading the target device failed, see lvmerrors.IsIOError.
+	FailureIOError = "io-error"
+)
+
+// HostProfile is a declarative description of the storage of a host, used to create a Simulation.
+// It is usually decoded from JSON with ParseHostProfile. The fields are also tagged for YAML,
+// so that profiles can be decoded with any YAML library and passed to NewSimulation.
+//
+// Example:
+//
+//	{
+//	  "devices": [{"name": "/dev/sdb", "size": "10G"}, {"name": "/dev/sdc", "size": "10G"}],
+//	  "volumeGroups": [{
+//	    "name": "vg", "physicalVolumes": ["/dev/sdb"],
+//	    "logicalVolumes": [{"name": "pool", "size": "4G", "type": "thin-pool"}, {"name": "data", "size": "20G", "thinPool": "pool"}]
+//	  }],
+//	  "failures": [{"command": "pvcreate", "target": "/dev/sdc", "error": "busy"}]
+//	}
+type HostProfile struct {
+	// Devices are the block devices of the host, see Client.SetDevice.
+	Devices []DeviceProfile `json:"devices,omitempty" yaml:"devices,omitempty"`
+	// VolumeGroups are created in order on the devices, initializing them as physical volumes.
+	VolumeGroups []VolumeGroupProfile `json:"volumeGroups,omitempty" yaml:"volumeGroups,omitempty"`
+	// Failures are injected into the commands of the Simulation.
+	Failures []FailureProfile `json:"failures,omitempty" yaml:"failures,omitempty"`
+}
+
+// DeviceProfile describes a block device with a size such as 10G, see lvm2go.ParseSize.
+type DeviceProfile struct {
+	Name string `json:"name" yaml:"name"`
+	Size string `json:"size" yaml:"size"`
+}
+
+// VolumeGroupProfile describes a volume group and its logical volumes.
+type VolumeGroupProfile struct {
+	Name            string   `json:"name" yaml:"name"`
+	PhysicalVolumes []string `json:"physicalVolumes" yaml:"physicalVolumes"`
+	// ExtentSize is the size of the physical extents, 4M if empty.
+	ExtentSize     string                 `json:"extentSize,omitempty" yaml:"extentSize,omitempty"`
+	Tags           []string               `json:"tags,omitempty" yaml:"tags,omitempty"`
+	LogicalVolumes []LogicalVolumeProfile `json:"logicalVolumes,omitempty" yaml:"logicalVolumes,omitempty"`
+}
+
+// LogicalVolumeProfile describes a logical volume, which is created in order with the others of its volume group.
+type LogicalVolumeProfile struct {
+	Name string `json:"name" yaml:"name"`
+	// Size is the size of the logical volume, or the virtual size of thin volumes.
+	Size string `json:"size" yaml:"size"`
+	// Type is the segment type, e.g. thin-pool. Logical volumes in a ThinPool are thin volumes.
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+	// ThinPool is the name of the thin pool of thin volumes, which has to be created before.
+	ThinPool string   `json:"thinPool,omitempty" yaml:"thinPool,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Inactive deactivates the logical volume after its creation.
+	Inactive bool `json:"inactive,omitempty" yaml:"inactive,omitempty"`
+}
+
+// FailureProfile makes the Simulation fail commands instead of running them.
+type FailureProfile struct {
+	// Command is the lvm2 command that fails, e.g. pvcreate or lvextend.
+	Command string `json:"command" yaml:"command"`
+	// Target restricts the failure to invocations with the argument, e.g. /dev/sdb or vg/lv. Empty matches all invocations.
+	Target string `json:"target,omitempty" yaml:"target,omitempty"`
+	// Error is FailureBusy, FailureIOError or the stderr returned by the command.
+	Error string `json:"error" yaml:"error"`
+	// Times limits how often the failure occurs, e.g. 1 for a command that succeeds when retried. Zero fails every time.
+	Times int `json:"times,omitempty" yaml:"times,omitempty"`
+}
+
+// ParseHostProfile decodes a HostProfile from JSON, rejecting unknown fields.
+func ParseHostProfile(data []byte) (*HostProfile, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	profile := &HostProfile{}
+	if err := decoder.Decode(profile); err != nil {
+		return nil, fmt.Errorf("failed to parse host profile: %w", err)
+	}
+	return profile, nil
+}
+
+// Simulation is a Client set up from a HostProfile, which fails commands as described by the failures of the profile.
+// It allows scenario tests of storage logic built on lvm2go to run without any disks or lvm2 installation.
+// Failures are injected into the mutating commands as well as into the LVs, VGs and PVs reports,
+// other methods run unchanged on the underlying Client.
+type Simulation struct {
+	*Client
+
+	mu       sync.Mutex
+	failures []*failure
+}
+
+var _ lvm2go.Client = (*Simulation)(nil)
+
+type failure struct {
+	FailureProfile
+	occurred int
+}
+
+// NewSimulation creates the devices, volume groups and logical volumes of the profile.
+func NewSimulation(profile *HostProfile) (*Simulation, error) {
+	ctx := context.Background()
+	client := NewClient()
+	for _, device := range profile.Devices {
+		size, err := lvm2go.ParseSize(device.Size)
+		if err != nil {
+			return nil, fmt.Errorf("invalid size of device %s: %w", device.Name, err)
+		}
+		if err := client.SetDevice(device.Name, size); err != nil {
+			return nil, err
+		}
+	}
+	for _, vg := range profile.VolumeGroups {
+		if err := createVolumeGroup(ctx, client, vg); err != nil {
+			return nil, fmt.Errorf("failed to create volume group %s of host profile: %w", vg.Name, err)
+		}
+	}
+
+	sim := &Simulation{Client: client}
+	for _, f := range profile.Failures {
+		if f.Command == "" {
+			return nil, fmt.Errorf("failure %q of host profile has no command", f.Error)
+		}
+		sim.failures = append(sim.failures, &failure{FailureProfile: f})
+	}
+	return sim, nil
+}
+
+func createVolumeGroup(ctx context.Context, client *Client, profile VolumeGroupProfile) error {
+	opts := []lvm2go.VGCreateOption{
+		lvm2go.VolumeGroupName(profile.Name),
+		lvm2go.PhysicalVolumesFrom(profile.PhysicalVolumes...),
+	}
+	if len(profile.Tags) > 0 {
+		opts = append(opts, lvm2go.Tags(profile.Tags))
+	}
+	if profile.ExtentSize != "" {
+		size, err := lvm2go.ParseSize(profile.ExtentSize)
+		if err != nil {
+			return fmt.Errorf("invalid extent size: %w", err)
+		}
+		opts = append(opts, lvm2go.PhysicalExtentSize(size))
+	}
+	if err := client.VGCreate(ctx, opts...); err != nil {
+		return err
+	}
+
+	for _, lv := range profile.LogicalVolumes {
+		size, err := lvm2go.ParseSize(lv.Size)
+		if err != nil {
+			return fmt.Errorf("invalid size of logical volume %s: %w", lv.Name, err)
+		}
+		opts := []lvm2go.LVCreateOption{lvm2go.LogicalVolumeName(lv.Name)}
+		if lv.ThinPool != "" {
+			pool, err := lvm2go.NewThinPool(lvm2go.VolumeGroupName(profile.Name), lvm2go.LogicalVolumeName(lv.ThinPool))
+			if err != nil {
+				return err
+			}
+			opts = append(opts, pool, size.Virtual())
+		} else {
+			opts = append(opts, lvm2go.VolumeGroupName(profile.Name), size)
+		}
+		if lv.Type != "" {
+			opts = append(opts, lvm2go.Type(lv.Type))
+		}
+		if len(lv.Tags) > 0 {
+			opts = append(opts, lvm2go.Tags(lv.Tags))
+		}
+		if lv.Inactive {
+			opts = append(opts, lvm2go.Deactivate)
+		}
+		if err := client.LVCreate(ctx, opts...); err != nil {
+			return fmt.Errorf("failed to create logical volume %s: %w", lv.Name, err)
+		}
+	}
+	return nil
+}
+
+// fail returns the injected failure of the command, if any. The target of failures is matched against the
+// arguments generated from the options and the given targets, so that failures of logical volumes match
+// even if the options cannot be turned into arguments.
+func (s *Simulation) fail(command string, generator lvm2go.ArgumentGenerator, targets ...string) error {
+	raw := targets
+	if args, err := generator.AsArgs(); err == nil {
+		raw = append(raw, args.GetRaw()...)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, f := range s.failures {
+		if f.Command != command || (f.Target != "" && !slices.Contains(raw, f.Target)) {
+			continue
+		}
+		if f.Times > 0 && f.occurred >= f.Times {
+			continue
+		}
+		f.occurred++
+		return f.err(command)
+	}
+	return nil
+}
+
+// lvTarget returns the target of failures of the logical volume, e.g. vg/lv.
+func lvTarget(vg lvm2go.VolumeGroupName, lv lvm2go.LogicalVolumeName) string {
+	return fmt.Sprintf("%s/%s", vg, lv)
+}
+
+func (f *failure) err(command string) error {
+	target := f.Target
+	if target == "" {
+		target = command
+	}
+	switch f.Error {
+	case FailureBusy:
+		return lvmError("Can't open %s exclusively.  Mounted filesystem?", target)
+	case FailureIOError:
+		return lvmError("Error reading device %s at 0 length 4096.", target)
+	case "":
+		return lvmError("%s failed", command)
+	}
+	return lvmError("%s", strings.TrimSpace(f.Error))
+}
+
+func (s *Simulation) LVs(ctx context.Context, opts ...lvm2go.LVsOption) ([]*lvm2go.LogicalVolume, error) {
+	if err := s.fail("lvs", lvm2go.LVsOptionsList(opts)); err != nil {
+		return nil, err
+	}
+	return s.Client.LVs(ctx, opts...)
+}
+
+func (s *Simulation) VGs(ctx context.Context, opts ...lvm2go.VGsOption) ([]*lvm2go.VolumeGroup, error) {
+	if err := s.fail("vgs", lvm2go.VGsOptionsList(opts)); err != nil {
+		return nil, err
+	}
+	return s.Client.VGs(ctx, opts...)
+}
+
+func (s *Simulation) PVs(ctx context.Context, opts ...lvm2go.PVsOption) ([]*lvm2go.PhysicalVolume, error) {
+	if err := s.fail("pvs", lvm2go.PVsOptionsList(opts)); err != nil {
+		return nil, err
+	}
+	return s.Client.PVs(ctx, opts...)
+}
+
+func (s *Simulation) LVCreate(ctx context.Context, opts ...lvm2go.LVCreateOption) error {
+	if err := s.fail("lvcreate", lvm2go.LVCreateOptionList(opts)); err != nil {
+		return err
+	}
+	return s.Client.LVCreate(ctx, opts...)
+}
+
+func (s *Simulation) LVRemove(ctx context.Context, opts ...lvm2go.LVRemoveOption) error {
+	options := lvm2go.LVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRemoveOptions(&options)
+	}
+	if err := s.fail("lvremove", lvm2go.LVRemoveOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVRemove(ctx, opts...)
+}
+
+func (s *Simulation) LVResize(ctx context.Context, opts ...lvm2go.LVResizeOption) error {
+	options := lvm2go.LVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVResizeOptions(&options)
+	}
+	if err := s.fail("lvresize", lvm2go.LVResizeOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVResize(ctx, opts...)
+}
+
+func (s *Simulation) LVExtend(ctx context.Context, opts ...lvm2go.LVExtendOption) error {
+	options := lvm2go.LVExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVExtendOptions(&options)
+	}
+	if err := s.fail("lvextend", lvm2go.LVExtendOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVExtend(ctx, opts...)
+}
+
+func (s *Simulation) LVReduce(ctx context.Context, opts ...lvm2go.LVReduceOption) error {
+	options := lvm2go.LVReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVReduceOptions(&options)
+	}
+	if err := s.fail("lvreduce", lvm2go.LVReduceOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVReduce(ctx, opts...)
+}
+
+func (s *Simulation) LVRename(ctx context.Context, opts ...lvm2go.LVRenameOption) error {
+	options := lvm2go.LVRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRenameOptions(&options)
+	}
+	if err := s.fail("lvrename", lvm2go.LVRenameOptionsList(opts), lvTarget(options.VolumeGroupName, options.Old)); err != nil {
+		return err
+	}
+	return s.Client.LVRename(ctx, opts...)
+}
+
+func (s *Simulation) LVChange(ctx context.Context, opts ...lvm2go.LVChangeOption) error {
+	options := lvm2go.LVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVChangeOptions(&options)
+	}
+	if err := s.fail("lvchange", lvm2go.LVChangeOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVChange(ctx, opts...)
+}
+
+func (s *Simulation) LVConvert(ctx context.Context, opts ...lvm2go.LVConvertOption) error {
+	options := lvm2go.LVConvertOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVConvertOptions(&options)
+	}
+	if err := s.fail("lvconvert", lvm2go.LVConvertOptionsList(opts), lvTarget(options.VolumeGroupName, options.LogicalVolumeName)); err != nil {
+		return err
+	}
+	return s.Client.LVConvert(ctx, opts...)
+}
+
+func (s *Simulation) VGCreate(ctx context.Context, opts ...lvm2go.VGCreateOption) error {
+	if err := s.fail("vgcreate", lvm2go.VGCreateOptionList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGCreate(ctx, opts...)
+}
+
+func (s *Simulation) VGRemove(ctx context.Context, opts ...lvm2go.VGRemoveOption) error {
+	if err := s.fail("vgremove", lvm2go.VGRemoveOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGRemove(ctx, opts...)
+}
+
+func (s *Simulation) VGExtend(ctx context.Context, opts ...lvm2go.VGExtendOption) error {
+	if err := s.fail("vgextend", lvm2go.VGExtendOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGExtend(ctx, opts...)
+}
+
+func (s *Simulation) VGReduce(ctx context.Context, opts ...lvm2go.VGReduceOption) error {
+	if err := s.fail("vgreduce", lvm2go.VGReduceOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGReduce(ctx, opts...)
+}
+
+func (s *Simulation) VGRename(ctx context.Context, opts ...lvm2go.VGRenameOption) error {
+	if err := s.fail("vgrename", lvm2go.VGRenameOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGRename(ctx, opts...)
+}
+
+func (s *Simulation) VGChange(ctx context.Context, opts ...lvm2go.VGChangeOption) error {
+	if err := s.fail("vgchange", lvm2go.VGChangeOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.VGChange(ctx, opts...)
+}
+
+func (s *Simulation) PVCreate(ctx context.Context, opts ...lvm2go.PVCreateOption) error {
+	if err := s.fail("pvcreate", lvm2go.PVCreateOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.PVCreate(ctx, opts...)
+}
+
+func (s *Simulation) PVRemove(ctx context.Context, opts ...lvm2go.PVRemoveOption) error {
+	if err := s.fail("pvremove", lvm2go.PVRemoveOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.PVRemove(ctx, opts...)
+}
+
+func (s *Simulation) PVResize(ctx context.Context, opts ...lvm2go.PVResizeOption) error {
+	if err := s.fail("pvresize", lvm2go.PVResizeOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.PVResize(ctx, opts...)
+}
+
+func (s *Simulation) PVChange(ctx context.Context, opts ...lvm2go.PVChangeOption) error {
+	if err := s.fail("pvchange", lvm2go.PVChangeOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.PVChange(ctx, opts...)
+}
+
+func (s *Simulation) PVMove(ctx context.Context, opts ...lvm2go.PVMoveOption) error {
+	if err := s.fail("pvmove", lvm2go.PVMoveOptionsList(opts)); err != nil {
+		return err
+	}
+	return s.Client.PVMove(ctx, opts...)
+}
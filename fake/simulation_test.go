@@ -0,0 +1,94 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+	"github.com/azalio/lvm2go/lvmerrors"
+)
+
+const hostProfile = `{
+  "devices": [{"name": "/dev/sdb", "size": "101M"}, {"name": "/dev/sdc", "size": "101M"}],
+  "volumeGroups": [{
+    "name": "vg", "physicalVolumes": ["/dev/sdb"], "tags": ["managed"],
+    "logicalVolumes": [
+      {"name": "pool", "size": "40M", "type": "thin-pool"},
+      {"name": "thin", "size": "1G", "thinPool": "pool"},
+      {"name": "data", "size": "8M", "inactive": true}
+    ]
+  }],
+  "failures": [
+    {"command": "pvcreate", "target": "/dev/sdc", "error": "busy"},
+    {"command": "lvextend", "target": "vg/data", "error": "io-error", "times": 1}
+  ]
+}`
+
+func TestSimulation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	profile, err := fake.ParseHostProfile([]byte(hostProfile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim, err := fake.NewSimulation(profile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lvs, err := sim.LVs(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 3 {
+		t.Fatalf("expected 3 logical volumes, got %d", len(lvs))
+	}
+	for _, lv := range lvs {
+		switch lv.Name {
+		case "thin":
+			if lv.PoolLogicalVolume != "pool" {
+				t.Fatalf("expected thin volume in pool, got %v", lv)
+			}
+		case "data":
+			if lv.Attr.State == StateActive {
+				t.Fatalf("expected inactive logical volume, got %s", lv.Attr)
+			}
+		}
+	}
+
+	for range 2 {
+		if err := sim.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdc"}); err == nil {
+			t.Fatal("expected pvcreate on busy /dev/sdc to fail every time")
+		}
+	}
+
+	extend := []LVExtendOption{VolumeGroupName("vg"), LogicalVolumeName("data"), MustParsePrefixedSize("+4M")}
+	if err := sim.LVExtend(ctx, extend...); !lvmerrors.IsIOError(err) {
+		t.Fatalf("expected injected I/O error, got %v", err)
+	}
+	if err := sim.LVExtend(ctx, extend...); err != nil {
+		t.Fatalf("expected retry to succeed after the failure occurred once: %v", err)
+	}
+
+	if _, err := fake.ParseHostProfile([]byte(`{"disks": []}`)); err == nil {
+		t.Fatal("expected error for unknown field")
+	}
+}
@@ -0,0 +1,788 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package fake
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/azalio/lvm2go"
+)
+
+func (c *Client) VG(ctx context.Context, opts ...lvm2go.VGsOption) (*lvm2go.VolumeGroup, error) {
+	options := lvm2go.VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, lvm2go.ErrVolumeGroupNameRequired
+	}
+	vgs, err := c.VGs(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(vgs) == 0 {
+		return nil, lvm2go.ErrVolumeGroupNotFound
+	}
+	return vgs[0], nil
+}
+
+func (c *Client) VGs(_ context.Context, opts ...lvm2go.VGsOption) ([]*lvm2go.VolumeGroup, error) {
+	options := lvm2go.VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return nil, err
+	}
+	if err := errSortUnsupported(options.SortFields); err != nil {
+		return nil, err
+	}
+	if err := errRetainRawJSONUnsupported(options.RetainRawJSON); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var vgs []*lvm2go.VolumeGroup
+	for _, name := range c.sortedVolumeGroups() {
+		vg := c.vgs[name]
+		named := options.VolumeGroupName != "" || len(options.VolumeGroupNames) > 0
+		if !inPositionalScope(named, options.InScope(vg.name), vg.tags, options.Tags) {
+			continue
+		}
+		if options.ByUUID != "" && vg.uuid != string(options.ByUUID) {
+			continue
+		}
+		vgs = append(vgs, c.reportVolumeGroup(vg, options.Unit))
+	}
+	return vgs, nil
+}
+
+func (c *Client) sortedVolumeGroups() []lvm2go.VolumeGroupName {
+	names := make([]lvm2go.VolumeGroupName, 0, len(c.vgs))
+	for name := range c.vgs {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (c *Client) reportVolumeGroup(vg *volumeGroup, unit lvm2go.Unit) *lvm2go.VolumeGroup {
+	attr, _ := lvm2go.ParseVGAttributes("wz--n-")
+	if vg.exported {
+		attr.Exported = lvm2go.ExportedTrue
+	}
+	if vg.notResizeable {
+		attr.Resizeable = lvm2go.ResizeableFalse
+	}
+	var lvCount, snapCount int64
+	for _, lv := range vg.lvs {
+		lvCount++
+		if lv.volumeType == lvm2go.VolumeTypeSnapshot {
+			snapCount++
+		}
+	}
+	extentCount, freeCount := c.extentCount(vg), c.freeCount(vg)
+	report := &lvm2go.VolumeGroup{
+		UUID:         vg.uuid,
+		Name:         vg.name,
+		SysID:        string(vg.systemID),
+		SystemID:     string(vg.systemID),
+		Attr:         attr,
+		Tags:         slices.Clone(vg.tags),
+		Extendable:   lvm2go.ExtendableTrue,
+		Permissions:  "writeable",
+		ExtentSize:   sizeIn(vg.extentSize, unit),
+		ExtentCount:  int64(extentCount),
+		SeqNo:        vg.seqNo,
+		Size:         sizeIn(extentCount*vg.extentSize, unit),
+		Free:         sizeIn(freeCount*vg.extentSize, unit),
+		FreeCount:    int64(freeCount),
+		PvCount:      int64(len(vg.pvs)),
+		MaxPv:        int64(vg.maxPv),
+		LvCount:      lvCount,
+		MaxLv:        int64(vg.maxLv),
+		SnapCount:    snapCount,
+		MDACount:     c.mdaCount(vg),
+		MDAUsedCount: c.mdaUsedCount(vg),
+	}
+	if !vg.noAutoActivation {
+		report.AutoActivation = lvm2go.AutoActivationFromReportEnabled
+	}
+	return report
+}
+
+// addPhysicalVolumes adds devices to the volume group, initializing them as physical volumes if needed
+// like vgcreate and vgextend do. Nothing is changed if any of the devices cannot be added.
+func (c *Client) addPhysicalVolumes(vg *volumeGroup, names lvm2go.PhysicalVolumeNames) error {
+	for i, name := range names {
+		if vg.maxPv > 0 && len(vg.pvs)+i >= vg.maxPv {
+			return lvmError("No space for '%s' - volume group '%s' holds max %d physical volume(s).", name, vg.name, vg.maxPv)
+		}
+		if _, ok := c.devices[name]; !ok {
+			return lvmError("No device found for %s.", name)
+		}
+		if pv, ok := c.pvs[name]; ok && pv.vg != "" {
+			return lvmError("Physical volume '%s' is already in volume group '%s'", name, pv.vg)
+		}
+	}
+	for _, name := range names {
+		if _, ok := c.pvs[name]; !ok {
+			if err := c.createPhysicalVolume(name); err != nil {
+				return err
+			}
+		}
+		// physical volumes become allocatable when added to a volume group
+		c.pvs[name].vg, c.pvs[name].notAllocatable = vg.name, false
+		vg.pvs = append(vg.pvs, name)
+	}
+	return nil
+}
+
+func (c *Client) VGCreate(_ context.Context, opts ...lvm2go.VGCreateOption) error {
+	options := lvm2go.VGCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCreateOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return lvm2go.ErrVolumeGroupNameRequired
+	}
+	if len(options.PhysicalVolumeNames) == 0 {
+		return lvmError("Please enter physical volume name(s)")
+	}
+	if options.Shared || options.LockType == lvm2go.LockTypeSanlock || options.LockType == lvm2go.LockTypeDLM {
+		return errLockdUnavailable()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.vgs[options.VolumeGroupName]; ok {
+		return lvmError("A volume group called %s already exists.", options.VolumeGroupName)
+	}
+
+	vg := &volumeGroup{
+		uuid:       c.nextUUID(),
+		name:       options.VolumeGroupName,
+		extentSize: DefaultExtentSize,
+		tags:       addTags(nil, options.Tags, nil),
+		maxLv:      max(int(options.MaximumLogicalVolumes), 0),
+		maxPv:      max(int(options.MaximumPhysicalVolumes), 0),
+		seqNo:      1,
+		systemID:   options.SystemID,
+	}
+	vg.noAutoActivation = options.AutoActivation == lvm2go.SetNoAutoActivate
+	if options.PhysicalExtentSize.Val > 0 {
+		extentSize, err := lvm2go.Size(options.PhysicalExtentSize).ToUnit(lvm2go.UnitBytes)
+		if err != nil {
+			return err
+		}
+		vg.extentSize = uint64(extentSize.Val)
+	}
+
+	if err := c.addPhysicalVolumes(vg, options.PhysicalVolumeNames); err != nil {
+		return err
+	}
+
+	c.vgs[vg.name] = vg
+	return nil
+}
+
+func (c *Client) VGRemove(_ context.Context, opts ...lvm2go.VGRemoveOption) error {
+	options := lvm2go.VGRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRemoveOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, err := c.volumeGroup(options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	if len(vg.lvs) > 0 && !options.Force {
+		return lvmError("Volume group %q still contains %d logical volume(s)", vg.name, len(vg.lvs))
+	}
+	for _, pv := range vg.pvs {
+		c.pvs[pv].vg = ""
+	}
+	delete(c.vgs, vg.name)
+	return nil
+}
+
+func (c *Client) VGExtend(_ context.Context, opts ...lvm2go.VGExtendOption) error {
+	options := lvm2go.VGExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGExtendOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, err := c.volumeGroup(options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	if vg.notResizeable {
+		return lvmError("Volume group %s is not resizeable.", vg.name)
+	}
+	if err := c.addPhysicalVolumes(vg, options.PhysicalVolumeNames); err != nil {
+		return err
+	}
+	vg.seqNo++
+	return nil
+}
+
+func (c *Client) VGReduce(_ context.Context, opts ...lvm2go.VGReduceOption) error {
+	options := lvm2go.VGReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGReduceOptions(&options)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, err := c.volumeGroup(options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	if vg.notResizeable {
+		return lvmError("Volume group %s is not resizeable.", vg.name)
+	}
+	// physical volumes of the fake are never missing, so RemoveMissing has nothing to remove
+	if options.RemoveMissing && len(options.PhysicalVolumeNames) == 0 {
+		return nil
+	}
+	for _, name := range options.PhysicalVolumeNames {
+		if !slices.Contains(vg.pvs, name) {
+			return lvmError("Physical Volume %q not found in Volume Group %q.", name, vg.name)
+		}
+		if vg.pvAllocated(name) > 0 {
+			return lvmError("Physical volume %q still in use", name)
+		}
+	}
+	if len(options.PhysicalVolumeNames) >= len(vg.pvs) {
+		return lvmError("Can't remove final physical volume from volume group %q", vg.name)
+	}
+	for _, name := range options.PhysicalVolumeNames {
+		vg.pvs = slices.DeleteFunc(vg.pvs, func(pv lvm2go.PhysicalVolumeName) bool {
+			return pv == name
+		})
+		c.pvs[name].vg = ""
+	}
+	vg.seqNo++
+	return nil
+}
+
+func (c *Client) VGRename(_ context.Context, opts ...lvm2go.VGRenameOption) error {
+	options := lvm2go.VGRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRenameOptions(&options)
+	}
+	if options.New == "" {
+		return lvm2go.ErrVolumeGroupNameRequired
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vg, err := c.volumeGroup(options.Old)
+	if err != nil {
+		return err
+	}
+	if _, ok := c.vgs[options.New]; ok {
+		return lvmError("New volume group %q already exists", options.New)
+	}
+	delete(c.vgs, vg.name)
+	vg.name = options.New
+	for _, pv := range vg.pvs {
+		c.pvs[pv].vg = vg.name
+	}
+	c.vgs[vg.name] = vg
+	vg.seqNo++
+	return nil
+}
+
+// VGSplit moves the physical volumes, or those of a logical volume, together with the logical volumes
+// located on them into a new or existing volume group. Logical volumes have to be inactive and located
+// entirely on the split physical volumes, thin volumes follow their thin pool.
+func (c *Client) VGSplit(_ context.Context, opts ...lvm2go.VGSplitOption) error {
+	options := lvm2go.VGSplitOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGSplitOptions(&options)
+	}
+	if _, err := lvm2go.VGSplitOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, err := c.volumeGroup(options.From)
+	if err != nil {
+		return err
+	}
+
+	pvs := options.PhysicalVolumeNames
+	if options.LogicalVolumeName != "" {
+		lv := src.lv(options.LogicalVolumeName)
+		if lv == nil {
+			return errLogicalVolumeNotFound(src.name, options.LogicalVolumeName)
+		}
+		if lv.pool != "" {
+			lv = src.lv(lv.pool)
+		}
+		for _, seg := range lv.segments {
+			if !slices.Contains(pvs, seg.pv) {
+				pvs = append(pvs, seg.pv)
+			}
+		}
+	}
+	for _, pv := range pvs {
+		if !slices.Contains(src.pvs, pv) {
+			return lvmError("Physical volume %s not in volume group %s.", pv, src.name)
+		}
+	}
+	if len(pvs) == len(src.pvs) {
+		return lvmError("Cannot split: Nowhere to store metadata for new Volume Group")
+	}
+
+	// logical volumes with extents on the split physical volumes move, thin volumes move with their pool
+	var moved []*logicalVolume
+	for _, lv := range src.lvs {
+		in, out := false, false
+		for _, seg := range lv.segments {
+			if slices.Contains(pvs, seg.pv) {
+				in = true
+			} else {
+				out = true
+			}
+		}
+		if in && out {
+			return lvmError("Can't split Logical Volume %s between two Volume Groups", lv.name)
+		}
+		if in {
+			moved = append(moved, lv)
+		}
+	}
+	for _, lv := range src.lvs {
+		if lv.pool != "" && slices.ContainsFunc(moved, func(pool *logicalVolume) bool { return pool.name == lv.pool }) {
+			moved = append(moved, lv)
+		}
+	}
+	for _, lv := range moved {
+		if lv.active {
+			return lvmError("Logical volume %s/%s must be inactive.", src.name, lv.name)
+		}
+	}
+
+	dst, exists := c.vgs[options.To]
+	if exists {
+		if dst.extentSize != src.extentSize {
+			return lvmError("Extent sizes differ: %d (%s) != %d (%s)", src.extentSize, src.name, dst.extentSize, dst.name)
+		}
+		for _, lv := range moved {
+			if dst.lv(lv.name) != nil {
+				return lvmError("Duplicate logical volume name %s in %s and %s", lv.name, src.name, dst.name)
+			}
+		}
+	} else {
+		dst = &volumeGroup{
+			uuid:       c.nextUUID(),
+			name:       options.To,
+			extentSize: src.extentSize,
+			maxLv:      int(options.MaximumLogicalVolumes),
+			maxPv:      int(options.MaximumPhysicalVolumes),
+		}
+		c.vgs[dst.name] = dst
+	}
+
+	src.pvs = slices.DeleteFunc(src.pvs, func(pv lvm2go.PhysicalVolumeName) bool { return slices.Contains(pvs, pv) })
+	src.lvs = slices.DeleteFunc(src.lvs, func(lv *logicalVolume) bool { return slices.Contains(moved, lv) })
+	dst.pvs = append(dst.pvs, pvs...)
+	dst.lvs = append(dst.lvs, moved...)
+	for _, pv := range pvs {
+		c.pvs[pv].vg = dst.name
+	}
+	src.seqNo++
+	dst.seqNo++
+	return nil
+}
+
+// VGMerge moves the physical and logical volumes of the source volume group into the destination volume group
+// and removes the source. With Test, the merge is only validated. List is not supported.
+func (c *Client) VGMerge(_ context.Context, opts ...lvm2go.VGMergeOption) error {
+	options := lvm2go.VGMergeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGMergeOptions(&options)
+	}
+	if _, err := lvm2go.VGMergeOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+	if options.List != nil {
+		return fmt.Errorf("VGMerge with List: %w", ErrUnsupported)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	src, err := c.volumeGroup(options.Source)
+	if err != nil {
+		return err
+	}
+	dst, err := c.volumeGroup(options.Destination)
+	if err != nil {
+		return err
+	}
+	if src.extentSize != dst.extentSize {
+		return lvmError("Extent sizes differ: %d (%s) != %d (%s)", src.extentSize, src.name, dst.extentSize, dst.name)
+	}
+	for _, lv := range src.lvs {
+		if lv.active {
+			return lvmError("Logical volumes in \"%s\" must be inactive", src.name)
+		}
+		if dst.lv(lv.name) != nil {
+			return lvmError("Duplicate logical volume name \"%s\" in \"%s\" and \"%s\"", lv.name, dst.name, src.name)
+		}
+	}
+	if dst.maxLv > 0 && len(dst.lvs)+len(src.lvs) > dst.maxLv {
+		return lvmError("Maximum number of logical volumes (%d) exceeded for \"%s\" and \"%s\"", dst.maxLv, dst.name, src.name)
+	}
+	if dst.maxPv > 0 && len(dst.pvs)+len(src.pvs) > dst.maxPv {
+		return lvmError("Maximum number of physical volumes (%d) exceeded for \"%s\" and \"%s\"", dst.maxPv, dst.name, src.name)
+	}
+	if options.Test {
+		return nil
+	}
+
+	for _, pv := range src.pvs {
+		c.pvs[pv].vg = dst.name
+	}
+	dst.pvs = append(dst.pvs, src.pvs...)
+	dst.lvs = append(dst.lvs, src.lvs...)
+	dst.seqNo++
+	delete(c.vgs, src.name)
+	return nil
+}
+
+// VGExport marks volume groups as exported. Other commands do not refuse exported volume groups.
+func (c *Client) VGExport(_ context.Context, opts ...lvm2go.VGExportOption) error {
+	options := lvm2go.VGExportOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGExportOptions(&options)
+	}
+	if _, err := lvm2go.VGExportOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vgs, err := c.selectVolumeGroups(options.VolumeGroupName, options.All)
+	if err != nil {
+		return err
+	}
+	for _, vg := range vgs {
+		if vg.exported {
+			return lvmError("Volume group \"%s\" is already exported", vg.name)
+		}
+		if slices.ContainsFunc(vg.lvs, func(lv *logicalVolume) bool { return lv.active }) {
+			return lvmError("Volume group \"%s\" has active logical volumes", vg.name)
+		}
+	}
+	for _, vg := range vgs {
+		vg.exported = true
+		vg.seqNo++
+	}
+	return nil
+}
+
+// VGImport clears the exported mark of volume groups.
+func (c *Client) VGImport(_ context.Context, opts ...lvm2go.VGImportOption) error {
+	options := lvm2go.VGImportOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGImportOptions(&options)
+	}
+	if _, err := lvm2go.VGImportOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vgs, err := c.selectVolumeGroups(options.VolumeGroupName, options.All)
+	if err != nil {
+		return err
+	}
+	for _, vg := range vgs {
+		if !vg.exported && !bool(options.All) {
+			return lvmError("Volume group \"%s\" is not exported", vg.name)
+		}
+	}
+	for _, vg := range vgs {
+		if vg.exported {
+			vg.exported = false
+			vg.seqNo++
+		}
+	}
+	return nil
+}
+
+// VGImportClone gives a volume group and its physical volumes new UUIDs and renames it, as if its
+// physical volumes were clones. All physical volumes of the volume group have to be given.
+// The fake client has no duplicates of physical volumes, so the original volume group is not kept.
+func (c *Client) VGImportClone(_ context.Context, opts ...lvm2go.VGImportCloneOption) error {
+	options := lvm2go.VGImportCloneOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGImportCloneOptions(&options)
+	}
+	if _, err := lvm2go.VGImportCloneOptionsList(opts).AsArgs(); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var vg *volumeGroup
+	for _, name := range options.PhysicalVolumeNames {
+		pv, ok := c.pvs[name]
+		if !ok || pv.vg == "" {
+			return lvmError("Device %s is not in a volume group.", name)
+		}
+		if vg != nil && vg.name != pv.vg {
+			return lvmError("Cannot import clone of multiple volume groups.")
+		}
+		vg = c.vgs[pv.vg]
+	}
+	if len(options.PhysicalVolumeNames) != len(vg.pvs) {
+		return lvmError("Found missing devices for volume group %s, all of its devices have to be given.", vg.name)
+	}
+	base := lvm2go.VolumeGroupName(options.BaseVolumeGroupName)
+	if base == "" {
+		base = vg.name
+	}
+	name := base
+	for i := 1; c.vgs[name] != nil; i++ {
+		name = lvm2go.VolumeGroupName(fmt.Sprintf("%s%d", base, i))
+	}
+
+	delete(c.vgs, vg.name)
+	vg.name, vg.uuid = name, c.nextUUID()
+	if options.Import {
+		vg.exported = false
+	}
+	for _, pv := range vg.pvs {
+		c.pvs[pv].vg, c.pvs[pv].uuid = name, c.nextUUID()
+	}
+	c.vgs[name] = vg
+	vg.seqNo++
+	return nil
+}
+
+// VGCheck reports all volume groups as consistent, as the fake client cannot corrupt metadata.
+func (c *Client) VGCheck(_ context.Context, opts ...lvm2go.VGCheckOption) (*lvm2go.VGCheckResult, error) {
+	options := lvm2go.VGCheckOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCheckOptions(&options)
+	}
+	if _, err := lvm2go.VGCheckOptionsList(opts).AsArgs(); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	vgs, err := c.selectVolumeGroups(options.VolumeGroupName, options.VolumeGroupName == "")
+	if err != nil {
+		return nil, err
+	}
+	if options.UpdateMetadata {
+		for _, vg := range vgs {
+			vg.seqNo++
+		}
+	}
+	return &lvm2go.VGCheckResult{Consistent: true}, nil
+}
+
+// VGScan only validates the options, as the fake client has no devices to scan.
+func (c *Client) VGScan(_ context.Context, opts ...lvm2go.VGScanOption) error {
+	_, err := lvm2go.VGScanOptionsList(opts).AsArgs()
+	return err
+}
+
+// selectVolumeGroups returns the named volume group or all volume groups. It has to be called with the lock held.
+func (c *Client) selectVolumeGroups(name lvm2go.VolumeGroupName, all lvm2go.All) ([]*volumeGroup, error) {
+	if !all {
+		vg, err := c.volumeGroup(name)
+		if err != nil {
+			return nil, err
+		}
+		return []*volumeGroup{vg}, nil
+	}
+	var vgs []*volumeGroup
+	for _, name := range c.sortedVolumeGroups() {
+		vgs = append(vgs, c.vgs[name])
+	}
+	return vgs, nil
+}
+
+func (c *Client) VGChange(_ context.Context, opts ...lvm2go.VGChangeOption) error {
+	options := lvm2go.VGChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGChangeOptions(&options)
+	}
+	if err := errSelectUnsupported(options.Select); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := (lvm2go.VGChangeOptionsList{&options}).AsArgs(); err != nil {
+		return err
+	}
+	if options.LockType == lvm2go.LockTypeSanlock || options.LockType == lvm2go.LockTypeDLM {
+		return errLockdUnavailable()
+	}
+	// all volume groups of the fake client are local, so there are no lockspaces to start or stop
+	if bool(options.LockStart) || bool(options.LockStop) {
+		if options.VolumeGroupName == "" {
+			return nil
+		}
+		_, err := c.volumeGroup(options.VolumeGroupName)
+		return err
+	}
+	vg, err := c.volumeGroup(options.VolumeGroupName)
+	if err != nil {
+		return err
+	}
+	if options.RegenerateUUID {
+		if slices.ContainsFunc(vg.lvs, func(lv *logicalVolume) bool { return lv.active }) {
+			return lvmError("Volume group has active logical volumes")
+		}
+		vg.uuid = c.nextUUID()
+	}
+	if options.MaximumLogicalVolumes < 0 {
+		vg.maxLv = 0
+	} else if options.MaximumLogicalVolumes > 0 {
+		if int(options.MaximumLogicalVolumes) < len(vg.lvs) {
+			return lvmError("MaxLogicalVolume is less than the current number %d of LVs for %s", len(vg.lvs), vg.name)
+		}
+		vg.maxLv = int(options.MaximumLogicalVolumes)
+	}
+	if options.MaximumPhysicalVolumes < 0 {
+		vg.maxPv = 0
+	} else if options.MaximumPhysicalVolumes > 0 {
+		if int(options.MaximumPhysicalVolumes) < len(vg.pvs) {
+			return lvmError("MaxPhysicalVolumes is less than the current number %d of PVs for %q", len(vg.pvs), vg.name)
+		}
+		vg.maxPv = int(options.MaximumPhysicalVolumes)
+	}
+	if options.PhysicalExtentSize.Val > 0 {
+		if err := vg.changeExtentSize(options.PhysicalExtentSize); err != nil {
+			return err
+		}
+	}
+	if options.AutoActivation != "" {
+		vg.noAutoActivation = options.AutoActivation == lvm2go.SetNoAutoActivate
+	}
+	if options.ResizeableVolumeGroup != "" {
+		vg.notResizeable = options.ResizeableVolumeGroup == lvm2go.SetNotResizeable
+	}
+	if options.SystemID != "" {
+		vg.systemID = options.SystemID
+	} else if options.RemoveSystemID {
+		vg.systemID = ""
+	}
+	for _, lv := range vg.lvs {
+		switch options.ActivationState {
+		case lvm2go.Activate, lvm2go.ActivateExclusive, lvm2go.ActivateShared:
+			lv.activate(options.IgnoreActivationSkip)
+		case lvm2go.AutoActivate:
+			if !vg.noAutoActivation && !lv.noAutoActivation {
+				lv.activate(options.IgnoreActivationSkip)
+			}
+		case lvm2go.Deactivate:
+			lv.active = false
+		}
+	}
+	vg.tags = addTags(vg.tags, options.Tags, options.DelTags)
+	vg.seqNo++
+	return nil
+}
+
+// changeExtentSize rescales the segments of all logical volumes to the new extent size.
+// Like lvm2, it fails if a segment is not an exact number of new extents, as no data is moved.
+func (vg *volumeGroup) changeExtentSize(size lvm2go.PhysicalExtentSize) error {
+	if _, err := (lvm2go.VGChangeOptionsList{vg.name, size}).AsArgs(); err != nil {
+		return err
+	}
+	bytes, err := lvm2go.Size(size).ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return err
+	}
+	extentSize := uint64(bytes.Val)
+
+	var blocking []string
+	var lines []string
+	for _, lv := range vg.lvs {
+		for _, seg := range lv.segments {
+			if seg.extents*vg.extentSize%extentSize != 0 {
+				blocking = append(blocking, fmt.Sprintf("%s segment length", lv.name))
+				lines = append(lines, fmt.Sprintf("New size %d for %s segment length not an exact number of new extents.", seg.extents*vg.extentSize/512, lv.name))
+			}
+		}
+	}
+	if len(blocking) > 0 {
+		return &lvm2go.ExtentSizeChangeError{
+			VolumeGroup: vg.name,
+			ExtentSize:  size,
+			Blocking:    blocking,
+			Err:         lvmError("%s", strings.Join(lines, "\n")),
+		}
+	}
+
+	for _, lv := range vg.lvs {
+		for i := range lv.segments {
+			lv.segments[i].extents = lv.segments[i].extents * vg.extentSize / extentSize
+		}
+	}
+	vg.extentSize = extentSize
+	return nil
+}
+
+func (c *Client) mdaCount(vg *volumeGroup) (count int64) {
+	for _, name := range vg.pvs {
+		count += c.pvs[name].mdaCount()
+	}
+	return count
+}
+
+func (c *Client) mdaUsedCount(vg *volumeGroup) (count int64) {
+	for _, name := range vg.pvs {
+		count += c.pvs[name].mdaUsedCount()
+	}
+	return count
+}
@@ -0,0 +1,130 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// FileBackedVolumeGroup is a volume group on a single loopback device backed by a sparse file,
+// created with CreateFileBackedVG. It has to be torn down with Teardown after use.
+type FileBackedVolumeGroup struct {
+	Name       VolumeGroupName
+	LoopDevice LoopbackDevice
+
+	client    Client
+	pvCreated bool
+	vgCreated bool
+}
+
+// CreateFileBackedVG creates a sparse backing file of the given size at path, attaches a loopback device to it,
+// and creates a physical volume and a volume group with the given options on the device.
+// If path is empty, the backing file is created in the temporary directory. An existing file is never overwritten.
+// The name of the volume group has to be given with VolumeGroupName, the physical volume of the
+// loopback device is added to the options.
+// If any step fails, everything created so far is torn down, including the backing file.
+//
+// This is intended for development environments and test sandboxes, not for production data.
+func CreateFileBackedVG(ctx context.Context, client Client, path string, size Size, opts ...VGCreateOption) (*FileBackedVolumeGroup, error) {
+	options := VGCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCreateOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, ErrVolumeGroupNameRequired
+	}
+	if path != "" {
+		if _, err := os.Stat(path); err == nil {
+			return nil, fmt.Errorf("backing file %s already exists", path)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+	}
+
+	loop, err := CreateLoopbackDevice(size)
+	if err != nil {
+		return nil, err
+	}
+	vg := &FileBackedVolumeGroup{Name: options.VolumeGroupName, LoopDevice: loop, client: client}
+
+	create := func() error {
+		if err := loop.SetBackingFile(path); err != nil {
+			return err
+		}
+		if err := loop.FindFree(); err != nil {
+			return fmt.Errorf("failed to find free loopback device: %w", err)
+		}
+		if err := loop.Open(); err != nil {
+			return fmt.Errorf("failed to open loopback device: %w", err)
+		}
+
+		pv := PhysicalVolumeName(loop.Device())
+		if err := client.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: pv, CommonOptions: options.CommonOptions}); err != nil {
+			return fmt.Errorf("failed to create physical volume: %w", err)
+		}
+		vg.pvCreated = true
+
+		options.PhysicalVolumeNames = PhysicalVolumeNames{pv}
+		if err := client.VGCreate(ctx, &options); err != nil {
+			return fmt.Errorf("failed to create volume group: %w", err)
+		}
+		vg.vgCreated = true
+		return nil
+	}
+
+	if err := create(); err != nil {
+		// the backing file is only created by SetBackingFile, so it was not there before
+		if !loop.IsOpen() && loop.File() != "" {
+			err = errors.Join(err, os.Remove(loop.File()))
+		}
+		return nil, errors.Join(err, vg.Teardown(ctx))
+	}
+
+	return vg, nil
+}
+
+// Teardown removes the volume group including all of its logical volumes and the physical volume,
+// detaches the loopback device and removes its backing file.
+// Teardown continues on errors and returns all errors joined together.
+func (vg *FileBackedVolumeGroup) Teardown(ctx context.Context) error {
+	var errs []error
+
+	if vg.vgCreated {
+		if err := vg.client.VGRemove(ctx, vg.Name, Force(true)); err != nil && !IsNotFound(err) && !IsVolumeGroupNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove volume group: %w", err))
+		} else {
+			vg.vgCreated = false
+		}
+	}
+
+	if vg.pvCreated {
+		if err := vg.client.PVRemove(ctx, PhysicalVolumeName(vg.LoopDevice.Device()), Force(true)); err != nil && !IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove physical volume: %w", err))
+		} else {
+			vg.pvCreated = false
+		}
+	}
+
+	if err := vg.LoopDevice.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close loopback device %s: %w", vg.LoopDevice.Device(), err))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestCreateFileBackedVG_Validation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+
+	if _, err := CreateFileBackedVG(ctx, clnt, "", MustParseSize("64M")); !errors.Is(err, ErrVolumeGroupNameRequired) {
+		t.Fatalf("expected %v, got %v", ErrVolumeGroupNameRequired, err)
+	}
+
+	existing := filepath.Join(t.TempDir(), "existing.img")
+	if err := os.WriteFile(existing, []byte("data"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := CreateFileBackedVG(ctx, clnt, existing, MustParseSize("64M"), VolumeGroupName("vg")); err == nil {
+		t.Fatal("expected error for an existing backing file")
+	}
+	if data, err := os.ReadFile(existing); err != nil || string(data) != "data" {
+		t.Fatalf("existing backing file was modified: %q, %v", data, err)
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var ErrUnsupportedFilesystem = errors.New("unsupported filesystem")
+
+// RawCommandRunner is implemented by clients that run arbitrary commands through their exec layer,
+// such as the client returned by NewClient. Commands run this way honor the CommandRunner, nsenter
+// and environment options of the client.
+type RawCommandRunner interface {
+	RunRaw(ctx context.Context, process RawOutputProcessor, args ...string) error
+}
+
+// GenerateFilesystemUUID can be passed to SetFilesystemUUID to generate a new random UUID.
+const GenerateFilesystemUUID = ""
+
+// FilesystemUUID returns the filesystem type and UUID of the logical volume as reported by blkid.
+func FilesystemUUID(ctx context.Context, client Client, lv *FQLogicalVolumeName) (fsType, uuid string, err error) {
+	runner, device, err := filesystemCommandTarget(ctx, client, lv, false)
+	if err != nil {
+		return "", "", err
+	}
+	if fsType, err = blkidValue(ctx, runner, "TYPE", device); err != nil {
+		return "", "", fmt.Errorf("failed to detect filesystem on %s: %w", lv, err)
+	}
+	if uuid, err = blkidValue(ctx, runner, "UUID", device); err != nil {
+		return "", "", fmt.Errorf("failed to read filesystem uuid of %s: %w", lv, err)
+	}
+	return fsType, uuid, nil
+}
+
+// SetFilesystemUUID overrides the UUID of the filesystem on the logical volume with xfs_admin -U (xfs)
+// or tune2fs -U (ext2, ext3 and ext4). If uuid is GenerateFilesystemUUID, a new random UUID is generated.
+//
+// Snapshots and clones carry the UUID of their origin, so mounting them next to the origin fails
+// unless the UUID is changed (or ignored with the nouuid mount option for xfs, see SnapshotMountOptions).
+// The filesystem must not be mounted. Both tools refuse to change the UUID of a filesystem with a dirty log,
+// which is the case for snapshots of mounted filesystems: xfs has to be mounted and unmounted once
+// to replay the log, ext4 has to be checked with e2fsck first.
+//
+// The commands are executed through the exec layer of the client, which has to implement RawCommandRunner.
+func SetFilesystemUUID(ctx context.Context, client Client, lv *FQLogicalVolumeName, uuid string) error {
+	runner, device, err := filesystemCommandTarget(ctx, client, lv, true)
+	if err != nil {
+		return err
+	}
+	fsType, err := blkidValue(ctx, runner, "TYPE", device)
+	if err != nil {
+		return fmt.Errorf("failed to detect filesystem on %s: %w", lv, err)
+	}
+
+	var args []string
+	switch fsType {
+	case "xfs":
+		if uuid == GenerateFilesystemUUID {
+			uuid = "generate"
+		}
+		args = []string{"xfs_admin", "-U", uuid, device}
+	case "ext2", "ext3", "ext4":
+		if uuid == GenerateFilesystemUUID {
+			uuid = "random"
+		}
+		args = []string{"tune2fs", "-U", uuid, device}
+	case "":
+		return fmt.Errorf("%w: no filesystem found on %s", ErrUnsupportedFilesystem, lv)
+	default:
+		return fmt.Errorf("%w: cannot change uuid of %s filesystem on %s", ErrUnsupportedFilesystem, fsType, lv)
+	}
+
+	if _, err := runRawOutput(ctx, runner, args...); err != nil {
+		return fmt.Errorf("failed to change filesystem uuid of %s: %w", lv, err)
+	}
+	return nil
+}
+
+// RegenerateFilesystemUUID generates a new random UUID for the filesystem on the logical volume.
+// It is a shorthand for SetFilesystemUUID with GenerateFilesystemUUID.
+func RegenerateFilesystemUUID(ctx context.Context, client Client, lv *FQLogicalVolumeName) error {
+	return SetFilesystemUUID(ctx, client, lv, GenerateFilesystemUUID)
+}
+
+// filesystemCommandTarget returns the runner and device of the logical volume for commands on its filesystem.
+// The runner is the outermost RawCommandRunner of the client, so that the commands honor the context and locks
+// of wrapped clients. Changes of the filesystem are only allowed for logical volumes the client may change,
+// see guardLogicalVolume.
+func filesystemCommandTarget(ctx context.Context, client Client, lv *FQLogicalVolumeName, change bool) (RawCommandRunner, string, error) {
+	if err := lv.Validate(); err != nil {
+		return nil, "", err
+	}
+	runner, err := rawCommandRunner(client)
+	if err != nil {
+		return nil, "", err
+	}
+	if change {
+		if err := guardLogicalVolume(ctx, client, lv); err != nil {
+			return nil, "", err
+		}
+	}
+	return runner, lv.DevicePath(), nil
+}
+
+// blkidValue probes the device for the value of tag. A device without the tag results in an empty value.
+func blkidValue(ctx context.Context, runner RawCommandRunner, tag, device string) (string, error) {
+	value, err := runRawOutput(ctx, runner, "blkid", "-p", "-o", "value", "-s", tag, device)
+	// blkid exits with 2 if nothing was found on the device
+	if exitErr, ok := AsExitCodeError(err); ok && exitErr.ExitCode() == 2 {
+		return "", nil
+	}
+	return value, err
+}
+
+// runRawOutput runs the command with the runner and returns its trimmed output.
+func runRawOutput(ctx context.Context, runner RawCommandRunner, args ...string) (string, error) {
+	var output string
+	err := runner.RunRaw(ctx, func(out io.Reader) error {
+		data, err := io.ReadAll(out)
+		output = strings.TrimSpace(string(data))
+		return err
+	}, args...)
+	return output, err
+}
@@ -0,0 +1,128 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestSetFilesystemUUID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv, err := NewFQLogicalVolumeName("vg", "clone")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	blkid := func(tag, value string) CommandRecording {
+		return CommandRecording{
+			Command: "blkid",
+			Args:    []string{"-p", "-o", "value", "-s", tag, "/dev/vg/clone"},
+			Stdout:  value + "\n",
+		}
+	}
+
+	for name, tc := range map[string]struct {
+		uuid       string
+		recordings []CommandRecording
+		err        error
+	}{
+		"xfs generate": {
+			uuid: GenerateFilesystemUUID,
+			recordings: []CommandRecording{
+				blkid("TYPE", "xfs"),
+				{Command: "xfs_admin", Args: []string{"-U", "generate", "/dev/vg/clone"}},
+			},
+		},
+		"ext4 override": {
+			uuid: "0b4d3a2e-9f0c-4a59-8d5e-1c4f2a6b7e80",
+			recordings: []CommandRecording{
+				blkid("TYPE", "ext4"),
+				{Command: "tune2fs", Args: []string{"-U", "0b4d3a2e-9f0c-4a59-8d5e-1c4f2a6b7e80", "/dev/vg/clone"}},
+			},
+		},
+		"no filesystem": {
+			recordings: []CommandRecording{
+				{Command: "blkid", Args: []string{"-p", "-o", "value", "-s", "TYPE", "/dev/vg/clone"}, ExitCode: 2},
+			},
+			err: ErrUnsupportedFilesystem,
+		},
+		"btrfs": {
+			recordings: []CommandRecording{blkid("TYPE", "btrfs")},
+			err:        ErrUnsupportedFilesystem,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			clnt := NewClient(ReplayCommandRunner(tc.recordings))
+			if err := SetFilesystemUUID(ctx, clnt, lv, tc.uuid); !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+		})
+	}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{
+		blkid("TYPE", "xfs"),
+		blkid("UUID", "0b4d3a2e-9f0c-4a59-8d5e-1c4f2a6b7e80"),
+	}))
+	fsType, uuid, err := FilesystemUUID(ctx, clnt, lv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fsType != "xfs" || uuid != "0b4d3a2e-9f0c-4a59-8d5e-1c4f2a6b7e80" {
+		t.Fatalf("unexpected filesystem %s with uuid %s", fsType, uuid)
+	}
+
+	if err := RegenerateFilesystemUUID(ctx, NewDBusClient(), lv); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected unsupported client, got %v", err)
+	}
+}
+
+func TestFilesystemUUIDWithNoNsenter(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv, err := NewFQLogicalVolumeName("vg", "lv")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var commands [][]string
+	clnt := WithNoNsenter(NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		commands = append(commands, cmd.Args)
+		return io.NopCloser(strings.NewReader("ext4\n")), nil
+	})))
+
+	if _, _, err := FilesystemUUID(ctx, clnt, lv); err != nil {
+		t.Fatal(err)
+	}
+	if len(commands) == 0 {
+		t.Fatal("expected blkid to be run")
+	}
+	for _, command := range commands {
+		if command[0] != "blkid" || slices.Contains(command, "nsenter") {
+			t.Fatalf("expected blkid to run without nsenter, got %v", command)
+		}
+	}
+}
@@ -38,6 +38,18 @@ func (opt Force) ApplyToPVCreateOptions(opts *PVCreateOptions) {
 	opts.Force = opt
 }
 
+func (opt Force) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Force = opt
+}
+
+func (opt Force) ApplyToVGImportOptions(opts *VGImportOptions) {
+	opts.Force = opt
+}
+
+func (opt Force) ApplyToVGCfgRestoreOptions(opts *VGCfgRestoreOptions) {
+	opts.Force = opt
+}
+
 func (opt Force) ApplyToArgs(args Arguments) error {
 	if opt {
 		args.AddOrReplaceAll([]string{"--force"})
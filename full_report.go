@@ -0,0 +1,322 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+type (
+	// FullReportOptions limit the report to the volume groups matching the options.
+	// Orphan physical volumes are only reported if no volume group names are given.
+	FullReportOptions struct {
+		VolumeGroupName
+		VolumeGroupNames
+		Unit
+		ReportLocking
+		FullReportColumns
+
+		CommonOptions
+	}
+	FullReportOption interface {
+		ApplyToFullReportOptions(opts *FullReportOptions)
+	}
+	FullReportOptionsList []FullReportOption
+)
+
+var (
+	_ ArgumentGenerator = FullReportOptionsList{}
+	_ Argument          = (*FullReportOptions)(nil)
+)
+
+// FullReport is the topology of volume groups, physical volumes, logical volumes and their segments
+// taken from a single consistent report.
+type FullReport struct {
+	VolumeGroups []*VolumeGroupReport
+	// OrphanPhysicalVolumes are the physical volumes that are not part of any volume group.
+	OrphanPhysicalVolumes []*PhysicalVolumeReport
+}
+
+// VolumeGroupReport is a volume group together with its physical and logical volumes.
+type VolumeGroupReport struct {
+	*VolumeGroup
+	PhysicalVolumes []*PhysicalVolumeReport
+	LogicalVolumes  []*LogicalVolumeReport
+}
+
+// PhysicalVolumeReport is a physical volume together with its segments ordered by their start.
+type PhysicalVolumeReport struct {
+	*PhysicalVolume
+	Segments []*PhysicalVolumeSegment
+}
+
+// LogicalVolumeReport is a logical volume together with its segments ordered by their start.
+type LogicalVolumeReport struct {
+	*LogicalVolume
+	Segments []*LogicalVolumeSegment
+}
+
+// VolumeGroup returns the report of the volume group with the given name, nil if it is not part of the report.
+func (r *FullReport) VolumeGroup(name VolumeGroupName) *VolumeGroupReport {
+	for _, vg := range r.VolumeGroups {
+		if vg.Name == name {
+			return vg
+		}
+	}
+	return nil
+}
+
+// LogicalVolume returns the report of the logical volume with the given name, nil if it is not part of the report.
+func (r *VolumeGroupReport) LogicalVolume(name LogicalVolumeName) *LogicalVolumeReport {
+	for _, lv := range r.LogicalVolumes {
+		if lv.Name == name {
+			return lv
+		}
+	}
+	return nil
+}
+
+// PhysicalVolume returns the report of the physical volume with the given name, nil if it is not part of the report.
+func (r *VolumeGroupReport) PhysicalVolume(name PhysicalVolumeName) *PhysicalVolumeReport {
+	for _, pv := range r.PhysicalVolumes {
+		if pv.Name == name {
+			return pv
+		}
+	}
+	return nil
+}
+
+// NewFullReport correlates separately reported volume groups, physical volumes, logical volumes and segments
+// into a FullReport. Physical volume segments are correlated by the name of their physical volume,
+// logical volume segments by the UUID of their logical volume. Everything that cannot be correlated is omitted,
+// except physical volumes without volume group, which are orphans.
+func NewFullReport(
+	vgs []*VolumeGroup,
+	pvs []*PhysicalVolume,
+	lvs []*LogicalVolume,
+	pvSegments []*PhysicalVolumeSegment,
+	lvSegments []*LogicalVolumeSegment,
+) *FullReport {
+	report := &FullReport{}
+	vgIndex := make(map[VolumeGroupName]*VolumeGroupReport, len(vgs))
+	for _, vg := range vgs {
+		vgReport := &VolumeGroupReport{VolumeGroup: vg}
+		vgIndex[vg.Name] = vgReport
+		report.VolumeGroups = append(report.VolumeGroups, vgReport)
+	}
+
+	pvIndex := make(map[PhysicalVolumeName]*PhysicalVolumeReport, len(pvs))
+	for _, pv := range pvs {
+		pvReport := &PhysicalVolumeReport{PhysicalVolume: pv}
+		pvIndex[pv.Name] = pvReport
+		if pv.VGName == "" {
+			report.OrphanPhysicalVolumes = append(report.OrphanPhysicalVolumes, pvReport)
+		} else if vg, ok := vgIndex[pv.VGName]; ok {
+			vg.PhysicalVolumes = append(vg.PhysicalVolumes, pvReport)
+		}
+	}
+	for _, seg := range pvSegments {
+		if pv, ok := pvIndex[seg.PhysicalVolumeName]; ok {
+			pv.Segments = append(pv.Segments, seg)
+		}
+	}
+	for _, pv := range pvIndex {
+		slices.SortFunc(pv.Segments, func(a, b *PhysicalVolumeSegment) int {
+			return cmp.Compare(a.Start, b.Start)
+		})
+	}
+
+	lvIndex := make(map[string]*LogicalVolumeReport, len(lvs))
+	for _, lv := range lvs {
+		lvReport := &LogicalVolumeReport{LogicalVolume: lv}
+		lvIndex[lv.UUID] = lvReport
+		if vg, ok := vgIndex[lv.VolumeGroupName]; ok {
+			vg.LogicalVolumes = append(vg.LogicalVolumes, lvReport)
+		}
+	}
+	for _, seg := range lvSegments {
+		if lv, ok := lvIndex[seg.LogicalVolumeUUID]; ok {
+			seg.LogicalVolumeName = lv.Name
+			lv.Segments = append(lv.Segments, seg)
+		}
+	}
+	for _, lv := range lvIndex {
+		slices.SortFunc(lv.Segments, func(a, b *LogicalVolumeSegment) int {
+			return cmp.Compare(a.Start, b.Start)
+		})
+	}
+
+	return report
+}
+
+// fullReportEntry is the report of lvm fullreport for a single volume group, or for the orphan physical volumes.
+// Its physical volume segments are only identified by the UUIDs of their physical and logical volume,
+// and its physical and logical volumes do not carry the name of their volume group.
+type fullReportEntry struct {
+	VG    []*VolumeGroup           `json:"vg"`
+	PV    []*PhysicalVolume        `json:"pv"`
+	LV    []*LogicalVolume         `json:"lv"`
+	PVSeg []*PhysicalVolumeSegment `json:"pvseg"`
+	Seg   []*LogicalVolumeSegment  `json:"seg"`
+}
+
+// FullReport returns the topology of all volume groups matching the given options in one report.
+// It is really just a wrapper around the `lvm fullreport --reportformat json` command.
+func (c *client) FullReport(ctx context.Context, opts ...FullReportOption) (*FullReport, error) {
+	type fullReport struct {
+		Report []fullReportEntry `json:"report"`
+	}
+	res := new(fullReport)
+
+	args := []string{
+		"fullreport", "--reportformat", "json",
+	}
+	// the report locking of the client is applied first, so that it can be overridden per report
+	argsFromOpts, err := FullReportOptionsList(append([]FullReportOption{c.opts.ReportLocking}, opts...)).AsArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
+
+	// names that are not found are skipped, the other names are still reported
+	if IsNotFound(err) {
+		err = nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var vgs []*VolumeGroup
+	var pvs []*PhysicalVolume
+	var lvs []*LogicalVolume
+	var pvSegments []*PhysicalVolumeSegment
+	var lvSegments []*LogicalVolumeSegment
+	for _, entry := range res.Report {
+		entry.correlate()
+		vgs = append(vgs, entry.VG...)
+		pvs = append(pvs, entry.PV...)
+		lvs = append(lvs, entry.LV...)
+		pvSegments = append(pvSegments, entry.PVSeg...)
+		lvSegments = append(lvSegments, entry.Seg...)
+	}
+
+	return NewFullReport(vgs, pvs, lvs, pvSegments, lvSegments), nil
+}
+
+// correlate fills in the names the entry only references by UUID or implies by being the report of its volume group,
+// and drops the raw JSON captured while decoding.
+func (entry *fullReportEntry) correlate() {
+	var vgName VolumeGroupName
+	for _, vg := range entry.VG {
+		vgName = vg.Name
+		vg.raw = nil
+	}
+
+	pvNames := make(map[string]PhysicalVolumeName, len(entry.PV))
+	for _, pv := range entry.PV {
+		pv.VGName = vgName
+		pv.raw = nil
+		pvNames[pv.UUID] = pv.Name
+	}
+	lvNames := make(map[string]LogicalVolumeName, len(entry.LV))
+	for _, lv := range entry.LV {
+		lv.VolumeGroupName = vgName
+		lv.raw = nil
+		lvNames[lv.UUID] = lv.Name
+	}
+	for _, seg := range entry.PVSeg {
+		seg.VolumeGroupName = vgName
+		if name, ok := pvNames[seg.PhysicalVolumeUUID]; ok {
+			seg.PhysicalVolumeName = name
+		}
+		if name, ok := lvNames[seg.LogicalVolumeUUID]; ok {
+			seg.LogicalVolumeName = name
+		}
+	}
+}
+
+// InScope reports whether a volume group is within the scope of the names of the options.
+// It can be used by clients that filter reports themselves instead of passing the names to lvm.
+func (opts *FullReportOptions) InScope(vg VolumeGroupName) bool {
+	if opts.VolumeGroupName == "" && len(opts.VolumeGroupNames) == 0 {
+		return true
+	}
+	return opts.VolumeGroupName == vg || slices.Contains(opts.VolumeGroupNames, vg)
+}
+
+func (list FullReportOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := FullReportOptions{}
+	for _, opt := range list {
+		opt.ApplyToFullReportOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *FullReportOptions) ApplyToFullReportOptions(new *FullReportOptions) {
+	*new = *opts
+}
+
+func (opts *FullReportOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.VolumeGroupName,
+		opts.VolumeGroupNames,
+		opts.Unit,
+		opts.ReportLocking,
+		opts.FullReportColumns,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FullReportColumns limit the columns of the sub reports of FullReport, keyed by the name of the sub report
+// (vg, pv, lv, pvseg or seg). Sub reports without columns are reported with the default columns of lvm.
+// Fields that are not reported stay empty, so the columns used for correlation, e.g. vg_name and lv_uuid,
+// should be included.
+type FullReportColumns map[string]ColumnOptions
+
+func (opt FullReportColumns) ApplyToFullReportOptions(opts *FullReportOptions) {
+	opts.FullReportColumns = opt
+}
+
+func (opt FullReportColumns) ApplyToArgs(args Arguments) error {
+	reports := make([]string, 0, len(opt))
+	for report := range opt {
+		reports = append(reports, report)
+	}
+	// the sub reports are sorted to keep the arguments stable
+	slices.Sort(reports)
+	for _, report := range reports {
+		if len(opt[report]) == 0 {
+			continue
+		}
+		args.AddOrReplace(fmt.Sprintf("--configreport=%s", report), fmt.Sprintf("--options=%s", strings.Join(opt[report], ",")))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+const fullReportJSON = `{
+  "report": [
+    {
+      "vg": [{"vg_uuid":"vg-uuid", "vg_name":"vg", "vg_attr":"wz--n-", "vg_size":"200.00m", "vg_free":"80.00m", "vg_extent_size":"4.00m", "vg_extent_count":"50", "vg_free_count":"20", "lv_count":"1", "pv_count":"2"}],
+      "pv": [
+        {"pv_uuid":"sdb-uuid", "pv_name":"/dev/sdb", "pv_attr":"a--", "pv_size":"100.00m", "pv_free":"0"},
+        {"pv_uuid":"sdc-uuid", "pv_name":"/dev/sdc", "pv_attr":"a--", "pv_size":"100.00m", "pv_free":"80.00m"}
+      ],
+      "lv": [{"lv_uuid":"lv-uuid", "lv_name":"lv", "lv_full_name":"vg/lv", "lv_attr":"-wi-a-----", "lv_size":"120.00m"}],
+      "pvseg": [
+        {"pvseg_start":"5", "pvseg_size":"20", "pv_uuid":"sdc-uuid", "lv_uuid":""},
+        {"pvseg_start":"0", "pvseg_size":"5", "pv_uuid":"sdc-uuid", "lv_uuid":"lv-uuid"},
+        {"pvseg_start":"0", "pvseg_size":"25", "pv_uuid":"sdb-uuid", "lv_uuid":"lv-uuid"}
+      ],
+      "seg": [
+        {"segtype":"linear", "stripes":"1", "seg_start_pe":"25", "seg_size_pe":"5", "devices":"/dev/sdc(0)", "lv_uuid":"lv-uuid"},
+        {"segtype":"linear", "stripes":"1", "seg_start_pe":"0", "seg_size_pe":"25", "devices":"/dev/sdb(0)", "lv_uuid":"lv-uuid"}
+      ]
+    },
+    {
+      "vg": [],
+      "pv": [{"pv_uuid":"sdd-uuid", "pv_name":"/dev/sdd", "pv_attr":"---", "pv_size":"100.00m", "pv_free":"100.00m"}],
+      "lv": [],
+      "pvseg": [{"pvseg_start":"0", "pvseg_size":"0", "pv_uuid":"sdd-uuid", "lv_uuid":""}],
+      "seg": []
+    }
+  ]
+}`
+
+func TestFullReport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := FullReportOptionsList{VolumeGroupNames{"vg"}, UnitMiB}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"vg", "--units=m", "--yes"}
+	if !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    []string{"fullreport", "--reportformat", "json", "--yes"},
+		Stdout:  fullReportJSON,
+	}}))
+	report, err := clnt.FullReport(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.OrphanPhysicalVolumes) != 1 || report.OrphanPhysicalVolumes[0].Name != "/dev/sdd" {
+		t.Fatalf("expected /dev/sdd as orphan, got %v", report.OrphanPhysicalVolumes)
+	}
+	vg := report.VolumeGroup("vg")
+	if vg == nil || len(vg.PhysicalVolumes) != 2 {
+		t.Fatalf("expected vg with two physical volumes, got %v", vg)
+	}
+
+	lv := vg.LogicalVolume("lv")
+	if lv == nil || lv.VolumeGroupName != "vg" || len(lv.Segments) != 2 {
+		t.Fatalf("expected lv of vg with two segments, got %+v", lv)
+	}
+	if seg := lv.Segments[0]; seg.Start != 0 || seg.Size != 25 || seg.LogicalVolumeName != "lv" || !slices.Equal(seg.Devices, []string{"/dev/sdb(0)"}) {
+		t.Fatalf("unexpected first segment %+v", seg)
+	}
+
+	pv := vg.PhysicalVolume("/dev/sdc")
+	if pv == nil || pv.VGName != "vg" || len(pv.Segments) != 2 {
+		t.Fatalf("expected /dev/sdc of vg with two segments, got %+v", pv)
+	}
+	if seg := pv.Segments[0]; seg.Start != 0 || seg.PhysicalVolumeName != "/dev/sdc" || seg.LogicalVolumeName != "lv" || seg.VolumeGroupName != "vg" {
+		t.Fatalf("unexpected allocated segment %+v", seg)
+	}
+	if seg := pv.Segments[1]; seg.Start != 5 || !seg.IsFree() {
+		t.Fatalf("unexpected free segment %+v", seg)
+	}
+}
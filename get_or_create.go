@@ -0,0 +1,79 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GetOrCreateLV returns the report of the logical volume named by the options,
+// creating it with the options if it does not exist yet. created reports whether the logical volume was created.
+// An existing logical volume is returned as is, even if it does not match the other options.
+//
+// Clients returned by NewLockingClient hold their lock across the lookup and the creation, so that concurrent
+// reconcilers sharing the client cannot create the same logical volume twice and fail on each other.
+// Other clients, as well as callers in other processes, are not synchronized.
+func GetOrCreateLV(ctx context.Context, client Client, opts ...LVCreateOption) (lv *LogicalVolume, created bool, err error) {
+	options := LVCreateOptions{}
+	LVCreateOptionList(opts).ApplyToLVCreateOptions(&options)
+	if options.LogicalVolumeName == "" {
+		return nil, false, ErrLogicalVolumeNameRequired
+	}
+
+	err = atomically(client, func(client Client) error {
+		lv, err = client.LV(ctx, options.volumeGroupName(), options.LogicalVolumeName)
+		if !errors.Is(err, ErrLogicalVolumeNotFound) {
+			return err
+		}
+		lv, err = lvCreateAndGet(ctx, client, &options, opts)
+		created = err == nil
+		return err
+	})
+	return lv, created, err
+}
+
+// GetOrCreateVG returns the report of the volume group named by the options,
+// creating it with the options if it does not exist yet. created reports whether the volume group was created.
+// An existing volume group is returned as is, even if it does not match the other options.
+// Like GetOrCreateLV, the lookup and the creation are only atomic for clients returned by NewLockingClient.
+func GetOrCreateVG(ctx context.Context, client Client, opts ...VGCreateOption) (vg *VolumeGroup, created bool, err error) {
+	options := VGCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCreateOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		return nil, false, ErrVolumeGroupNameRequired
+	}
+
+	err = atomically(client, func(client Client) error {
+		vg, err = client.VG(ctx, options.VolumeGroupName)
+		if !errors.Is(err, ErrVolumeGroupNotFound) {
+			return err
+		}
+		if err = client.VGCreate(ctx, opts...); err != nil {
+			return err
+		}
+		if vg, err = client.VG(ctx, options.VolumeGroupName); err != nil {
+			return fmt.Errorf("failed to get volume group %s after creating it: %w", options.VolumeGroupName, err)
+		}
+		created = true
+		return nil
+	})
+	return vg, created, err
+}
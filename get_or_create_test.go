@@ -0,0 +1,71 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestGetOrCreate(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	backend := fake.NewClient()
+	if err := backend.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewLockingClient(backend)
+
+	// concurrent reconcilers sharing the client create the volume group and logical volume exactly once
+	var vgsCreated, lvsCreated atomic.Int32
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vg, created, err := GetOrCreateVG(ctx, clnt, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb"))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if created {
+				vgsCreated.Add(1)
+			}
+			lv, created, err := GetOrCreateLV(ctx, clnt, vg.Name, LogicalVolumeName("lv"), MustParseSize("8M"))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if created {
+				lvsCreated.Add(1)
+			}
+			if lv.Name != "lv" {
+				t.Errorf("unexpected logical volume %s", lv.Name)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if vgsCreated.Load() != 1 || lvsCreated.Load() != 1 {
+		t.Fatalf("expected a single creation, got %d volume groups and %d logical volumes", vgsCreated.Load(), lvsCreated.Load())
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/azalio/lvm2go
 
 go 1.22.5
+
+require github.com/godbus/dbus/v5 v5.1.0
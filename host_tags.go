@@ -0,0 +1,95 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// hostTagsConfig is the configuration of lvm2 that defines the host tag.
+type hostTagsConfig struct {
+	Tags struct {
+		HostTags int64 `lvm:"hosttags"`
+	} `lvm:"tags"`
+}
+
+// HostTagsEnabled reports whether tags/hosttags is set in the lvm2 configuration.
+// With host tags enabled, lvm2 defines a tag with the host name of the machine, which can be used
+// in activation/volume_list (e.g. volume_list = [ "@*" ]) to only activate volumes tagged for the host.
+func HostTagsEnabled(ctx context.Context, client Client) (bool, error) {
+	config := &hostTagsConfig{}
+	if err := client.ReadAndDecodeConfig(ctx, config, ConfigTypeFull); err != nil {
+		return false, fmt.Errorf("failed to read host tags configuration: %w", err)
+	}
+	return config.Tags.HostTags != 0, nil
+}
+
+// HostTag returns the tag lvm2 defines for the host if tags/hosttags is enabled, which is its host name.
+// The host name is read through the exec layer of the client, which has to implement RawCommandRunner,
+// so that it is the name of the host lvm2 runs on, e.g. when the client enters the host namespaces with nsenter.
+// Wrapped clients read it with their context, e.g. without nsenter for clients created with WithNoNsenter
+// like the lvm2 commands of the client.
+func HostTag(ctx context.Context, client Client) (string, error) {
+	runner, ok := ClientImplements[RawCommandRunner](client)
+	if !ok {
+		return "", fmt.Errorf("%w: client cannot run raw commands", errors.ErrUnsupported)
+	}
+	host, err := runRawOutput(ctx, runner, "uname", "-n")
+	if err != nil {
+		return "", fmt.Errorf("failed to read host name: %w", err)
+	}
+	return host, nil
+}
+
+// AddHostTag tags the volume groups for the host, following the HA-LVM convention of marking
+// the node that owns a volume group with its host tag. Together with host tags and
+// activation/volume_list = [ "@*" ], only the owning node can activate the volume groups.
+// All volume groups are tagged, errors are returned joined.
+func AddHostTag(ctx context.Context, client Client, host string, vgs ...VolumeGroupName) error {
+	return changeVolumeGroupTags(ctx, client, vgs, Tags{host})
+}
+
+// RemoveHostTag removes the tag of the host from the volume groups, releasing their ownership.
+// All volume groups are changed, errors are returned joined.
+func RemoveHostTag(ctx context.Context, client Client, host string, vgs ...VolumeGroupName) error {
+	return changeVolumeGroupTags(ctx, client, vgs, DelTags{host})
+}
+
+// MoveHostTag moves the ownership of the volume groups from one host to another, e.g. during a failover.
+// The tags are replaced with a single metadata update per volume group, so that no volume group is
+// owned by both or neither host at any time. All volume groups are changed, errors are returned joined.
+func MoveHostTag(ctx context.Context, client Client, from, to string, vgs ...VolumeGroupName) error {
+	return changeVolumeGroupTags(ctx, client, vgs, DelTags{from}, Tags{to})
+}
+
+// HostTaggedVolumeGroups returns the volume groups tagged for the host.
+func HostTaggedVolumeGroups(ctx context.Context, client Client, host string, opts ...VGsOption) ([]*VolumeGroup, error) {
+	return client.VGs(ctx, append(slices.Clone(opts), Tags{host})...)
+}
+
+func changeVolumeGroupTags(ctx context.Context, client Client, vgs []VolumeGroupName, opts ...VGChangeOption) error {
+	var errs []error
+	for _, vg := range vgs {
+		if err := client.VGChange(ctx, append([]VGChangeOption{vg}, opts...)...); err != nil {
+			errs = append(errs, fmt.Errorf("failed to change tags of volume group %s: %w", vg, err))
+		}
+	}
+	return errors.Join(errs...)
+}
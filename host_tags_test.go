@@ -0,0 +1,106 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestHostTags(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		output := "hosttags=1\n"
+		if slices.Contains(cmd.Args, "uname") {
+			output = "node1\n"
+		}
+		return io.NopCloser(strings.NewReader(output)), nil
+	}))
+	if enabled, err := HostTagsEnabled(ctx, clnt); err != nil || !enabled {
+		t.Fatalf("expected host tags to be enabled, got %v: %v", enabled, err)
+	}
+	if host, err := HostTag(ctx, clnt); err != nil || host != "node1" {
+		t.Fatalf("expected host tag node1, got %q: %v", host, err)
+	}
+
+	// the host name is read where the lvm2 commands of the client run
+	var unameArgs []string
+	noNsenter := WithNoNsenter(NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		unameArgs = cmd.Args
+		return io.NopCloser(strings.NewReader("container\n")), nil
+	})))
+	if host, err := HostTag(ctx, noNsenter); err != nil || host != "container" {
+		t.Fatalf("expected host tag container, got %q: %v", host, err)
+	}
+	if !slices.Equal(unameArgs, []string{"uname", "-n"}) {
+		t.Fatalf("expected uname to run without nsenter, got %v", unameArgs)
+	}
+
+	fakeClient := fake.NewClient()
+	for _, device := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := fakeClient.SetDevice(device, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for vg, device := range map[VolumeGroupName]string{"shared1": "/dev/sdb", "shared2": "/dev/sdc"} {
+		if err := fakeClient.VGCreate(ctx, vg, PhysicalVolumesFrom(device)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	owned := func(host string) []VolumeGroupName {
+		t.Helper()
+		vgs, err := HostTaggedVolumeGroups(ctx, fakeClient, host)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []VolumeGroupName
+		for _, vg := range vgs {
+			names = append(names, vg.Name)
+		}
+		return names
+	}
+
+	if err := AddHostTag(ctx, fakeClient, "node1", "shared1", "shared2"); err != nil {
+		t.Fatal(err)
+	}
+	if vgs := owned("node1"); len(vgs) != 2 {
+		t.Fatalf("expected node1 to own both volume groups, got %v", vgs)
+	}
+
+	if err := MoveHostTag(ctx, fakeClient, "node1", "node2", "shared2"); err != nil {
+		t.Fatal(err)
+	}
+	if vgs := owned("node2"); !slices.Equal(vgs, []VolumeGroupName{"shared2"}) {
+		t.Fatalf("expected node2 to own shared2, got %v", vgs)
+	}
+
+	if err := RemoveHostTag(ctx, fakeClient, "node1", "shared1", "missing"); err == nil {
+		t.Fatal("expected error for missing volume group")
+	}
+	if vgs := owned("node1"); len(vgs) != 0 {
+		t.Fatalf("expected node1 to own no volume groups, got %v", vgs)
+	}
+}
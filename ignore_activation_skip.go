@@ -0,0 +1,40 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// IgnoreActivationSkip activates logical volumes that are flagged to be skipped during activation,
+// such as thin snapshots. It is equivalent to -K.
+type IgnoreActivationSkip bool
+
+func (opt IgnoreActivationSkip) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--ignoreactivationskip"})
+	}
+	return nil
+}
+
+func (opt IgnoreActivationSkip) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.IgnoreActivationSkip = opt
+}
+
+func (opt IgnoreActivationSkip) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.IgnoreActivationSkip = opt
+}
+
+func (opt IgnoreActivationSkip) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.IgnoreActivationSkip = opt
+}
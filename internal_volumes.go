@@ -0,0 +1,65 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"regexp"
+	"strings"
+)
+
+// InternalType is the kind of a hidden logical volume that lvm2 creates internally for raid, mirror,
+// thin pool, cache or vdo volumes. Hidden logical volumes are only reported by LVs with All.
+// Their names are reported in brackets, e.g. [lv_rimage_0].
+type InternalType string
+
+const (
+	InternalTypeRaidImage         InternalType = "rimage"
+	InternalTypeRaidMetadata      InternalType = "rmeta"
+	InternalTypeMirrorImage       InternalType = "mimage"
+	InternalTypeMirrorLog         InternalType = "mlog"
+	InternalTypeThinPoolData      InternalType = "tdata"
+	InternalTypeThinPoolMetadata  InternalType = "tmeta"
+	InternalTypeCacheData         InternalType = "cdata"
+	InternalTypeCacheMetadata     InternalType = "cmeta"
+	InternalTypeCacheOrigin       InternalType = "corig"
+	InternalTypeVDOPoolData       InternalType = "vdata"
+	InternalTypeIntegrityMetadata InternalType = "imeta"
+	InternalTypePoolMetadataSpare InternalType = "pmspare"
+	// InternalTypeHidden is a hidden logical volume without one of the known suffixes,
+	// e.g. a cache pool attached to a cached logical volume.
+	InternalTypeHidden InternalType = "hidden"
+)
+
+// internalSuffix matches the suffix lvm2 gives internal logical volumes, e.g. _rimage_0 or _tdata.
+var internalSuffix = regexp.MustCompile(`_(rimage|rmeta|mimage|mlog|tdata|tmeta|cdata|cmeta|corig|vdata|imeta|pmspare)(_[0-9]+)?$`)
+
+// internalTypeOf returns the InternalType of a logical volume by its name as reported by lvm2,
+// and an empty InternalType for logical volumes that are not hidden.
+func internalTypeOf(name LogicalVolumeName) InternalType {
+	if !strings.HasPrefix(string(name), "[") || !strings.HasSuffix(string(name), "]") {
+		return ""
+	}
+	if match := internalSuffix.FindStringSubmatch(strings.Trim(string(name), "[]")); match != nil {
+		return InternalType(match[1])
+	}
+	return InternalTypeHidden
+}
+
+// IsInternal returns true if the logical volume is a hidden logical volume created internally by lvm2.
+func (lv *LogicalVolume) IsInternal() bool {
+	return lv.Internal != ""
+}
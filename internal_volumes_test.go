@@ -0,0 +1,70 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVsInternal(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	opts := LVsOptionsList{VolumeGroupName("vg"), All(true)}
+	args, err := opts.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    append([]string{"lvs", "--reportformat", "json"}, args.GetRaw()...),
+		Stdout: `{"report":[{"lv":[
+			{"lv_name":"raid","vg_name":"vg","lv_attr":"rwi-a-r---"},
+			{"lv_name":"[raid_rimage_0]","vg_name":"vg","lv_attr":"iwi-aor---"},
+			{"lv_name":"[raid_rmeta_0]","vg_name":"vg","lv_attr":"ewi-aor---"},
+			{"lv_name":"[pool_tdata]","vg_name":"vg","lv_attr":"Twi-ao----"},
+			{"lv_name":"[pool_tmeta]","vg_name":"vg","lv_attr":"ewi-ao----"},
+			{"lv_name":"[lvol0_pmspare]","vg_name":"vg","lv_attr":"ewi-------"},
+			{"lv_name":"[cpool]","vg_name":"vg","lv_attr":"Cwi---C---"}
+		]}]}`,
+	}}))
+
+	lvs, err := clnt.LVs(ctx, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[LogicalVolumeName]InternalType{
+		"raid":            "",
+		"[raid_rimage_0]": InternalTypeRaidImage,
+		"[raid_rmeta_0]":  InternalTypeRaidMetadata,
+		"[pool_tdata]":    InternalTypeThinPoolData,
+		"[pool_tmeta]":    InternalTypeThinPoolMetadata,
+		"[lvol0_pmspare]": InternalTypePoolMetadataSpare,
+		"[cpool]":         InternalTypeHidden,
+	}
+	if len(lvs) != len(expected) {
+		t.Fatalf("expected %d logical volumes, got %d", len(expected), len(lvs))
+	}
+	for _, lv := range lvs {
+		if lv.Internal != expected[lv.Name] || lv.IsInternal() != (expected[lv.Name] != "") {
+			t.Errorf("expected %s to be of internal type %q, got %q", lv.Name, expected[lv.Name], lv.Internal)
+		}
+	}
+}
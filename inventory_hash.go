@@ -0,0 +1,87 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"slices"
+)
+
+// InventoryHashColumns are the columns of the full report used by InventoryHash.
+// vg_seqno is incremented by lvm2 on every metadata change of a volume group,
+// lv_attr covers state changes that do not change metadata, e.g. activation,
+// and physical volumes are included to detect changes of physical volumes without a volume group.
+// The segments are not needed for the hash and reported with a single column only.
+var InventoryHashColumns = FullReportColumns{
+	"vg":    {"vg_name", "vg_uuid", "vg_seqno", "vg_attr"},
+	"lv":    {"lv_uuid", "lv_attr"},
+	"pv":    {"pv_uuid", "pv_attr"},
+	"pvseg": {"pvseg_start"},
+	"seg":   {"seg_start"},
+}
+
+// InventoryHash returns a hash over a minimal full report of all volume groups, logical volumes
+// and physical volumes on the host, so that every poll runs a single lvm command.
+//
+// The hash changes whenever the metadata of a volume group changes, a logical volume changes its state
+// (e.g. through activation) or a physical volume is added, removed or changes its attributes.
+// This allows watchers to cheaply detect that "something changed" before doing a full listing:
+//
+//	last, _ := InventoryHash(ctx, client)
+//	for range time.Tick(interval) {
+//		if current, err := InventoryHash(ctx, client); err == nil && current != last {
+//			last = current
+//			vgs, err := client.VGs(ctx) // full listing
+//		}
+//	}
+//
+// The hash is only meaningful for comparison with other results of InventoryHash on the same host.
+// It requires a client that supports FullReport.
+func InventoryHash(ctx context.Context, client Client) (uint64, error) {
+	report, err := client.FullReport(ctx, InventoryHashColumns)
+	if err != nil {
+		return 0, fmt.Errorf("failed to report inventory for inventory hash: %w", err)
+	}
+
+	var entries []string
+	for _, vg := range report.VolumeGroups {
+		entries = append(entries, fmt.Sprintf("vg:%s:%d:%s", vg.UUID, vg.SeqNo, vg.Attr))
+		for _, lv := range vg.LogicalVolumes {
+			entries = append(entries, fmt.Sprintf("lv:%s:%s", lv.UUID, lv.Attr))
+		}
+		for _, pv := range vg.PhysicalVolumes {
+			entries = append(entries, fmt.Sprintf("pv:%s:%s:%s", pv.UUID, pv.Attr, vg.Name))
+		}
+	}
+	for _, pv := range report.OrphanPhysicalVolumes {
+		entries = append(entries, fmt.Sprintf("pv:%s:%s:", pv.UUID, pv.Attr))
+	}
+
+	// reports are not guaranteed to be ordered, so the entries are sorted to make the hash stable
+	slices.Sort(entries)
+
+	hash := fnv.New64a()
+	for _, entry := range entries {
+		if _, err := hash.Write([]byte(entry + "\n")); err != nil {
+			return 0, err
+		}
+	}
+
+	return hash.Sum64(), nil
+}
@@ -0,0 +1,140 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestInventoryHashStableUnderReordering(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var commands [][]string
+	reports := func(reversed bool) Client {
+		entries := [][]string{
+			{
+				`"vg":[{"vg_name":"a", "vg_uuid":"vg-a", "vg_seqno":"3", "vg_attr":"wz--n-"}]`,
+				`"pv":[{"pv_uuid":"pv-a", "pv_attr":"a--"}]`,
+				`"lv":[{"lv_uuid":"lv-a", "lv_attr":"-wi-a-----"}, {"lv_uuid":"lv-b", "lv_attr":"-wi-------"}]`,
+			},
+			{
+				`"vg":[{"vg_name":"b", "vg_uuid":"vg-b", "vg_seqno":"7", "vg_attr":"wz--n-"}]`,
+				`"pv":[{"pv_uuid":"pv-b", "pv_attr":"a--"}]`,
+				`"lv":[{"lv_uuid":"lv-c", "lv_attr":"twi-aotz--"}]`,
+			},
+			{
+				`"vg":[]`,
+				`"pv":[{"pv_uuid":"pv-c", "pv_attr":"---"}, {"pv_uuid":"pv-d", "pv_attr":"---"}]`,
+			},
+		}
+		if reversed {
+			slices.Reverse(entries)
+			for _, entry := range entries {
+				slices.Reverse(entry)
+			}
+			// the orphans are reported in reverse order as well
+			entries[0][0] = `"pv":[{"pv_uuid":"pv-d", "pv_attr":"---"}, {"pv_uuid":"pv-c", "pv_attr":"---"}]`
+		}
+		var report []string
+		for _, entry := range entries {
+			report = append(report, "{"+strings.Join(entry, ",")+"}")
+		}
+		return NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+			commands = append(commands, cmd.Args)
+			return io.NopCloser(strings.NewReader(`{"report":[` + strings.Join(report, ",") + `]}`)), nil
+		}))
+	}
+
+	ordered, err := InventoryHash(ctx, reports(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reordered, err := InventoryHash(ctx, reports(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ordered != reordered {
+		t.Fatalf("expected hash to be independent of report order, got %d and %d", ordered, reordered)
+	}
+	if len(commands) != 2 {
+		t.Fatalf("expected a single command per hash, got %v", commands)
+	}
+	for _, arg := range []string{"fullreport", "--configreport=vg", "--options=vg_name,vg_uuid,vg_seqno,vg_attr", "--configreport=lv", "--options=lv_uuid,lv_attr"} {
+		if !slices.Contains(commands[0], arg) {
+			t.Fatalf("expected %q in %v", arg, commands[0])
+		}
+	}
+}
+
+func TestInventoryHashChanges(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	if err := clnt.SetDevice("/dev/sdb", MustParseSize("1G")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("64M")); err != nil {
+		t.Fatal(err)
+	}
+
+	last, err := InventoryHash(ctx, clnt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current, err := InventoryHash(ctx, clnt); err != nil || current != last {
+		t.Fatalf("expected hash to be stable without changes, got %d and %d: %v", last, current, err)
+	}
+
+	for _, change := range []struct {
+		name string
+		fn   func() error
+	}{
+		{"add logical volume", func() error {
+			return clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv2"), MustParseSize("64M"))
+		}},
+		{"resize logical volume", func() error {
+			return clnt.LVExtend(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), GrowBy(MustParseSize("64M")))
+		}},
+		{"add tag", func() error {
+			return clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Tags{"tag"})
+		}},
+	} {
+		if err := change.fn(); err != nil {
+			t.Fatalf("failed to %s: %v", change.name, err)
+		}
+		current, err := InventoryHash(ctx, clnt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if current == last {
+			t.Fatalf("expected hash to change after %s", change.name)
+		}
+		last = current
+	}
+}
@@ -0,0 +1,582 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxConcurrentJobs is the number of jobs the JobManager runs at the same time.
+	DefaultMaxConcurrentJobs = 1
+	// DefaultJobPollInterval is the interval in which the JobManager polls the progress of running jobs.
+	DefaultJobPollInterval = 10 * time.Second
+)
+
+var (
+	ErrJobNotFound    = errors.New("job not found")
+	ErrJobNotQueued   = errors.New("job is not queued")
+	ErrJobCanceled    = errors.New("job canceled")
+	ErrUnknownJobKind = errors.New("unknown job kind")
+)
+
+// JobKind is the operation run by a job of the JobManager.
+type JobKind string
+
+const (
+	// JobKindPVMove moves the extents of a physical volume with PVMove.
+	JobKindPVMove JobKind = "pvmove"
+	// JobKindLVConvert converts a logical volume with LVConvert, e.g. to raid1,
+	// and waits for the synchronization of the new images.
+	JobKindLVConvert JobKind = "lvconvert"
+	// JobKindScrub scrubs a raid logical volume with SyncAction and waits for it to complete.
+	JobKindScrub JobKind = "scrub"
+)
+
+// JobState is the state of a job of the JobManager.
+type JobState string
+
+const (
+	JobStateQueued    JobState = "queued"
+	JobStateRunning   JobState = "running"
+	JobStateSucceeded JobState = "succeeded"
+	JobStateFailed    JobState = "failed"
+	JobStateCanceled  JobState = "canceled"
+)
+
+// Done returns true if the job will not change its state anymore.
+func (state JobState) Done() bool {
+	return state == JobStateSucceeded || state == JobStateFailed || state == JobStateCanceled
+}
+
+// JobSpec describes the operation of a job. Only the fields of its Kind are used.
+// It is plain data, so that the queue of the JobManager can be persisted and resumed after a restart.
+type JobSpec struct {
+	Kind JobKind `json:"kind"`
+
+	// From is the physical volume moved by a pvmove, To the optional destinations.
+	// LogicalVolumeName restricts a pvmove to a single logical volume.
+	From PhysicalVolumeName  `json:"from,omitempty"`
+	To   PhysicalVolumeNames `json:"to,omitempty"`
+
+	// VolumeGroupName and LogicalVolumeName are the logical volume converted or scrubbed.
+	VolumeGroupName   VolumeGroupName   `json:"vg_name,omitempty"`
+	LogicalVolumeName LogicalVolumeName `json:"lv_name,omitempty"`
+
+	// Type, Mirrors and Stripes are the conversion of an lvconvert.
+	// Mirrors is nil if unset, so that a Mirrors of 0 converts to linear.
+	Type    Type     `json:"type,omitempty"`
+	Mirrors *Mirrors `json:"mirrors,omitempty"`
+	Stripes Stripes  `json:"stripes,omitempty"`
+
+	// SyncAction is the operation of a scrub, SyncActionCheck if empty.
+	SyncAction SyncAction `json:"sync_action,omitempty"`
+}
+
+func (spec JobSpec) Validate() error {
+	switch spec.Kind {
+	case JobKindPVMove:
+		if spec.From == "" {
+			return fmt.Errorf("from is empty: %w", ErrPhysicalVolumeNameRequired)
+		}
+		return nil
+	case JobKindLVConvert:
+		// the conversion is validated like LVConvert does, so that an invalid job is not queued
+		if _, err := spec.lvConvertOptions().AsArgs(); err != nil {
+			return fmt.Errorf("invalid lvconvert job: %w", err)
+		}
+	case JobKindScrub:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownJobKind, spec.Kind)
+	}
+	fq, err := NewFQLogicalVolumeName(spec.VolumeGroupName, spec.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	return fq.Validate()
+}
+
+// lvConvertOptions are the options of the lvconvert of the spec.
+func (spec JobSpec) lvConvertOptions() LVConvertOptionsList {
+	opts := LVConvertOptionsList{spec.VolumeGroupName, spec.LogicalVolumeName, spec.Type, spec.Stripes}
+	if spec.Mirrors != nil {
+		opts = append(opts, *spec.Mirrors)
+	}
+	return opts
+}
+
+func (spec JobSpec) String() string {
+	switch spec.Kind {
+	case JobKindPVMove:
+		return fmt.Sprintf("%s %s", spec.Kind, spec.From)
+	default:
+		return fmt.Sprintf("%s %s/%s", spec.Kind, spec.VolumeGroupName, spec.LogicalVolumeName)
+	}
+}
+
+// JobStatus is a snapshot of the state and progress of a job.
+type JobStatus struct {
+	ID    string   `json:"id"`
+	Spec  JobSpec  `json:"spec"`
+	State JobState `json:"state"`
+	// Progress is the progress of the running operation in percent.
+	Progress float64 `json:"progress"`
+	// Error is the error of a failed job.
+	Error string `json:"error,omitempty"`
+
+	SubmittedAt time.Time `json:"submitted_at"`
+	StartedAt   time.Time `json:"started_at,omitempty"`
+	FinishedAt  time.Time `json:"finished_at,omitempty"`
+}
+
+// Job is a job queued in a JobManager.
+type Job struct {
+	manager *JobManager
+	status  JobStatus
+	done    chan struct{}
+}
+
+// ID returns the id of the job, unique within its JobManager.
+func (j *Job) ID() string {
+	return j.status.ID
+}
+
+// Status returns a snapshot of the state and progress of the job.
+func (j *Job) Status() JobStatus {
+	j.manager.mu.Lock()
+	defer j.manager.mu.Unlock()
+	return j.status
+}
+
+// Cancel removes the job from the queue. Running jobs cannot be canceled, as lvm2 continues
+// their operation independently of the JobManager.
+func (j *Job) Cancel() error {
+	return j.manager.Cancel(j.ID())
+}
+
+// Wait blocks until the job is done. It returns the error of a failed job and ErrJobCanceled
+// if the job was canceled.
+func (j *Job) Wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-j.done:
+	}
+	status := j.Status()
+	switch status.State {
+	case JobStateFailed:
+		return fmt.Errorf("job %s (%s) failed: %s", status.ID, status.Spec, status.Error)
+	case JobStateCanceled:
+		return fmt.Errorf("job %s (%s): %w", status.ID, status.Spec, ErrJobCanceled)
+	}
+	return nil
+}
+
+// JobManager queues heavy operations such as pvmoves, conversions and scrubs and runs them
+// in the order of submission with a limited concurrency, so that disruptive storage work can be scheduled
+// without saturating the devices.
+//
+// If StateFile is set, the queue is persisted on every change and restored once the JobManager is first used.
+// Jobs that were running when the process exited are resumed: a pvmove still in progress is only tracked,
+// a pvmove that already finished succeeds and conversions and scrubs wait for the running synchronization
+// instead of starting over.
+//
+// Example:
+//
+//	manager := &JobManager{
+//		Client:            client,
+//		MaxConcurrentJobs: 2,
+//		MaxRecoveryRate:   MaxRecoveryRate(MustParseSize("100M")),
+//		StateFile:         "/var/lib/storage/jobs.json",
+//	}
+//	go manager.Run(ctx)
+//	job, err := manager.Submit(JobSpec{Kind: JobKindPVMove, From: "/dev/sdb"})
+type JobManager struct {
+	Client Client
+
+	// MaxConcurrentJobs is the number of jobs that run at the same time.
+	// If zero, DefaultMaxConcurrentJobs is used.
+	MaxConcurrentJobs int
+	// MaxRecoveryRate limits the synchronization of raid logical volumes by conversions and scrubs.
+	// lvm2 applies the limit per device, pvmoves cannot be limited.
+	MaxRecoveryRate MaxRecoveryRate
+	// StateFile is the file the queue is persisted to. If empty, the queue is not persisted.
+	StateFile string
+	// PollInterval is the interval in which the progress of running jobs is polled.
+	// If zero, DefaultJobPollInterval is used.
+	PollInterval time.Duration
+
+	mu     sync.Mutex
+	loaded bool
+	jobs   []*Job
+	nextID int
+	wake   chan struct{}
+}
+
+// jobManagerState is the persisted queue of a JobManager.
+type jobManagerState struct {
+	NextID int         `json:"next_id"`
+	Jobs   []JobStatus `json:"jobs"`
+}
+
+// Submit validates the spec and queues a new job for it.
+func (m *JobManager) Submit(spec JobSpec) (*Job, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+
+	m.nextID++
+	job := &Job{
+		manager: m,
+		status: JobStatus{
+			ID:          fmt.Sprintf("job-%d", m.nextID),
+			Spec:        spec,
+			State:       JobStateQueued,
+			SubmittedAt: time.Now(),
+		},
+		done: make(chan struct{}),
+	}
+	m.jobs = append(m.jobs, job)
+	if err := m.persist(); err != nil {
+		m.jobs = m.jobs[:len(m.jobs)-1]
+		return nil, err
+	}
+	m.notify()
+	return job, nil
+}
+
+// Job returns the job with the given id.
+func (m *JobManager) Job(id string) (*Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	for _, job := range m.jobs {
+		if job.status.ID == id {
+			return job, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrJobNotFound, id)
+}
+
+// Jobs returns the status of all jobs in the order of submission, including the ones that are done.
+func (m *JobManager) Jobs() ([]JobStatus, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.load(); err != nil {
+		return nil, err
+	}
+	statuses := make([]JobStatus, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		statuses = append(statuses, job.status)
+	}
+	return statuses, nil
+}
+
+// Cancel removes a queued job from the queue, see Job.Cancel.
+func (m *JobManager) Cancel(id string) error {
+	job, err := m.Job(id)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if job.status.State != JobStateQueued {
+		return fmt.Errorf("%w: %s is %s", ErrJobNotQueued, id, job.status.State)
+	}
+	if !job.status.StartedAt.IsZero() {
+		return fmt.Errorf("%w: %s was interrupted while running and is resumed", ErrJobNotQueued, id)
+	}
+	m.finish(job, JobStateCanceled, nil)
+	return nil
+}
+
+// Run starts queued jobs while less than MaxConcurrentJobs are running, until the context is canceled.
+// Once canceled, Run waits for the running jobs to return and leaves them in the running state,
+// so that they are resumed by the next Run. Run returns the error of the context.
+// Run must not be called concurrently.
+func (m *JobManager) Run(ctx context.Context) error {
+	maxJobs := m.MaxConcurrentJobs
+	if maxJobs <= 0 {
+		maxJobs = DefaultMaxConcurrentJobs
+	}
+
+	m.mu.Lock()
+	err := m.load()
+	wake := m.wake
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	running := 0
+	// buffered so that jobs returning after the cancellation never block
+	finished := make(chan struct{}, maxJobs)
+	for {
+		m.mu.Lock()
+		for _, job := range m.jobs {
+			if running >= maxJobs {
+				break
+			}
+			if job.status.State != JobStateQueued {
+				continue
+			}
+			resumed := !job.status.StartedAt.IsZero()
+			if !resumed {
+				job.status.StartedAt = time.Now()
+			}
+			job.status.State = JobStateRunning
+			m.persistOrLog(ctx)
+			running++
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				m.run(ctx, job, resumed)
+				finished <- struct{}{}
+			}()
+		}
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case <-finished:
+			running--
+		case <-wake:
+		}
+	}
+}
+
+// run runs the operation of the job and records its result.
+// A job interrupted by the cancellation of the context is queued again to be resumed.
+func (m *JobManager) run(ctx context.Context, job *Job, resumed bool) {
+	progress := func(percent float64) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		job.status.Progress = percent
+	}
+
+	var err error
+	switch spec := job.status.Spec; spec.Kind {
+	case JobKindPVMove:
+		err = m.pvmove(ctx, spec, resumed, progress)
+	case JobKindLVConvert:
+		err = m.lvconvert(ctx, spec, resumed, progress)
+	case JobKindScrub:
+		err = m.scrub(ctx, spec, resumed, progress)
+	default:
+		err = fmt.Errorf("%w: %q", ErrUnknownJobKind, spec.Kind)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	switch {
+	case ctx.Err() != nil:
+		job.status.State = JobStateQueued
+		m.persistOrLog(ctx)
+	case err != nil:
+		m.finish(job, JobStateFailed, err)
+	default:
+		job.status.Progress = 100
+		m.finish(job, JobStateSucceeded, nil)
+	}
+}
+
+func (m *JobManager) pvmove(ctx context.Context, spec JobSpec, resumed bool, progress func(float64)) error {
+	pvs, err := m.Client.PVs(ctx, PhysicalVolumeNames{spec.From})
+	if err != nil {
+		return err
+	}
+	if len(pvs) == 0 || pvs[0].VGName == "" {
+		return fmt.Errorf("physical volume %s is not part of a volume group", spec.From)
+	}
+	handle := &PVMoveHandle{
+		client:          m.Client,
+		From:            spec.From,
+		VolumeGroupName: pvs[0].VGName,
+		PollInterval:    m.pollInterval(),
+	}
+
+	// a move that is still in progress, e.g. after a restart, is only tracked
+	if _, done, err := handle.Progress(ctx); err != nil {
+		return err
+	} else if done {
+		opts := []PVMoveOption{spec.From, spec.To, spec.LogicalVolumeName, Background(true)}
+		if err := m.Client.PVMove(ctx, opts...); err != nil {
+			// a resumed move of a single logical volume that finished before the restart
+			// leaves no extents of the logical volume on the source physical volume
+			if IsNoDataToMove(err) || resumed && errors.Is(err, ErrLogicalVolumeNotOnPhysicalVolume) {
+				return nil
+			}
+			return err
+		}
+	}
+	return handle.Wait(ctx, progress)
+}
+
+func (m *JobManager) lvconvert(ctx context.Context, spec JobSpec, resumed bool, progress func(float64)) error {
+	if !resumed {
+		if err := m.Client.LVConvert(ctx, spec.lvConvertOptions()...); err != nil {
+			return err
+		}
+	}
+	if (spec.Mirrors == nil || *spec.Mirrors == 0) && spec.Type != TypeMirrored && !strings.HasPrefix(string(spec.Type), "raid") {
+		return nil
+	}
+	return m.sync(ctx, spec, progress)
+}
+
+func (m *JobManager) scrub(ctx context.Context, spec JobSpec, resumed bool, progress func(float64)) error {
+	if !resumed {
+		action := spec.SyncAction
+		if action == "" {
+			action = SyncActionCheck
+		}
+		if err := m.Client.LVChange(ctx, spec.VolumeGroupName, spec.LogicalVolumeName, action, m.MaxRecoveryRate); err != nil {
+			return err
+		}
+	}
+	return m.sync(ctx, spec, progress)
+}
+
+// sync limits the synchronization of the raid logical volume of the spec to MaxRecoveryRate and waits for it.
+func (m *JobManager) sync(ctx context.Context, spec JobSpec, progress func(float64)) error {
+	if m.MaxRecoveryRate.Val > 0 && spec.Kind != JobKindScrub {
+		if err := m.Client.LVChange(ctx, spec.VolumeGroupName, spec.LogicalVolumeName, m.MaxRecoveryRate); err != nil {
+			return err
+		}
+	}
+	fq, err := NewFQLogicalVolumeName(spec.VolumeGroupName, spec.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	_, err = WaitForSync(ctx, m.Client, fq, SyncPollInterval(m.pollInterval()), SyncProgress(func(_ string, percent float64) {
+		progress(percent)
+	}))
+	return err
+}
+
+func (m *JobManager) pollInterval() time.Duration {
+	if m.PollInterval == 0 {
+		return DefaultJobPollInterval
+	}
+	return m.PollInterval
+}
+
+// finish records the final state of the job. It has to be called with the lock held.
+func (m *JobManager) finish(job *Job, state JobState, err error) {
+	job.status.State = state
+	job.status.FinishedAt = time.Now()
+	if err != nil {
+		job.status.Error = err.Error()
+	}
+	close(job.done)
+	m.persistOrLog(context.Background())
+	m.notify()
+}
+
+// notify wakes up Run to schedule the queue again. It has to be called with the lock held.
+func (m *JobManager) notify() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+// load initializes the JobManager on first use and restores the queue from StateFile.
+// Jobs that were running are queued again to be resumed. It has to be called with the lock held.
+func (m *JobManager) load() error {
+	if m.loaded {
+		return nil
+	}
+	m.wake = make(chan struct{}, 1)
+
+	if m.StateFile != "" {
+		data, err := os.ReadFile(m.StateFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to read job state: %w", err)
+		}
+		if len(data) > 0 {
+			state := jobManagerState{}
+			if err := json.Unmarshal(data, &state); err != nil {
+				return fmt.Errorf("failed to decode job state %s: %w", m.StateFile, err)
+			}
+			m.nextID = state.NextID
+			for _, status := range state.Jobs {
+				if status.State == JobStateRunning {
+					status.State = JobStateQueued
+				}
+				m.jobs = append(m.jobs, &Job{manager: m, status: status, done: make(chan struct{})})
+			}
+		}
+	}
+
+	m.loaded = true
+	return nil
+}
+
+// persist writes the jobs that are not done to StateFile. It has to be called with the lock held.
+func (m *JobManager) persist() error {
+	if m.StateFile == "" {
+		return nil
+	}
+	state := jobManagerState{NextID: m.nextID, Jobs: []JobStatus{}}
+	for _, job := range m.jobs {
+		if !job.status.State.Done() {
+			state.Jobs = append(state.Jobs, job.status)
+		}
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	// write and rename, so that a crash never leaves a partially written state behind
+	tmp := m.StateFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write job state: %w", err)
+	}
+	if err := os.Rename(tmp, m.StateFile); err != nil {
+		return fmt.Errorf("failed to write job state: %w", err)
+	}
+	return nil
+}
+
+// persistOrLog persists the queue for changes that cannot be rolled back, logging errors instead of returning them.
+func (m *JobManager) persistOrLog(ctx context.Context) {
+	if err := m.persist(); err != nil {
+		clientLogger(m.Client).ErrorContext(ctx, "failed to persist job state", slog.String("error", err.Error()))
+	}
+}
@@ -0,0 +1,256 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestJobManager(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clnt := fake.NewClient()
+	for _, dev := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := clnt.SetDevice(dev, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	for _, lv := range []LogicalVolumeName{"data", "pool"} {
+		if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), lv, MustParseSize("8M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	state := filepath.Join(t.TempDir(), "jobs.json")
+	manager := &JobManager{Client: clnt, StateFile: state, PollInterval: time.Millisecond}
+
+	specs := []JobSpec{
+		{Kind: JobKindPVMove, From: "/dev/sdb", To: PhysicalVolumeNames{"/dev/sdc"}},
+		// the second move finds no data left and succeeds
+		{Kind: JobKindPVMove, From: "/dev/sdb"},
+		{Kind: JobKindLVConvert, VolumeGroupName: "vg", LogicalVolumeName: "pool", Type: TypeThinPool},
+		{Kind: JobKindScrub, VolumeGroupName: "vg", LogicalVolumeName: "data"},
+	}
+	for _, spec := range specs {
+		if _, err := manager.Submit(spec); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := manager.Submit(JobSpec{Kind: JobKindScrub}); err == nil {
+		t.Fatal("expected validation error for scrub without logical volume")
+	}
+	if _, err := manager.Submit(JobSpec{Kind: JobKindLVConvert, VolumeGroupName: "vg", LogicalVolumeName: "data", Stripes: 2}); err == nil {
+		t.Fatal("expected validation error for lvconvert with only Stripes")
+	}
+
+	// the queue survives a restart and is resumed by a new manager
+	manager = &JobManager{Client: clnt, StateFile: state, PollInterval: time.Millisecond}
+	jobs, err := manager.Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != len(specs) || jobs[0].ID != "job-1" || jobs[0].State != JobStateQueued {
+		t.Fatalf("unexpected restored jobs %v", jobs)
+	}
+	canceled, err := manager.Submit(JobSpec{Kind: JobKindPVMove, From: "/dev/sdc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if canceled.ID() != "job-5" {
+		t.Fatalf("expected job-5, got %s", canceled.ID())
+	}
+	if err := canceled.Cancel(); err != nil {
+		t.Fatal(err)
+	}
+	if err := canceled.Wait(ctx); !errors.Is(err, ErrJobCanceled) {
+		t.Fatalf("expected canceled job, got %v", err)
+	}
+
+	go func() {
+		_ = manager.Run(ctx)
+	}()
+
+	for _, status := range jobs {
+		job, err := manager.Job(status.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = job.Wait(ctx)
+		if status.Spec.Kind == JobKindScrub {
+			// the fake client does not model raid logical volumes
+			if err == nil || job.Status().State != JobStateFailed {
+				t.Fatalf("expected scrub of linear logical volume to fail, got %v", err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status := job.Status(); status.Progress != 100 || status.StartedAt.IsZero() || status.FinishedAt.IsZero() {
+			t.Fatalf("unexpected status of succeeded job %+v", status)
+		}
+	}
+
+	pool, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.Attr.VolumeType != VolumeTypeThinPool {
+		t.Fatalf("expected pool to be converted, got %s", pool.Attr)
+	}
+
+	// done jobs are not persisted
+	restored, err := (&JobManager{Client: clnt, StateFile: state}).Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(restored) != 0 {
+		t.Fatalf("expected no persisted jobs, got %v", restored)
+	}
+}
+
+func TestJobManagerConvertToLinear(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var mu sync.Mutex
+	var commands [][]string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Args)
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+	manager := &JobManager{Client: clnt, StateFile: filepath.Join(t.TempDir(), "jobs.json"), PollInterval: time.Millisecond}
+
+	mirrors := Mirrors(0)
+	job, err := manager.Submit(JobSpec{Kind: JobKindLVConvert, VolumeGroupName: "vg", LogicalVolumeName: "data", Mirrors: &mirrors})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.Submit(JobSpec{Kind: JobKindLVConvert, VolumeGroupName: "vg", LogicalVolumeName: "data"}); err == nil {
+		t.Fatal("expected validation error for lvconvert without conversion")
+	}
+
+	// the explicit zero mirrors survive a restart
+	manager = &JobManager{Client: clnt, StateFile: manager.StateFile, PollInterval: time.Millisecond}
+	jobs, err := manager.Jobs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(jobs) != 1 || jobs[0].Spec.Mirrors == nil || *jobs[0].Spec.Mirrors != 0 {
+		t.Fatalf("expected restored job with zero mirrors, got %+v", jobs)
+	}
+
+	go func() {
+		_ = manager.Run(ctx)
+	}()
+
+	if job, err = manager.Job(job.ID()); err != nil {
+		t.Fatal(err)
+	}
+	if err := job.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	// the conversion to linear does not wait for a synchronization
+	if len(commands) != 1 {
+		t.Fatalf("expected a single lvconvert, got %v", commands)
+	}
+	if i := slices.Index(commands[0], "--mirrors"); i < 0 || i+1 >= len(commands[0]) || commands[0][i+1] != "0" {
+		t.Fatalf("expected lvconvert --mirrors 0, got %v", commands[0])
+	}
+}
+
+func TestJobManagerResumeFinishedPVMove(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	clnt := fake.NewClient()
+	for _, dev := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := clnt.SetDevice(dev, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	for _, lv := range []LogicalVolumeName{"data", "other"} {
+		if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), lv, MustParseSize("8M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	spec := JobSpec{Kind: JobKindPVMove, From: "/dev/sdb", To: PhysicalVolumeNames{"/dev/sdc"}, LogicalVolumeName: "data"}
+	// the move finished before the process exited, but the job was still persisted as running
+	if err := clnt.PVMove(ctx, spec.From, spec.To, spec.LogicalVolumeName); err != nil {
+		t.Fatal(err)
+	}
+	state := filepath.Join(t.TempDir(), "jobs.json")
+	if err := os.WriteFile(state, []byte(`{"next_id":1,"jobs":[{"id":"job-1","state":"running","progress":40,
+		"spec":{"kind":"pvmove","from":"/dev/sdb","to":["/dev/sdc"],"lv_name":"data"},
+		"submitted_at":"2024-01-01T00:00:00Z","started_at":"2024-01-01T00:00:01Z"}]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := &JobManager{Client: clnt, StateFile: state, PollInterval: time.Millisecond}
+	// a new move of a logical volume that is not on the physical volume still fails
+	fresh, err := manager.Submit(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	go func() {
+		_ = manager.Run(ctx)
+	}()
+
+	resumed, err := manager.Job("job-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := resumed.Wait(ctx); err != nil {
+		t.Fatalf("expected resumed move to succeed, got %v", err)
+	}
+	if status := resumed.Status(); status.State != JobStateSucceeded || status.Progress != 100 {
+		t.Fatalf("unexpected status of resumed job %+v", status)
+	}
+	if err := fresh.Wait(ctx); err == nil || !strings.Contains(fresh.Status().Error, ErrLogicalVolumeNotOnPhysicalVolume.Error()) {
+		t.Fatalf("expected new move to fail with logical volume not on physical volume, got %v", err)
+	}
+}
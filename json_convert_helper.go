@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"strconv"
 	"strings"
+	"time"
 )
 
 func unmarshalToStringAndParseCommaSeparatedStrings(raw map[string]json.RawMessage, key string, fieldPtr *[]string) error {
@@ -37,6 +38,20 @@ func unmarshalToStringAndParseInt64(raw map[string]json.RawMessage, key string,
 	})
 }
 
+// unmarshalToStringAndParseTime parses report timestamps with ParseReportTime.
+// With --reportformat json_std, timestamps formatted as unix time (%s) are reported as numbers instead of strings.
+func unmarshalToStringAndParseTime(raw map[string]json.RawMessage, key string, fieldPtr *time.Time) error {
+	if val := raw[key]; len(val) > 0 && val[0] != '"' && string(val) != "null" {
+		seconds, err := strconv.ParseInt(string(val), 10, 64)
+		if err != nil {
+			return newReportFieldError(key, val, err)
+		}
+		*fieldPtr = time.Unix(seconds, 0)
+		return nil
+	}
+	return unmarshalToStringAndParse(raw, key, fieldPtr, ParseReportTime)
+}
+
 func unmarshalToStringAndParseFloat64(raw map[string]json.RawMessage, key string, fieldPtr *float64) error {
 	return unmarshalToStringAndParse(raw, key, fieldPtr, func(str string) (float64, error) {
 		return strconv.ParseFloat(str, 64)
@@ -55,7 +70,7 @@ func unmarshalToStringAndParse[T any](
 	}
 	var str string
 	if err := json.Unmarshal(raw[key], &str); err != nil {
-		return err
+		return newReportFieldError(key, raw[key], err)
 	}
 	if str == "" {
 		*fieldPtr = *new(T)
@@ -63,7 +78,7 @@ func unmarshalToStringAndParse[T any](
 	}
 	attrs, err := parse(str)
 	if err != nil {
-		return err
+		return newReportFieldError(key, raw[key], err)
 	}
 	*fieldPtr = attrs
 	return nil
@@ -0,0 +1,148 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultKernelMessagesLimit is the maximum number of kernel messages attached per device.
+	DefaultKernelMessagesLimit = 20
+	// DefaultKernelMessagesTimeout bounds the time spent fetching kernel messages after an IO error.
+	DefaultKernelMessagesTimeout = 5 * time.Second
+)
+
+// KernelMessages enables the correlation of IO errors with kernel messages for every command of the client.
+// If enabled and lvm2 reports an IO error such as "Error reading device /dev/sdb at 0 length 4096.",
+// the client fetches the recent kernel messages of the device with dmesg (or journalctl --dmesg as fallback)
+// and attaches them to the returned error as a KernelMessagesError.
+type KernelMessages bool
+
+func (opt KernelMessages) ApplyToClientOptions(opts *ClientOptions) {
+	opts.KernelMessages = opt
+}
+
+// KernelMessagesError is an error of lvm2 with the recent kernel messages
+// of the devices implicated in the error attached.
+type KernelMessagesError struct {
+	Err error
+	// Messages are the recent kernel messages of each device, keyed by the device path reported by lvm2.
+	Messages map[string][]string
+}
+
+func (e *KernelMessagesError) Error() string {
+	var sb strings.Builder
+	sb.WriteString(e.Err.Error())
+	for device, messages := range e.Messages {
+		sb.WriteString(fmt.Sprintf("\nkernel messages for %s:", device))
+		for _, message := range messages {
+			sb.WriteString("\n\t")
+			sb.WriteString(message)
+		}
+	}
+	return sb.String()
+}
+
+func (e *KernelMessagesError) Unwrap() error {
+	return e.Err
+}
+
+// AsKernelMessagesError returns the KernelMessagesError from the error if it exists
+// and a bool indicating if it is present or not.
+func AsKernelMessagesError(err error) (*KernelMessagesError, bool) {
+	var kernelMessagesErr *KernelMessagesError
+	ok := errors.As(err, &kernelMessagesErr)
+	return kernelMessagesErr, ok
+}
+
+// withKernelMessages attaches kernel messages to err if KernelMessages is enabled and err is an IO error.
+// If the kernel messages cannot be fetched, err is returned unchanged.
+func (c *client) withKernelMessages(ctx context.Context, err error) error {
+	if !c.opts.KernelMessages || err == nil {
+		return err
+	}
+	devices := IOErrorDevices(err)
+	if len(devices) == 0 {
+		return err
+	}
+
+	// the original context might already be canceled, e.g. due to the error itself
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultKernelMessagesTimeout)
+	defer cancel()
+
+	lines, fetchErr := c.kernelMessages(ctx)
+	if fetchErr != nil {
+		c.logger().DebugContext(ctx, "failed to fetch kernel messages for IO error", slog.String("error", fetchErr.Error()))
+		return err
+	}
+
+	messages := make(map[string][]string, len(devices))
+	for _, device := range devices {
+		messages[device] = filterKernelMessages(lines, device, DefaultKernelMessagesLimit)
+	}
+
+	return &KernelMessagesError{Err: err, Messages: messages}
+}
+
+// kernelMessages returns the kernel ring buffer through dmesg, falling back to journalctl --dmesg.
+func (c *client) kernelMessages(ctx context.Context) ([]string, error) {
+	var errs []error
+	for _, args := range [][]string{
+		{"dmesg"},
+		{"journalctl", "--dmesg", "--no-pager", "--quiet"},
+	} {
+		var lines []string
+		err := c.RunRaw(ctx, func(out io.Reader) error {
+			scanner := bufio.NewScanner(out)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+			return scanner.Err()
+		}, args...)
+		if err == nil {
+			return lines, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", args[0], err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+// filterKernelMessages returns the last limit lines that mention the device by its kernel name.
+// The name has to be delimited by characters that cannot be part of a device name, so that e.g. loop1 does not match loop10.
+func filterKernelMessages(lines []string, device string, limit int) []string {
+	pattern := regexp.MustCompile(`(^|[^\w-])` + regexp.QuoteMeta(filepath.Base(device)) + `($|[^\w-])`)
+	var matching []string
+	for _, line := range lines {
+		if pattern.MatchString(line) {
+			matching = append(matching, strings.TrimSpace(line))
+		}
+	}
+	if len(matching) > limit {
+		matching = matching[len(matching)-limit:]
+	}
+	return matching
+}
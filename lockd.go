@@ -0,0 +1,194 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// LockType is the lvmlockd lock manager of a shared volume group.
+// It is set on creation together with Shared, and can be changed with VGChange,
+// e.g. to LockTypeNone to convert a shared volume group back to a local one.
+type LockType string
+
+const (
+	// LockTypeSanlock uses sanlock leases stored on the shared devices of the volume group.
+	LockTypeSanlock LockType = "sanlock"
+	// LockTypeDLM uses the distributed lock manager of corosync.
+	LockTypeDLM LockType = "dlm"
+	// LockTypeNone removes the lock type from a volume group, making it a local volume group.
+	LockTypeNone LockType = "none"
+)
+
+func (opt LockType) ApplyToArgs(args Arguments) error {
+	switch opt {
+	case "":
+		return nil
+	case LockTypeSanlock, LockTypeDLM, LockTypeNone:
+		args.AddOrReplace(fmt.Sprintf("--locktype=%s", opt))
+		return nil
+	default:
+		return fmt.Errorf("invalid lock type %q", string(opt))
+	}
+}
+
+func (opt LockType) ApplyToVGCreateOptions(opts *VGCreateOptions) {
+	opts.LockType = opt
+}
+
+func (opt LockType) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.LockType = opt
+}
+
+// LockOpt passes options for special cases to lvmlockd, e.g. LockOptAuto when the locks of
+// all shared volume groups are started at boot, or LockOptForce to change the lock type of
+// a volume group whose locks cannot be acquired. It can be passed to all commands.
+type LockOpt string
+
+const (
+	LockOptAuto     LockOpt = "auto"
+	LockOptAutoWait LockOpt = "autowait"
+	LockOptNoDelay  LockOpt = "nodelay"
+	LockOptForce    LockOpt = "force"
+)
+
+func (opt LockOpt) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--lockopt=%s", opt))
+	return nil
+}
+
+func (opt LockOpt) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVRenameOptions(opts *LVRenameOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVChangeOptions(opts *PVChangeOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVMoveOptions(opts *PVMoveOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVRemoveOptions(opts *PVRemoveOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVResizeOptions(opts *PVResizeOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGCreateOptions(opts *VGCreateOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGExtendOptions(opts *VGExtendOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGReduceOptions(opts *VGReduceOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGRenameOptions(opts *VGRenameOptions) {
+	opts.LockOpt = opt
+}
+
+func (opt LockOpt) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.LockOpt = opt
+}
+
+// LockStart starts the lockspace of shared volume groups in lvmlockd with VGChange, which is required
+// on every host before it can use them. Without a VolumeGroupName, the lockspaces of all shared
+// volume groups are started.
+type LockStart bool
+
+func (opt LockStart) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--lockstart"})
+	}
+	return nil
+}
+
+func (opt LockStart) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.LockStart = opt
+}
+
+// LockStop stops the lockspace of shared volume groups in lvmlockd with VGChange, e.g. before a host
+// leaves the cluster. Logical volumes of the volume groups have to be deactivated before.
+type LockStop bool
+
+func (opt LockStop) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--lockstop"})
+	}
+	return nil
+}
+
+func (opt LockStop) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.LockStop = opt
+}
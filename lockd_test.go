@@ -0,0 +1,92 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestLockd(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := VGCreateOptionList{
+		VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}, Shared(true), LockTypeSanlock,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--shared", "--locktype=sanlock"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	if _, err := (VGCreateOptionList{VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}, LockTypeDLM}).AsArgs(); err == nil {
+		t.Fatal("expected LockType to require Shared")
+	}
+
+	args, err = VGChangeOptionsList{LockStart(true), LockOptAuto}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--lockstart", "--lockopt=auto"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), LockStart(true), LockStop(true)}).AsArgs(); err == nil {
+		t.Fatal("expected LockStart and LockStop to be mutually exclusive")
+	}
+
+	args, err = LVChangeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("lv"), ActivateExclusive}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := args.GetRaw(); !slices.Equal(raw[len(raw)-2:], []string{"--activate", "ey"}) {
+		t.Fatalf("expected exclusive activation, got %v", args.GetRaw())
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("100M"))
+	if err := clnt.VGCreate(ctx, VolumeGroupName("shared"), PhysicalVolumeNames{"/dev/sdb"}, Shared(true)); err == nil {
+		t.Fatal("expected the fake client to reject shared volume groups")
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("40M"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGChange(ctx, LockStart(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGChange(ctx, VolumeGroupName("vg"), ActivateShared); err != nil {
+		t.Fatal(err)
+	}
+	lv, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Attr.State != StateActive {
+		t.Fatalf("expected the logical volume to be active, got %s", lv.Attr)
+	}
+}
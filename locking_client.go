@@ -21,7 +21,49 @@ func NewLockingClient(clnt Client) Client {
 	return lc
 }
 
-var _ Client = &lockingClient{}
+var (
+	_ WrappedClient    = &lockingClient{}
+	_ atomicClient     = &lockingClient{}
+	_ RawCommandRunner = &lockingClient{}
+)
+
+// atomicClient is implemented by clients that can run a sequence of calls
+// without other calls of the same client in between.
+type atomicClient interface {
+	atomically(fn func(clnt Client) error) error
+}
+
+// atomically runs fn with the write lock held if client is a locking client.
+// fn has to use the client it is called with, calls of the locking client itself would deadlock.
+// Other clients run fn without any synchronization.
+func atomically(client Client, fn func(clnt Client) error) error {
+	if atomic, ok := client.(atomicClient); ok {
+		return atomic.atomically(fn)
+	}
+	return fn(client)
+}
+
+func (l *lockingClient) atomically(fn func(clnt Client) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return fn(l.clnt)
+}
+
+// Unwrap implements WrappedClient.
+func (l *lockingClient) Unwrap() Client {
+	return l.clnt
+}
+
+// RunRaw implements RawCommandRunner. Raw commands can change anything, so they hold the write lock.
+func (l *lockingClient) RunRaw(ctx context.Context, process RawOutputProcessor, args ...string) error {
+	runner, err := rawCommandRunner(l.clnt)
+	if err != nil {
+		return err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return runner.RunRaw(ctx, process, args...)
+}
 
 func (l *lockingClient) LV(ctx context.Context, opts ...LVsOption) (*LogicalVolume, error) {
 	l.mu.RLock()
@@ -35,6 +77,12 @@ func (l *lockingClient) LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalV
 	return l.clnt.LVs(ctx, opts...)
 }
 
+func (l *lockingClient) LVSegments(ctx context.Context, opts ...LVsOption) ([]*LogicalVolumeSegment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.clnt.LVSegments(ctx, opts...)
+}
+
 func (l *lockingClient) LVCreate(ctx context.Context, opts ...LVCreateOption) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -77,6 +125,18 @@ func (l *lockingClient) LVChange(ctx context.Context, opts ...LVChangeOption) er
 	return l.clnt.LVChange(ctx, opts...)
 }
 
+func (l *lockingClient) LVScan(ctx context.Context, opts ...LVScanOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.LVScan(ctx, opts...)
+}
+
+func (l *lockingClient) LVConvert(ctx context.Context, opts ...LVConvertOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.LVConvert(ctx, opts...)
+}
+
 func (l *lockingClient) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error) {
 	l.mu.RLock()
 	defer l.mu.RUnlock()
@@ -119,6 +179,60 @@ func (l *lockingClient) VGRename(ctx context.Context, opts ...VGRenameOption) er
 	return l.clnt.VGRename(ctx, opts...)
 }
 
+func (l *lockingClient) VGSplit(ctx context.Context, opts ...VGSplitOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGSplit(ctx, opts...)
+}
+
+func (l *lockingClient) VGMerge(ctx context.Context, opts ...VGMergeOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGMerge(ctx, opts...)
+}
+
+func (l *lockingClient) VGExport(ctx context.Context, opts ...VGExportOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGExport(ctx, opts...)
+}
+
+func (l *lockingClient) VGImport(ctx context.Context, opts ...VGImportOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGImport(ctx, opts...)
+}
+
+func (l *lockingClient) VGImportClone(ctx context.Context, opts ...VGImportCloneOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGImportClone(ctx, opts...)
+}
+
+func (l *lockingClient) VGCfgBackup(ctx context.Context, opts ...VGCfgBackupOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGCfgBackup(ctx, opts...)
+}
+
+func (l *lockingClient) VGCfgRestore(ctx context.Context, opts ...VGCfgRestoreOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGCfgRestore(ctx, opts...)
+}
+
+func (l *lockingClient) VGCheck(ctx context.Context, opts ...VGCheckOption) (*VGCheckResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGCheck(ctx, opts...)
+}
+
+func (l *lockingClient) VGScan(ctx context.Context, opts ...VGScanOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.VGScan(ctx, opts...)
+}
+
 func (l *lockingClient) VGChange(ctx context.Context, opts ...VGChangeOption) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -131,6 +245,12 @@ func (l *lockingClient) PVs(ctx context.Context, opts ...PVsOption) ([]*Physical
 	return l.clnt.PVs(ctx, opts...)
 }
 
+func (l *lockingClient) PVSegments(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolumeSegment, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.clnt.PVSegments(ctx, opts...)
+}
+
 func (l *lockingClient) PVCreate(ctx context.Context, opts ...PVCreateOption) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -149,6 +269,18 @@ func (l *lockingClient) PVResize(ctx context.Context, opts ...PVResizeOption) er
 	return l.clnt.PVResize(ctx, opts...)
 }
 
+func (l *lockingClient) PVCheck(ctx context.Context, opts ...PVCheckOption) (*PVCheckResult, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.PVCheck(ctx, opts...)
+}
+
+func (l *lockingClient) PVScan(ctx context.Context, opts ...PVScanOption) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.PVScan(ctx, opts...)
+}
+
 func (l *lockingClient) PVChange(ctx context.Context, opts ...PVChangeOption) error {
 	l.mu.Lock()
 	defer l.mu.Unlock()
@@ -173,7 +305,7 @@ func (l *lockingClient) DevCheck(ctx context.Context, opts ...DevCheckOption) er
 	return l.clnt.DevCheck(ctx, opts...)
 }
 
-func (l *lockingClient) DevUpdate(ctx context.Context, opts ...DevUpdateOption) error {
+func (l *lockingClient) DevUpdate(ctx context.Context, opts ...DevUpdateOption) (*DevUpdateResult, error) {
 	l.mu.Lock()
 	defer l.mu.Unlock()
 	return l.clnt.DevUpdate(ctx, opts...)
@@ -248,3 +380,20 @@ func (l *lockingClient) GetProfileDirectory(ctx context.Context) (string, error)
 	// no locking needed
 	return l.clnt.GetProfileDirectory(ctx)
 }
+
+func (l *lockingClient) Defaults(ctx context.Context) Defaults {
+	// no locking needed
+	return l.clnt.Defaults(ctx)
+}
+
+func (l *lockingClient) FullReport(ctx context.Context, opts ...FullReportOption) (*FullReport, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	return l.clnt.FullReport(ctx, opts...)
+}
+
+func (l *lockingClient) LVMDump(ctx context.Context, opts ...LVMDumpOption) (string, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.clnt.LVMDump(ctx, opts...)
+}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 )
 
 var ErrVolumeGroupNameRequired = errors.New("VolumeGroupName is required for a fully qualified logical volume")
@@ -38,14 +39,58 @@ type LogicalVolume struct {
 	Attr LVAttributes `json:"lv_attr"`
 	Size Size         `json:"lv_size"`
 
+	MetadataSize Size `json:"lv_metadata_size"`
+
 	Origin            string `json:"origin"`
 	OriginSize        Size   `json:"origin_size"`
 	PoolLogicalVolume string `json:"pool_lv"`
+	// WhenFull is the behavior of a thin pool running out of data space, see ErrorWhenFull.
+	WhenFull WhenFull `json:"lv_when_full"`
+
+	// AutoActivation reports whether the logical volume is activated by event based autoactivation,
+	// if autoactivation is also enabled for its volume group. See VolumeGroup.AutoActivation.
+	AutoActivation AutoActivationFromReport `json:"lv_autoactivation"`
+
+	// CreationTime is the time the logical volume was created, see ParseReportTime.
+	CreationTime time.Time `json:"lv_time"`
+	// RemovalTime is the time a historical logical volume was removed, zero for other logical volumes.
+	RemovalTime time.Time `json:"lv_time_removed"`
 
 	VolumeGroupName VolumeGroupName `json:"vg_name"`
 
 	DataPercent     float64 `json:"data_percent"`
 	MetadataPercent float64 `json:"metadata_percent"`
+	// SnapPercent is the fill of the copy-on-write space of a snapshot. A snapshot becomes invalid at 100%.
+	// Thin snapshots allocate from their thin pool instead and report DataPercent.
+	SnapPercent float64 `json:"snap_percent"`
+
+	// RaidSyncAction is the current synchronization action of raid logical volumes,
+	// e.g. idle, resync, recover, check or repair. See SyncAction.
+	RaidSyncAction string `json:"raid_sync_action"`
+	// SyncPercent is the progress of the current synchronization action of raid and mirror logical volumes.
+	SyncPercent float64 `json:"sync_percent"`
+	// RaidMismatchCount is the number of inconsistent regions found by the last check of a raid logical volume.
+	RaidMismatchCount int64 `json:"raid_mismatch_count"`
+
+	// MovePV is the physical volume the extents of the logical volume are moved from by a running pvmove.
+	MovePV PhysicalVolumeName `json:"move_pv"`
+	// CopyPercent is the progress of the running pvmove of the logical volume, see MovePV.
+	CopyPercent float64 `json:"copy_percent"`
+
+	// Internal is the kind of hidden logical volumes, which are only reported with All.
+	// It is empty for all other logical volumes. See InternalType.
+	Internal InternalType `json:"-"`
+
+	// ExtraFields are the reported fields that are not covered by the struct, see ExtraColumns.
+	ExtraFields ExtraFields `json:"-"`
+
+	raw RawJSON
+}
+
+// RawJSON returns the raw JSON of the logical volume as reported by lvm.
+// It is only retained by report calls with RetainRawJSON, otherwise it is nil.
+func (lv *LogicalVolume) RawJSON() RawJSON {
+	return lv.raw
 }
 
 func (lv *LogicalVolume) UnmarshalJSON(data []byte) error {
@@ -53,20 +98,25 @@ func (lv *LogicalVolume) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	lv.raw = rawJSON(data)
 
 	for key, fieldPtr := range map[string]*string{
-		"lv_uuid":      &lv.UUID,
-		"lv_name":      (*string)(&lv.Name),
-		"lv_full_name": &lv.FullName,
-		"lv_path":      &lv.Path,
-		"origin":       &lv.Origin,
-		"pool_lv":      &lv.PoolLogicalVolume,
-		"vg_name":      (*string)(&lv.VolumeGroupName),
+		"lv_uuid":           &lv.UUID,
+		"lv_name":           (*string)(&lv.Name),
+		"lv_full_name":      &lv.FullName,
+		"lv_path":           &lv.Path,
+		"origin":            &lv.Origin,
+		"pool_lv":           &lv.PoolLogicalVolume,
+		"lv_autoactivation": (*string)(&lv.AutoActivation),
+		"lv_when_full":      (*string)(&lv.WhenFull),
+		"raid_sync_action":  &lv.RaidSyncAction,
+		"move_pv":           (*string)(&lv.MovePV),
+		"vg_name":           (*string)(&lv.VolumeGroupName),
 	} {
 		if val, ok := raw[key]; !ok {
 			continue
 		} else if err := json.Unmarshal(val, fieldPtr); err != nil {
-			return err
+			return newReportFieldError(key, val, err)
 		}
 	}
 
@@ -79,8 +129,9 @@ func (lv *LogicalVolume) UnmarshalJSON(data []byte) error {
 	}
 
 	for key, fieldPtr := range map[string]*int64{
-		"lv_kernel_major": &lv.Major,
-		"lv_kernel_minor": &lv.Minor,
+		"lv_kernel_major":     &lv.Major,
+		"lv_kernel_minor":     &lv.Minor,
+		"raid_mismatch_count": &lv.RaidMismatchCount,
 	} {
 		if err := unmarshalToStringAndParseInt64(raw, key, fieldPtr); err != nil {
 			return err
@@ -90,6 +141,9 @@ func (lv *LogicalVolume) UnmarshalJSON(data []byte) error {
 	for key, fieldPtr := range map[string]*float64{
 		"data_percent":     &lv.DataPercent,
 		"metadata_percent": &lv.MetadataPercent,
+		"snap_percent":     &lv.SnapPercent,
+		"sync_percent":     &lv.SyncPercent,
+		"copy_percent":     &lv.CopyPercent,
 	} {
 		if err := unmarshalToStringAndParseFloat64(raw, key, fieldPtr); err != nil {
 			return err
@@ -97,14 +151,26 @@ func (lv *LogicalVolume) UnmarshalJSON(data []byte) error {
 	}
 
 	for key, fieldPtr := range map[string]*Size{
-		"lv_size":     &lv.Size,
-		"origin_size": &lv.OriginSize,
+		"lv_size":          &lv.Size,
+		"lv_metadata_size": &lv.MetadataSize,
+		"origin_size":      &lv.OriginSize,
 	} {
 		if err := unmarshalToStringAndParse(raw, key, fieldPtr, ParseSizeLenient); err != nil {
 			return err
 		}
 	}
 
+	for key, fieldPtr := range map[string]*time.Time{
+		"lv_time":         &lv.CreationTime,
+		"lv_time_removed": &lv.RemovalTime,
+	} {
+		if err := unmarshalToStringAndParseTime(raw, key, fieldPtr); err != nil {
+			return err
+		}
+	}
+
+	lv.Internal = internalTypeOf(lv.Name)
+
 	return unmarshalToStringAndParse(raw, "lv_attr", &lv.Attr, ParseLVAttributes)
 }
 
@@ -142,6 +208,10 @@ func (opt LogicalVolumeName) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.LogicalVolumeName = opt
 }
 
+func (opt LogicalVolumeName) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.LogicalVolumeName = opt
+}
+
 func (opt LogicalVolumeName) ApplyToLVsOptions(opts *LVsOptions) {
 	opts.LogicalVolumeName = opt
 }
@@ -154,6 +224,10 @@ func (opt LogicalVolumeName) ApplyToPVMoveOptions(opts *PVMoveOptions) {
 	opts.LogicalVolumeName = opt
 }
 
+func (opt LogicalVolumeName) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.LogicalVolumeName = opt
+}
+
 type FQLogicalVolumeName struct {
 	VolumeGroupName
 	LogicalVolumeName
@@ -175,6 +249,10 @@ func (opt *FQLogicalVolumeName) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.VolumeGroupName, opts.LogicalVolumeName = opt.VolumeGroupName, opt.LogicalVolumeName
 }
 
+func (opt *FQLogicalVolumeName) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.VolumeGroupName, opts.LogicalVolumeName = opt.VolumeGroupName, opt.LogicalVolumeName
+}
+
 func (opt *FQLogicalVolumeName) ApplyToLVResizeOptions(opts *LVResizeOptions) {
 	opts.VolumeGroupName, opts.LogicalVolumeName = opt.VolumeGroupName, opt.LogicalVolumeName
 }
@@ -192,6 +270,22 @@ func (opt *FQLogicalVolumeName) ApplyToLVsOptions(opts *LVsOptions) {
 	opts.VolumeGroupName, opts.LogicalVolumeName = opt.VolumeGroupName, opt.LogicalVolumeName
 }
 
+// FQLogicalVolumeNames limit reports to the given logical volumes.
+type FQLogicalVolumeNames []*FQLogicalVolumeName
+
+func (opt FQLogicalVolumeNames) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.FQLogicalVolumeNames = append(opts.FQLogicalVolumeNames, opt...)
+}
+
+func (opt FQLogicalVolumeNames) ApplyToArgs(args Arguments) error {
+	for _, name := range opt {
+		if err := name.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (opt *FQLogicalVolumeName) Split() (VolumeGroupName, LogicalVolumeName) {
 	return opt.VolumeGroupName, opt.LogicalVolumeName
 }
@@ -17,6 +17,7 @@
 package lvm2go
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/rand"
@@ -24,6 +25,7 @@ import (
 	"fmt"
 	"hash"
 	"hash/fnv"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -42,12 +44,17 @@ const BackingFilePattern = "loopback-%s"
 
 // LoopbackDevice is an interface that represents a loopback device created with losetup.
 // It can be used to create a loopback device with a backing file, find a free loopback device,
-// open (set it up) and close (detach) the loopback device.
+// open (set it up), resize and close (detach) the loopback device.
 // for more information see man losetup.
 type LoopbackDevice interface {
 	Open() error
 	Close() error
 
+	// Resize grows or shrinks the backing file of an opened loopback device to the given size
+	// and lets the kernel pick up the new capacity (losetup -c).
+	// Shrinking a device that is in use, e.g. as a physical volume, destroys its data.
+	Resize(size Size) error
+
 	FindFree() error
 	SetBackingFile(file string) error
 
@@ -223,7 +230,7 @@ func (dev *loopbackDevice) Close() error {
 	ctx, cancel := context.WithTimeout(context.Background(), dev.commandTimeout)
 	defer cancel()
 
-	if err := exec.CommandContext(ctx, "losetup", "-d", dev.device).Run(); err != nil {
+	if _, err := exec.CommandContext(ctx, "losetup", "-d", dev.device).Output(); err != nil {
 		if isLosetupNoSuchFileOrAddressError(err) {
 			dev.closed = true
 			dev.opened = false
@@ -342,7 +349,7 @@ func (dev *loopbackDevice) Open() error {
 
 	args := []string{dev.device, dev.file}
 	if dev.sectorSize.Val > 0 {
-		args = append(args, fmt.Sprintf("--sector-size=%d", uint64(dev.size.Val)))
+		args = append(args, fmt.Sprintf("--sector-size=%d", uint64(dev.sectorSize.Val)))
 	}
 
 	args = append(args, "--direct-io=on")
@@ -355,12 +362,169 @@ func (dev *loopbackDevice) Open() error {
 	return nil
 }
 
+func (dev *loopbackDevice) Resize(size Size) error {
+	dev.mu.Lock()
+	defer dev.mu.Unlock()
+
+	if dev.closed {
+		return ErrDeviceAlreadyClosed
+	}
+	if !dev.opened {
+		return fmt.Errorf("loopback device must be opened to be resized")
+	}
+
+	size, err := size.ToUnit(UnitBytes)
+	if err != nil {
+		return fmt.Errorf("failed to convert size to bytes to use with truncate: %w", err)
+	}
+
+	if err := os.Truncate(dev.file, int64(size.Val)); err != nil {
+		return fmt.Errorf("failed to truncate backing file %s to size %v: %w", dev.file, size.Val, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dev.commandTimeout)
+	defer cancel()
+
+	out, err := exec.CommandContext(ctx, "losetup", "-c", dev.device).CombinedOutput()
+	if err != nil {
+		return errors.Join(err, errors.New(string(out)))
+	}
+	dev.size = size
+	return nil
+}
+
+// LoopDeviceInfo describes a loop device that is set up on the system, independent of
+// whether it was created with a LoopbackDevice.
+type LoopDeviceInfo struct {
+	// Device is the path of the loop device, e.g. /dev/loop0.
+	Device string
+	// BackingFile is the path of the backing file.
+	BackingFile string
+	// BackingFileDeleted is set if the backing file was removed while the device is still set up.
+	BackingFileDeleted bool
+	// SizeLimit is the size limit of the device, zero if it uses the whole backing file.
+	SizeLimit Size
+	// Offset is the offset of the device into the backing file.
+	Offset Size
+	// ReadOnly is set if the device was set up read-only.
+	ReadOnly bool
+	// DirectIO is set if the device uses direct I/O on the backing file.
+	DirectIO bool
+}
+
+const loopDeviceColumns = "NAME,BACK-FILE,SIZELIMIT,OFFSET,RO,DIO"
+
+// ListLoopDevices lists all loop devices that are set up on the system.
+func ListLoopDevices(ctx context.Context) ([]LoopDeviceInfo, error) {
+	command := exec.CommandContext(ctx, "losetup", "--list", "--raw", "--noheadings", "--bytes", "--output", loopDeviceColumns)
+	stdErr := bytes.Buffer{}
+	command.Stderr = &stdErr
+	out, err := command.Output()
+	if stdErr.Len() > 0 {
+		err = errors.Join(err, errors.New(stdErr.String()))
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseLoopDevices(bytes.NewReader(out))
+}
+
+// DetachAllLoopDevices detaches all loop devices whose backing file starts with the given prefix,
+// e.g. filepath.Join(os.TempDir(), "loopback-") for the devices created by NewLoopbackDevice.
+// This is intended to clean up devices leaked by aborted runs. The backing files are not removed.
+// The detached devices are returned, also if some devices could not be detached.
+func DetachAllLoopDevices(ctx context.Context, prefix string) ([]LoopDeviceInfo, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("a backing file prefix is required to detach loop devices")
+	}
+
+	devices, err := ListLoopDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var detached []LoopDeviceInfo
+	var errs []error
+	for _, device := range devices {
+		if !strings.HasPrefix(device.BackingFile, prefix) {
+			continue
+		}
+		if _, err := exec.CommandContext(ctx, "losetup", "-d", device.Device).Output(); err != nil {
+			if isLosetupNoSuchFileOrAddressError(err) {
+				continue
+			}
+			errs = append(errs, fmt.Errorf("failed to detach loop device %s: %w", device.Device, err))
+			continue
+		}
+		detached = append(detached, device)
+	}
+	return detached, errors.Join(errs...)
+}
+
+// parseLoopDevices parses the raw output of losetup for loopDeviceColumns.
+// In raw output, whitespace and other unsafe characters in the values are hex escaped, e.g. \x20.
+func parseLoopDevices(out io.Reader) ([]LoopDeviceInfo, error) {
+	var devices []LoopDeviceInfo
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 6 {
+			return nil, fmt.Errorf("unexpected losetup output %q", scanner.Text())
+		}
+
+		device := LoopDeviceInfo{
+			Device:      unescapeRawOutput(fields[0]),
+			BackingFile: unescapeRawOutput(fields[1]),
+			ReadOnly:    fields[4] == "1",
+			DirectIO:    fields[5] == "1",
+		}
+		if file, ok := strings.CutSuffix(device.BackingFile, " (deleted)"); ok {
+			device.BackingFile, device.BackingFileDeleted = file, true
+		}
+		for _, field := range []struct {
+			raw  string
+			size *Size
+		}{
+			{fields[2], &device.SizeLimit},
+			{fields[3], &device.Offset},
+		} {
+			val, err := strconv.ParseUint(field.raw, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected losetup output %q: %w", scanner.Text(), err)
+			}
+			*field.size = NewSize(float64(val), UnitBytes)
+		}
+		devices = append(devices, device)
+	}
+	return devices, scanner.Err()
+}
+
+// unescapeRawOutput decodes the \xHH escapes of the --raw output of util-linux tools such as losetup and findmnt.
+func unescapeRawOutput(value string) string {
+	if !strings.Contains(value, `\x`) {
+		return value
+	}
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		if value[i] == '\\' && i+3 < len(value) && value[i+1] == 'x' {
+			if c, err := strconv.ParseUint(value[i+2:i+4], 16, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(value[i])
+	}
+	return b.String()
+}
+
 func isLosetupNoSuchFileOrAddressError(err error) bool {
 	exitErr := &exec.ExitError{}
-	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
-		if exitErr.Stderr != nil && strings.Contains(string(exitErr.Stderr), "RequestConfirm such device or address") {
-			return true
-		}
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		return strings.Contains(string(exitErr.Stderr), "No such device or address")
 	}
 	return false
 }
@@ -0,0 +1,58 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseLoopDevices(t *testing.T) {
+	t.Parallel()
+	out := "/dev/loop0 /tmp/loopback-1234 0 0 0 1\n" +
+		`/dev/loop1 /var/lib/ci\x20images/disk.img\x20(deleted) 1048576 4096 1 0` + "\n"
+
+	devices, err := parseLoopDevices(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []LoopDeviceInfo{
+		{
+			Device:      "/dev/loop0",
+			BackingFile: "/tmp/loopback-1234",
+			SizeLimit:   NewSize(0, UnitBytes),
+			Offset:      NewSize(0, UnitBytes),
+			DirectIO:    true,
+		},
+		{
+			Device:             "/dev/loop1",
+			BackingFile:        "/var/lib/ci images/disk.img",
+			BackingFileDeleted: true,
+			SizeLimit:          NewSize(1048576, UnitBytes),
+			Offset:             NewSize(4096, UnitBytes),
+			ReadOnly:           true,
+		},
+	}
+	if !reflect.DeepEqual(devices, expected) {
+		t.Fatalf("expected %+v, got %+v", expected, devices)
+	}
+
+	if _, err := parseLoopDevices(strings.NewReader("/dev/loop0 /tmp/file\n")); err == nil {
+		t.Fatal("expected error for unexpected output")
+	}
+}
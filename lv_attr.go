@@ -46,6 +46,7 @@ var (
 	ErrUnknownVolumeState                        = errors.New("unknown volume state, verification on the host system is required")
 	ErrHistoricalVolumeState                     = errors.New("historical volume state (volume no longer exists but is kept around in logs), verification on the host system is required")
 	ErrLogicalVolumeUnderlyingDeviceStateUnknown = errors.New("logical volume underlying device state is unknown, verification on the host system is required")
+	ErrInterruptedOperation                      = errors.New("logical volume is part of an interrupted pvmove or conversion that is not progressing, polling can be resumed with vgchange --poll y")
 )
 
 const (
@@ -67,6 +68,7 @@ const (
 	VolumeTypeThinPool                   VolumeType = 't'
 	VolumeTypeThinPoolData               VolumeType = 'T'
 	VolumeTypeThinPoolMetadata           VolumeType = 'e'
+	VolumeTypeCache                      VolumeType = 'C'
 	VolumeTypeNone                       VolumeType = '-'
 )
 
@@ -233,6 +235,7 @@ func (attr LVAttributes) MarshalText() ([]byte, error) {
 // VerifyHealth checks the health of the logical volume based on the attributes, mainly
 // bit 9 (volume health indicator) based on bit 1 (volume type indicator)
 // All failed known states are reported with an error message.
+// Interrupted operations are only reported if they are inactive, see LVAttributes.IsInterrupted.
 func (attr LVAttributes) VerifyHealth() error {
 	if attr.VolumeHealth == VolumeHealthPartialActivation {
 		return ErrPartialActivation
@@ -301,5 +304,9 @@ func (attr LVAttributes) VerifyHealth() error {
 		return ErrLogicalVolumeUnderlyingDeviceStateUnknown
 	}
 
+	if attr.IsInterrupted() {
+		return ErrInterruptedOperation
+	}
+
 	return nil
 }
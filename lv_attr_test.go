@@ -191,6 +191,16 @@ func TestVerifyHealth(t *testing.T) {
 			rawAttr: "-----X----",
 			wantErr: ErrLogicalVolumeUnderlyingDeviceStateUnknown,
 		},
+		{
+			name:    "Interrupted PVMove",
+			rawAttr: "p-C-------",
+			wantErr: ErrInterruptedOperation,
+		},
+		{
+			name:    "Active PVMove",
+			rawAttr: "p-C-a-----",
+			wantErr: nil,
+		},
 		{
 			name:    "Healthy Volume",
 			rawAttr: "-wi-a-----",
@@ -0,0 +1,195 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"strings"
+)
+
+// LogicalVolumeRelation is the kind of relationship between a parent logical volume and its child.
+type LogicalVolumeRelation string
+
+const (
+	// LogicalVolumeRelationSnapshot relates an origin to its snapshots, including thin snapshots.
+	LogicalVolumeRelationSnapshot LogicalVolumeRelation = "snapshot"
+	// LogicalVolumeRelationThin relates a thin pool to its thin volumes.
+	LogicalVolumeRelationThin LogicalVolumeRelation = "thin"
+	// LogicalVolumeRelationCache relates a cache pool or cachevol to the logical volume it caches.
+	LogicalVolumeRelationCache LogicalVolumeRelation = "cache"
+	// LogicalVolumeRelationPool relates any other pool, e.g. a vdo pool, to the logical volumes allocated from it.
+	LogicalVolumeRelationPool LogicalVolumeRelation = "pool"
+)
+
+// LogicalVolumeEdge is the relationship of a parent logical volume to one of its children.
+type LogicalVolumeEdge struct {
+	Parent   *LogicalVolume
+	Child    *LogicalVolume
+	Relation LogicalVolumeRelation
+}
+
+// LogicalVolumeGraph is the graph of the parent/child relationships of logical volumes,
+// built from the origin and pool_lv fields of their report. A child cannot outlive its parents,
+// e.g. a snapshot its origin or a thin volume its thin pool. A thin snapshot has two parents.
+// Relationships to logical volumes that are not part of the report are omitted, e.g. those to
+// hidden logical volumes if the report was created without All.
+type LogicalVolumeGraph struct {
+	lvs      []*LogicalVolume
+	index    map[FQLogicalVolumeName]*LogicalVolume
+	edges    []LogicalVolumeEdge
+	children map[*LogicalVolume][]*LogicalVolume
+	parents  map[*LogicalVolume][]*LogicalVolume
+}
+
+// BuildLogicalVolumeGraph reports the logical volumes with the given options and builds their graph.
+func BuildLogicalVolumeGraph(ctx context.Context, client Client, opts ...LVsOption) (*LogicalVolumeGraph, error) {
+	lvs, err := client.LVs(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return NewLogicalVolumeGraph(lvs), nil
+}
+
+// NewLogicalVolumeGraph builds the graph of the given logical volumes.
+func NewLogicalVolumeGraph(lvs []*LogicalVolume) *LogicalVolumeGraph {
+	g := &LogicalVolumeGraph{
+		lvs:      lvs,
+		index:    make(map[FQLogicalVolumeName]*LogicalVolume, len(lvs)),
+		children: make(map[*LogicalVolume][]*LogicalVolume),
+		parents:  make(map[*LogicalVolume][]*LogicalVolume),
+	}
+	for _, lv := range lvs {
+		g.index[graphKey(lv.VolumeGroupName, lv.Name)] = lv
+	}
+
+	for _, lv := range lvs {
+		if origin, ok := g.index[graphKey(lv.VolumeGroupName, LogicalVolumeName(lv.Origin))]; ok && lv.Origin != "" {
+			g.addEdge(origin, lv, LogicalVolumeRelationSnapshot)
+		}
+		if pool, ok := g.index[graphKey(lv.VolumeGroupName, LogicalVolumeName(lv.PoolLogicalVolume))]; ok && lv.PoolLogicalVolume != "" {
+			switch lv.Attr.VolumeType {
+			case VolumeTypeThinVolume:
+				g.addEdge(pool, lv, LogicalVolumeRelationThin)
+			case VolumeTypeCache:
+				g.addEdge(pool, lv, LogicalVolumeRelationCache)
+			default:
+				g.addEdge(pool, lv, LogicalVolumeRelationPool)
+			}
+		}
+	}
+	return g
+}
+
+// graphKey identifies a logical volume in the graph. Hidden logical volumes are referenced
+// and reported with their name in brackets, which are removed.
+func graphKey(vg VolumeGroupName, lv LogicalVolumeName) FQLogicalVolumeName {
+	return FQLogicalVolumeName{VolumeGroupName: vg, LogicalVolumeName: LogicalVolumeName(strings.Trim(string(lv), "[]"))}
+}
+
+func (g *LogicalVolumeGraph) addEdge(parent, child *LogicalVolume, relation LogicalVolumeRelation) {
+	g.edges = append(g.edges, LogicalVolumeEdge{Parent: parent, Child: child, Relation: relation})
+	g.children[parent] = append(g.children[parent], child)
+	g.parents[child] = append(g.parents[child], parent)
+}
+
+// LV returns the logical volume of the graph with the given name, nil if it is not part of the graph.
+func (g *LogicalVolumeGraph) LV(name *FQLogicalVolumeName) *LogicalVolume {
+	return g.index[graphKey(name.VolumeGroupName, name.LogicalVolumeName)]
+}
+
+// Edges returns all relationships of the graph.
+func (g *LogicalVolumeGraph) Edges() []LogicalVolumeEdge {
+	return g.edges
+}
+
+// Children returns the logical volumes that directly depend on the given logical volume.
+func (g *LogicalVolumeGraph) Children(name *FQLogicalVolumeName) []*LogicalVolume {
+	return g.children[g.LV(name)]
+}
+
+// Parents returns the logical volumes the given logical volume directly depends on.
+func (g *LogicalVolumeGraph) Parents(name *FQLogicalVolumeName) []*LogicalVolume {
+	return g.parents[g.LV(name)]
+}
+
+// Descendants returns all logical volumes that depend on the given logical volume directly or indirectly,
+// closest first.
+func (g *LogicalVolumeGraph) Descendants(name *FQLogicalVolumeName) []*LogicalVolume {
+	return g.walk(g.LV(name), g.children)
+}
+
+// Ancestors returns all logical volumes the given logical volume depends on directly or indirectly,
+// closest first.
+func (g *LogicalVolumeGraph) Ancestors(name *FQLogicalVolumeName) []*LogicalVolume {
+	return g.walk(g.LV(name), g.parents)
+}
+
+// walk traverses next breadth first from start, excluding start.
+func (g *LogicalVolumeGraph) walk(start *LogicalVolume, next map[*LogicalVolume][]*LogicalVolume) []*LogicalVolume {
+	if start == nil {
+		return nil
+	}
+	var result []*LogicalVolume
+	seen := map[*LogicalVolume]bool{start: true}
+	queue := []*LogicalVolume{start}
+	for len(queue) > 0 {
+		lv := queue[0]
+		queue = queue[1:]
+		for _, n := range next[lv] {
+			if seen[n] {
+				continue
+			}
+			seen[n] = true
+			result = append(result, n)
+			queue = append(queue, n)
+		}
+	}
+	return result
+}
+
+// RemovalOrder returns the given logical volumes and all of their descendants in an order in which
+// they can be removed one after another, every logical volume before the ones it depends on.
+// If no names are given, the order covers all logical volumes of the graph.
+func (g *LogicalVolumeGraph) RemovalOrder(names ...*FQLogicalVolumeName) []*LogicalVolume {
+	roots := g.lvs
+	if len(names) > 0 {
+		roots = nil
+		for _, name := range names {
+			if lv := g.LV(name); lv != nil {
+				roots = append(roots, lv)
+			}
+		}
+	}
+
+	var order []*LogicalVolume
+	visited := make(map[*LogicalVolume]bool)
+	var visit func(lv *LogicalVolume)
+	visit = func(lv *LogicalVolume) {
+		if visited[lv] {
+			return
+		}
+		visited[lv] = true
+		for _, child := range g.children[lv] {
+			visit(child)
+		}
+		order = append(order, lv)
+	}
+	for _, lv := range roots {
+		visit(lv)
+	}
+	return order
+}
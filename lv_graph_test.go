@@ -0,0 +1,99 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLogicalVolumeGraph(t *testing.T) {
+	t.Parallel()
+
+	lv := func(name, attr, origin, pool string) *LogicalVolume {
+		parsed, err := ParseLVAttributes(attr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &LogicalVolume{Name: LogicalVolumeName(name), VolumeGroupName: "vg", Attr: parsed, Origin: origin, PoolLogicalVolume: pool}
+	}
+	graph := NewLogicalVolumeGraph([]*LogicalVolume{
+		lv("pool", "twi-aotz--", "", ""),
+		lv("thin", "Vwi-aotz--", "", "pool"),
+		lv("thin-snap", "Vwi---tz-k", "thin", "pool"),
+		lv("linear", "owi-a-----", "", ""),
+		lv("cow", "swi-a-s---", "linear", ""),
+		lv("cached", "Cwi-a-C---", "", "[fast_cvol]"),
+		lv("fast_cvol", "Cwi-aoC---", "", ""),
+	})
+
+	names := func(lvs []*LogicalVolume) []string {
+		var names []string
+		for _, lv := range lvs {
+			names = append(names, string(lv.Name))
+		}
+		return names
+	}
+	fq := MustNewFQLogicalVolumeName
+
+	if got := names(graph.Descendants(fq("vg", "pool"))); !slices.Equal(got, []string{"thin", "thin-snap"}) {
+		t.Fatalf("unexpected descendants of pool %v", got)
+	}
+	if got := names(graph.Ancestors(fq("vg", "thin-snap"))); !slices.Equal(got, []string{"thin", "pool"}) {
+		t.Fatalf("unexpected ancestors of thin-snap %v", got)
+	}
+	if got := names(graph.Parents(fq("vg", "cached"))); !slices.Equal(got, []string{"fast_cvol"}) {
+		t.Fatalf("unexpected parents of cached %v", got)
+	}
+	if got := names(graph.Children(fq("vg", "linear"))); !slices.Equal(got, []string{"cow"}) {
+		t.Fatalf("unexpected children of linear %v", got)
+	}
+	if graph.Descendants(fq("vg", "missing")) != nil {
+		t.Fatal("expected no descendants of a logical volume that is not part of the graph")
+	}
+
+	relations := map[string]LogicalVolumeRelation{}
+	for _, edge := range graph.Edges() {
+		relations[string(edge.Parent.Name)+">"+string(edge.Child.Name)] = edge.Relation
+	}
+	for edge, relation := range map[string]LogicalVolumeRelation{
+		"pool>thin":        LogicalVolumeRelationThin,
+		"pool>thin-snap":   LogicalVolumeRelationThin,
+		"thin>thin-snap":   LogicalVolumeRelationSnapshot,
+		"linear>cow":       LogicalVolumeRelationSnapshot,
+		"fast_cvol>cached": LogicalVolumeRelationCache,
+	} {
+		if relations[edge] != relation {
+			t.Errorf("expected relation %s for %s, got %q", relation, edge, relations[edge])
+		}
+	}
+
+	if got := names(graph.RemovalOrder(fq("vg", "pool"))); !slices.Equal(got, []string{"thin-snap", "thin", "pool"}) {
+		t.Fatalf("unexpected removal order of pool %v", got)
+	}
+	order := names(graph.RemovalOrder())
+	if len(order) != 7 {
+		t.Fatalf("expected all logical volumes in the removal order, got %v", order)
+	}
+	for _, edge := range graph.Edges() {
+		if slices.Index(order, string(edge.Child.Name)) > slices.Index(order, string(edge.Parent.Name)) {
+			t.Errorf("expected %s to be removed before %s, got %v", edge.Child.Name, edge.Parent.Name, order)
+		}
+	}
+}
@@ -0,0 +1,225 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var DefaultLVSegmentsColumnOptions = ColumnOptions{
+	"lv_name",
+	"lv_uuid",
+	"vg_name",
+	"segtype",
+	"seg_start_pe",
+	"seg_size_pe",
+	"stripes",
+	"stripe_size",
+	"chunk_size",
+	"seg_pe_ranges",
+	"devices",
+	"seg_monitor",
+}
+
+// LogicalVolumeSegment is a segment of a logical volume, a range of its logical extents
+// with a single segment type and layout.
+type LogicalVolumeSegment struct {
+	LogicalVolumeName LogicalVolumeName `json:"lv_name"`
+	LogicalVolumeUUID string            `json:"lv_uuid"`
+	VolumeGroupName   VolumeGroupName   `json:"vg_name"`
+
+	// Start is the first logical extent of the segment.
+	Start int64 `json:"seg_start_pe"`
+	// Size is the number of logical extents in the segment.
+	Size        int64  `json:"seg_size_pe"`
+	SegmentType string `json:"segtype"`
+
+	Stripes    int64 `json:"stripes"`
+	StripeSize Size  `json:"stripe_size"`
+	// ChunkSize is the chunk size of snapshot, thin pool and cache segments.
+	ChunkSize Size `json:"chunk_size"`
+
+	// PhysicalExtentRanges are the ranges of physical extents the segment occupies.
+	// Segments that are layered on other logical volumes, such as raid or thin pool segments,
+	// report the ranges of their hidden sub logical volumes instead.
+	PhysicalExtentRanges []PhysicalExtentRange `json:"seg_pe_ranges"`
+	// Devices are the underlying devices of the segment with their starting extent,
+	// e.g. /dev/sdb(0) or the hidden sub logical volumes of raid and thin pool segments.
+	Devices []string `json:"devices"`
+	// Monitor is the monitoring status of the segment by dmeventd, e.g. of thin pool, snapshot or raid segments.
+	Monitor SegmentMonitor `json:"seg_monitor"`
+}
+
+// PhysicalExtentRange is an inclusive range of physical extents on a device, e.g. /dev/sdb:0-24.
+type PhysicalExtentRange struct {
+	PhysicalVolumeName PhysicalVolumeName
+	Start              int64
+	End                int64
+}
+
+// ParsePhysicalExtentRange parses a range in the format of seg_pe_ranges, e.g. /dev/sdb:0-24.
+func ParsePhysicalExtentRange(str string) (PhysicalExtentRange, error) {
+	i := strings.LastIndex(str, ":")
+	if i <= 0 {
+		return PhysicalExtentRange{}, fmt.Errorf("invalid physical extent range %q", str)
+	}
+	start, end, ok := strings.Cut(str[i+1:], "-")
+	if !ok {
+		return PhysicalExtentRange{}, fmt.Errorf("invalid physical extent range %q", str)
+	}
+	r := PhysicalExtentRange{PhysicalVolumeName: PhysicalVolumeName(str[:i])}
+	var err error
+	if r.Start, err = strconv.ParseInt(start, 10, 64); err != nil {
+		return PhysicalExtentRange{}, fmt.Errorf("invalid start of physical extent range %q: %w", str, err)
+	}
+	if r.End, err = strconv.ParseInt(end, 10, 64); err != nil {
+		return PhysicalExtentRange{}, fmt.Errorf("invalid end of physical extent range %q: %w", str, err)
+	}
+	return r, nil
+}
+
+func (r PhysicalExtentRange) String() string {
+	return fmt.Sprintf("%s:%d-%d", r.PhysicalVolumeName, r.Start, r.End)
+}
+
+// Size returns the number of physical extents in the range.
+func (r PhysicalExtentRange) Size() int64 {
+	return r.End - r.Start + 1
+}
+
+func (seg *LogicalVolumeSegment) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for key, fieldPtr := range map[string]*string{
+		"lv_name":     (*string)(&seg.LogicalVolumeName),
+		"lv_uuid":     &seg.LogicalVolumeUUID,
+		"vg_name":     (*string)(&seg.VolumeGroupName),
+		"segtype":     &seg.SegmentType,
+		"seg_monitor": (*string)(&seg.Monitor),
+	} {
+		if val, ok := raw[key]; !ok {
+			continue
+		} else if err := json.Unmarshal(val, fieldPtr); err != nil {
+			return newReportFieldError(key, val, err)
+		}
+	}
+
+	for key, fieldPtr := range map[string]*int64{
+		"seg_start_pe": &seg.Start,
+		"seg_size_pe":  &seg.Size,
+		"stripes":      &seg.Stripes,
+	} {
+		if err := unmarshalToStringAndParseInt64(raw, key, fieldPtr); err != nil {
+			return err
+		}
+	}
+
+	for key, fieldPtr := range map[string]*Size{
+		"stripe_size": &seg.StripeSize,
+		"chunk_size":  &seg.ChunkSize,
+	} {
+		if err := unmarshalToStringAndParse(raw, key, fieldPtr, ParseSizeLenient); err != nil {
+			return err
+		}
+	}
+
+	if err := unmarshalToStringAndParse(raw, "seg_pe_ranges", &seg.PhysicalExtentRanges, parsePhysicalExtentRanges); err != nil {
+		return err
+	}
+
+	return unmarshalToStringAndParseCommaSeparatedStrings(raw, "devices", &seg.Devices)
+}
+
+// parsePhysicalExtentRanges parses the ranges of seg_pe_ranges, which are separated by spaces or commas.
+func parsePhysicalExtentRanges(str string) ([]PhysicalExtentRange, error) {
+	var ranges []PhysicalExtentRange
+	for _, field := range strings.FieldsFunc(str, func(r rune) bool { return r == ' ' || r == ',' }) {
+		r, err := ParsePhysicalExtentRange(field)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+// End returns the last logical extent of the segment.
+func (seg *LogicalVolumeSegment) End() int64 {
+	return seg.Start + seg.Size - 1
+}
+
+// PhysicalVolumes returns the physical volumes the segment occupies, in the order of its ranges.
+func (seg *LogicalVolumeSegment) PhysicalVolumes() []PhysicalVolumeName {
+	var pvs []PhysicalVolumeName
+	for _, r := range seg.PhysicalExtentRanges {
+		if !slices.Contains(pvs, r.PhysicalVolumeName) {
+			pvs = append(pvs, r.PhysicalVolumeName)
+		}
+	}
+	return pvs
+}
+
+// LVSegments returns the segments of all logical volumes that match the given options.
+// If no segments are found, nil is returned.
+// It is really just a wrapper around the `lvs --segments --reportformat json` command.
+func (c *client) LVSegments(ctx context.Context, opts ...LVsOption) ([]*LogicalVolumeSegment, error) {
+	type segReport struct {
+		Report []struct {
+			Seg []*LogicalVolumeSegment `json:"seg"`
+		} `json:"report"`
+	}
+
+	var res = new(segReport)
+
+	args := []string{
+		"lvs", "--segments", "--reportformat", "json",
+	}
+	argsFromOpts, err := LVsOptionsList(append([]LVsOption{DefaultLVSegmentsColumnOptions, c.opts.ReportLocking}, opts...)).AsArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
+
+	if IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Report) == 0 {
+		return nil, nil
+	}
+
+	segments := res.Report[0].Seg
+
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	return segments, nil
+}
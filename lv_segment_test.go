@@ -0,0 +1,96 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVSegments(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := LVsOptionsList{DefaultLVSegmentsColumnOptions, VolumeGroupName("vg")}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    append([]string{"lvs", "--segments", "--reportformat", "json"}, args.GetRaw()...),
+		Stdout: `{"report": [{"seg": [
+			{"lv_name":"lv", "lv_uuid":"lv-uuid", "vg_name":"vg", "segtype":"striped", "seg_start_pe":"0", "seg_size_pe":"20",
+			 "stripes":"2", "stripe_size":"64.00k", "chunk_size":"0", "seg_pe_ranges":"/dev/sdb:0-9 /dev/sdc:0-9", "devices":"/dev/sdb(0),/dev/sdc(0)"},
+			{"lv_name":"pool", "lv_uuid":"pool-uuid", "vg_name":"vg", "segtype":"thin-pool", "seg_start_pe":"0", "seg_size_pe":"5",
+			 "stripes":"1", "stripe_size":"0", "chunk_size":"64.00k", "seg_pe_ranges":"", "devices":"pool_tdata(0)",
+			 "seg_monitor":"monitored"}
+		]}]}`,
+	}}))
+
+	segments, err := clnt.LVSegments(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) != 2 {
+		t.Fatalf("expected 2 segments, got %d", len(segments))
+	}
+
+	striped := segments[0]
+	if striped.LogicalVolumeName != "lv" || striped.SegmentType != "striped" || striped.Stripes != 2 || striped.End() != 19 {
+		t.Fatalf("unexpected striped segment %+v", striped)
+	}
+	if striped.StripeSize != MustParseSize("64k") {
+		t.Fatalf("expected stripe size of 64k, got %s", striped.StripeSize)
+	}
+	expectedRanges := []PhysicalExtentRange{{"/dev/sdb", 0, 9}, {"/dev/sdc", 0, 9}}
+	if !slices.Equal(striped.PhysicalExtentRanges, expectedRanges) {
+		t.Fatalf("expected ranges %v, got %v", expectedRanges, striped.PhysicalExtentRanges)
+	}
+	if !slices.Equal(striped.PhysicalVolumes(), []PhysicalVolumeName{"/dev/sdb", "/dev/sdc"}) {
+		t.Fatalf("unexpected physical volumes %v", striped.PhysicalVolumes())
+	}
+	if !slices.Equal(striped.Devices, []string{"/dev/sdb(0)", "/dev/sdc(0)"}) {
+		t.Fatalf("unexpected devices %v", striped.Devices)
+	}
+
+	pool := segments[1]
+	if pool.ChunkSize != MustParseSize("64k") || len(pool.PhysicalExtentRanges) != 0 || pool.Monitor != SegmentMonitored {
+		t.Fatalf("unexpected thin pool segment %+v", pool)
+	}
+}
+
+func TestParsePhysicalExtentRange(t *testing.T) {
+	t.Parallel()
+	r, err := ParsePhysicalExtentRange("/dev/disk/by-id/dm-name-mpath:a:10-19")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.PhysicalVolumeName != "/dev/disk/by-id/dm-name-mpath:a" || r.Start != 10 || r.End != 19 || r.Size() != 10 {
+		t.Fatalf("unexpected range %+v", r)
+	}
+	if r.String() != "/dev/disk/by-id/dm-name-mpath:a:10-19" {
+		t.Fatalf("unexpected string %s", r)
+	}
+	for _, invalid := range []string{"/dev/sdb", "/dev/sdb:10", ":0-1", "/dev/sdb:a-b"} {
+		if _, err := ParsePhysicalExtentRange(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
 )
 
 type (
@@ -26,6 +27,9 @@ type (
 		LogicalVolumeName
 
 		Permission
+		*PersistentDeviceNumber
+		RemovePersistentDeviceNumber
+		ReadAhead
 
 		Tags
 		DelTags
@@ -33,17 +37,31 @@ type (
 		Zero
 		RequestConfirm
 		ActivationState
+		Refresh
 		ActivationMode
+		IgnoreActivationSkip
+		ActivationSkip
 		AllocationPolicy
 		*ErrorWhenFull
 		Partial
 		SyncAction
+		MinRecoveryRate
+		MaxRecoveryRate
+		WriteMostly
+		WriteBehind
 		Rebuild
 		Resync
 		Discards
 		*Deduplication
 		*Compression
 		AutoActivation
+		Poll
+		Monitor
+		IgnoreMonitoring
+
+		// Select changes the logical volumes matching the select expression instead of a named logical volume.
+		// A VolumeGroupName limits the selection to the volume group.
+		Select
 
 		CommonOptions
 	}
@@ -64,6 +82,16 @@ func (c *client) LVChange(ctx context.Context, opts ...LVChangeOption) error {
 		return err
 	}
 
+	options := LVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVChangeOptions(&options)
+	}
+	if options.Resync {
+		c.logger().WarnContext(ctx, "resynchronizing logical volume, "+
+			"it has no complete redundant copy of its data until the synchronization finished",
+			"vg", options.VolumeGroupName, "lv", options.LogicalVolumeName)
+	}
+
 	return c.RunLVM(ctx, append([]string{"lvchange"}, args.GetRaw()...)...)
 }
 
@@ -84,30 +112,64 @@ func (list LVChangeOptionsList) AsArgs() (Arguments, error) {
 }
 
 func (opts *LVChangeOptions) ApplyToArgs(args Arguments) error {
-	id, err := NewFQLogicalVolumeName(opts.VolumeGroupName, opts.LogicalVolumeName)
-	if err != nil {
+	if opts.PersistentDeviceNumber != nil && bool(opts.RemovePersistentDeviceNumber) {
+		return fmt.Errorf("PersistentDeviceNumber and RemovePersistentDeviceNumber are mutually exclusive")
+	}
+	if len(opts.Rebuild) > 0 && bool(opts.Resync) {
+		return fmt.Errorf("Rebuild and Resync are mutually exclusive")
+	}
+	if err := validateRecoveryRates(opts.MinRecoveryRate, opts.MaxRecoveryRate); err != nil {
+		return err
+	}
+	if err := validateMonitoring(opts.Monitor, opts.IgnoreMonitoring); err != nil {
 		return err
 	}
+	if bool(opts.Refresh) && opts.ActivationState != "" {
+		return fmt.Errorf("Refresh and ActivationState are mutually exclusive")
+	}
+
+	var scope Argument = opts.VolumeGroupName
+	if opts.Select == "" || opts.LogicalVolumeName != "" {
+		id, err := NewFQLogicalVolumeName(opts.VolumeGroupName, opts.LogicalVolumeName)
+		if err != nil {
+			return err
+		}
+		scope = id
+	}
 
 	for _, arg := range []Argument{
-		id,
+		scope,
 		opts.Permission,
+		opts.PersistentDeviceNumber,
+		opts.RemovePersistentDeviceNumber,
+		opts.ReadAhead,
 		opts.Tags,
 		opts.DelTags,
 		opts.Zero,
 		opts.RequestConfirm,
 		opts.ActivationState,
+		opts.Refresh,
 		opts.ActivationMode,
+		opts.IgnoreActivationSkip,
+		opts.ActivationSkip,
 		opts.AllocationPolicy,
 		opts.ErrorWhenFull,
 		opts.Partial,
 		opts.SyncAction,
+		opts.MinRecoveryRate,
+		opts.MaxRecoveryRate,
+		opts.WriteMostly,
+		opts.WriteBehind,
 		opts.Rebuild,
 		opts.Resync,
 		opts.Discards,
 		opts.Deduplication,
 		opts.Compression,
 		opts.AutoActivation,
+		opts.Poll,
+		opts.Monitor,
+		opts.IgnoreMonitoring,
+		opts.Select,
 		opts.CommonOptions,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
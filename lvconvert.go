@@ -0,0 +1,204 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+type (
+	// LVConvertOptions describe a conversion of a logical volume with lvconvert.
+	// Exactly one conversion has to be requested:
+	//   - a change of the Type, e.g. TypeThinPool (optionally with PoolMetadata or PoolMetadataSize),
+	//     TypeCache (with CachePool or CacheVolume), TypeWriteCache (with CacheVolume) or TypeRAID1 (with Mirrors),
+	//   - a change of the Mirrors of a mirror or raid logical volume without a Type, Mirrors(0) converts it to linear,
+	//   - a change of the MirrorLog of a mirror logical volume,
+	//   - MergeSnapshot to merge a snapshot into its origin,
+	//   - SplitCache or Uncache to detach the cache from a cached logical volume,
+	//   - Repair to repair a raid, mirror, thin pool or cache pool.
+	//   - SwapMetadata to swap the metadata volume of a pool with the logical volume given as PoolMetadata.
+	LVConvertOptions struct {
+		VolumeGroupName
+		LogicalVolumeName
+
+		Type
+		*Mirrors
+		Stripes
+		MirrorLog
+		StripeSize
+		ChunkSize
+		Zero
+
+		*PoolMetadata
+		PoolMetadataSize
+		PoolMetadataSpare
+		*CachePool
+		*CacheVolume
+		CacheMode
+		CachePolicy
+		CacheSettings
+
+		MergeSnapshot
+		SplitCache
+		Uncache
+		Repair
+		UsePolicies
+		SwapMetadata
+		Force
+
+		CommonOptions
+	}
+	LVConvertOption interface {
+		ApplyToLVConvertOptions(opts *LVConvertOptions)
+	}
+	LVConvertOptionsList []LVConvertOption
+)
+
+var (
+	_ ArgumentGenerator = LVConvertOptionsList{}
+	_ Argument          = (*LVConvertOptions)(nil)
+)
+
+func (c *client) LVConvert(ctx context.Context, opts ...LVConvertOption) error {
+	args, err := LVConvertOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"lvconvert"}, args.GetRaw()...)...)
+}
+
+func (opts *LVConvertOptions) ApplyToLVConvertOptions(new *LVConvertOptions) {
+	*new = *opts
+}
+
+func (list LVConvertOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeLVConvert)
+	options := LVConvertOptions{}
+	for _, opt := range list {
+		opt.ApplyToLVConvertOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *LVConvertOptions) ApplyToArgs(args Arguments) error {
+	id, err := NewFQLogicalVolumeName(opts.VolumeGroupName, opts.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+
+	conversions := 0
+	for _, requested := range []bool{
+		opts.Type != "" || opts.Mirrors != nil || opts.MirrorLog != "",
+		bool(opts.MergeSnapshot),
+		bool(opts.Repair),
+		bool(opts.SwapMetadata),
+		bool(opts.SplitCache),
+		bool(opts.Uncache),
+	} {
+		if requested {
+			conversions++
+		}
+	}
+	if conversions == 0 {
+		return fmt.Errorf("a Type, Mirrors, MirrorLog, MergeSnapshot, Repair, SwapMetadata, SplitCache or Uncache must be specified for a conversion")
+	} else if conversions > 1 {
+		return fmt.Errorf("Type, MergeSnapshot, Repair, SwapMetadata, SplitCache and Uncache are mutually exclusive")
+	}
+
+	if opts.CachePool != nil && opts.CacheVolume != nil {
+		return fmt.Errorf("CachePool and CacheVolume are mutually exclusive")
+	}
+	if opts.CachePool != nil && opts.Type != TypeCache {
+		return fmt.Errorf("CachePool requires Type %s", TypeCache)
+	}
+	if opts.CacheVolume != nil && opts.Type != TypeCache && opts.Type != TypeWriteCache {
+		return fmt.Errorf("CacheVolume requires Type %s or %s", TypeCache, TypeWriteCache)
+	}
+	if opts.Type == TypeCache && opts.CachePool == nil && opts.CacheVolume == nil {
+		return fmt.Errorf("CachePool or CacheVolume is required for Type %s", TypeCache)
+	}
+	if opts.Type == TypeWriteCache && opts.CacheVolume == nil {
+		return fmt.Errorf("CacheVolume is required for Type %s", TypeWriteCache)
+	}
+	if (opts.CacheMode != "" || opts.CachePolicy != "") && opts.Type != TypeCache {
+		return fmt.Errorf("CacheMode and CachePolicy require Type %s", TypeCache)
+	}
+	if len(opts.CacheSettings) > 0 && opts.Type != TypeCache && opts.Type != TypeWriteCache {
+		return fmt.Errorf("CacheSettings require Type %s or %s", TypeCache, TypeWriteCache)
+	}
+	if bool(opts.SwapMetadata) && opts.PoolMetadata == nil {
+		return fmt.Errorf("SwapMetadata requires PoolMetadata")
+	}
+	if (opts.PoolMetadata != nil && !bool(opts.SwapMetadata) || opts.PoolMetadataSize.Val > 0 || opts.PoolMetadataSpare != "") &&
+		opts.Type != TypeThinPool && opts.Type != TypePool {
+		return fmt.Errorf("PoolMetadata, PoolMetadataSize and PoolMetadataSpare require Type %s or %s", TypeThinPool, TypePool)
+	}
+	if bool(opts.UsePolicies) && !bool(opts.Repair) {
+		return fmt.Errorf("UsePolicies requires Repair")
+	}
+
+	arguments := []Argument{
+		id,
+		opts.Type,
+		opts.Stripes,
+		opts.MirrorLog,
+		opts.Zero,
+		opts.PoolMetadata,
+		opts.PoolMetadataSpare,
+		opts.CachePool,
+		opts.CacheVolume,
+		opts.CacheMode,
+		opts.CachePolicy,
+		opts.CacheSettings,
+		opts.MergeSnapshot,
+		opts.SplitCache,
+		opts.Uncache,
+		opts.Repair,
+		opts.UsePolicies,
+		opts.SwapMetadata,
+		opts.Force,
+		opts.CommonOptions,
+	}
+	// sizes are only passed if set, as their zero values are valid sizes
+	if opts.StripeSize.Val > 0 {
+		arguments = append(arguments, opts.StripeSize)
+	}
+	if opts.ChunkSize.Val > 0 {
+		arguments = append(arguments, opts.ChunkSize)
+	}
+	if opts.PoolMetadataSize.Val > 0 {
+		arguments = append(arguments, opts.PoolMetadataSize)
+	}
+	// mirrors are passed whenever set, as Mirrors(0) converts to linear
+	if opts.Mirrors != nil {
+		args.AddOrReplaceAll([]string{"--mirrors", strconv.Itoa(int(*opts.Mirrors))})
+	}
+
+	for _, arg := range arguments {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVConvertOptions(t *testing.T) {
+	t.Parallel()
+	lv := MustNewFQLogicalVolumeName("vg", "lv")
+	cachePool, err := NewCachePool("vg", "cpool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cacheVolume, err := NewCacheVolume("vg", "fast")
+	if err != nil {
+		t.Fatal(err)
+	}
+	metadata, err := NewPoolMetadata("vg", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		opts     LVConvertOptionsList
+		expected []string
+	}{
+		"thin pool": {
+			opts:     LVConvertOptionsList{lv, TypeThinPool, metadata, ChunkSize(MustParseSize("64K"))},
+			expected: []string{"vg/lv", "--type=thin-pool", "--poolmetadata=vg/meta", "--chunksize=64.00k"},
+		},
+		"cache pool": {
+			opts:     LVConvertOptionsList{lv, TypeCache, cachePool, CacheModeWriteBack},
+			expected: []string{"--type=cache", "--cachepool=vg/cpool", "--cachemode=writeback"},
+		},
+		"cache volume with policy": {
+			opts: LVConvertOptionsList{lv, TypeCache, cacheVolume, CachePolicySMQ, CacheSettings{"migration_threshold": "2048", "cleaner": "0"}},
+			expected: []string{"--type=cache", "--cachevol=vg/fast", "--cachepolicy=smq",
+				"--cachesettings=cleaner=0 migration_threshold=2048"},
+		},
+		"split cache": {
+			opts:     LVConvertOptionsList{lv, SplitCache(true)},
+			expected: []string{"vg/lv", "--splitcache"},
+		},
+		"uncache": {
+			opts:     LVConvertOptionsList{lv, Uncache(true)},
+			expected: []string{"vg/lv", "--uncache"},
+		},
+		"writecache": {
+			opts:     LVConvertOptionsList{lv, TypeWriteCache, cacheVolume},
+			expected: []string{"--type=writecache", "--cachevol=vg/fast"},
+		},
+		"raid1": {
+			opts:     LVConvertOptionsList{lv, TypeRAID1, Mirrors(1)},
+			expected: []string{"--type=raid1", "--mirrors", "1"},
+		},
+		"linear": {
+			opts:     LVConvertOptionsList{lv, Mirrors(0)},
+			expected: []string{"vg/lv", "--mirrors", "0"},
+		},
+		"merge": {
+			opts:     LVConvertOptionsList{lv, MergeSnapshot(true)},
+			expected: []string{"--mergesnapshot"},
+		},
+		"repair": {
+			opts:     LVConvertOptionsList{lv, Repair(true), UsePolicies(true)},
+			expected: []string{"--repair", "--use-policies", "--yes"},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			args, err := tc.opts.AsArgs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, arg := range tc.expected {
+				if !slices.Contains(args.GetRaw(), arg) {
+					t.Fatalf("expected %q in %v", arg, args.GetRaw())
+				}
+			}
+		})
+	}
+
+	for name, opts := range map[string]LVConvertOptionsList{
+		"no conversion":           {lv},
+		"no logical volume":       {TypeThinPool},
+		"merge and repair":        {lv, MergeSnapshot(true), Repair(true)},
+		"cache without cache":     {lv, TypeCache},
+		"cache pool without type": {lv, cachePool},
+		"writecache with pool":    {lv, TypeWriteCache, cachePool},
+		"metadata without pool":   {lv, TypeRAID1, metadata},
+		"policies without repair": {lv, MergeSnapshot(true), UsePolicies(true)},
+		"split and uncache":       {lv, SplitCache(true), Uncache(true)},
+		"policy without cache":    {lv, TypeWriteCache, cacheVolume, CachePolicySMQ},
+		"mode on split":           {lv, SplitCache(true), CacheModeWriteThrough},
+		"invalid cache setting":   {lv, TypeCache, cachePool, CacheSettings{"a b": "1"}},
+	} {
+		if _, err := opts.AsArgs(); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package lvm2go
 import (
 	"context"
 	"fmt"
+	"slices"
 )
 
 type (
@@ -32,16 +33,35 @@ type (
 		VirtualSize
 
 		AllocationPolicy
+		Permission
+		*PersistentDeviceNumber
+		ReadAhead
 		ActivationState
+		ActivationSkip
+		IgnoreActivationSkip
+		Monitor
+		IgnoreMonitoring
 		Zero
 		ChunkSize
+		PoolMetadataSize
+		PoolMetadataSpare
 		Type
 		Thin
 		*ThinPool
+		*SnapshotOf
+		*SuspendIO
 
 		Stripes
 		Mirrors
 		StripeSize
+		NoSync
+		MirrorLog
+		MinRecoveryRate
+		MaxRecoveryRate
+
+		SizeAlignment
+
+		PhysicalExtentSelectors
 
 		CommonOptions
 	}
@@ -63,14 +83,91 @@ var (
 )
 
 func (c *client) LVCreate(ctx context.Context, opts ...LVCreateOption) error {
-	args, err := LVCreateOptionList(opts).AsArgs()
+	options := LVCreateOptions{}
+	LVCreateOptionList(opts).ApplyToLVCreateOptions(&options)
+
+	if options.SizeAlignment != SizeAlignmentNone && (options.Size.Val > 0 || options.VirtualSize.Val > 0) {
+		extentSize, err := c.extentSize(ctx, options.volumeGroupName())
+		if err != nil {
+			return err
+		}
+		if options.Size.Val > 0 {
+			if options.Size, err = options.SizeAlignment.Align(options.Size, extentSize); err != nil {
+				return err
+			}
+		}
+		if options.VirtualSize.Val > 0 {
+			virtualSize, err := options.SizeAlignment.Align(Size(options.VirtualSize), extentSize)
+			if err != nil {
+				return err
+			}
+			options.VirtualSize = VirtualSize(virtualSize)
+		}
+	}
+
+	args, err := LVCreateOptionList{&options}.AsArgs()
 	if err != nil {
 		return err
 	}
 
+	if options.NoSync {
+		c.logger().WarnContext(ctx, "creating logical volume without initial synchronization, "+
+			"regions not written after creation are not redundant",
+			"vg", options.VolumeGroupName, "lv", options.LogicalVolumeName)
+	}
+
+	if options.SuspendIO != nil {
+		return options.SuspendIO.fence(ctx, c, c.logger(), (*FQLogicalVolumeName)(options.SnapshotOf), func(ctx context.Context) error {
+			return c.RunLVM(ctx, append([]string{"lvcreate"}, args.GetRaw()...)...)
+		})
+	}
+
 	return c.RunLVM(ctx, append([]string{"lvcreate"}, args.GetRaw()...)...)
 }
 
+// LVCreateAndGet creates a logical volume and returns its report.
+// The logical volume has to be named, as the name generated by lvm2 for unnamed logical volumes is not known.
+// Clients returned by NewLockingClient hold their lock across the creation and the report,
+// so that no other call of the client can change the logical volume in between.
+func LVCreateAndGet(ctx context.Context, client Client, opts ...LVCreateOption) (*LogicalVolume, error) {
+	options := LVCreateOptions{}
+	LVCreateOptionList(opts).ApplyToLVCreateOptions(&options)
+	if options.LogicalVolumeName == "" {
+		return nil, ErrLogicalVolumeNameRequired
+	}
+
+	var lv *LogicalVolume
+	err := atomically(client, func(client Client) (err error) {
+		lv, err = lvCreateAndGet(ctx, client, &options, opts)
+		return err
+	})
+	return lv, err
+}
+
+func lvCreateAndGet(ctx context.Context, client Client, options *LVCreateOptions, opts []LVCreateOption) (*LogicalVolume, error) {
+	if err := client.LVCreate(ctx, opts...); err != nil {
+		return nil, err
+	}
+	lv, err := client.LV(ctx, options.volumeGroupName(), options.LogicalVolumeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get logical volume %s after creating it: %w", options.LogicalVolumeName, err)
+	}
+	return lv, nil
+}
+
+// volumeGroupName returns the volume group of the logical volume to create,
+// which is part of the name of the thin pool or origin if set.
+func (opts *LVCreateOptions) volumeGroupName() VolumeGroupName {
+	switch {
+	case opts.ThinPool != nil:
+		return opts.ThinPool.VolumeGroupName
+	case opts.SnapshotOf != nil:
+		return opts.SnapshotOf.VolumeGroupName
+	default:
+		return opts.VolumeGroupName
+	}
+}
+
 func (list LVCreateOptionList) AsArgs() (Arguments, error) {
 	args := NewArgs(ArgsTypeLVCreate)
 	options := LVCreateOptions{}
@@ -88,9 +185,10 @@ func (opts *LVCreateOptions) ApplyToArgs(args Arguments) error {
 		return ErrLogicalVolumeNameRequired
 	}
 
+	// snapshots of thin volumes are thin themselves and do not need a size
 	if opts.Extents.Val > 0 && opts.Size.Val > 0 && opts.VirtualSize.Val > 0 {
 		return fmt.Errorf("size, virtual size and extents are mutually exclusive")
-	} else if opts.Extents.Val <= 0 && opts.Size.Val <= 0 && opts.VirtualSize.Val <= 0 {
+	} else if opts.Extents.Val <= 0 && opts.Size.Val <= 0 && opts.VirtualSize.Val <= 0 && opts.SnapshotOf == nil {
 		return fmt.Errorf("size, virtual size or extents must be specified")
 	}
 
@@ -102,10 +200,41 @@ func (opts *LVCreateOptions) ApplyToArgs(args Arguments) error {
 		return fmt.Errorf("ThinPool and VolumeGroupName are mutually exclusive. VolumeGroupName is a part of ThinPool name")
 	}
 
+	if opts.SnapshotOf != nil && (opts.ThinPool != nil || opts.VolumeGroupName != "") {
+		return fmt.Errorf("SnapshotOf is mutually exclusive with ThinPool and VolumeGroupName. VolumeGroupName is a part of SnapshotOf name")
+	}
+
+	isPool := bool(opts.Thin) || opts.Type == TypeThinPool || opts.Type == TypePool
+	if (opts.PoolMetadataSize.Val > 0 || opts.PoolMetadataSpare != "") && !isPool {
+		return fmt.Errorf("PoolMetadataSize and PoolMetadataSpare require Thin or Type %s or %s", TypeThinPool, TypePool)
+	}
+
+	if opts.SuspendIO != nil && opts.SnapshotOf == nil {
+		return ErrSuspendIORequiresSnapshotOf
+	}
+
+	if bool(opts.NoSync) && opts.Mirrors <= 0 && !slices.Contains([]Type{TypeMirrored, TypeRAID1, TypeRAID4, TypeRAID5, TypeRAID10}, opts.Type) {
+		return fmt.Errorf("NoSync requires Mirrors or a mirrored or raid Type")
+	}
+
+	if opts.MirrorLog != "" && opts.Type != TypeMirrored {
+		return fmt.Errorf("MirrorLog requires Type %s", TypeMirrored)
+	}
+
+	if err := validateRecoveryRates(opts.MinRecoveryRate, opts.MaxRecoveryRate); err != nil {
+		return err
+	}
+
+	if err := validateMonitoring(opts.Monitor, opts.IgnoreMonitoring); err != nil {
+		return err
+	}
+
 	var identifier []Argument
 
 	if opts.ThinPool != nil {
 		identifier = []Argument{opts.ThinPool, opts.LogicalVolumeName}
+	} else if opts.SnapshotOf != nil {
+		identifier = []Argument{opts.LogicalVolumeName, opts.SnapshotOf}
 	} else {
 		identifier = []Argument{opts.VolumeGroupName, opts.LogicalVolumeName}
 	}
@@ -115,17 +244,33 @@ func (opts *LVCreateOptions) ApplyToArgs(args Arguments) error {
 		sizeArgument = opts.Extents
 	} else if opts.Size.Val > 0 {
 		sizeArgument = opts.Size
-	} else {
+	} else if opts.VirtualSize.Val > 0 {
 		sizeArgument = opts.VirtualSize
 	}
+	if sizeArgument != nil {
+		identifier = append(identifier, sizeArgument)
+	}
+	identifier = append(identifier, opts.PhysicalExtentSelectors)
 
 	for _, arg := range append(identifier,
-		sizeArgument,
 		opts.AllocationPolicy,
 		opts.Thin,
 		opts.Type,
+		opts.Mirrors,
+		opts.NoSync,
+		opts.MirrorLog,
+		opts.MinRecoveryRate,
+		opts.MaxRecoveryRate,
+		opts.Permission,
+		opts.PersistentDeviceNumber,
+		opts.ReadAhead,
 		opts.ActivationState,
+		opts.ActivationSkip,
+		opts.IgnoreActivationSkip,
+		opts.Monitor,
+		opts.IgnoreMonitoring,
 		opts.Zero,
+		opts.PoolMetadataSpare,
 		opts.Tags,
 		opts.CommonOptions,
 	) {
@@ -134,6 +279,18 @@ func (opts *LVCreateOptions) ApplyToArgs(args Arguments) error {
 		}
 	}
 
+	// sizes are only passed if set, as their zero values are valid sizes
+	if opts.ChunkSize.Val > 0 {
+		if err := opts.ChunkSize.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+	if opts.PoolMetadataSize.Val > 0 {
+		if err := opts.PoolMetadataSize.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
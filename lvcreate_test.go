@@ -0,0 +1,58 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestLVCreateAndGet(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	backend := fake.NewClient()
+	if err := backend.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewLockingClient(backend)
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+
+	lv, err := LVCreateAndGet(ctx, clnt, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("30M"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Name != "lv" || lv.VolumeGroupName != "vg" {
+		t.Fatalf("unexpected logical volume %s/%s", lv.VolumeGroupName, lv.Name)
+	}
+	// the report reflects the size after rounding up to 8 extents
+	if size, err := lv.Size.ToUnit(UnitMiB); err != nil || size.Val != 32 {
+		t.Fatalf("unexpected size %s: %v", lv.Size, err)
+	}
+
+	if _, err := LVCreateAndGet(ctx, clnt, VolumeGroupName("vg"), MustParseSize("4M")); !errors.Is(err, ErrLogicalVolumeNameRequired) {
+		t.Fatalf("expected name to be required, got %v", err)
+	}
+	if _, err := LVCreateAndGet(ctx, clnt, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("4M")); err == nil {
+		t.Fatal("expected error for existing logical volume")
+	}
+}
@@ -29,7 +29,16 @@ type (
 
 		PoolMetadataPrefixedSize
 		PrefixedSize
+		SizeDelta
 		PrefixedExtents
+		UsePolicies
+		SizeAlignment
+
+		ResizeFS
+		FS
+		FSMode
+
+		PhysicalExtentSelectors
 
 		CommonOptions
 	}
@@ -46,7 +55,30 @@ var (
 )
 
 func (c *client) LVExtend(ctx context.Context, opts ...LVExtendOption) error {
-	args, err := LVExtendOptionsList(opts).AsArgs()
+	options := LVExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVExtendOptions(&options)
+	}
+
+	if options.SizeAlignment != SizeAlignmentNone && (options.PrefixedSize.Val > 0 || options.SizeDelta.Val > 0) {
+		extentSize, err := c.extentSize(ctx, options.VolumeGroupName)
+		if err != nil {
+			return err
+		}
+		// with a prefix, the size by which the logical volume is extended is aligned
+		if options.PrefixedSize.Val > 0 {
+			if options.PrefixedSize.Size, err = options.SizeAlignment.Align(options.PrefixedSize.Size, extentSize); err != nil {
+				return err
+			}
+		}
+		if options.SizeDelta.Val > 0 {
+			if options.SizeDelta.Size, err = options.SizeAlignment.Align(options.SizeDelta.Size, extentSize); err != nil {
+				return err
+			}
+		}
+	}
+
+	args, err := LVExtendOptionsList{&options}.AsArgs()
 	if err != nil {
 		return err
 	}
@@ -76,14 +108,22 @@ func (opts *LVExtendOptions) ApplyToArgs(args Arguments) error {
 	if err != nil {
 		return err
 	}
+	size, err := opts.SizeDelta.orPrefixedSize(opts.PrefixedSize)
+	if err != nil {
+		return err
+	}
 
-	if opts.Extents.Val > 0 && opts.PrefixedSize.Val > 0 {
+	if opts.UsePolicies {
+		if opts.Extents.Val > 0 || size.Val > 0 || opts.PoolMetadataPrefixedSize.Val > 0 {
+			return fmt.Errorf("size, extents and pool metadata size are mutually exclusive with use policies")
+		}
+	} else if opts.Extents.Val > 0 && size.Val > 0 {
 		return fmt.Errorf("size and extents are mutually exclusive")
-	} else if opts.Extents.Val <= 0 && opts.PrefixedSize.Val <= 0 {
-		return fmt.Errorf("size or extents must be specified")
+	} else if opts.Extents.Val <= 0 && size.Val <= 0 && opts.PoolMetadataPrefixedSize.Val <= 0 {
+		return fmt.Errorf("size, extents or pool metadata size must be specified")
 	}
 
-	if opts.PrefixedSize.SizePrefix == SizePrefixMinus {
+	if size.SizePrefix == SizePrefixMinus {
 		return fmt.Errorf("size prefix must be positive")
 	} else if opts.PrefixedExtents.SizePrefix == SizePrefixMinus {
 		return fmt.Errorf("extents prefix must be positive")
@@ -91,15 +131,23 @@ func (opts *LVExtendOptions) ApplyToArgs(args Arguments) error {
 		return fmt.Errorf("pool metadata size prefix must be positive")
 	}
 
-	if opts.PoolMetadataPrefixedSize.Val == 0 && opts.PrefixedSize.Val == 0 && opts.Extents.Val == 0 {
+	if !opts.UsePolicies && opts.PoolMetadataPrefixedSize.Val == 0 && size.Val == 0 && opts.Extents.Val == 0 {
 		return errors.New("PoolMetadataPrefixedSize, Size or Extents is required")
 	}
 
+	if err := validateResizeFS(opts.ResizeFS, opts.FS, opts.FSMode); err != nil {
+		return err
+	}
+
 	for _, arg := range []Argument{
 		id,
-		opts.PrefixedSize,
-		opts.PrefixedExtents,
+		opts.PhysicalExtentSelectors,
+		size,
 		opts.PoolMetadataPrefixedSize,
+		opts.UsePolicies,
+		opts.ResizeFS,
+		opts.FS,
+		opts.FSMode,
 		opts.CommonOptions,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
@@ -107,5 +155,12 @@ func (opts *LVExtendOptions) ApplyToArgs(args Arguments) error {
 		}
 	}
 
+	// extents are only passed if set, as their zero value is invalid
+	if opts.PrefixedExtents.Val > 0 {
+		if err := opts.PrefixedExtents.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
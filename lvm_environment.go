@@ -0,0 +1,138 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+const (
+	LVMProfileDirEnv     = "LVM_PROFILE_DIR"
+	LVMCommandProfileEnv = "LVM_COMMAND_PROFILE"
+	DMDisableUdevEnv     = "DM_DISABLE_UDEV"
+)
+
+// ErrInvalidLVMEnvironment is returned by commands of a client with an invalid LVMEnvironment.
+var ErrInvalidLVMEnvironment = errors.New("invalid lvm environment")
+
+// volumeGroupNamePattern are the characters lvm allows in volume group names.
+var volumeGroupNamePattern = regexp.MustCompile(`^[a-zA-Z0-9+_.][a-zA-Z0-9+_.-]*$`)
+
+// LVMEnvironment are the environment variables of lvm that can be set for every command of a client.
+// Each variable can be set with its own typed client option, e.g. LVMSystemDirectory,
+// so that the names of the variables do not have to be passed to Environment.
+// Variables set with Environment or WithCustomEnvironment take precedence.
+type LVMEnvironment struct {
+	LVMSystemDirectory
+	LVMProfileDirectory
+	CommandProfile
+	DefaultVolumeGroupName
+	DisableUdev
+}
+
+func (opt LVMEnvironment) ApplyToClientOptions(opts *ClientOptions) {
+	opts.LVMEnvironment = opt
+}
+
+// LVMSystemDirectory is the directory of lvm.conf and lvmlocal.conf, set as LVM_SYSTEM_DIR.
+type LVMSystemDirectory string
+
+func (opt LVMSystemDirectory) ApplyToClientOptions(opts *ClientOptions) {
+	opts.LVMSystemDirectory = opt
+}
+
+// LVMProfileDirectory is the directory of the configuration profiles, set as LVM_PROFILE_DIR.
+type LVMProfileDirectory string
+
+func (opt LVMProfileDirectory) ApplyToClientOptions(opts *ClientOptions) {
+	opts.LVMProfileDirectory = opt
+}
+
+// CommandProfile is the configuration profile used by every command, set as LVM_COMMAND_PROFILE.
+// Like Profile, it is the name of the profile with an optional .profile extension.
+type CommandProfile string
+
+func (opt CommandProfile) ApplyToClientOptions(opts *ClientOptions) {
+	opts.CommandProfile = opt
+}
+
+// DefaultVolumeGroupName is the volume group lvm uses for logical volume names without a volume group,
+// set as LVM_VG_NAME. It is equivalent to calling WithDefaultVolumeGroup on every context passed to the client.
+type DefaultVolumeGroupName VolumeGroupName
+
+func (opt DefaultVolumeGroupName) ApplyToClientOptions(opts *ClientOptions) {
+	opts.DefaultVolumeGroupName = opt
+}
+
+// DisableUdev makes device-mapper create device nodes itself instead of relying on udev,
+// set as DM_DISABLE_UDEV. This is useful in containers without a running udev.
+type DisableUdev bool
+
+func (opt DisableUdev) ApplyToClientOptions(opts *ClientOptions) {
+	opts.DisableUdev = opt
+}
+
+// Validate checks that the directories are absolute paths and that the names are valid for lvm.
+func (opt LVMEnvironment) Validate() error {
+	for env, dir := range map[string]string{
+		LVMSystemDirEnv:  string(opt.LVMSystemDirectory),
+		LVMProfileDirEnv: string(opt.LVMProfileDirectory),
+	} {
+		if dir != "" && !filepath.IsAbs(dir) {
+			return fmt.Errorf("%w: %s must be an absolute path, got %q", ErrInvalidLVMEnvironment, env, dir)
+		}
+	}
+	if profile := string(opt.CommandProfile); profile != "" {
+		if strings.ContainsRune(profile, filepath.Separator) {
+			return fmt.Errorf("%w: %s must be a profile name, got %q", ErrInvalidLVMEnvironment, LVMCommandProfileEnv, profile)
+		}
+		if ext := filepath.Ext(profile); ext != "" && ext != LVMProfileExtension {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidLVMEnvironment, LVMCommandProfileEnv, ErrInvalidProfileExtension)
+		}
+	}
+	if vg := string(opt.DefaultVolumeGroupName); vg != "" {
+		if !volumeGroupNamePattern.MatchString(vg) || vg == "." || vg == ".." {
+			return fmt.Errorf("%w: %s must be a volume group name, got %q", ErrInvalidLVMEnvironment, DefaultVolumeGroupEnv, vg)
+		}
+	}
+	return nil
+}
+
+// Variables returns the environment variables of the set options.
+func (opt LVMEnvironment) Variables() map[string]string {
+	env := map[string]string{}
+	if opt.LVMSystemDirectory != "" {
+		env[LVMSystemDirEnv] = string(opt.LVMSystemDirectory)
+	}
+	if opt.LVMProfileDirectory != "" {
+		env[LVMProfileDirEnv] = string(opt.LVMProfileDirectory)
+	}
+	if opt.CommandProfile != "" {
+		env[LVMCommandProfileEnv] = strings.TrimSuffix(string(opt.CommandProfile), LVMProfileExtension)
+	}
+	if opt.DefaultVolumeGroupName != "" {
+		env[DefaultVolumeGroupEnv] = string(opt.DefaultVolumeGroupName)
+	}
+	if opt.DisableUdev {
+		env[DMDisableUdevEnv] = "1"
+	}
+	return env
+}
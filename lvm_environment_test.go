@@ -0,0 +1,79 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVMEnvironment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var env []string
+	runner := CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		env = cmd.Env
+		return io.NopCloser(strings.NewReader(`{"report":[{}]}`)), nil
+	})
+
+	clnt := NewClient(runner,
+		LVMSystemDirectory("/etc/lvm-alt"),
+		CommandProfile("thin-performance.profile"),
+		DefaultVolumeGroupName("vg1"),
+		DisableUdev(true),
+	)
+	if _, err := clnt.VGs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{
+		"LVM_SYSTEM_DIR=/etc/lvm-alt",
+		"LVM_COMMAND_PROFILE=thin-performance",
+		"LVM_VG_NAME=vg1",
+		"DM_DISABLE_UDEV=1",
+	} {
+		if !slices.Contains(env, expected) {
+			t.Errorf("expected %s in %v", expected, env)
+		}
+	}
+
+	if _, err := clnt.VGs(WithDefaultVolumeGroup(ctx, "vg2")); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(env, "LVM_VG_NAME=vg2") || slices.Contains(env, "LVM_VG_NAME=vg1") {
+		t.Errorf("expected the default volume group of the context to take precedence in %v", env)
+	}
+
+	for _, invalid := range []LVMEnvironment{
+		{LVMSystemDirectory: "etc/lvm"},
+		{LVMProfileDirectory: "profiles"},
+		{CommandProfile: "../thin"},
+		{CommandProfile: "thin.conf"},
+		{DefaultVolumeGroupName: "-vg"},
+	} {
+		if _, err := NewClient(runner, invalid).VGs(ctx); !errors.Is(err, ErrInvalidLVMEnvironment) {
+			t.Errorf("expected %v for %+v, got %v", ErrInvalidLVMEnvironment, invalid, err)
+		}
+	}
+}
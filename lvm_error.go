@@ -17,144 +17,138 @@
 package lvm2go
 
 import (
-	"fmt"
 	"regexp"
-)
-
-var (
-
-	// NotFoundPatterns are regular expressions that matches the error message when a device, volume group or logical volume is not found.
-
-	volumeGroupNotFoundPattern   = `Volume group "(.*?)" not found`
-	VolumeGroupNotFoundPattern   = regexp.MustCompile(volumeGroupNotFoundPattern)
-	logicalVolumeNotFoundPattern = `Failed to find logical volume "(.*?)"`
-	LogicalVolumeNotFoundPattern = regexp.MustCompile(logicalVolumeNotFoundPattern)
-	deviceNotFoundPattern        = `Couldn't find device with uuid (.{6}-.{4}-.{4}-.{4}-.{4}-.{4}-.{6})`
-	DeviceNotFoundPattern        = regexp.MustCompile(deviceNotFoundPattern)
-	NotFoundPattern              = regexp.MustCompile(fmt.Sprintf(`%s|%s|%s`, volumeGroupNotFoundPattern, logicalVolumeNotFoundPattern, deviceNotFoundPattern))
-
-	// NoSuchCommandPattern is a regular expression that matches the error message when a command is not found.
-	NoSuchCommandPattern = regexp.MustCompile(`no such command`)
 
-	// MaximumNumberOfLogicalVolumesPattern is a regular expression that matches the error message when the maximum number of logical volumes is reached.
-	MaximumNumberOfLogicalVolumesPattern = regexp.MustCompile(`Maximum number of logical volumes \(\d+\) reached in volume group (.*?)`)
+	"github.com/azalio/lvm2go/lvmerrors"
+)
 
-	// MaximumNumberOfPhysicalVolumesPattern is a regular expression that matches the error message when the maximum number of physical volumes is reached.
-	MaximumNumberOfPhysicalVolumesPattern = regexp.MustCompile(`No space for '(.*?)' - volume group '(.*?)' holds max \d+ physical volume\(s\)\.`)
+// The errors of lvm2 commands are classified by the lvmerrors package.
+// The declarations below are aliases of lvmerrors kept for compatibility.
 
-	// CannotChangeVGWhilePVsAreMissingPattern is a regular expression that matches the error message when the volume group is immutable because physical volumes are missing.
-	CannotChangeVGWhilePVsAreMissingPattern = regexp.MustCompile(`Cannot change VG (.*?) while PVs are missing\.`)
+const LVMWarningPrefix = lvmerrors.LVMWarningPrefix
 
-	// CouldNotFindDeviceWithUUIDPattern is a regular expression that matches the error message when a device with a specific UUID is not found.
-	CouldNotFindDeviceWithUUIDPattern = regexp.MustCompile(`Couldn't find device with uuid [\w-]+\.`)
+type (
+	LVMStdErr     = lvmerrors.LVMStdErr
+	Warning       = lvmerrors.Warning
+	ExitCodeError = lvmerrors.ExitCodeError
+)
 
-	// VGMissingPVsPattern is a regular expression that matches the error message when a volume group is missing physical volumes.
-	VGMissingPVsPattern = regexp.MustCompile(`VG (.*?) is missing PV (.*?) \(last written to (.*?)\)`)
+var (
+	VolumeGroupNotFoundPattern                          = lvmerrors.VolumeGroupNotFoundPattern
+	LogicalVolumeNotFoundPattern                        = lvmerrors.LogicalVolumeNotFoundPattern
+	DeviceNotFoundPattern                               = lvmerrors.DeviceNotFoundPattern
+	NotFoundPattern                                     = lvmerrors.NotFoundPattern
+	NoSuchCommandPattern                                = lvmerrors.NoSuchCommandPattern
+	MaximumNumberOfLogicalVolumesPattern                = lvmerrors.MaximumNumberOfLogicalVolumesPattern
+	MaximumNumberOfPhysicalVolumesPattern               = lvmerrors.MaximumNumberOfPhysicalVolumesPattern
+	CannotChangeVGWhilePVsAreMissingPattern             = lvmerrors.CannotChangeVGWhilePVsAreMissingPattern
+	CouldNotFindDeviceWithUUIDPattern                   = lvmerrors.CouldNotFindDeviceWithUUIDPattern
+	VGMissingPVsPattern                                 = lvmerrors.VGMissingPVsPattern
+	ThereAreStillPartialLVsPattern                      = lvmerrors.ThereAreStillPartialLVsPattern
+	PartialLVNeedsRepairOrRemovePattern                 = lvmerrors.PartialLVNeedsRepairOrRemovePattern
+	NoDataToMovePattern                                 = lvmerrors.NoDataToMovePattern
+	NoFreeExtentsPattern                                = lvmerrors.NoFreeExtentsPattern
+	ConfigurationSectionNotCustomizableByProfilePattern = lvmerrors.ConfigurationSectionNotCustomizableByProfilePattern
+	ExtentSizeNotDivisiblePattern                       = lvmerrors.ExtentSizeNotDivisiblePattern
+	IOErrorPattern                                      = lvmerrors.IOErrorPattern
+)
 
-	// ThereAreStillPartialLVsPattern is a regular expression that matches the error message when there are still partial logical volumes in a volume group.
-	ThereAreStillPartialLVsPattern = regexp.MustCompile(`There are still partial LVs in VG (.*?)\.`)
+func AsLVMStdErr(err error) (LVMStdErr, bool) {
+	return lvmerrors.AsLVMStdErr(err)
+}
 
-	// PartialLVNeedsRepairOrRemovePattern is a regular expression that matches the error message when a logical volume needs repair or remove.
-	PartialLVNeedsRepairOrRemovePattern = regexp.MustCompile(`Partial LV (.*?) needs to be repaired or removed\.`)
+func NewLVMStdErr(stderr []byte) LVMStdErr {
+	return lvmerrors.NewLVMStdErr(stderr)
+}
 
-	// NoDataToMovePattern is a regular expression that matches the error message when there is no data to move for a specific volume group during a pvmove operation.
-	NoDataToMovePattern = regexp.MustCompile(`No data to move for (.*?)`)
+func NewWarning(raw []byte) Warning {
+	return lvmerrors.NewWarning(raw)
+}
 
-	// NoFreeExtentsPattern is a regular expression that matches the error message when there are no free extents on a physical volume.
-	NoFreeExtentsPattern = regexp.MustCompile(`No free extents on physical volume "(.*?)"`)
+func AsExitCodeError(err error) (ExitCodeError, bool) {
+	return lvmerrors.AsExitCodeError(err)
+}
 
-	ConfigurationSectionNotCustomizableByProfilePattern = regexp.MustCompile(`Configuration section "(.*?)" is not customizable by a profile\.`)
-)
+func NewExitCodeError(err error) ExitCodeError {
+	return lvmerrors.NewExitCodeError(err)
+}
 
-// IsLVMError returns true if the error is an LVM error with a specific exit code and matches a specific pattern.
-// The validExitCodes are the exit codes that are considered valid for the error.
-// While lvm2go packages a lot of predefined patterns, it is possible to use a custom pattern.
-//
-// Example:
-//
-//	func IsLVMCustomError(err error) bool {
-//		return IsLVMError(err, regexp.MustCompile(`custom error pattern`))
-//	}
 func IsLVMError(err error, pattern *regexp.Regexp) bool {
-	if err == nil {
-		return false
-	}
-
-	if stdErr, ok := AsLVMStdErr(err); ok {
-		for _, line := range stdErr.Lines(true) {
-			if pattern.Match(line) {
-				return true
-			}
-		}
-	}
-
-	return false
+	return lvmerrors.IsLVMError(err, pattern)
 }
 
 func IsNotFound(err error) bool {
-	return IsLVMError(err, NotFoundPattern)
+	return lvmerrors.IsNotFound(err)
 }
 
 func IsVolumeGroupNotFound(err error) bool {
-	return IsLVMError(err, VolumeGroupNotFoundPattern)
+	return lvmerrors.IsVolumeGroupNotFound(err)
 }
 
 func IsLogicalVolumeNotFound(err error) bool {
-	return IsLVMError(err, LogicalVolumeNotFoundPattern)
+	return lvmerrors.IsLogicalVolumeNotFound(err)
 }
 
 func IsDeviceNotFound(err error) bool {
-	return IsLVMError(err, DeviceNotFoundPattern)
+	return lvmerrors.IsDeviceNotFound(err)
 }
 
 func IsNoSuchCommand(err error) bool {
-	return IsLVMError(err, NoSuchCommandPattern)
+	return lvmerrors.IsNoSuchCommand(err)
 }
 
 func IsMaximumLogicalVolumesReached(err error) bool {
-	return IsLVMError(err, MaximumNumberOfLogicalVolumesPattern)
+	return lvmerrors.IsMaximumLogicalVolumesReached(err)
 }
 
 func IsMaximumPhysicalVolumesReached(err error) bool {
-	return IsLVMError(err, MaximumNumberOfPhysicalVolumesPattern)
+	return lvmerrors.IsMaximumPhysicalVolumesReached(err)
 }
 
 func IsVGImmutableDueToMissingPVs(err error) bool {
-	return IsLVMError(err, CannotChangeVGWhilePVsAreMissingPattern)
+	return lvmerrors.IsVGImmutableDueToMissingPVs(err)
 }
 
 func IsCouldNotFindDeviceWithUUID(err error) bool {
-	return IsLVMError(err, CouldNotFindDeviceWithUUIDPattern)
+	return lvmerrors.IsCouldNotFindDeviceWithUUID(err)
 }
 
 func IsVGMissingPVs(err error) bool {
-	return IsLVMError(err, VGMissingPVsPattern)
+	return lvmerrors.IsVGMissingPVs(err)
 }
 
 func VGMissingPVsDetails(err error) (vg string, pv string, lastWrittenTo string, ok bool) {
-	submatches := VGMissingPVsPattern.FindStringSubmatch(err.Error())
-	if submatches == nil {
-		return "", "", "", false
-	}
-	return submatches[1], submatches[2], submatches[3], true
+	return lvmerrors.VGMissingPVsDetails(err)
 }
 
 func IsPartialLVNeedsRepairOrRemove(err error) bool {
-	return IsLVMError(err, PartialLVNeedsRepairOrRemovePattern)
+	return lvmerrors.IsPartialLVNeedsRepairOrRemove(err)
 }
 
 func IsThereAreStillPartialLVs(err error) bool {
-	return IsLVMError(err, ThereAreStillPartialLVsPattern)
+	return lvmerrors.IsThereAreStillPartialLVs(err)
 }
 
 func IsNoDataToMove(err error) bool {
-	return IsLVMError(err, NoDataToMovePattern)
+	return lvmerrors.IsNoDataToMove(err)
 }
 
 func IsNoFreeExtents(err error) bool {
-	return IsLVMError(err, NoFreeExtentsPattern)
+	return lvmerrors.IsNoFreeExtents(err)
 }
 
 func IsConfigurationSectionNotCustomizableByProfile(err error) bool {
-	return IsLVMError(err, ConfigurationSectionNotCustomizableByProfilePattern)
+	return lvmerrors.IsConfigurationSectionNotCustomizableByProfile(err)
+}
+
+func IsExtentSizeNotDivisible(err error) bool {
+	return lvmerrors.IsExtentSizeNotDivisible(err)
+}
+
+func IsIOError(err error) bool {
+	return lvmerrors.IsIOError(err)
+}
+
+func IOErrorDevices(err error) []string {
+	return lvmerrors.IOErrorDevices(err)
 }
@@ -42,7 +42,7 @@ func (c *client) DevCheck(ctx context.Context, opts ...DevCheckOption) error {
 		return err
 	}
 
-	return c.RunRaw(
+	return c.RunLVMRaw(
 		ctx,
 		NoOpRawOutputProcessor(),
 		append([]string{"lvmdevices", "--check"}, args.GetRaw()...)...,
@@ -103,7 +103,7 @@ func (c *client) DevModify(ctx context.Context, opts ...DevModifyOption) error {
 		return err
 	}
 
-	return c.RunRaw(
+	return c.RunLVMRaw(
 		ctx,
 		NoOpRawOutputProcessor(),
 		append([]string{"lvmdevices"}, args.GetRaw()...)...,
@@ -91,3 +91,64 @@ func verifyDevListEntryForLoopBackDevice(
 		t.Fatalf("Expected PVID %q, got %q", ePVID, dev.PVID)
 	}
 }
+
+func TestParseDevUpdateResult(t *testing.T) {
+	t.Parallel()
+	out := `  Device /dev/sdc has updated name (devices file /dev/sdb)
+  Devices file PVID 8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U updating IDNAME to 0x5000c500a1b2c3d4.
+  Removing not found PVID qTm1X9aZrLkE4uVb7sWc2NdY0fHgJ6pO from devices file.
+  Updated devices file to version 1.1.7
+`
+	result, err := ParseDevUpdateResult(strings.NewReader(out))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.HasChanges() {
+		t.Fatal("expected changes")
+	}
+	if len(result.DevNamesCorrected) != 1 ||
+		result.DevNamesCorrected[0].Field != "DEVNAME" ||
+		result.DevNamesCorrected[0].Old != "/dev/sdb" ||
+		result.DevNamesCorrected[0].New != "/dev/sdc" {
+		t.Fatalf("unexpected corrected device names: %+v", result.DevNamesCorrected)
+	}
+	if len(result.Updated) != 1 ||
+		result.Updated[0].PVID != "8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U" ||
+		result.Updated[0].Field != "IDNAME" ||
+		result.Updated[0].Old != "" ||
+		result.Updated[0].New != "0x5000c500a1b2c3d4" {
+		t.Fatalf("unexpected updated entries: %+v", result.Updated)
+	}
+	if len(result.Removed) != 1 || result.Removed[0].PVID != "qTm1X9aZrLkE4uVb7sWc2NdY0fHgJ6pO" {
+		t.Fatalf("unexpected removed entries: %+v", result.Removed)
+	}
+	if len(result.Messages) != 1 {
+		t.Fatalf("expected 1 unattributed message, got %v", result.Messages)
+	}
+}
+
+func TestParseDevUpdateResultUpdated(t *testing.T) {
+	t.Parallel()
+	for line, expected := range map[string]DevUpdateChange{
+		"  Devices file PVID 8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U updating IDNAME to 0x5000c500a1b2c3d4.": {
+			PVID: "8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U", Field: "IDNAME", New: "0x5000c500a1b2c3d4",
+		},
+		"  Devices file PVID 8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U updating IDNAME to /dev/sdb.": {
+			PVID: "8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U", Field: "IDNAME", New: "/dev/sdb",
+		},
+		"  Devices file PVID 8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U updating IDTYPE to devname.": {
+			PVID: "8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U", Field: "IDTYPE", New: "devname",
+		},
+		"  Devices file PVID 8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U updating IDNAME from /dev/sdc to /dev/sdb.": {
+			PVID: "8Bq2mFfZxKcP0tDnVp1rJ4QeLh7sYw3U", Field: "IDNAME", Old: "/dev/sdc", New: "/dev/sdb",
+		},
+	} {
+		result, err := ParseDevUpdateResult(strings.NewReader(line))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Updated) != 1 || result.Updated[0] != expected {
+			t.Errorf("expected %+v for %q, got %+v", expected, line, result.Updated)
+		}
+	}
+}
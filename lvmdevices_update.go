@@ -17,7 +17,11 @@
 package lvm2go
 
 import (
+	"bufio"
 	"context"
+	"io"
+	"regexp"
+	"strings"
 )
 
 type DeleteNotFound bool
@@ -51,17 +55,96 @@ var (
 	_ Argument          = (*DevUpdateOptions)(nil)
 )
 
-func (c *client) DevUpdate(ctx context.Context, opts ...DevUpdateOption) error {
+// DevUpdateChange is a single change to an entry of the devices file reported by lvmdevices --update.
+// Depending on the change, not all fields are set.
+type DevUpdateChange struct {
+	PVID    string
+	DevName string
+	// Field is the changed field of the entry, e.g. IDNAME or DEVNAME.
+	Field string
+	// Old is the previous value of the changed field, e.g. the previous device name.
+	// It is empty if lvm2 does not report it.
+	Old string
+	// New is the new value of the changed field, e.g. the corrected device name.
+	New string
+}
+
+// DevUpdateResult is a summary of the changes made to the devices file by lvmdevices --update.
+type DevUpdateResult struct {
+	// Updated are the entries whose device id was updated.
+	Updated []DevUpdateChange
+	// Removed are the entries that were removed because their device was not found.
+	Removed []DevUpdateChange
+	// DevNamesCorrected are the entries whose device name was corrected.
+	DevNamesCorrected []DevUpdateChange
+	// Messages are all lines of the output that could not be attributed to a change.
+	Messages []string
+}
+
+// HasChanges returns true if any entry of the devices file was changed.
+func (r *DevUpdateResult) HasChanges() bool {
+	return len(r.Updated) > 0 || len(r.Removed) > 0 || len(r.DevNamesCorrected) > 0
+}
+
+var (
+	devUpdateDevNameCorrectedRegex = regexp.MustCompile(`^Device (\S+) has updated name \(devices file (\S+)\)`)
+	devUpdateUpdatedRegex          = regexp.MustCompile(`^Devices file PVID (\w+) updating (\w+)(?: from (\S+))? to (\S+?)\.?$`)
+	devUpdateRemovedRegex          = regexp.MustCompile(`^(?:Removing|Deleting)\b.*\bPVID (\w+)`)
+)
+
+// ParseDevUpdateResult parses the output of lvmdevices --update into a DevUpdateResult.
+func ParseDevUpdateResult(out io.Reader) (*DevUpdateResult, error) {
+	result := &DevUpdateResult{}
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if match := devUpdateDevNameCorrectedRegex.FindStringSubmatch(line); match != nil {
+			result.DevNamesCorrected = append(result.DevNamesCorrected, DevUpdateChange{
+				DevName: match[1],
+				Field:   "DEVNAME",
+				Old:     match[2],
+				New:     match[1],
+			})
+		} else if match := devUpdateUpdatedRegex.FindStringSubmatch(line); match != nil {
+			result.Updated = append(result.Updated, DevUpdateChange{
+				PVID:  match[1],
+				Field: match[2],
+				Old:   match[3],
+				New:   match[4],
+			})
+		} else if match := devUpdateRemovedRegex.FindStringSubmatch(line); match != nil {
+			result.Removed = append(result.Removed, DevUpdateChange{
+				PVID: match[1],
+			})
+		} else {
+			result.Messages = append(result.Messages, line)
+		}
+	}
+	return result, scanner.Err()
+}
+
+func (c *client) DevUpdate(ctx context.Context, opts ...DevUpdateOption) (*DevUpdateResult, error) {
 	args, err := DevUpdateOptionsList(opts).AsArgs()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return c.RunRaw(
+	var result *DevUpdateResult
+	if err := c.RunLVMRaw(
 		ctx,
-		NoOpRawOutputProcessor(),
+		func(out io.Reader) error {
+			result, err = ParseDevUpdateResult(out)
+			return err
+		},
 		append([]string{"lvmdevices", "--update"}, args.GetRaw()...)...,
-	)
+	); err != nil {
+		return nil, err
+	}
+
+	return result, nil
 }
 
 func (list DevUpdateOptionsList) AsArgs() (Arguments, error) {
@@ -0,0 +1,201 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// LVMDumpOptions describe the diagnostics collected by LVMDump.
+	// Without LVMDumpDirectory, lvmdump creates a tarball in the working directory of the command.
+	LVMDumpOptions struct {
+		LVMDumpAdvanced
+		LVMDumpMetadata
+		LVMDumpUdev
+		LVMDumpLvmlockd
+		LVMDumpLvmpolld
+		LVMDumpSystemInfo
+		LVMDumpDirectory
+	}
+	LVMDumpOption interface {
+		ApplyToLVMDumpOptions(opts *LVMDumpOptions)
+	}
+	LVMDumpOptionsList []LVMDumpOption
+)
+
+// LVMDumpAdvanced collects the advanced debug output of lvm2 commands, which can take a long time.
+type LVMDumpAdvanced bool
+
+// LVMDumpMetadata collects the raw metadata areas of all physical volumes.
+type LVMDumpMetadata bool
+
+// LVMDumpUdev collects the udev environment, rules and database.
+type LVMDumpUdev bool
+
+// LVMDumpLvmlockd collects the state of lvmlockd.
+type LVMDumpLvmlockd bool
+
+// LVMDumpLvmpolld collects the state of lvmpolld.
+type LVMDumpLvmpolld bool
+
+// LVMDumpSystemInfo collects system information and the state of lvm2 related systemd units.
+type LVMDumpSystemInfo bool
+
+// LVMDumpDirectory collects the diagnostics into the given directory instead of a tarball.
+// The directory must not exist yet.
+type LVMDumpDirectory string
+
+func (opt LVMDumpAdvanced) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpAdvanced = opt
+}
+func (opt LVMDumpMetadata) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpMetadata = opt
+}
+func (opt LVMDumpUdev) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpUdev = opt
+}
+func (opt LVMDumpLvmlockd) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpLvmlockd = opt
+}
+func (opt LVMDumpLvmpolld) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpLvmpolld = opt
+}
+func (opt LVMDumpSystemInfo) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpSystemInfo = opt
+}
+func (opt LVMDumpDirectory) ApplyToLVMDumpOptions(opts *LVMDumpOptions) {
+	opts.LVMDumpDirectory = opt
+}
+
+func (opt LVMDumpAdvanced) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-a")
+}
+func (opt LVMDumpMetadata) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-m")
+}
+func (opt LVMDumpUdev) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-u")
+}
+func (opt LVMDumpLvmlockd) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-l")
+}
+func (opt LVMDumpLvmpolld) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-p")
+}
+func (opt LVMDumpSystemInfo) ApplyToArgs(args Arguments) error {
+	return lvmDumpFlag(args, bool(opt), "-s")
+}
+func (opt LVMDumpDirectory) ApplyToArgs(args Arguments) error {
+	if opt != "" {
+		args.AddOrReplaceAll([]string{"-d", string(opt)})
+	}
+	return nil
+}
+
+func lvmDumpFlag(args Arguments, set bool, flag string) error {
+	if set {
+		args.AddOrReplaceAll([]string{flag})
+	}
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = LVMDumpOptionsList{}
+	_ Argument          = (*LVMDumpOptions)(nil)
+)
+
+const (
+	lvmDumpTarballPrefix   = "Creating report tarball in "
+	lvmDumpDirectoryPrefix = "Creating dump directory: "
+)
+
+func (c *client) LVMDump(ctx context.Context, opts ...LVMDumpOption) (string, error) {
+	args, err := LVMDumpOptionsList(opts).AsArgs()
+	if err != nil {
+		return "", err
+	}
+
+	var path string
+	err = c.RunRaw(ctx, func(out io.Reader) error {
+		path, err = parseLVMDumpOutput(out)
+		return err
+	}, append([]string{"lvmdump"}, args.GetRaw()...)...)
+	if err != nil {
+		return "", err
+	}
+	if path == "" {
+		return "", fmt.Errorf("lvmdump did not report the location of the dump")
+	}
+	return path, nil
+}
+
+// parseLVMDumpOutput returns the tarball created by lvmdump, or the dump directory if no tarball was created.
+func parseLVMDumpOutput(out io.Reader) (string, error) {
+	var dir, tarball string
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if path, ok := strings.CutPrefix(line, lvmDumpTarballPrefix); ok {
+			tarball = strings.TrimSuffix(path, "...")
+		} else if path, ok := strings.CutPrefix(line, lvmDumpDirectoryPrefix); ok {
+			dir = path
+		}
+	}
+	if tarball != "" {
+		return tarball, scanner.Err()
+	}
+	return dir, scanner.Err()
+}
+
+func (list LVMDumpOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := LVMDumpOptions{}
+	for _, opt := range list {
+		opt.ApplyToLVMDumpOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *LVMDumpOptions) ApplyToLVMDumpOptions(new *LVMDumpOptions) {
+	*new = *opts
+}
+
+func (opts *LVMDumpOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.LVMDumpAdvanced,
+		opts.LVMDumpMetadata,
+		opts.LVMDumpUdev,
+		opts.LVMDumpLvmlockd,
+		opts.LVMDumpLvmpolld,
+		opts.LVMDumpSystemInfo,
+		opts.LVMDumpDirectory,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
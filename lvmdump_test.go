@@ -0,0 +1,65 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVMDump(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{
+		{
+			Command: "lvmdump",
+			Args:    []string{"-m", "-u", "-l"},
+			Stdout: " Creating dump directory: /root/lvmdump-host-20241016120000\n" +
+				" \n" +
+				" Gathering LVM & device-mapper version info...\n" +
+				" Gathering dmsetup info...\n" +
+				" Gathering udev info...\n" +
+				" Creating report tarball in /root/lvmdump-host-20241016120000.tgz...\n",
+		},
+		{
+			Command: "lvmdump",
+			Args:    []string{"-s", "-d", "/tmp/dump"},
+			Stdout: " Creating dump directory: /tmp/dump\n" +
+				" \n" +
+				" Gathering system info...\n",
+		},
+	}))
+
+	path, err := clnt.LVMDump(ctx, LVMDumpMetadata(true), LVMDumpUdev(true), LVMDumpLvmlockd(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/root/lvmdump-host-20241016120000.tgz" {
+		t.Fatalf("expected tarball path, got %q", path)
+	}
+
+	path, err = clnt.LVMDump(ctx, LVMDumpSystemInfo(true), LVMDumpDirectory("/tmp/dump"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/tmp/dump" {
+		t.Fatalf("expected dump directory, got %q", path)
+	}
+}
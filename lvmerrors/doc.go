@@ -0,0 +1,36 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package lvmerrors classifies the errors of lvm2 commands run by lvm2go.
+//
+// Errors of lvm2 commands are made up of up to two parts, which are joined into the returned error:
+//   - an ExitCodeError carrying the exit code of the command, see AsExitCodeError,
+//   - an LVMStdErr carrying the deduplicated lines written to stderr, including warnings, see AsLVMStdErr.
+//
+// As lvm2 reports the cause of a failure only as a message on stderr, the messages are classified
+// into stable sentinel errors that can be matched with errors.Is, independent of the regular expressions
+// used to recognize them. The sentinel errors form a taxonomy:
+//   - ErrNotFound: ErrVolumeGroupNotFound, ErrLogicalVolumeNotFound and ErrDeviceNotFound,
+//   - ErrLimitReached: ErrMaximumLogicalVolumesReached and ErrMaximumPhysicalVolumesReached,
+//   - ErrMissingPhysicalVolumes: ErrVGImmutableDueToMissingPVs, ErrCouldNotFindDeviceWithUUID, ErrVGMissingPVs,
+//     ErrThereAreStillPartialLVs and ErrPartialLVNeedsRepairOrRemove,
+//   - ErrNoSuchCommand, ErrNoDataToMove, ErrNoFreeExtents, ErrConfigurationSectionNotCustomizableByProfile,
+//     ErrExtentSizeNotDivisible and ErrIOError.
+//
+// For example, errors.Is(err, ErrNotFound) reports whether lvm2 did not find a volume group, logical volume or device.
+// The Is helpers, such as IsNotFound, are shorthands for the same checks, and IsLVMError matches custom patterns.
+// The root package lvm2go keeps aliases of all helpers for compatibility.
+package lvmerrors
@@ -0,0 +1,106 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvmerrors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+var (
+	// ErrNotFound is the category of errors about volume groups, logical volumes or devices that do not exist.
+	ErrNotFound = errors.New("not found")
+	// ErrVolumeGroupNotFound is matched by errors reporting that a volume group does not exist.
+	ErrVolumeGroupNotFound = fmt.Errorf("volume group %w", ErrNotFound)
+	// ErrLogicalVolumeNotFound is matched by errors reporting that a logical volume does not exist.
+	ErrLogicalVolumeNotFound = fmt.Errorf("logical volume %w", ErrNotFound)
+	// ErrDeviceNotFound is matched by errors reporting that the device of a physical volume is not available.
+	ErrDeviceNotFound = fmt.Errorf("device %w", ErrNotFound)
+
+	// ErrLimitReached is the category of errors about configured maximums of a volume group.
+	ErrLimitReached = errors.New("limit reached")
+	// ErrMaximumLogicalVolumesReached is matched by errors reporting that a volume group holds its maximum of logical volumes.
+	ErrMaximumLogicalVolumesReached = fmt.Errorf("maximum number of logical volumes: %w", ErrLimitReached)
+	// ErrMaximumPhysicalVolumesReached is matched by errors reporting that a volume group holds its maximum of physical volumes.
+	ErrMaximumPhysicalVolumesReached = fmt.Errorf("maximum number of physical volumes: %w", ErrLimitReached)
+
+	// ErrMissingPhysicalVolumes is the category of errors caused by physical volumes missing from a volume group.
+	ErrMissingPhysicalVolumes = errors.New("missing physical volumes")
+	// ErrVGImmutableDueToMissingPVs is matched by errors reporting that a volume group cannot be changed while physical volumes are missing.
+	ErrVGImmutableDueToMissingPVs = fmt.Errorf("volume group cannot be changed: %w", ErrMissingPhysicalVolumes)
+	// ErrCouldNotFindDeviceWithUUID is matched by errors reporting that the device of a physical volume with a UUID is missing.
+	ErrCouldNotFindDeviceWithUUID = fmt.Errorf("device with uuid not found: %w", ErrMissingPhysicalVolumes)
+	// ErrVGMissingPVs is matched by errors reporting which physical volumes a volume group is missing.
+	ErrVGMissingPVs = fmt.Errorf("volume group is missing physical volumes: %w", ErrMissingPhysicalVolumes)
+	// ErrThereAreStillPartialLVs is matched by errors reporting that partial logical volumes prevent the change of a volume group.
+	ErrThereAreStillPartialLVs = fmt.Errorf("partial logical volumes left: %w", ErrMissingPhysicalVolumes)
+	// ErrPartialLVNeedsRepairOrRemove is matched by errors reporting that a partial logical volume has to be repaired or removed.
+	ErrPartialLVNeedsRepairOrRemove = fmt.Errorf("partial logical volume needs to be repaired or removed: %w", ErrMissingPhysicalVolumes)
+
+	// ErrNoSuchCommand is matched by errors reporting that lvm2 does not know the command.
+	ErrNoSuchCommand = errors.New("no such command")
+	// ErrNoDataToMove is matched by errors reporting that pvmove found no data to move.
+	ErrNoDataToMove = errors.New("no data to move")
+	// ErrNoFreeExtents is matched by errors reporting that a physical volume has no free extents.
+	ErrNoFreeExtents = errors.New("no free extents")
+	// ErrConfigurationSectionNotCustomizableByProfile is matched by errors reporting that a profile sets a section it must not.
+	ErrConfigurationSectionNotCustomizableByProfile = errors.New("configuration section not customizable by profile")
+	// ErrExtentSizeNotDivisible is matched by errors reporting that a segment is not a multiple of a new extent size.
+	ErrExtentSizeNotDivisible = errors.New("extent size does not divide segment")
+	// ErrIOError is matched by errors reporting that lvm2 failed to read from or write to a device.
+	ErrIOError = errors.New("device io error")
+)
+
+// classes recognize the sentinel errors in the stderr of lvm2. Categories are matched
+// through the sentinel errors wrapping them.
+var classes = []struct {
+	err     error
+	pattern *regexp.Regexp
+}{
+	{ErrVolumeGroupNotFound, VolumeGroupNotFoundPattern},
+	{ErrLogicalVolumeNotFound, LogicalVolumeNotFoundPattern},
+	{ErrDeviceNotFound, DeviceNotFoundPattern},
+	{ErrMaximumLogicalVolumesReached, MaximumNumberOfLogicalVolumesPattern},
+	{ErrMaximumPhysicalVolumesReached, MaximumNumberOfPhysicalVolumesPattern},
+	{ErrVGImmutableDueToMissingPVs, CannotChangeVGWhilePVsAreMissingPattern},
+	{ErrCouldNotFindDeviceWithUUID, CouldNotFindDeviceWithUUIDPattern},
+	{ErrVGMissingPVs, VGMissingPVsPattern},
+	{ErrThereAreStillPartialLVs, ThereAreStillPartialLVsPattern},
+	{ErrPartialLVNeedsRepairOrRemove, PartialLVNeedsRepairOrRemovePattern},
+	{ErrNoSuchCommand, NoSuchCommandPattern},
+	{ErrNoDataToMove, NoDataToMovePattern},
+	{ErrNoFreeExtents, NoFreeExtentsPattern},
+	{ErrConfigurationSectionNotCustomizableByProfile, ConfigurationSectionNotCustomizableByProfilePattern},
+	{ErrExtentSizeNotDivisible, ExtentSizeNotDivisiblePattern},
+	{ErrIOError, IOErrorPattern},
+}
+
+// Is reports whether the stderr contains a message classified as target or as an error of the category target.
+func (e *stdErr) Is(target error) bool {
+	for _, class := range classes {
+		if !errors.Is(class.err, target) {
+			continue
+		}
+		for _, line := range e.Lines(true) {
+			if class.pattern.Match(line) {
+				return true
+			}
+		}
+	}
+	return false
+}
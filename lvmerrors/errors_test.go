@@ -0,0 +1,78 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvmerrors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/azalio/lvm2go/lvmerrors"
+)
+
+func TestClassification(t *testing.T) {
+	t.Parallel()
+
+	for name, tc := range map[string]struct {
+		stderr    string
+		is        []error
+		isNot     []error
+		shorthand func(error) bool
+	}{
+		"volume group not found": {
+			stderr:    "  Volume group \"vg\" not found\n  Cannot process volume group vg\n",
+			is:        []error{ErrVolumeGroupNotFound, ErrNotFound},
+			isNot:     []error{ErrLogicalVolumeNotFound, ErrMissingPhysicalVolumes},
+			shorthand: IsVolumeGroupNotFound,
+		},
+		"missing physical volumes": {
+			stderr:    "  WARNING: VG vg is missing PV abc (last written to /dev/sdb).\n  Cannot change VG vg while PVs are missing.\n",
+			is:        []error{ErrVGMissingPVs, ErrVGImmutableDueToMissingPVs, ErrMissingPhysicalVolumes},
+			isNot:     []error{ErrNotFound, ErrLimitReached},
+			shorthand: IsVGImmutableDueToMissingPVs,
+		},
+		"maximum logical volumes": {
+			stderr:    "  Maximum number of logical volumes (1) reached in volume group vg\n",
+			is:        []error{ErrMaximumLogicalVolumesReached, ErrLimitReached},
+			isNot:     []error{ErrMaximumPhysicalVolumesReached},
+			shorthand: IsMaximumLogicalVolumesReached,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// errors of commands join the stderr with the exit code error and are wrapped by callers
+			err := fmt.Errorf("failed: %w", errors.Join(NewLVMStdErr([]byte(tc.stderr)), NewExitCodeError(errors.New("exit status 5"))))
+			for _, target := range tc.is {
+				if !errors.Is(err, target) {
+					t.Errorf("expected error to be %v", target)
+				}
+			}
+			for _, target := range tc.isNot {
+				if errors.Is(err, target) {
+					t.Errorf("expected error not to be %v", target)
+				}
+			}
+			if !tc.shorthand(err) {
+				t.Error("expected shorthand to match")
+			}
+		})
+	}
+
+	if !IsNotFound(ErrLogicalVolumeNotFound) || IsNotFound(ErrNoFreeExtents) {
+		t.Fatal("expected sentinel errors to be classified by their category")
+	}
+}
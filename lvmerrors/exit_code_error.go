@@ -14,7 +14,7 @@
  limitations under the License.
 */
 
-package lvm2go
+package lvmerrors
 
 import (
 	"errors"
@@ -0,0 +1,331 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+// Package lvmtest provides helpers to test code using lvm2go against realistic broken states of lvm2,
+// such as volume groups with missing physical volumes, full thin pools or suspended logical volumes.
+//
+// The helpers create loopback devices and volume groups on the host and therefore require root privileges.
+// They are destructive and must never be used outside of test environments.
+package lvmtest
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/azalio/lvm2go"
+)
+
+var ErrNoPhysicalVolumesInSpec = errors.New("at least one physical volume is required to build a degraded volume group")
+
+// Degradation describes a broken state that is injected into a volume group.
+// Degradations are used to test recovery logic against realistic broken states.
+type Degradation interface {
+	fmt.Stringer
+	// Degrade injects the broken state into the given volume group.
+	Degrade(ctx context.Context, client lvm2go.Client, vg *DegradedVolumeGroup) error
+}
+
+// DegradedVolumeGroupSpec is a declarative description of a volume group in a degraded state.
+// The volume group is backed by loopback devices and the Degradations are applied in order
+// after the volume group was created.
+// Degradations that remove physical volumes, such as MissingPhysicalVolume, should be ordered last
+// as lvm2 refuses most changes to a volume group with missing physical volumes.
+//
+// Example:
+//
+//	for _, degradations := range lvmtest.DegradationMatrix(
+//		&lvmtest.FullThinPool{Name: "pool", Size: lvm2go.MustParseSize("100M")},
+//		&lvmtest.SuspendedLogicalVolume{Name: "lv", Size: lvm2go.MustParseSize("100M")},
+//		&lvmtest.MissingPhysicalVolume{Index: 1},
+//	) {
+//		spec := lvmtest.DegradedVolumeGroupSpec{
+//			PhysicalVolumes: []lvm2go.Size{lvm2go.MustParseSize("1G"), lvm2go.MustParseSize("1G")},
+//			Degradations:    degradations,
+//		}
+//		t.Run(spec.String(), func(t *testing.T) {
+//			vg, err := spec.Build(ctx, client)
+//			if err != nil {
+//				t.Fatal(err)
+//			}
+//			defer vg.Teardown(ctx)
+//			// test recovery logic against vg.Name
+//		})
+//	}
+type DegradedVolumeGroupSpec struct {
+	// Name is the name of the volume group. If empty, a random name is generated.
+	Name lvm2go.VolumeGroupName
+	// PhysicalVolumes are the sizes of the loopback devices used as physical volumes.
+	PhysicalVolumes []lvm2go.Size
+	// Degradations are applied in order after the volume group was created.
+	Degradations []Degradation
+}
+
+func (spec DegradedVolumeGroupSpec) String() string {
+	if len(spec.Degradations) == 0 {
+		return "healthy"
+	}
+	names := make([]string, 0, len(spec.Degradations))
+	for _, degradation := range spec.Degradations {
+		names = append(names, degradation.String())
+	}
+	return strings.Join(names, "+")
+}
+
+// DegradationMatrix returns all non-empty combinations of the given degradations.
+// The order of the degradations is preserved within each combination.
+// This can be used to generate a test matrix covering all combinations of broken states.
+func DegradationMatrix(degradations ...Degradation) [][]Degradation {
+	var matrix [][]Degradation
+	for mask := 1; mask < 1<<len(degradations); mask++ {
+		var combination []Degradation
+		for i, degradation := range degradations {
+			if mask&(1<<i) != 0 {
+				combination = append(combination, degradation)
+			}
+		}
+		matrix = append(matrix, combination)
+	}
+	return matrix
+}
+
+// DegradedVolumeGroup is a volume group built from a DegradedVolumeGroupSpec.
+// It has to be torn down with Teardown after use.
+type DegradedVolumeGroup struct {
+	Name        lvm2go.VolumeGroupName
+	LoopDevices []lvm2go.LoopbackDevice
+
+	client    lvm2go.Client
+	suspended []string
+	missing   bool
+}
+
+// Build creates the loopback devices and the volume group described by the spec
+// and applies all degradations in order.
+// If building the volume group fails, everything created so far is torn down.
+func (spec DegradedVolumeGroupSpec) Build(ctx context.Context, client lvm2go.Client) (*DegradedVolumeGroup, error) {
+	if len(spec.PhysicalVolumes) == 0 {
+		return nil, ErrNoPhysicalVolumesInSpec
+	}
+
+	vg := &DegradedVolumeGroup{Name: spec.Name, client: client}
+	if vg.Name == "" {
+		id, err := randomID()
+		if err != nil {
+			return nil, err
+		}
+		vg.Name = lvm2go.VolumeGroupName(fmt.Sprintf("degraded-%s", id))
+	}
+
+	build := func() error {
+		for _, size := range spec.PhysicalVolumes {
+			loop, err := lvm2go.NewLoopbackDevice(size)
+			if err != nil {
+				return fmt.Errorf("failed to create loopback device: %w", err)
+			}
+			vg.LoopDevices = append(vg.LoopDevices, loop)
+		}
+
+		var devices []string
+		for _, loop := range vg.LoopDevices {
+			devices = append(devices, loop.Device())
+		}
+		if err := client.VGCreate(ctx, vg.Name, lvm2go.PhysicalVolumesFrom(devices...)); err != nil {
+			return fmt.Errorf("failed to create volume group: %w", err)
+		}
+
+		for _, degradation := range spec.Degradations {
+			if err := degradation.Degrade(ctx, client, vg); err != nil {
+				return fmt.Errorf("failed to apply degradation %s: %w", degradation, err)
+			}
+		}
+		return nil
+	}
+
+	if err := build(); err != nil {
+		return nil, errors.Join(err, vg.Teardown(ctx))
+	}
+
+	return vg, nil
+}
+
+// Teardown resumes suspended logical volumes, removes the volume group
+// and closes all loopback devices.
+// Teardown continues on errors and returns all errors joined together.
+func (vg *DegradedVolumeGroup) Teardown(ctx context.Context) error {
+	var errs []error
+
+	for _, name := range vg.suspended {
+		if err := dmsetup(ctx, vg.client, "resume", name); err != nil {
+			errs = append(errs, fmt.Errorf("failed to resume %s: %w", name, err))
+		}
+	}
+	vg.suspended = nil
+
+	if vg.missing {
+		if err := vg.client.VGReduce(ctx, vg.Name, lvm2go.RemoveMissing(true), lvm2go.Force(true)); err != nil && !lvm2go.IsNotFound(err) {
+			errs = append(errs, fmt.Errorf("failed to remove missing physical volumes: %w", err))
+		}
+	}
+
+	if err := vg.client.VGRemove(ctx, vg.Name, lvm2go.Force(true)); err != nil && !lvm2go.IsNotFound(err) && !lvm2go.IsVolumeGroupNotFound(err) {
+		errs = append(errs, fmt.Errorf("failed to remove volume group: %w", err))
+	}
+
+	for _, loop := range vg.LoopDevices {
+		if err := loop.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close loopback device %s: %w", loop.Device(), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MissingPhysicalVolume detaches the loopback device with the given Index in the spec,
+// so that its physical volume is reported as missing by lvm2.
+type MissingPhysicalVolume struct {
+	Index int
+}
+
+func (d *MissingPhysicalVolume) String() string {
+	return fmt.Sprintf("missing-pv-%d", d.Index)
+}
+
+func (d *MissingPhysicalVolume) Degrade(_ context.Context, _ lvm2go.Client, vg *DegradedVolumeGroup) error {
+	if d.Index < 0 || d.Index >= len(vg.LoopDevices) {
+		return fmt.Errorf("physical volume index %d out of range, volume group has %d physical volumes", d.Index, len(vg.LoopDevices))
+	}
+	if err := vg.LoopDevices[d.Index].Close(); err != nil {
+		return err
+	}
+	vg.missing = true
+	return nil
+}
+
+// FullThinPool creates a thin pool of the given Size and fills its data space completely
+// through a thin volume that is bigger than the thin pool.
+// The thin pool is configured to error when full, so that further writes fail instead of being queued.
+type FullThinPool struct {
+	Name lvm2go.LogicalVolumeName
+	Size lvm2go.Size
+}
+
+func (d *FullThinPool) String() string {
+	return fmt.Sprintf("full-thin-pool-%s", d.Name)
+}
+
+func (d *FullThinPool) Degrade(ctx context.Context, client lvm2go.Client, vg *DegradedVolumeGroup) error {
+	pool, err := lvm2go.NewThinPool(vg.Name, d.Name)
+	if err != nil {
+		return err
+	}
+	if err := client.LVCreate(ctx, vg.Name, d.Name, d.Size, lvm2go.Thin(true)); err != nil {
+		return err
+	}
+	errorWhenFull := lvm2go.ErrorWhenFull(true)
+	if err := client.LVChange(ctx, vg.Name, d.Name, &errorWhenFull); err != nil {
+		return err
+	}
+
+	// the created thin pool can be bigger than requested due to rounding to extents
+	lv, err := client.LV(ctx, vg.Name, d.Name, lvm2go.UnitBytes)
+	if err != nil {
+		return err
+	}
+	size, err := lv.Size.ToUnit(lvm2go.UnitBytes)
+	if err != nil {
+		return err
+	}
+
+	fill := lvm2go.LogicalVolumeName(fmt.Sprintf("%s_fill", d.Name))
+	if err := client.LVCreate(ctx, pool, fill, lvm2go.NewSize(size.Val*2, lvm2go.UnitBytes).Virtual()); err != nil {
+		return err
+	}
+
+	return fillDevice(filepath.Join("/dev", string(vg.Name), string(fill)), int64(size.Val))
+}
+
+// SuspendedLogicalVolume creates a logical volume of the given Size and suspends it with dmsetup suspend,
+// so that all IO to it blocks until it is resumed.
+type SuspendedLogicalVolume struct {
+	Name lvm2go.LogicalVolumeName
+	Size lvm2go.Size
+}
+
+func (d *SuspendedLogicalVolume) String() string {
+	return fmt.Sprintf("suspended-lv-%s", d.Name)
+}
+
+func (d *SuspendedLogicalVolume) Degrade(ctx context.Context, client lvm2go.Client, vg *DegradedVolumeGroup) error {
+	if err := client.LVCreate(ctx, vg.Name, d.Name, d.Size); err != nil {
+		return err
+	}
+	name := (&lvm2go.FQLogicalVolumeName{VolumeGroupName: vg.Name, LogicalVolumeName: d.Name}).DeviceMapperName()
+	if err := dmsetup(ctx, client, "suspend", name); err != nil {
+		return err
+	}
+	vg.suspended = append(vg.suspended, name)
+	return nil
+}
+
+// fillDevice writes size bytes of non-zero data to the beginning of the device at path.
+func fillDevice(path string, size int64) error {
+	device, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	defer device.Close()
+
+	chunk := []byte(strings.Repeat("lvm2go", 1<<16/len("lvm2go")+1))[:1<<16]
+	for written := int64(0); written < size; {
+		n := min(int64(len(chunk)), size-written)
+		if _, err := device.Write(chunk[:n]); err != nil {
+			return fmt.Errorf("failed to fill %s: %w", path, err)
+		}
+		written += n
+	}
+
+	return device.Sync()
+}
+
+// dmsetup runs dmsetup through the client, so that it is executed in the same environment as lvm2,
+// with the context and lock of wrapped clients, see lvm2go.ClientImplements.
+func dmsetup(ctx context.Context, client lvm2go.Client, args ...string) error {
+	runner, ok := lvm2go.ClientImplements[lvm2go.RawCommandRunner](client)
+	if !ok {
+		return fmt.Errorf("%w: client cannot run raw commands", errors.ErrUnsupported)
+	}
+	return runner.RunRaw(ctx, lvm2go.NoOpRawOutputProcessor(), append([]string{"dmsetup"}, args...)...)
+}
+
+// randomID returns a random identifier for the names of built volume groups.
+func randomID() (string, error) {
+	hash := fnv.New32()
+	randomData := make([]byte, 32)
+	if _, err := rand.Read(randomData); err != nil {
+		return "", err
+	}
+	if _, err := hash.Write(randomData); err != nil {
+		return "", err
+	}
+	return strconv.Itoa(int(hash.Sum32())), nil
+}
@@ -0,0 +1,57 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvmtest_test
+
+import (
+	"testing"
+
+	. "github.com/azalio/lvm2go/lvmtest"
+)
+
+func TestDegradationMatrix(t *testing.T) {
+	t.Parallel()
+	matrix := DegradationMatrix(
+		&FullThinPool{Name: "pool"},
+		&SuspendedLogicalVolume{Name: "lv"},
+		&MissingPhysicalVolume{Index: 1},
+	)
+	if len(matrix) != 7 {
+		t.Fatalf("expected 7 combinations, got %d", len(matrix))
+	}
+
+	names := make(map[string]struct{})
+	for _, degradations := range matrix {
+		names[DegradedVolumeGroupSpec{Degradations: degradations}.String()] = struct{}{}
+	}
+	for _, expected := range []string{
+		"full-thin-pool-pool",
+		"suspended-lv-lv",
+		"missing-pv-1",
+		"full-thin-pool-pool+suspended-lv-lv",
+		"full-thin-pool-pool+missing-pv-1",
+		"suspended-lv-lv+missing-pv-1",
+		"full-thin-pool-pool+suspended-lv-lv+missing-pv-1",
+	} {
+		if _, ok := names[expected]; !ok {
+			t.Fatalf("expected combination %q in matrix, got %v", expected, names)
+		}
+	}
+
+	if name := (DegradedVolumeGroupSpec{}).String(); name != "healthy" {
+		t.Fatalf("expected spec without degradations to be healthy, got %q", name)
+	}
+}
@@ -18,7 +18,6 @@ package lvm2go
 
 import (
 	"context"
-	"errors"
 	"fmt"
 )
 
@@ -26,6 +25,14 @@ type (
 	LVReduceOptions struct {
 		VolumeGroupName
 		LogicalVolumeName
+
+		PrefixedSize
+		SizeDelta
+
+		ResizeFS
+		FS
+		FSMode
+
 		CommonOptions
 	}
 	LVReduceOption interface {
@@ -37,6 +44,7 @@ type (
 var (
 	_ ArgumentGenerator = LVReduceOptionsList{}
 	_ Argument          = (*LVReduceOptions)(nil)
+	_ LVReduceOption    = (*LVReduceOptions)(nil)
 )
 
 func (c *client) LVReduce(ctx context.Context, opts ...LVReduceOption) error {
@@ -48,10 +56,58 @@ func (c *client) LVReduce(ctx context.Context, opts ...LVReduceOption) error {
 	return c.RunLVM(ctx, append([]string{"lvreduce"}, args.GetRaw()...)...)
 }
 
+func (opts *LVReduceOptions) ApplyToLVReduceOptions(new *LVReduceOptions) {
+	*new = *opts
+}
+
 func (list LVReduceOptionsList) AsArgs() (Arguments, error) {
-	return nil, fmt.Errorf("not implemented: %w", errors.ErrUnsupported)
+	args := NewArgs(ArgsTypeGeneric)
+	options := LVReduceOptions{}
+	for _, opt := range list {
+		opt.ApplyToLVReduceOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
 }
 
-func (opts *LVReduceOptions) ApplyToArgs(_ Arguments) error {
-	return fmt.Errorf("not implemented: %w", errors.ErrUnsupported)
+// ApplyToArgs reduces to an absolute size or by a size with SizePrefixMinus or a shrinking SizeDelta.
+// lvreduce does not resize the filesystem on the logical volume unless ResizeFS or FSResize is set,
+// see also ShrinkLVWithFS.
+func (opts *LVReduceOptions) ApplyToArgs(args Arguments) error {
+	id, err := NewFQLogicalVolumeName(opts.VolumeGroupName, opts.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	size, err := opts.SizeDelta.orPrefixedSize(opts.PrefixedSize)
+	if err != nil {
+		return err
+	}
+
+	if size.Val <= 0 {
+		return fmt.Errorf("size must be specified")
+	}
+	if size.SizePrefix == SizePrefixPlus {
+		return fmt.Errorf("size prefix must be negative")
+	}
+
+	if err := validateResizeFS(opts.ResizeFS, opts.FS, opts.FSMode); err != nil {
+		return err
+	}
+
+	for _, arg := range []Argument{
+		id,
+		size,
+		opts.ResizeFS,
+		opts.FS,
+		opts.FSMode,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
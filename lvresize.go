@@ -18,6 +18,7 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
 )
 
 type (
@@ -26,6 +27,13 @@ type (
 		VolumeGroupName
 
 		PrefixedSize
+		SizeDelta
+		PrefixedExtents
+		UsePolicies
+
+		ResizeFS
+		FS
+		FSMode
 
 		CommonOptions
 	}
@@ -70,10 +78,32 @@ func (opts *LVResizeOptions) ApplyToArgs(args Arguments) error {
 	if err != nil {
 		return err
 	}
+	size, err := opts.SizeDelta.orPrefixedSize(opts.PrefixedSize)
+	if err != nil {
+		return err
+	}
+
+	sizes := 0
+	for _, set := range []bool{size.Val > 0, opts.PrefixedExtents.Val > 0, bool(opts.UsePolicies)} {
+		if set {
+			sizes++
+		}
+	}
+	if sizes > 1 {
+		return fmt.Errorf("size, extents and use policies are mutually exclusive")
+	}
+
+	if err := validateResizeFS(opts.ResizeFS, opts.FS, opts.FSMode); err != nil {
+		return err
+	}
 
 	for _, opt := range []Argument{
 		id,
-		opts.PrefixedSize,
+		size,
+		opts.UsePolicies,
+		opts.ResizeFS,
+		opts.FS,
+		opts.FSMode,
 		opts.CommonOptions,
 	} {
 		if err := opt.ApplyToArgs(args); err != nil {
@@ -81,5 +111,12 @@ func (opts *LVResizeOptions) ApplyToArgs(args Arguments) error {
 		}
 	}
 
+	// extents are only passed if set, as their zero value is invalid
+	if opts.PrefixedExtents.Val > 0 {
+		if err := opts.PrefixedExtents.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -18,15 +18,32 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
+	"slices"
 )
 
 type (
+	// LVsOptions limit the report to logical volumes matching the options.
+	// VolumeGroupName, VolumeGroupNames and FQLogicalVolumeNames are passed as positional arguments,
+	// so that lvm itself limits the scope of the report to the union of the named volume groups
+	// and logical volumes. A LogicalVolumeName is only used together with a VolumeGroupName.
 	LVsOptions struct {
 		VolumeGroupName
 		LogicalVolumeName
+		VolumeGroupNames
+		FQLogicalVolumeNames
 		Tags
 		Unit
 		Select
+		ByUUID
+		RetainRawJSON
+		ExtraColumns
+		ReportLocking
+		SortFields
+
+		// All includes hidden logical volumes created internally by lvm2, e.g. the images of raid volumes
+		// or the data and metadata of thin pools. Like lvs, LVs omits them by default. See InternalType.
+		All
 
 		ColumnOptions
 		CommonOptions
@@ -57,6 +74,18 @@ func (c *client) LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalVolume,
 	args := []string{
 		"lvs", "--reportformat", "json",
 	}
+	// the report locking of the client is applied first, so that it can be overridden per report
+	opts = append([]LVsOption{c.opts.ReportLocking}, opts...)
+	options := LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	typedFields := reportFields[LogicalVolume]()
+	strictColumns := bool(c.opts.StrictDecoding) && len(options.ColumnOptions) == 0
+	if strictColumns {
+		opts = append(slices.Clone(opts), typedFields)
+	}
+
 	argsFromOpts, err := LVsOptionsList(opts).AsArgs()
 	if err != nil {
 		return nil, err
@@ -64,8 +93,9 @@ func (c *client) LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalVolume,
 
 	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
 
+	// names that are not found are skipped, the other names are still reported
 	if IsNotFound(err) {
-		return nil, nil
+		err = nil
 	}
 
 	if err != nil {
@@ -82,6 +112,27 @@ func (c *client) LVs(ctx context.Context, opts ...LVsOption) ([]*LogicalVolume,
 		return nil, nil
 	}
 
+	if c.opts.StrictDecoding {
+		for _, lv := range lvs {
+			if err := verifyReportFields(lv.raw, append(slices.Clone(typedFields), options.ExtraColumns...), strictColumns, bool(options.RetainRawJSON)); err != nil {
+				return nil, fmt.Errorf("failed to decode logical volume %s strictly: %w", lv.FullName, err)
+			}
+		}
+	}
+
+	for _, lv := range lvs {
+		if lv.ExtraFields, err = extraReportFields(lv.raw, typedFields); err != nil {
+			return nil, fmt.Errorf("failed to decode extra fields of logical volume %s: %w", lv.FullName, err)
+		}
+	}
+
+	// the raw JSON is always captured while decoding and dropped here unless it was requested
+	if !options.RetainRawJSON {
+		for _, lv := range lvs {
+			lv.raw = nil
+		}
+	}
+
 	return lvs, nil
 }
 
@@ -118,14 +169,38 @@ func (c *client) LV(ctx context.Context, opts ...LVsOption) (*LogicalVolume, err
 	return lvs[0], nil
 }
 
+// InScope reports whether a logical volume is within the scope of the names of the options.
+// It can be used by clients that filter reports themselves instead of passing the names to lvm.
+func (opts *LVsOptions) InScope(vg VolumeGroupName, lv LogicalVolumeName) bool {
+	if opts.VolumeGroupName == "" && len(opts.VolumeGroupNames) == 0 && len(opts.FQLogicalVolumeNames) == 0 {
+		return true
+	}
+	if opts.VolumeGroupName == vg && (opts.LogicalVolumeName == "" || opts.LogicalVolumeName == lv) {
+		return true
+	}
+	return slices.Contains(opts.VolumeGroupNames, vg) || slices.ContainsFunc(opts.FQLogicalVolumeNames, func(name *FQLogicalVolumeName) bool {
+		return name.VolumeGroupName == vg && name.LogicalVolumeName == lv
+	})
+}
+
 func (opts *LVsOptions) ApplyToArgs(args Arguments) error {
+	var scope Argument = opts.VolumeGroupName
+	if opts.VolumeGroupName != "" && opts.LogicalVolumeName != "" {
+		scope = &FQLogicalVolumeName{VolumeGroupName: opts.VolumeGroupName, LogicalVolumeName: opts.LogicalVolumeName}
+	}
+
 	for _, arg := range []Argument{
-		opts.VolumeGroupName,
+		scope,
+		opts.VolumeGroupNames,
+		opts.FQLogicalVolumeNames,
 		opts.Tags,
+		opts.All,
 		opts.Unit,
+		opts.ReportLocking,
 		opts.CommonOptions,
-		opts.ColumnOptions,
-		opts.Select,
+		opts.ColumnOptions.withExtra(opts.ExtraColumns),
+		opts.SortFields,
+		opts.ByUUID.selectOn(LVUUID, opts.Select),
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
 			return err
@@ -0,0 +1,78 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+)
+
+type (
+	// LVScanOptions describe a scan of all volume groups for logical volumes.
+	// The found logical volumes are written to the log, use LVs to retrieve them.
+	LVScanOptions struct {
+		All
+
+		CommonOptions
+	}
+	LVScanOption interface {
+		ApplyToLVScanOptions(opts *LVScanOptions)
+	}
+	LVScanOptionsList []LVScanOption
+)
+
+var (
+	_ ArgumentGenerator = LVScanOptionsList{}
+	_ Argument          = (*LVScanOptions)(nil)
+)
+
+func (c *client) LVScan(ctx context.Context, opts ...LVScanOption) error {
+	args, err := LVScanOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"lvscan"}, args.GetRaw()...)...)
+}
+
+func (list LVScanOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := LVScanOptions{}
+	for _, opt := range list {
+		opt.ApplyToLVScanOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *LVScanOptions) ApplyToLVScanOptions(new *LVScanOptions) {
+	*new = *opts
+}
+
+func (opts *LVScanOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.All,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
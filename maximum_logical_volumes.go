@@ -20,8 +20,13 @@ import (
 	"fmt"
 )
 
+// MaximumLogicalVolumes limits the number of logical volumes in a volume group.
+// It is passed as --logicalvolume to VGChange and as --maxlogicalvolumes otherwise.
 type MaximumLogicalVolumes int
 
+// UnlimitedLogicalVolumes removes the limit of the number of logical volumes, as the zero value is not passed.
+const UnlimitedLogicalVolumes MaximumLogicalVolumes = -1
+
 func (opt MaximumLogicalVolumes) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 	opts.MaximumLogicalVolumes = opt
 }
@@ -30,10 +35,16 @@ func (opt MaximumLogicalVolumes) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.MaximumLogicalVolumes = opt
 }
 
+func (opt MaximumLogicalVolumes) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.MaximumLogicalVolumes = opt
+}
+
 func (opt MaximumLogicalVolumes) ApplyToArgs(args Arguments) error {
 	if opt == 0 {
 		return nil
 	}
+	// lvm2 treats a limit of 0 as unlimited
+	opt = max(opt, 0)
 	switch args.GetType() {
 	case ArgsTypeVGChange:
 		args.AddOrReplace(fmt.Sprintf("--logicalvolume=%d", opt))
@@ -20,8 +20,12 @@ import (
 	"fmt"
 )
 
+// MaximumPhysicalVolumes limits the number of physical volumes in a volume group.
 type MaximumPhysicalVolumes int
 
+// UnlimitedPhysicalVolumes removes the limit of the number of physical volumes, as the zero value is not passed.
+const UnlimitedPhysicalVolumes MaximumPhysicalVolumes = -1
+
 func (opt MaximumPhysicalVolumes) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 	opts.MaximumPhysicalVolumes = opt
 }
@@ -30,10 +34,15 @@ func (opt MaximumPhysicalVolumes) ApplyToVGCreateOptions(opts *VGCreateOptions)
 	opts.MaximumPhysicalVolumes = opt
 }
 
+func (opt MaximumPhysicalVolumes) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.MaximumPhysicalVolumes = opt
+}
+
 func (opt MaximumPhysicalVolumes) ApplyToArgs(args Arguments) error {
 	if opt == 0 {
 		return nil
 	}
-	args.AddOrReplace(fmt.Sprintf("--maxphysicalvolumes=%d", opt))
+	// lvm2 treats a limit of 0 as unlimited
+	args.AddOrReplace(fmt.Sprintf("--maxphysicalvolumes=%d", max(opt, 0)))
 	return nil
 }
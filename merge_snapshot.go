@@ -0,0 +1,160 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrSnapshotMergeDeferred is returned by LVMergeSnapshot if the origin is open.
+// lvm2 starts the merge when the origin is next activated.
+var ErrSnapshotMergeDeferred = errors.New("snapshot merge deferred until the origin is next activated")
+
+type (
+	LVMergeSnapshotOptions struct {
+		MergeWaitInterval
+		MergeProgress
+		ProgressFunc
+	}
+	LVMergeSnapshotOption interface {
+		ApplyToLVMergeSnapshotOptions(opts *LVMergeSnapshotOptions)
+	}
+)
+
+// MergeWaitInterval makes LVMergeSnapshot block until the merge is complete,
+// polling the progress of the merge at the interval.
+type MergeWaitInterval time.Duration
+
+func (opt MergeWaitInterval) ApplyToLVMergeSnapshotOptions(opts *LVMergeSnapshotOptions) {
+	opts.MergeWaitInterval = opt
+}
+
+// MergeProgress is called with the progress of the merge in percent every time LVMergeSnapshot polls it,
+// and with 100 once the merge is complete. It is only called together with MergeWaitInterval.
+type MergeProgress func(percent float64)
+
+func (opt MergeProgress) ApplyToLVMergeSnapshotOptions(opts *LVMergeSnapshotOptions) {
+	opts.MergeProgress = opt
+}
+
+// MergeSnapshot merges a snapshot into its origin with LVConvert, reverting the origin to the state of the snapshot.
+// The snapshot is removed after the merge. If the origin is open, the merge is deferred until it is next activated.
+type MergeSnapshot bool
+
+func (opt MergeSnapshot) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--mergesnapshot"})
+	}
+	return nil
+}
+
+func (opt MergeSnapshot) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.MergeSnapshot = opt
+}
+
+// LVMergeSnapshot merges the snapshot into its origin with lvconvert --merge, rolling the origin back
+// to the state of the snapshot. The snapshot is removed once the merge is complete.
+//
+// Without MergeWaitInterval, LVMergeSnapshot returns as soon as lvm2 started merging in the background.
+// With MergeWaitInterval, it blocks until the snapshot is gone and reports the progress to MergeProgress.
+// The progress of copy-on-write snapshots is derived from snap_percent, which starts at the fill level of the
+// snapshot and drops to 0 while the changed blocks are copied back, so the progress is the share of the
+// initial fill level that was merged. Merges of thin snapshots complete at once.
+//
+// If the origin is open, e.g. because it is mounted, lvm2 defers the merge until the origin is next
+// activated and ErrSnapshotMergeDeferred is returned without waiting. A merge that lvm2 reports as failed
+// while waiting results in ErrSnapshotMergeFailed.
+func LVMergeSnapshot(ctx context.Context, client Client, snapshot *FQLogicalVolumeName, opts ...LVMergeSnapshotOption) error {
+	options := LVMergeSnapshotOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVMergeSnapshotOptions(&options)
+	}
+
+	if err := snapshot.Validate(); err != nil {
+		return err
+	}
+	lv, err := client.LV(ctx, snapshot.VolumeGroupName, snapshot.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	if lv.Origin == "" {
+		return fmt.Errorf("%s is not a snapshot", snapshot)
+	}
+	origin, err := client.LV(ctx, snapshot.VolumeGroupName, LogicalVolumeName(lv.Origin))
+	if err != nil {
+		return err
+	}
+
+	if err := client.LVConvert(ctx, snapshot.VolumeGroupName, snapshot.LogicalVolumeName, MergeSnapshot(true)); err != nil {
+		return err
+	}
+
+	if origin.Attr.Open == OpenTrue {
+		return fmt.Errorf("%w: origin %s/%s is open", ErrSnapshotMergeDeferred, origin.VolumeGroupName, origin.Name)
+	}
+	if options.MergeWaitInterval <= 0 {
+		return nil
+	}
+	return waitForSnapshotMerge(ctx, client, snapshot, lv.SnapPercent, options)
+}
+
+// waitForSnapshotMerge waits for the merge of the snapshot, whose snap_percent was initial when the merge started.
+func waitForSnapshotMerge(ctx context.Context, client Client, snapshot *FQLogicalVolumeName, initial float64, options LVMergeSnapshotOptions) error {
+	tracker := NewProgressTracker(ProgressOperationMerge, 0)
+	progress := func(percent float64, done bool) {
+		if options.MergeProgress != nil {
+			options.MergeProgress(percent)
+		}
+		options.ProgressFunc.report(tracker.Update(percent, done))
+	}
+
+	ticker := time.NewTicker(time.Duration(options.MergeWaitInterval))
+	defer ticker.Stop()
+
+	for {
+		lvs, err := client.LVs(ctx, FQLogicalVolumeNames{snapshot})
+		if err != nil {
+			return err
+		}
+		if len(lvs) == 0 {
+			progress(100, true)
+			return nil
+		}
+		if state := lvs[0].Attr.State; state == StateSnapshotMergeFailed || state == StateSuspendedSnapshotMergeFailed {
+			return fmt.Errorf("%s: %w", snapshot, ErrSnapshotMergeFailed)
+		}
+		progress(mergeProgress(initial, lvs[0].SnapPercent), false)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// mergeProgress returns the progress of a merge in percent from the snap_percent of the snapshot
+// when the merge started and now. A snapshot without changes has nothing to merge.
+func mergeProgress(initial, current float64) float64 {
+	if initial <= 0 {
+		return 0
+	}
+	return min(max((initial-current)/initial*100, 0), 100)
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+// snapshotMergeRunner reports a copy-on-write snapshot whose merge progresses with every poll.
+func snapshotMergeRunner(originAttr string, snapPercents ...string) CommandRunner {
+	return func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		report := func(lvs ...string) (io.ReadCloser, error) {
+			return io.NopCloser(strings.NewReader(fmt.Sprintf(`{"report":[{"lv":[%s]}]}`, strings.Join(lvs, ",")))), nil
+		}
+		switch {
+		case slices.Contains(cmd.Args, "lvconvert"):
+			return io.NopCloser(strings.NewReader("")), nil
+		case slices.Contains(cmd.Args, "vg/origin"):
+			return report(fmt.Sprintf(`{"lv_name":"origin","vg_name":"vg","lv_attr":%q}`, originAttr))
+		case len(snapPercents) == 0:
+			return report()
+		}
+		snapPercent := snapPercents[0]
+		snapPercents = snapPercents[1:]
+		return report(fmt.Sprintf(`{"lv_name":"snap","vg_name":"vg","origin":"origin","lv_attr":"Swi-a-s---","snap_percent":%q}`, snapPercent))
+	}
+}
+
+func TestLVMergeSnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	snapshot := MustNewFQLogicalVolumeName("vg", "snap")
+
+	var progress []float64
+	// the snapshot is 20% full when the merge starts
+	clnt := NewClient(snapshotMergeRunner("Owi-a-s---", "20.00", "15.00", "5.00"))
+	if err := LVMergeSnapshot(ctx, clnt, snapshot, MergeWaitInterval(time.Millisecond), MergeProgress(func(percent float64) {
+		progress = append(progress, percent)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	// the first report is the lookup of the snapshot before the merge is started
+	if !slices.Equal(progress, []float64{25, 75, 100}) {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+
+	clnt = NewClient(snapshotMergeRunner("Owi-aos---", "80.00"))
+	if err := LVMergeSnapshot(ctx, clnt, snapshot, MergeWaitInterval(time.Millisecond)); !errors.Is(err, ErrSnapshotMergeDeferred) {
+		t.Fatalf("expected deferred merge, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"cmp"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LVMArchiveDirName is the directory in LVMSystemDir in which lvm2 archives the metadata of a volume group
+// before every change to it.
+const LVMArchiveDirName = "archive"
+
+// MetadataArchiveDir returns the directory of the metadata archives, usually /etc/lvm/archive.
+func MetadataArchiveDir() string {
+	return filepath.Join(LVMSystemDir(), LVMArchiveDirName)
+}
+
+// archiveFileName matches archive files named like vg_00042-1234567890.vg.
+var archiveFileName = regexp.MustCompile(`^(.+)_(\d+)-\d+\.vg$`)
+
+// MetadataVersion is a version of the metadata of a volume group in the text format of lvm2,
+// as written to the metadata archive and backup directories.
+// It can be restored with VGCfgRestore and BackupFile.
+type MetadataVersion struct {
+	File            string
+	VolumeGroupName VolumeGroupName
+	SeqNo           int64
+	// Description is the description lvm2 gave the version, e.g. Created *before* executing 'lvremove vg/lv'.
+	Description  string
+	CreationHost string
+	CreationTime time.Time
+
+	// values are the settings of the volume group, keyed by their path below the volume group section,
+	// e.g. logical_volumes/lv/segment1/extent_count.
+	values map[string]string
+}
+
+// MetadataChangeKind is the kind of change of a setting between two metadata versions.
+type MetadataChangeKind string
+
+const (
+	MetadataAdded   MetadataChangeKind = "added"
+	MetadataRemoved MetadataChangeKind = "removed"
+	MetadataChanged MetadataChangeKind = "changed"
+)
+
+// MetadataChange is a setting that differs between two metadata versions.
+// Old is empty for added settings and New is empty for removed settings.
+type MetadataChange struct {
+	Key  string
+	Kind MetadataChangeKind
+	Old  string
+	New  string
+}
+
+func (c MetadataChange) String() string {
+	switch c.Kind {
+	case MetadataAdded:
+		return fmt.Sprintf("+ %s = %s", c.Key, c.New)
+	case MetadataRemoved:
+		return fmt.Sprintf("- %s = %s", c.Key, c.Old)
+	default:
+		return fmt.Sprintf("~ %s = %s -> %s", c.Key, c.Old, c.New)
+	}
+}
+
+// ListMetadataArchives reads the archived metadata versions of the volume group in dir, usually MetadataArchiveDir,
+// ordered by their sequence number, oldest first. If vg is empty, the versions of all volume groups are listed.
+func ListMetadataArchives(dir string, vg VolumeGroupName) ([]*MetadataVersion, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var versions []*MetadataVersion
+	for _, entry := range entries {
+		match := archiveFileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil || (vg != "" && VolumeGroupName(match[1]) != vg) {
+			continue
+		}
+		version, err := ReadMetadataVersion(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	slices.SortStableFunc(versions, func(a, b *MetadataVersion) int {
+		if a.VolumeGroupName != b.VolumeGroupName {
+			return strings.Compare(string(a.VolumeGroupName), string(b.VolumeGroupName))
+		}
+		if a.SeqNo != b.SeqNo {
+			return cmp.Compare(a.SeqNo, b.SeqNo)
+		}
+		return a.CreationTime.Compare(b.CreationTime)
+	})
+	return versions, nil
+}
+
+// ReadMetadataVersion reads a metadata archive or backup file.
+func ReadMetadataVersion(path string) (*MetadataVersion, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	version, err := ParseMetadataVersion(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metadata in %s: %w", path, err)
+	}
+	version.File = path
+	return version, nil
+}
+
+// ParseMetadataVersion parses metadata in the text format of lvm2.
+func ParseMetadataVersion(r io.Reader) (*MetadataVersion, error) {
+	version := &MetadataVersion{values: map[string]string{}}
+
+	var sections []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(stripMetadataComment(scanner.Text()))
+		switch {
+		case line == "":
+			continue
+		case line == "}":
+			if len(sections) == 0 {
+				return nil, fmt.Errorf("unexpected closing brace")
+			}
+			sections = sections[:len(sections)-1]
+			continue
+		case strings.HasSuffix(line, "{"):
+			name := strings.TrimSpace(strings.TrimSuffix(line, "{"))
+			if len(sections) == 0 {
+				if version.VolumeGroupName != "" {
+					return nil, fmt.Errorf("unexpected second volume group %s", name)
+				}
+				version.VolumeGroupName = VolumeGroupName(name)
+			}
+			sections = append(sections, name)
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("unexpected line without assignment: %s", line)
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		// lists such as stripes can span multiple lines
+		for strings.HasPrefix(value, "[") && !strings.HasSuffix(value, "]") && scanner.Scan() {
+			value += " " + strings.TrimSpace(stripMetadataComment(scanner.Text()))
+		}
+		value = normalizeMetadataValue(value)
+
+		if len(sections) > 0 {
+			version.values[strings.Join(append(sections[1:], key), "/")] = value
+			if len(sections) == 1 && key == "seqno" {
+				seqNo, err := strconv.ParseInt(value, 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("failed to parse seqno: %w", err)
+				}
+				version.SeqNo = seqNo
+			}
+			continue
+		}
+
+		switch key {
+		case "description":
+			version.Description = value
+		case "creation_host":
+			version.CreationHost = value
+		case "creation_time":
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse creation_time: %w", err)
+			}
+			version.CreationTime = time.Unix(seconds, 0)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(sections) > 0 {
+		return nil, fmt.Errorf("unexpected end of metadata in section %s", strings.Join(sections, "/"))
+	}
+	if version.VolumeGroupName == "" {
+		return nil, fmt.Errorf("no volume group found")
+	}
+	return version, nil
+}
+
+// stripMetadataComment removes a trailing # comment outside of quotes.
+func stripMetadataComment(line string) string {
+	quoted := false
+	for i, r := range line {
+		switch r {
+		case '"':
+			quoted = !quoted
+		case '#':
+			if !quoted {
+				return line[:i]
+			}
+		}
+	}
+	return line
+}
+
+// normalizeMetadataValue unquotes strings, also in lists, so that values only differ in their content.
+func normalizeMetadataValue(value string) string {
+	if strings.HasPrefix(value, "[") {
+		elems := strings.Split(strings.Trim(value, "[]"), ",")
+		for i, elem := range elems {
+			elems[i] = strings.Trim(strings.TrimSpace(elem), `"`)
+		}
+		if len(elems) == 1 && elems[0] == "" {
+			return "[]"
+		}
+		return "[" + strings.Join(elems, ", ") + "]"
+	}
+	return strings.Trim(value, `"`)
+}
+
+// Get returns the value of a setting of the volume group by its path below the volume group section,
+// e.g. logical_volumes/lv/segment1/extent_count. Strings are unquoted.
+func (v *MetadataVersion) Get(key string) (string, bool) {
+	value, ok := v.values[key]
+	return value, ok
+}
+
+// Diff returns the settings of the volume group that changed from v to newer, ordered by key.
+// The description and creation fields of the versions are not compared.
+func (v *MetadataVersion) Diff(newer *MetadataVersion) []MetadataChange {
+	var changes []MetadataChange
+	for key, old := range v.values {
+		if value, ok := newer.values[key]; !ok {
+			changes = append(changes, MetadataChange{Key: key, Kind: MetadataRemoved, Old: old})
+		} else if value != old {
+			changes = append(changes, MetadataChange{Key: key, Kind: MetadataChanged, Old: old, New: value})
+		}
+	}
+	for key, value := range newer.values {
+		if _, ok := v.values[key]; !ok {
+			changes = append(changes, MetadataChange{Key: key, Kind: MetadataAdded, New: value})
+		}
+	}
+	slices.SortFunc(changes, func(a, b MetadataChange) int {
+		return strings.Compare(a.Key, b.Key)
+	})
+	return changes
+}
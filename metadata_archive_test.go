@@ -0,0 +1,146 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+const archivedMetadata = `# Generated by LVM2 version 2.03.16(2) (2022-05-18): Mon Oct 14 10:00:00 2024
+
+contents = "Text Format Volume Group"
+version = 1
+
+description = "Created *before* executing 'lvextend -L 16M vg/lv'"
+
+creation_host = "host"	# Linux host 6.1.0 #1 SMP x86_64
+creation_time = 1728900000	# Mon Oct 14 10:00:00 2024
+
+vg {
+	id = "vg-uuid"
+	seqno = %d
+	format = "lvm2"			# informational
+	status = ["RESIZEABLE", "READ", "WRITE"]
+	extent_size = 8192		# 4 Megabytes
+
+	physical_volumes {
+
+		pv0 {
+			id = "pv-uuid"
+			device = "/dev/sdb"	# Hint only
+			pe_count = 25	# 100 Megabytes
+		}
+	}
+
+	logical_volumes {
+
+		lv {
+			id = "lv-uuid"
+			status = ["READ", "WRITE", "VISIBLE"]
+			segment_count = 1
+
+			segment1 {
+				start_extent = 0
+				extent_count = %d	# %d Megabytes
+
+				type = "striped"
+				stripe_count = 1	# linear
+
+				stripes = [
+					"pv0", 0
+				]
+			}
+		}
+%s	}
+
+}
+`
+
+func TestMetadataArchives(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+
+	for name, content := range map[string]string{
+		"vg_00001-2222.vg":    fmtMetadata(3, 2, ""),
+		"vg_00000-1111.vg":    fmtMetadata(2, 1, ""),
+		"vg_other_00000-1.vg": fmtMetadata(1, 1, ""),
+		"unrelated.txt":       "",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	versions, err := ListMetadataArchives(dir, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(versions) != 2 || versions[0].SeqNo != 2 || versions[1].SeqNo != 3 {
+		t.Fatalf("expected versions 2 and 3 of vg, got %v", versions)
+	}
+	old := versions[0]
+	if old.File != filepath.Join(dir, "vg_00000-1111.vg") || old.VolumeGroupName != "vg" ||
+		old.Description != "Created *before* executing 'lvextend -L 16M vg/lv'" || old.CreationHost != "host" ||
+		!old.CreationTime.Equal(time.Unix(1728900000, 0)) {
+		t.Fatalf("unexpected version %+v", old)
+	}
+	if status, _ := old.Get("status"); status != "[RESIZEABLE, READ, WRITE]" {
+		t.Fatalf("unexpected status %q", status)
+	}
+	if stripes, _ := old.Get("logical_volumes/lv/segment1/stripes"); stripes != "[pv0, 0]" {
+		t.Fatalf("unexpected stripes %q", stripes)
+	}
+
+	if all, err := ListMetadataArchives(dir, ""); err != nil {
+		t.Fatal(err)
+	} else if len(all) != 3 {
+		t.Fatalf("expected 3 versions, got %d", len(all))
+	}
+
+	changes := old.Diff(versions[1])
+	expected := []MetadataChange{
+		{Key: "logical_volumes/lv/segment1/extent_count", Kind: MetadataChanged, Old: "1", New: "2"},
+		{Key: "seqno", Kind: MetadataChanged, Old: "2", New: "3"},
+	}
+	if !slices.Equal(changes, expected) {
+		t.Fatalf("expected %v, got %v", expected, changes)
+	}
+
+	newer, err := ParseMetadataVersion(strings.NewReader(fmtMetadata(4, 2, "\t\tlv2 {\n\t\t\tid = \"lv2-uuid\"\n\t\t}\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	changes = versions[1].Diff(newer)
+	if len(changes) != 2 || changes[0] != (MetadataChange{Key: "logical_volumes/lv2/id", Kind: MetadataAdded, New: "lv2-uuid"}) {
+		t.Fatalf("unexpected changes %v", changes)
+	}
+	if reverse := newer.Diff(versions[1]); reverse[0].Kind != MetadataRemoved || reverse[0].String() != "- logical_volumes/lv2/id = lv2-uuid" {
+		t.Fatalf("unexpected changes %v", reverse)
+	}
+}
+
+func fmtMetadata(seqNo, extents int, extraLVs string) string {
+	return fmt.Sprintf(archivedMetadata, seqNo, extents, extents*4, extraLVs)
+}
@@ -33,3 +33,7 @@ func (opt Mirrors) ApplyToArgs(args Arguments) error {
 func (opt Mirrors) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Mirrors = opt
 }
+
+func (opt Mirrors) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Mirrors = &opt
+}
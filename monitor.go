@@ -0,0 +1,95 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// Monitor controls the monitoring of logical volumes by dmeventd, which is used e.g. for the
+// automatic extension of thin pools and snapshots or the repair of failed raid images.
+// Monitoring has to be stopped before a node is drained, otherwise dmeventd keeps the devices open.
+type Monitor string
+
+const (
+	// MonitorStart starts monitoring of the logical volumes by dmeventd.
+	MonitorStart Monitor = "y"
+	// MonitorStop stops monitoring of the logical volumes by dmeventd.
+	MonitorStop Monitor = "n"
+)
+
+func (opt Monitor) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--monitor=%s", string(opt)))
+	return nil
+}
+
+func (opt Monitor) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.Monitor = opt
+}
+
+func (opt Monitor) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.Monitor = opt
+}
+
+func (opt Monitor) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.Monitor = opt
+}
+
+// IgnoreMonitoring makes lvm2 neither start nor stop monitoring by dmeventd when logical volumes are
+// activated, deactivated or created, e.g. in containers that cannot reach the dmeventd of the host.
+// It is mutually exclusive with Monitor.
+type IgnoreMonitoring bool
+
+func (opt IgnoreMonitoring) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--ignoremonitoring"})
+	}
+	return nil
+}
+
+func (opt IgnoreMonitoring) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.IgnoreMonitoring = opt
+}
+
+func (opt IgnoreMonitoring) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.IgnoreMonitoring = opt
+}
+
+func (opt IgnoreMonitoring) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.IgnoreMonitoring = opt
+}
+
+// validateMonitoring returns an error if monitoring is both controlled and ignored.
+func validateMonitoring(monitor Monitor, ignore IgnoreMonitoring) error {
+	if monitor != "" && bool(ignore) {
+		return fmt.Errorf("Monitor and IgnoreMonitoring are mutually exclusive")
+	}
+	return nil
+}
+
+// SegmentMonitor is the monitoring status of a segment by dmeventd as reported in LogicalVolumeSegment.Monitor.
+// It is empty for segments that cannot be monitored.
+type SegmentMonitor string
+
+const (
+	SegmentMonitored    SegmentMonitor = "monitored"
+	SegmentNotMonitored SegmentMonitor = "not monitored"
+	SegmentMonitorFail  SegmentMonitor = "failed"
+)
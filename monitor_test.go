@@ -0,0 +1,46 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestIgnoreMonitoring(t *testing.T) {
+	t.Parallel()
+
+	args, err := VGChangeOptionsList{VolumeGroupName("vg"), Activate, IgnoreMonitoring(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--ignoremonitoring") {
+		t.Fatalf("expected --ignoremonitoring in %v", args.GetRaw())
+	}
+	args, err = LVCreateOptionList{VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("1G"), TypeThinPool, MonitorStop}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--monitor=n") {
+		t.Fatalf("expected --monitor=n in %v", args.GetRaw())
+	}
+	if _, err := (LVChangeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("pool"), MonitorStart, IgnoreMonitoring(true)}).AsArgs(); err == nil {
+		t.Fatal("expected Monitor and IgnoreMonitoring to be mutually exclusive")
+	}
+}
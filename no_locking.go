@@ -0,0 +1,105 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// NoLocking runs commands without taking any locks, e.g. in an initramfs or rescue environment
+// without a writable lock directory. Concurrent commands can then corrupt the metadata, so it must
+// only be used if no other lvm2 command can run at the same time. Reports use ReportLockingNone instead.
+type NoLocking bool
+
+func (opt NoLocking) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--nolocking"})
+	}
+	return nil
+}
+
+func (opt NoLocking) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVRenameOptions(opts *LVRenameOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToPVChangeOptions(opts *PVChangeOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToPVMoveOptions(opts *PVMoveOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToPVRemoveOptions(opts *PVRemoveOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToPVResizeOptions(opts *PVResizeOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGCreateOptions(opts *VGCreateOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGExtendOptions(opts *VGExtendOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGReduceOptions(opts *VGReduceOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
+	opts.NoLocking = opt
+}
+
+func (opt NoLocking) ApplyToVGRenameOptions(opts *VGRenameOptions) {
+	opts.NoLocking = opt
+}
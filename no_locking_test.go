@@ -0,0 +1,57 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestNoLockingAndNoUdev(t *testing.T) {
+	t.Parallel()
+
+	args, err := LVChangeOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), Activate,
+		NoLocking(true), LockOptNoDelay, NoUdevSync(true), NoUdevRules(true),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--nolocking", "--lockopt=nodelay", "--noudevsync", "--config=activation/udev_rules=0"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+
+	args, err = VGChangeOptionsList{LockStart(true), LockOptAutoWait}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--lockopt=autowait") {
+		t.Fatalf("expected --lockopt=autowait in %v", args.GetRaw())
+	}
+
+	args, err = PVsOptionsList{LockOptNoDelay}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--lockopt=nodelay") {
+		t.Fatalf("expected --lockopt=nodelay in %v", args.GetRaw())
+	}
+}
@@ -32,8 +32,25 @@ func (c *noNsenterClient) applyNoNsenter(ctx context.Context) context.Context {
 	return WithForceNoNsenter(ctx, true)
 }
 
-// Ensure noNsenterClient implements Client
-var _ Client = (*noNsenterClient)(nil)
+// Ensure noNsenterClient implements WrappedClient and RawCommandRunner
+var (
+	_ WrappedClient    = (*noNsenterClient)(nil)
+	_ RawCommandRunner = (*noNsenterClient)(nil)
+)
+
+// Unwrap implements WrappedClient.
+func (c *noNsenterClient) Unwrap() Client {
+	return c.client
+}
+
+// RunRaw implements RawCommandRunner, running the command with the NoNsenter context.
+func (c *noNsenterClient) RunRaw(ctx context.Context, process RawOutputProcessor, args ...string) error {
+	runner, err := rawCommandRunner(c.client)
+	if err != nil {
+		return err
+	}
+	return runner.RunRaw(c.applyNoNsenter(ctx), process, args...)
+}
 
 // Version implements MetaClient.
 func (c *noNsenterClient) Version(ctx context.Context, opts ...VersionOption) (Version, error) {
@@ -90,6 +107,21 @@ func (c *noNsenterClient) GetProfileDirectory(ctx context.Context) (string, erro
 	return c.client.GetProfileDirectory(c.applyNoNsenter(ctx))
 }
 
+// Defaults implements MetaClient.
+func (c *noNsenterClient) Defaults(ctx context.Context) Defaults {
+	return c.client.Defaults(c.applyNoNsenter(ctx))
+}
+
+// FullReport implements MetaClient.
+func (c *noNsenterClient) FullReport(ctx context.Context, opts ...FullReportOption) (*FullReport, error) {
+	return c.client.FullReport(c.applyNoNsenter(ctx), opts...)
+}
+
+// LVMDump implements MetaClient.
+func (c *noNsenterClient) LVMDump(ctx context.Context, opts ...LVMDumpOption) (string, error) {
+	return c.client.LVMDump(c.applyNoNsenter(ctx), opts...)
+}
+
 // VG implements VolumeGroupClient.
 func (c *noNsenterClient) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error) {
 	return c.client.VG(c.applyNoNsenter(ctx), opts...)
@@ -120,6 +152,51 @@ func (c *noNsenterClient) VGReduce(ctx context.Context, opts ...VGReduceOption)
 	return c.client.VGReduce(c.applyNoNsenter(ctx), opts...)
 }
 
+// VGSplit implements VolumeGroupClient.
+func (c *noNsenterClient) VGSplit(ctx context.Context, opts ...VGSplitOption) error {
+	return c.client.VGSplit(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGMerge implements VolumeGroupClient.
+func (c *noNsenterClient) VGMerge(ctx context.Context, opts ...VGMergeOption) error {
+	return c.client.VGMerge(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGExport implements VolumeGroupClient.
+func (c *noNsenterClient) VGExport(ctx context.Context, opts ...VGExportOption) error {
+	return c.client.VGExport(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGImport implements VolumeGroupClient.
+func (c *noNsenterClient) VGImport(ctx context.Context, opts ...VGImportOption) error {
+	return c.client.VGImport(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGImportClone implements VolumeGroupClient.
+func (c *noNsenterClient) VGImportClone(ctx context.Context, opts ...VGImportCloneOption) error {
+	return c.client.VGImportClone(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGCfgBackup implements VolumeGroupClient.
+func (c *noNsenterClient) VGCfgBackup(ctx context.Context, opts ...VGCfgBackupOption) error {
+	return c.client.VGCfgBackup(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGCfgRestore implements VolumeGroupClient.
+func (c *noNsenterClient) VGCfgRestore(ctx context.Context, opts ...VGCfgRestoreOption) error {
+	return c.client.VGCfgRestore(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGCheck implements VolumeGroupClient.
+func (c *noNsenterClient) VGCheck(ctx context.Context, opts ...VGCheckOption) (*VGCheckResult, error) {
+	return c.client.VGCheck(c.applyNoNsenter(ctx), opts...)
+}
+
+// VGScan implements VolumeGroupClient.
+func (c *noNsenterClient) VGScan(ctx context.Context, opts ...VGScanOption) error {
+	return c.client.VGScan(c.applyNoNsenter(ctx), opts...)
+}
+
 // VGRename implements VolumeGroupClient.
 func (c *noNsenterClient) VGRename(ctx context.Context, opts ...VGRenameOption) error {
 	return c.client.VGRename(c.applyNoNsenter(ctx), opts...)
@@ -140,6 +217,11 @@ func (c *noNsenterClient) LVs(ctx context.Context, opts ...LVsOption) ([]*Logica
 	return c.client.LVs(c.applyNoNsenter(ctx), opts...)
 }
 
+// LVSegments implements LogicalVolumeClient.
+func (c *noNsenterClient) LVSegments(ctx context.Context, opts ...LVsOption) ([]*LogicalVolumeSegment, error) {
+	return c.client.LVSegments(c.applyNoNsenter(ctx), opts...)
+}
+
 // LVCreate implements LogicalVolumeClient.
 func (c *noNsenterClient) LVCreate(ctx context.Context, opts ...LVCreateOption) error {
 	return c.client.LVCreate(c.applyNoNsenter(ctx), opts...)
@@ -175,11 +257,26 @@ func (c *noNsenterClient) LVChange(ctx context.Context, opts ...LVChangeOption)
 	return c.client.LVChange(c.applyNoNsenter(ctx), opts...)
 }
 
+// LVScan implements LogicalVolumeClient.
+func (c *noNsenterClient) LVScan(ctx context.Context, opts ...LVScanOption) error {
+	return c.client.LVScan(c.applyNoNsenter(ctx), opts...)
+}
+
+// LVConvert implements LogicalVolumeClient.
+func (c *noNsenterClient) LVConvert(ctx context.Context, opts ...LVConvertOption) error {
+	return c.client.LVConvert(c.applyNoNsenter(ctx), opts...)
+}
+
 // PVs implements PhysicalVolumeClient.
 func (c *noNsenterClient) PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume, error) {
 	return c.client.PVs(c.applyNoNsenter(ctx), opts...)
 }
 
+// PVSegments implements PhysicalVolumeClient.
+func (c *noNsenterClient) PVSegments(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolumeSegment, error) {
+	return c.client.PVSegments(c.applyNoNsenter(ctx), opts...)
+}
+
 // PVCreate implements PhysicalVolumeClient.
 func (c *noNsenterClient) PVCreate(ctx context.Context, opts ...PVCreateOption) error {
 	return c.client.PVCreate(c.applyNoNsenter(ctx), opts...)
@@ -205,6 +302,16 @@ func (c *noNsenterClient) PVMove(ctx context.Context, opts ...PVMoveOption) erro
 	return c.client.PVMove(c.applyNoNsenter(ctx), opts...)
 }
 
+// PVCheck implements PhysicalVolumeClient.
+func (c *noNsenterClient) PVCheck(ctx context.Context, opts ...PVCheckOption) (*PVCheckResult, error) {
+	return c.client.PVCheck(c.applyNoNsenter(ctx), opts...)
+}
+
+// PVScan implements PhysicalVolumeClient.
+func (c *noNsenterClient) PVScan(ctx context.Context, opts ...PVScanOption) error {
+	return c.client.PVScan(c.applyNoNsenter(ctx), opts...)
+}
+
 // DevList implements DevicesClient.
 func (c *noNsenterClient) DevList(ctx context.Context, opts ...DevListOption) ([]DeviceListEntry, error) {
 	return c.client.DevList(c.applyNoNsenter(ctx), opts...)
@@ -216,7 +323,7 @@ func (c *noNsenterClient) DevCheck(ctx context.Context, opts ...DevCheckOption)
 }
 
 // DevUpdate implements DevicesClient.
-func (c *noNsenterClient) DevUpdate(ctx context.Context, opts ...DevUpdateOption) error {
+func (c *noNsenterClient) DevUpdate(ctx context.Context, opts ...DevUpdateOption) (*DevUpdateResult, error) {
 	return c.client.DevUpdate(c.applyNoNsenter(ctx), opts...)
 }
 
@@ -0,0 +1,38 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// NoSync skips the initial synchronization of a mirror or raid logical volume created with LVCreate,
+// e.g. for large mirrors that are written completely before they are read.
+// It requires Mirrors or one of the types TypeMirrored, TypeRAID1, TypeRAID4, TypeRAID5 and TypeRAID10.
+//
+// Warning: the original contents of the devices are not copied between the mirror legs, and no parity
+// is written for raid4 and raid5. Regions that were not written after the creation can return different
+// data depending on the leg they are read from, and cannot be recovered from a failed leg.
+// Only data written after the creation is redundant. Use Resync with LVChange to synchronize such a volume later.
+type NoSync bool
+
+func (opt NoSync) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--nosync"})
+	}
+	return nil
+}
+
+func (opt NoSync) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.NoSync = opt
+}
@@ -0,0 +1,71 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestNoSync(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var logs bytes.Buffer
+	var calls [][]string
+	clnt := NewClient(
+		NewClientLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+			calls = append(calls, cmd.Args)
+			return io.NopCloser(strings.NewReader("")), nil
+		}),
+	)
+
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("1G"), NoSync(true)); err == nil {
+		t.Fatal("expected error for NoSync without mirrors")
+	}
+
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("1G"), Mirrors(1), NoSync(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Resync(true)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 commands, got %v", calls)
+	}
+	for _, arg := range []string{"--mirrors", "1", "--nosync"} {
+		if !slices.Contains(calls[0], arg) {
+			t.Fatalf("expected %q in %v", arg, calls[0])
+		}
+	}
+	if !slices.Contains(calls[1], "--resync") {
+		t.Fatalf("expected --resync in %v", calls[1])
+	}
+	if warnings := strings.Count(logs.String(), "level=WARN"); warnings != 2 {
+		t.Fatalf("expected a warning for NoSync and Resync, got %s", logs.String())
+	}
+}
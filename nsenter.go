@@ -0,0 +1,75 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNsenterUnavailable is returned for commands that would be run with nsenter if the host namespaces
+// cannot be entered through PID 1, see CheckNsenter.
+var ErrNsenterUnavailable = errors.New("nsenter cannot enter the host namespaces")
+
+const nsenterGuidance = "run the container with hostPID and the privileges to enter the namespaces of PID 1 " +
+	"(privileged, or CAP_SYS_ADMIN and CAP_SYS_PTRACE), or disable nsenter with NsenterPolicyNever"
+
+// nsenterNamespaces are the namespaces entered by CommandContext.
+var nsenterNamespaces = []string{"mnt", "uts", "ipc", "net", "pid"}
+
+var nsenterCheck = sync.OnceValue(func() error {
+	return checkNsenter("/proc", nsenter, IsContainerized(context.Background()))
+})
+
+// CheckNsenter verifies that commands can be run in the host namespaces with nsenter: the nsenter binary
+// has to exist, the namespaces of PID 1 have to be accessible and, in a containerized environment, PID 1 has to be
+// the host init instead of a process of the container, which is the case without hostPID.
+// Errors wrap ErrNsenterUnavailable and describe how to fix the environment.
+// The result is determined once per process. Clients call it before running commands with nsenter.
+func CheckNsenter() error {
+	return nsenterCheck()
+}
+
+func checkNsenter(proc, binary string, containerized bool) error {
+	unavailable := func(format string, args ...any) error {
+		return fmt.Errorf("%w: %s; %s", ErrNsenterUnavailable, fmt.Sprintf(format, args...), nsenterGuidance)
+	}
+
+	if _, err := os.Stat(binary); err != nil {
+		return unavailable("%s is not available: %v", binary, err)
+	}
+
+	for _, ns := range nsenterNamespaces {
+		if _, err := os.Readlink(filepath.Join(proc, "1", "ns", ns)); err != nil {
+			return unavailable("cannot access the %s namespace of PID 1: %v", ns, err)
+		}
+	}
+
+	if containerized {
+		host, _ := os.Readlink(filepath.Join(proc, "1", "ns", "mnt"))
+		self, err := os.Readlink(filepath.Join(proc, "self", "ns", "mnt"))
+		if err == nil && host == self {
+			return unavailable("PID 1 shares the mount namespace of this container and is not the host init")
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckNsenter(t *testing.T) {
+	t.Parallel()
+
+	proc := func(t *testing.T, hostMnt, selfMnt string) string {
+		dir := t.TempDir()
+		for pid, mnt := range map[string]string{"1": hostMnt, "self": selfMnt} {
+			if err := os.MkdirAll(filepath.Join(dir, pid, "ns"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			for _, ns := range nsenterNamespaces {
+				target := ns + ":[4026531840]"
+				if ns == "mnt" {
+					target = mnt
+				}
+				if err := os.Symlink(target, filepath.Join(dir, pid, "ns", ns)); err != nil {
+					t.Fatal(err)
+				}
+			}
+		}
+		return dir
+	}
+	binary := filepath.Join(t.TempDir(), "nsenter")
+	if err := os.WriteFile(binary, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range map[string]struct {
+		proc          string
+		binary        string
+		containerized bool
+		available     bool
+	}{
+		"host pid":          {proc(t, "mnt:[1]", "mnt:[2]"), binary, true, true},
+		"container pid":     {proc(t, "mnt:[2]", "mnt:[2]"), binary, true, false},
+		"host":              {proc(t, "mnt:[1]", "mnt:[1]"), binary, false, true},
+		"missing binary":    {proc(t, "mnt:[1]", "mnt:[2]"), filepath.Join(t.TempDir(), "nsenter"), true, false},
+		"inaccessible proc": {t.TempDir(), binary, true, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			err := checkNsenter(tc.proc, tc.binary, tc.containerized)
+			if tc.available && err != nil {
+				t.Fatalf("expected nsenter to be available, got %v", err)
+			}
+			if !tc.available && !errors.Is(err, ErrNsenterUnavailable) {
+				t.Fatalf("expected ErrNsenterUnavailable, got %v", err)
+			}
+		})
+	}
+}
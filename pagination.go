@@ -0,0 +1,219 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidContinueToken is returned if a continue token was not issued by a previous page of the same listing.
+var ErrInvalidContinueToken = errors.New("invalid continue token")
+
+var (
+	// PageLVsColumnOptions are the columns of the name listing used by LVsPage.
+	PageLVsColumnOptions = ColumnOptions{"vg_name", "lv_name"}
+	// PageVGsColumnOptions are the columns of the name listing used by VGsPage.
+	PageVGsColumnOptions = ColumnOptions{"vg_name"}
+	// PagePVsColumnOptions are the columns of the name listing used by PVsPage.
+	PagePVsColumnOptions = ColumnOptions{"pv_name"}
+)
+
+// Paginate returns up to limit items sorted by key, starting after the item the continue token points to.
+// An empty continue token starts at the first item, a limit of 0 or less returns all remaining items.
+// The returned continue token is empty if there are no further items.
+//
+// Continue tokens point to the key of the last item of a page instead of an offset,
+// so items that are added or removed between two pages do not shift the following pages.
+func Paginate[T any](items []T, key func(T) string, limit int, continueToken string) ([]T, string, error) {
+	start, err := decodeContinueToken(continueToken)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sorted := slices.Clone(items)
+	slices.SortFunc(sorted, func(a, b T) int {
+		return strings.Compare(key(a), key(b))
+	})
+
+	if continueToken != "" {
+		idx, found := slices.BinarySearchFunc(sorted, start, func(item T, start string) int {
+			return strings.Compare(key(item), start)
+		})
+		if found {
+			idx++
+		}
+		sorted = sorted[idx:]
+	}
+
+	if limit <= 0 || len(sorted) <= limit {
+		return sorted, "", nil
+	}
+	page := sorted[:limit]
+	return page, encodeContinueToken(key(page[limit-1])), nil
+}
+
+// LVsPage returns a page of up to limit logical volumes matching the options, sorted by volume group and name.
+// Pass the returned continue token to the next call to get the following page, an empty token means
+// that there are no further pages. The same options have to be passed for every page of a listing.
+//
+// Every page runs two reports: a listing of the names of all matching logical volumes and a full report
+// that is limited to the logical volumes of the page. This keeps pages cheap for large inventories
+// even if the full report of a logical volume is expensive.
+func LVsPage(ctx context.Context, client Client, limit int, continueToken string, opts ...LVsOption) ([]*LogicalVolume, string, error) {
+	key := func(lv *LogicalVolume) string {
+		return fmt.Sprintf("%s/%s", lv.VolumeGroupName, lv.Name)
+	}
+
+	names, err := client.LVs(ctx, append(slices.Clone(opts), PageLVsColumnOptions)...)
+	if err != nil {
+		return nil, "", err
+	}
+	page, next, err := Paginate(names, key, limit, continueToken)
+	if err != nil || len(page) == 0 {
+		return nil, "", err
+	}
+
+	options := LVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVsOptions(&options)
+	}
+	// the names of the page were already selected by the listing, so they replace the selection of the options
+	options.VolumeGroupName, options.LogicalVolumeName, options.VolumeGroupNames = "", "", nil
+	options.Tags, options.Select = nil, ""
+	options.FQLogicalVolumeNames = make(FQLogicalVolumeNames, 0, len(page))
+	for _, lv := range page {
+		options.FQLogicalVolumeNames = append(options.FQLogicalVolumeNames, &FQLogicalVolumeName{
+			VolumeGroupName:   lv.VolumeGroupName,
+			LogicalVolumeName: lv.Name,
+		})
+	}
+	options.ColumnOptions = pageColumnOptions(options.ColumnOptions, PageLVsColumnOptions)
+
+	lvs, err := client.LVs(ctx, &options)
+	if err != nil {
+		return nil, "", err
+	}
+	return sortedPage(lvs, key), next, nil
+}
+
+// VGsPage returns a page of up to limit volume groups matching the options, sorted by name.
+// It works like LVsPage.
+func VGsPage(ctx context.Context, client Client, limit int, continueToken string, opts ...VGsOption) ([]*VolumeGroup, string, error) {
+	key := func(vg *VolumeGroup) string {
+		return string(vg.Name)
+	}
+
+	names, err := client.VGs(ctx, append(slices.Clone(opts), PageVGsColumnOptions)...)
+	if err != nil {
+		return nil, "", err
+	}
+	page, next, err := Paginate(names, key, limit, continueToken)
+	if err != nil || len(page) == 0 {
+		return nil, "", err
+	}
+
+	options := VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	options.VolumeGroupName, options.Tags, options.Select = "", nil, ""
+	options.VolumeGroupNames = make(VolumeGroupNames, 0, len(page))
+	for _, vg := range page {
+		options.VolumeGroupNames = append(options.VolumeGroupNames, vg.Name)
+	}
+	options.ColumnOptions = pageColumnOptions(options.ColumnOptions, PageVGsColumnOptions)
+
+	vgs, err := client.VGs(ctx, &options)
+	if err != nil {
+		return nil, "", err
+	}
+	return sortedPage(vgs, key), next, nil
+}
+
+// PVsPage returns a page of up to limit physical volumes matching the options, sorted by name.
+// It works like LVsPage.
+func PVsPage(ctx context.Context, client Client, limit int, continueToken string, opts ...PVsOption) ([]*PhysicalVolume, string, error) {
+	key := func(pv *PhysicalVolume) string {
+		return string(pv.Name)
+	}
+
+	names, err := client.PVs(ctx, append(slices.Clone(opts), PagePVsColumnOptions)...)
+	if err != nil {
+		return nil, "", err
+	}
+	page, next, err := Paginate(names, key, limit, continueToken)
+	if err != nil || len(page) == 0 {
+		return nil, "", err
+	}
+
+	options := PVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVsOptions(&options)
+	}
+	options.Tags, options.Select = nil, ""
+	options.PhysicalVolumeNames = make(PhysicalVolumeNames, 0, len(page))
+	for _, pv := range page {
+		options.PhysicalVolumeNames = append(options.PhysicalVolumeNames, pv.Name)
+	}
+	options.ColumnOptions = pageColumnOptions(options.ColumnOptions, PagePVsColumnOptions)
+
+	pvs, err := client.PVs(ctx, &options)
+	if err != nil {
+		return nil, "", err
+	}
+	return sortedPage(pvs, key), next, nil
+}
+
+// pageColumnOptions adds the columns needed to sort a page to custom column options.
+// Empty column options are kept, as the default columns already contain them.
+func pageColumnOptions(columns, required ColumnOptions) ColumnOptions {
+	if len(columns) == 0 {
+		return columns
+	}
+	columns = slices.Clone(columns)
+	for _, column := range required {
+		if !slices.Contains(columns, column) {
+			columns = append(columns, column)
+		}
+	}
+	return columns
+}
+
+// sortedPage sorts the full report of a page, as reports are not guaranteed to be ordered.
+func sortedPage[T any](items []T, key func(T) string) []T {
+	slices.SortFunc(items, func(a, b T) int {
+		return strings.Compare(key(a), key(b))
+	})
+	return items
+}
+
+func encodeContinueToken(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(key))
+}
+
+func decodeContinueToken(token string) (string, error) {
+	key, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", fmt.Errorf("%w: %q", ErrInvalidContinueToken, token)
+	}
+	return string(key), nil
+}
@@ -0,0 +1,112 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPaginate(t *testing.T) {
+	t.Parallel()
+	key := func(s string) string { return s }
+	items := []string{"d", "b", "e", "a", "c"}
+
+	page, next, err := Paginate(items, key, 2, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(page, []string{"a", "b"}) || next == "" {
+		t.Fatalf("unexpected first page %v with token %q", page, next)
+	}
+
+	// removing an item of a previous page does not shift the following pages
+	page, next, err = Paginate([]string{"e", "c", "d"}, key, 2, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(page, []string{"c", "d"}) || next == "" {
+		t.Fatalf("unexpected second page %v with token %q", page, next)
+	}
+
+	page, next, err = Paginate(items, key, 2, next)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(page, []string{"e"}) || next != "" {
+		t.Fatalf("unexpected last page %v with token %q", page, next)
+	}
+
+	if _, _, err := Paginate(items, key, 2, "not a token!"); !errors.Is(err, ErrInvalidContinueToken) {
+		t.Fatalf("expected invalid continue token, got %v", err)
+	}
+}
+
+func TestLVsPage(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	if err := clnt.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	for i := range 5 {
+		if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName(fmt.Sprintf("lv%d", 4-i)), MustParseSize("4M")); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var names []LogicalVolumeName
+	var token string
+	for pages := 1; ; pages++ {
+		lvs, next, err := LVsPage(ctx, clnt, 2, token, VolumeGroupName("vg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, lv := range lvs {
+			if lv.Size.Val == 0 {
+				t.Fatalf("expected full report of %s", lv.Name)
+			}
+			names = append(names, lv.Name)
+		}
+		if token = next; token == "" {
+			if pages != 3 {
+				t.Fatalf("expected 3 pages, got %d", pages)
+			}
+			break
+		}
+	}
+	if !slices.Equal(names, []LogicalVolumeName{"lv0", "lv1", "lv2", "lv3", "lv4"}) {
+		t.Fatalf("unexpected logical volumes %v", names)
+	}
+
+	vgs, next, err := VGsPage(ctx, clnt, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(vgs) != 1 || vgs[0].Name != "vg" || next != "" {
+		t.Fatalf("unexpected volume groups %v with token %q", vgs, next)
+	}
+}
@@ -20,6 +20,8 @@ import (
 	"fmt"
 )
 
+// Permission sets the access permission of a logical volume with --permission.
+// Read-only logical volumes, e.g. snapshots exported for backups, can be activated without risking writes to them.
 type Permission string
 
 const (
@@ -28,13 +30,21 @@ const (
 )
 
 func (opt Permission) ApplyToArgs(args Arguments) error {
-	if opt == "" {
+	switch opt {
+	case "":
 		return nil
+	case PermissionReadOnly, PermissionReadWrite:
+	default:
+		return fmt.Errorf("invalid permission %q", string(opt))
 	}
 	args.AddOrReplace(fmt.Sprintf("--permission=%s", string(opt)))
 	return nil
 }
 
+func (opt Permission) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.Permission = opt
+}
+
 func (opt Permission) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.Permission = opt
 }
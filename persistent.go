@@ -0,0 +1,66 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// PersistentDeviceNumber makes the kernel use a fixed device number for a logical volume with
+// --persistent y --minor, e.g. for NFS exports whose file handles contain the device number.
+// Current kernels assign major numbers dynamically and lvm2 ignores the major number with a warning,
+// so --major is only passed if Major is set.
+// Passed to LVChange, an active logical volume is reactivated to use the new device number.
+type PersistentDeviceNumber DeviceNumber
+
+func (opt *PersistentDeviceNumber) ApplyToArgs(args Arguments) error {
+	if opt == nil {
+		return nil
+	}
+	if opt.Major < 0 || opt.Minor < 0 {
+		return fmt.Errorf("invalid persistent device number %s", DeviceNumber(*opt))
+	}
+	args.AddOrReplace("--persistent=y")
+	if opt.Major > 0 {
+		args.AddOrReplace(fmt.Sprintf("--major=%d", opt.Major))
+	}
+	args.AddOrReplace(fmt.Sprintf("--minor=%d", opt.Minor))
+	return nil
+}
+
+func (opt *PersistentDeviceNumber) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.PersistentDeviceNumber = opt
+}
+
+func (opt *PersistentDeviceNumber) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.PersistentDeviceNumber = opt
+}
+
+// RemovePersistentDeviceNumber lets the kernel assign the device number of a logical volume again
+// with --persistent n, reverting PersistentDeviceNumber.
+type RemovePersistentDeviceNumber bool
+
+func (opt RemovePersistentDeviceNumber) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplace("--persistent=n")
+	}
+	return nil
+}
+
+func (opt RemovePersistentDeviceNumber) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.RemovePersistentDeviceNumber = opt
+}
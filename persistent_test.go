@@ -0,0 +1,107 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPermissionAndDeviceNumberOptions(t *testing.T) {
+	t.Parallel()
+
+	args, err := LVCreateOptionList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("1G"),
+		PermissionReadOnly, &PersistentDeviceNumber{Minor: 42}, ReadAheadSectors(256),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--permission=r --persistent=y --minor=42 --readahead=256") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+
+	args, err = LVChangeOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), RemovePersistentDeviceNumber(true), ReadAheadAuto,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--persistent=n --readahead=auto") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+
+	for _, opts := range []LVChangeOptionsList{
+		{VolumeGroupName("vg"), LogicalVolumeName("lv"), Permission("w")},
+		{VolumeGroupName("vg"), LogicalVolumeName("lv"), ReadAhead("fast")},
+		{VolumeGroupName("vg"), LogicalVolumeName("lv"), &PersistentDeviceNumber{Minor: -1}},
+		{VolumeGroupName("vg"), LogicalVolumeName("lv"), &PersistentDeviceNumber{Minor: 1}, RemovePersistentDeviceNumber(true)},
+	} {
+		if _, err := opts.AsArgs(); err == nil {
+			t.Fatalf("expected error for %v", opts)
+		}
+	}
+}
+
+func TestReadOnlySnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := fake.NewClient()
+	if err := client.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("40M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewThinPool("vg", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, pool, LogicalVolumeName("data"), MustParseSize("1G").Virtual()); err != nil {
+		t.Fatal(err)
+	}
+	origin, err := NewSnapshotOf("vg", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, origin, LogicalVolumeName("backup"), PermissionReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("backup"), Activate, IgnoreActivationSkip(true),
+		&PersistentDeviceNumber{Major: 253, Minor: 42}); err != nil {
+		t.Fatal(err)
+	}
+
+	lv, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("backup"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Attr.LVPermissions != LVPermissionsReadOnly || lv.Attr.Minor != MinorTrue || lv.Attr.State != StateActive {
+		t.Fatalf("expected active read-only snapshot with persistent minor, got %s", lv.Attr)
+	}
+	if lv.Major != 253 || lv.Minor != 42 {
+		t.Fatalf("expected device number 253:42, got %d:%d", lv.Major, lv.Minor)
+	}
+}
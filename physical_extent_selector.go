@@ -0,0 +1,126 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// PhysicalExtentSelector restricts the allocation of LVCreate and LVExtend to a physical volume,
+// optionally to ranges of its physical extents, e.g. /dev/sdb:0-1000.
+// Multiple selectors allow placing data precisely, e.g. on the fast disks of a volume group with disks of different speed.
+// The physical volume names of the ranges have to be empty or the one of the selector.
+//
+// A PhysicalExtentRange, e.g. a free range reported by PVSegments, can also be passed as option directly.
+type PhysicalExtentSelector struct {
+	PhysicalVolumeName
+	Ranges []PhysicalExtentRange
+}
+
+// ParsePhysicalExtentSelector parses a selector in the PV[:PE[-PE]]... format of lvm2,
+// where ranges can also be given as PE+count.
+func ParsePhysicalExtentSelector(str string) (PhysicalExtentSelector, error) {
+	parts := strings.Split(str, ":")
+	selector := PhysicalExtentSelector{PhysicalVolumeName: PhysicalVolumeName(parts[0])}
+	for _, part := range parts[1:] {
+		r := PhysicalExtentRange{PhysicalVolumeName: selector.PhysicalVolumeName}
+		var err error
+		if start, count, ok := strings.Cut(part, "+"); ok {
+			var n int64
+			if r.Start, err = strconv.ParseInt(start, 10, 64); err == nil {
+				n, err = strconv.ParseInt(count, 10, 64)
+				r.End = r.Start + n
+			}
+		} else if start, end, ok := strings.Cut(part, "-"); ok {
+			if r.Start, err = strconv.ParseInt(start, 10, 64); err == nil {
+				r.End, err = strconv.ParseInt(end, 10, 64)
+			}
+		} else {
+			r.Start, err = strconv.ParseInt(part, 10, 64)
+			r.End = r.Start
+		}
+		if err != nil {
+			return PhysicalExtentSelector{}, fmt.Errorf("invalid physical extent range %q in %q: %w", part, str, err)
+		}
+		selector.Ranges = append(selector.Ranges, r)
+	}
+	return selector, selector.Validate()
+}
+
+func (opt PhysicalExtentSelector) Validate() error {
+	if opt.PhysicalVolumeName == "" {
+		return fmt.Errorf("physical extent selector requires a physical volume")
+	}
+	for _, r := range opt.Ranges {
+		if r.PhysicalVolumeName != "" && r.PhysicalVolumeName != opt.PhysicalVolumeName {
+			return fmt.Errorf("physical extent range %s is not on %s", r, opt.PhysicalVolumeName)
+		}
+		if r.Start < 0 || r.End < r.Start {
+			return fmt.Errorf("invalid physical extent range %d-%d on %s", r.Start, r.End, opt.PhysicalVolumeName)
+		}
+	}
+	return nil
+}
+
+// String returns the selector in the format of lvm2, e.g. /dev/sdb:0-1000:2000-2999.
+func (opt PhysicalExtentSelector) String() string {
+	var str strings.Builder
+	str.WriteString(string(opt.PhysicalVolumeName))
+	for _, r := range opt.Ranges {
+		fmt.Fprintf(&str, ":%d-%d", r.Start, r.End)
+	}
+	return str.String()
+}
+
+func (opt PhysicalExtentSelector) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.PhysicalExtentSelectors = append(opts.PhysicalExtentSelectors, opt)
+}
+
+func (opt PhysicalExtentSelector) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.PhysicalExtentSelectors = append(opts.PhysicalExtentSelectors, opt)
+}
+
+func (r PhysicalExtentRange) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	PhysicalExtentSelector{PhysicalVolumeName: r.PhysicalVolumeName, Ranges: []PhysicalExtentRange{r}}.ApplyToLVCreateOptions(opts)
+}
+
+func (r PhysicalExtentRange) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	PhysicalExtentSelector{PhysicalVolumeName: r.PhysicalVolumeName, Ranges: []PhysicalExtentRange{r}}.ApplyToLVExtendOptions(opts)
+}
+
+// PhysicalExtentSelectors are passed as positional arguments after the logical volume.
+type PhysicalExtentSelectors []PhysicalExtentSelector
+
+func (opt PhysicalExtentSelectors) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.PhysicalExtentSelectors = append(opts.PhysicalExtentSelectors, opt...)
+}
+
+func (opt PhysicalExtentSelectors) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.PhysicalExtentSelectors = append(opts.PhysicalExtentSelectors, opt...)
+}
+
+func (opt PhysicalExtentSelectors) ApplyToArgs(args Arguments) error {
+	for _, selector := range opt {
+		if err := selector.Validate(); err != nil {
+			return err
+		}
+		args.AddOrReplaceAll([]string{selector.String()})
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestParsePhysicalExtentSelector(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{in: "/dev/sdb", want: "/dev/sdb"},
+		{in: "/dev/sdb:0-1000", want: "/dev/sdb:0-1000"},
+		{in: "/dev/sdb:7", want: "/dev/sdb:7-7"},
+		{in: "/dev/sdb:10+5:100-200", want: "/dev/sdb:10-15:100-200"},
+		{in: "/dev/sdb:10-5", err: true},
+		{in: "/dev/sdb:a-5", err: true},
+		{in: ":0-1", err: true},
+	} {
+		selector, err := ParsePhysicalExtentSelector(tc.in)
+		if tc.err {
+			if err == nil {
+				t.Errorf("expected error for %q", tc.in)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error for %q: %v", tc.in, err)
+		} else if selector.String() != tc.want {
+			t.Errorf("expected %q for %q, got %q", tc.want, tc.in, selector.String())
+		}
+	}
+}
+
+func TestPhysicalExtentSelectorArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := LVCreateOptionList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("1G"),
+		PhysicalExtentSelector{PhysicalVolumeName: "/dev/sdb", Ranges: []PhysicalExtentRange{{Start: 0, End: 1000}}},
+		PhysicalExtentRange{PhysicalVolumeName: "/dev/sdc", Start: 5, End: 10},
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.HasPrefix(raw, "vg --name=lv --size=1.00g /dev/sdb:0-1000 /dev/sdc:5-10") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+
+	args, err = LVExtendOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("+1G"),
+		PhysicalExtentSelectors{{PhysicalVolumeName: "/dev/sdc"}},
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "/dev/sdc") {
+		t.Fatalf("expected physical volume in args %v", args.GetRaw())
+	}
+
+	if _, err := (LVCreateOptionList{
+		VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("1G"),
+		PhysicalExtentSelector{PhysicalVolumeName: "/dev/sdb", Ranges: []PhysicalExtentRange{{PhysicalVolumeName: "/dev/sdc", End: 1}}},
+	}).AsArgs(); err == nil {
+		t.Fatal("expected error for range on another physical volume")
+	}
+}
+
+func TestPhysicalExtentSelectorPlacement(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := fake.NewClient()
+	for _, device := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := client.SetDevice(device, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb", "/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("8M"),
+		PhysicalExtentSelector{PhysicalVolumeName: "/dev/sdc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVExtend(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("+8M"),
+		PhysicalExtentSelector{PhysicalVolumeName: "/dev/sdc"}); err != nil {
+		t.Fatal(err)
+	}
+	pvs, err := client.PVs(ctx, PhysicalVolumeNames{"/dev/sdb", "/dev/sdc"}, UnitMiB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pv := range pvs {
+		want := 0.0
+		if pv.Name == "/dev/sdc" {
+			want = 16
+		}
+		if pv.Used.Val != want {
+			t.Fatalf("expected %v MiB used on %s, got %v", want, pv.Name, pv.Used)
+		}
+	}
+
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("other"), MustParseSize("8M"),
+		PhysicalExtentSelector{PhysicalVolumeName: "/dev/sdd"}); err == nil {
+		t.Fatal("expected error for physical volume outside of the volume group")
+	}
+}
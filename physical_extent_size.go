@@ -16,12 +16,35 @@
 
 package lvm2go
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPhysicalExtentSize is returned for physical extent sizes that lvm2 does not accept.
+var ErrInvalidPhysicalExtentSize = errors.New("invalid physical extent size")
+
+// MinNonPowerOfTwoPhysicalExtentSize is the granularity of physical extent sizes that are not a power of 2.
+var MinNonPowerOfTwoPhysicalExtentSize = NewSize(128, UnitKiB)
+
+// PhysicalExtentSize is the size of the physical extents of a volume group.
+// It has to be a multiple of 512 bytes and either a power of 2 or a multiple of MinNonPowerOfTwoPhysicalExtentSize.
+//
+// Passed to VGChange, it changes the extent size of an existing volume group. This only succeeds if
+// the size of every logical volume segment is a multiple of the new extent size, as lvm2 does not move
+// any data. Otherwise VGChange returns an ExtentSizeChangeError. Growing the extent size of volume groups
+// created with small extents therefore usually requires logical volumes sized in multiples of the new size.
 type PhysicalExtentSize Size
 
 func (opt PhysicalExtentSize) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.PhysicalExtentSize = opt
 }
 
+func (opt PhysicalExtentSize) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.PhysicalExtentSize = opt
+}
+
 func (opt PhysicalExtentSize) ApplyToArgs(args Arguments) error {
 	if opt.Val == 0 {
 		return nil
@@ -32,7 +55,70 @@ func (opt PhysicalExtentSize) ApplyToArgs(args Arguments) error {
 	if err := size.Validate(); err != nil {
 		return err
 	}
+	if err := opt.validateDivisibility(); err != nil {
+		return err
+	}
 
 	args.AddOrReplaceAll([]string{"--physicalextentsize", size.String()})
 	return nil
 }
+
+func (opt PhysicalExtentSize) validateDivisibility() error {
+	size, err := Size(opt).ToUnit(UnitBytes)
+	if err != nil {
+		return err
+	}
+	minNonPowerOfTwo, err := MinNonPowerOfTwoPhysicalExtentSize.ToUnit(UnitBytes)
+	if err != nil {
+		return err
+	}
+
+	bytes := uint64(size.Val)
+	if float64(bytes) != size.Val || bytes%512 != 0 {
+		return fmt.Errorf("%w: %s is not a multiple of 512 bytes", ErrInvalidPhysicalExtentSize, Size(opt))
+	}
+	if bytes&(bytes-1) != 0 && bytes%uint64(minNonPowerOfTwo.Val) != 0 {
+		return fmt.Errorf("%w: %s is neither a power of 2 nor a multiple of %s",
+			ErrInvalidPhysicalExtentSize, Size(opt), MinNonPowerOfTwoPhysicalExtentSize)
+	}
+	return nil
+}
+
+// ExtentSizeChangeError is returned by VGChange if logical volumes block the change of the physical extent size,
+// because they are not an exact number of extents of the new size.
+type ExtentSizeChangeError struct {
+	VolumeGroup VolumeGroupName
+	ExtentSize  PhysicalExtentSize
+	// Blocking are the parts of logical volumes that are not divisible by the new extent size
+	// as reported by lvm2, e.g. "lv segment length".
+	Blocking []string
+	Err      error
+}
+
+func (e *ExtentSizeChangeError) Error() string {
+	return fmt.Sprintf("cannot change physical extent size of volume group %s to %s, not divisible: %s",
+		e.VolumeGroup, Size(e.ExtentSize), strings.Join(e.Blocking, ", "))
+}
+
+func (e *ExtentSizeChangeError) Unwrap() error {
+	return e.Err
+}
+
+// newExtentSizeChangeError returns an ExtentSizeChangeError if err reports logical volumes
+// blocking the change of the extent size, otherwise err.
+func newExtentSizeChangeError(vg VolumeGroupName, size PhysicalExtentSize, err error) error {
+	stdErr, ok := AsLVMStdErr(err)
+	if !ok {
+		return err
+	}
+	var blocking []string
+	for _, line := range stdErr.Lines(true) {
+		if match := ExtentSizeNotDivisiblePattern.FindSubmatch(line); match != nil {
+			blocking = append(blocking, string(match[2]))
+		}
+	}
+	if len(blocking) == 0 {
+		return err
+	}
+	return &ExtentSizeChangeError{VolumeGroup: vg, ExtentSize: size, Blocking: blocking, Err: err}
+}
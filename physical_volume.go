@@ -46,6 +46,17 @@ type PhysicalVolume struct {
 	VGName       VolumeGroupName    `json:"vg_name"`
 	DeviceID     string             `json:"pv_device_id"`
 	DeviceIDType string             `json:"pv_device_id_type"`
+
+	// ExtraFields are the reported fields that are not covered by the struct, see ExtraColumns.
+	ExtraFields ExtraFields `json:"-"`
+
+	raw RawJSON
+}
+
+// RawJSON returns the raw JSON of the physical volume as reported by lvm.
+// It is only retained by report calls with RetainRawJSON, otherwise it is nil.
+func (pv *PhysicalVolume) RawJSON() RawJSON {
+	return pv.raw
 }
 
 func (pv *PhysicalVolume) UnmarshalJSON(data []byte) error {
@@ -53,6 +64,7 @@ func (pv *PhysicalVolume) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	pv.raw = rawJSON(data)
 
 	for key, fieldPtr := range map[string]*string{
 		"pv_uuid":      &pv.UUID,
@@ -63,7 +75,7 @@ func (pv *PhysicalVolume) UnmarshalJSON(data []byte) error {
 		if val, ok := raw[key]; !ok {
 			continue
 		} else if err := json.Unmarshal(val, fieldPtr); err != nil {
-			return err
+			return newReportFieldError(key, val, err)
 		}
 	}
 
@@ -115,6 +127,9 @@ func (opt PhysicalVolumeName) ApplyToArgs(args Arguments) error {
 func (opt PhysicalVolumeName) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt)
 }
+func (opt PhysicalVolumeName) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt)
+}
 func (opt PhysicalVolumeName) ApplyToVGExtendOptions(opts *VGExtendOptions) {
 	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt)
 }
@@ -130,6 +145,9 @@ func (opt PhysicalVolumeName) ApplyToPVRemoveOptions(opts *PVRemoveOptions) {
 func (opt PhysicalVolumeName) ApplyToPVMoveOptions(opts *PVMoveOptions) {
 	opts.SetOldOrNew(opt)
 }
+func (opt PhysicalVolumeName) ApplyToVGImportCloneOptions(opts *VGImportCloneOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt)
+}
 
 type PhysicalVolumeNames []PhysicalVolumeName
 
@@ -145,6 +163,10 @@ func (opt PhysicalVolumeNames) ApplyToVGExtendOptions(opts *VGExtendOptions) {
 	}
 }
 
+func (opt PhysicalVolumeNames) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt...)
+}
+
 func (opt PhysicalVolumeNames) ApplyToPVMoveOptions(opts *PVMoveOptions) {
 	if opts.From == "" {
 		opts.From = opt[0]
@@ -176,3 +198,9 @@ func (opt PhysicalVolumeNames) ApplyToArgs(args Arguments) error {
 func (opt PhysicalVolumeNames) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt...)
 }
+func (opt PhysicalVolumeNames) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt...)
+}
+func (opt PhysicalVolumeNames) ApplyToVGImportCloneOptions(opts *VGImportCloneOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt...)
+}
@@ -0,0 +1,214 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+type (
+	// InterruptedOperationsOptions select the logical volumes checked by InterruptedOperations.
+	// Without VolumeGroupNames, all volume groups are checked.
+	InterruptedOperationsOptions struct {
+		VolumeGroupNames
+		ProgressInterval
+	}
+	InterruptedOperationsOption interface {
+		ApplyToInterruptedOperationsOptions(opts *InterruptedOperationsOptions)
+	}
+	InterruptedOperationsOptionsList []InterruptedOperationsOption
+)
+
+func (opts *InterruptedOperationsOptions) ApplyToInterruptedOperationsOptions(new *InterruptedOperationsOptions) {
+	*new = *opts
+}
+
+// ProgressInterval is the time InterruptedOperations and RecoverNode observe active transient operations
+// to find operations that are active but not progressing. Without it, only inactive operations are found
+// and the calls do not block.
+//
+// It should be longer than the polling interval of lvm2 (activation/polling_interval, 15 seconds by default):
+// the device of a pvmove does not change while it waits for lvm2 to move on to the next segment.
+type ProgressInterval time.Duration
+
+func (opt ProgressInterval) ApplyToInterruptedOperationsOptions(opts *InterruptedOperationsOptions) {
+	opts.ProgressInterval = opt
+}
+
+func (opt ProgressInterval) ApplyToRecoverNodeOptions(opts *RecoverNodeOptions) {
+	opts.ProgressInterval = opt
+}
+
+// Poll controls the background polling of transient operations such as pvmove and
+// conversions (e.g. snapshot merges or mirror conversions).
+// Polling has to be resumed after an operation was interrupted, e.g. by a crash or reboot.
+type Poll string
+
+const (
+	// PollResume starts polling of interrupted operations.
+	PollResume Poll = "y"
+	// PollDefer defers polling of interrupted operations, e.g. until the system is fully booted.
+	PollDefer Poll = "n"
+)
+
+func (opt Poll) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--poll=%s", string(opt)))
+	return nil
+}
+
+func (opt Poll) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.Poll = opt
+}
+
+func (opt Poll) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.Poll = opt
+}
+
+// IsTransient returns true if the logical volume is part of an operation that is polled by lvm2,
+// e.g. the temporary pvmove volume or a volume under conversion.
+func (attr LVAttributes) IsTransient() bool {
+	return attr.VolumeType == VolumeTypePVMove || attr.VolumeType == VolumeTypeUnderConversion
+}
+
+// IsInterrupted returns true if the logical volume is part of a transient operation
+// that is not active and thus not progressing, e.g. because the operation was interrupted by a crash.
+//
+// The attributes cannot tell whether an active operation is progressing: after a crash or reboot,
+// the activation of the volume group brings a pvmove or conversion back up as active without anything
+// polling it. Use InterruptedOperations with a ProgressInterval, which also observes active operations over time.
+func (attr LVAttributes) IsInterrupted() bool {
+	return attr.IsTransient() && attr.State != StateActive
+}
+
+// InterruptedOperations returns all logical volumes that are part of an interrupted transient operation.
+// Hidden logical volumes are always included, as the temporary volume of a pvmove, e.g. [pvmove0], is hidden.
+//
+// Inactive operations are interrupted, see LVAttributes.IsInterrupted. With a ProgressInterval, active operations
+// are observed for the interval, and operations that did not progress are interrupted as well, as they are not
+// polled by lvm2. An operation did not progress if neither its copy and sync progress nor the device-mapper status
+// of its device changed, so the client has to be able to run raw commands to read the status with dmsetup.
+// The status is read with the context and lock of wrapped clients, like the reports of the client.
+func InterruptedOperations(ctx context.Context, client Client, opts ...InterruptedOperationsOption) ([]*LogicalVolume, error) {
+	options := InterruptedOperationsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToInterruptedOperationsOptions(&options)
+	}
+	lvs, err := client.LVs(ctx, options.VolumeGroupNames, All(true))
+	if err != nil {
+		return nil, err
+	}
+	stalled, err := stalledOperations(ctx, client, lvs, options.ProgressInterval)
+	if err != nil {
+		return nil, err
+	}
+	var interrupted []*LogicalVolume
+	for _, lv := range lvs {
+		if lv.Attr.IsInterrupted() || stalled[lv] {
+			interrupted = append(interrupted, lv)
+		}
+	}
+	return interrupted, nil
+}
+
+// stalledOperations returns the logical volumes of active transient operations that did not progress
+// within the interval. Operations that are about to finish are never stalled.
+// The reported progress is rounded, so a stall is only confirmed if the device-mapper status did not change either.
+func stalledOperations(ctx context.Context, client Client, lvs []*LogicalVolume, interval ProgressInterval) (map[*LogicalVolume]bool, error) {
+	if interval <= 0 {
+		return nil, nil
+	}
+	var active []*LogicalVolume
+	var vgs VolumeGroupNames
+	for _, lv := range lvs {
+		if !lv.Attr.IsTransient() || lv.Attr.State != StateActive {
+			continue
+		}
+		active = append(active, lv)
+		if !slices.Contains(vgs, lv.VolumeGroupName) {
+			vgs = append(vgs, lv.VolumeGroupName)
+		}
+	}
+	if len(active) == 0 {
+		return nil, nil
+	}
+
+	runner, ok := ClientImplements[RawCommandRunner](client)
+	if !ok {
+		return nil, fmt.Errorf("%w: client cannot run raw commands to read the device-mapper status", errors.ErrUnsupported)
+	}
+	status := map[*LogicalVolume]string{}
+	for _, lv := range active {
+		var err error
+		if status[lv], err = deviceMapperStatus(ctx, runner, lv); err != nil {
+			return nil, err
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(time.Duration(interval)):
+	}
+
+	reported, err := client.LVs(ctx, vgs, All(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to report progress of transient operations: %w", err)
+	}
+	stalled := map[*LogicalVolume]bool{}
+	for _, before := range active {
+		for _, after := range reported {
+			if after.VolumeGroupName != before.VolumeGroupName || after.Name != before.Name {
+				continue
+			}
+			if !after.Attr.IsTransient() || after.CopyPercent != before.CopyPercent || after.SyncPercent != before.SyncPercent ||
+				max(after.CopyPercent, after.SyncPercent) >= 100 {
+				continue
+			}
+			current, err := deviceMapperStatus(ctx, runner, after)
+			if err != nil {
+				return nil, err
+			}
+			stalled[before] = current == status[before]
+		}
+	}
+	return stalled, nil
+}
+
+// deviceMapperStatus returns the device-mapper status of the device of an active logical volume,
+// e.g. the synchronized regions of the mirror of a pvmove.
+func deviceMapperStatus(ctx context.Context, runner RawCommandRunner, lv *LogicalVolume) (string, error) {
+	id := &FQLogicalVolumeName{VolumeGroupName: lv.VolumeGroupName, LogicalVolumeName: LogicalVolumeName(strings.Trim(string(lv.Name), "[]"))}
+	status, err := runRawOutput(ctx, runner, "dmsetup", "status", id.DeviceMapperName())
+	if err != nil {
+		return "", fmt.Errorf("failed to get device-mapper status of %s: %w", lv.FullName, err)
+	}
+	return status, nil
+}
+
+// ResumeInterruptedOperations resumes polling of all interrupted operations in the given volume group.
+// It is equivalent to running vgchange --poll y.
+func ResumeInterruptedOperations(ctx context.Context, client Client, vg VolumeGroupName) error {
+	return client.VGChange(ctx, vg, PollResume)
+}
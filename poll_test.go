@@ -0,0 +1,150 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestIsInterrupted(t *testing.T) {
+	t.Parallel()
+	for attr, expected := range map[string]bool{
+		"pwC---m---": true,
+		"pwC-ao----": false,
+		"cwi---c---": true,
+		"-wi-a-----": false,
+		"-wi-------": false,
+	} {
+		parsed, err := ParseLVAttributes(attr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed.IsInterrupted() != expected {
+			t.Errorf("expected IsInterrupted of %s to be %t", attr, expected)
+		}
+	}
+}
+
+func TestInterruptedOperations(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var args []string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		args = cmd.Args
+		return io.NopCloser(strings.NewReader(`{"report":[{"lv":[
+			{"lv_name":"lv", "vg_name":"vg", "lv_attr":"-wI-------"},
+			{"lv_name":"[pvmove0]", "vg_name":"vg", "lv_attr":"pwC---m---", "move_pv":"/dev/sdb"},
+			{"lv_name":"other", "vg_name":"vg", "lv_attr":"-wi-a-----"}
+		]}]}`)), nil
+	}))
+
+	interrupted, err := InterruptedOperations(ctx, clnt, VolumeGroupNames{"vg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args, "--all") {
+		t.Fatalf("expected hidden logical volumes to be reported, got %v", args)
+	}
+	if len(interrupted) != 1 || interrupted[0].Name != "[pvmove0]" || interrupted[0].MovePV != "/dev/sdb" {
+		t.Fatalf("expected the hidden pvmove to be interrupted, got %v", interrupted)
+	}
+}
+
+func TestInterruptedOperationsActive(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// pvmove0 neither progresses in the report nor on the device, pvmove1 progresses in the report
+	// and pvmove2 only progresses on the device, as the reported progress is rounded
+	reports := []string{
+		`{"report":[{"lv":[
+			{"lv_name":"[pvmove0]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdb", "copy_percent":"12.50"},
+			{"lv_name":"[pvmove1]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdc", "copy_percent":"40.00"},
+			{"lv_name":"[pvmove2]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdd", "copy_percent":"0.01"},
+			{"lv_name":"lv", "vg_name":"vg", "lv_attr":"-wI-ao----"}
+		]}]}`,
+		`{"report":[{"lv":[
+			{"lv_name":"[pvmove0]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdb", "copy_percent":"12.50"},
+			{"lv_name":"[pvmove1]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdc", "copy_percent":"45.00"},
+			{"lv_name":"[pvmove2]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdd", "copy_percent":"0.01"},
+			{"lv_name":"lv", "vg_name":"vg", "lv_attr":"-wI-ao----"}
+		]}]}`,
+	}
+	status := map[string][]string{
+		"vg-pvmove0": {"0 8192 mirror 2 253:1 253:2 512/4096 1 AA 3 disk 253:0 A"},
+		"vg-pvmove1": {"0 8192 mirror 2 253:1 253:2 1638/4096 1 AA 3 disk 253:0 A"},
+		"vg-pvmove2": {
+			"0 8192 mirror 2 253:1 253:2 1/16777216 1 AA 3 disk 253:0 A",
+			"0 8192 mirror 2 253:1 253:2 2/16777216 1 AA 3 disk 253:0 A",
+		},
+	}
+	var reported []string
+	var mu sync.Mutex
+	// the status is read with the context and lock of the wrapped client like the reports
+	clnt := NewLockingClient(WithNoNsenter(NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if cmd.Args[0] == "/usr/bin/nsenter" {
+			t.Errorf("expected commands to run without nsenter, got %v", cmd.Args)
+		}
+		if slices.Contains(cmd.Args, "dmsetup") {
+			name := cmd.Args[len(cmd.Args)-1]
+			reported = append(reported, name)
+			current := status[name][0]
+			if len(status[name]) > 1 {
+				status[name] = status[name][1:]
+			}
+			return io.NopCloser(strings.NewReader(current)), nil
+		}
+		report := reports[0]
+		if len(reports) > 1 {
+			reports = reports[1:]
+		}
+		return io.NopCloser(strings.NewReader(report)), nil
+	}))))
+
+	interrupted, err := InterruptedOperations(ctx, clnt, VolumeGroupNames{"vg"}, ProgressInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 {
+		t.Fatal("expected the progress to be reported again")
+	}
+	if len(interrupted) != 1 || interrupted[0].Name != "[pvmove0]" {
+		t.Fatalf("expected the active pvmove without progress to be interrupted, got %v", interrupted)
+	}
+	// the status of pvmove1 is not read again, as it progressed in the report
+	if expected := []string{"vg-pvmove0", "vg-pvmove1", "vg-pvmove2", "vg-pvmove0", "vg-pvmove2"}; !slices.Equal(reported, expected) {
+		t.Fatalf("expected device-mapper status %v, got %v", expected, reported)
+	}
+
+	// without a progress interval, active operations are not observed
+	reported = nil
+	if interrupted, err = InterruptedOperations(ctx, clnt, VolumeGroupNames{"vg"}); err != nil || len(interrupted) != 0 || len(reported) != 0 {
+		t.Fatalf("expected no interrupted operations without observing, got %v: %v", interrupted, err)
+	}
+}
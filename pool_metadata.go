@@ -0,0 +1,93 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// PoolMetadata is an existing logical volume used by LVConvert as the metadata volume
+// when converting a logical volume to a thin pool or cache pool.
+type PoolMetadata FQLogicalVolumeName
+
+func (opt *PoolMetadata) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.PoolMetadata = opt
+}
+
+func (opt *PoolMetadata) ApplyToArgs(args Arguments) error {
+	if opt == nil {
+		return nil
+	}
+
+	if err := (*FQLogicalVolumeName)(opt).Validate(); err != nil {
+		return err
+	}
+
+	args.AddOrReplace(fmt.Sprintf("--poolmetadata=%s/%s", opt.VolumeGroupName, opt.LogicalVolumeName))
+
+	return nil
+}
+
+func NewPoolMetadata(vg VolumeGroupName, lv LogicalVolumeName) (*PoolMetadata, error) {
+	fq, err := NewFQLogicalVolumeName(vg, lv)
+	if err != nil {
+		return nil, err
+	}
+	return (*PoolMetadata)(fq), fq.Validate()
+}
+
+const (
+	CreatePoolMetadataSpare PoolMetadataSpare = "y"
+	NoPoolMetadataSpare     PoolMetadataSpare = "n"
+)
+
+// PoolMetadataSpare controls whether a spare metadata volume is kept in the volume group when creating
+// or converting a thin pool or cache pool. The spare has the size of the largest pool metadata volume
+// and is used by Repair to rebuild damaged pool metadata. lvm2 creates it by default.
+type PoolMetadataSpare string
+
+func (opt PoolMetadataSpare) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.PoolMetadataSpare = opt
+}
+
+func (opt PoolMetadataSpare) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.PoolMetadataSpare = opt
+}
+
+func (opt PoolMetadataSpare) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--poolmetadataspare=%s", string(opt)))
+	return nil
+}
+
+// SwapMetadata swaps the metadata volume of an inactive thin or cache pool with the inactive logical volume
+// given as PoolMetadata using LVConvert, e.g. to check and repair damaged metadata with thin_check and thin_repair.
+// See RepairThinPool.
+type SwapMetadata bool
+
+func (opt SwapMetadata) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--swapmetadata"})
+	}
+	return nil
+}
+
+func (opt SwapMetadata) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.SwapMetadata = opt
+}
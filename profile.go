@@ -88,6 +88,9 @@ func (opt Profile) ApplyToLVRenameOptions(opts *LVRenameOptions) {
 func (opt Profile) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.Profile = opt
 }
+func (opt Profile) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Profile = opt
+}
 
 func (opt Profile) ApplyToPVsOptions(opts *PVsOptions) {
 	opts.Profile = opt
@@ -0,0 +1,158 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// ProgressOperationPVMove is the Progress.Operation of moves tracked with PVMoveHandle.
+	ProgressOperationPVMove = "pvmove"
+	// ProgressOperationMerge is the Progress.Operation of snapshot merges awaited by LVMergeSnapshot.
+	ProgressOperationMerge = "merge"
+	// ProgressOperationSync is the Progress.Operation of raid and mirror synchronizations awaited by WaitForSync,
+	// including the conversion of a linear logical volume into a mirror.
+	ProgressOperationSync = "sync"
+)
+
+// Progress is the progress of a long-running operation of lvm, so that any operation can be rendered uniformly.
+type Progress interface {
+	// Operation names the operation, e.g. ProgressOperationPVMove.
+	Operation() string
+	// Percent is the progress of the operation from 0 to 100.
+	Percent() float64
+	// BytesDone is the amount of data processed by the operation, zero if BytesTotal is unknown.
+	BytesDone() uint64
+	// BytesTotal is the amount of data processed by the complete operation, zero if unknown.
+	BytesTotal() uint64
+	// ETA estimates the remaining duration of the operation from the progress observed so far.
+	// It reports false as long as there is not enough progress to estimate it.
+	ETA() (time.Duration, bool)
+	// Done reports whether the operation has finished.
+	Done() bool
+}
+
+// ProgressFunc is called with the progress of an operation every time it is polled.
+type ProgressFunc func(progress Progress)
+
+func (opt ProgressFunc) ApplyToLVMergeSnapshotOptions(opts *LVMergeSnapshotOptions) {
+	opts.ProgressFunc = opt
+}
+
+func (opt ProgressFunc) ApplyToWaitForSyncOptions(opts *WaitForSyncOptions) {
+	opts.ProgressFunc = opt
+}
+
+func (opt ProgressFunc) report(progress Progress) {
+	if opt != nil {
+		opt(progress)
+	}
+}
+
+// ProgressTracker derives the Progress of an operation from the percentages observed while polling it.
+// It can be used to report the progress of other long-running operations in the same way as the ones of lvm2go.
+type ProgressTracker struct {
+	operation  string
+	bytesTotal uint64
+
+	mu           sync.Mutex
+	start        time.Time
+	startPercent float64
+}
+
+// NewProgressTracker creates a tracker for the operation, bytesTotal is zero if unknown.
+func NewProgressTracker(operation string, bytesTotal uint64) *ProgressTracker {
+	return &ProgressTracker{operation: operation, bytesTotal: bytesTotal}
+}
+
+// Update observes the progress in percent at the current time.
+func (t *ProgressTracker) Update(percent float64, done bool) Progress {
+	return t.Observe(time.Now(), percent, done)
+}
+
+// Observe observes the progress in percent at the given time.
+// The ETA is extrapolated from the progress since the first observation.
+func (t *ProgressTracker) Observe(at time.Time, percent float64, done bool) Progress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	percent = min(max(percent, 0), 100)
+	if done {
+		percent = 100
+	}
+	if t.start.IsZero() {
+		t.start, t.startPercent = at, percent
+	}
+
+	p := &operationProgress{
+		operation:  t.operation,
+		percent:    percent,
+		bytesTotal: t.bytesTotal,
+		done:       done,
+	}
+	p.bytesDone = uint64(float64(t.bytesTotal) * percent / 100)
+	if done {
+		p.eta, p.etaKnown = 0, true
+	} else if advanced := percent - t.startPercent; advanced > 0 && at.After(t.start) {
+		p.eta, p.etaKnown = time.Duration(float64(at.Sub(t.start))*(100-percent)/advanced), true
+	}
+	return p
+}
+
+type operationProgress struct {
+	operation             string
+	percent               float64
+	bytesDone, bytesTotal uint64
+	eta                   time.Duration
+	etaKnown              bool
+	done                  bool
+}
+
+func (p *operationProgress) Operation() string {
+	return p.operation
+}
+
+func (p *operationProgress) Percent() float64 {
+	return p.percent
+}
+
+func (p *operationProgress) BytesDone() uint64 {
+	return p.bytesDone
+}
+
+func (p *operationProgress) BytesTotal() uint64 {
+	return p.bytesTotal
+}
+
+func (p *operationProgress) ETA() (time.Duration, bool) {
+	return p.eta, p.etaKnown
+}
+
+func (p *operationProgress) Done() bool {
+	return p.done
+}
+
+// sizeInBytes returns the size in bytes, zero if it cannot be converted.
+func sizeInBytes(size Size) uint64 {
+	bytes, err := size.ToUnit(UnitBytes)
+	if err != nil {
+		return 0
+	}
+	return uint64(bytes.Val)
+}
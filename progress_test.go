@@ -0,0 +1,72 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestProgressTracker(t *testing.T) {
+	t.Parallel()
+	start := time.Now()
+	tracker := NewProgressTracker(ProgressOperationPVMove, 1000)
+
+	p := tracker.Observe(start, 10, false)
+	if _, ok := p.ETA(); ok {
+		t.Fatal("expected no ETA without advancing progress")
+	}
+	if p.BytesDone() != 100 || p.BytesTotal() != 1000 {
+		t.Fatalf("unexpected bytes %d/%d", p.BytesDone(), p.BytesTotal())
+	}
+
+	p = tracker.Observe(start.Add(time.Minute), 40, false)
+	if eta, ok := p.ETA(); !ok || eta != 2*time.Minute {
+		t.Fatalf("expected an ETA of 2m, got %s", eta)
+	}
+	if p.Operation() != ProgressOperationPVMove || p.Percent() != 40 || p.Done() {
+		t.Fatalf("unexpected progress %s %.0f %t", p.Operation(), p.Percent(), p.Done())
+	}
+
+	p = tracker.Observe(start.Add(2*time.Minute), 90, true)
+	if eta, ok := p.ETA(); !ok || eta != 0 || p.Percent() != 100 || p.BytesDone() != 1000 {
+		t.Fatalf("expected a finished progress, got %.0f with ETA %s", p.Percent(), eta)
+	}
+}
+
+func TestWaitForSync_ProgressFunc(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv := MustNewFQLogicalVolumeName("vg", "raid")
+
+	var progress []Progress
+	clnt := NewClient(raidReportRunner("rwi-a-r---", [2]string{"resync", "50.00"}, [2]string{"idle", "100.00"}))
+	if _, err := WaitForSync(ctx, clnt, lv, SyncPollInterval(time.Millisecond), ProgressFunc(func(p Progress) {
+		progress = append(progress, p)
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if len(progress) != 2 || progress[0].Done() || !progress[1].Done() {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+	if progress[0].Operation() != ProgressOperationSync || progress[0].Percent() != 50 {
+		t.Fatalf("unexpected progress %s %.0f", progress[0].Operation(), progress[0].Percent())
+	}
+}
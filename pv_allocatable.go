@@ -0,0 +1,46 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+const (
+	SetAllocatable    AllocatablePhysicalVolume = "y"
+	SetNotAllocatable AllocatablePhysicalVolume = "n"
+)
+
+// AllocatablePhysicalVolume allows or prevents the allocation of new extents on a physical volume with PVChange,
+// e.g. to drain it with PVMove without new logical volumes being placed on it in the meantime.
+// Extents already allocated are not affected. See PVAttributes.DuplicateAllocatableUsed.
+type AllocatablePhysicalVolume string
+
+func (opt AllocatablePhysicalVolume) ApplyToPVChangeOptions(opts *PVChangeOptions) {
+	opts.AllocatablePhysicalVolume = opt
+}
+
+func (opt AllocatablePhysicalVolume) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	if opt != SetAllocatable && opt != SetNotAllocatable {
+		return fmt.Errorf("invalid allocatable value %q, must be %q or %q", string(opt), SetAllocatable, SetNotAllocatable)
+	}
+	args.AddOrReplace(fmt.Sprintf("--allocatable=%s", string(opt)))
+	return nil
+}
@@ -0,0 +1,95 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPVChangeAllocatable(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := PVChangeOptionsList{
+		PhysicalVolumeName("/dev/sdb"), SetNotAllocatable, RegenerateUUID(true), Tags{"draining"},
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--allocatable=n", "--uuid"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--addtag @draining") {
+		t.Fatalf("expected the tag to be added in %s", raw)
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("100M"))
+	clnt.SetDevice("/dev/sdc", MustParseSize("100M"))
+	vgName := VolumeGroupName("vg")
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb", "/dev/sdc"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, vgName, LogicalVolumeName("old"), MustParseSize("40M")); err != nil {
+		t.Fatal(err)
+	}
+
+	// draining /dev/sdb places new allocations on /dev/sdc only
+	if err := clnt.PVChange(ctx, PhysicalVolumeName("/dev/sdb"), SetNotAllocatable, Tags{"draining"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, vgName, LogicalVolumeName("new"), MustParseSize("60M")); err != nil {
+		t.Fatal(err)
+	}
+	pvs, err := clnt.PVs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, pv := range pvs {
+		switch pv.Name {
+		case "/dev/sdb":
+			if pv.Attr.DuplicateAllocatableUsed == Allocatable || !slices.Contains(pv.Tags, "draining") {
+				t.Fatalf("expected /dev/sdb to be tagged and not allocatable, got %+v", pv)
+			}
+		case "/dev/sdc":
+			if pv.Used.Val == 0 {
+				t.Fatal("expected the new logical volume on /dev/sdc")
+			}
+		}
+	}
+	if err := clnt.PVMove(ctx, PhysicalVolumeName("/dev/sdb")); err == nil {
+		t.Fatal("expected error moving 40M to /dev/sdc with 36M free")
+	}
+	if err := clnt.LVRemove(ctx, vgName, LogicalVolumeName("new")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.PVMove(ctx, PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clnt.PVChange(ctx, PhysicalVolumeName("/dev/sdb"), RegenerateUUID(true)); err == nil {
+		t.Fatal("expected error regenerating the UUID with active logical volumes")
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+const (
+	// NoMetadataCopies stores no metadata on a physical volume, which then relies on the metadata
+	// of the other physical volumes of its volume group. It is passed as 0, as the zero value is not passed.
+	NoMetadataCopies  MetadataCopies = -1
+	OneMetadataCopy   MetadataCopies = 1
+	TwoMetadataCopies MetadataCopies = 2
+)
+
+// MetadataCopies is the number of metadata areas on a physical volume created by PVCreate.
+// A second copy is stored at the end of the device.
+type MetadataCopies int
+
+func (opt MetadataCopies) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.MetadataCopies = opt
+}
+
+func (opt MetadataCopies) ApplyToArgs(args Arguments) error {
+	if opt == 0 {
+		return nil
+	}
+	if opt < NoMetadataCopies || opt > TwoMetadataCopies {
+		return fmt.Errorf("invalid number of metadata copies %d, must be 0, 1 or 2", opt)
+	}
+	args.AddOrReplace(fmt.Sprintf("--metadatacopies=%d", max(opt, 0)))
+	return nil
+}
+
+// BootLoaderAreaSize reserves space for a boot loader at the start of a physical volume created by PVCreate,
+// between the metadata and the data area.
+type BootLoaderAreaSize Size
+
+func (opt BootLoaderAreaSize) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.BootLoaderAreaSize = opt
+}
+
+func (opt BootLoaderAreaSize) ApplyToArgs(args Arguments) error {
+	if opt.Val == 0 {
+		return nil
+	}
+	return Size(opt).applyToArgs(bootLoaderAreaSizeArg, args)
+}
+
+const (
+	SetMetadataIgnore   MetadataIgnore = "y"
+	ClearMetadataIgnore MetadataIgnore = "n"
+)
+
+// MetadataIgnore makes lvm2 ignore the metadata areas of a physical volume with PVCreate or PVChange,
+// so that updates of the volume group metadata do not write to them. At least one physical volume
+// of a volume group has to keep using its metadata areas.
+type MetadataIgnore string
+
+func (opt MetadataIgnore) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.MetadataIgnore = opt
+}
+
+func (opt MetadataIgnore) ApplyToPVChangeOptions(opts *PVChangeOptions) {
+	opts.MetadataIgnore = opt
+}
+
+func (opt MetadataIgnore) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	if opt != SetMetadataIgnore && opt != ClearMetadataIgnore {
+		return fmt.Errorf("invalid metadata ignore value %q, must be %q or %q", string(opt), SetMetadataIgnore, ClearMetadataIgnore)
+	}
+	args.AddOrReplace(fmt.Sprintf("--metadataignore=%s", string(opt)))
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPhysicalVolumeLayout(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := PVCreateOptionsList{&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); strings.Contains(raw, "--metadatasize") || strings.Contains(raw, "--dataalignment") {
+		t.Fatalf("expected unset sizes not to be passed, got %s", raw)
+	}
+
+	args, err = PVCreateOptionsList{
+		&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"},
+		NoMetadataCopies, MetadataSize(MustParseSize("4M")), DataAlignment(MustParseSize("1M")),
+		DataAlignmentOffset(MustParseSize("64K")), BootLoaderAreaSize(MustParseSize("1M")), SetMetadataIgnore,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{
+		"--metadatacopies=0", "--metadatasize=4.00m", "--dataalignment=1.00m",
+		"--dataalignmentoffset=64.00k", "--bootloaderareasize=1.00m", "--metadataignore=y",
+	} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	if _, err := (PVCreateOptionsList{&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, MetadataCopies(3)}).AsArgs(); err == nil {
+		t.Fatal("expected error for three metadata copies")
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("1G"))
+	clnt.SetDevice("/dev/sdc", MustParseSize("1G"))
+	if err := clnt.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, TwoMetadataCopies); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdc"}, NoMetadataCopies); err != nil {
+		t.Fatal(err)
+	}
+	vgName := VolumeGroupName("vg")
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb", "/dev/sdc"}); err != nil {
+		t.Fatal(err)
+	}
+	vg, err := clnt.VG(ctx, vgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.MDACount != 2 || vg.MDAUsedCount != 2 {
+		t.Fatalf("expected two metadata areas, got %d with %d used", vg.MDACount, vg.MDAUsedCount)
+	}
+
+	if err := clnt.PVChange(ctx, PhysicalVolumeName("/dev/sdb"), SetMetadataIgnore); err == nil {
+		t.Fatal("expected error ignoring the last used metadata areas")
+	}
+	if err := clnt.PVChange(ctx, PhysicalVolumeName("/dev/sdc"), SetMetadataIgnore); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PhysicalVolumeUUID is the UUID given to a physical volume by PVCreate instead of a random one.
+// It is used to recreate a physical volume that was lost or overwritten with its old UUID,
+// so that the metadata of its volume group can be restored with VGCfgRestore afterward.
+// It requires either RestoreFile or NoRestoreFile.
+type PhysicalVolumeUUID string
+
+func (opt PhysicalVolumeUUID) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.PhysicalVolumeUUID = opt
+}
+
+func (opt PhysicalVolumeUUID) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+	args.AddOrReplace(fmt.Sprintf("--uuid=%s", string(opt)))
+	return nil
+}
+
+// Validate checks that the UUID has the format of lvm2, 32 alphanumeric characters optionally
+// grouped by dashes, e.g. 7JbVPt-gA2P-fNZr-PLgL-MZ5J-vDvn-nVo6eN.
+func (opt PhysicalVolumeUUID) Validate() error {
+	uuid := strings.ReplaceAll(string(opt), "-", "")
+	if len(uuid) != 32 || strings.IndexFunc(uuid, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) >= 0 {
+		return fmt.Errorf("invalid physical volume UUID %q", string(opt))
+	}
+	return nil
+}
+
+// RestoreFile is a metadata backup, e.g. /etc/lvm/backup/vg, PVCreate reads the location of the metadata
+// and data areas of the physical volume with the PhysicalVolumeUUID from, so that they match the backup.
+type RestoreFile string
+
+func (opt RestoreFile) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.RestoreFile = opt
+}
+
+func (opt RestoreFile) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--restorefile=%s", string(opt)))
+	return nil
+}
+
+// NoRestoreFile allows PhysicalVolumeUUID without a RestoreFile, placing the data area at the default location.
+type NoRestoreFile bool
+
+func (opt NoRestoreFile) ApplyToPVCreateOptions(opts *PVCreateOptions) {
+	opts.NoRestoreFile = opt
+}
+
+func (opt NoRestoreFile) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--norestorefile"})
+	}
+	return nil
+}
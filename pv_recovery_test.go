@@ -0,0 +1,103 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPVCreateRecovery(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	const uuid = "7JbVPt-gA2P-fNZr-PLgL-MZ5J-vDvn-nVo6eN"
+	args, err := PVCreateOptionsList{
+		&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, PhysicalVolumeUUID(uuid), RestoreFile("/etc/lvm/backup/vg"),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--uuid=" + uuid, "--restorefile=/etc/lvm/backup/vg"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	for _, opts := range []PVCreateOptionsList{
+		{&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, PhysicalVolumeUUID(uuid)},
+		{&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, RestoreFile("/etc/lvm/backup/vg"), NoRestoreFile(true)},
+		{&PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}, PhysicalVolumeUUID("not-a-uuid"), NoRestoreFile(true)},
+	} {
+		if _, err := opts.AsArgs(); err == nil {
+			t.Fatalf("expected error for %v", opts)
+		}
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("1G"))
+	vgName := VolumeGroupName("vg")
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCfgBackup(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+	pvs, err := clnt.PVs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pvs) != 1 {
+		t.Fatalf("expected one physical volume, got %d", len(pvs))
+	}
+	pv := pvs[0]
+
+	// losing the physical volume and recreating it with a new UUID prevents the restore
+	if err := clnt.VGRemove(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.PVRemove(ctx, PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCfgRestore(ctx, vgName); err == nil {
+		t.Fatal("expected error restoring onto a physical volume with a new UUID")
+	}
+	if err := clnt.PVRemove(ctx, PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clnt.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdb"},
+		PhysicalVolumeUUID(uuid), RestoreFile(fake.DefaultBackupDirectory+"/vg")); err == nil {
+		t.Fatal("expected error for a UUID that is not in the backup")
+	}
+	if err := clnt.PVCreate(ctx, &PVCreateOptions{PhysicalVolumeName: "/dev/sdb"},
+		PhysicalVolumeUUID(pv.UUID), RestoreFile(fake.DefaultBackupDirectory+"/vg")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCfgRestore(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := clnt.VG(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+}
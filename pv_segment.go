@@ -0,0 +1,240 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"slices"
+)
+
+// SegmentTypeFree is the segment type reported by lvm2 for unallocated physical extents.
+const SegmentTypeFree = "free"
+
+var DefaultPVSegmentsColumnOptions = ColumnOptions{
+	"pv_name",
+	"pv_uuid",
+	"vg_name",
+	"pvseg_start",
+	"pvseg_size",
+	"lv_name",
+	"lv_uuid",
+	"seg_start_pe",
+	"segtype",
+}
+
+// PhysicalVolumeSegment is a contiguous range of physical extents on a physical volume.
+// It is the structured equivalent of a single "Physical extent X to Y" block of pvdisplay --maps.
+// If the segment is allocated, it maps the physical extents [Start, Start+Size) to the logical extents
+// [LogicalExtentStart, LogicalExtentStart+Size) of the logical volume LogicalVolumeName.
+type PhysicalVolumeSegment struct {
+	PhysicalVolumeName PhysicalVolumeName `json:"pv_name"`
+	PhysicalVolumeUUID string             `json:"pv_uuid"`
+	VolumeGroupName    VolumeGroupName    `json:"vg_name"`
+
+	// Start is the first physical extent of the segment.
+	Start int64 `json:"pvseg_start"`
+	// Size is the number of physical extents in the segment.
+	Size int64 `json:"pvseg_size"`
+
+	LogicalVolumeName LogicalVolumeName `json:"lv_name"`
+	LogicalVolumeUUID string            `json:"lv_uuid"`
+	// LogicalExtentStart is the first logical extent of the logical volume mapped by the segment.
+	LogicalExtentStart int64  `json:"seg_start_pe"`
+	SegmentType        string `json:"segtype"`
+}
+
+func (seg *PhysicalVolumeSegment) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	for key, fieldPtr := range map[string]*string{
+		"pv_name": (*string)(&seg.PhysicalVolumeName),
+		"pv_uuid": &seg.PhysicalVolumeUUID,
+		"vg_name": (*string)(&seg.VolumeGroupName),
+		"lv_name": (*string)(&seg.LogicalVolumeName),
+		"lv_uuid": &seg.LogicalVolumeUUID,
+		"segtype": &seg.SegmentType,
+	} {
+		if val, ok := raw[key]; !ok {
+			continue
+		} else if err := json.Unmarshal(val, fieldPtr); err != nil {
+			return err
+		}
+	}
+
+	for key, fieldPtr := range map[string]*int64{
+		"pvseg_start":  &seg.Start,
+		"pvseg_size":   &seg.Size,
+		"seg_start_pe": &seg.LogicalExtentStart,
+	} {
+		if err := unmarshalToStringAndParseInt64(raw, key, fieldPtr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// IsFree returns true if the segment is not allocated to any logical volume.
+func (seg *PhysicalVolumeSegment) IsFree() bool {
+	return seg.SegmentType == SegmentTypeFree || seg.LogicalVolumeName == ""
+}
+
+// End returns the last physical extent of the segment.
+func (seg *PhysicalVolumeSegment) End() int64 {
+	return seg.Start + seg.Size - 1
+}
+
+// LogicalExtentEnd returns the last logical extent of the logical volume mapped by the segment.
+func (seg *PhysicalVolumeSegment) LogicalExtentEnd() int64 {
+	return seg.LogicalExtentStart + seg.Size - 1
+}
+
+// PhysicalExtentMap groups physical volume segments by the physical volume they are located on.
+// The segments of each physical volume are ordered by their starting physical extent.
+type PhysicalExtentMap map[PhysicalVolumeName][]*PhysicalVolumeSegment
+
+// NewPhysicalExtentMap creates a PhysicalExtentMap from the given segments.
+func NewPhysicalExtentMap(segments []*PhysicalVolumeSegment) PhysicalExtentMap {
+	extentMap := make(PhysicalExtentMap)
+	for _, seg := range segments {
+		extentMap[seg.PhysicalVolumeName] = append(extentMap[seg.PhysicalVolumeName], seg)
+	}
+	for _, segs := range extentMap {
+		slices.SortFunc(segs, func(a, b *PhysicalVolumeSegment) int {
+			return cmp.Compare(a.Start, b.Start)
+		})
+	}
+	return extentMap
+}
+
+// ForLogicalVolume returns all segments of the map that are allocated to the given logical volume.
+func (m PhysicalExtentMap) ForLogicalVolume(vg VolumeGroupName, lv LogicalVolumeName) []*PhysicalVolumeSegment {
+	var segments []*PhysicalVolumeSegment
+	for _, segs := range m {
+		for _, seg := range segs {
+			if seg.VolumeGroupName == vg && seg.LogicalVolumeName == lv {
+				segments = append(segments, seg)
+			}
+		}
+	}
+	return segments
+}
+
+// FreeRanges returns the ranges of unallocated physical extents on the given physical volume ordered by their start.
+// Adjacent free segments are merged into a single range.
+func (m PhysicalExtentMap) FreeRanges(pv PhysicalVolumeName) []PhysicalExtentRange {
+	var ranges []PhysicalExtentRange
+	for _, seg := range m[pv] {
+		if !seg.IsFree() || seg.Size == 0 {
+			continue
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End+1 == seg.Start {
+			ranges[n-1].End = seg.End()
+			continue
+		}
+		ranges = append(ranges, PhysicalExtentRange{PhysicalVolumeName: pv, Start: seg.Start, End: seg.End()})
+	}
+	return ranges
+}
+
+// FreeExtents returns the number of unallocated physical extents on the given physical volume.
+func (m PhysicalExtentMap) FreeExtents(pv PhysicalVolumeName) int64 {
+	var free int64
+	for _, r := range m.FreeRanges(pv) {
+		free += r.Size()
+	}
+	return free
+}
+
+// LargestFreeRange returns the largest range of unallocated physical extents on the given physical volume.
+// If the physical volume has no free extents, false is returned.
+func (m PhysicalExtentMap) LargestFreeRange(pv PhysicalVolumeName) (PhysicalExtentRange, bool) {
+	ranges := m.FreeRanges(pv)
+	if len(ranges) == 0 {
+		return PhysicalExtentRange{}, false
+	}
+	return slices.MaxFunc(ranges, func(a, b PhysicalExtentRange) int {
+		// prefer the first range among ranges of equal size
+		return cmp.Or(cmp.Compare(a.Size(), b.Size()), cmp.Compare(b.Start, a.Start))
+	}), true
+}
+
+// FindContiguousFree returns the first range of unallocated physical extents on the given physical volume
+// that can hold the given number of extents, similar to the contiguous allocation policy of lvm2.
+// The returned range is trimmed to the requested number of extents.
+// If no such range exists, false is returned.
+// Sizes can be converted to extents with Size.ToExtents and the extent size of the volume group.
+func (m PhysicalExtentMap) FindContiguousFree(pv PhysicalVolumeName, extents int64) (PhysicalExtentRange, bool) {
+	if extents <= 0 {
+		return PhysicalExtentRange{}, false
+	}
+	for _, r := range m.FreeRanges(pv) {
+		if r.Size() >= extents {
+			r.End = r.Start + extents - 1
+			return r, true
+		}
+	}
+	return PhysicalExtentRange{}, false
+}
+
+// PVSegments returns the physical extent segments of all physical volumes that match the given options.
+// If no segments are found, nil is returned.
+// It is really just a wrapper around the `pvs --segments --reportformat json` command.
+func (c *client) PVSegments(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolumeSegment, error) {
+	type pvsegReport struct {
+		Report []struct {
+			PVSeg []*PhysicalVolumeSegment `json:"pvseg"`
+		} `json:"report"`
+	}
+
+	var res = new(pvsegReport)
+
+	args := []string{
+		"pvs", "--segments", "--reportformat", "json",
+	}
+	argsFromOpts, err := PVsOptionsList(append([]PVsOption{DefaultPVSegmentsColumnOptions, c.opts.ReportLocking}, opts...)).AsArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
+
+	if IsNotFound(err) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.Report) == 0 {
+		return nil, nil
+	}
+
+	segments := res.Report[0].PVSeg
+
+	if len(segments) == 0 {
+		return nil, nil
+	}
+
+	return segments, nil
+}
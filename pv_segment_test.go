@@ -0,0 +1,99 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestPhysicalExtentMap(t *testing.T) {
+	t.Parallel()
+	raw := `[
+		{"pv_name":"/dev/loop0", "vg_name":"vg", "pvseg_start":"10", "pvseg_size":"5", "lv_name":"", "seg_start_pe":"0", "segtype":"free"},
+		{"pv_name":"/dev/loop0", "vg_name":"vg", "pvseg_start":"0", "pvseg_size":"10", "lv_name":"lv", "seg_start_pe":"0", "segtype":"linear"},
+		{"pv_name":"/dev/loop1", "vg_name":"vg", "pvseg_start":"0", "pvseg_size":"4", "lv_name":"lv", "seg_start_pe":"10", "segtype":"linear"}
+	]`
+
+	var segments []*PhysicalVolumeSegment
+	if err := json.Unmarshal([]byte(raw), &segments); err != nil {
+		t.Fatal(err)
+	}
+
+	extentMap := NewPhysicalExtentMap(segments)
+	if len(extentMap) != 2 {
+		t.Fatalf("expected 2 physical volumes in map, got %d", len(extentMap))
+	}
+
+	loop0 := extentMap["/dev/loop0"]
+	if len(loop0) != 2 {
+		t.Fatalf("expected 2 segments on /dev/loop0, got %d", len(loop0))
+	}
+	if loop0[0].Start != 0 || loop0[0].End() != 9 || loop0[0].IsFree() {
+		t.Fatalf("unexpected first segment on /dev/loop0: %+v", loop0[0])
+	}
+	if loop0[1].Start != 10 || !loop0[1].IsFree() {
+		t.Fatalf("unexpected second segment on /dev/loop0: %+v", loop0[1])
+	}
+
+	lvSegments := extentMap.ForLogicalVolume("vg", "lv")
+	if len(lvSegments) != 2 {
+		t.Fatalf("expected 2 segments for vg/lv, got %d", len(lvSegments))
+	}
+	for _, seg := range lvSegments {
+		if seg.PhysicalVolumeName == "/dev/loop1" && seg.LogicalExtentEnd() != 13 {
+			t.Fatalf("expected logical extent end 13, got %d", seg.LogicalExtentEnd())
+		}
+	}
+}
+
+func TestPhysicalExtentMap_FreeRanges(t *testing.T) {
+	t.Parallel()
+	extentMap := NewPhysicalExtentMap([]*PhysicalVolumeSegment{
+		{PhysicalVolumeName: "/dev/loop0", Start: 0, Size: 10, LogicalVolumeName: "lv1", SegmentType: "linear"},
+		{PhysicalVolumeName: "/dev/loop0", Start: 10, Size: 5, SegmentType: SegmentTypeFree},
+		{PhysicalVolumeName: "/dev/loop0", Start: 15, Size: 5, LogicalVolumeName: "lv2", SegmentType: "linear"},
+		{PhysicalVolumeName: "/dev/loop0", Start: 20, Size: 30, SegmentType: SegmentTypeFree},
+		{PhysicalVolumeName: "/dev/loop1", Start: 0, Size: 25, LogicalVolumeName: "lv1", SegmentType: "linear"},
+	})
+
+	ranges := extentMap.FreeRanges("/dev/loop0")
+	if len(ranges) != 2 || ranges[0].String() != "/dev/loop0:10-14" || ranges[1].String() != "/dev/loop0:20-49" {
+		t.Fatalf("unexpected free ranges %v", ranges)
+	}
+	if free := extentMap.FreeExtents("/dev/loop0"); free != 35 {
+		t.Fatalf("expected 35 free extents, got %d", free)
+	}
+	if largest, ok := extentMap.LargestFreeRange("/dev/loop0"); !ok || largest.Size() != 30 {
+		t.Fatalf("unexpected largest free range %v", largest)
+	}
+	if _, ok := extentMap.LargestFreeRange("/dev/loop1"); ok {
+		t.Fatal("expected no free range on /dev/loop1")
+	}
+
+	if r, ok := extentMap.FindContiguousFree("/dev/loop0", 5); !ok || r.String() != "/dev/loop0:10-14" {
+		t.Fatalf("expected first fit in the first free range, got %v", r)
+	}
+	if r, ok := extentMap.FindContiguousFree("/dev/loop0", 8); !ok || r.String() != "/dev/loop0:20-27" {
+		t.Fatalf("expected first fit in the second free range, got %v", r)
+	}
+	if _, ok := extentMap.FindContiguousFree("/dev/loop0", 31); ok {
+		t.Fatal("expected no contiguous range of 31 extents")
+	}
+}
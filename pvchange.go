@@ -25,6 +25,9 @@ type (
 		PhysicalVolumeName
 		Tags
 		DelTags
+		AllocatablePhysicalVolume
+		RegenerateUUID
+		MetadataIgnore
 		CommonOptions
 	}
 	PVChangeOption interface {
@@ -52,7 +55,7 @@ func (opts *PVChangeOptions) ApplyToPVChangeOptions(new *PVChangeOptions) {
 }
 
 func (list PVChangeOptionsList) AsArgs() (Arguments, error) {
-	args := NewArgs(ArgsTypeGeneric)
+	args := NewArgs(ArgsTypePVChange)
 	options := PVChangeOptions{}
 	for _, opt := range list {
 		opt.ApplyToPVChangeOptions(&options)
@@ -72,6 +75,9 @@ func (opts *PVChangeOptions) ApplyToArgs(args Arguments) error {
 		opts.PhysicalVolumeName,
 		opts.Tags,
 		opts.DelTags,
+		opts.AllocatablePhysicalVolume,
+		opts.RegenerateUUID,
+		opts.MetadataIgnore,
 		opts.CommonOptions,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
@@ -0,0 +1,176 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+type (
+	// PVCheckOptions describe a check of the lvm2 headers and metadata on a physical volume.
+	// With Dump, the headers or metadata are dumped, with Repair, they are rewritten.
+	PVCheckOptions struct {
+		PhysicalVolumeName
+		PVCheckDump
+		Repair
+		BackupFile
+
+		CommonOptions
+	}
+	PVCheckOption interface {
+		ApplyToPVCheckOptions(opts *PVCheckOptions)
+	}
+	PVCheckOptionsList []PVCheckOption
+)
+
+// PVCheckDump selects what PVCheck dumps from the physical volume.
+// With BackupFile, metadata dumps are written to the file instead.
+type PVCheckDump string
+
+const (
+	PVCheckDumpHeaders        PVCheckDump = "headers"
+	PVCheckDumpMetadata       PVCheckDump = "metadata"
+	PVCheckDumpMetadataAll    PVCheckDump = "metadata_all"
+	PVCheckDumpMetadataSearch PVCheckDump = "metadata_search"
+	PVCheckDumpMetadataArea   PVCheckDump = "metadata_area"
+)
+
+func (opt PVCheckDump) ApplyToPVCheckOptions(opts *PVCheckOptions) {
+	opts.PVCheckDump = opt
+}
+
+func (opt PVCheckDump) ApplyToArgs(args Arguments) error {
+	if opt != "" {
+		args.AddOrReplace(fmt.Sprintf("--dump=%s", string(opt)))
+	}
+	return nil
+}
+
+func (opt BackupFile) ApplyToPVCheckOptions(opts *PVCheckOptions) {
+	opts.BackupFile = opt
+}
+
+func (opt PhysicalVolumeName) ApplyToPVCheckOptions(opts *PVCheckOptions) {
+	opts.PhysicalVolumeName = opt
+}
+
+// pvCheckProblemPrefix is the prefix of failed checks in the output of pvck.
+const pvCheckProblemPrefix = "CHECK: "
+
+// PVCheckResult is the result of PVCheck.
+type PVCheckResult struct {
+	// Fields are the dumped header fields by name, e.g. pv_header.device_size.
+	Fields map[string]string
+	// Problems are the failed checks of pvck, without the CHECK prefix.
+	Problems []string
+	// Output is the complete output of pvck, e.g. the metadata text of PVCheckDumpMetadata.
+	Output string
+}
+
+// Healthy returns true if pvck found no problems.
+func (r *PVCheckResult) Healthy() bool {
+	return len(r.Problems) == 0
+}
+
+var (
+	_ ArgumentGenerator = PVCheckOptionsList{}
+	_ Argument          = (*PVCheckOptions)(nil)
+)
+
+func (c *client) PVCheck(ctx context.Context, opts ...PVCheckOption) (*PVCheckResult, error) {
+	args, err := PVCheckOptionsList(opts).AsArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	var result *PVCheckResult
+	err = c.RunLVMRaw(ctx, func(out io.Reader) error {
+		var err error
+		result, err = ParsePVCheckOutput(out)
+		return err
+	}, append([]string{"pvck"}, args.GetRaw()...)...)
+	// pvck fails if checks fail, the problems are still reported with the error
+	return result, err
+}
+
+// ParsePVCheckOutput parses the output of pvck.
+func ParsePVCheckOutput(out io.Reader) (*PVCheckResult, error) {
+	result := &PVCheckResult{Fields: map[string]string{}}
+	var output strings.Builder
+	scanner := bufio.NewScanner(out)
+	for scanner.Scan() {
+		output.WriteString(scanner.Text())
+		output.WriteByte('\n')
+
+		line := strings.TrimSpace(scanner.Text())
+		if problem, ok := strings.CutPrefix(line, pvCheckProblemPrefix); ok {
+			result.Problems = append(result.Problems, problem)
+			continue
+		}
+		// header fields are dumped as "<header>.<field> <value>"
+		key, value, ok := strings.Cut(line, " ")
+		if ok && strings.Contains(key, ".") && !strings.ContainsAny(key, "=/") {
+			result.Fields[key] = strings.TrimSpace(value)
+		}
+	}
+	result.Output = output.String()
+	return result, scanner.Err()
+}
+
+func (list PVCheckOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := PVCheckOptions{}
+	for _, opt := range list {
+		opt.ApplyToPVCheckOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *PVCheckOptions) ApplyToPVCheckOptions(new *PVCheckOptions) {
+	*new = *opts
+}
+
+func (opts *PVCheckOptions) ApplyToArgs(args Arguments) error {
+	if opts.PhysicalVolumeName == "" {
+		return ErrPhysicalVolumeNameRequired
+	}
+	if opts.PVCheckDump != "" && opts.Repair {
+		return errors.New("PVCheckDump and Repair are mutually exclusive")
+	}
+
+	for _, arg := range []Argument{
+		opts.PVCheckDump,
+		opts.Repair,
+		opts.BackupFile,
+		opts.PhysicalVolumeName,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
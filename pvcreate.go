@@ -18,6 +18,7 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
 )
 
 type (
@@ -28,6 +29,14 @@ type (
 		DataAlignment
 		DataAlignmentOffset
 		MetadataSize
+		MetadataCopies
+		MetadataIgnore
+		BootLoaderAreaSize
+
+		PhysicalVolumeUUID
+		RestoreFile
+		NoRestoreFile
+
 		CommonOptions
 	}
 	PVCreateOption interface {
@@ -71,6 +80,16 @@ func (opts *PVCreateOptions) ApplyToArgs(args Arguments) error {
 		return ErrPhysicalVolumeNameRequired
 	}
 
+	if opts.RestoreFile != "" && bool(opts.NoRestoreFile) {
+		return fmt.Errorf("RestoreFile and NoRestoreFile are mutually exclusive")
+	}
+	if opts.RestoreFile != "" && opts.PhysicalVolumeUUID == "" {
+		return fmt.Errorf("RestoreFile requires PhysicalVolumeUUID")
+	}
+	if opts.PhysicalVolumeUUID != "" && opts.RestoreFile == "" && !bool(opts.NoRestoreFile) {
+		return fmt.Errorf("PhysicalVolumeUUID requires RestoreFile or NoRestoreFile")
+	}
+
 	for _, arg := range []Argument{
 		opts.PhysicalVolumeName,
 		opts.Force,
@@ -78,6 +97,12 @@ func (opts *PVCreateOptions) ApplyToArgs(args Arguments) error {
 		opts.DataAlignment,
 		opts.DataAlignmentOffset,
 		opts.MetadataSize,
+		opts.MetadataCopies,
+		opts.MetadataIgnore,
+		opts.BootLoaderAreaSize,
+		opts.PhysicalVolumeUUID,
+		opts.RestoreFile,
+		opts.NoRestoreFile,
 		opts.CommonOptions,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
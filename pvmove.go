@@ -18,15 +18,26 @@ package lvm2go
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 )
 
+// ErrLogicalVolumeNotOnPhysicalVolume is returned by PVMove if the logical volume to move
+// has no extents on the source physical volume.
+var ErrLogicalVolumeNotOnPhysicalVolume = errors.New("logical volume has no extents on the physical volume")
+
 type (
+	// PVMoveOptions describe a move of the extents of the physical volume From to the physical volumes To.
+	// With LogicalVolumeName, only the extents of that logical volume are moved.
 	PVMoveOptions struct {
 		From PhysicalVolumeName
 		To   PhysicalVolumeNames
 		LogicalVolumeName
 		AllocationPolicy
+		Atomic
+		Background
 		CommonOptions
 	}
 	PVMoveOption interface {
@@ -54,6 +65,29 @@ func (c *client) PVMove(ctx context.Context, opts ...PVMoveOption) error {
 		return err
 	}
 
+	options := PVMoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVMoveOptions(&options)
+	}
+	// lvm2 only reports that there is no data to move once the move was set up
+	if options.LogicalVolumeName != "" {
+		segments, err := c.PVSegments(ctx, PhysicalVolumeNames{options.From})
+		if err != nil {
+			return err
+		}
+		// the extents of raid, mirror, thin pool and cache logical volumes belong to their hidden sub volumes,
+		// which are named after the logical volume with an internal suffix, e.g. [lv_rimage_0]
+		if !slices.ContainsFunc(segments, func(seg *PhysicalVolumeSegment) bool {
+			name := string(seg.LogicalVolumeName)
+			if internalTypeOf(seg.LogicalVolumeName) != "" {
+				name = internalSuffix.ReplaceAllString(strings.Trim(name, "[]"), "")
+			}
+			return name == string(options.LogicalVolumeName)
+		}) {
+			return fmt.Errorf("%w: %s is not located on %s", ErrLogicalVolumeNotOnPhysicalVolume, options.LogicalVolumeName, options.From)
+		}
+	}
+
 	return c.RunLVM(ctx, append([]string{"pvmove"}, args.GetRaw()...)...)
 }
 
@@ -86,6 +120,8 @@ func (opts *PVMoveOptions) ApplyToArgs(args Arguments) error {
 		opts.From,
 		opts.To,
 		opts.AllocationPolicy,
+		opts.Atomic,
+		opts.Background,
 		opts.CommonOptions,
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
@@ -95,3 +131,18 @@ func (opts *PVMoveOptions) ApplyToArgs(args Arguments) error {
 
 	return nil
 }
+
+// Atomic makes PVMove move all segments of the logical volumes at once, so that either all or none of
+// their extents end up on the destination physical volumes if the move is interrupted.
+type Atomic bool
+
+func (opt Atomic) ApplyToPVMoveOptions(opts *PVMoveOptions) {
+	opts.Atomic = opt
+}
+
+func (opt Atomic) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--atomic"})
+	}
+	return nil
+}
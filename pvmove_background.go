@@ -0,0 +1,151 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// DefaultPVMovePollInterval is the interval in which PVMoveHandle.Wait polls the progress of a pvmove.
+const DefaultPVMovePollInterval = 10 * time.Second
+
+// Background makes PVMove return as soon as the move was started, while lvm2 continues it in the background.
+// See PVMoveInBackground for tracking the progress of the move.
+type Background bool
+
+func (opt Background) ApplyToPVMoveOptions(opts *PVMoveOptions) {
+	opts.Background = opt
+}
+
+func (opt Background) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--background"})
+	}
+	return nil
+}
+
+// PVMoveHandle tracks a pvmove running in the background, see PVMoveInBackground.
+type PVMoveHandle struct {
+	client Client
+
+	// From is the physical volume that is moved.
+	From PhysicalVolumeName
+	// VolumeGroupName is the volume group of the physical volume.
+	VolumeGroupName VolumeGroupName
+	// PollInterval is the interval in which Wait polls the progress.
+	// If zero, DefaultPVMovePollInterval is used.
+	PollInterval time.Duration
+}
+
+// PVMoveInBackground starts PVMove with Background and returns a handle to track the progress of the move,
+// so that callers do not have to block on the command for the hours a move of a large physical volume can take.
+// The move continues if the handle is dropped or the process exits.
+func PVMoveInBackground(ctx context.Context, client Client, opts ...PVMoveOption) (*PVMoveHandle, error) {
+	options := PVMoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVMoveOptions(&options)
+	}
+	if options.From == "" {
+		return nil, fmt.Errorf("from is empty: %w", ErrPhysicalVolumeNameRequired)
+	}
+
+	pvs, err := client.PVs(ctx, PhysicalVolumeNames{options.From})
+	if err != nil {
+		return nil, err
+	}
+	if len(pvs) == 0 || pvs[0].VGName == "" {
+		return nil, fmt.Errorf("physical volume %s is not part of a volume group", options.From)
+	}
+
+	if err := client.PVMove(ctx, append(slices.Clone(opts), Background(true))...); err != nil {
+		return nil, err
+	}
+
+	return &PVMoveHandle{
+		client:          client,
+		From:            options.From,
+		VolumeGroupName: pvs[0].VGName,
+	}, nil
+}
+
+// Progress returns the progress of the move in percent, derived from the copy_percent of the logical volumes
+// that are moved, and whether the move is done. lvm2 moves the logical volumes one after another,
+// so the progress is the one of the logical volume that is moved at the moment.
+// A move that was aborted is reported as done as well, as lvm2 leaves no trace of it in the reports.
+func (h *PVMoveHandle) Progress(ctx context.Context) (percent float64, done bool, err error) {
+	lvs, err := h.client.LVs(ctx, h.VolumeGroupName)
+	if err != nil {
+		return 0, false, err
+	}
+	moving := false
+	percent = 100
+	for _, lv := range lvs {
+		if lv.MovePV != h.From {
+			continue
+		}
+		moving = true
+		percent = min(percent, lv.CopyPercent)
+	}
+	return percent, !moving, nil
+}
+
+// Wait blocks until the move is done, calling progress with the progress in percent every time it is polled
+// if progress is not nil.
+func (h *PVMoveHandle) Wait(ctx context.Context, progress func(percent float64)) error {
+	return h.Watch(ctx, func(p Progress) {
+		if progress != nil {
+			progress(p.Percent())
+		}
+	})
+}
+
+// Watch blocks until the move is done like Wait, reporting the Progress of the move every time it is polled.
+// The amount of data to move is the used space of the physical volume when Watch is called.
+func (h *PVMoveHandle) Watch(ctx context.Context, progress ProgressFunc) error {
+	var bytesTotal uint64
+	if pvs, err := h.client.PVs(ctx, PhysicalVolumeNames{h.From}); err == nil && len(pvs) > 0 {
+		bytesTotal = sizeInBytes(pvs[0].Used)
+	}
+	tracker := NewProgressTracker(ProgressOperationPVMove, bytesTotal)
+
+	interval := h.PollInterval
+	if interval == 0 {
+		interval = DefaultPVMovePollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		percent, done, err := h.Progress(ctx)
+		if err != nil {
+			return err
+		}
+		progress.report(tracker.Update(percent, done))
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,74 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestPVMoveInBackground(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	record := func(command string, opts ArgumentGenerator, prefix []string, stdout string) CommandRecording {
+		args, err := opts.AsArgs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return CommandRecording{
+			Command: "lvm",
+			Args:    append(append([]string{command}, prefix...), args.GetRaw()...),
+			Stdout:  stdout,
+		}
+	}
+	report := []string{"--reportformat", "json"}
+	moveOpts := PVMoveOptionsList{&PVMoveOptions{From: "/dev/sdb", To: PhysicalVolumeNames{"/dev/sdc"}}}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{
+		record("pvs", PVsOptionsList{PhysicalVolumeNames{"/dev/sdb"}}, report,
+			`{"report":[{"pv":[{"pv_name":"/dev/sdb","vg_name":"vg"}]}]}`),
+		record("pvmove", append(slices.Clone(moveOpts), Background(true)), nil, ""),
+		record("lvs", LVsOptionsList{VolumeGroupName("vg")}, report,
+			`{"report":[{"lv":[{"lv_name":"a","vg_name":"vg","move_pv":"/dev/sdb","copy_percent":"42.50"},{"lv_name":"b","vg_name":"vg","move_pv":"","copy_percent":""}]}]}`),
+		record("lvs", LVsOptionsList{VolumeGroupName("vg")}, report,
+			`{"report":[{"lv":[{"lv_name":"a","vg_name":"vg","move_pv":"","copy_percent":""}]}]}`),
+	}))
+
+	handle, err := PVMoveInBackground(ctx, clnt, moveOpts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if handle.VolumeGroupName != "vg" {
+		t.Fatalf("unexpected volume group %s", handle.VolumeGroupName)
+	}
+	handle.PollInterval = time.Millisecond
+
+	var progress []float64
+	if err := handle.Wait(ctx, func(percent float64) {
+		progress = append(progress, percent)
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(progress, []float64{42.5, 100}) {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+}
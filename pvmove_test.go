@@ -0,0 +1,70 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestPVMoveLogicalVolume(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	opts := PVMoveOptionsList{&PVMoveOptions{From: "/dev/sdb", To: PhysicalVolumeNames{"/dev/sdc"}}, LogicalVolumeName("data"), Atomic(true)}
+	args, err := opts.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--name=data", "/dev/sdb", "/dev/sdc", "--atomic"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %q in %v", arg, args.GetRaw())
+		}
+	}
+
+	segmentArgs, err := PVsOptionsList{DefaultPVSegmentsColumnOptions, PhysicalVolumeNames{"/dev/sdb"}}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	segments := func(lv string) CommandRecording {
+		return CommandRecording{
+			Command: "lvm",
+			Args:    append([]string{"pvs", "--segments", "--reportformat", "json"}, segmentArgs.GetRaw()...),
+			Stdout:  `{"report":[{"pvseg":[{"pv_name":"/dev/sdb","vg_name":"vg","pvseg_start":"0","pvseg_size":"25","lv_name":"` + lv + `","segtype":"linear"}]}]}`,
+		}
+	}
+	move := CommandRecording{Command: "lvm", Args: append([]string{"pvmove"}, args.GetRaw()...)}
+
+	// logical volumes that only share the prefix of the name are not sub volumes of data
+	for _, lv := range []string{"other", "data_backup", "[data_backup_rimage_0]", "[data_foo]"} {
+		clnt := NewClient(ReplayCommandRunner([]CommandRecording{segments(lv)}))
+		if err := clnt.PVMove(ctx, opts...); !errors.Is(err, ErrLogicalVolumeNotOnPhysicalVolume) {
+			t.Fatalf("expected logical volume not to be on the physical volume with segment of %s, got %v", lv, err)
+		}
+	}
+
+	for _, lv := range []string{"data", "[data_rimage_0]", "[data_tdata]", "[data_corig]", "[data_mlog]"} {
+		clnt := NewClient(ReplayCommandRunner([]CommandRecording{segments(lv), move}))
+		if err := clnt.PVMove(ctx, opts...); err != nil {
+			t.Fatalf("unexpected error moving segment of %s: %v", lv, err)
+		}
+	}
+}
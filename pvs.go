@@ -18,13 +18,24 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
+	"slices"
 )
 
 type (
+	// PVsOptions limit the report to physical volumes matching the options.
+	// PhysicalVolumeNames are passed as positional arguments,
+	// so that lvm itself limits the scope of the report to the named physical volumes.
 	PVsOptions struct {
+		PhysicalVolumeNames
 		Unit
 		Tags
 		Select
+		ByUUID
+		RetainRawJSON
+		ExtraColumns
+		ReportLocking
+		SortFields
 
 		ColumnOptions
 		CommonOptions
@@ -55,6 +66,18 @@ func (c *client) PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume,
 	args := []string{
 		"pvs", "--reportformat", "json",
 	}
+	// the report locking of the client is applied first, so that it can be overridden per report
+	opts = append([]PVsOption{c.opts.ReportLocking}, opts...)
+	options := PVsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVsOptions(&options)
+	}
+	typedFields := reportFields[PhysicalVolume]()
+	strictColumns := bool(c.opts.StrictDecoding) && len(options.ColumnOptions) == 0
+	if strictColumns {
+		opts = append(slices.Clone(opts), typedFields)
+	}
+
 	argsFromOpts, err := PVsOptionsList(opts).AsArgs()
 	if err != nil {
 		return nil, err
@@ -62,8 +85,9 @@ func (c *client) PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume,
 
 	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
 
+	// names that are not found are skipped, the other names are still reported
 	if IsNotFound(err) {
-		return nil, nil
+		err = nil
 	}
 
 	if err != nil {
@@ -80,16 +104,45 @@ func (c *client) PVs(ctx context.Context, opts ...PVsOption) ([]*PhysicalVolume,
 		return nil, nil
 	}
 
+	if c.opts.StrictDecoding {
+		for _, pv := range pvs {
+			if err := verifyReportFields(pv.raw, append(slices.Clone(typedFields), options.ExtraColumns...), strictColumns, bool(options.RetainRawJSON)); err != nil {
+				return nil, fmt.Errorf("failed to decode physical volume %s strictly: %w", pv.Name, err)
+			}
+		}
+	}
+
+	for _, pv := range pvs {
+		if pv.ExtraFields, err = extraReportFields(pv.raw, typedFields); err != nil {
+			return nil, fmt.Errorf("failed to decode extra fields of physical volume %s: %w", pv.Name, err)
+		}
+	}
+
+	if !options.RetainRawJSON {
+		for _, pv := range pvs {
+			pv.raw = nil
+		}
+	}
+
 	return pvs, nil
 }
 
+// InScope reports whether a physical volume is within the scope of the names of the options.
+// It can be used by clients that filter reports themselves instead of passing the names to lvm.
+func (opts *PVsOptions) InScope(pv PhysicalVolumeName) bool {
+	return len(opts.PhysicalVolumeNames) == 0 || slices.Contains(opts.PhysicalVolumeNames, pv)
+}
+
 func (opts *PVsOptions) ApplyToArgs(args Arguments) error {
 	for _, arg := range []Argument{
+		opts.PhysicalVolumeNames,
 		opts.Unit,
 		opts.Tags,
+		opts.ReportLocking,
 		opts.CommonOptions,
-		opts.ColumnOptions,
-		opts.Select,
+		opts.ColumnOptions.withExtra(opts.ExtraColumns),
+		opts.SortFields,
+		opts.ByUUID.selectOn(PVUUID, opts.Select),
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
 			return err
@@ -0,0 +1,166 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type (
+	// PVScanOptions describe a scan of devices for physical volumes.
+	//
+	// With Cache and AutoActivate, the scan is used for event based autoactivation: the given devices are recorded
+	// as online and the volume groups they complete are autoactivated, e.g. for a udev event of a newly appeared device:
+	//
+	//	err := client.PVScan(ctx, Cache(true), AutoActivate, DeviceNumber{Major: 8, Minor: 16})
+	PVScanOptions struct {
+		Cache
+		ActivationState
+		PhysicalVolumeNames
+		DeviceNumbers
+
+		CommonOptions
+	}
+	PVScanOption interface {
+		ApplyToPVScanOptions(opts *PVScanOptions)
+	}
+	PVScanOptionsList []PVScanOption
+)
+
+// Cache records the scanned devices as online physical volumes for event based autoactivation with PVScan.
+type Cache bool
+
+func (opt Cache) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.Cache = opt
+}
+
+func (opt Cache) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--cache"})
+	}
+	return nil
+}
+
+func (opt ActivationState) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.ActivationState = opt
+}
+
+func (opt PhysicalVolumeName) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt)
+}
+
+func (opt PhysicalVolumeNames) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.PhysicalVolumeNames = append(opts.PhysicalVolumeNames, opt...)
+}
+
+// DeviceNumber identifies a block device by its major and minor number, as reported by udev events.
+type DeviceNumber struct {
+	Major int64
+	Minor int64
+}
+
+// ParseDeviceNumber parses a device number in the major:minor format.
+func ParseDeviceNumber(s string) (DeviceNumber, error) {
+	major, minor, ok := strings.Cut(s, ":")
+	if !ok {
+		return DeviceNumber{}, fmt.Errorf("invalid device number %q, expected major:minor", s)
+	}
+	var dev DeviceNumber
+	var err error
+	if dev.Major, err = strconv.ParseInt(major, 10, 64); err != nil {
+		return DeviceNumber{}, fmt.Errorf("invalid major number in %q: %w", s, err)
+	}
+	if dev.Minor, err = strconv.ParseInt(minor, 10, 64); err != nil {
+		return DeviceNumber{}, fmt.Errorf("invalid minor number in %q: %w", s, err)
+	}
+	return dev, nil
+}
+
+func (opt DeviceNumber) String() string {
+	return fmt.Sprintf("%d:%d", opt.Major, opt.Minor)
+}
+
+func (opt DeviceNumber) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.DeviceNumbers = append(opts.DeviceNumbers, opt)
+}
+
+// DeviceNumbers are the device numbers of the block devices to scan with PVScan.
+type DeviceNumbers []DeviceNumber
+
+func (opt DeviceNumbers) ApplyToPVScanOptions(opts *PVScanOptions) {
+	opts.DeviceNumbers = append(opts.DeviceNumbers, opt...)
+}
+
+func (opt DeviceNumbers) ApplyToArgs(args Arguments) error {
+	for _, dev := range opt {
+		args.AddOrReplaceAll([]string{dev.String()})
+	}
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = PVScanOptionsList{}
+	_ Argument          = (*PVScanOptions)(nil)
+)
+
+func (c *client) PVScan(ctx context.Context, opts ...PVScanOption) error {
+	args, err := PVScanOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"pvscan"}, args.GetRaw()...)...)
+}
+
+func (list PVScanOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := PVScanOptions{}
+	for _, opt := range list {
+		opt.ApplyToPVScanOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *PVScanOptions) ApplyToPVScanOptions(new *PVScanOptions) {
+	*new = *opts
+}
+
+func (opts *PVScanOptions) ApplyToArgs(args Arguments) error {
+	if opts.ActivationState != "" && opts.ActivationState != AutoActivate {
+		return fmt.Errorf("pvscan only supports autoactivation, got activation state %q", opts.ActivationState)
+	}
+
+	for _, arg := range []Argument{
+		opts.Cache,
+		opts.ActivationState,
+		opts.PhysicalVolumeNames,
+		opts.DeviceNumbers,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
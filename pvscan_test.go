@@ -0,0 +1,105 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestPVScan(t *testing.T) {
+	t.Parallel()
+
+	dev, err := ParseDeviceNumber("8:16")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dev != (DeviceNumber{Major: 8, Minor: 16}) {
+		t.Fatalf("unexpected device number %v", dev)
+	}
+	for _, invalid := range []string{"8", "a:16", "8:b"} {
+		if _, err := ParseDeviceNumber(invalid); err == nil {
+			t.Fatalf("expected error for %q", invalid)
+		}
+	}
+
+	args, err := (PVScanOptionsList{Cache(true), AutoActivate, dev}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--cache", "--activate", "ay", "8:16", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+	if _, err := (PVScanOptionsList{Activate}).AsArgs(); err == nil {
+		t.Fatal("expected error for activation other than autoactivation")
+	}
+
+	args, err = (VGScanOptionsList{Mknodes(true)}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--mknodes", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+	args, err = (LVScanOptionsList{All(true)}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--all", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+}
+
+func TestPVScan_AutoActivation(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	if err := clnt.SetDevice("/dev/sdb", MustParseSize("101M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("8M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := clnt.PVScan(ctx, Cache(true), PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if lv, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv")); err != nil {
+		t.Fatal(err)
+	} else if lv.Attr.State == StateActive {
+		t.Fatal("expected logical volume to stay inactive without autoactivation")
+	}
+
+	if err := clnt.PVScan(ctx, Cache(true), AutoActivate, PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if lv, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv")); err != nil {
+		t.Fatal(err)
+	} else if lv.Attr.State != StateActive {
+		t.Fatal("expected logical volume to be autoactivated")
+	}
+}
@@ -0,0 +1,158 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+// MinRecoveryRate guarantees the synchronization of raid logical volumes a minimum rate per device and second,
+// so that it completes even while applications keep the devices busy. See MaxRecoveryRate for the upper bound.
+type MinRecoveryRate Size
+
+func (opt MinRecoveryRate) ApplyToArgs(args Arguments) error {
+	if opt.Val == 0 {
+		return nil
+	}
+	return Size(opt).applyToArgs(minRecoveryRateArg, args)
+}
+
+func (opt MinRecoveryRate) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.MinRecoveryRate = opt
+}
+
+func (opt MinRecoveryRate) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.MinRecoveryRate = opt
+}
+
+// validateRecoveryRates returns an error if the minimum recovery rate exceeds the maximum recovery rate.
+func validateRecoveryRates(min MinRecoveryRate, max MaxRecoveryRate) error {
+	if min.Val == 0 || max.Val == 0 {
+		return nil
+	}
+	res, err := Size(min).Cmp(Size(max))
+	if err != nil {
+		return err
+	}
+	if res > 0 {
+		return fmt.Errorf("MinRecoveryRate %s must not exceed MaxRecoveryRate %s", Size(min), Size(max))
+	}
+	return nil
+}
+
+// WriteMostlyMode determines how LVChange changes the write-mostly flag of a raid1 image.
+type WriteMostlyMode string
+
+const (
+	// WriteMostlySet marks the image as write-mostly, so that reads avoid it.
+	WriteMostlySet WriteMostlyMode = "y"
+	// WriteMostlyClear removes the write-mostly flag from the image.
+	WriteMostlyClear WriteMostlyMode = "n"
+	// WriteMostlyToggle inverts the write-mostly flag of the image.
+	WriteMostlyToggle WriteMostlyMode = "t"
+)
+
+// WriteMostlyDevice changes the write-mostly flag of the raid1 image on the physical volume.
+type WriteMostlyDevice struct {
+	PhysicalVolumeName
+	Mode WriteMostlyMode
+}
+
+// WriteMostly changes the write-mostly flags of raid1 images with LVChange, e.g. to keep reads away
+// from a slow or remote leg of the mirror. The health of a write-mostly image is reported as ErrRAIDWriteMostly.
+type WriteMostly []WriteMostlyDevice
+
+func (opt WriteMostly) ApplyToArgs(args Arguments) error {
+	for _, device := range opt {
+		if device.PhysicalVolumeName == "" {
+			return fmt.Errorf("WriteMostly requires a PhysicalVolumeName")
+		}
+		switch device.Mode {
+		case "":
+			args.AddOrReplace(fmt.Sprintf("--writemostly=%s", device.PhysicalVolumeName))
+		case WriteMostlySet, WriteMostlyClear, WriteMostlyToggle:
+			args.AddOrReplace(fmt.Sprintf("--writemostly=%s:%s", device.PhysicalVolumeName, device.Mode))
+		default:
+			return fmt.Errorf("invalid write-mostly mode %q for %s", device.Mode, device.PhysicalVolumeName)
+		}
+	}
+	return nil
+}
+
+func (opt WriteMostly) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.WriteMostly = append(opts.WriteMostly, opt...)
+}
+
+// WriteBehind is the number of outstanding writes that raid1 images marked with WriteMostly may lag behind
+// the other images. Use NoWriteBehind to make writes to them synchronous again.
+type WriteBehind int
+
+// NoWriteBehind disables the write-behind of write-mostly images, as a zero WriteBehind is not passed.
+const NoWriteBehind WriteBehind = -1
+
+// MaxWriteBehind is the largest WriteBehind accepted by lvm2.
+const MaxWriteBehind WriteBehind = 16383
+
+func (opt WriteBehind) ApplyToArgs(args Arguments) error {
+	switch {
+	case opt == 0:
+		return nil
+	case opt == NoWriteBehind:
+		args.AddOrReplace("--writebehind=0")
+	case opt < 0 || opt > MaxWriteBehind:
+		return fmt.Errorf("WriteBehind must be between 0 and %d, got %d", MaxWriteBehind, opt)
+	default:
+		args.AddOrReplace(fmt.Sprintf("--writebehind=%d", opt))
+	}
+	return nil
+}
+
+func (opt WriteBehind) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.WriteBehind = opt
+}
+
+// MirrorLog determines where a logical volume of TypeMirrored keeps track of the synchronized regions.
+type MirrorLog string
+
+const (
+	// MirrorLogDisk keeps the log on a separate physical volume, so the mirror does not resynchronize after a restart.
+	MirrorLogDisk MirrorLog = "disk"
+	// MirrorLogCore keeps the log in memory, so the mirror resynchronizes completely after every activation.
+	MirrorLogCore MirrorLog = "core"
+	// MirrorLogMirrored keeps a mirrored log on two separate physical volumes.
+	MirrorLogMirrored MirrorLog = "mirrored"
+)
+
+func (opt MirrorLog) ApplyToArgs(args Arguments) error {
+	switch opt {
+	case "":
+		return nil
+	case MirrorLogDisk, MirrorLogCore, MirrorLogMirrored:
+		args.AddOrReplace(fmt.Sprintf("--mirrorlog=%s", opt))
+		return nil
+	default:
+		return fmt.Errorf("invalid mirror log %q", string(opt))
+	}
+}
+
+func (opt MirrorLog) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.MirrorLog = opt
+}
+
+func (opt MirrorLog) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.MirrorLog = opt
+}
@@ -0,0 +1,98 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestRaidTuning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	vgName := VolumeGroupName("vg")
+
+	args, err := LVChangeOptionsList{
+		vgName, LogicalVolumeName("raid"),
+		MinRecoveryRate(MustParseSize("10M")), MaxRecoveryRate(MustParseSize("100M")),
+		WriteMostly{{PhysicalVolumeName: "/dev/sdb"}, {PhysicalVolumeName: "/dev/sdc", Mode: WriteMostlyClear}},
+		WriteBehind(512),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{
+		"--minrecoveryrate=10.00m", "--maxrecoveryrate=100.00m",
+		"--writemostly=/dev/sdb", "--writemostly=/dev/sdc:n", "--writebehind=512",
+	} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+
+	args, err = LVChangeOptionsList{vgName, LogicalVolumeName("raid"), NoWriteBehind}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--writebehind=0") {
+		t.Fatalf("expected write-behind to be disabled in %v", args.GetRaw())
+	}
+
+	for name, list := range map[string]LVChangeOptionsList{
+		"min above max":     {vgName, LogicalVolumeName("raid"), MinRecoveryRate(MustParseSize("1G")), MaxRecoveryRate(MustParseSize("100M"))},
+		"write-behind":      {vgName, LogicalVolumeName("raid"), MaxWriteBehind + 1},
+		"write-mostly mode": {vgName, LogicalVolumeName("raid"), WriteMostly{{PhysicalVolumeName: "/dev/sdb", Mode: "x"}}},
+	} {
+		if _, err := list.AsArgs(); err == nil {
+			t.Fatalf("expected an error for %s", name)
+		}
+	}
+
+	args, err = LVCreateOptionList{
+		vgName, LogicalVolumeName("mirror"), MustParseSize("1G"), TypeMirrored, Mirrors(1), MirrorLogCore,
+		MaxRecoveryRate(MustParseSize("50M")),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--mirrorlog=core") || !strings.Contains(raw, "--maxrecoveryrate=50.00m") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+	if _, err := (LVCreateOptionList{vgName, LogicalVolumeName("raid"), MustParseSize("1G"), TypeRAID1, MirrorLogDisk}).AsArgs(); err == nil {
+		t.Fatal("expected MirrorLog to require a mirrored type")
+	}
+	if _, err := (LVConvertOptionsList{vgName, LogicalVolumeName("mirror"), MirrorLogDisk}).AsArgs(); err != nil {
+		t.Fatalf("expected a change of the mirror log to be a conversion, got %v", err)
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("100M"))
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, vgName, LogicalVolumeName("linear"), MustParseSize("40M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVChange(ctx, vgName, LogicalVolumeName("linear"), WriteBehind(100)); err == nil {
+		t.Fatal("expected write-behind to be rejected for a linear logical volume")
+	}
+}
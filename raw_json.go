@@ -0,0 +1,67 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"encoding/json"
+	"slices"
+)
+
+// RetainRawJSON keeps the raw JSON of every object of a report, so that report fields which are not
+// covered by LogicalVolume, VolumeGroup or PhysicalVolume yet can be read without running lvm again:
+//
+//	lvs, err := client.LVs(ctx, ColumnOptions{"lv_all", "seg_all"}, RetainRawJSON(true))
+//	readAhead, ok := lvs[0].RawJSON().Field("lv_read_ahead")
+//
+// Only clients that decode the JSON reports of lvm support RetainRawJSON, such as the client returned by NewClient.
+type RetainRawJSON bool
+
+func (opt RetainRawJSON) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.RetainRawJSON = opt
+}
+
+func (opt RetainRawJSON) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.RetainRawJSON = opt
+}
+
+func (opt RetainRawJSON) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.RetainRawJSON = opt
+}
+
+// RawJSON is the raw JSON of an object of a lvm report. It is only parsed on access.
+type RawJSON json.RawMessage
+
+// Field returns the value of the report field name, e.g. "lv_read_ahead".
+// lvm reports every value as a string. If the field is not part of the report, ok is false.
+func (raw RawJSON) Field(name string) (value string, ok bool) {
+	var fields map[string]string
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return "", false
+	}
+	value, ok = fields[name]
+	return value, ok
+}
+
+// Unmarshal decodes the raw JSON into v, e.g. into a struct with the fields the caller is interested in.
+func (raw RawJSON) Unmarshal(v any) error {
+	return json.Unmarshal(raw, v)
+}
+
+// rawJSON copies data, as json.Unmarshaler implementations must not retain the data they are passed.
+func rawJSON(data []byte) RawJSON {
+	return RawJSON(slices.Clone(data))
+}
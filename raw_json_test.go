@@ -0,0 +1,66 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestRetainRawJSON(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"lv","vg_name":"vg","lv_read_ahead":"auto"}]}]}`)), nil
+	}))
+
+	lvs, err := clnt.LVs(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != 1 || lvs[0].RawJSON() != nil {
+		t.Fatalf("expected no raw JSON without RetainRawJSON, got %v", lvs)
+	}
+
+	lvs, err = clnt.LVs(ctx, RetainRawJSON(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readAhead, ok := lvs[0].RawJSON().Field("lv_read_ahead"); !ok || readAhead != "auto" {
+		t.Fatalf("expected lv_read_ahead auto, got %q", readAhead)
+	}
+	if _, ok := lvs[0].RawJSON().Field("lv_missing"); ok {
+		t.Fatal("expected missing field")
+	}
+	var fields struct {
+		ReadAhead string `json:"lv_read_ahead"`
+	}
+	if err := lvs[0].RawJSON().Unmarshal(&fields); err != nil || fields.ReadAhead != "auto" {
+		t.Fatalf("expected lv_read_ahead auto, got %q: %v", fields.ReadAhead, err)
+	}
+
+	if _, err := fake.NewClient().LVs(ctx, RetainRawJSON(true)); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected unsupported, got %v", err)
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ReadAhead sets the read ahead of a logical volume with --readahead, either ReadAheadAuto,
+// ReadAheadNone or a number of 512 byte sectors created with ReadAheadSectors.
+type ReadAhead string
+
+const (
+	// ReadAheadAuto lets the kernel choose the read ahead, which takes the stripes of the logical volume into account.
+	ReadAheadAuto ReadAhead = "auto"
+	// ReadAheadNone disables the read ahead.
+	ReadAheadNone ReadAhead = "none"
+)
+
+// ReadAheadSectors returns the read ahead of the given number of 512 byte sectors,
+// which lvm2 rounds to a multiple of 8 sectors.
+func ReadAheadSectors(sectors uint64) ReadAhead {
+	return ReadAhead(strconv.FormatUint(sectors, 10))
+}
+
+func (opt ReadAhead) ApplyToArgs(args Arguments) error {
+	switch opt {
+	case "":
+		return nil
+	case ReadAheadAuto, ReadAheadNone:
+	default:
+		if _, err := strconv.ParseUint(string(opt), 10, 64); err != nil {
+			return fmt.Errorf("invalid read ahead %q, expected auto, none or a number of sectors", string(opt))
+		}
+	}
+	args.AddOrReplace(fmt.Sprintf("--readahead=%s", string(opt)))
+	return nil
+}
+
+func (opt ReadAhead) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.ReadAhead = opt
+}
+
+func (opt ReadAhead) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.ReadAhead = opt
+}
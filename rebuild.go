@@ -16,15 +16,25 @@
 
 package lvm2go
 
-type Rebuild bool
+import (
+	"fmt"
+)
+
+// Rebuild reconstructs the raid images on the physical volumes with LVChange from the remaining images,
+// e.g. after a device came back from a transient failure with stale data.
+// Unlike Resync, only the images on the given physical volumes are rewritten.
+type Rebuild PhysicalVolumeNames
 
 func (opt Rebuild) ApplyToArgs(args Arguments) error {
-	if opt {
-		args.AddOrReplaceAll([]string{"--rebuild"})
+	for _, name := range opt {
+		if name == "" {
+			return fmt.Errorf("Rebuild requires a PhysicalVolumeName")
+		}
+		args.AddOrReplace(fmt.Sprintf("--rebuild=%s", name))
 	}
 	return nil
 }
 
 func (opt Rebuild) ApplyToLVChangeOptions(opts *LVChangeOptions) {
-	opts.Rebuild = opt
+	opts.Rebuild = append(opts.Rebuild, opt...)
 }
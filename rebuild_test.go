@@ -0,0 +1,46 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestRebuild(t *testing.T) {
+	t.Parallel()
+	lv := []LVChangeOption{VolumeGroupName("vg"), LogicalVolumeName("raid")}
+
+	args, err := LVChangeOptionsList(append(lv, Rebuild{"/dev/sdb"}, Rebuild{"/dev/sdc"})).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--rebuild=/dev/sdb", "--rebuild=/dev/sdc"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+
+	if _, err := LVChangeOptionsList(append(lv, Rebuild{"/dev/sdb"}, Resync(true))).AsArgs(); err == nil {
+		t.Fatal("expected Rebuild and Resync to be mutually exclusive")
+	}
+	if _, err := LVChangeOptionsList(append(lv, Rebuild{""})).AsArgs(); err == nil {
+		t.Fatal("expected an error for an empty physical volume name")
+	}
+}
@@ -0,0 +1,185 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ErrNoRecording is returned by a replaying client if no recording matches the command.
+var ErrNoRecording = errors.New("no recording found for command")
+
+// CommandRecording is a single command recorded by RecordingCommandRunner.
+// Recordings are stored as one JSON object per line.
+type CommandRecording struct {
+	// Command is the base name of the executed binary, e.g. lvm, independent of the configured LVMPath.
+	Command string `json:"command"`
+	// Args are the arguments of the command without the prefix of the ExecutionStrategy, e.g. nsenter.
+	Args     []string `json:"args"`
+	Stdout   string   `json:"stdout,omitempty"`
+	Stderr   string   `json:"stderr,omitempty"`
+	ExitCode int      `json:"exitCode"`
+}
+
+// NewRecordingClient returns a Client that executes commands on the system and records
+// every command with its output and exit code to w.
+// The recording can be replayed with NewReplayClient without touching the system,
+// e.g. to capture the behavior of lvm once on a development machine and replay it in CI.
+//
+// Example:
+//
+//	f, _ := os.Create("testdata/recording.jsonl")
+//	defer f.Close()
+//	client := lvm2go.NewRecordingClient(f)
+func NewRecordingClient(w io.Writer, opts ...ClientOption) Client {
+	return NewClient(append(opts, RecordingCommandRunner(w))...)
+}
+
+// NewReplayClient returns a Client that serves the commands recorded by NewRecordingClient from r
+// instead of executing them. Commands without a matching recording fail with ErrNoRecording.
+func NewReplayClient(r io.Reader, opts ...ClientOption) (Client, error) {
+	recordings, err := ReadCommandRecordings(r)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(append(opts, ReplayCommandRunner(recordings))...), nil
+}
+
+// ReadCommandRecordings reads the recordings written by RecordingCommandRunner.
+func ReadCommandRecordings(r io.Reader) ([]CommandRecording, error) {
+	var recordings []CommandRecording
+	decoder := json.NewDecoder(r)
+	for {
+		var recording CommandRecording
+		if err := decoder.Decode(&recording); errors.Is(err, io.EOF) {
+			return recordings, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to read recording %d: %w", len(recordings)+1, err)
+		}
+		recordings = append(recordings, recording)
+	}
+}
+
+// RecordingCommandRunner is a CommandRunner that runs the command to completion
+// and writes a CommandRecording of it to w before returning its output.
+// Commands that cannot be started are not recorded.
+func RecordingCommandRunner(w io.Writer) CommandRunner {
+	var mu sync.Mutex
+	encoder := json.NewEncoder(w)
+
+	return func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		var stdout, stderr bytes.Buffer
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+
+		err := cmd.Run()
+		var exitErr *exec.ExitError
+		if err != nil && !errors.As(err, &exitErr) {
+			return nil, err
+		}
+
+		recording := newCommandRecording(ctx, cmd)
+		recording.Stdout, recording.Stderr = stdout.String(), stderr.String()
+		if exitErr != nil {
+			recording.ExitCode = exitErr.ExitCode()
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if err := encoder.Encode(recording); err != nil {
+			return nil, fmt.Errorf("failed to record command: %w", err)
+		}
+
+		return recording.output(), nil
+	}
+}
+
+// ReplayCommandRunner is a CommandRunner that serves the output of the given recordings
+// instead of running the command. Every recording is served once, in the order of the recordings,
+// to the first command with the same binary and arguments.
+func ReplayCommandRunner(recordings []CommandRecording) CommandRunner {
+	var mu sync.Mutex
+	used := make([]bool, len(recordings))
+
+	return func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		command := newCommandRecording(ctx, cmd)
+
+		mu.Lock()
+		defer mu.Unlock()
+		for i, recording := range recordings {
+			if used[i] || recording.Command != command.Command || !slices.Equal(recording.Args, command.Args) {
+				continue
+			}
+			used[i] = true
+			return recording.output(), nil
+		}
+		return nil, fmt.Errorf("%w: %s %s", ErrNoRecording, command.Command, strings.Join(command.Args, " "))
+	}
+}
+
+// newCommandRecording creates a recording for the binary and arguments of cmd run with ctx,
+// stripping the prefix of the ExecutionStrategy that wrapped the command, e.g. nsenter or chroot,
+// so that recordings are portable between environments.
+func newCommandRecording(ctx context.Context, cmd *exec.Cmd) CommandRecording {
+	args := unwrapArgs(commandExecutionStrategy(ctx), cmd.Args)
+	if len(args) == 0 {
+		return CommandRecording{Command: filepath.Base(cmd.Path)}
+	}
+	return CommandRecording{Command: filepath.Base(args[0]), Args: slices.Clone(args[1:])}
+}
+
+// output returns the recorded stdout as a ReadCloser that returns the recorded stderr and exit code on Close,
+// like the ReadCloser returned by StreamedCommand.
+func (r CommandRecording) output() io.ReadCloser {
+	var err error
+	if r.ExitCode != 0 {
+		err = NewExitCodeError(recordedExitError(r.ExitCode))
+	}
+	return &recordedOutput{
+		Reader: strings.NewReader(r.Stdout),
+		err:    errors.Join(NewLVMStdErr([]byte(r.Stderr)), err),
+	}
+}
+
+type recordedOutput struct {
+	io.Reader
+	err error
+}
+
+func (o *recordedOutput) Close() error {
+	return o.err
+}
+
+// recordedExitError is the replayed counterpart of exec.ExitError.
+type recordedExitError int
+
+func (e recordedExitError) Error() string {
+	return fmt.Sprintf("exit status %d", int(e))
+}
+
+func (e recordedExitError) ExitCode() int {
+	return int(e)
+}
@@ -0,0 +1,123 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+// fakeLVMScript answers vgs with a report and fails every other command like a missing volume group.
+const fakeLVMScript = `#!/bin/sh
+if [ "$1" = "vgs" ]; then
+	echo '{"report":[{"vg":[{"vg_name":"vg","vg_attr":"wz--n-","vg_size":"1073741824B"}]}]}'
+	exit 0
+fi
+echo '  Volume group "missing" not found' >&2
+exit 5
+`
+
+func TestRecordAndReplay(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	lvm := filepath.Join(t.TempDir(), "lvm")
+	if err := os.WriteFile(lvm, []byte(fakeLVMScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	var recording bytes.Buffer
+	assert := func(t *testing.T, clnt Client) {
+		t.Helper()
+		vgs, err := clnt.VGs(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(vgs) != 1 || vgs[0].Name != "vg" {
+			t.Fatalf("unexpected vgs: %v", vgs)
+		}
+		err = clnt.VGRemove(ctx, VolumeGroupName("missing"))
+		if !IsVolumeGroupNotFound(err) {
+			t.Fatalf("expected volume group not found, got %v", err)
+		}
+		if exitErr, ok := AsExitCodeError(err); !ok || exitErr.ExitCode() != 5 {
+			t.Fatalf("expected exit code 5, got %v", err)
+		}
+	}
+
+	assert(t, NewRecordingClient(&recording, LVMPath(lvm), NsenterPolicyNever))
+
+	recordings, err := ReadCommandRecordings(bytes.NewReader(recording.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(recordings) != 2 || recordings[0].Command != "lvm" || recordings[1].ExitCode != 5 {
+		t.Fatalf("unexpected recordings: %v", recordings)
+	}
+
+	// the replay does not depend on the recorded binary
+	replay, err := NewReplayClient(bytes.NewReader(recording.Bytes()), LVMPath("/nonexistent/lvm"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert(t, replay)
+
+	// every recording is served only once
+	if _, err := replay.VGs(ctx); !errors.Is(err, ErrNoRecording) {
+		t.Fatalf("expected no recording, got %v", err)
+	}
+}
+
+func TestReplayIsIndependentOfExecutionStrategy(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	if _, err := exec.LookPath("chroot"); err != nil {
+		t.Skip("chroot is not available")
+	}
+	// the host root only needs an executable lvm for the probe of the chroot strategy,
+	// the replay does not run the command
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "lvm"), []byte(fakeLVMScript), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	recording := `{"command":"lvm","args":["vgremove","missing","--yes"],"exitCode":5,"stderr":"  Volume group \"missing\" not found\n"}`
+	for name, opts := range map[string][]ClientOption{
+		"chroot":  {LVMPath("/lvm"), ExecutionStrategies{ChrootStrategy{Root: root}}},
+		"nsenter": {NsenterPolicyAlways},
+		"direct":  {NsenterPolicyNever},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			replay, err := NewReplayClient(bytes.NewReader([]byte(recording)), opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := replay.VGRemove(ctx, VolumeGroupName("missing")); !IsVolumeGroupNotFound(err) {
+				t.Fatalf("expected the recorded error, got %v", err)
+			}
+		})
+	}
+}
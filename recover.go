@@ -0,0 +1,216 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+type (
+	// RecoverNodeOptions select the volume groups checked by RecoverNode.
+	// Without VolumeGroupNames, all volume groups are checked.
+	RecoverNodeOptions struct {
+		VolumeGroupNames
+		RecoverDryRun
+		ProgressInterval
+	}
+	RecoverNodeOption interface {
+		ApplyToRecoverNodeOptions(opts *RecoverNodeOptions)
+	}
+	RecoverNodeOptionsList []RecoverNodeOption
+)
+
+// RecoverDryRun only reports the findings of RecoverNode without fixing them.
+type RecoverDryRun bool
+
+func (opt RecoverDryRun) ApplyToRecoverNodeOptions(opts *RecoverNodeOptions) {
+	opts.RecoverDryRun = opt
+}
+
+func (opts *RecoverNodeOptions) ApplyToRecoverNodeOptions(new *RecoverNodeOptions) {
+	*new = *opts
+}
+
+// RecoveryIssue is the kind of leftover of an unclean shutdown found by RecoverNode.
+type RecoveryIssue string
+
+const (
+	// RecoveryIssueInterruptedOperation is a pvmove or conversion that is not progressing, see InterruptedOperations.
+	// It is fixed by resuming the polling of the volume group.
+	RecoveryIssueInterruptedOperation RecoveryIssue = "interrupted operation"
+	// RecoveryIssueUnmergedSnapshot is an origin with a snapshot that is not merged yet.
+	// It is fixed by activating the origin, which starts the merge, and resuming the polling of the volume group.
+	RecoveryIssueUnmergedSnapshot RecoveryIssue = "unmerged snapshot"
+	// RecoveryIssueUnmonitoredThinPool is an active thin pool that is not monitored by dmeventd,
+	// so it is not extended automatically. It is fixed by starting monitoring.
+	RecoveryIssueUnmonitoredThinPool RecoveryIssue = "unmonitored thin pool"
+	// RecoveryIssueInactiveAutoActivation is an inactive logical volume with autoactivation enabled for itself
+	// and its volume group, which was not activated during boot. It is fixed by autoactivating it.
+	RecoveryIssueInactiveAutoActivation RecoveryIssue = "inactive autoactivated volume"
+)
+
+// RecoveryFinding is an issue of a logical volume found by RecoverNode.
+type RecoveryFinding struct {
+	LogicalVolume *FQLogicalVolumeName
+	Issue         RecoveryIssue
+	// Fixed is true if the issue was fixed. With RecoverDryRun, issues are only reported.
+	Fixed bool
+	// Err is the error of the attempt to fix the issue, or of the attempt to determine whether there is one.
+	Err error
+}
+
+// RecoveryReport is the result of RecoverNode.
+type RecoveryReport struct {
+	Findings []*RecoveryFinding
+}
+
+// Err returns the errors of all findings that could not be fixed joined into a single error,
+// or nil if all fixes succeeded.
+func (r *RecoveryReport) Err() error {
+	var errs []error
+	for _, finding := range r.Findings {
+		if finding.Err != nil {
+			errs = append(errs, fmt.Errorf("failed to fix %s of %s: %w", finding.Issue, finding.LogicalVolume, finding.Err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (r *RecoveryReport) add(lv *LogicalVolume, issue RecoveryIssue) *RecoveryFinding {
+	finding := &RecoveryFinding{
+		LogicalVolume: &FQLogicalVolumeName{VolumeGroupName: lv.VolumeGroupName, LogicalVolumeName: lv.Name},
+		Issue:         issue,
+	}
+	r.Findings = append(r.Findings, finding)
+	return finding
+}
+
+// RecoverNode reconciles the logical volumes of the node after an unclean shutdown, e.g. when an agent starts up.
+// It finds interrupted pvmoves and conversions, snapshots that are not merged yet, active thin pools that are not
+// monitored and inactive logical volumes that should have been autoactivated, and fixes them unless RecoverDryRun
+// is given. Active pvmoves and conversions are only checked for progress with a ProgressInterval, see InterruptedOperations.
+// Every issue is reported as a RecoveryFinding. Issues that cannot be fixed do not stop the recovery of
+// the others, their errors are returned together as error of the report.
+func RecoverNode(ctx context.Context, client Client, opts ...RecoverNodeOption) (*RecoveryReport, error) {
+	options := RecoverNodeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToRecoverNodeOptions(&options)
+	}
+
+	vgs, err := client.VGs(ctx, options.VolumeGroupNames)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list volume groups to recover: %w", err)
+	}
+	autoActivated := map[VolumeGroupName]bool{}
+	for _, vg := range vgs {
+		autoActivated[vg.Name] = vg.AutoActivation.True()
+	}
+
+	// hidden logical volumes are included, as the temporary volume of an interrupted pvmove is hidden
+	lvs, err := client.LVs(ctx, options.VolumeGroupNames, All(true))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list logical volumes to recover: %w", err)
+	}
+	stalled, err := stalledOperations(ctx, client, lvs, options.ProgressInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RecoveryReport{}
+	activate := map[*RecoveryFinding]ActivationState{}
+	poll := map[VolumeGroupName][]*RecoveryFinding{}
+	var monitor []*RecoveryFinding
+	for _, lv := range lvs {
+		switch {
+		case lv.Attr.IsInterrupted() || stalled[lv]:
+			finding := report.add(lv, RecoveryIssueInterruptedOperation)
+			poll[lv.VolumeGroupName] = append(poll[lv.VolumeGroupName], finding)
+		case lv.Attr.VolumeType == VolumeTypeOriginWithMergingSnapshot:
+			finding := report.add(lv, RecoveryIssueUnmergedSnapshot)
+			if lv.Attr.State == StateNone {
+				activate[finding] = Activate
+			}
+			poll[lv.VolumeGroupName] = append(poll[lv.VolumeGroupName], finding)
+		case lv.Attr.VolumeType == VolumeTypeThinPool && lv.Attr.State == StateActive:
+			status, err := segmentMonitor(ctx, client, lv)
+			if err != nil {
+				// the thin pool is reported as unmonitored with the error, so that the other issues are still fixed
+				report.add(lv, RecoveryIssueUnmonitoredThinPool).Err = err
+			} else if status == SegmentNotMonitored || status == SegmentMonitorFail {
+				monitor = append(monitor, report.add(lv, RecoveryIssueUnmonitoredThinPool))
+			}
+		case lv.Attr.State == StateNone && isAutoActivated(lv, autoActivated[lv.VolumeGroupName]):
+			activate[report.add(lv, RecoveryIssueInactiveAutoActivation)] = AutoActivate
+		}
+	}
+
+	if options.RecoverDryRun {
+		return report, report.Err()
+	}
+
+	// activations start the merge of snapshots, which is then resumed together with the other polled operations
+	for _, finding := range report.Findings {
+		state, ok := activate[finding]
+		if !ok {
+			continue
+		}
+		finding.Err = client.LVChange(ctx, finding.LogicalVolume.VolumeGroupName, finding.LogicalVolume.LogicalVolumeName, state)
+		finding.Fixed = finding.Err == nil && finding.Issue != RecoveryIssueUnmergedSnapshot
+	}
+	for vg, findings := range poll {
+		err := ResumeInterruptedOperations(ctx, client, vg)
+		for _, finding := range findings {
+			if finding.Err == nil {
+				finding.Err, finding.Fixed = err, err == nil
+			}
+		}
+	}
+	for _, finding := range monitor {
+		finding.Err = client.LVChange(ctx, finding.LogicalVolume.VolumeGroupName, finding.LogicalVolume.LogicalVolumeName, MonitorStart)
+		finding.Fixed = finding.Err == nil
+	}
+
+	return report, report.Err()
+}
+
+// isAutoActivated reports whether the logical volume is activated by autoactivation.
+// Snapshots, transient and internal volumes are activated together with their origins, operations
+// and top-level volumes instead.
+func isAutoActivated(lv *LogicalVolume, vgAutoActivated bool) bool {
+	if !vgAutoActivated || lv.Internal != "" || !lv.AutoActivation.True() || lv.Attr.SkipActivation == SkipActivationTrue || lv.Attr.IsTransient() {
+		return false
+	}
+	return !slices.Contains([]VolumeType{VolumeTypeSnapshot, VolumeTypeMergingSnapshot}, lv.Attr.VolumeType)
+}
+
+// segmentMonitor reports the monitoring status of the logical volume by dmeventd.
+// It is empty if the status is not reported, e.g. if monitoring is not supported.
+func segmentMonitor(ctx context.Context, client Client, lv *LogicalVolume) (SegmentMonitor, error) {
+	reported, err := client.LVs(ctx, lv.VolumeGroupName, lv.Name, ColumnOptions{"vg_name", "lv_name"}, ExtraColumns{"seg_monitor"})
+	if err != nil {
+		return "", fmt.Errorf("failed to get monitoring status of %s: %w", lv.FullName, err)
+	}
+	for _, r := range reported {
+		if status, ok := r.ExtraFields.Get("seg_monitor"); ok {
+			return SegmentMonitor(status), nil
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,195 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestRecoverNode(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	for _, device := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := clnt.SetDevice(device, MustParseSize("1G")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("manual"), PhysicalVolumesFrom("/dev/sdc"), SetNoAutoActivate); err != nil {
+		t.Fatal(err)
+	}
+	for _, lv := range []struct {
+		vg   VolumeGroupName
+		name LogicalVolumeName
+		opts []LVChangeOption
+	}{
+		{vg: "vg", name: "active"},
+		{vg: "vg", name: "inactive", opts: []LVChangeOption{Deactivate}},
+		{vg: "vg", name: "manual", opts: []LVChangeOption{Deactivate, SetNoAutoActivate}},
+		{vg: "manual", name: "inactive", opts: []LVChangeOption{Deactivate}},
+	} {
+		if err := clnt.LVCreate(ctx, lv.vg, lv.name, MustParseSize("64M")); err != nil {
+			t.Fatal(err)
+		}
+		if len(lv.opts) > 0 {
+			if err := clnt.LVChange(ctx, append([]LVChangeOption{lv.vg, lv.name}, lv.opts...)...); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	report, err := RecoverNode(ctx, clnt, RecoverDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 1 {
+		t.Fatalf("expected a single finding, got %+v", report.Findings)
+	}
+	finding := report.Findings[0]
+	if finding.LogicalVolume.String() != "vg/inactive" || finding.Issue != RecoveryIssueInactiveAutoActivation || finding.Fixed {
+		t.Fatalf("unexpected finding %+v", finding)
+	}
+
+	if report, err = RecoverNode(ctx, clnt, VolumeGroupNames{"vg"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Findings) != 1 || !report.Findings[0].Fixed {
+		t.Fatalf("expected the finding to be fixed, got %+v", report.Findings)
+	}
+	lv, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("inactive"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lv.Attr.State != StateActive {
+		t.Fatalf("expected vg/inactive to be active, got %c", lv.Attr.State)
+	}
+
+	if report, err = RecoverNode(ctx, clnt); err != nil || len(report.Findings) != 0 {
+		t.Fatalf("expected nothing to recover, got %+v: %v", report, err)
+	}
+}
+
+func TestRecoverNodeInterruptedPVMove(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var lvsArgs, vgchangeArgs []string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		switch {
+		case slices.Contains(cmd.Args, "vgs"):
+			return io.NopCloser(strings.NewReader(`{"report":[{"vg":[
+				{"vg_name":"vg", "vg_attr":"wz--n-", "vg_autoactivation":"enabled"}
+			]}]}`)), nil
+		case slices.Contains(cmd.Args, "lvs"):
+			lvsArgs = cmd.Args
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[
+				{"lv_name":"lv", "vg_name":"vg", "lv_attr":"-wI-ao----", "lv_autoactivation":"enabled"},
+				{"lv_name":"[pvmove0]", "vg_name":"vg", "lv_attr":"pwC---m---", "move_pv":"/dev/sdb", "lv_autoactivation":"enabled"},
+				{"lv_name":"[pvmove1]", "vg_name":"vg", "lv_attr":"pwC-ao----", "move_pv":"/dev/sdc", "copy_percent":"12.50", "lv_autoactivation":"enabled"}
+			]}]}`)), nil
+		case slices.Contains(cmd.Args, "dmsetup"):
+			return io.NopCloser(strings.NewReader("0 8192 mirror 2 253:1 253:2 512/4096 1 AA 3 disk 253:0 A")), nil
+		case slices.Contains(cmd.Args, "vgchange"):
+			vgchangeArgs = cmd.Args
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+
+	report, err := RecoverNode(ctx, clnt, ProgressInterval(10*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(lvsArgs, "--all") {
+		t.Fatalf("expected hidden logical volumes to be reported, got %v", lvsArgs)
+	}
+	// the active pvmove does not progress while it is observed, as it is not polled
+	if len(report.Findings) != 2 {
+		t.Fatalf("expected two findings, got %+v", report.Findings)
+	}
+	for i, name := range []string{"vg/[pvmove0]", "vg/[pvmove1]"} {
+		finding := report.Findings[i]
+		if finding.LogicalVolume.String() != name || finding.Issue != RecoveryIssueInterruptedOperation || !finding.Fixed {
+			t.Fatalf("unexpected finding %+v", finding)
+		}
+	}
+	if !slices.Contains(vgchangeArgs, "vg") || !slices.Contains(vgchangeArgs, "--poll=y") {
+		t.Fatalf("expected polling of vg to be resumed, got %v", vgchangeArgs)
+	}
+}
+
+func TestRecoverNodeMonitoringStatusFailure(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var lvchangeArgs []string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		switch {
+		case slices.Contains(cmd.Args, "vgs"):
+			return io.NopCloser(strings.NewReader(`{"report":[{"vg":[
+				{"vg_name":"vg", "vg_attr":"wz--n-", "vg_autoactivation":"enabled"}
+			]}]}`)), nil
+		case slices.Contains(cmd.Args, "lvs") && strings.Contains(strings.Join(cmd.Args, " "), "seg_monitor"):
+			return nil, errors.New("monitoring status not available")
+		case slices.Contains(cmd.Args, "lvs"):
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[
+				{"lv_name":"pool", "vg_name":"vg", "lv_attr":"twi-aotz--", "lv_autoactivation":"enabled"},
+				{"lv_name":"lv", "vg_name":"vg", "lv_attr":"-wi-------", "lv_autoactivation":"enabled"}
+			]}]}`)), nil
+		case slices.Contains(cmd.Args, "lvchange"):
+			lvchangeArgs = cmd.Args
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+
+	report, err := RecoverNode(ctx, clnt)
+	if err == nil {
+		t.Fatal("expected the monitoring status failure to be reported")
+	}
+	if len(report.Findings) != 2 {
+		t.Fatalf("expected two findings, got %+v", report.Findings)
+	}
+	for _, finding := range report.Findings {
+		switch finding.LogicalVolume.String() {
+		case "vg/pool":
+			if finding.Issue != RecoveryIssueUnmonitoredThinPool || finding.Err == nil || finding.Fixed {
+				t.Fatalf("expected the monitoring status failure on the thin pool, got %+v", finding)
+			}
+		case "vg/lv":
+			if finding.Issue != RecoveryIssueInactiveAutoActivation || !finding.Fixed {
+				t.Fatalf("expected vg/lv to be autoactivated, got %+v", finding)
+			}
+		default:
+			t.Fatalf("unexpected finding %+v", finding)
+		}
+	}
+	if !slices.Contains(lvchangeArgs, "vg/lv") {
+		t.Fatalf("expected vg/lv to be changed, got %v", lvchangeArgs)
+	}
+}
@@ -0,0 +1,37 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// Refresh reloads the device mapper tables of active logical volumes from the metadata with LVChange or VGChange,
+// e.g. after the path of an underlying device changed, to resume suspended tables, or to bring back raid images
+// that reported ErrRAIDRefreshNeeded after a transient failure. Inactive logical volumes are not activated.
+type Refresh bool
+
+func (opt Refresh) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--refresh"})
+	}
+	return nil
+}
+
+func (opt Refresh) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.Refresh = opt
+}
+
+func (opt Refresh) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.Refresh = opt
+}
@@ -0,0 +1,64 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestRefresh(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := LVChangeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("lv"), Refresh(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--refresh") {
+		t.Fatalf("expected --refresh in %v", args.GetRaw())
+	}
+	args, err = VGChangeOptionsList{VolumeGroupName("vg"), Refresh(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--refresh") {
+		t.Fatalf("expected --refresh in %v", args.GetRaw())
+	}
+	if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), Refresh(true), Activate}).AsArgs(); err == nil {
+		t.Fatal("expected Refresh and ActivationState to be mutually exclusive")
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("100M"))
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("40M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Refresh(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Refresh(true), Deactivate); err == nil {
+		t.Fatal("expected Refresh and Deactivate to be rejected by the fake client")
+	}
+}
@@ -0,0 +1,38 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// Repair replaces failed devices of a raid or mirror logical volume, or repairs the metadata of a thin or cache pool with LVConvert.
+// Combined with UsePolicies, the configured fault policies decide how failed devices are handled.
+// With PVCheck, it rewrites the lvm2 headers and metadata of a physical volume, taking the metadata from
+// the physical volume if it is still intact or from BackupFile otherwise.
+type Repair bool
+
+func (opt Repair) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--repair"})
+	}
+	return nil
+}
+
+func (opt Repair) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Repair = opt
+}
+
+func (opt Repair) ApplyToPVCheckOptions(opts *PVCheckOptions) {
+	opts.Repair = opt
+}
@@ -0,0 +1,67 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// ReportLocking controls the locks taken by report commands such as lvs, vgs, pvs and fullreport.
+// By default, reports take shared locks on the volume groups and thus wait for metadata operations
+// that are running elsewhere on the host, e.g. a long pvmove or lvconvert.
+// Monitoring paths such as metrics collection can use ReportLockingNone or ReportLockingReadOnly
+// to never block behind such operations, at the cost of possibly inconsistent results.
+//
+// ReportLocking can be passed to a single report or as ClientOption to all reports of the client,
+// in which case it can still be overridden per report. It only affects the locks of lvm itself,
+// reports through a locking client still wait for the writes of that client.
+type ReportLocking string
+
+const (
+	// ReportLockingDefault takes the locks of lvm as configured.
+	ReportLockingDefault ReportLocking = ""
+	// ReportLockingNone runs reports with --nolocking. The state of the device-mapper devices is still reported,
+	// but metadata that is changed concurrently may be reported inconsistently.
+	ReportLockingNone ReportLocking = "nolocking"
+	// ReportLockingReadOnly runs reports with --readonly, which reads the metadata from disk without any locks and
+	// without communicating with the device-mapper kernel driver. Activation and usage of logical volumes
+	// cannot be reported in this mode.
+	ReportLockingReadOnly ReportLocking = "readonly"
+)
+
+func (opt ReportLocking) ApplyToArgs(args Arguments) error {
+	if opt != ReportLockingDefault {
+		args.AddOrReplace("--" + string(opt))
+	}
+	return nil
+}
+
+func (opt ReportLocking) ApplyToClientOptions(opts *ClientOptions) {
+	opts.ReportLocking = opt
+}
+
+func (opt ReportLocking) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.ReportLocking = opt
+}
+
+func (opt ReportLocking) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.ReportLocking = opt
+}
+
+func (opt ReportLocking) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.ReportLocking = opt
+}
+
+func (opt ReportLocking) ApplyToFullReportOptions(opts *FullReportOptions) {
+	opts.ReportLocking = opt
+}
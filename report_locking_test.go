@@ -0,0 +1,76 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestReportLocking(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	var commands [][]string
+	runner := CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		commands = append(commands, cmd.Args)
+		return io.NopCloser(strings.NewReader(`{"report":[{}]}`)), nil
+	})
+	hasFlag := func(flag string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Contains(commands[len(commands)-1], flag)
+	}
+
+	clnt := NewClient(runner)
+	if _, err := clnt.VGs(ctx, ReportLockingReadOnly); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFlag("--readonly") {
+		t.Fatalf("expected --readonly in %v", commands)
+	}
+
+	clnt = NewClient(runner, ReportLockingNone)
+	if _, err := clnt.LVs(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFlag("--nolocking") {
+		t.Fatalf("expected --nolocking in %v", commands)
+	}
+	if _, err := clnt.PVSegments(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !hasFlag("--nolocking") {
+		t.Fatalf("expected --nolocking in %v", commands)
+	}
+	if _, err := clnt.PVs(ctx, ReportLockingDefault); err != nil {
+		t.Fatal(err)
+	}
+	if hasFlag("--nolocking") {
+		t.Fatalf("expected the report locking of the client to be overridden in %v", commands)
+	}
+}
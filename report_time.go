@@ -0,0 +1,51 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportTimeLayouts are the layouts of timestamps in reports tried by ParseReportTime in order.
+// The first layout is the default report/time_format of lvm, "%Y-%m-%d %T %z".
+// If report/time_format is configured to another strftime format,
+// its equivalent Go layout can be added to decode the timestamps of the reports.
+var ReportTimeLayouts = []string{
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	time.RFC3339,
+	time.ANSIC,
+}
+
+// ParseReportTime parses a timestamp of a report such as lv_time.
+// Besides ReportTimeLayouts, unix time (report/time_format = "%s") is accepted.
+// Timestamps without a time zone are interpreted in the local time zone, like lvm formats them.
+func ParseReportTime(str string) (time.Time, error) {
+	str = strings.TrimSpace(str)
+	if seconds, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	for _, layout := range ReportTimeLayouts {
+		if t, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unknown time format of %q", str)
+}
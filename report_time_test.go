@@ -0,0 +1,92 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestParseReportTime(t *testing.T) {
+	t.Parallel()
+	expected := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, str := range []string{
+		"2024-05-01 12:00:00 +0200",
+		"2024-05-01T10:00:00Z",
+		"1714557600",
+	} {
+		parsed, err := ParseReportTime(str)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", str, err)
+		}
+		if !parsed.Equal(expected) {
+			t.Errorf("expected %s for %q, got %s", expected, str, parsed)
+		}
+	}
+
+	if _, err := ParseReportTime("yesterday"); err == nil {
+		t.Fatal("expected error for an unknown time format")
+	}
+}
+
+func TestLogicalVolume_CreationTime(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var lv LogicalVolume
+	if err := json.Unmarshal([]byte(`{"lv_name":"lv","lv_time":"2024-05-01 12:00:00 +0200","lv_time_removed":""}`), &lv); err != nil {
+		t.Fatal(err)
+	}
+	if lv.CreationTime.Unix() != 1714557600 || !lv.RemovalTime.IsZero() {
+		t.Fatalf("unexpected times %s and %s", lv.CreationTime, lv.RemovalTime)
+	}
+
+	// with --reportformat json_std, unix timestamps are reported as numbers
+	if err := json.Unmarshal([]byte(`{"lv_name":"lv","lv_time":1714557600}`), &lv); err != nil {
+		t.Fatal(err)
+	}
+	if lv.CreationTime.Unix() != 1714557600 {
+		t.Fatalf("unexpected creation time %s", lv.CreationTime)
+	}
+
+	if err := json.Unmarshal([]byte(`{"lv_name":"lv","lv_time":"yesterday"}`), &lv); err == nil {
+		t.Fatal("expected error for an unknown time format")
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("1G"))
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	before := time.Now().Truncate(time.Second)
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	created, err := clnt.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.CreationTime.Before(before) || time.Since(created.CreationTime) > time.Minute {
+		t.Fatalf("unexpected creation time %s", created.CreationTime)
+	}
+}
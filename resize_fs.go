@@ -0,0 +1,235 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ResizeFS resizes the filesystem on the logical volume together with the logical volume.
+// Since lvm2 2.03.17, it is the same as FS with FSResize, before it resizes the filesystem with fsadm.
+type ResizeFS bool
+
+// FS controls the handling of the filesystem on the logical volume when it is resized.
+// It is supported since lvm2 2.03.17.
+type FS string
+
+const (
+	// FSCheckSize fails a reduction that would cut off the end of the filesystem,
+	// without resizing the filesystem. This is the default of lvreduce.
+	FSCheckSize FS = "checksize"
+	// FSResize resizes the filesystem with its own tools, e.g. resize2fs or xfs_growfs.
+	FSResize FS = "resize"
+	// FSResizeFSAdm resizes the filesystem with fsadm.
+	FSResizeFSAdm FS = "resize_fsadm"
+	// FSIgnore resizes the logical volume without looking at the filesystem.
+	// Reducing a logical volume with FSIgnore destroys the data at the end of the filesystem.
+	FSIgnore FS = "ignore"
+)
+
+// FSMode controls how the filesystem is mounted or unmounted to be resized with FSResize.
+type FSMode string
+
+const (
+	// FSModeManage mounts or unmounts the filesystem as needed for the resize. This is the default.
+	FSModeManage FSMode = "manage"
+	// FSModeNoChange fails the resize if the filesystem would have to be mounted or unmounted.
+	FSModeNoChange FSMode = "nochange"
+	// FSModeOffline unmounts the filesystem and resizes it offline, also if it could be resized online.
+	FSModeOffline FSMode = "offline"
+)
+
+func (opt ResizeFS) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.ResizeFS = opt
+}
+func (opt ResizeFS) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.ResizeFS = opt
+}
+func (opt ResizeFS) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.ResizeFS = opt
+}
+
+func (opt ResizeFS) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--resizefs"})
+	}
+	return nil
+}
+
+func (opt FS) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.FS = opt
+}
+func (opt FS) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.FS = opt
+}
+func (opt FS) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.FS = opt
+}
+
+func (opt FS) ApplyToArgs(args Arguments) error {
+	switch opt {
+	case "":
+		return nil
+	case FSCheckSize, FSResize, FSResizeFSAdm, FSIgnore:
+		args.AddOrReplace(fmt.Sprintf("--fs=%s", opt))
+		return nil
+	default:
+		return fmt.Errorf("unknown filesystem handling %q", string(opt))
+	}
+}
+
+func (opt FSMode) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.FSMode = opt
+}
+func (opt FSMode) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.FSMode = opt
+}
+func (opt FSMode) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.FSMode = opt
+}
+
+func (opt FSMode) ApplyToArgs(args Arguments) error {
+	switch opt {
+	case "":
+		return nil
+	case FSModeManage, FSModeNoChange, FSModeOffline:
+		args.AddOrReplace(fmt.Sprintf("--fsmode=%s", opt))
+		return nil
+	default:
+		return fmt.Errorf("unknown filesystem mode %q", string(opt))
+	}
+}
+
+// validateResizeFS checks that the filesystem options of a resize do not contradict each other.
+func validateResizeFS(resizeFS ResizeFS, fs FS, mode FSMode) error {
+	if resizeFS && fs != "" && fs != FSResize {
+		return fmt.Errorf("resizefs is mutually exclusive with fs %s", fs)
+	}
+	if mode != "" && !resizeFS && fs != FSResize {
+		return fmt.Errorf("fsmode requires resizefs or fs %s", FSResize)
+	}
+	return nil
+}
+
+type (
+	// FSAdmOptions are the options of FSAdm.
+	FSAdmOptions struct {
+		FSAdmDryRun
+		FSAdmExtOffline
+		Force
+	}
+	FSAdmOption interface {
+		ApplyToFSAdmOptions(opts *FSAdmOptions)
+	}
+)
+
+// FSAdmAction is the action of fsadm on the filesystem.
+type FSAdmAction string
+
+const (
+	// FSAdmCheck checks the filesystem with its fsck tool.
+	FSAdmCheck FSAdmAction = "check"
+	// FSAdmResize resizes the filesystem to the given size, or to the size of the device without size.
+	FSAdmResize FSAdmAction = "resize"
+)
+
+// FSAdmDryRun prints the commands fsadm would run instead of running them.
+// fsadm prints them only in verbose mode, so it is run with --verbose, see FSAdm.
+type FSAdmDryRun bool
+
+// FSAdmExtOffline unmounts ext2, ext3 and ext4 filesystems for a resize, also if they could be grown online.
+type FSAdmExtOffline bool
+
+func (opt FSAdmDryRun) ApplyToFSAdmOptions(opts *FSAdmOptions) {
+	opts.FSAdmDryRun = opt
+}
+func (opt FSAdmExtOffline) ApplyToFSAdmOptions(opts *FSAdmOptions) {
+	opts.FSAdmExtOffline = opt
+}
+func (opt Force) ApplyToFSAdmOptions(opts *FSAdmOptions) {
+	opts.Force = opt
+}
+
+// FSAdm checks or resizes the filesystem on the logical volume with fsadm, independent of the logical volume.
+// fsadm is run non-interactively, so that it answers all its questions, e.g. about unmounting, with yes.
+// With FSAdmResize, a size of zero resizes the filesystem to the size of the logical volume,
+// e.g. after it was extended without ResizeFS. The size is ignored with FSAdmCheck.
+// The command is executed through the exec layer of the client, which has to implement RawCommandRunner.
+// The lines printed by fsadm are returned, with FSAdmDryRun they contain the commands fsadm would run.
+//
+// See man fsadm for more information.
+func FSAdm(ctx context.Context, client Client, lv *FQLogicalVolumeName, action FSAdmAction, size Size, opts ...FSAdmOption) ([]string, error) {
+	options := FSAdmOptions{}
+	for _, opt := range opts {
+		opt.ApplyToFSAdmOptions(&options)
+	}
+
+	runner, device, err := filesystemCommandTarget(ctx, client, lv, true)
+	if err != nil {
+		return nil, err
+	}
+
+	args, err := fsadmArgs(action, device, size, options)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := runRawOutput(ctx, runner, args...)
+	if err != nil {
+		return nil, err
+	}
+	if output == "" {
+		return nil, nil
+	}
+	return strings.Split(output, "\n"), nil
+}
+
+func fsadmArgs(action FSAdmAction, device string, size Size, options FSAdmOptions) ([]string, error) {
+	args := []string{"fsadm", "--yes"}
+	if options.FSAdmDryRun {
+		args = append(args, "--dry-run", "--verbose")
+	}
+	if options.FSAdmExtOffline {
+		args = append(args, "--ext-offline")
+	}
+	if options.Force {
+		args = append(args, "--force")
+	}
+
+	switch action {
+	case FSAdmCheck:
+		return append(args, string(action), device), nil
+	case FSAdmResize:
+		args = append(args, string(action), device)
+		if size.Val == 0 {
+			return args, nil
+		}
+		if err := size.Validate(); err != nil {
+			return nil, err
+		}
+		bytes, err := size.ToUnit(UnitBytes)
+		if err != nil {
+			return nil, err
+		}
+		// fsadm interprets sizes without unit as 1KiB blocks
+		return append(args, fmt.Sprintf("%.0fB", bytes.Val)), nil
+	default:
+		return nil, fmt.Errorf("unknown fsadm action %q", string(action))
+	}
+}
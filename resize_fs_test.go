@@ -0,0 +1,111 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestResizeFSArgs(t *testing.T) {
+	t.Parallel()
+	lv := []LVReduceOption{VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("-1G")}
+
+	for _, tc := range []struct {
+		name     string
+		opts     []LVReduceOption
+		expected []string
+		err      bool
+	}{
+		{"resizefs", []LVReduceOption{ResizeFS(true)}, []string{"vg/lv", "--size=-1.00g", "--resizefs", "--yes"}, false},
+		{"fs", []LVReduceOption{FSResize, FSModeOffline}, []string{"vg/lv", "--size=-1.00g", "--fs=resize", "--fsmode=offline", "--yes"}, false},
+		{"checksize", []LVReduceOption{FSCheckSize}, []string{"vg/lv", "--size=-1.00g", "--fs=checksize", "--yes"}, false},
+		{"conflict", []LVReduceOption{ResizeFS(true), FSIgnore}, nil, true},
+		{"fsmode without resize", []LVReduceOption{FSCheckSize, FSModeNoChange}, nil, true},
+		{"unknown fs", []LVReduceOption{FS("shrink")}, nil, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := LVReduceOptionsList(append(slices.Clone(lv), tc.opts...)).AsArgs()
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(args.GetRaw(), tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, args.GetRaw())
+			}
+		})
+	}
+
+	args, err := LVResizeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParsePrefixedSize("+1G"), ResizeFS(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--resizefs") {
+		t.Fatalf("expected --resizefs in %v", args.GetRaw())
+	}
+}
+
+func TestFSAdm(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv := &FQLogicalVolumeName{VolumeGroupName: "vg", LogicalVolumeName: "lv"}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{
+		{
+			Command: "fsadm",
+			Args:    []string{"--yes", "resize", "/dev/vg/lv", "1073741824B"},
+		},
+		{
+			Command: "fsadm",
+			Args:    []string{"--yes", "--dry-run", "--verbose", "check", "/dev/vg/lv"},
+			Stdout:  "fsadm: \"ext4\" filesystem found on \"/dev/mapper/vg-lv\".\nfsadm: Dry execution fsck -f -p /dev/mapper/vg-lv\n",
+		},
+		{
+			Command:  "fsadm",
+			Args:     []string{"--yes", "resize", "/dev/vg/lv"},
+			Stderr:   "fsadm: Filesystem \"unknown\" on device \"/dev/vg/lv\" is not supported by this tool.\n",
+			ExitCode: 1,
+		},
+	}))
+
+	if output, err := FSAdm(ctx, clnt, lv, FSAdmResize, MustParseSize("1G")); err != nil {
+		t.Fatal(err)
+	} else if len(output) != 0 {
+		t.Fatalf("expected no output, got %q", output)
+	}
+	output, err := FSAdm(ctx, clnt, lv, FSAdmCheck, Size{}, FSAdmDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != 2 || output[1] != "fsadm: Dry execution fsck -f -p /dev/mapper/vg-lv" {
+		t.Fatalf("expected the dry run output of fsadm, got %q", output)
+	}
+	if _, err := FSAdm(ctx, clnt, lv, FSAdmResize, Size{}); err == nil {
+		t.Fatal("expected error for unsupported filesystem")
+	}
+	if _, err := FSAdm(ctx, clnt, lv, FSAdmAction("shrink"), Size{}); err == nil {
+		t.Fatal("expected error for unknown action")
+	}
+}
@@ -16,6 +16,12 @@
 
 package lvm2go
 
+// Resync forces the complete synchronization of a mirror or raid logical volume with LVChange,
+// e.g. after it was created with NoSync.
+//
+// Warning: data is copied from the primary leg to all other legs, overwriting their contents.
+// This can take considerable time, during which the logical volume has no complete redundant copy of its data.
+// Mirrors of the mirrored type are deactivated for the synchronization, so they must not be in use.
 type Resync bool
 
 func (opt Resync) ApplyToArgs(args Arguments) error {
@@ -23,7 +23,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"log/slog"
+	"slices"
 	"strings"
 )
 
@@ -35,18 +35,17 @@ func (c *client) RunLVM(ctx context.Context, args ...string) error {
 // RunLVMInto calls lvm2 sub-commands and decodes the output via JSON into the provided struct pointer.
 // if the struct pointer is nil, the output will be printed to the log instead.
 func (c *client) RunLVMInto(ctx context.Context, into any, args ...string) error {
-	cmd := CommandContext(ctx, GetLVMPath(), args...)
-
-	output, err := StreamedCommand(ctx, cmd)
+	args = c.withDevicesFile(args)
+	output, done, err := c.command(ctx, c.lvmPath(), args...)
 	if err != nil {
-		return fmt.Errorf("failed to execute command: %v", err)
+		return fmt.Errorf("failed to execute command: %w", err)
 	}
 
 	// if we don't decode the output into a struct, we can still log the command results from stdout.
 	if into == nil {
 		scanner := bufio.NewScanner(output)
 		for scanner.Scan() {
-			slog.InfoContext(ctx, strings.TrimSpace(scanner.Text()))
+			c.logger().InfoContext(ctx, strings.TrimSpace(scanner.Text()))
 		}
 		err = scanner.Err()
 	} else {
@@ -54,16 +53,33 @@ func (c *client) RunLVMInto(ctx context.Context, into any, args ...string) error
 	}
 
 	err = errors.Join(err, output.Close())
+	done(err)
 
 	if IsNoSuchCommand(err) {
 		return fmt.Errorf("%q is not a valid command: %w", strings.Join(args, " "), err)
 	}
 
-	return err
+	return c.withKernelMessages(ctx, err)
 }
 
 func (c *client) RunLVMRaw(ctx context.Context, process RawOutputProcessor, args ...string) error {
-	return c.RunRaw(ctx, process, append([]string{GetLVMPath()}, args...)...)
+	return c.RunRaw(ctx, process, append([]string{c.lvmPath()}, c.withDevicesFile(args)...)...)
+}
+
+// commandsWithoutDevicesFile are lvm sub-commands that do not accept --devicesfile.
+var commandsWithoutDevicesFile = []string{
+	"version", "lvmconfig", "config", "dumpconfig", "segtypes", "formats", "types", "help", "systemid",
+}
+
+// withDevicesFile adds the devices file of the client after the sub-command,
+// unless the command already selects a devices file or does not accept one.
+func (c *client) withDevicesFile(args []string) []string {
+	if c.opts.DevicesFile == "" || len(args) == 0 ||
+		slices.Contains(commandsWithoutDevicesFile, args[0]) ||
+		slices.Contains(args, "--devicesfile") {
+		return args
+	}
+	return slices.Concat(args[:1], []string{"--devicesfile", string(c.opts.DevicesFile)}, args[1:])
 }
 
 type RawOutputProcessor func(out io.Reader) error
@@ -85,13 +101,13 @@ func (c *client) RunRaw(ctx context.Context, process RawOutputProcessor, args ..
 	if len(args) == 0 {
 		return fmt.Errorf("no command provided")
 	}
-	cmd := CommandContext(ctx, args[0], args[1:]...)
-
-	output, err := StreamedCommand(ctx, cmd)
+	output, done, err := c.command(ctx, args[0], args[1:]...)
 	if err != nil {
-		return fmt.Errorf("failed to execute command: %v", err)
+		return fmt.Errorf("failed to execute command: %w", err)
 	}
 	err = process(output)
 	closeErr := output.Close()
-	return errors.Join(closeErr, err)
+	err = errors.Join(closeErr, err)
+	done(err)
+	return c.withKernelMessages(ctx, err)
 }
@@ -38,6 +38,12 @@ func (opt Select) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
 func (opt Select) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
 	opts.Select = opt
 }
+func (opt Select) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.Select = opt
+}
+func (opt Select) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.Select = opt
+}
 
 func (opt Select) ApplyToArgs(args Arguments) error {
 	if opt != "" {
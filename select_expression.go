@@ -0,0 +1,195 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"strings"
+)
+
+// ReportField is a field of the lvm reports that can be compared in select expressions and sorted by with SortBy.
+// The methods of a field build a Select that can be combined with Select.And, Select.Or and Select.Not, e.g.
+//
+//	LVSize.Gt("1g").And(LVTags.Contains("team=a"))
+//
+// Values are quoted if necessary, so that they cannot change the structure of the expression.
+// See man lvmreport for the fields and the syntax of select expressions.
+type ReportField string
+
+// Fields of logical volumes.
+const (
+	LVName            ReportField = "lv_name"
+	LVFullName        ReportField = "lv_full_name"
+	LVUUID            ReportField = "lv_uuid"
+	LVSize            ReportField = "lv_size"
+	LVTags            ReportField = "lv_tags"
+	LVActive          ReportField = "lv_active"
+	LVLayout          ReportField = "lv_layout"
+	LVRole            ReportField = "lv_role"
+	LVTime            ReportField = "lv_time"
+	LVOrigin          ReportField = "origin"
+	LVPool            ReportField = "pool_lv"
+	LVDataPercent     ReportField = "data_percent"
+	LVMetadataPercent ReportField = "metadata_percent"
+	LVAutoActivation  ReportField = "lv_autoactivation"
+)
+
+// Fields of volume groups.
+const (
+	VGName           ReportField = "vg_name"
+	VGUUID           ReportField = "vg_uuid"
+	VGSize           ReportField = "vg_size"
+	VGFree           ReportField = "vg_free"
+	VGTags           ReportField = "vg_tags"
+	VGExtentSize     ReportField = "vg_extent_size"
+	VGLVCount        ReportField = "lv_count"
+	VGPVCount        ReportField = "pv_count"
+	VGSysID          ReportField = "vg_sysid"
+	VGSystemID       ReportField = "vg_systemid"
+	VGLockType       ReportField = "vg_lock_type"
+	VGAutoActivation ReportField = "vg_autoactivation"
+)
+
+// Fields of physical volumes.
+const (
+	PVName    ReportField = "pv_name"
+	PVUUID    ReportField = "pv_uuid"
+	PVSize    ReportField = "pv_size"
+	PVFree    ReportField = "pv_free"
+	PVTags    ReportField = "pv_tags"
+	PVDevSize ReportField = "dev_size"
+	PVMissing ReportField = "pv_missing"
+)
+
+// Compare compares the field to the value with the given operator.
+func (field ReportField) Compare(operator SelectionComparisonOperator, value string) Select {
+	var sb strings.Builder
+	sb.WriteString(string(field))
+	sb.WriteString(string(operator))
+	sb.WriteString(quoteSelectValue(value))
+	return Select(sb.String())
+}
+
+// Eq matches if the field is equal to the value.
+func (field ReportField) Eq(value string) Select {
+	return field.Compare(Match, value)
+}
+
+// Ne matches if the field is not equal to the value.
+func (field ReportField) Ne(value string) Select {
+	return field.Compare(NotMatch, value)
+}
+
+// Gt matches if the field is greater than the value, e.g. a size such as 1g or a percentage.
+func (field ReportField) Gt(value string) Select {
+	return field.Compare(Greater, value)
+}
+
+// Ge matches if the field is greater than or equal to the value.
+func (field ReportField) Ge(value string) Select {
+	return field.Compare(GreaterOrEq, value)
+}
+
+// Lt matches if the field is less than the value.
+func (field ReportField) Lt(value string) Select {
+	return field.Compare(Less, value)
+}
+
+// Le matches if the field is less than or equal to the value.
+func (field ReportField) Le(value string) Select {
+	return field.Compare(LessOrEq, value)
+}
+
+// Matches matches if the field matches the regular expression.
+func (field ReportField) Matches(regex string) Select {
+	return field.Compare(MatchRegex, regex)
+}
+
+// NotMatches matches if the field does not match the regular expression.
+func (field ReportField) NotMatches(regex string) Select {
+	return field.Compare(NotMatchRegex, regex)
+}
+
+// Since matches if the time of the field is at or after the given time, e.g. "2024-01-01 12:00" or "yesterday".
+func (field ReportField) Since(time string) Select {
+	return field.Compare(" "+Since+" ", time)
+}
+
+// Before matches if the time of the field is before the given time.
+func (field ReportField) Before(time string) Select {
+	return field.Compare(" "+Before+" ", time)
+}
+
+// Contains matches if the list field, e.g. tags, contains all the given items.
+func (field ReportField) Contains(items ...string) Select {
+	return field.list(AllFieldsMatch, items)
+}
+
+// ContainsAny matches if the list field, e.g. tags, contains at least one of the given items.
+func (field ReportField) ContainsAny(items ...string) Select {
+	return field.list(AtLeastOneFieldMatches, items)
+}
+
+func (field ReportField) list(operator LogicalAndGroupingOperator, items []string) Select {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		quoted[i] = quoteSelectValue(item)
+	}
+	return Select(string(field) + string(Match) + string(ListSubsetStart) +
+		strings.Join(quoted, " "+string(operator)+" ") + string(ListSubsetEnd))
+}
+
+// And matches if the select and all the others match.
+func (opt Select) And(others ...Select) Select {
+	return combineSelects(AllFieldsMatch, append([]Select{opt}, others...))
+}
+
+// Or matches if the select or at least one of the others match.
+func (opt Select) Or(others ...Select) Select {
+	return combineSelects(AtLeastOneFieldMatches, append([]Select{opt}, others...))
+}
+
+// Not matches if the select does not match.
+func (opt Select) Not() Select {
+	return NotSelect(opt)
+}
+
+// combineSelects combines the non-empty selects with the operator.
+func combineSelects(operator LogicalAndGroupingOperator, selects []Select) Select {
+	nonEmpty := make([]Select, 0, len(selects))
+	for _, sel := range selects {
+		if sel != "" {
+			nonEmpty = append(nonEmpty, sel)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return ""
+	}
+	return NewCombinedSelect(operator, nonEmpty...)
+}
+
+// quoteSelectValue quotes values that contain whitespace or characters of the select syntax.
+// lvm does not support escapes in quoted values, so values containing double quotes are quoted with single quotes.
+// Values containing both kinds of quotes cannot be expressed and are rejected by lvm.
+func quoteSelectValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\"'=!~<>&|,#()[]{}") {
+		return value
+	}
+	if strings.Contains(value, `"`) {
+		return "'" + value + "'"
+	}
+	return `"` + value + `"`
+}
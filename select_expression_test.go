@@ -0,0 +1,100 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestSelectExpression(t *testing.T) {
+	t.Parallel()
+	for name, tc := range map[string]struct {
+		sel      Select
+		expected string
+	}{
+		"comparison": {
+			sel:      LVSize.Gt("1g"),
+			expected: "lv_size>1g",
+		},
+		"quoted value": {
+			sel:      LVName.Eq("a b"),
+			expected: `lv_name="a b"`,
+		},
+		"value with double quotes": {
+			sel:      LVName.Eq(`a"b`),
+			expected: `lv_name='a"b'`,
+		},
+		"injection": {
+			sel:      VGName.Eq("vg||lv_name=x"),
+			expected: `vg_name="vg||lv_name=x"`,
+		},
+		"list": {
+			sel:      LVTags.Contains("team=a", "prod"),
+			expected: `lv_tags={"team=a" && prod}`,
+		},
+		"list any": {
+			sel:      PVTags.ContainsAny("a", "b"),
+			expected: "pv_tags={a || b}",
+		},
+		"time": {
+			sel:      LVTime.Since("2024-01-01"),
+			expected: "lv_time since 2024-01-01",
+		},
+		"and": {
+			sel:      LVSize.Gt("1g").And(LVTags.Contains("team=a")),
+			expected: `(lv_size>1g)&&(lv_tags={"team=a"})`,
+		},
+		"or not": {
+			sel:      VGName.Eq("a").Or(VGName.Matches("^b")).Not(),
+			expected: "!((vg_name=a)||(vg_name=~^b))",
+		},
+		"empty": {
+			sel:      Select("").And(LVActive.Eq("active"), ""),
+			expected: "lv_active=active",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			if string(tc.sel) != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, tc.sel)
+			}
+		})
+	}
+}
+
+func TestSelectExpression_LVChange(t *testing.T) {
+	t.Parallel()
+	args, err := LVChangeOptionsList{
+		VolumeGroupName("vg"),
+		LVTags.Contains("managed"),
+		Deactivate,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := args.GetRaw()
+	if !slices.Contains(raw, "vg") || !slices.Contains(raw, "--select=lv_tags={managed}") {
+		t.Fatalf("unexpected args %v", raw)
+	}
+
+	if _, err := (LVChangeOptionsList{Deactivate}).AsArgs(); err == nil {
+		t.Fatal("expected error without logical volume name or select")
+	}
+}
@@ -16,6 +16,8 @@
 
 package lvm2go
 
+// Shared creates a volume group that is shared by multiple hosts and locked by lvmlockd with VGCreate.
+// The lock manager is chosen by LockType, which lvmlockd otherwise derives from the running lock managers.
 type Shared bool
 
 func (opt Shared) ApplyToArgs(args Arguments) error {
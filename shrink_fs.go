@@ -0,0 +1,142 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrFilesystemCannotShrink is returned by ShrinkLVWithFS for filesystems that cannot be shrunk, such as xfs.
+var ErrFilesystemCannotShrink = errors.New("filesystem cannot be shrunk")
+
+type (
+	ShrinkLVWithFSOptions struct {
+		ShrinkDryRun
+	}
+	ShrinkLVWithFSOption interface {
+		ApplyToShrinkLVWithFSOptions(opts *ShrinkLVWithFSOptions)
+	}
+)
+
+// ShrinkDryRun makes ShrinkLVWithFS only detect the filesystem and return the commands it would run.
+type ShrinkDryRun bool
+
+func (opt ShrinkDryRun) ApplyToShrinkLVWithFSOptions(opts *ShrinkLVWithFSOptions) {
+	opts.ShrinkDryRun = opt
+}
+
+// ShrinkLVWithFS shrinks the filesystem on the logical volume before reducing the logical volume to size.
+// Reducing a logical volume with lvreduce alone cuts off the end of the filesystem and destroys its data.
+//
+// The size is rounded up to the extent size of the volume group, so that the filesystem and the logical
+// volume end up with the same size. ext2, ext3 and ext4 are checked with e2fsck -f -p and shrunk with
+// resize2fs while unmounted. xfs cannot be shrunk at all and results in ErrFilesystemCannotShrink,
+// other filesystems and logical volumes without a filesystem in ErrUnsupportedFilesystem.
+//
+// The commands are returned in the order they are run. With ShrinkDryRun, nothing is modified
+// and only the commands that would be run are returned.
+// The filesystem commands are executed through the exec layer of the client, which has to implement RawCommandRunner.
+func ShrinkLVWithFS(ctx context.Context, client Client, lv *FQLogicalVolumeName, size Size, opts ...ShrinkLVWithFSOption) ([][]string, error) {
+	options := ShrinkLVWithFSOptions{}
+	for _, opt := range opts {
+		opt.ApplyToShrinkLVWithFSOptions(&options)
+	}
+
+	runner, device, err := filesystemCommandTarget(ctx, client, lv, true)
+	if err != nil {
+		return nil, err
+	}
+	if err := size.Validate(); err != nil {
+		return nil, err
+	}
+	target, err := size.ToUnit(UnitBytes)
+	if err != nil {
+		return nil, err
+	}
+	if target.Val <= 0 {
+		return nil, fmt.Errorf("size must be specified")
+	}
+
+	report, err := client.LV(ctx, lv.VolumeGroupName, lv.LogicalVolumeName, UnitBytes)
+	if err != nil {
+		return nil, err
+	}
+	if report.Attr.Open == OpenTrue {
+		return nil, fmt.Errorf("%s is open, the filesystem has to be unmounted to be shrunk", lv)
+	}
+	vg, err := client.VG(ctx, lv.VolumeGroupName, UnitBytes)
+	if err != nil {
+		return nil, err
+	}
+	if vg.ExtentSize.Val > 0 {
+		target.Val = math.Ceil(target.Val/vg.ExtentSize.Val) * vg.ExtentSize.Val
+	}
+	if target.Val >= report.Size.Val {
+		return nil, fmt.Errorf("new size %s of %s is not smaller than its current size %s", target, lv, report.Size)
+	}
+
+	fsType, err := blkidValue(ctx, runner, "TYPE", device)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect filesystem on %s: %w", lv, err)
+	}
+
+	var commands [][]string
+	switch fsType {
+	case "ext2", "ext3", "ext4":
+		// resize2fs refuses to shrink a filesystem that was not checked since it was last mounted
+		commands = [][]string{
+			{"e2fsck", "-f", "-p", device},
+			{"resize2fs", device, fmt.Sprintf("%.0fK", target.Val/1024)},
+		}
+	case "xfs":
+		return nil, fmt.Errorf("%w: %s filesystem on %s", ErrFilesystemCannotShrink, fsType, lv)
+	case "":
+		return nil, fmt.Errorf("%w: no filesystem found on %s", ErrUnsupportedFilesystem, lv)
+	default:
+		return nil, fmt.Errorf("%w: cannot shrink %s filesystem on %s", ErrUnsupportedFilesystem, fsType, lv)
+	}
+
+	reduce := LVReduceOptionsList{lv, PrefixedSize{Size: target}}
+	args, err := reduce.AsArgs()
+	if err != nil {
+		return nil, err
+	}
+	commands = append(commands, append([]string{"lvreduce"}, args.GetRaw()...))
+
+	if options.ShrinkDryRun {
+		return commands, nil
+	}
+
+	for _, command := range commands[:len(commands)-1] {
+		_, err := runRawOutput(ctx, runner, command...)
+		// e2fsck exits with 1 if it corrected errors on the filesystem
+		if exitErr, ok := AsExitCodeError(err); ok && command[0] == "e2fsck" && exitErr.ExitCode() == 1 {
+			err = nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to shrink filesystem on %s with %s: %w", lv, command[0], err)
+		}
+	}
+	if err := client.LVReduce(ctx, reduce...); err != nil {
+		return nil, fmt.Errorf("failed to reduce %s after shrinking its filesystem: %w", lv, err)
+	}
+
+	return commands, nil
+}
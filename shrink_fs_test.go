@@ -0,0 +1,99 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestShrinkLVWithFS(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv, err := NewFQLogicalVolumeName("vg", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := func(name string, opts ArgumentGenerator, stdout string) CommandRecording {
+		args, err := opts.AsArgs()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return CommandRecording{
+			Command: "lvm",
+			Args:    append([]string{name, "--reportformat", "json"}, args.GetRaw()...),
+			Stdout:  stdout,
+		}
+	}
+	lvs := report("lvs", LVsOptionsList{VolumeGroupName("vg"), LogicalVolumeName("data"), UnitBytes},
+		`{"report":[{"lv":[{"lv_name":"data","vg_name":"vg","lv_attr":"-wi-a-----","lv_size":"2147483648B"}]}]}`)
+	vgs := report("vgs", VGsOptionsList{VolumeGroupName("vg"), UnitBytes},
+		`{"report":[{"vg":[{"vg_name":"vg","vg_extent_size":"4194304B"}]}]}`)
+	blkid := func(fsType string) CommandRecording {
+		return CommandRecording{
+			Command: "blkid",
+			Args:    []string{"-p", "-o", "value", "-s", "TYPE", "/dev/vg/data"},
+			Stdout:  fsType + "\n",
+		}
+	}
+
+	// 1000.5MiB is rounded up to the next 4MiB extent boundary
+	reduceArgs, err := LVReduceOptionsList{lv, PrefixedSize{Size: NewSize(1004*1024*1024, UnitBytes)}}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{
+		{"e2fsck", "-f", "-p", "/dev/vg/data"},
+		{"resize2fs", "/dev/vg/data", "1028096K"},
+		append([]string{"lvreduce"}, reduceArgs.GetRaw()...),
+	}
+	size := MustParseSize("1000.5M")
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{lvs, vgs, blkid("ext4")}))
+	commands, err := ShrinkLVWithFS(ctx, clnt, lv, size, ShrinkDryRun(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.EqualFunc(commands, expected, slices.Equal) {
+		t.Fatalf("unexpected dry run commands %v", commands)
+	}
+
+	clnt = NewClient(ReplayCommandRunner([]CommandRecording{
+		lvs, vgs, blkid("ext4"),
+		{Command: "e2fsck", Args: expected[0][1:], ExitCode: 1},
+		{Command: "resize2fs", Args: expected[1][1:]},
+		{Command: "lvm", Args: expected[2]},
+	}))
+	if _, err := ShrinkLVWithFS(ctx, clnt, lv, size); err != nil {
+		t.Fatal(err)
+	}
+
+	clnt = NewClient(ReplayCommandRunner([]CommandRecording{lvs, vgs, blkid("xfs")}))
+	if _, err := ShrinkLVWithFS(ctx, clnt, lv, size); !errors.Is(err, ErrFilesystemCannotShrink) {
+		t.Fatalf("expected xfs to be refused, got %v", err)
+	}
+
+	clnt = NewClient(ReplayCommandRunner([]CommandRecording{lvs, vgs}))
+	if _, err := ShrinkLVWithFS(ctx, clnt, lv, MustParseSize("2G")); err == nil {
+		t.Fatal("expected shrinking to the current size to fail")
+	}
+}
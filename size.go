@@ -53,6 +53,9 @@ const (
 	chunkSizeArg           = "--chunksize"
 	dataAlignmentArg       = "--dataalignment"
 	dataAlignmentOffsetArg = "--dataalignmentoffset"
+	bootLoaderAreaSizeArg  = "--bootloaderareasize"
+	minRecoveryRateArg     = "--minrecoveryrate"
+	maxRecoveryRateArg     = "--maxrecoveryrate"
 )
 
 type Unit rune
@@ -104,6 +107,9 @@ func (unit Unit) ApplyToVGsOptions(opts *VGsOptions) {
 func (unit Unit) ApplyToPVsOptions(opts *PVsOptions) {
 	opts.Unit = unit
 }
+func (unit Unit) ApplyToFullReportOptions(opts *FullReportOptions) {
+	opts.Unit = unit
+}
 
 const (
 	conversionFactor      = 1024
@@ -330,7 +336,7 @@ func (opt Size) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 }
 
 func (opt Size) ApplyToLVResizeOptions(opts *LVResizeOptions) {
-	opts.Size = opt
+	opts.PrefixedSize.Size = opt
 }
 
 func (opt Size) ApplyToArgs(args Arguments) error {
@@ -441,18 +447,34 @@ func (opt PrefixedSize) ApplyToLVExtendOptions(opts *LVExtendOptions) {
 	opts.PrefixedSize = opt
 }
 
+func (opt PrefixedSize) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.PrefixedSize = opt
+}
+
 type PoolMetadataPrefixedSize PrefixedSize
 
 func (opt PoolMetadataPrefixedSize) ApplyToArgs(args Arguments) error {
 	return PrefixedSize(opt).applyToArgs(poolMetadataSizeArg, args)
 }
 
+func (opt PoolMetadataPrefixedSize) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.PoolMetadataPrefixedSize = opt
+}
+
 type PoolMetadataSize Size
 
 func (opt PoolMetadataSize) ApplyToArgs(args Arguments) error {
 	return Size(opt).applyToArgs(poolMetadataSizeArg, args)
 }
 
+func (opt PoolMetadataSize) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.PoolMetadataSize = opt
+}
+
+func (opt PoolMetadataSize) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.PoolMetadataSize = opt
+}
+
 type VirtualSize Size
 
 func (opt VirtualSize) ApplyToLVCreateOptions(opts *LVCreateOptions) {
@@ -475,6 +497,10 @@ func (opt ChunkSize) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.ChunkSize = opt
 }
 
+func (opt ChunkSize) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.ChunkSize = opt
+}
+
 func (opt ChunkSize) ApplyToArgs(args Arguments) error {
 	return Size(opt).applyToArgs(chunkSizeArg, args)
 }
@@ -482,6 +508,10 @@ func (opt ChunkSize) ApplyToArgs(args Arguments) error {
 type DataAlignment Size
 
 func (opt DataAlignment) ApplyToArgs(args Arguments) error {
+	// the zero value is not passed, as lvm2 would use it instead of its default
+	if opt.Val == 0 {
+		return nil
+	}
 	return Size(opt).applyToArgs(dataAlignmentArg, args)
 }
 
@@ -496,6 +526,9 @@ func (opt DataAlignment) ApplyToPVCreateOptions(opts *PVCreateOptions) {
 type DataAlignmentOffset Size
 
 func (opt DataAlignmentOffset) ApplyToArgs(args Arguments) error {
+	if opt.Val == 0 {
+		return nil
+	}
 	return Size(opt).applyToArgs(dataAlignmentOffsetArg, args)
 }
 
@@ -510,6 +543,9 @@ func (opt DataAlignmentOffset) ApplyToPVCreateOptions(opts *PVCreateOptions) {
 type MetadataSize Size
 
 func (opt MetadataSize) ApplyToArgs(args Arguments) error {
+	if opt.Val == 0 {
+		return nil
+	}
 	return Size(opt).applyToArgs(metadataSizeArg, args)
 }
 
@@ -0,0 +1,114 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrSizeNotAligned is returned if a size cannot be aligned to the extent size of a volume group
+// with the requested SizeAlignment.
+var ErrSizeNotAligned = errors.New("size is not aligned to the extent size")
+
+// SizeAlignment specifies how LVCreate and LVExtend align requested sizes to the extent size of the volume group
+// before building the arguments. By default, lvm2 rounds sizes up to the next extent on its own and only
+// reports the actual size in its output.
+type SizeAlignment int
+
+const (
+	// SizeAlignmentNone passes sizes as requested and leaves the rounding to lvm2.
+	SizeAlignmentNone SizeAlignment = iota
+	// SizeAlignmentRoundUp rounds sizes up to the next multiple of the extent size.
+	SizeAlignmentRoundUp
+	// SizeAlignmentRoundDown rounds sizes down to the previous multiple of the extent size.
+	// Sizes smaller than one extent result in ErrSizeNotAligned.
+	SizeAlignmentRoundDown
+	// SizeAlignmentExact rejects sizes that are not a multiple of the extent size with ErrSizeNotAligned.
+	SizeAlignmentExact
+)
+
+func (opt SizeAlignment) String() string {
+	switch opt {
+	case SizeAlignmentNone:
+		return "none"
+	case SizeAlignmentRoundUp:
+		return "roundup"
+	case SizeAlignmentRoundDown:
+		return "rounddown"
+	case SizeAlignmentExact:
+		return "exact"
+	default:
+		return fmt.Sprintf("SizeAlignment(%d)", int(opt))
+	}
+}
+
+func (opt SizeAlignment) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.SizeAlignment = opt
+}
+
+func (opt SizeAlignment) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.SizeAlignment = opt
+}
+
+// Align aligns size to a multiple of extentSize. The aligned size is returned in bytes,
+// SizeAlignmentNone returns the size unchanged.
+func (opt SizeAlignment) Align(size, extentSize Size) (Size, error) {
+	if opt == SizeAlignmentNone {
+		return size, nil
+	}
+	bytes, err := size.ToUnit(UnitBytes)
+	if err != nil {
+		return Size{}, err
+	}
+	extent, err := extentSize.ToUnit(UnitBytes)
+	if err != nil {
+		return Size{}, err
+	}
+	if extent.Val <= 0 {
+		return Size{}, fmt.Errorf("cannot align %s to extent size %s", size, extentSize)
+	}
+
+	extents := bytes.Val / extent.Val
+	switch opt {
+	case SizeAlignmentRoundUp:
+		extents = math.Ceil(extents)
+	case SizeAlignmentRoundDown:
+		if extents = math.Floor(extents); extents == 0 {
+			return Size{}, fmt.Errorf("%w: %s is smaller than the extent size %s", ErrSizeNotAligned, size, extentSize)
+		}
+	case SizeAlignmentExact:
+		if extents != math.Trunc(extents) {
+			return Size{}, fmt.Errorf("%w: %s is not a multiple of the extent size %s", ErrSizeNotAligned, size, extentSize)
+		}
+	default:
+		return Size{}, fmt.Errorf("unknown size alignment %s", opt)
+	}
+
+	return NewSize(extents*extent.Val, UnitBytes), nil
+}
+
+// extentSize returns the extent size of the volume group in bytes.
+func (c *client) extentSize(ctx context.Context, vg VolumeGroupName) (Size, error) {
+	report, err := c.VG(ctx, vg, UnitBytes)
+	if err != nil {
+		return Size{}, fmt.Errorf("failed to determine the extent size to align to: %w", err)
+	}
+	return report.ExtentSize, nil
+}
@@ -0,0 +1,81 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestSizeAlignment(t *testing.T) {
+	t.Parallel()
+	extentSize := MustParseSize("4M")
+
+	for name, tc := range map[string]struct {
+		alignment SizeAlignment
+		size      string
+		expected  Size
+		err       error
+	}{
+		"none":                    {alignment: SizeAlignmentNone, size: "30M", expected: MustParseSize("30M")},
+		"round up":                {alignment: SizeAlignmentRoundUp, size: "30M", expected: NewSize(32*1024*1024, UnitBytes)},
+		"round down":              {alignment: SizeAlignmentRoundDown, size: "30M", expected: NewSize(28*1024*1024, UnitBytes)},
+		"round down below extent": {alignment: SizeAlignmentRoundDown, size: "3M", err: ErrSizeNotAligned},
+		"exact":                   {alignment: SizeAlignmentExact, size: "1G", expected: NewSize(1024*1024*1024, UnitBytes)},
+		"exact unaligned":         {alignment: SizeAlignmentExact, size: "30M", err: ErrSizeNotAligned},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			size, err := tc.alignment.Align(MustParseSize(tc.size), extentSize)
+			if !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+			if size != tc.expected {
+				t.Fatalf("expected %s, got %s", tc.expected, size)
+			}
+		})
+	}
+}
+
+func TestLVCreateSizeAlignment(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	vgsArgs, err := VGsOptionsList{VolumeGroupName("vg"), UnitBytes}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	createArgs, err := LVCreateOptionList{VolumeGroupName("vg"), LogicalVolumeName("lv"), NewSize(28*1024*1024, UnitBytes)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{
+		{
+			Command: "lvm",
+			Args:    append([]string{"vgs", "--reportformat", "json"}, vgsArgs.GetRaw()...),
+			Stdout:  `{"report":[{"vg":[{"vg_name":"vg","vg_extent_size":"4194304B"}]}]}`,
+		},
+		{Command: "lvm", Args: append([]string{"lvcreate"}, createArgs.GetRaw()...)},
+	}))
+
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("30M"), SizeAlignmentRoundDown); err != nil {
+		t.Fatal(err)
+	}
+}
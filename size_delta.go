@@ -0,0 +1,96 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSizeDeltaWithAbsoluteSize is returned if a SizeDelta is combined with a PrefixedSize,
+// as it is ambiguous whether the logical volume is resized by or to a size.
+var ErrSizeDeltaWithAbsoluteSize = errors.New("SizeDelta and PrefixedSize are mutually exclusive")
+
+// SizeDelta is a relative change of the size of a logical volume for LVExtend, LVReduce and LVResize,
+// passed as --size=+1.00g or --size=-512.00m. Unlike PrefixedSize, it always has SizePrefixPlus or SizePrefixMinus,
+// so that it cannot be mistaken for an absolute size. Use GrowBy and ShrinkBy to create it.
+// LVExtend only accepts growing and LVReduce only shrinking deltas.
+type SizeDelta PrefixedSize
+
+// GrowBy returns a SizeDelta growing a logical volume by size.
+func GrowBy(size Size) SizeDelta {
+	return SizeDelta(NewPrefixedSize(SizePrefixPlus, size))
+}
+
+// ShrinkBy returns a SizeDelta shrinking a logical volume by size.
+func ShrinkBy(size Size) SizeDelta {
+	return SizeDelta(NewPrefixedSize(SizePrefixMinus, size))
+}
+
+// ParseSizeDelta parses a size with a mandatory + or - prefix, e.g. +1G or -512M.
+func ParseSizeDelta(str string) (SizeDelta, error) {
+	size, err := ParsePrefixedSize(str)
+	if err != nil {
+		return SizeDelta{}, err
+	}
+	delta := SizeDelta(size)
+	return delta, delta.Validate()
+}
+
+func MustParseSizeDelta(str string) SizeDelta {
+	delta, err := ParseSizeDelta(str)
+	if err != nil {
+		panic(err)
+	}
+	return delta
+}
+
+func (opt SizeDelta) Validate() error {
+	if opt.SizePrefix != SizePrefixPlus && opt.SizePrefix != SizePrefixMinus {
+		return fmt.Errorf("%w: a size delta must start with %q or %q", ErrInvalidSizePrefix, SizePrefixPlus, SizePrefixMinus)
+	}
+	if opt.Val <= 0 {
+		return fmt.Errorf("size delta must be greater than zero")
+	}
+	return PrefixedSize(opt).Validate()
+}
+
+func (opt SizeDelta) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.SizeDelta = opt
+}
+
+func (opt SizeDelta) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.SizeDelta = opt
+}
+
+func (opt SizeDelta) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.SizeDelta = opt
+}
+
+// orPrefixedSize returns the delta as PrefixedSize if set, or size otherwise.
+func (opt SizeDelta) orPrefixedSize(size PrefixedSize) (PrefixedSize, error) {
+	if opt == (SizeDelta{}) {
+		return size, nil
+	}
+	if size.Val > 0 {
+		return PrefixedSize{}, ErrSizeDeltaWithAbsoluteSize
+	}
+	if err := opt.Validate(); err != nil {
+		return PrefixedSize{}, err
+	}
+	return PrefixedSize(opt), nil
+}
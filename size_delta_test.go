@@ -0,0 +1,95 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestSizeDelta(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	vgName := VolumeGroupName("vg")
+	lvName := LogicalVolumeName("lv")
+
+	if _, err := ParseSizeDelta("1G"); err == nil {
+		t.Fatal("expected a size delta without prefix to be rejected")
+	}
+	if delta := MustParseSizeDelta("-512M"); delta != ShrinkBy(MustParseSize("512M")) {
+		t.Fatalf("unexpected size delta %v", delta)
+	}
+
+	args, err := LVExtendOptionsList{vgName, lvName, GrowBy(MustParseSize("1G"))}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--size=+1.00g") {
+		t.Fatalf("expected a relative size in %v", args.GetRaw())
+	}
+	args, err = LVResizeOptionsList{vgName, lvName, ShrinkBy(MustParseSize("512M"))}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(args.GetRaw(), "--size=-512.00m") {
+		t.Fatalf("expected a relative size in %v", args.GetRaw())
+	}
+
+	if _, err := (LVResizeOptionsList{vgName, lvName, MustParsePrefixedSize("2G"), GrowBy(MustParseSize("1G"))}).AsArgs(); !errors.Is(err, ErrSizeDeltaWithAbsoluteSize) {
+		t.Fatalf("expected ErrSizeDeltaWithAbsoluteSize, got %v", err)
+	}
+	if _, err := (LVExtendOptionsList{vgName, lvName, ShrinkBy(MustParseSize("1G"))}).AsArgs(); err == nil {
+		t.Fatal("expected LVExtend to reject a shrinking size delta")
+	}
+	if _, err := (LVReduceOptionsList{vgName, lvName, GrowBy(MustParseSize("1G"))}).AsArgs(); err == nil {
+		t.Fatal("expected LVReduce to reject a growing size delta")
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("200M"))
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, vgName, lvName, MustParseSize("40M")); err != nil {
+		t.Fatal(err)
+	}
+	for _, step := range []struct {
+		resize func() error
+		size   Size
+	}{
+		{func() error { return clnt.LVExtend(ctx, vgName, lvName, GrowBy(MustParseSize("40M"))) }, MustParseSize("80M")},
+		{func() error { return clnt.LVReduce(ctx, vgName, lvName, ShrinkBy(MustParseSize("20M"))) }, MustParseSize("60M")},
+		{func() error { return clnt.LVResize(ctx, vgName, lvName, GrowBy(MustParseSize("4M"))) }, MustParseSize("64M")},
+	} {
+		if err := step.resize(); err != nil {
+			t.Fatal(err)
+		}
+		lv, err := clnt.LV(ctx, vgName, lvName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected, _ := step.size.ToUnit(UnitBytes)
+		if actual, _ := lv.Size.ToUnit(UnitBytes); actual.Val != expected.Val {
+			t.Fatalf("expected size %s, got %s", step.size, lv.Size)
+		}
+	}
+}
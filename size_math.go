@@ -0,0 +1,132 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+)
+
+// Add returns the sum of both sizes in the unit of opt.
+func (opt Size) Add(other Size) (Size, error) {
+	val, err := other.valIn(opt.Unit)
+	if err != nil {
+		return InvalidSize, err
+	}
+	return NewSize(opt.Val+val, opt.Unit), nil
+}
+
+// Sub returns the difference of both sizes in the unit of opt.
+// As sizes cannot be negative, subtracting a larger size results in ErrInvalidSizeGEZero.
+func (opt Size) Sub(other Size) (Size, error) {
+	val, err := other.valIn(opt.Unit)
+	if err != nil {
+		return InvalidSize, err
+	}
+	if val > opt.Val {
+		return InvalidSize, fmt.Errorf("%w: cannot subtract %s from %s", ErrInvalidSizeGEZero, other, opt)
+	}
+	return NewSize(opt.Val-val, opt.Unit), nil
+}
+
+// Cmp compares both sizes independent of their units and returns
+// -1 if opt is smaller than other, 0 if they are equal and +1 if opt is larger.
+func (opt Size) Cmp(other Size) (int, error) {
+	val, err := other.valIn(opt.Unit)
+	if err != nil {
+		return 0, err
+	}
+	return cmp.Compare(opt.Val, val), nil
+}
+
+// MulPercent returns percent of the size, e.g. 80 for 80% of a volume group, in the unit of opt.
+func (opt Size) MulPercent(percent float64) (Size, error) {
+	if percent < 0 {
+		return InvalidSize, fmt.Errorf("%w: percent must not be negative, got %v", ErrInvalidSizeGEZero, percent)
+	}
+	return NewSize(opt.Val*percent/100, opt.Unit), nil
+}
+
+// valIn returns the value of the size converted to unit.
+// Sizes with UnitUnknown can only be combined with each other.
+func (opt Size) valIn(unit Unit) (float64, error) {
+	if opt.Unit == unit {
+		return opt.Val, nil
+	}
+	if unit == UnitUnknown {
+		return 0, fmt.Errorf("%w: %s cannot be combined with a size without unit", ErrInvalidUnit, opt)
+	}
+	converted, err := opt.ToUnit(unit)
+	if err != nil {
+		return 0, err
+	}
+	return converted.Val, nil
+}
+
+// ExtentsFor returns the number of extents of the volume group needed to hold size,
+// rounding up to a whole extent like lvm2 does on allocation.
+func (vg *VolumeGroup) ExtentsFor(size Size) (Extents, error) {
+	extentSize, err := vg.extentSizeInBytes()
+	if err != nil {
+		return Extents{}, err
+	}
+	return size.ToExtents(extentSize, "")
+}
+
+// SizeOf returns the size of the extents in the volume group in bytes.
+// Extents relative to the volume group or its free space are resolved against its size or free size.
+func (vg *VolumeGroup) SizeOf(extents Extents) (Size, error) {
+	extentSize, err := vg.extentSizeInBytes()
+	if err != nil {
+		return InvalidSize, err
+	}
+	switch extents.ExtentPercent {
+	case "":
+		return extents.ToSize(extentSize), nil
+	case ExtentPercentVG:
+		return vg.percentOf(vg.Size, extents.Val)
+	case ExtentPercentFree:
+		return vg.percentOf(vg.Free, extents.Val)
+	default:
+		return InvalidSize, fmt.Errorf("%w: %s cannot be resolved against a volume group", ErrInvalidPercentDefinition, extents.ExtentPercent)
+	}
+}
+
+func (vg *VolumeGroup) percentOf(size Size, percent uint64) (Size, error) {
+	bytes, err := size.ToUnit(UnitBytes)
+	if err != nil {
+		return InvalidSize, err
+	}
+	extentSize, err := vg.extentSizeInBytes()
+	if err != nil {
+		return InvalidSize, err
+	}
+	extents := math.Floor(bytes.Val * float64(percent) / 100 / float64(extentSize))
+	return NewSize(extents*float64(extentSize), UnitBytes), nil
+}
+
+func (vg *VolumeGroup) extentSizeInBytes() (uint64, error) {
+	extentSize, err := vg.ExtentSize.ToUnit(UnitBytes)
+	if err != nil {
+		return 0, err
+	}
+	if extentSize.Val <= 0 {
+		return 0, fmt.Errorf("volume group %s has no extent size", vg.Name)
+	}
+	return uint64(extentSize.Val), nil
+}
@@ -0,0 +1,115 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestSizeArithmetic(t *testing.T) {
+	t.Parallel()
+
+	sum, err := MustParseSize("1G").Add(MustParseSize("512M"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != NewSize(1.5, UnitGiB) {
+		t.Fatalf("expected 1.50g, got %s", sum)
+	}
+
+	diff, err := MustParseSize("1G").Sub(MustParseSize("256M"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != NewSize(0.75, UnitGiB) {
+		t.Fatalf("expected 0.75g, got %s", diff)
+	}
+	if _, err := MustParseSize("256M").Sub(MustParseSize("1G")); !errors.Is(err, ErrInvalidSizeGEZero) {
+		t.Fatalf("expected ErrInvalidSizeGEZero, got %v", err)
+	}
+
+	for _, tc := range []struct {
+		a, b     Size
+		expected int
+	}{
+		{MustParseSize("1G"), MustParseSize("1024M"), 0},
+		{MustParseSize("1G"), MustParseSize("1025M"), -1},
+		{MustParseSize("2048s"), MustParseSize("512K"), 1},
+	} {
+		if res, err := tc.a.Cmp(tc.b); err != nil {
+			t.Fatal(err)
+		} else if res != tc.expected {
+			t.Fatalf("expected %s compared to %s to be %d, got %d", tc.a, tc.b, tc.expected, res)
+		}
+	}
+	if _, err := MustParseSize("1G").Cmp(MustParseSize("1024")); !errors.Is(err, ErrInvalidUnit) {
+		t.Fatalf("expected ErrInvalidUnit for a size without unit, got %v", err)
+	}
+
+	eighty, err := MustParseSize("10G").MulPercent(80)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if eighty != NewSize(8, UnitGiB) {
+		t.Fatalf("expected 8.00g, got %s", eighty)
+	}
+}
+
+func TestVolumeGroupExtentConversion(t *testing.T) {
+	t.Parallel()
+
+	vg := &VolumeGroup{
+		Name:       "vg",
+		ExtentSize: MustParseSize("4M"),
+		Size:       MustParseSize("100M"),
+		Free:       MustParseSize("40M"),
+	}
+
+	extents, err := vg.ExtentsFor(MustParseSize("9M"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if extents.Val != 3 {
+		t.Fatalf("expected 9M to round up to 3 extents, got %d", extents.Val)
+	}
+
+	for _, tc := range []struct {
+		extents  Extents
+		expected Size
+	}{
+		{NewExtents(3, ""), MustParseSize("12M")},
+		{NewExtents(50, ExtentPercentVG), MustParseSize("48M")},
+		{NewExtents(100, ExtentPercentFree), MustParseSize("40M")},
+	} {
+		size, err := vg.SizeOf(tc.extents)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if res, _ := size.Cmp(tc.expected); res != 0 {
+			t.Fatalf("expected %v to be %s, got %s", tc.extents, tc.expected, size)
+		}
+	}
+	if _, err := vg.SizeOf(NewExtents(10, ExtentPercentOrigin)); !errors.Is(err, ErrInvalidPercentDefinition) {
+		t.Fatalf("expected ErrInvalidPercentDefinition, got %v", err)
+	}
+	if _, err := (&VolumeGroup{Name: "vg"}).ExtentsFor(MustParseSize("1M")); err == nil {
+		t.Fatal("expected an error without extent size")
+	}
+}
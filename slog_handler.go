@@ -27,24 +27,18 @@ import (
 	"time"
 )
 
-type contextKey string
-
-var (
-	fields contextKey = "slog_fields"
-)
-
 func WithValue(parent context.Context, key string, val any) context.Context {
 	if parent == nil {
 		panic("cannot create context from nil parent")
 	}
-	if v, ok := parent.Value(fields).(*sync.Map); ok {
+	if v, ok := parent.Value(logFieldsKey).(*sync.Map); ok {
 		mapCopy := copySyncMap(v)
 		mapCopy.Store(key, val)
-		return context.WithValue(parent, fields, mapCopy)
+		return context.WithValue(parent, logFieldsKey, mapCopy)
 	}
 	v := &sync.Map{}
 	v.Store(key, val)
-	return context.WithValue(parent, fields, v)
+	return context.WithValue(parent, logFieldsKey, v)
 }
 
 func copySyncMap(m *sync.Map) *sync.Map {
@@ -145,7 +139,7 @@ func (h *ContextPropagatingSlogHandler) Enabled(ctx context.Context, level slog.
 }
 
 func (h *ContextPropagatingSlogHandler) Handle(ctx context.Context, record slog.Record) error {
-	if v, ok := ctx.Value(fields).(*sync.Map); ok {
+	if v, ok := ctx.Value(logFieldsKey).(*sync.Map); ok {
 		v.Range(func(key, val any) bool {
 			if keyString, ok := key.(string); ok {
 				record.AddAttrs(slog.Any(keyString, val))
@@ -0,0 +1,85 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// SnapshotOf is the origin logical volume of a snapshot created with LVCreate.
+// The VolumeGroupName of the snapshot is implied by the origin.
+type SnapshotOf FQLogicalVolumeName
+
+func (opt *SnapshotOf) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.SnapshotOf = opt
+}
+
+func (opt *SnapshotOf) ApplyToArgs(args Arguments) error {
+	if opt == nil {
+		return nil
+	}
+
+	if err := (*FQLogicalVolumeName)(opt).Validate(); err != nil {
+		return err
+	}
+
+	args.AddOrReplaceAll([]string{
+		"--snapshot",
+		fmt.Sprintf("%s/%s", opt.VolumeGroupName, opt.LogicalVolumeName),
+	})
+
+	return nil
+}
+
+func MustNewSnapshotOf(vg VolumeGroupName, lv LogicalVolumeName) *SnapshotOf {
+	origin, err := NewSnapshotOf(vg, lv)
+	if err != nil {
+		panic(err)
+	}
+	return origin
+}
+
+func NewSnapshotOf(vg VolumeGroupName, lv LogicalVolumeName) (*SnapshotOf, error) {
+	fq, err := NewFQLogicalVolumeName(vg, lv)
+	if err != nil {
+		return nil, err
+	}
+	return (*SnapshotOf)(fq), nil
+}
+
+// LVCreateSnapshot creates the snapshot name of the origin logical volume.
+//
+// If size is set, a copy-on-write snapshot with that much space for changed blocks is created
+// (lvcreate --snapshot --size). Copy-on-write snapshots become invalid once the space is used up,
+// which can be monitored with LogicalVolume.SnapPercent.
+// If size is zero, the origin has to be a thin volume and a thin snapshot is created in the thin pool of the origin.
+// Thin snapshots are flagged to be skipped on activation by lvm2, see IgnoreActivationSkip.
+//
+// Further options such as Tags or SuspendIO are passed on to LVCreate.
+func LVCreateSnapshot(ctx context.Context, client Client, origin *FQLogicalVolumeName, name LogicalVolumeName, size Size, opts ...LVCreateOption) error {
+	snapshotOf, err := NewSnapshotOf(origin.VolumeGroupName, origin.LogicalVolumeName)
+	if err != nil {
+		return err
+	}
+	opts = append(slices.Clone(opts), name, snapshotOf)
+	if size.Val > 0 {
+		opts = append(opts, size)
+	}
+	return client.LVCreate(ctx, opts...)
+}
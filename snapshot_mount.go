@@ -0,0 +1,204 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var (
+	// DefaultSnapshotDeviceTimeout is the maximum time MountSnapshotReadOnly waits
+	// for the device node of an activated snapshot to appear.
+	DefaultSnapshotDeviceTimeout = 10 * time.Second
+	// DefaultSnapshotDevicePollInterval is the interval in which the device node is checked.
+	DefaultSnapshotDevicePollInterval = 100 * time.Millisecond
+)
+
+var ErrNoFilesystemOnSnapshot = errors.New("no filesystem found on snapshot")
+
+// SnapshotMountOptions returns the mount options used to mount a snapshot with the given filesystem read-only.
+// Snapshots of mounted filesystems contain a dirty journal, so journal recovery is disabled
+// for xfs (norecovery) and ext3/ext4 (noload), which would otherwise refuse to mount or write to the snapshot.
+// xfs additionally refuses to mount a filesystem with the UUID of a mounted one, which is ignored with nouuid.
+func SnapshotMountOptions(fsType string) []string {
+	switch fsType {
+	case "xfs":
+		return []string{"ro", "nouuid", "norecovery"}
+	case "ext3", "ext4":
+		return []string{"ro", "noload"}
+	default:
+		return []string{"ro"}
+	}
+}
+
+// SnapshotMount is a snapshot mounted with MountSnapshotReadOnly.
+// It has to be released with Unmount after use.
+type SnapshotMount struct {
+	Snapshot *FQLogicalVolumeName
+	Device   string
+	Target   string
+	FSType   string
+
+	client        Client
+	runner        RawCommandRunner
+	activated     bool
+	createdTarget bool
+}
+
+// MountSnapshotReadOnly mounts the filesystem of a snapshot read-only at target for backups.
+// If the snapshot is not active, it is activated, ignoring the activation skip flag of thin snapshots.
+// The target directory is created if it does not exist.
+// If mounting fails, the snapshot is deactivated again if it was activated.
+//
+// Every step is run through the client like lvm2, so the client has to be able to run raw commands:
+// the device node is awaited with test, the target is created with mkdir and removed with rmdir,
+// and the filesystem is probed with blkid and mounted with mount. The target is therefore a path
+// in the namespaces the client runs lvm2 in, e.g. on the host if the client uses nsenter.
+// See SnapshotMountOptions for the mount options used for the filesystem.
+//
+// Example:
+//
+//	snapshot, _ := lvm2go.NewFQLogicalVolumeName("vg", "snap")
+//	mount, err := lvm2go.MountSnapshotReadOnly(ctx, client, snapshot, "/mnt/backup")
+//	if err != nil {
+//		return err
+//	}
+//	defer mount.Unmount(ctx)
+func MountSnapshotReadOnly(ctx context.Context, client Client, snapshot *FQLogicalVolumeName, target string) (*SnapshotMount, error) {
+	runner, device, err := filesystemCommandTarget(ctx, client, snapshot, false)
+	if err != nil {
+		return nil, err
+	}
+	lv, err := client.LV(ctx, snapshot.VolumeGroupName, snapshot.LogicalVolumeName)
+	if err != nil {
+		return nil, err
+	}
+
+	mount := &SnapshotMount{
+		Snapshot: snapshot,
+		Device:   lv.Path,
+		Target:   target,
+		client:   client,
+		runner:   runner,
+	}
+	if mount.Device == "" {
+		mount.Device = device
+	}
+
+	if lv.Attr.State != StateActive {
+		if err := client.LVChange(ctx, snapshot.VolumeGroupName, snapshot.LogicalVolumeName,
+			Activate, IgnoreActivationSkip(lv.Attr.SkipActivation == SkipActivationTrue)); err != nil {
+			return nil, fmt.Errorf("failed to activate snapshot %s: %w", snapshot, err)
+		}
+		mount.activated = true
+	}
+
+	if err := mount.mount(ctx); err != nil {
+		return nil, errors.Join(err, mount.release(ctx))
+	}
+
+	return mount, nil
+}
+
+func (m *SnapshotMount) mount(ctx context.Context) error {
+	if err := waitForDevice(ctx, m.runner, m.Device); err != nil {
+		return err
+	}
+
+	fsType, err := blkidValue(ctx, m.runner, "TYPE", m.Device)
+	if err != nil || fsType == "" {
+		return errors.Join(fmt.Errorf("%w: %s", ErrNoFilesystemOnSnapshot, m.Snapshot), err)
+	}
+	m.FSType = fsType
+
+	// test exits with 1 if the target does not exist
+	if err := m.runner.RunRaw(ctx, NoOpRawOutputProcessor(), "test", "-e", m.Target); err != nil {
+		if exitErr, ok := AsExitCodeError(err); !ok || exitErr.ExitCode() != 1 {
+			return fmt.Errorf("failed to check mount target %s: %w", m.Target, err)
+		}
+		if err := m.runner.RunRaw(ctx, NoOpRawOutputProcessor(), "mkdir", "-p", m.Target); err != nil {
+			return fmt.Errorf("failed to create mount target %s: %w", m.Target, err)
+		}
+		m.createdTarget = true
+	}
+
+	if err := m.runner.RunRaw(ctx, NoOpRawOutputProcessor(), "mount",
+		"-t", m.FSType,
+		"-o", strings.Join(SnapshotMountOptions(m.FSType), ","),
+		m.Device, m.Target,
+	); err != nil {
+		return fmt.Errorf("failed to mount snapshot %s at %s: %w", m.Snapshot, m.Target, err)
+	}
+	return nil
+}
+
+// Unmount unmounts the snapshot and reverts everything done by MountSnapshotReadOnly:
+// the snapshot is deactivated if it was activated, and the target is removed if it was created.
+func (m *SnapshotMount) Unmount(ctx context.Context) error {
+	if err := m.runner.RunRaw(ctx, NoOpRawOutputProcessor(), "umount", m.Target); err != nil {
+		return fmt.Errorf("failed to unmount snapshot %s from %s: %w", m.Snapshot, m.Target, err)
+	}
+	return m.release(ctx)
+}
+
+// release deactivates the snapshot and removes the target if they were changed by MountSnapshotReadOnly.
+func (m *SnapshotMount) release(ctx context.Context) error {
+	var errs []error
+	if m.createdTarget {
+		if err := m.runner.RunRaw(ctx, NoOpRawOutputProcessor(), "rmdir", m.Target); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove mount target %s: %w", m.Target, err))
+		}
+		m.createdTarget = false
+	}
+	if m.activated {
+		if err := m.client.LVChange(ctx, m.Snapshot.VolumeGroupName, m.Snapshot.LogicalVolumeName, Deactivate); err != nil {
+			errs = append(errs, fmt.Errorf("failed to deactivate snapshot %s: %w", m.Snapshot, err))
+		}
+		m.activated = false
+	}
+	return errors.Join(errs...)
+}
+
+// waitForDevice waits until the device node exists, e.g. after udev processed the activation of a logical volume.
+// The device node is checked with test -b through the runner, so that it is awaited where it is mounted.
+func waitForDevice(ctx context.Context, runner RawCommandRunner, device string) error {
+	ctx, cancel := context.WithTimeout(ctx, DefaultSnapshotDeviceTimeout)
+	defer cancel()
+
+	ticker := time.NewTicker(DefaultSnapshotDevicePollInterval)
+	defer ticker.Stop()
+
+	for {
+		// test exits with 1 if the device node does not exist (yet)
+		err := runner.RunRaw(ctx, NoOpRawOutputProcessor(), "test", "-b", device)
+		if err == nil {
+			return nil
+		}
+		if exitErr, ok := AsExitCodeError(err); (!ok || exitErr.ExitCode() != 1) && ctx.Err() == nil {
+			return fmt.Errorf("failed to check device %s: %w", device, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("device %s did not appear: %w", device, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
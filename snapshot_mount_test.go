@@ -0,0 +1,187 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestMountSnapshotReadOnly(t *testing.T) {
+	SkipOrFailTestIfNotRoot(t)
+	if _, err := exec.LookPath("mkfs.xfs"); err != nil {
+		t.Skip("Skipping test because mkfs.xfs is not available.")
+	}
+	ctx := context.Background()
+	clnt := GetTestClient(ctx)
+
+	infra := test{
+		LoopDevices: []Size{MustParseSize("1G")},
+		Volumes:     []TestLogicalVolume{{Options: LVCreateOptionList{MustParseSize("300M")}}},
+	}.SetupDevicesAndVolumeGroup(t)
+	vg := infra.volumeGroup.Name
+	origin := infra.lvs[0].LogicalVolumeName()
+
+	originDevice := filepath.Join("/dev", string(vg), string(origin))
+	if out, err := exec.Command("mkfs.xfs", "-q", originDevice).CombinedOutput(); err != nil {
+		t.Fatalf("failed to create filesystem: %v: %s", err, out)
+	}
+
+	// the origin stays mounted, so that the snapshot has the same xfs UUID as a mounted filesystem
+	originTarget := t.TempDir()
+	if out, err := exec.Command("mount", originDevice, originTarget).CombinedOutput(); err != nil {
+		t.Fatalf("failed to mount origin: %v: %s", err, out)
+	}
+	t.Cleanup(func() {
+		_ = exec.Command("umount", originTarget).Run()
+	})
+	if err := os.WriteFile(filepath.Join(originTarget, "data"), []byte("backup"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := exec.Command("sync", "-f", originTarget).CombinedOutput(); err != nil {
+		t.Fatalf("failed to sync origin: %v: %s", err, out)
+	}
+
+	snapshotOf, err := NewSnapshotOf(vg, origin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshotName := LogicalVolumeName("snap")
+	if err := clnt.LVCreate(ctx, snapshotOf, snapshotName, MustParseSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := clnt.LVRemove(ctx, vg, snapshotName); err != nil && !IsSkippableErrorForCleanup(err) {
+			t.Error(err)
+		}
+	})
+
+	snapshot, err := NewFQLogicalVolumeName(vg, snapshotName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(t.TempDir(), "snapshot")
+	mount, err := MountSnapshotReadOnly(ctx, clnt, snapshot, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mount.FSType != "xfs" {
+		t.Fatalf("expected xfs filesystem, got %q", mount.FSType)
+	}
+
+	data, err := os.ReadFile(filepath.Join(target, "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "backup" {
+		t.Fatalf("unexpected data in snapshot: %q", data)
+	}
+	if err := os.WriteFile(filepath.Join(target, "data"), []byte("changed"), 0o644); err == nil {
+		t.Fatal("expected snapshot to be mounted read-only")
+	}
+
+	if err := mount.Unmount(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(target); !os.IsNotExist(err) {
+		t.Fatalf("expected created target to be removed, got %v", err)
+	}
+}
+
+func TestMountSnapshotReadOnlyThroughClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	// every step runs through the client, so neither the device nor the target exist for the test
+	device := "/dev/vg/snap"
+	target := "/mnt/snapshot"
+	replay := ReplayCommandRunner([]CommandRecording{
+		{Command: "test", Args: []string{"-b", device}},
+		{Command: "blkid", Args: []string{"-p", "-o", "value", "-s", "TYPE", device}, Stdout: "xfs\n"},
+		{Command: "test", Args: []string{"-e", target}, ExitCode: 1},
+		{Command: "mkdir", Args: []string{"-p", target}},
+		{Command: "mount", Args: []string{"-t", "xfs", "-o", "ro,nouuid,norecovery", device, target}},
+		{Command: "umount", Args: []string{target}},
+		{Command: "rmdir", Args: []string{target}},
+	})
+
+	var commands []string
+	clnt := NewClient(NsenterPolicyNever, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		if slices.Contains(cmd.Args, "lvs") {
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[
+				{"lv_name":"snap", "vg_name":"vg", "lv_attr":"swi-a-s---", "lv_path":"` + device + `"}
+			]}]}`)), nil
+		}
+		commands = append(commands, strings.Join(cmd.Args, " "))
+		return replay(ctx, cmd)
+	}))
+
+	snapshot, err := NewFQLogicalVolumeName("vg", "snap")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mount, err := MountSnapshotReadOnly(ctx, clnt, snapshot, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mount.FSType != "xfs" {
+		t.Fatalf("expected xfs filesystem, got %q", mount.FSType)
+	}
+	if err := mount.Unmount(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"test -b " + device,
+		"blkid -p -o value -s TYPE " + device,
+		"test -e " + target,
+		"mkdir -p " + target,
+		"mount -t xfs -o ro,nouuid,norecovery " + device + " " + target,
+		"umount " + target,
+		"rmdir " + target,
+	}
+	if !slices.Equal(commands, expected) {
+		t.Fatalf("expected commands %v, got %v", expected, commands)
+	}
+
+	if _, err := MountSnapshotReadOnly(ctx, NewDBusClient(), snapshot, target); !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected unsupported client, got %v", err)
+	}
+}
+
+func TestSnapshotMountOptions(t *testing.T) {
+	t.Parallel()
+	for fsType, expected := range map[string]string{
+		"xfs":   "ro,nouuid,norecovery",
+		"ext4":  "ro,noload",
+		"ext3":  "ro,noload",
+		"btrfs": "ro",
+	} {
+		if options := strings.Join(SnapshotMountOptions(fsType), ","); options != expected {
+			t.Errorf("expected %q for %s, got %q", expected, fsType, options)
+		}
+	}
+}
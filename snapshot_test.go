@@ -0,0 +1,80 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestLVCreateSnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var calls [][]string
+	clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		calls = append(calls, cmd.Args)
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+	origin := MustNewFQLogicalVolumeName("vg", "origin")
+
+	if err := LVCreateSnapshot(ctx, clnt, origin, LogicalVolumeName("cow"), MustParseSize("1G")); err != nil {
+		t.Fatal(err)
+	}
+	if err := LVCreateSnapshot(ctx, clnt, origin, LogicalVolumeName("thin"), Size{}, Tags{"backup"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := LVCreateSnapshot(ctx, clnt, &FQLogicalVolumeName{LogicalVolumeName: "origin"}, LogicalVolumeName("snap"), Size{}); err == nil {
+		t.Fatal("expected error for origin without volume group")
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("expected 2 commands, got %v", calls)
+	}
+	hasSize := func(args []string) bool {
+		return slices.ContainsFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--size") })
+	}
+	for i, snapshot := range []string{"cow", "thin"} {
+		if !slices.Contains(calls[i], "--name="+snapshot) || !slices.Contains(calls[i], "--snapshot") || !slices.Contains(calls[i], "vg/origin") {
+			t.Fatalf("expected snapshot %s of vg/origin, got %v", snapshot, calls[i])
+		}
+	}
+	if !hasSize(calls[0]) {
+		t.Fatalf("expected size for copy-on-write snapshot, got %v", calls[0])
+	}
+	if hasSize(calls[1]) || !slices.Contains(calls[1], "--addtag") {
+		t.Fatalf("expected thin snapshot with tag, got %v", calls[1])
+	}
+}
+
+func TestLogicalVolumeSnapPercent(t *testing.T) {
+	t.Parallel()
+	var lv LogicalVolume
+	if err := json.Unmarshal([]byte(`{"lv_name":"snap","origin":"origin","snap_percent":"12.50"}`), &lv); err != nil {
+		t.Fatal(err)
+	}
+	if lv.Origin != "origin" || lv.SnapPercent != 12.5 {
+		t.Fatalf("unexpected snapshot %v", lv)
+	}
+}
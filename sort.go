@@ -0,0 +1,63 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortFields order a report by the given fields, e.g. SortBy(VGName, LVSize.Descending()).
+// Sorting is done by lvm, so large listings do not have to be sorted again by the caller.
+type SortFields []ReportField
+
+// SortBy orders a report by the given fields. Fields are sorted in ascending order unless they are Descending.
+func SortBy(fields ...ReportField) SortFields {
+	return fields
+}
+
+// Descending sorts by the field in descending order.
+func (field ReportField) Descending() ReportField {
+	return "-" + field
+}
+
+func (opt SortFields) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.SortFields = opt
+}
+
+func (opt SortFields) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.SortFields = opt
+}
+
+func (opt SortFields) ApplyToPVsOptions(opts *PVsOptions) {
+	opts.SortFields = opt
+}
+
+func (opt SortFields) ApplyToArgs(args Arguments) error {
+	if len(opt) == 0 {
+		return nil
+	}
+	fields := make([]string, len(opt))
+	for i, field := range opt {
+		if name := strings.TrimPrefix(string(field), "-"); name == "" || strings.ContainsAny(name, ", -") {
+			return fmt.Errorf("invalid sort field %q", field)
+		}
+		fields[i] = string(field)
+	}
+	args.AddOrReplaceAll([]string{"--sort", strings.Join(fields, ",")})
+	return nil
+}
@@ -0,0 +1,49 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestSortBy(t *testing.T) {
+	t.Parallel()
+	args, err := LVsOptionsList{SortBy(VGName, LVSize.Descending())}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := args.GetRaw()
+	if idx := slices.Index(raw, "--sort"); idx < 0 || raw[idx+1] != "vg_name,-lv_size" {
+		t.Fatalf("unexpected args %v", raw)
+	}
+
+	for _, invalid := range []ReportField{"", "-", "lv_name,lv_size"} {
+		if _, err := (PVsOptionsList{SortBy(invalid)}).AsArgs(); err == nil {
+			t.Errorf("expected error for sort field %q", invalid)
+		}
+	}
+
+	if _, err := fake.NewClient().VGs(context.Background(), SortBy(VGFree)); !errors.Is(err, fake.ErrUnsupported) {
+		t.Fatalf("expected sort to be unsupported by the fake client, got %v", err)
+	}
+}
@@ -0,0 +1,211 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+var (
+	stressDuration = flag.Duration("stress-duration", 0, "Duration of the stress test, the stress test is skipped if zero")
+	stressWorkers  = flag.Int("stress-workers", 4, "Number of concurrent workers in the stress test")
+	stressSeed     = flag.Int64("stress-seed", 0, "Seed for the randomized operations of the stress test, a random seed is used if zero")
+)
+
+// stressMaxExtents bounds the growth of a single logical volume so that the volume group does not run full.
+const stressMaxExtents = 32
+
+// stressModel is the expected state of the logical volumes in the stress test.
+// Sizes are tracked in extents of TestExtentBytes.
+type stressModel struct {
+	mu      sync.Mutex
+	volumes map[LogicalVolumeName]uint64
+}
+
+func (m *stressModel) set(name LogicalVolumeName, extents uint64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if extents == 0 {
+		delete(m.volumes, name)
+	} else {
+		m.volumes[name] = extents
+	}
+}
+
+func (m *stressModel) get(name LogicalVolumeName) (uint64, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	extents, ok := m.volumes[name]
+	return extents, ok
+}
+
+// TestStress runs randomized concurrent create, resize and remove operations against a loopback backed
+// volume group through a locking client and verifies the state of each logical volume after every step.
+// After all workers finished, the volume group is verified against the expected state as a whole.
+//
+// The test is skipped unless -stress-duration is set, e.g.
+//
+//	go test -run TestStress -stress-duration=5m -stress-workers=8
+func TestStress(t *testing.T) {
+	if *stressDuration == 0 {
+		t.Skip("Skipping stress test because -stress-duration is not set.")
+	}
+	SkipOrFailTestIfNotRoot(t)
+
+	seed := *stressSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	t.Logf("running stress test with seed %d", seed)
+
+	infra := test{LoopDevices: []Size{MustParseSize("1G")}}.SetupDevicesAndVolumeGroup(t)
+	vgName := infra.volumeGroup.Name
+
+	ctx, cancel := context.WithTimeout(context.Background(), *stressDuration)
+	defer cancel()
+	clnt := NewLockingClient(NewClient())
+	model := &stressModel{volumes: make(map[LogicalVolumeName]uint64)}
+
+	t.Cleanup(func() {
+		for name := range model.volumes {
+			if err := clnt.LVRemove(context.Background(), vgName, name); err != nil && !IsNotFound(err) {
+				t.Logf("failed to remove logical volume %s: %v", name, err)
+			}
+		}
+	})
+
+	var wg sync.WaitGroup
+	for worker := range *stressWorkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed + int64(worker)))
+			// each worker owns its logical volumes, so that the model of a volume is only changed by one worker
+			names := make([]LogicalVolumeName, 4)
+			for i := range names {
+				names[i] = LogicalVolumeName(fmt.Sprintf("stress-%d-%d", worker, i))
+			}
+			for step := 0; ctx.Err() == nil; step++ {
+				name := names[rng.Intn(len(names))]
+				if err := stressStep(ctx, clnt, rng, model, vgName, name); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+					t.Errorf("worker %d failed at step %d on %s: %v", worker, step, name, err)
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if t.Failed() {
+		return
+	}
+
+	verifyCtx := context.Background()
+	lvs, err := clnt.LVs(verifyCtx, vgName, UnitBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lvs) != len(model.volumes) {
+		t.Fatalf("expected %d logical volumes, got %d", len(model.volumes), len(lvs))
+	}
+	var used uint64
+	for _, lv := range lvs {
+		extents, ok := model.volumes[lv.Name]
+		if !ok {
+			t.Fatalf("unexpected logical volume %s", lv.Name)
+		}
+		if uint64(lv.Size.Val) != extents*TestExtentBytes {
+			t.Fatalf("expected logical volume %s to have size %d, got %s", lv.Name, extents*TestExtentBytes, lv.Size)
+		}
+		used += extents * TestExtentBytes
+	}
+	vg, err := clnt.VG(verifyCtx, vgName, UnitBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uint64(vg.Size.Val-vg.Free.Val) != used {
+		t.Fatalf("expected %d bytes used in volume group, got %v", used, vg.Size.Val-vg.Free.Val)
+	}
+}
+
+// stressStep runs a random operation on the logical volume and verifies the result against the model.
+func stressStep(
+	ctx context.Context,
+	clnt Client,
+	rng *rand.Rand,
+	model *stressModel,
+	vgName VolumeGroupName,
+	name LogicalVolumeName,
+) error {
+	extents, exists := model.get(name)
+	delta := uint64(rng.Intn(4) + 1)
+	size := func(extents uint64) Size {
+		return NewSize(float64(extents*TestExtentBytes), UnitBytes)
+	}
+
+	var err error
+	switch op := rng.Intn(4); {
+	case !exists:
+		if err = clnt.LVCreate(ctx, vgName, name, size(delta)); err == nil {
+			extents = delta
+		}
+	case op == 0:
+		if err = clnt.LVRemove(ctx, vgName, name); err == nil {
+			extents = 0
+		}
+	case op == 1 && extents > delta, extents > stressMaxExtents:
+		if err = clnt.LVResize(ctx, vgName, name, NewPrefixedSize(SizePrefixMinus, size(delta))); err == nil {
+			extents -= delta
+		}
+	default:
+		if err = clnt.LVResize(ctx, vgName, name, NewPrefixedSize(SizePrefixPlus, size(delta))); err == nil {
+			extents += delta
+		}
+	}
+	if err != nil {
+		return err
+	}
+	model.set(name, extents)
+
+	lv, err := clnt.LV(ctx, vgName, name, UnitBytes)
+	if extents == 0 {
+		if !errors.Is(err, ErrLogicalVolumeNotFound) {
+			return fmt.Errorf("expected logical volume to be removed, got %v", err)
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if uint64(lv.Size.Val) != extents*TestExtentBytes {
+		return fmt.Errorf("expected size %d, got %s", extents*TestExtentBytes, lv.Size)
+	}
+	return nil
+}
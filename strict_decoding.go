@@ -0,0 +1,116 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+var (
+	// ErrUnknownReportField is reported by StrictDecoding for a report field that is not covered by the typed struct.
+	ErrUnknownReportField = errors.New("unknown report field")
+	// ErrMissingReportField is reported by StrictDecoding for a field of the typed struct that is missing in the report.
+	ErrMissingReportField = errors.New("missing report field")
+)
+
+// ReportFieldError is returned for a field of a lvm report that cannot be decoded into the typed structs
+// LogicalVolume, VolumeGroup or PhysicalVolume. Value is the raw JSON value of the field.
+type ReportFieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *ReportFieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("report field %s: %v", e.Field, e.Err)
+	}
+	return fmt.Sprintf("report field %s with value %s: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *ReportFieldError) Unwrap() error {
+	return e.Err
+}
+
+// StrictDecoding makes the reports of LVs, VGs and PVs fail with a ReportFieldError instead of
+// silently decoding zero values, so that lvm versions which renamed, dropped or reformatted
+// a report field are caught in CI rather than in production:
+//
+//   - Reports without ColumnOptions request exactly the fields of the typed struct instead of
+//     the *_all columns. Fields that are unknown to lvm fail the command itself, fields that
+//     are missing in the report fail with ErrMissingReportField.
+//   - Fields that are not covered by the typed struct fail with ErrUnknownReportField,
+//     unless they are requested with RetainRawJSON.
+//
+// Values that cannot be parsed always fail with a ReportFieldError.
+type StrictDecoding bool
+
+func (opt StrictDecoding) ApplyToClientOptions(opts *ClientOptions) {
+	opts.StrictDecoding = opt
+}
+
+// reportFields returns the report fields decoded into T, as declared by its json tags.
+func reportFields[T any]() ColumnOptions {
+	var fields ColumnOptions
+	typ := reflect.TypeFor[T]()
+	for i := range typ.NumField() {
+		if name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// verifyReportFields checks the raw JSON of a reported object against the fields of the typed struct.
+func verifyReportFields(raw RawJSON, fields ColumnOptions, requireAll, allowUnknown bool) error {
+	var reported map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &reported); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(reported))
+	for field := range reported {
+		names = append(names, field)
+	}
+	// map iteration is random, the fields are sorted to get stable errors
+	slices.Sort(names)
+
+	var errs []error
+	for _, field := range names {
+		if !allowUnknown && !slices.Contains(fields, field) {
+			errs = append(errs, &ReportFieldError{Field: field, Value: string(reported[field]), Err: ErrUnknownReportField})
+		}
+	}
+	if requireAll {
+		for _, field := range fields {
+			if _, ok := reported[field]; !ok {
+				errs = append(errs, &ReportFieldError{Field: field, Err: ErrMissingReportField})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// newReportFieldError wraps an error decoding the raw value of a report field.
+func newReportFieldError(field string, value json.RawMessage, err error) error {
+	return &ReportFieldError{Field: field, Value: string(value), Err: err}
+}
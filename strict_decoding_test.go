@@ -0,0 +1,148 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+// reportRunner answers lvs with a logical volume that reports every requested column,
+// modified by the given function.
+func reportRunner(modify func(lv map[string]string)) CommandRunner {
+	return func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		lv := map[string]string{}
+		if idx := slices.Index(cmd.Args, "--options"); idx > -1 {
+			for _, column := range strings.Split(cmd.Args[idx+1], ",") {
+				lv[column] = ""
+			}
+		}
+		lv["lv_name"], lv["vg_name"], lv["lv_full_name"] = "lv", "vg", "vg/lv"
+		modify(lv)
+		report, err := json.Marshal(map[string]any{"report": []any{map[string]any{"lv": []any{lv}}}})
+		return io.NopCloser(strings.NewReader(string(report))), err
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		modify func(lv map[string]string)
+		opts   []LVsOption
+		field  string
+		err    error
+	}{
+		"complete": {
+			modify: func(lv map[string]string) {},
+		},
+		"missing": {
+			modify: func(lv map[string]string) { delete(lv, "snap_percent") },
+			field:  "snap_percent",
+			err:    ErrMissingReportField,
+		},
+		"unknown": {
+			modify: func(lv map[string]string) { lv["lv_read_ahead"] = "auto" },
+			opts:   []LVsOption{ColumnOptions{"lv_name", "vg_name", "lv_read_ahead"}},
+			field:  "lv_read_ahead",
+			err:    ErrUnknownReportField,
+		},
+		"extra": {
+			modify: func(lv map[string]string) { lv["vg_free"] = "1.00g" },
+			opts:   []LVsOption{ExtraColumns{"vg_free"}},
+		},
+		"unknown retained": {
+			modify: func(lv map[string]string) { lv["lv_read_ahead"] = "auto" },
+			opts:   []LVsOption{ColumnOptions{"lv_name", "vg_name", "lv_read_ahead"}, RetainRawJSON(true)},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			clnt := NewClient(StrictDecoding(true), reportRunner(tc.modify))
+			lvs, err := clnt.LVs(ctx, tc.opts...)
+			if tc.err == nil {
+				if err != nil || len(lvs) != 1 {
+					t.Fatalf("expected a logical volume, got %v: %v", lvs, err)
+				}
+				return
+			}
+			var fieldErr *ReportFieldError
+			if !errors.Is(err, tc.err) || !errors.As(err, &fieldErr) || fieldErr.Field != tc.field {
+				t.Fatalf("expected %v for %s, got %v", tc.err, tc.field, err)
+			}
+		})
+	}
+
+	// without strict decoding, missing fields are zero values, but unparseable values are still typed errors
+	clnt := NewClient(reportRunner(func(lv map[string]string) { lv["lv_size"] = "many" }))
+	var fieldErr *ReportFieldError
+	if _, err := clnt.LVs(ctx); !errors.As(err, &fieldErr) || fieldErr.Field != "lv_size" || fieldErr.Value != `"many"` {
+		t.Fatalf("expected report field error for lv_size, got %v", err)
+	}
+}
+
+func TestExtraColumns(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for name, tc := range map[string]struct {
+		opts    []LVsOption
+		columns string
+	}{
+		"default columns": {
+			opts:    []LVsOption{ExtraColumns{"vg_free", "lv_name"}},
+			columns: "lv_all,vg_free,lv_name",
+		},
+		"custom columns": {
+			opts:    []LVsOption{ColumnOptions{"lv_name", "vg_name"}, ExtraColumns{"vg_free"}, ExtraColumns{"lv_name"}},
+			columns: "lv_name,vg_name,vg_free",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			args, err := LVsOptionsList(tc.opts).AsArgs()
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw := args.GetRaw()
+			if idx := slices.Index(raw, "--options"); idx < 0 || raw[idx+1] != tc.columns {
+				t.Fatalf("expected columns %s, got %v", tc.columns, raw)
+			}
+
+			clnt := NewClient(reportRunner(func(lv map[string]string) { lv["vg_free"] = "1.00g" }))
+			lvs, err := clnt.LVs(ctx, tc.opts...)
+			if err != nil || len(lvs) != 1 {
+				t.Fatalf("expected a logical volume, got %v: %v", lvs, err)
+			}
+			if val, ok := lvs[0].ExtraFields.Get("vg_free"); !ok || val != "1.00g" {
+				t.Fatalf("expected extra field vg_free, got %v", lvs[0].ExtraFields)
+			}
+			if _, ok := lvs[0].ExtraFields.Get("lv_name"); ok {
+				t.Fatal("expected typed field lv_name not to be an extra field")
+			}
+		})
+	}
+}
@@ -33,3 +33,7 @@ func (opt Stripes) ApplyToArgs(args Arguments) error {
 func (opt Stripes) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Stripes = opt
 }
+
+func (opt Stripes) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Stripes = opt
+}
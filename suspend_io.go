@@ -0,0 +1,125 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// DefaultSuspendIOResumeTimeout is the time granted to resume a suspended device after the fenced
+// operation finished, independent of the cancellation of the context used for the operation.
+var DefaultSuspendIOResumeTimeout = 30 * time.Second
+
+// ErrSuspendIORequiresSnapshotOf is returned if SuspendIO is used without a snapshot origin.
+var ErrSuspendIORequiresSnapshotOf = errors.New("SuspendIO requires SnapshotOf to determine the device to suspend")
+
+// SuspendIO suspends the origin of a snapshot before the snapshot is created, so that the snapshot
+// reflects the origin exactly as of the suspend.
+// The origin device is suspended with dmsetup suspend, which flushes all in-flight IO and queues new IO,
+// then lvcreate is called and the origin is resumed with dmsetup resume afterward,
+// regardless of the outcome of the snapshot creation.
+//
+// SuspendIO does not hold IO across the whole snapshot creation: lvcreate suspends and resumes the
+// origin itself to take the snapshot, so queued IO can be released before lvcreate returns.
+// What it guarantees is that no write issued after the suspend ends up in the snapshot,
+// as the snapshot point is taken while the writes are still queued.
+// This is meant for origins that cannot be frozen with fsfreeze, e.g. raw block workloads.
+//
+// The Timeout bounds only the suspend, e.g. if it blocks on flushing IO to a failed device.
+// If it is exceeded, the snapshot is not created and the origin is resumed.
+// The snapshot creation itself is never interrupted, as canceling lvcreate while it commits metadata
+// can leave the volume group inconsistent. A zero Timeout means no bound.
+//
+// Example:
+//
+//	err := client.LVCreate(ctx,
+//		LogicalVolumeName("snap"),
+//		MustNewSnapshotOf("vg", "origin"),
+//		MustParseSize("1G"),
+//		&SuspendIO{Timeout: 10 * time.Second},
+//	)
+type SuspendIO struct {
+	Timeout time.Duration
+}
+
+func (opt *SuspendIO) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.SuspendIO = opt
+}
+
+// Fence suspends the IO to the given logical volume, runs fn, e.g. the creation of a snapshot of the
+// logical volume, and resumes the IO afterward. The IO is resumed even if fn fails.
+// The device is suspended and resumed through the client, honoring the context and locks of wrapped clients,
+// see RawCommandRunner. Clients with a ManagedTag only fence logical volumes carrying the tag.
+func (opt *SuspendIO) Fence(ctx context.Context, clnt Client, lv *FQLogicalVolumeName, fn func(ctx context.Context) error) error {
+	runner, ok := ClientImplements[RawCommandRunner](clnt)
+	if !ok {
+		return fmt.Errorf("%w: client cannot suspend IO", errors.ErrUnsupported)
+	}
+	if err := guardLogicalVolume(ctx, clnt, lv); err != nil {
+		return err
+	}
+	return opt.fence(ctx, runner, clientLogger(clnt), lv, fn)
+}
+
+// fence suspends the device-mapper device of the given logical volume, runs fn and resumes the device.
+// The device is resumed even if fn fails or the suspend timed out, as it may be left suspended then.
+func (opt *SuspendIO) fence(
+	ctx context.Context,
+	runner RawCommandRunner,
+	logger *slog.Logger,
+	lv *FQLogicalVolumeName,
+	fn func(ctx context.Context) error,
+) (err error) {
+	name := lv.DeviceMapperName()
+	logger = logger.With(slog.String("device", name))
+
+	resume := func() {
+		resumeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), DefaultSuspendIOResumeTimeout)
+		defer cancel()
+		if resumeErr := dmsetup(resumeCtx, runner, "resume", name); resumeErr != nil {
+			err = errors.Join(err, fmt.Errorf("failed to resume IO on %s: %w", name, resumeErr))
+			return
+		}
+		logger.DebugContext(ctx, "resumed IO")
+	}
+
+	suspendCtx := ctx
+	if opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		suspendCtx, cancel = context.WithTimeout(ctx, opt.Timeout)
+		defer cancel()
+	}
+	if err = dmsetup(suspendCtx, runner, "suspend", name); err != nil {
+		err = fmt.Errorf("failed to suspend IO on %s: %w", name, err)
+		if suspendCtx.Err() != nil {
+			resume()
+		}
+		return err
+	}
+	logger.DebugContext(ctx, "suspended IO")
+	defer resume()
+
+	return fn(ctx)
+}
+
+func dmsetup(ctx context.Context, runner RawCommandRunner, args ...string) error {
+	return runner.RunRaw(ctx, NoOpRawOutputProcessor(), append([]string{"dmsetup"}, args...)...)
+}
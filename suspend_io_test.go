@@ -0,0 +1,154 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestSuspendIO(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	errLVCreate := errors.New("lvcreate failed")
+
+	for name, tc := range map[string]struct {
+		suspend  func(ctx context.Context) error
+		lvcreate func(ctx context.Context) error
+		steps    []string
+		err      error
+	}{
+		"success": {
+			steps: []string{"suspend", "lvcreate", "resume"},
+		},
+		"lvcreate failure": {
+			lvcreate: func(context.Context) error { return errLVCreate },
+			steps:    []string{"suspend", "lvcreate", "resume"},
+			err:      errLVCreate,
+		},
+		"lvcreate slower than timeout": {
+			lvcreate: func(ctx context.Context) error {
+				time.Sleep(50 * time.Millisecond)
+				return ctx.Err()
+			},
+			steps: []string{"suspend", "lvcreate", "resume"},
+		},
+		"suspend timeout": {
+			suspend: func(ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			steps: []string{"suspend", "resume"},
+			err:   context.DeadlineExceeded,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			var mu sync.Mutex
+			var steps []string
+			clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+				var step string
+				var err error
+				switch {
+				case slices.Contains(cmd.Args, "suspend"):
+					step = "suspend"
+					if tc.suspend != nil {
+						err = tc.suspend(ctx)
+					}
+				case slices.Contains(cmd.Args, "lvcreate"):
+					step = "lvcreate"
+					if tc.lvcreate != nil {
+						err = tc.lvcreate(ctx)
+					}
+				case slices.Contains(cmd.Args, "resume"):
+					step = "resume"
+				default:
+					t.Errorf("unexpected command %v", cmd.Args)
+				}
+				mu.Lock()
+				steps = append(steps, step)
+				mu.Unlock()
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(strings.NewReader("")), nil
+			}))
+
+			err := clnt.LVCreate(ctx,
+				LogicalVolumeName("snap"),
+				MustNewSnapshotOf("vg", "origin"),
+				MustParseSize("1G"),
+				&SuspendIO{Timeout: 10 * time.Millisecond},
+			)
+			if !errors.Is(err, tc.err) || (tc.err == nil) != (err == nil) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+			if !slices.Equal(steps, tc.steps) {
+				t.Fatalf("expected %v, got %v", tc.steps, steps)
+			}
+		})
+	}
+}
+
+func TestSuspendIOFenceWrappedClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	origin := &FQLogicalVolumeName{VolumeGroupName: "vg", LogicalVolumeName: "origin"}
+
+	var commands [][]string
+	base := NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		commands = append(commands, cmd.Args)
+		if slices.Contains(cmd.Args, "lvs") {
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"origin","vg_name":"vg","lv_tags":""}]}]}`)), nil
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+
+	clnt := NewLockingClient(WithNoNsenter(base))
+	if err := (&SuspendIO{}).Fence(ctx, clnt, origin, func(ctx context.Context) error {
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	expected := [][]string{{"dmsetup", "suspend", "vg-origin"}, {"dmsetup", "resume", "vg-origin"}}
+	if !slices.EqualFunc(commands, expected, slices.Equal) {
+		t.Fatalf("expected dmsetup to run without nsenter, got %v", commands)
+	}
+
+	// the origin does not carry the managed tag
+	commands = nil
+	if err := (&SuspendIO{}).Fence(ctx, NewTagGuardClient(clnt, "managed"), origin, func(ctx context.Context) error {
+		t.Fatal("expected the fence to be refused")
+		return nil
+	}); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected %v, got %v", ErrNotManagedByThisClient, err)
+	}
+	for _, command := range commands {
+		if slices.Contains(command, "dmsetup") {
+			t.Fatalf("expected no IO to be suspended, got %v", command)
+		}
+	}
+}
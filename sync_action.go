@@ -17,16 +17,50 @@
 package lvm2go
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"time"
 )
 
+// DefaultSyncPollInterval is the interval in which WaitForSync polls the synchronization progress.
+const DefaultSyncPollInterval = 5 * time.Second
+
+// SyncAction starts a scrubbing operation on a raid logical volume with LVChange.
+// The progress is reported in LogicalVolume.RaidSyncAction and LogicalVolume.SyncPercent
+// and can be awaited with WaitForSync.
 type SyncAction string
 
 const (
-	SyncActionCheck  SyncAction = "check"
+	// SyncActionCheck reads all data and parity of the raid logical volume and counts inconsistent regions
+	// in LogicalVolume.RaidMismatchCount without correcting them.
+	SyncActionCheck SyncAction = "check"
+	// SyncActionRepair reads all data and parity of the raid logical volume and corrects inconsistent regions.
 	SyncActionRepair SyncAction = "repair"
 )
 
+// MaxRecoveryRate limits the rate of the synchronization of raid logical volumes per device and second,
+// e.g. of a scrub started with SyncAction, so that it does not starve the I/O of applications.
+type MaxRecoveryRate Size
+
+func (opt MaxRecoveryRate) ApplyToArgs(args Arguments) error {
+	if opt.Val == 0 {
+		return nil
+	}
+	return Size(opt).applyToArgs(maxRecoveryRateArg, args)
+}
+
+func (opt MaxRecoveryRate) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.MaxRecoveryRate = opt
+}
+
+func (opt MaxRecoveryRate) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.MaxRecoveryRate = opt
+}
+
+// RaidSyncActionIdle is the LogicalVolume.RaidSyncAction of raid logical volumes without a running synchronization.
+const RaidSyncActionIdle = "idle"
+
 func (opt SyncAction) ApplyToArgs(args Arguments) error {
 	if opt == "" {
 		return nil
@@ -38,3 +72,91 @@ func (opt SyncAction) ApplyToArgs(args Arguments) error {
 func (opt SyncAction) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.SyncAction = opt
 }
+
+type (
+	WaitForSyncOptions struct {
+		SyncPollInterval
+		SyncProgress
+		ProgressFunc
+	}
+	WaitForSyncOption interface {
+		ApplyToWaitForSyncOptions(opts *WaitForSyncOptions)
+	}
+)
+
+// SyncPollInterval is the interval in which WaitForSync polls the synchronization progress.
+// If zero, DefaultSyncPollInterval is used.
+type SyncPollInterval time.Duration
+
+func (opt SyncPollInterval) ApplyToWaitForSyncOptions(opts *WaitForSyncOptions) {
+	opts.SyncPollInterval = opt
+}
+
+// SyncProgress is called by WaitForSync with the current synchronization action and its progress in percent
+// every time the progress is polled.
+type SyncProgress func(action string, percent float64)
+
+func (opt SyncProgress) ApplyToWaitForSyncOptions(opts *WaitForSyncOptions) {
+	opts.SyncProgress = opt
+}
+
+// WaitForSync blocks until the raid or mirror logical volume is fully synchronized and no synchronization
+// action is running, e.g. after the initial synchronization or a scrub started with SyncAction.
+// It returns the logical volume as last reported, so that e.g. the RaidMismatchCount of a check can be read.
+//
+// Example of a periodic scrub:
+//
+//	if err := client.LVChange(ctx, vg, lv, SyncActionCheck); err != nil {
+//		return err
+//	}
+//	synced, err := WaitForSync(ctx, client, fq)
+//	if err == nil && synced.RaidMismatchCount > 0 {
+//		err = client.LVChange(ctx, vg, lv, SyncActionRepair)
+//	}
+func WaitForSync(ctx context.Context, client Client, lv *FQLogicalVolumeName, opts ...WaitForSyncOption) (*LogicalVolume, error) {
+	options := WaitForSyncOptions{}
+	for _, opt := range opts {
+		opt.ApplyToWaitForSyncOptions(&options)
+	}
+	interval := time.Duration(options.SyncPollInterval)
+	if interval == 0 {
+		interval = DefaultSyncPollInterval
+	}
+	if err := lv.Validate(); err != nil {
+		return nil, err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var tracker *ProgressTracker
+	for {
+		report, err := client.LV(ctx, lv.VolumeGroupName, lv.LogicalVolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains([]VolumeType{
+			VolumeTypeRAID, VolumeTypeRAIDNoInitialSync, VolumeTypeMirrored, VolumeTypeMirroredNoInitialSync,
+		}, report.Attr.VolumeType) {
+			return nil, fmt.Errorf("%s is not a raid or mirror logical volume", lv)
+		}
+		if options.SyncProgress != nil {
+			options.SyncProgress(report.RaidSyncAction, report.SyncPercent)
+		}
+		if tracker == nil {
+			tracker = NewProgressTracker(ProgressOperationSync, sizeInBytes(report.Size))
+		}
+		// mirrors do not report a sync action
+		synced := report.SyncPercent >= 100 && (report.RaidSyncAction == RaidSyncActionIdle || report.RaidSyncAction == "")
+		options.ProgressFunc.report(tracker.Update(report.SyncPercent, synced))
+		if synced {
+			return report, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
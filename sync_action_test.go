@@ -0,0 +1,82 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+// raidReportRunner reports the raid logical volume vg/raid with the given sync action and progress on every call.
+func raidReportRunner(attr string, states ...[2]string) CommandRunner {
+	return func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		state := states[0]
+		if len(states) > 1 {
+			states = states[1:]
+		}
+		return io.NopCloser(strings.NewReader(fmt.Sprintf(
+			`{"report":[{"lv":[{"lv_name":"raid","vg_name":"vg","lv_attr":%q,"raid_sync_action":%q,"sync_percent":%q,"raid_mismatch_count":"3"}]}]}`,
+			attr, state[0], state[1],
+		))), nil
+	}
+}
+
+func TestWaitForSync(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	lv := MustNewFQLogicalVolumeName("vg", "raid")
+
+	var progress []string
+	clnt := NewClient(raidReportRunner("rwi-a-r---", [2]string{"check", "20.00"}, [2]string{"check", "80.00"}, [2]string{"idle", "100.00"}))
+	synced, err := WaitForSync(ctx, clnt, lv, SyncPollInterval(time.Millisecond), SyncProgress(func(action string, percent float64) {
+		progress = append(progress, fmt.Sprintf("%s %.0f", action, percent))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if synced.RaidMismatchCount != 3 {
+		t.Fatalf("expected 3 mismatches, got %d", synced.RaidMismatchCount)
+	}
+	if strings.Join(progress, ",") != "check 20,check 80,idle 100" {
+		t.Fatalf("unexpected progress %v", progress)
+	}
+
+	clnt = NewClient(raidReportRunner("-wi-a-----", [2]string{"", ""}))
+	if _, err := WaitForSync(ctx, clnt, lv, SyncPollInterval(time.Millisecond)); err == nil {
+		t.Fatal("expected error for linear logical volume")
+	}
+}
+
+func TestMaxRecoveryRate(t *testing.T) {
+	t.Parallel()
+	args, err := LVChangeOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("raid"), SyncActionCheck, MaxRecoveryRate(MustParseSize("100M")),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, "--syncaction=check --maxrecoveryrate=100.00m") {
+		t.Fatalf("unexpected args %s", raw)
+	}
+}
@@ -0,0 +1,112 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+// SystemIDSource is the method lvm2 uses to determine the system ID of the local host, configured as
+// global/system_id_source in lvm.conf. Volume groups with a SystemID are only accessible by the host
+// with the same system ID, which protects volume groups on shared storage from concurrent use.
+// See man lvmsystemid.
+type SystemIDSource string
+
+const (
+	// SystemIDSourceNone disables the system ID of the host, so that volume groups with a SystemID are not accessible.
+	SystemIDSourceNone SystemIDSource = "none"
+	// SystemIDSourceMachineID uses the machine ID of the host from /etc/machine-id.
+	SystemIDSourceMachineID SystemIDSource = "machineid"
+	// SystemIDSourceUname uses the host name of the host.
+	SystemIDSourceUname SystemIDSource = "uname"
+	// SystemIDSourceLVMLocal uses local/system_id in lvmlocal.conf, see SetLocalSystemID.
+	SystemIDSourceLVMLocal SystemIDSource = "lvmlocal"
+	// SystemIDSourceFile uses the contents of the file configured as global/system_id_file.
+	SystemIDSourceFile SystemIDSource = "file"
+)
+
+func (source SystemIDSource) Validate() error {
+	switch source {
+	case SystemIDSourceNone, SystemIDSourceMachineID, SystemIDSourceUname, SystemIDSourceLVMLocal, SystemIDSourceFile:
+		return nil
+	default:
+		return fmt.Errorf("invalid system ID source %q", string(source))
+	}
+}
+
+type systemIDSourceConfig struct {
+	Global struct {
+		SystemIDSource string `lvm:"system_id_source"`
+	} `lvm:"global"`
+}
+
+type systemIDFileConfig struct {
+	Global struct {
+		SystemIDSource string `lvm:"system_id_source"`
+		SystemIDFile   string `lvm:"system_id_file"`
+	} `lvm:"global"`
+}
+
+type localSystemIDConfig struct {
+	Local struct {
+		SystemID string `lvm:"system_id"`
+	} `lvm:"local"`
+}
+
+// SetSystemIDSource sets the source of the system ID of the local host in lvm.conf with UpdateGlobalConfig.
+// The file is only used and required with SystemIDSourceFile.
+// Volume groups created afterwards get the new system ID of the host, existing volume groups keep
+// their SystemID and have to be changed with VGChange to remain accessible.
+func SetSystemIDSource(ctx context.Context, client Client, source SystemIDSource, file string) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+	if (source == SystemIDSourceFile) != (file != "") {
+		return fmt.Errorf("a system ID file is required for and only used with system ID source %s", SystemIDSourceFile)
+	}
+
+	var config any
+	if source == SystemIDSourceFile {
+		fileConfig := &systemIDFileConfig{}
+		fileConfig.Global.SystemIDSource = string(source)
+		fileConfig.Global.SystemIDFile = file
+		config = fileConfig
+	} else {
+		sourceConfig := &systemIDSourceConfig{}
+		sourceConfig.Global.SystemIDSource = string(source)
+		config = sourceConfig
+	}
+	if err := client.UpdateGlobalConfig(ctx, config); err != nil {
+		return fmt.Errorf("failed to set system ID source to %s: %w", source, err)
+	}
+	return nil
+}
+
+// SetLocalSystemID sets the system ID of the local host in lvmlocal.conf with UpdateLocalConfig.
+// It is only used with SystemIDSourceLVMLocal.
+func SetLocalSystemID(ctx context.Context, client Client, id SystemID) error {
+	if id == "" {
+		return fmt.Errorf("a system ID is required")
+	}
+	config := &localSystemIDConfig{}
+	config.Local.SystemID = string(id)
+	if err := client.UpdateLocalConfig(ctx, config); err != nil {
+		return fmt.Errorf("failed to set local system ID to %s: %w", id, err)
+	}
+	return nil
+}
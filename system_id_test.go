@@ -0,0 +1,80 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+// configRecordingClient encodes the configuration updates instead of writing them.
+type configRecordingClient struct {
+	*fake.Client
+	global, local bytes.Buffer
+}
+
+func (c *configRecordingClient) UpdateGlobalConfig(ctx context.Context, v any) error {
+	return c.WriteAndEncodeConfig(ctx, v, &c.global)
+}
+
+func (c *configRecordingClient) UpdateLocalConfig(ctx context.Context, v any) error {
+	return c.WriteAndEncodeConfig(ctx, v, &c.local)
+}
+
+func TestSystemID(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := &configRecordingClient{Client: fake.NewClient()}
+
+	if err := SetSystemIDSource(ctx, clnt, SystemIDSourceFile, "/etc/lvm/system_id"); err != nil {
+		t.Fatal(err)
+	}
+	for _, expected := range []string{`system_id_source = "file"`, `system_id_file = "/etc/lvm/system_id"`} {
+		if !strings.Contains(clnt.global.String(), expected) {
+			t.Fatalf("expected %s in %s", expected, clnt.global.String())
+		}
+	}
+	if err := SetSystemIDSource(ctx, clnt, SystemIDSourceUname, "/etc/lvm/system_id"); err == nil {
+		t.Fatal("expected an error for a file without system ID source file")
+	}
+	if err := SetSystemIDSource(ctx, clnt, "hostname", ""); err == nil {
+		t.Fatal("expected an error for an invalid system ID source")
+	}
+	if err := SetLocalSystemID(ctx, clnt, "host-a"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(clnt.local.String(), `system_id = "host-a"`) {
+		t.Fatalf("expected the local system ID in %s", clnt.local.String())
+	}
+
+	clnt.SetDevice("/dev/sdb", MustParseSize("100M"))
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}, SystemID("host-a")); err != nil {
+		t.Fatal(err)
+	}
+	vg, err := clnt.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.SystemID != "host-a" {
+		t.Fatalf("expected system ID host-a, got %q", vg.SystemID)
+	}
+}
@@ -0,0 +1,194 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// SystemdUnitDirectory is the directory for unit files of the local administrator.
+const SystemdUnitDirectory = "/etc/systemd/system"
+
+// systemdUnitPrefix prefixes the names of all units generated by GenerateSystemdUnits.
+const systemdUnitPrefix = "lvm2go-"
+
+// SystemdUnit is a systemd unit file generated by GenerateSystemdUnits.
+type SystemdUnit struct {
+	// Name is the file name of the unit, e.g. lvm2go-vg-vg.service.
+	Name string
+	// Content is the content of the unit file.
+	Content string
+}
+
+// SystemdMount is a logical volume to mount on boot.
+type SystemdMount struct {
+	LogicalVolumeName
+	// Where is the absolute path of the mount point.
+	Where string
+	// Type is the filesystem type, e.g. ext4. If empty, it is detected by mount.
+	Type string
+	// Options are the mount options, e.g. noatime.
+	Options []string
+}
+
+// SystemdUnitsSpec describes a volume group to set up on boot.
+type SystemdUnitsSpec struct {
+	VolumeGroupName
+	// BackingFiles are the backing files of the loopback devices of a file backed volume group,
+	// e.g. created with CreateFileBackedVG. They are attached before the volume group is activated.
+	BackingFiles []string
+	// Mounts are the logical volumes of the volume group to mount once it is activated.
+	Mounts []SystemdMount
+}
+
+// GenerateSystemdUnits generates the units that set up the volume group of the spec on boot in order:
+// a service per backing file that attaches its loopback device, a service that activates the volume group
+// once all of its loopback devices are attached, and a mount unit per mount that requires the activation.
+// Loopback devices are not set up again by the system after a reboot, and the mount units make the
+// mounts wait for the activation of their logical volumes.
+//
+// The units are returned in the order they are started and can be installed with WriteSystemdUnits.
+// The volume group is activated with autoactivation, so logical volumes with activation skip stay inactive.
+func GenerateSystemdUnits(spec SystemdUnitsSpec) ([]SystemdUnit, error) {
+	if spec.VolumeGroupName == "" {
+		return nil, ErrVolumeGroupNameRequired
+	}
+
+	var units []SystemdUnit
+	var loops []string
+	for _, file := range spec.BackingFiles {
+		if !filepath.IsAbs(file) {
+			return nil, fmt.Errorf("backing file %s must be an absolute path", file)
+		}
+		name := fmt.Sprintf("%sloop-%s.service", systemdUnitPrefix, systemdEscapePath(file))
+		loops = append(loops, name)
+		units = append(units, SystemdUnit{Name: name, Content: fmt.Sprintf(`[Unit]
+Description=Loopback device for %[1]s
+DefaultDependencies=no
+RequiresMountsFor=%[1]s
+Before=shutdown.target
+Conflicts=shutdown.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/sbin/losetup --find --direct-io=on %[1]s
+ExecStop=/bin/sh -c '/usr/sbin/losetup --noheadings --output NAME --associated %[1]s | xargs -r /usr/sbin/losetup --detach'
+`, file)})
+	}
+
+	vgUnit := fmt.Sprintf("%svg-%s.service", systemdUnitPrefix, systemdEscape(string(spec.VolumeGroupName)))
+	var dependencies string
+	if len(loops) > 0 {
+		dependencies = fmt.Sprintf("Requires=%[1]s\nAfter=%[1]s\n", strings.Join(loops, " "))
+	}
+	units = append(units, SystemdUnit{Name: vgUnit, Content: fmt.Sprintf(`[Unit]
+Description=Activation of volume group %[1]s
+DefaultDependencies=no
+%[2]sAfter=systemd-udev-settle.service lvm2-lvmpolld.socket
+Before=shutdown.target
+Conflicts=shutdown.target
+
+[Service]
+Type=oneshot
+RemainAfterExit=yes
+ExecStart=/usr/sbin/lvm vgchange --activate ay %[1]s
+ExecStop=/usr/sbin/lvm vgchange --activate n %[1]s
+
+[Install]
+WantedBy=local-fs.target
+`, spec.VolumeGroupName, dependencies)})
+
+	for _, mount := range spec.Mounts {
+		lv, err := NewFQLogicalVolumeName(spec.VolumeGroupName, mount.LogicalVolumeName)
+		if err != nil {
+			return nil, err
+		}
+		if !filepath.IsAbs(mount.Where) {
+			return nil, fmt.Errorf("mount point %s must be an absolute path", mount.Where)
+		}
+		where := filepath.Clean(mount.Where)
+
+		var mountOptions strings.Builder
+		if mount.Type != "" {
+			fmt.Fprintf(&mountOptions, "Type=%s\n", mount.Type)
+		}
+		if len(mount.Options) > 0 {
+			fmt.Fprintf(&mountOptions, "Options=%s\n", strings.Join(mount.Options, ","))
+		}
+		units = append(units, SystemdUnit{Name: systemdEscapePath(where) + ".mount", Content: fmt.Sprintf(`[Unit]
+Description=Mount of %[1]s
+Requires=%[2]s
+After=%[2]s
+
+[Mount]
+What=%[3]s
+Where=%[4]s
+%[5]s
+[Install]
+WantedBy=local-fs.target
+`, lv, vgUnit, lv.DevicePath(), where, mountOptions.String())})
+	}
+
+	return units, nil
+}
+
+// WriteSystemdUnits writes the units to dir, usually SystemdUnitDirectory.
+// systemd has to reload its units afterward, e.g. with systemctl daemon-reload,
+// and the volume group service and the mounts have to be enabled to be started on boot.
+func WriteSystemdUnits(dir string, units []SystemdUnit) error {
+	var errs []error
+	for _, unit := range units {
+		if err := os.WriteFile(filepath.Join(dir, unit.Name), []byte(unit.Content), 0644); err != nil {
+			errs = append(errs, fmt.Errorf("failed to write unit %s: %w", unit.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// systemdEscape escapes s for use in a unit name like systemd-escape.
+func systemdEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '/':
+			b.WriteByte('-')
+		case c == '.' && i == 0,
+			!(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || slices.Contains([]byte(":_."), c)):
+			fmt.Fprintf(&b, `\x%02x`, c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// systemdEscapePath escapes the path for use in a unit name like systemd-escape --path,
+// e.g. /mnt/my-data becomes mnt-my\x2ddata.
+func systemdEscapePath(path string) string {
+	path = strings.Trim(filepath.Clean(path), "/")
+	if path == "" {
+		return "-"
+	}
+	return systemdEscape(path)
+}
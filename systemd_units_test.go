@@ -0,0 +1,90 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSystemdEscapePath(t *testing.T) {
+	t.Parallel()
+	for path, expected := range map[string]string{
+		"/":                   "-",
+		"/mnt/data":           "mnt-data",
+		"/mnt//my-data/":      `mnt-my\x2ddata`,
+		"/var/lib/.hidden":    `var-lib-.hidden`,
+		"/.hidden":            `\x2ehidden`,
+		"/srv/disk image.img": `srv-disk\x20image.img`,
+	} {
+		if escaped := systemdEscapePath(path); escaped != expected {
+			t.Errorf("expected %s for %s, got %s", expected, path, escaped)
+		}
+	}
+}
+
+func TestGenerateSystemdUnits(t *testing.T) {
+	t.Parallel()
+
+	units, err := GenerateSystemdUnits(SystemdUnitsSpec{
+		VolumeGroupName: "vg-data",
+		BackingFiles:    []string{"/var/lib/disks/vg.img"},
+		Mounts: []SystemdMount{{
+			LogicalVolumeName: "lv",
+			Where:             "/mnt/data",
+			Type:              "ext4",
+			Options:           []string{"noatime", "nofail"},
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for _, unit := range units {
+		names = append(names, unit.Name)
+	}
+	expected := []string{"lvm2go-loop-var-lib-disks-vg.img.service", `lvm2go-vg-vg\x2ddata.service`, "mnt-data.mount"}
+	if strings.Join(names, " ") != strings.Join(expected, " ") {
+		t.Fatalf("expected units %v, got %v", expected, names)
+	}
+	for unit, lines := range map[int][]string{
+		0: {"RequiresMountsFor=/var/lib/disks/vg.img", "ExecStart=/usr/sbin/losetup --find --direct-io=on /var/lib/disks/vg.img"},
+		1: {"Requires=lvm2go-loop-var-lib-disks-vg.img.service", "ExecStart=/usr/sbin/lvm vgchange --activate ay vg-data"},
+		2: {`Requires=lvm2go-vg-vg\x2ddata.service`, "What=/dev/vg-data/lv", "Where=/mnt/data", "Type=ext4", "Options=noatime,nofail"},
+	} {
+		for _, line := range lines {
+			if !strings.Contains(units[unit].Content, line+"\n") {
+				t.Errorf("expected %q in unit %s:\n%s", line, units[unit].Name, units[unit].Content)
+			}
+		}
+	}
+
+	dir := t.TempDir()
+	if err := WriteSystemdUnits(dir, units); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, units[2].Name)); err != nil || string(data) != units[2].Content {
+		t.Fatalf("unexpected unit file %q: %v", data, err)
+	}
+
+	if _, err := GenerateSystemdUnits(SystemdUnitsSpec{VolumeGroupName: "vg", BackingFiles: []string{"vg.img"}}); err == nil {
+		t.Fatal("expected error for a relative backing file")
+	}
+}
@@ -0,0 +1,558 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// ErrNotManagedByThisClient is returned by clients with a ManagedTag for mutations of objects without the tag.
+var ErrNotManagedByThisClient = errors.New("not managed by this client")
+
+// ManagedTag restricts the mutations of a client created with NewClient or NewDBusClient to objects carrying the tag,
+// see NewTagGuardClient.
+type ManagedTag string
+
+func (opt ManagedTag) ApplyToClientOptions(opts *ClientOptions) {
+	opts.ManagedTag = opt
+}
+
+// tagGuardClient verifies that the objects of mutations carry its tag before passing them on.
+// Reports and other calls that do not change volumes are passed on unchanged.
+type tagGuardClient struct {
+	Client
+	tag string
+}
+
+var _ WrappedClient = &tagGuardClient{}
+
+// NewTagGuardClient returns a client that only changes objects carrying the tag,
+// so that a misconfigured controller cannot modify volumes managed by humans or other controllers on a shared host.
+// Before every mutation, the objects are reported and ErrNotManagedByThisClient is returned if they lack the tag:
+//   - logical volumes must carry the tag themselves, logical volumes are created in volume groups carrying the tag
+//     and get the tag on creation;
+//   - volume groups must carry the tag, volume groups get the tag on creation;
+//   - physical volumes must carry the tag or be part of a volume group carrying it. Devices that are no physical
+//     volume yet can be initialized with PVCreate.
+//
+// Mutations selecting objects by Select are limited to objects carrying the tag,
+// mutations without names or selection are only allowed for the tag itself.
+// VGCheck with UpdateMetadata and PVCheck with Repair are guarded like other mutations.
+// PVScan only autoactivates volume groups carrying the tag, VGScan with Mknodes is refused.
+// Entries of the devices file can only be added or removed by device with DevModify, which guards the device
+// like PVCreate and PVRemove. DevUpdate is refused as it corrects all entries of the devices file at once.
+// Raw commands run through the wrapped client, e.g. with RunLVM, are not guarded, but helpers that change
+// a logical volume with raw commands, e.g. SuspendIO.Fence or SetFilesystemUUID, only change logical volumes
+// carrying the tag.
+func NewTagGuardClient(client Client, tag string) Client {
+	return &tagGuardClient{Client: client, tag: tag}
+}
+
+// Unwrap implements WrappedClient.
+func (g *tagGuardClient) Unwrap() Client {
+	return g.Client
+}
+
+// logicalVolumeGuard is implemented by clients that restrict the logical volumes that can be changed.
+type logicalVolumeGuard interface {
+	guardLV(ctx context.Context, vg VolumeGroupName, lv LogicalVolumeName) error
+}
+
+// guardLogicalVolume verifies with the guards in the decoration chain of client that the logical volume can be
+// changed, for changes that are made with raw commands instead of a method of the client, e.g. of its filesystem.
+func guardLogicalVolume(ctx context.Context, client Client, lv *FQLogicalVolumeName) error {
+	for _, c := range ClientChain(client) {
+		if guard, ok := c.(logicalVolumeGuard); ok {
+			if err := guard.guardLV(ctx, lv.VolumeGroupName, lv.LogicalVolumeName); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (g *tagGuardClient) notManaged(format string, args ...any) error {
+	return fmt.Errorf("%w: %s is not tagged with %s", ErrNotManagedByThisClient, fmt.Sprintf(format, args...), g.tag)
+}
+
+func (g *tagGuardClient) guardLV(ctx context.Context, vg VolumeGroupName, lv LogicalVolumeName) error {
+	if vg == "" || lv == "" {
+		return fmt.Errorf("%w: logical volumes can only be changed by name", ErrNotManagedByThisClient)
+	}
+	report, err := g.Client.LV(ctx, vg, lv)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(report.Tags, g.tag) {
+		return g.notManaged("logical volume %s/%s", vg, lv)
+	}
+	return nil
+}
+
+func (g *tagGuardClient) guardVG(ctx context.Context, vgs ...VolumeGroupName) error {
+	for _, vg := range vgs {
+		if vg == "" {
+			return fmt.Errorf("%w: volume groups can only be changed by name", ErrNotManagedByThisClient)
+		}
+		report, err := g.Client.VG(ctx, vg)
+		if err != nil {
+			return err
+		}
+		if !slices.Contains(report.Tags, g.tag) {
+			return g.notManaged("volume group %s", vg)
+		}
+	}
+	return nil
+}
+
+// guardPV verifies the physical volume, devices that are no physical volume are only allowed with allowNew.
+func (g *tagGuardClient) guardPV(ctx context.Context, pv PhysicalVolumeName, allowNew bool) error {
+	if pv == "" {
+		return fmt.Errorf("%w: physical volumes can only be changed by name", ErrNotManagedByThisClient)
+	}
+	pvs, err := g.Client.PVs(ctx, PhysicalVolumeNames{pv})
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(pvs) == 0 && allowNew:
+		return nil
+	case len(pvs) == 0:
+		return g.notManaged("device %s", pv)
+	case slices.Contains(pvs[0].Tags, g.tag):
+		return nil
+	case pvs[0].VGName != "":
+		return g.guardVG(ctx, pvs[0].VGName)
+	}
+	return g.notManaged("physical volume %s", pv)
+}
+
+// guardUnnamed allows mutations without names only for objects selected by the tag.
+func (g *tagGuardClient) guardUnnamed(tags Tags, sel Select) error {
+	if sel != "" || (len(tags) == 1 && tags[0] == g.tag) {
+		return nil
+	}
+	return fmt.Errorf("%w: only objects tagged with %s can be changed without names", ErrNotManagedByThisClient, g.tag)
+}
+
+func (g *tagGuardClient) LVCreate(ctx context.Context, opts ...LVCreateOption) error {
+	options := LVCreateOptions{}
+	LVCreateOptionList(opts).ApplyToLVCreateOptions(&options)
+	if err := g.guardVG(ctx, options.volumeGroupName()); err != nil {
+		return err
+	}
+	if !slices.Contains(options.Tags, g.tag) {
+		options.Tags = append(slices.Clone(options.Tags), g.tag)
+	}
+	return g.Client.LVCreate(ctx, &options)
+}
+
+func (g *tagGuardClient) LVRemove(ctx context.Context, opts ...LVRemoveOption) error {
+	options := LVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRemoveOptions(&options)
+	}
+	if options.LogicalVolumeName == "" {
+		if err := g.guardUnnamed(options.Tags, options.Select); err != nil {
+			return err
+		}
+		if options.Select != "" {
+			options.Select = options.Select.And(LVTags.Contains(g.tag))
+		}
+		return g.Client.LVRemove(ctx, &options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVRemove(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVResize(ctx context.Context, opts ...LVResizeOption) error {
+	options := LVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVResizeOptions(&options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVResize(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVExtend(ctx context.Context, opts ...LVExtendOption) error {
+	options := LVExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVExtendOptions(&options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVExtend(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVReduce(ctx context.Context, opts ...LVReduceOption) error {
+	options := LVReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVReduceOptions(&options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVReduce(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVRename(ctx context.Context, opts ...LVRenameOption) error {
+	options := LVRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVRenameOptions(&options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.Old); err != nil {
+		return err
+	}
+	return g.Client.LVRename(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVChange(ctx context.Context, opts ...LVChangeOption) error {
+	options := LVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVChangeOptions(&options)
+	}
+	if options.LogicalVolumeName == "" && options.Select != "" {
+		options.Select = options.Select.And(LVTags.Contains(g.tag))
+		return g.Client.LVChange(ctx, &options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVChange(ctx, opts...)
+}
+
+func (g *tagGuardClient) LVConvert(ctx context.Context, opts ...LVConvertOption) error {
+	options := LVConvertOptions{}
+	for _, opt := range opts {
+		opt.ApplyToLVConvertOptions(&options)
+	}
+	if err := g.guardLV(ctx, options.VolumeGroupName, options.LogicalVolumeName); err != nil {
+		return err
+	}
+	return g.Client.LVConvert(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGCreate(ctx context.Context, opts ...VGCreateOption) error {
+	options := VGCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCreateOptions(&options)
+	}
+	for _, pv := range options.PhysicalVolumeNames {
+		if err := g.guardPV(ctx, pv, true); err != nil {
+			return err
+		}
+	}
+	if !slices.Contains(options.Tags, g.tag) {
+		options.Tags = append(slices.Clone(options.Tags), g.tag)
+	}
+	return g.Client.VGCreate(ctx, &options)
+}
+
+func (g *tagGuardClient) VGRemove(ctx context.Context, opts ...VGRemoveOption) error {
+	options := VGRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRemoveOptions(&options)
+	}
+	if options.VolumeGroupName == "" {
+		if err := g.guardUnnamed(options.Tags, options.Select); err != nil {
+			return err
+		}
+		if options.Select != "" {
+			options.Select = options.Select.And(VGTags.Contains(g.tag))
+		}
+		return g.Client.VGRemove(ctx, &options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	return g.Client.VGRemove(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGExtend(ctx context.Context, opts ...VGExtendOption) error {
+	options := VGExtendOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGExtendOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	for _, pv := range options.PhysicalVolumeNames {
+		if err := g.guardPV(ctx, pv, true); err != nil {
+			return err
+		}
+	}
+	return g.Client.VGExtend(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGReduce(ctx context.Context, opts ...VGReduceOption) error {
+	options := VGReduceOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGReduceOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	return g.Client.VGReduce(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGRename(ctx context.Context, opts ...VGRenameOption) error {
+	options := VGRenameOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGRenameOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.Old); err != nil {
+		return err
+	}
+	return g.Client.VGRename(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGSplit(ctx context.Context, opts ...VGSplitOption) error {
+	options := VGSplitOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGSplitOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.From); err != nil {
+		return err
+	}
+	return g.Client.VGSplit(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGMerge(ctx context.Context, opts ...VGMergeOption) error {
+	options := VGMergeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGMergeOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.Destination, options.Source); err != nil {
+		return err
+	}
+	return g.Client.VGMerge(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGExport(ctx context.Context, opts ...VGExportOption) error {
+	options := VGExportOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGExportOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	return g.Client.VGExport(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGImport(ctx context.Context, opts ...VGImportOption) error {
+	options := VGImportOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGImportOptions(&options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	return g.Client.VGImport(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGImportClone(ctx context.Context, opts ...VGImportCloneOption) error {
+	options := VGImportCloneOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGImportCloneOptions(&options)
+	}
+	if len(options.PhysicalVolumeNames) == 0 {
+		return fmt.Errorf("%w: physical volumes can only be changed by name", ErrNotManagedByThisClient)
+	}
+	for _, pv := range options.PhysicalVolumeNames {
+		if err := g.guardPV(ctx, pv, false); err != nil {
+			return err
+		}
+	}
+	return g.Client.VGImportClone(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGCfgRestore(ctx context.Context, opts ...VGCfgRestoreOption) error {
+	options := VGCfgRestoreOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCfgRestoreOptions(&options)
+	}
+	// listing the backups does not change the volume group
+	if options.List == nil {
+		if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+			return err
+		}
+	}
+	return g.Client.VGCfgRestore(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGChange(ctx context.Context, opts ...VGChangeOption) error {
+	options := VGChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGChangeOptions(&options)
+	}
+	if options.VolumeGroupName == "" && options.Select != "" {
+		options.Select = options.Select.And(VGTags.Contains(g.tag))
+		return g.Client.VGChange(ctx, &options)
+	}
+	if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+		return err
+	}
+	return g.Client.VGChange(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVCreate(ctx context.Context, opts ...PVCreateOption) error {
+	options := PVCreateOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVCreateOptions(&options)
+	}
+	if err := g.guardPV(ctx, options.PhysicalVolumeName, true); err != nil {
+		return err
+	}
+	return g.Client.PVCreate(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVRemove(ctx context.Context, opts ...PVRemoveOption) error {
+	options := PVRemoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVRemoveOptions(&options)
+	}
+	if err := g.guardPV(ctx, options.PhysicalVolumeName, false); err != nil {
+		return err
+	}
+	return g.Client.PVRemove(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVResize(ctx context.Context, opts ...PVResizeOption) error {
+	options := PVResizeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVResizeOptions(&options)
+	}
+	if err := g.guardPV(ctx, options.PhysicalVolumeName, false); err != nil {
+		return err
+	}
+	return g.Client.PVResize(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVChange(ctx context.Context, opts ...PVChangeOption) error {
+	options := PVChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVChangeOptions(&options)
+	}
+	if err := g.guardPV(ctx, options.PhysicalVolumeName, false); err != nil {
+		return err
+	}
+	return g.Client.PVChange(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVMove(ctx context.Context, opts ...PVMoveOption) error {
+	options := PVMoveOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVMoveOptions(&options)
+	}
+	if err := g.guardPV(ctx, options.From, false); err != nil {
+		return err
+	}
+	return g.Client.PVMove(ctx, opts...)
+}
+
+func (g *tagGuardClient) VGCheck(ctx context.Context, opts ...VGCheckOption) (*VGCheckResult, error) {
+	options := VGCheckOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCheckOptions(&options)
+	}
+	// checking without updating the metadata does not change the volume group
+	if options.UpdateMetadata {
+		if err := g.guardVG(ctx, options.VolumeGroupName); err != nil {
+			return nil, err
+		}
+	}
+	return g.Client.VGCheck(ctx, opts...)
+}
+
+func (g *tagGuardClient) PVCheck(ctx context.Context, opts ...PVCheckOption) (*PVCheckResult, error) {
+	options := PVCheckOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVCheckOptions(&options)
+	}
+	// checks and dumps do not change the physical volume
+	if options.Repair {
+		if err := g.guardPV(ctx, options.PhysicalVolumeName, false); err != nil {
+			return nil, err
+		}
+	}
+	return g.Client.PVCheck(ctx, opts...)
+}
+
+// PVScan refuses autoactivation unless all scanned devices are named and belong to volume groups carrying the tag,
+// as the volume groups completed by the scan are activated. Scans without activation do not change volumes.
+func (g *tagGuardClient) PVScan(ctx context.Context, opts ...PVScanOption) error {
+	options := PVScanOptions{}
+	for _, opt := range opts {
+		opt.ApplyToPVScanOptions(&options)
+	}
+	if options.ActivationState != "" {
+		if len(options.DeviceNumbers) > 0 || len(options.PhysicalVolumeNames) == 0 {
+			return fmt.Errorf("%w: volume groups can only be autoactivated by the names of their devices", ErrNotManagedByThisClient)
+		}
+		for _, pv := range options.PhysicalVolumeNames {
+			if err := g.guardPV(ctx, pv, false); err != nil {
+				return err
+			}
+		}
+	}
+	return g.Client.PVScan(ctx, opts...)
+}
+
+// VGScan refuses Mknodes, as it changes the device nodes of all active logical volumes.
+func (g *tagGuardClient) VGScan(ctx context.Context, opts ...VGScanOption) error {
+	options := VGScanOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGScanOptions(&options)
+	}
+	if options.Mknodes {
+		return fmt.Errorf("%w: device nodes can only be changed by logical volume", ErrNotManagedByThisClient)
+	}
+	return g.Client.VGScan(ctx, opts...)
+}
+
+// DevUpdate is refused, as the corrections of lvmdevices --update apply to all entries of the devices file.
+func (g *tagGuardClient) DevUpdate(context.Context, ...DevUpdateOption) (*DevUpdateResult, error) {
+	return nil, fmt.Errorf("%w: the devices file can only be changed by device", ErrNotManagedByThisClient)
+}
+
+func (g *tagGuardClient) DevModify(ctx context.Context, opts ...DevModifyOption) error {
+	options := DevModifyOptions{}
+	for _, opt := range opts {
+		opt.ApplyToDevModifyOptions(&options)
+	}
+	switch options.ModifyDeviceType {
+	case AddDev:
+		if err := g.guardPV(ctx, PhysicalVolumeName(options.Device), true); err != nil {
+			return err
+		}
+	case DelDev:
+		if err := g.guardPV(ctx, PhysicalVolumeName(options.Device), false); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: the devices file can only be changed by device", ErrNotManagedByThisClient)
+	}
+	return g.Client.DevModify(ctx, opts...)
+}
@@ -0,0 +1,184 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestTagGuardClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	base := fake.NewClient()
+	for _, device := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := base.SetDevice(device, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := base.VGCreate(ctx, VolumeGroupName("human"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.LVCreate(ctx, VolumeGroupName("human"), LogicalVolumeName("data"), MustParseSize("8M")); err != nil {
+		t.Fatal(err)
+	}
+
+	clnt := NewTagGuardClient(base, "controller")
+	if err := clnt.LVRemove(ctx, VolumeGroupName("human"), LogicalVolumeName("data")); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for an untagged logical volume, got %v", err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("human"), LogicalVolumeName("lv"), MustParseSize("8M")); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for an untagged volume group, got %v", err)
+	}
+	if err := clnt.PVRemove(ctx, PhysicalVolumeName("/dev/sdb")); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for a physical volume of an untagged volume group, got %v", err)
+	}
+	if err := clnt.VGRemove(ctx); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for a removal of all volume groups, got %v", err)
+	}
+
+	if err := clnt.VGCreate(ctx, VolumeGroupName("managed"), PhysicalVolumesFrom("/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("managed"), LogicalVolumeName("lv"), MustParseSize("8M")); err != nil {
+		t.Fatal(err)
+	}
+	lv, err := clnt.LV(ctx, VolumeGroupName("managed"), LogicalVolumeName("lv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(lv.Tags, "controller") {
+		t.Fatalf("expected created logical volume to be tagged, got %v", lv.Tags)
+	}
+	if err := clnt.LVExtend(ctx, VolumeGroupName("managed"), LogicalVolumeName("lv"), MustParsePrefixedSize("+4M")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVRemove(ctx, VolumeGroupName("managed"), LogicalVolumeName("lv")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.LV(ctx, VolumeGroupName("human"), LogicalVolumeName("data")); err != nil {
+		t.Fatalf("expected untagged logical volume to be untouched: %v", err)
+	}
+}
+
+func TestTagGuardClient_Select(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var args []string
+	runner := CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		args = cmd.Args
+		return io.NopCloser(strings.NewReader("")), nil
+	})
+
+	clnt := NewClient(runner, ManagedTag("controller"))
+	if _, ok := clnt.(WrappedClient); !ok {
+		t.Fatalf("expected client with ManagedTag to be wrapped, got %T", clnt)
+	}
+	if err := clnt.LVChange(ctx, Select("lv_name=data"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(args, func(arg string) bool {
+		return strings.HasPrefix(arg, "--select=") && strings.Contains(arg, "lv_tags") && strings.Contains(arg, "controller")
+	}) {
+		t.Fatalf("expected selection to be limited to the managed tag in %v", args)
+	}
+}
+
+func TestTagGuardClient_ChecksAndDevices(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	base := fake.NewClient()
+	for _, device := range []string{"/dev/sdb", "/dev/sdc", "/dev/sdd"} {
+		if err := base.SetDevice(device, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := base.VGCreate(ctx, VolumeGroupName("human"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewTagGuardClient(base, "controller")
+	if err := clnt.VGCreate(ctx, VolumeGroupName("managed"), PhysicalVolumesFrom("/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := clnt.VGCheck(ctx, VolumeGroupName("human"), UpdateMetadata(true)); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for a metadata update of an untagged volume group, got %v", err)
+	}
+	if _, err := clnt.VGCheck(ctx, VolumeGroupName("human")); errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected check of an untagged volume group to be allowed, got %v", err)
+	}
+	if _, err := clnt.VGCheck(ctx, VolumeGroupName("managed"), UpdateMetadata(true)); errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected metadata update of a tagged volume group to be allowed, got %v", err)
+	}
+
+	if _, err := clnt.PVCheck(ctx, PhysicalVolumeName("/dev/sdb"), Repair(true)); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for a repair of a physical volume of an untagged volume group, got %v", err)
+	}
+	if _, err := clnt.PVCheck(ctx, PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatalf("expected check of an untagged physical volume to be allowed, got %v", err)
+	}
+	if _, err := clnt.PVCheck(ctx, PhysicalVolumeName("/dev/sdc"), Repair(true)); errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected repair of a physical volume of a tagged volume group to be allowed, got %v", err)
+	}
+
+	for name, opts := range map[string][]PVScanOption{
+		"untagged volume group": {Cache(true), AutoActivate, PhysicalVolumeName("/dev/sdb")},
+		"device number":         {Cache(true), AutoActivate, DeviceNumber{Major: 8, Minor: 48}},
+		"all devices":           {Cache(true), AutoActivate},
+	} {
+		if err := clnt.PVScan(ctx, opts...); !errors.Is(err, ErrNotManagedByThisClient) {
+			t.Fatalf("expected ErrNotManagedByThisClient for an autoactivation of %s, got %v", name, err)
+		}
+	}
+	if err := clnt.PVScan(ctx, Cache(true), AutoActivate, PhysicalVolumeName("/dev/sdc")); errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected autoactivation of a tagged volume group to be allowed, got %v", err)
+	}
+	if err := clnt.PVScan(ctx, Cache(true)); err != nil {
+		t.Fatalf("expected scan without activation to be allowed, got %v", err)
+	}
+	if err := clnt.VGScan(ctx, Mknodes(true)); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for vgscan --mknodes, got %v", err)
+	}
+	if err := clnt.VGScan(ctx); err != nil {
+		t.Fatalf("expected vgscan to be allowed, got %v", err)
+	}
+
+	if _, err := clnt.DevUpdate(ctx); !errors.Is(err, ErrNotManagedByThisClient) {
+		t.Fatalf("expected ErrNotManagedByThisClient for an update of the devices file, got %v", err)
+	}
+	for _, modification := range []ModifyDevice{DelDevice("/dev/sdb"), DelDeviceByPVID("abc"), AddDeviceByPVID("abc")} {
+		if err := clnt.DevModify(ctx, modification); !errors.Is(err, ErrNotManagedByThisClient) {
+			t.Fatalf("expected ErrNotManagedByThisClient for %s of %s, got %v", modification.ModifyDeviceType, modification.Device, err)
+		}
+	}
+	for _, modification := range []ModifyDevice{AddDevice("/dev/sdd"), DelDevice("/dev/sdc")} {
+		if err := clnt.DevModify(ctx, modification); errors.Is(err, ErrNotManagedByThisClient) {
+			t.Fatalf("expected %s of %s to be allowed, got %v", modification.ModifyDeviceType, modification.Device, err)
+		}
+	}
+}
@@ -38,6 +38,9 @@ func (opt Tags) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
 func (opt Tags) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 	opts.Tags = opt
 }
+func (opt Tags) ApplyToDrainNodeOptions(opts *DrainNodeOptions) {
+	opts.Tags = opt
+}
 func (opt Tags) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
 	opts.Tags = opt
 }
@@ -58,6 +61,8 @@ func (opt Tags) ApplyToArgs(args Arguments) error {
 		fallthrough
 	case ArgsTypeVGChange:
 		fallthrough
+	case ArgsTypePVChange:
+		fallthrough
 	case ArgsTypeVGCreate:
 		fallthrough
 	case ArgsTypeLVCreate:
@@ -20,6 +20,13 @@ import (
 	"fmt"
 )
 
+// Thin creates a thin pool with LVCreate from the Size or Extents of the logical volume.
+// The pool can be tuned with ChunkSize, PoolMetadataSize and PoolMetadataSpare.
+//
+// Example:
+//
+//	err := client.LVCreate(ctx, lvm2go.VolumeGroupName("vg"), lvm2go.LogicalVolumeName("pool"),
+//		lvm2go.MustParseSize("10G"), lvm2go.Thin(true), lvm2go.ChunkSize(lvm2go.MustParseSize("64K")))
 type Thin bool
 
 func (opt Thin) ApplyToArgs(args Arguments) error {
@@ -33,6 +40,13 @@ func (opt Thin) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Thin = opt
 }
 
+// ThinPool creates a thin volume in the given thin pool with LVCreate.
+// The size of thin volumes is set with VirtualSize and can exceed the size of the pool.
+//
+// Example:
+//
+//	err := client.LVCreate(ctx, lvm2go.MustNewThinPool("vg", "pool"), lvm2go.LogicalVolumeName("thin"),
+//		lvm2go.MustParseSize("100G").Virtual())
 type ThinPool FQLogicalVolumeName
 
 func (opt *ThinPool) ApplyToLVCreateOptions(opts *LVCreateOptions) {
@@ -0,0 +1,343 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultThinPoolAutoExtendThreshold is the usage in percent of data or metadata
+	// at which a thin pool is extended, equivalent to activation/thin_pool_autoextend_threshold.
+	DefaultThinPoolAutoExtendThreshold = 70
+	// DefaultThinPoolAutoExtendPercent is the percentage of the current size by which a thin pool
+	// is extended, equivalent to activation/thin_pool_autoextend_percent.
+	DefaultThinPoolAutoExtendPercent = 20
+	// DefaultThinPoolAutoExtendInterval is the interval in which thin pools are checked by
+	// ThinPoolAutoExtender.Run.
+	DefaultThinPoolAutoExtendInterval = 10 * time.Second
+)
+
+var (
+	ErrThinPoolAutoExtendInvalidThreshold = errors.New("thin pool autoextend threshold must be greater than 0 and less than or equal to 100")
+	ErrThinPoolAutoExtendInvalidPercent   = errors.New("thin pool autoextend percent must be greater than 0")
+	ErrThinPoolAutoExtendNoFreeSpace      = errors.New("thin pool cannot be extended, volume group has no free space left")
+)
+
+// ThinPoolAutoExtenderColumnOptions are the columns of the logical volume report used by the ThinPoolAutoExtender.
+var ThinPoolAutoExtenderColumnOptions = ColumnOptions{
+	"lv_name", "vg_name", "lv_attr", "lv_size", "lv_metadata_size", "data_percent", "metadata_percent",
+}
+
+// ThinPoolAutoExtender extends thin pools once their data or metadata usage crosses a threshold.
+// It is an alternative to the monitoring of thin pools through dmeventd for environments
+// where dmeventd is not available, e.g. inside of containers.
+//
+// Like dmeventd, a thin pool whose data or metadata usage is greater than or equal to Threshold
+// is extended by Percent of its current data or metadata size.
+// The extension is bounded by the free space left in the volume group.
+// A Policy replaces this extension based on Threshold and Percent, and with DryRun the extensions
+// are only planned and logged.
+//
+// Example:
+//
+//	extender := &ThinPoolAutoExtender{
+//		Client:    client,
+//		Threshold: 80,
+//		Percent:   20,
+//	}
+//	go extender.Run(ctx)
+type ThinPoolAutoExtender struct {
+	Client Client
+
+	// VolumeGroupName limits the thin pools to the given volume group.
+	// If empty, thin pools in all volume groups are extended.
+	VolumeGroupName VolumeGroupName
+
+	// Threshold is the usage in percent at which a thin pool is extended.
+	// A Threshold of 100 disables the extension, like in lvm2.
+	// If zero, DefaultThinPoolAutoExtendThreshold is used.
+	Threshold float64
+	// Percent is the percentage of the current size by which a thin pool is extended.
+	// If zero, DefaultThinPoolAutoExtendPercent is used.
+	Percent float64
+	// Interval is the interval in which thin pools are checked by Run.
+	// If zero, DefaultThinPoolAutoExtendInterval is used.
+	Interval time.Duration
+
+	// Policy decides the extension of thin pools instead of Threshold and Percent if set,
+	// e.g. to extend by fixed sizes or to stop extending thin pools beyond a maximum size.
+	Policy ThinPoolAutoExtendPolicy
+	// DryRun makes Extend only return and log the extensions it would apply, without extending any thin pool.
+	DryRun bool
+}
+
+// ThinPoolAutoExtendPolicy decides the extension of a thin pool for the ThinPoolAutoExtender.
+// Plan is called with a thin pool reported with ThinPoolAutoExtenderColumnOptions in bytes
+// and the free space left in its volume group, and returns the sizes to add to the thin pool.
+// A ThinPoolAutoExtender without Policy is itself the policy based on its Threshold and Percent,
+// so that custom policies can build on it.
+type ThinPoolAutoExtendPolicy interface {
+	Plan(pool *LogicalVolume, vgFree Size) (*ThinPoolExtension, error)
+}
+
+// ThinPoolAutoExtendPolicyFunc is a function implementing ThinPoolAutoExtendPolicy.
+type ThinPoolAutoExtendPolicyFunc func(pool *LogicalVolume, vgFree Size) (*ThinPoolExtension, error)
+
+func (f ThinPoolAutoExtendPolicyFunc) Plan(pool *LogicalVolume, vgFree Size) (*ThinPoolExtension, error) {
+	return f(pool, vgFree)
+}
+
+// ThinPoolExtension describes the extension of a single thin pool.
+// Data and Metadata are the sizes added to the thin pool, a zero Val means no extension.
+type ThinPoolExtension struct {
+	ThinPool *FQLogicalVolumeName
+	Data     Size
+	Metadata Size
+}
+
+// IsEmpty returns true if the extension does not add any space to the thin pool.
+func (ext *ThinPoolExtension) IsEmpty() bool {
+	return ext.Data.Val == 0 && ext.Metadata.Val == 0
+}
+
+// inBytes returns the data and metadata sizes of the extension in bytes.
+func (ext *ThinPoolExtension) inBytes() (float64, float64, error) {
+	var bytes [2]float64
+	for i, size := range []Size{ext.Data, ext.Metadata} {
+		if size.Val == 0 {
+			continue
+		}
+		if size.Val < 0 {
+			return 0, 0, fmt.Errorf("negative size %s", size)
+		}
+		inBytes, err := size.ToUnit(UnitBytes)
+		if err != nil {
+			return 0, 0, err
+		}
+		bytes[i] = inBytes.Val
+	}
+	return bytes[0], bytes[1], nil
+}
+
+// Run checks and extends thin pools in the configured Interval until the context is canceled.
+// Errors of a single check are logged and do not stop the extender.
+// Run returns the error of the context once it is canceled.
+func (e *ThinPoolAutoExtender) Run(ctx context.Context) error {
+	interval := e.Interval
+	if interval == 0 {
+		interval = DefaultThinPoolAutoExtendInterval
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := e.Extend(ctx); err != nil {
+			clientLogger(e.Client).ErrorContext(ctx, "failed to autoextend thin pools", slog.String("error", err.Error()))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Extend checks all thin pools once and extends the ones that crossed the threshold.
+// It returns the extensions that were applied.
+// A failure to extend a thin pool does not prevent the extension of other thin pools,
+// all errors are joined and returned together.
+func (e *ThinPoolAutoExtender) Extend(ctx context.Context) ([]*ThinPoolExtension, error) {
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	opts := []LVsOption{ThinPoolAutoExtenderColumnOptions, UnitBytes}
+	if e.VolumeGroupName != "" {
+		opts = append(opts, e.VolumeGroupName)
+	}
+	lvs, err := e.Client.LVs(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list thin pools: %w", err)
+	}
+
+	var extensions []*ThinPoolExtension
+	var errs []error
+	free := make(map[VolumeGroupName]Size)
+	for _, lv := range lvs {
+		if lv.Attr.VolumeType != VolumeTypeThinPool {
+			continue
+		}
+
+		vgFree, ok := free[lv.VolumeGroupName]
+		if !ok {
+			vg, err := e.Client.VG(ctx, lv.VolumeGroupName, UnitBytes)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to get free space of volume group %s: %w", lv.VolumeGroupName, err))
+				continue
+			}
+			vgFree = vg.Free
+			free[lv.VolumeGroupName] = vgFree
+		}
+
+		ext, err := e.Plan(lv, vgFree)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if ext == nil || ext.IsEmpty() {
+			continue
+		}
+		data, metadata, err := ext.inBytes()
+		if err != nil {
+			errs = append(errs, fmt.Errorf("invalid extension of thin pool %s: %w", ext.ThinPool, err))
+			continue
+		}
+
+		if e.DryRun {
+			clientLogger(e.Client).InfoContext(ctx, "would extend thin pool",
+				slog.String("thin_pool", ext.ThinPool.String()),
+				slog.String("data", ext.Data.String()),
+				slog.String("metadata", ext.Metadata.String()),
+			)
+		} else if err := e.Client.LVExtend(ctx,
+			lv.VolumeGroupName,
+			lv.Name,
+			NewPrefixedSize(SizePrefixPlus, ext.Data),
+			PoolMetadataPrefixedSize(NewPrefixedSize(SizePrefixPlus, ext.Metadata)),
+		); err != nil {
+			errs = append(errs, fmt.Errorf("failed to extend thin pool %s: %w", ext.ThinPool, err))
+			continue
+		} else {
+			clientLogger(e.Client).InfoContext(ctx, "extended thin pool",
+				slog.String("thin_pool", ext.ThinPool.String()),
+				slog.String("data", ext.Data.String()),
+				slog.String("metadata", ext.Metadata.String()),
+			)
+		}
+
+		free[lv.VolumeGroupName] = NewSize(vgFree.Val-data-metadata, UnitBytes)
+		extensions = append(extensions, ext)
+	}
+
+	return extensions, errors.Join(errs...)
+}
+
+// Plan returns the extension that Extend would apply to the given thin pool
+// with vgFree left in its volume group, without extending the thin pool.
+// The sizes of the thin pool and vgFree must have a known unit.
+// If a Policy is set, the extension is planned by the Policy.
+//
+// If the volume group has no free space left for a required extension,
+// ErrThinPoolAutoExtendNoFreeSpace is returned.
+func (e *ThinPoolAutoExtender) Plan(pool *LogicalVolume, vgFree Size) (*ThinPoolExtension, error) {
+	if e.Policy != nil {
+		ext, err := e.Policy.Plan(pool, vgFree)
+		if err != nil || ext == nil {
+			return ext, err
+		}
+		if ext.ThinPool == nil {
+			if ext.ThinPool, err = pool.GetFQLogicalVolumeName(); err != nil {
+				return nil, err
+			}
+		}
+		// sizes left empty by the policy add nothing
+		for _, size := range []*Size{&ext.Data, &ext.Metadata} {
+			if size.Val == 0 {
+				*size = NewSize(0, UnitBytes)
+			}
+		}
+		return ext, nil
+	}
+
+	if err := e.validate(); err != nil {
+		return nil, err
+	}
+
+	fq, err := pool.GetFQLogicalVolumeName()
+	if err != nil {
+		return nil, err
+	}
+	ext := &ThinPoolExtension{
+		ThinPool: fq,
+		Data:     NewSize(0, UnitBytes),
+		Metadata: NewSize(0, UnitBytes),
+	}
+
+	threshold, percent := e.thresholdAndPercent()
+	if threshold >= 100 {
+		return ext, nil
+	}
+
+	free, err := vgFree.ToUnit(UnitBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine free space of volume group %s: %w", pool.VolumeGroupName, err)
+	}
+
+	for _, target := range []struct {
+		usage    float64
+		size     Size
+		increase *Size
+	}{
+		{pool.DataPercent, pool.Size, &ext.Data},
+		{pool.MetadataPercent, pool.MetadataSize, &ext.Metadata},
+	} {
+		if target.usage < threshold {
+			continue
+		}
+		size, err := target.size.ToUnit(UnitBytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine size of thin pool %s: %w", fq, err)
+		}
+		if free.Val <= 0 {
+			return nil, fmt.Errorf("%w: %s", ErrThinPoolAutoExtendNoFreeSpace, fq)
+		}
+		increase := math.Min(math.Ceil(size.Val*percent/100), free.Val)
+		*target.increase = NewSize(increase, UnitBytes)
+		free.Val -= increase
+	}
+
+	return ext, nil
+}
+
+func (e *ThinPoolAutoExtender) thresholdAndPercent() (float64, float64) {
+	threshold, percent := e.Threshold, e.Percent
+	if threshold == 0 {
+		threshold = DefaultThinPoolAutoExtendThreshold
+	}
+	if percent == 0 {
+		percent = DefaultThinPoolAutoExtendPercent
+	}
+	return threshold, percent
+}
+
+func (e *ThinPoolAutoExtender) validate() error {
+	threshold, percent := e.thresholdAndPercent()
+	if threshold < 0 || threshold > 100 {
+		return ErrThinPoolAutoExtendInvalidThreshold
+	}
+	if percent < 0 {
+		return ErrThinPoolAutoExtendInvalidPercent
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"os/exec"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestThinPoolAutoExtender_Plan(t *testing.T) {
+	t.Parallel()
+	pool := func(data, metadata float64) *LogicalVolume {
+		return &LogicalVolume{
+			Name:            "pool",
+			VolumeGroupName: "vg",
+			Size:            MustParseSize("100m"),
+			MetadataSize:    MustParseSize("4m"),
+			DataPercent:     data,
+			MetadataPercent: metadata,
+		}
+	}
+	mib := func(val float64) Size {
+		return NewSize(val*1024*1024, UnitBytes)
+	}
+
+	for _, tc := range []struct {
+		name     string
+		extender ThinPoolAutoExtender
+		pool     *LogicalVolume
+		free     Size
+		data     Size
+		metadata Size
+		err      error
+	}{
+		{
+			name:     "below threshold",
+			extender: ThinPoolAutoExtender{Threshold: 80, Percent: 20},
+			pool:     pool(50, 50),
+			free:     mib(1000),
+			data:     mib(0),
+			metadata: mib(0),
+		},
+		{
+			name:     "data above threshold",
+			extender: ThinPoolAutoExtender{Threshold: 80, Percent: 20},
+			pool:     pool(85, 10),
+			free:     mib(1000),
+			data:     mib(20),
+			metadata: mib(0),
+		},
+		{
+			name:     "data and metadata above threshold",
+			extender: ThinPoolAutoExtender{Threshold: 80, Percent: 50},
+			pool:     pool(90, 90),
+			free:     mib(1000),
+			data:     mib(50),
+			metadata: mib(2),
+		},
+		{
+			name:     "bounded by free space",
+			extender: ThinPoolAutoExtender{Threshold: 80, Percent: 50},
+			pool:     pool(90, 90),
+			free:     mib(51),
+			data:     mib(50),
+			metadata: mib(1),
+		},
+		{
+			name:     "disabled",
+			extender: ThinPoolAutoExtender{Threshold: 100, Percent: 20},
+			pool:     pool(100, 100),
+			free:     mib(1000),
+			data:     mib(0),
+			metadata: mib(0),
+		},
+		{
+			name:     "no free space",
+			extender: ThinPoolAutoExtender{Threshold: 80, Percent: 20},
+			pool:     pool(90, 10),
+			free:     mib(0),
+			err:      ErrThinPoolAutoExtendNoFreeSpace,
+		},
+		{
+			name:     "invalid threshold",
+			extender: ThinPoolAutoExtender{Threshold: 120},
+			pool:     pool(90, 10),
+			free:     mib(1000),
+			err:      ErrThinPoolAutoExtendInvalidThreshold,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			ext, err := tc.extender.Plan(tc.pool, tc.free)
+			if tc.err != nil {
+				if !errors.Is(err, tc.err) {
+					t.Fatalf("expected error %v, got %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ext.Data != tc.data {
+				t.Fatalf("expected data extension %s, got %s", tc.data, ext.Data)
+			}
+			if ext.Metadata != tc.metadata {
+				t.Fatalf("expected metadata extension %s, got %s", tc.metadata, ext.Metadata)
+			}
+		})
+	}
+}
+
+func TestThinPoolAutoExtender_PolicyAndDryRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	client := fake.NewClient()
+	if err := client.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("40M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	poolSize := func() Size {
+		t.Helper()
+		pool, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), UnitMiB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pool.Size
+	}
+
+	// extend every thin pool by 8 MiB, regardless of its usage
+	extender := &ThinPoolAutoExtender{
+		Client: client,
+		Policy: ThinPoolAutoExtendPolicyFunc(func(pool *LogicalVolume, vgFree Size) (*ThinPoolExtension, error) {
+			return &ThinPoolExtension{Data: MustParseSize("8M")}, nil
+		}),
+		DryRun: true,
+	}
+	extensions, err := extender.Extend(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extensions) != 1 || extensions[0].ThinPool.String() != "vg/pool" {
+		t.Fatalf("expected planned extension of vg/pool, got %v", extensions)
+	}
+	if size := poolSize(); size.Val != 40 {
+		t.Fatalf("expected dry run to keep the thin pool at 40 MiB, got %s", size)
+	}
+
+	extender.DryRun = false
+	if _, err := extender.Extend(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if size := poolSize(); size.Val != 48 {
+		t.Fatalf("expected thin pool to be extended to 48 MiB, got %s", size)
+	}
+}
+
+// vgCountingClient counts the volume group lookups of the wrapped client.
+type vgCountingClient struct {
+	Client
+	lookups int
+}
+
+func (c *vgCountingClient) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error) {
+	c.lookups++
+	return c.Client.VG(ctx, opts...)
+}
+
+func TestThinPoolAutoExtender_VolumeGroupLookedUpOnce(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	base := fake.NewClient()
+	if err := base.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := base.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	for _, pool := range []LogicalVolumeName{"pool1", "pool2"} {
+		if err := base.LVCreate(ctx, VolumeGroupName("vg"), pool, MustParseSize("16M"), Thin(true)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the thin pools are empty, so none of them is extended
+	client := &vgCountingClient{Client: base}
+	extensions, err := (&ThinPoolAutoExtender{Client: client}).Extend(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(extensions) != 0 {
+		t.Fatalf("expected no extensions of empty thin pools, got %v", extensions)
+	}
+	if client.lookups != 1 {
+		t.Fatalf("expected the volume group to be looked up once, got %d lookups", client.lookups)
+	}
+}
+
+func TestThinPoolAutoExtender_RunLogsToClientLogger(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the first check fails and cancels the context, so Run returns after logging the failure
+	var logs bytes.Buffer
+	client := NewClient(
+		NewClientLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+			cancel()
+			return nil, errors.New("lvs failed")
+		}),
+	)
+
+	if err := (&ThinPoolAutoExtender{Client: client}).Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the context to be canceled, got %v", err)
+	}
+	if !strings.Contains(logs.String(), "failed to autoextend thin pools") {
+		t.Fatalf("expected the failure to be logged to the client logger, got %q", logs.String())
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestThinPoolTuning(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	errorWhenFull := ErrorWhenFull(true)
+	args, err := LVChangeOptionsList{
+		VolumeGroupName("vg"), LogicalVolumeName("pool"), Activate, &errorWhenFull, DiscardsNoPassdown, DoNotZeroVolume,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--zero=n", "--errorwhenfull=y", "--discards nopassdown"} {
+		if raw := strings.Join(args.GetRaw(), " "); !strings.Contains(raw, arg) {
+			t.Fatalf("expected %s in args %s", arg, raw)
+		}
+	}
+	if _, err := (LVChangeOptionsList{VolumeGroupName("vg"), LogicalVolumeName("pool"), Discards("all")}).AsArgs(); err == nil {
+		t.Fatal("expected error for invalid discards")
+	}
+
+	client := fake.NewClient()
+	if err := client.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := client.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), MustParseSize("40M"), Thin(true)); err != nil {
+		t.Fatal(err)
+	}
+	pool, err := client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pool.WhenFull != WhenFullQueue || pool.Attr.ZeroAttr != ZeroAttrTrue {
+		t.Fatalf("expected zeroing thin pool queueing when full, got %s, %s", pool.WhenFull, pool.Attr)
+	}
+
+	if err := client.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool"), &errorWhenFull, DoNotZeroVolume); err != nil {
+		t.Fatal(err)
+	}
+	if pool, err = client.LV(ctx, VolumeGroupName("vg"), LogicalVolumeName("pool")); err != nil {
+		t.Fatal(err)
+	}
+	if pool.WhenFull != WhenFullError || pool.Attr.ZeroAttr != ZeroAttrFalse {
+		t.Fatalf("expected thin pool without zeroing erroring when full, got %s, %s", pool.WhenFull, pool.Attr)
+	}
+}
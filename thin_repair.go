@@ -0,0 +1,327 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type (
+	RepairThinPoolOptions struct {
+		ThinPoolRepairProgress
+		KeepDamagedMetadata
+		RepairIntactMetadata
+	}
+	RepairThinPoolOption interface {
+		ApplyToRepairThinPoolOptions(opts *RepairThinPoolOptions)
+	}
+)
+
+// ThinPoolRepairStep is a step of RepairThinPool.
+type ThinPoolRepairStep string
+
+const (
+	// ThinPoolRepairStepDeactivate deactivates the thin pool, as its metadata can only be swapped while inactive.
+	ThinPoolRepairStepDeactivate ThinPoolRepairStep = "deactivate"
+	// ThinPoolRepairStepSwapOut swaps the metadata of the thin pool into a temporary logical volume.
+	ThinPoolRepairStepSwapOut ThinPoolRepairStep = "swap-out"
+	// ThinPoolRepairStepCheck checks the swapped out metadata with thin_check.
+	ThinPoolRepairStepCheck ThinPoolRepairStep = "check"
+	// ThinPoolRepairStepRepair writes repaired metadata into a second temporary logical volume with thin_repair
+	// and checks it with thin_check.
+	ThinPoolRepairStepRepair ThinPoolRepairStep = "repair"
+	// ThinPoolRepairStepSwapIn swaps the repaired, or the intact original, metadata back into the thin pool.
+	ThinPoolRepairStepSwapIn ThinPoolRepairStep = "swap-in"
+	// ThinPoolRepairStepCleanup removes the temporary logical volumes.
+	ThinPoolRepairStepCleanup ThinPoolRepairStep = "cleanup"
+	// ThinPoolRepairStepRollback swaps the original metadata back into the thin pool after a failed step.
+	ThinPoolRepairStepRollback ThinPoolRepairStep = "rollback"
+)
+
+// ThinPoolRepairProgress is called by RepairThinPool before every step.
+type ThinPoolRepairProgress func(step ThinPoolRepairStep)
+
+func (opt ThinPoolRepairProgress) ApplyToRepairThinPoolOptions(opts *RepairThinPoolOptions) {
+	opts.ThinPoolRepairProgress = opt
+}
+
+func (opt ThinPoolRepairProgress) report(step ThinPoolRepairStep) {
+	if opt != nil {
+		opt(step)
+	}
+}
+
+// KeepDamagedMetadata keeps the original metadata swapped out of the thin pool by RepairThinPool
+// in the logical volume returned by it, e.g. to inspect it with thin_dump, instead of removing it.
+type KeepDamagedMetadata bool
+
+func (opt KeepDamagedMetadata) ApplyToRepairThinPoolOptions(opts *RepairThinPoolOptions) {
+	opts.KeepDamagedMetadata = opt
+}
+
+// RepairIntactMetadata makes RepairThinPool rewrite the metadata with thin_repair
+// even if thin_check finds no problems.
+type RepairIntactMetadata bool
+
+func (opt RepairIntactMetadata) ApplyToRepairThinPoolOptions(opts *RepairThinPoolOptions) {
+	opts.RepairIntactMetadata = opt
+}
+
+// repairThinPoolRollbackTimeout bounds the rollback of RepairThinPool, which also runs if ctx is cancelled.
+const repairThinPoolRollbackTimeout = 2 * time.Minute
+
+// RepairThinPool checks the metadata of the thin pool with thin_check and repairs it with thin_repair if needed,
+// as an alternative to Repair that reports every step and never leaves the thin pool without its metadata.
+//
+// The thin pool is deactivated and its metadata is swapped into a temporary logical volume with
+// lvconvert --swapmetadata. If thin_check finds no problems and RepairIntactMetadata is not set,
+// the metadata is swapped back unchanged. Otherwise thin_repair writes repaired metadata into a second
+// temporary logical volume, which is checked again and swapped into the thin pool. The thin pool stays inactive.
+// If a step fails after the metadata was swapped out, the original metadata is swapped back
+// and the temporary logical volumes are removed before the error is returned.
+// Errors removing the temporary logical volumes after the metadata was swapped in are returned without a rollback.
+//
+// With KeepDamagedMetadata, the original metadata is kept after a repair and the returned logical volume names it,
+// otherwise nil is returned. The volume group needs free space for twice the size of the pool metadata.
+// The tools are executed through the exec layer of the client, which has to implement RawCommandRunner.
+// Clients returned by NewLockingClient hold their lock across the whole repair,
+// so that no other call of the client can use the thin pool while its metadata is swapped out.
+func RepairThinPool(ctx context.Context, client Client, pool *FQLogicalVolumeName, opts ...RepairThinPoolOption) (*FQLogicalVolumeName, error) {
+	options := RepairThinPoolOptions{}
+	for _, opt := range opts {
+		opt.ApplyToRepairThinPoolOptions(&options)
+	}
+
+	if err := pool.Validate(); err != nil {
+		return nil, err
+	}
+
+	var kept *FQLogicalVolumeName
+	err := atomically(client, func(client Client) (err error) {
+		kept, err = repairThinPool(ctx, client, pool, options)
+		return err
+	})
+	return kept, err
+}
+
+func repairThinPool(ctx context.Context, client Client, pool *FQLogicalVolumeName, options RepairThinPoolOptions) (*FQLogicalVolumeName, error) {
+	runner, ok := ClientImplements[RawCommandRunner](client)
+	if !ok {
+		return nil, fmt.Errorf("%w: client cannot run raw commands", errors.ErrUnsupported)
+	}
+
+	lv, err := client.LV(ctx, pool.VolumeGroupName, pool.LogicalVolumeName, UnitBytes)
+	if err != nil {
+		return nil, err
+	}
+	if lv.Attr.VolumeType != VolumeTypeThinPool {
+		return nil, fmt.Errorf("%s is not a thin pool", pool)
+	}
+	if lv.MetadataSize.Val <= 0 {
+		return nil, fmt.Errorf("unknown metadata size of thin pool %s", pool)
+	}
+
+	options.ThinPoolRepairProgress.report(ThinPoolRepairStepDeactivate)
+	if err := client.LVChange(ctx, pool.VolumeGroupName, pool.LogicalVolumeName, Deactivate); err != nil {
+		return nil, fmt.Errorf("failed to deactivate thin pool %s: %w", pool, err)
+	}
+
+	names, err := freeLogicalVolumeNames(ctx, client, pool, 2)
+	if err != nil {
+		return nil, err
+	}
+	repair := &thinPoolRepair{
+		client:   client,
+		runner:   runner,
+		pool:     pool,
+		damaged:  names[0],
+		repaired: names[1],
+		options:  options,
+	}
+	if err := repair.createMetadataVolume(ctx, repair.damaged, lv.MetadataSize); err != nil {
+		return nil, err
+	}
+	if err := repair.createMetadataVolume(ctx, repair.repaired, lv.MetadataSize); err != nil {
+		return nil, errors.Join(err, repair.remove(context.WithoutCancel(ctx), repair.damaged))
+	}
+
+	kept, err := repair.run(ctx)
+	if err != nil && !repair.swappedIn {
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), repairThinPoolRollbackTimeout)
+		defer cancel()
+		return nil, errors.Join(err, repair.rollback(rollbackCtx))
+	}
+	return kept, err
+}
+
+type thinPoolRepair struct {
+	client            Client
+	runner            RawCommandRunner
+	pool              *FQLogicalVolumeName
+	damaged, repaired *FQLogicalVolumeName
+	options           RepairThinPoolOptions
+
+	// swappedOut is set while the original metadata of the pool is in damaged.
+	swappedOut bool
+	// swappedIn is set once the repaired or intact metadata is back in the pool.
+	swappedIn bool
+	// removed are the temporary logical volumes already removed.
+	removed map[*FQLogicalVolumeName]bool
+}
+
+func (r *thinPoolRepair) run(ctx context.Context) (*FQLogicalVolumeName, error) {
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepSwapOut)
+	if err := r.swap(ctx, r.damaged); err != nil {
+		return nil, err
+	}
+	r.swappedOut = true
+
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepCheck)
+	checkErr := r.check(ctx, r.damaged)
+	if checkErr == nil && !r.options.RepairIntactMetadata {
+		r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepSwapIn)
+		if err := r.swap(ctx, r.damaged); err != nil {
+			return nil, err
+		}
+		r.swappedOut, r.swappedIn = false, true
+		return nil, r.cleanup(ctx, r.damaged, r.repaired)
+	}
+
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepRepair)
+	if err := r.repair(ctx); err != nil {
+		return nil, errors.Join(checkErr, err)
+	}
+
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepSwapIn)
+	if err := r.swap(ctx, r.repaired); err != nil {
+		return nil, err
+	}
+	r.swappedOut, r.swappedIn = false, true
+
+	// repaired now holds the metadata swapped out of the pool before, which is not used anymore
+	if r.options.KeepDamagedMetadata {
+		return r.damaged, r.cleanup(ctx, r.repaired)
+	}
+	return nil, r.cleanup(ctx, r.repaired, r.damaged)
+}
+
+func (r *thinPoolRepair) createMetadataVolume(ctx context.Context, lv *FQLogicalVolumeName, size Size) error {
+	// inactive logical volumes cannot be zeroed
+	if err := r.client.LVCreate(ctx, lv.VolumeGroupName, lv.LogicalVolumeName, size, Deactivate, DoNotZeroVolume); err != nil {
+		return fmt.Errorf("failed to create %s for the metadata of thin pool %s: %w", lv, r.pool, err)
+	}
+	return nil
+}
+
+// swap swaps the metadata of the pool with lv, which has to be inactive.
+func (r *thinPoolRepair) swap(ctx context.Context, lv *FQLogicalVolumeName) error {
+	if err := r.client.LVChange(ctx, lv.VolumeGroupName, lv.LogicalVolumeName, Deactivate); err != nil {
+		return fmt.Errorf("failed to deactivate %s: %w", lv, err)
+	}
+	if err := r.client.LVConvert(ctx, r.pool.VolumeGroupName, r.pool.LogicalVolumeName,
+		SwapMetadata(true), (*PoolMetadata)(lv)); err != nil {
+		return fmt.Errorf("failed to swap the metadata of thin pool %s with %s: %w", r.pool, lv, err)
+	}
+	return nil
+}
+
+func (r *thinPoolRepair) check(ctx context.Context, lv *FQLogicalVolumeName) error {
+	if err := r.client.LVChange(ctx, lv.VolumeGroupName, lv.LogicalVolumeName, Activate); err != nil {
+		return fmt.Errorf("failed to activate %s: %w", lv, err)
+	}
+	if _, err := runRawOutput(ctx, r.runner, "thin_check", lv.DevicePath()); err != nil {
+		return fmt.Errorf("thin_check of the metadata of thin pool %s failed: %w", r.pool, err)
+	}
+	return nil
+}
+
+func (r *thinPoolRepair) repair(ctx context.Context) error {
+	if err := r.client.LVChange(ctx, r.repaired.VolumeGroupName, r.repaired.LogicalVolumeName, Activate); err != nil {
+		return fmt.Errorf("failed to activate %s: %w", r.repaired, err)
+	}
+	if _, err := runRawOutput(ctx, r.runner, "thin_repair",
+		"-i", r.damaged.DevicePath(), "-o", r.repaired.DevicePath()); err != nil {
+		return fmt.Errorf("thin_repair of the metadata of thin pool %s failed: %w", r.pool, err)
+	}
+	return r.check(ctx, r.repaired)
+}
+
+func (r *thinPoolRepair) cleanup(ctx context.Context, lvs ...*FQLogicalVolumeName) error {
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepCleanup)
+	return r.remove(ctx, lvs...)
+}
+
+func (r *thinPoolRepair) remove(ctx context.Context, lvs ...*FQLogicalVolumeName) error {
+	if r.removed == nil {
+		r.removed = make(map[*FQLogicalVolumeName]bool, len(lvs))
+	}
+	var errs []error
+	for _, lv := range lvs {
+		if r.removed[lv] {
+			continue
+		}
+		if err := r.client.LVRemove(ctx, lv.VolumeGroupName, lv.LogicalVolumeName, Force(true)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove %s: %w", lv, err))
+			continue
+		}
+		r.removed[lv] = true
+	}
+	return errors.Join(errs...)
+}
+
+// rollback swaps the original metadata back into the pool if it is still swapped out
+// and removes the temporary logical volumes. If the swap fails, the temporary logical volumes are kept,
+// as damaged holds the only copy of the original metadata.
+func (r *thinPoolRepair) rollback(ctx context.Context) error {
+	r.options.ThinPoolRepairProgress.report(ThinPoolRepairStepRollback)
+	if r.swappedOut {
+		if err := r.swap(ctx, r.damaged); err != nil {
+			return fmt.Errorf("failed to roll back, the original metadata of thin pool %s is kept in %s: %w", r.pool, r.damaged, err)
+		}
+		r.swappedOut = false
+	}
+	return r.remove(ctx, r.damaged, r.repaired)
+}
+
+// freeLogicalVolumeNames returns n unused names <pool>_metaN in the volume group of the pool,
+// following the naming of the metadata kept by lvconvert --repair.
+func freeLogicalVolumeNames(ctx context.Context, client Client, pool *FQLogicalVolumeName, n int) ([]*FQLogicalVolumeName, error) {
+	lvs, err := client.LVs(ctx, pool.VolumeGroupName)
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[LogicalVolumeName]bool, len(lvs))
+	for _, lv := range lvs {
+		used[lv.Name] = true
+	}
+	names := make([]*FQLogicalVolumeName, 0, n)
+	for i := 0; len(names) < n; i++ {
+		name := LogicalVolumeName(fmt.Sprintf("%s_meta%d", pool.LogicalVolumeName, i))
+		if used[name] {
+			continue
+		}
+		fq, err := NewFQLogicalVolumeName(pool.VolumeGroupName, name)
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, fq)
+	}
+	return names, nil
+}
@@ -0,0 +1,182 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestRepairThinPool(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := NewFQLogicalVolumeName("vg", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(failing string, opts ...RepairThinPoolOption) ([]string, []ThinPoolRepairStep, *FQLogicalVolumeName, error) {
+		var commands []string
+		var steps []ThinPoolRepairStep
+		clnt := NewClient(CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+			command := strings.Join(cmd.Args, " ")
+			if i := strings.Index(command, "lvm "); i >= 0 {
+				command = command[i+len("lvm "):]
+			}
+			commands = append(commands, command)
+			if failing != "" && strings.Contains(command, failing) {
+				return nil, errors.New("failed")
+			}
+			if strings.HasPrefix(command, "lvs") {
+				return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"pool","vg_name":"vg",` +
+					`"lv_attr":"twi-a-tz--","lv_size":"1073741824B","lv_metadata_size":"4194304B"}]}]}`)), nil
+			}
+			return io.NopCloser(strings.NewReader("")), nil
+		}))
+		progress := ThinPoolRepairProgress(func(step ThinPoolRepairStep) {
+			steps = append(steps, step)
+		})
+		kept, err := RepairThinPool(ctx, clnt, pool, append(opts, progress)...)
+		return commands, steps, kept, err
+	}
+	contains := func(commands []string, parts ...string) int {
+		return slices.IndexFunc(commands, func(command string) bool {
+			for _, part := range parts {
+				if !strings.Contains(command, part) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	// damaged metadata is repaired into vg/pool_meta1 and swapped in
+	commands, steps, kept, err := run("thin_check /dev/vg/pool_meta0", KeepDamagedMetadata(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept == nil || kept.String() != "vg/pool_meta0" {
+		t.Fatalf("expected the damaged metadata to be kept in vg/pool_meta0, got %v", kept)
+	}
+	expectedSteps := []ThinPoolRepairStep{
+		ThinPoolRepairStepDeactivate, ThinPoolRepairStepSwapOut, ThinPoolRepairStepCheck,
+		ThinPoolRepairStepRepair, ThinPoolRepairStepSwapIn, ThinPoolRepairStepCleanup,
+	}
+	if !slices.Equal(steps, expectedSteps) {
+		t.Fatalf("expected steps %v, got %v", expectedSteps, steps)
+	}
+	swapOut := contains(commands, "lvconvert", "--swapmetadata", "--poolmetadata=vg/pool_meta0")
+	repair := contains(commands, "thin_repair -i /dev/vg/pool_meta0 -o /dev/vg/pool_meta1")
+	swapIn := contains(commands, "lvconvert", "--swapmetadata", "--poolmetadata=vg/pool_meta1")
+	if swapOut < 0 || repair < swapOut || swapIn < repair {
+		t.Fatalf("unexpected commands %v", commands)
+	}
+	if contains(commands, "lvremove", "vg/pool_meta1") < swapIn || contains(commands, "lvremove", "vg/pool_meta0") >= 0 {
+		t.Fatalf("expected only vg/pool_meta1 to be removed in %v", commands)
+	}
+
+	// intact metadata is swapped back unchanged
+	commands, steps, kept, err = run("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kept != nil || contains(commands, "thin_repair") >= 0 || slices.Contains(steps, ThinPoolRepairStepRepair) {
+		t.Fatalf("expected no repair of intact metadata, got %v", commands)
+	}
+	if contains(commands, "lvremove", "vg/pool_meta0") < 0 || contains(commands, "lvremove", "vg/pool_meta1") < 0 {
+		t.Fatalf("expected the temporary logical volumes to be removed in %v", commands)
+	}
+
+	// a failed repair swaps the original metadata back
+	commands, steps, _, err = run("thin_repair", RepairIntactMetadata(true))
+	if err == nil {
+		t.Fatal("expected error for a failed thin_repair")
+	}
+	if steps[len(steps)-1] != ThinPoolRepairStepRollback {
+		t.Fatalf("expected a rollback, got steps %v", steps)
+	}
+	repair = contains(commands, "thin_repair")
+	rollback := slices.IndexFunc(commands[repair+1:], func(command string) bool {
+		return strings.Contains(command, "--swapmetadata") && strings.Contains(command, "--poolmetadata=vg/pool_meta0")
+	})
+	if rollback < 0 || contains(commands, "lvremove", "vg/pool_meta0") < repair || contains(commands, "lvremove", "vg/pool_meta1") < repair {
+		t.Fatalf("expected the original metadata to be swapped back in %v", commands)
+	}
+
+	if _, err := RepairThinPool(ctx, NewClient(), &FQLogicalVolumeName{}); err == nil {
+		t.Fatal("expected error for an invalid thin pool name")
+	}
+}
+
+func TestRepairThinPoolWithWrappedClient(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	pool, err := NewFQLogicalVolumeName("vg", "pool")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var mu sync.Mutex
+	var commands [][]string
+	var concurrent sync.WaitGroup
+	var clnt Client
+	clnt = NewLockingClient(WithNoNsenter(NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		mu.Lock()
+		commands = append(commands, cmd.Args)
+		mu.Unlock()
+		switch {
+		case cmd.Args[0] == "thin_check" && slices.Contains(cmd.Args, "/dev/vg/pool_meta0"):
+			// a concurrent call of the client has to wait for the end of the repair
+			concurrent.Add(1)
+			go func() {
+				defer concurrent.Done()
+				_, _ = clnt.VGs(ctx)
+			}()
+			// give the concurrent call time to block on the lock
+			time.Sleep(10 * time.Millisecond)
+		case slices.Contains(cmd.Args, "lvs"):
+			return io.NopCloser(strings.NewReader(`{"report":[{"lv":[{"lv_name":"pool","vg_name":"vg",` +
+				`"lv_attr":"twi-a-tz--","lv_size":"1073741824B","lv_metadata_size":"4194304B"}]}]}`)), nil
+		case slices.Contains(cmd.Args, "vgs"):
+			return io.NopCloser(strings.NewReader(`{"report":[{"vg":[]}]}`)), nil
+		}
+		return io.NopCloser(strings.NewReader("")), nil
+	}))))
+
+	if _, err := RepairThinPool(ctx, clnt, pool); err != nil {
+		t.Fatal(err)
+	}
+	concurrent.Wait()
+
+	if last := commands[len(commands)-1]; !slices.Contains(last, "vgs") {
+		t.Fatalf("expected the concurrent call to run after the repair, got %v", commands)
+	}
+	for _, command := range commands {
+		if command[0] == "/usr/bin/nsenter" {
+			t.Fatalf("expected commands to run without nsenter, got %v", command)
+		}
+	}
+}
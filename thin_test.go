@@ -0,0 +1,57 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestThinPoolLVCreateOptions(t *testing.T) {
+	t.Parallel()
+	vg, lv := VolumeGroupName("vg"), LogicalVolumeName("pool")
+
+	args, err := LVCreateOptionList{
+		vg, lv, MustParseSize("10G"), Thin(true),
+		ChunkSize(MustParseSize("64K")),
+		PoolMetadataSize(MustParseSize("128M")),
+		NoPoolMetadataSpare,
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--thin", "--chunksize=64.00k", "--poolmetadataspare=n"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %q in %v", arg, args.GetRaw())
+		}
+	}
+	if !slices.ContainsFunc(args.GetRaw(), func(arg string) bool { return strings.HasPrefix(arg, "--poolmetadatasize=") }) {
+		t.Fatalf("expected --poolmetadatasize in %v", args.GetRaw())
+	}
+
+	for name, opts := range map[string]LVCreateOptionList{
+		"metadata size without pool":  {vg, lv, MustParseSize("10G"), PoolMetadataSize(MustParseSize("128M"))},
+		"metadata spare without pool": {vg, lv, MustParseSize("10G"), CreatePoolMetadataSpare},
+	} {
+		if _, err := opts.AsArgs(); err == nil {
+			t.Errorf("expected error for %s", name)
+		}
+	}
+}
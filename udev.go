@@ -0,0 +1,120 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// NoUdevSync makes commands that create or remove device nodes not wait for udev to process their events,
+// e.g. in environments without a running udev daemon, in which the commands would otherwise hang.
+// Device nodes and symlinks are then created by lvm2 itself, see also NoUdevRules.
+type NoUdevSync bool
+
+func (opt NoUdevSync) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--noudevsync"})
+	}
+	return nil
+}
+
+func (opt NoUdevSync) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVRenameOptions(opts *LVRenameOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.NoUdevSync = opt
+}
+
+func (opt NoUdevSync) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
+	opts.NoUdevSync = opt
+}
+
+// NoUdevRules makes lvm2 create the device nodes and symlinks of logical volumes itself instead of relying
+// on its udev rules, by overriding activation/udev_rules for the command. It is usually combined with NoUdevSync.
+type NoUdevRules bool
+
+func (opt NoUdevRules) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplace("--config=activation/udev_rules=0")
+	}
+	return nil
+}
+
+func (opt NoUdevRules) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVCreateOptions(opts *LVCreateOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVReduceOptions(opts *LVReduceOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVRemoveOptions(opts *LVRemoveOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVRenameOptions(opts *LVRenameOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.NoUdevRules = opt
+}
+
+func (opt NoUdevRules) ApplyToVGRemoveOptions(opts *VGRemoveOptions) {
+	opts.NoUdevRules = opt
+}
@@ -0,0 +1,44 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+// UsePolicies extends the logical volume according to the autoextend policy
+// (activation/thin_pool_autoextend_threshold and activation/thin_pool_autoextend_percent)
+// configured in lvm.conf or the profile of the logical volume.
+// This is the same code path that is used by dmeventd to automatically extend thin pools and snapshots.
+// Combined with Repair in LVConvert, failed devices are handled according to
+// activation/raid_fault_policy and activation/mirror_image_fault_policy instead.
+type UsePolicies bool
+
+func (opt UsePolicies) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--use-policies"})
+	}
+	return nil
+}
+
+func (opt UsePolicies) ApplyToLVExtendOptions(opts *LVExtendOptions) {
+	opts.UsePolicies = opt
+}
+
+func (opt UsePolicies) ApplyToLVResizeOptions(opts *LVResizeOptions) {
+	opts.UsePolicies = opt
+}
+
+func (opt UsePolicies) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.UsePolicies = opt
+}
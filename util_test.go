@@ -265,10 +265,9 @@ func (vg TestVolumeGroup) MakeTestLogicalVolume(template TestLogicalVolume) Test
 	var sizeOption LVCreateOption
 	if size := template.Size(); size.Val > 0 {
 		var err error
-		if size, err = size.ToUnit(UnitBytes); err != nil {
+		if size, err = SizeAlignmentRoundDown.Align(size, TestExtentSize); err != nil {
 			vg.t.Fatal(err)
 		}
-		size.Val = RoundDown(size.Val, TestExtentBytes)
 		sizeOption = size
 	} else if extents := template.Extents(); extents.Val > 0 {
 		sizeOption = extents
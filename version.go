@@ -18,32 +18,129 @@ package lvm2go
 
 import (
 	"bufio"
+	"cmp"
 	"context"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
 	"time"
 )
 
+// Version is the output of lvm version.
 type Version struct {
-	LVMVersion     string
-	LibraryVersion string
-	DriverVersion  string
+	LVMVersion     VersionNumber
+	LibraryVersion VersionNumber
+	DriverVersion  VersionNumber
 
 	LVMBuild     time.Time
 	LibraryBuild time.Time
 
 	ConfigurationFlags []string
+
+	// Targets are the device-mapper targets of the kernel, only reported with TargetVersions.
+	Targets DeviceMapperTargets
+}
+
+// AtLeast reports whether the lvm2 version is the given version or newer.
+func (v Version) AtLeast(version VersionNumber) bool {
+	return v.LVMVersion.AtLeast(version)
+}
+
+// VersionNumber is a version as reported by lvm version, e.g. 2.03.16(2)-RHEL9 for lvm2, 1.02.185 for
+// the device-mapper library or 4.48.0 for the kernel driver. Versions are compared by their
+// major, minor and patch numbers. The API revision and distribution suffix of the raw version are ignored.
+type VersionNumber struct {
+	Major int
+	Minor int
+	Patch int
+	// Raw is the version as reported.
+	Raw string
+}
+
+// ParseVersionNumber parses a version that starts with major and minor number, optionally followed by a patch number.
+func ParseVersionNumber(str string) (VersionNumber, error) {
+	version := VersionNumber{Raw: str}
+	numbers := str
+	if i := strings.IndexFunc(numbers, func(r rune) bool { return r != '.' && (r < '0' || r > '9') }); i >= 0 {
+		numbers = numbers[:i]
+	}
+	parts := strings.Split(numbers, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return VersionNumber{}, fmt.Errorf("invalid version %q", str)
+	}
+	for i, ptr := range []*int{&version.Major, &version.Minor, &version.Patch}[:len(parts)] {
+		n, err := strconv.Atoi(parts[i])
+		if err != nil {
+			return VersionNumber{}, fmt.Errorf("invalid version %q: %w", str, err)
+		}
+		*ptr = n
+	}
+	return version, nil
+}
+
+// MustParseVersionNumber is like ParseVersionNumber but panics if the version cannot be parsed.
+func MustParseVersionNumber(str string) VersionNumber {
+	version, err := ParseVersionNumber(str)
+	if err != nil {
+		panic(err)
+	}
+	return version
+}
+
+// Compare returns -1, 0 or +1 depending on whether v is older, the same or newer than other.
+func (v VersionNumber) Compare(other VersionNumber) int {
+	return cmp.Or(
+		cmp.Compare(v.Major, other.Major),
+		cmp.Compare(v.Minor, other.Minor),
+		cmp.Compare(v.Patch, other.Patch),
+	)
+}
+
+// AtLeast reports whether v is the same as or newer than other.
+func (v VersionNumber) AtLeast(other VersionNumber) bool {
+	return v.Compare(other) >= 0
+}
+
+// IsZero reports whether the version is unknown.
+func (v VersionNumber) IsZero() bool {
+	return v == VersionNumber{}
+}
+
+func (v VersionNumber) String() string {
+	if v.Raw != "" {
+		return v.Raw
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+func (v VersionNumber) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+func (v *VersionNumber) UnmarshalText(text []byte) (err error) {
+	*v, err = ParseVersionNumber(string(text))
+	return err
 }
 
 type (
-	VersionOptions struct{}
-	VersionOption  interface {
+	VersionOptions struct {
+		TargetVersions
+	}
+	VersionOption interface {
 		ApplyToVersionOptions(opts *VersionOptions)
 	}
 	VersionOptionsList []VersionOption
 )
 
+// TargetVersions includes the device-mapper targets of the kernel and their versions in the Version,
+// so that features can be gated on the kernel and not only on lvm2. They are read with dmsetup targets.
+type TargetVersions bool
+
+func (opt TargetVersions) ApplyToVersionOptions(opts *VersionOptions) {
+	opts.TargetVersions = opt
+}
+
 var (
 	_ ArgumentGenerator = VersionOptionsList{}
 	_ Argument          = (*VersionOptions)(nil)
@@ -62,7 +159,11 @@ func DefaultVersionOutputProcessor() (*Version, RawOutputProcessor) {
 		if len(versionFields) < 4 {
 			return fmt.Errorf("invalid version line: %q", scanner.Text())
 		}
-		version.LVMVersion = versionFields[2]
+		if lvmVersion, err := ParseVersionNumber(versionFields[2]); err != nil {
+			return fmt.Errorf("failed to parse lvm version: %v", err)
+		} else {
+			version.LVMVersion = lvmVersion
+		}
 		if lvmBuildDate, err := time.Parse(time.DateOnly, strings.Trim(versionFields[3], "()")); err != nil {
 			return fmt.Errorf("failed to parse library build date: %v", err)
 		} else {
@@ -77,7 +178,11 @@ func DefaultVersionOutputProcessor() (*Version, RawOutputProcessor) {
 		if len(libraryFields) < 4 {
 			return fmt.Errorf("invalid version line: %q", scanner.Text())
 		}
-		version.LibraryVersion = libraryFields[2]
+		if libraryVersion, err := ParseVersionNumber(libraryFields[2]); err != nil {
+			return fmt.Errorf("failed to parse library version: %v", err)
+		} else {
+			version.LibraryVersion = libraryVersion
+		}
 
 		if libraryBuildDate, err := time.Parse(time.DateOnly, strings.Trim(libraryFields[3], "()")); err != nil {
 			return fmt.Errorf("failed to parse library build date: %v", err)
@@ -93,7 +198,11 @@ func DefaultVersionOutputProcessor() (*Version, RawOutputProcessor) {
 		if len(driverFields) < 3 {
 			return fmt.Errorf("invalid version line: %q", scanner.Text())
 		}
-		version.DriverVersion = driverFields[2]
+		if driverVersion, err := ParseVersionNumber(driverFields[2]); err != nil {
+			return fmt.Errorf("failed to parse driver version: %v", err)
+		} else {
+			version.DriverVersion = driverVersion
+		}
 
 		configurationLine := scanner.Scan()
 		if !configurationLine {
@@ -120,9 +229,32 @@ func (c *client) Version(ctx context.Context, opts ...VersionOption) (Version, e
 		return Version{}, fmt.Errorf("failed to get version: %v", err)
 	}
 
+	options := VersionOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVersionOptions(&options)
+	}
+	if options.TargetVersions {
+		if version.Targets, err = deviceMapperTargets(ctx, c); err != nil {
+			return Version{}, err
+		}
+	}
+
 	return *version, nil
 }
 
+// deviceMapperTargets reads the device-mapper targets of the kernel with dmsetup targets.
+func deviceMapperTargets(ctx context.Context, runner RawCommandRunner) (DeviceMapperTargets, error) {
+	var targets DeviceMapperTargets
+	err := runner.RunRaw(ctx, func(out io.Reader) (err error) {
+		targets, err = ParseDeviceMapperTargets(out)
+		return err
+	}, "dmsetup", "targets")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device-mapper targets: %w", err)
+	}
+	return targets, nil
+}
+
 func (list VersionOptionsList) AsArgs() (Arguments, error) {
 	args := NewArgs(ArgsTypeGeneric)
 	options := VersionOptions{}
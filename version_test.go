@@ -19,6 +19,7 @@ package lvm2go_test
 import (
 	"context"
 	"log/slog"
+	"strings"
 	"testing"
 
 	. "github.com/azalio/lvm2go"
@@ -37,7 +38,7 @@ func Test_Version(t *testing.T) {
 		t.Fatalf("failed to get version: %v", err)
 	}
 
-	if ver.LVMVersion == "" {
+	if ver.LVMVersion.IsZero() {
 		t.Fatalf("LVM Version is empty")
 	}
 
@@ -45,7 +46,7 @@ func Test_Version(t *testing.T) {
 		t.Fatalf("LVM Build Date is zero")
 	}
 
-	if ver.LibraryVersion == "" {
+	if ver.LibraryVersion.IsZero() {
 		t.Fatalf("Library Version is empty")
 	}
 
@@ -53,7 +54,7 @@ func Test_Version(t *testing.T) {
 		t.Fatalf("Library Build Date is zero")
 	}
 
-	if ver.DriverVersion == "" {
+	if ver.DriverVersion.IsZero() {
 		t.Fatalf("Driver Version is empty")
 	}
 
@@ -61,3 +62,79 @@ func Test_Version(t *testing.T) {
 		t.Fatalf("Configuration Flags is empty")
 	}
 }
+
+func TestVersionOutput(t *testing.T) {
+	t.Parallel()
+	version, process := DefaultVersionOutputProcessor()
+	output := "  LVM version:     2.03.14(2)-RHEL8 (2021-10-20)\n" +
+		"  Library version: 1.02.181-RHEL8 (2021-10-20)\n" +
+		"  Driver version:  4.46.0\n" +
+		"  Configuration:   ./configure --build=x86_64-redhat-linux-gnu --with-default-dm-run-dir=/run\n"
+	if err := process(strings.NewReader(output)); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := version.LVMVersion; v.Major != 2 || v.Minor != 3 || v.Patch != 14 || v.String() != "2.03.14(2)-RHEL8" {
+		t.Fatalf("unexpected lvm version %+v", v)
+	}
+	if v := version.LibraryVersion; v.Compare(MustParseVersionNumber("1.02.181")) != 0 {
+		t.Fatalf("unexpected library version %+v", v)
+	}
+	if v := version.DriverVersion; v.Compare(MustParseVersionNumber("4.46")) != 0 {
+		t.Fatalf("unexpected driver version %+v", v)
+	}
+
+	if !version.AtLeast(MustParseVersionNumber("2.03.14")) || version.AtLeast(MustParseVersionNumber("2.03.17")) {
+		t.Fatalf("unexpected comparison of %s", version.LVMVersion)
+	}
+	if MustParseVersionNumber("2.03.9").Compare(MustParseVersionNumber("2.03.10")) != -1 {
+		t.Fatal("expected numeric comparison of patch versions")
+	}
+
+	for _, invalid := range []string{"", "2", "v2.03.14", "2.03.14.1"} {
+		if _, err := ParseVersionNumber(invalid); err == nil {
+			t.Errorf("expected error for %q", invalid)
+		}
+	}
+}
+
+func TestVersion_TargetVersions(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    []string{"version"},
+		Stdout: "  LVM version:     2.03.16(2) (2022-05-18)\n" +
+			"  Library version: 1.02.185 (2022-05-18)\n" +
+			"  Driver version:  4.48.0\n" +
+			"  Configuration:   ./configure --enable-dmeventd\n",
+	}, {
+		Command: "dmsetup",
+		Args:    []string{"targets"},
+		Stdout: "thin-pool        v1.23.0\n" +
+			"thin             v1.23.0\n" +
+			"striped          v1.6.0\n" +
+			"linear           v1.4.0\n",
+	}}))
+
+	version, err := clnt.Version(ctx, TargetVersions(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(version.Targets) != 4 {
+		t.Fatalf("unexpected targets %v", version.Targets)
+	}
+	if v, ok := version.Targets.Get(DeviceMapperTargetThinPool); !ok || v.String() != "1.23.0" {
+		t.Fatalf("unexpected thin-pool target version %v", v)
+	}
+	if !version.Targets.AtLeast(DeviceMapperTargetThin, MustParseVersionNumber("1.22.0")) {
+		t.Fatal("expected thin target of at least 1.22.0")
+	}
+	if version.Targets.AtLeast(DeviceMapperTargetCache, MustParseVersionNumber("1.0.0")) {
+		t.Fatal("expected cache target to be missing")
+	}
+
+	if _, err := ParseDeviceMapperTargets(strings.NewReader("thin-pool v1.x\n")); err == nil {
+		t.Fatal("expected error for invalid target version")
+	}
+}
@@ -43,6 +43,11 @@ const (
 	ExportedFalse Exported = '-'
 )
 
+// IsExported returns true if the volume group was exported with VGExport and has to be imported before it can be used.
+func (attr VGAttributes) IsExported() bool {
+	return attr.Exported == ExportedTrue
+}
+
 type PartialAttr rune
 
 const (
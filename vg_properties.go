@@ -0,0 +1,98 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"fmt"
+)
+
+const (
+	SetResizeable    ResizeableVolumeGroup = "y"
+	SetNotResizeable ResizeableVolumeGroup = "n"
+)
+
+// ResizeableVolumeGroup allows or prevents adding physical volumes to and removing them from a volume group
+// with VGChange, e.g. to freeze the layout of a provisioned volume group. See VGAttributes.Resizeable.
+type ResizeableVolumeGroup string
+
+func (opt ResizeableVolumeGroup) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.ResizeableVolumeGroup = opt
+}
+
+func (opt ResizeableVolumeGroup) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	if opt != SetResizeable && opt != SetNotResizeable {
+		return fmt.Errorf("invalid resizeable value %q, must be %q or %q", string(opt), SetResizeable, SetNotResizeable)
+	}
+	args.AddOrReplace(fmt.Sprintf("--resizeable=%s", string(opt)))
+	return nil
+}
+
+// RegenerateUUID generates a new random UUID for a volume group with VGChange or a physical volume with PVChange,
+// e.g. to tell apart volume groups of cloned disks. The volume group must not have active logical volumes.
+type RegenerateUUID bool
+
+func (opt RegenerateUUID) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.RegenerateUUID = opt
+}
+
+func (opt RegenerateUUID) ApplyToPVChangeOptions(opts *PVChangeOptions) {
+	opts.RegenerateUUID = opt
+}
+
+func (opt RegenerateUUID) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--uuid"})
+	}
+	return nil
+}
+
+// SystemID is the system ID of a volume group, restricting its use to the host with the same system ID.
+// It is set with VGCreate or VGChange and reported as VolumeGroup.SystemID. See man lvmsystemid.
+type SystemID string
+
+func (opt SystemID) ApplyToVGCreateOptions(opts *VGCreateOptions) {
+	opts.SystemID = opt
+}
+
+func (opt SystemID) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.SystemID = opt
+}
+
+func (opt SystemID) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--systemid=%s", string(opt)))
+	return nil
+}
+
+// RemoveSystemID removes the system ID of a volume group with VGChange, so that every host can use it.
+type RemoveSystemID bool
+
+func (opt RemoveSystemID) ApplyToVGChangeOptions(opts *VGChangeOptions) {
+	opts.RemoveSystemID = opt
+}
+
+func (opt RemoveSystemID) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplace("--systemid=")
+	}
+	return nil
+}
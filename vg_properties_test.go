@@ -0,0 +1,109 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestVolumeGroupProperties(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := VGChangeOptionsList{
+		VolumeGroupName("vg"), UnlimitedLogicalVolumes, MaximumPhysicalVolumes(4),
+		SetNotResizeable, RegenerateUUID(true), SystemID("host-a"),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--logicalvolume=0", "--maxphysicalvolumes=4", "--resizeable=n", "--uuid", "--systemid=host-a"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	args, err = VGCreateOptionList{
+		VolumeGroupName("vg"), PhysicalVolumeNames{"/dev/sdb"}, UnlimitedPhysicalVolumes, SystemID("host-a"),
+	}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, arg := range []string{"--maxphysicalvolumes=0", "--systemid=host-a"} {
+		if !slices.Contains(args.GetRaw(), arg) {
+			t.Fatalf("expected %s in %v", arg, args.GetRaw())
+		}
+	}
+	if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), SystemID("host-a"), RemoveSystemID(true)}).AsArgs(); err == nil {
+		t.Fatal("expected error for SystemID together with RemoveSystemID")
+	}
+	if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), ResizeableVolumeGroup("maybe")}).AsArgs(); err == nil {
+		t.Fatal("expected error for an invalid resizeable value")
+	}
+
+	clnt := fake.NewClient()
+	clnt.SetDevice("/dev/sdb", MustParseSize("1G"))
+	clnt.SetDevice("/dev/sdc", MustParseSize("1G"))
+	vgName := VolumeGroupName("vg")
+	if err := clnt.VGCreate(ctx, vgName, PhysicalVolumeNames{"/dev/sdb"}, MaximumLogicalVolumes(1), SystemID("host-a")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, vgName, LogicalVolumeName("lv"), MustParseSize("100M")); err != nil {
+		t.Fatal(err)
+	}
+	vg, err := clnt.VG(ctx, vgName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vg.SysID != "host-a" || vg.MaxLv != 1 {
+		t.Fatalf("unexpected volume group %+v", vg)
+	}
+
+	if err := clnt.VGChange(ctx, vgName, SetNotResizeable, UnlimitedLogicalVolumes, RemoveSystemID(true)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGExtend(ctx, vgName, PhysicalVolumeNames{"/dev/sdc"}); err == nil {
+		t.Fatal("expected error extending a volume group that is not resizeable")
+	}
+	if vg, err = clnt.VG(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+	if vg.Attr.Resizeable != ResizeableFalse || vg.MaxLv != 0 || vg.SysID != "" {
+		t.Fatalf("unexpected volume group %+v", vg)
+	}
+
+	uuid := vg.UUID
+	if err := clnt.VGChange(ctx, vgName, RegenerateUUID(true)); err == nil {
+		t.Fatal("expected error regenerating the UUID with active logical volumes")
+	}
+	if err := clnt.VGChange(ctx, vgName, Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGChange(ctx, vgName, RegenerateUUID(true), SetResizeable); err != nil {
+		t.Fatal(err)
+	}
+	if vg, err = clnt.VG(ctx, vgName); err != nil {
+		t.Fatal(err)
+	}
+	if vg.UUID == uuid || vg.Attr.Resizeable != ResizeableTrue {
+		t.Fatalf("unexpected volume group %+v", vg)
+	}
+}
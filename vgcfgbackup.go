@@ -0,0 +1,103 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// VGCfgBackupOptions describe a backup of the metadata of a volume group.
+	// Without VolumeGroupName, the metadata of all volume groups is backed up.
+	VGCfgBackupOptions struct {
+		VolumeGroupName
+		BackupFile
+
+		CommonOptions
+	}
+	VGCfgBackupOption interface {
+		ApplyToVGCfgBackupOptions(opts *VGCfgBackupOptions)
+	}
+	VGCfgBackupOptionsList []VGCfgBackupOption
+)
+
+// BackupFile is the file the metadata of a volume group is written to by VGCfgBackup or restored from by VGCfgRestore.
+// If empty, the backup in the backup directory of lvm2, e.g. /etc/lvm/backup/vg, is used.
+// VGCfgBackup replaces %s in the file with the name of the volume group when backing up multiple volume groups.
+// For PVCheck, it is the file metadata is dumped to or repaired from.
+type BackupFile string
+
+func (opt BackupFile) ApplyToVGCfgBackupOptions(opts *VGCfgBackupOptions) {
+	opts.BackupFile = opt
+}
+
+func (opt BackupFile) ApplyToVGCfgRestoreOptions(opts *VGCfgRestoreOptions) {
+	opts.BackupFile = opt
+}
+
+func (opt BackupFile) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--file=%s", string(opt)))
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = VGCfgBackupOptionsList{}
+	_ Argument          = (*VGCfgBackupOptions)(nil)
+)
+
+func (c *client) VGCfgBackup(ctx context.Context, opts ...VGCfgBackupOption) error {
+	args, err := VGCfgBackupOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgcfgbackup"}, args.GetRaw()...)...)
+}
+
+func (list VGCfgBackupOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGCfgBackupOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGCfgBackupOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGCfgBackupOptions) ApplyToVGCfgBackupOptions(new *VGCfgBackupOptions) {
+	*new = *opts
+}
+
+func (opts *VGCfgBackupOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.BackupFile,
+		opts.VolumeGroupName,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
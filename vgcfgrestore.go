@@ -0,0 +1,166 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type (
+	// VGCfgRestoreOptions describe the restore of the metadata of a volume group from a backup.
+	// All logical volumes of the volume group have to be inactive. Volume groups with thin pools
+	// are only restored with Force, as lvm2 cannot verify that the restored metadata matches the thin pool
+	// metadata. With List, the available backups are listed instead, see ListMetadataBackups.
+	VGCfgRestoreOptions struct {
+		VolumeGroupName
+		BackupFile
+		Force
+		*List
+
+		CommonOptions
+	}
+	VGCfgRestoreOption interface {
+		ApplyToVGCfgRestoreOptions(opts *VGCfgRestoreOptions)
+	}
+	VGCfgRestoreOptionsList []VGCfgRestoreOption
+)
+
+var (
+	_ ArgumentGenerator = VGCfgRestoreOptionsList{}
+	_ Argument          = (*VGCfgRestoreOptions)(nil)
+)
+
+func (c *client) VGCfgRestore(ctx context.Context, opts ...VGCfgRestoreOption) error {
+	options := VGCfgRestoreOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGCfgRestoreOptions(&options)
+	}
+	args, err := VGCfgRestoreOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	if options.List == nil || options.List.Writer == nil {
+		return c.RunLVM(ctx, append([]string{"vgcfgrestore"}, args.GetRaw()...)...)
+	}
+	return c.RunLVMRaw(ctx, func(out io.Reader) error {
+		_, err := io.Copy(options.List.Writer, out)
+		return err
+	}, append([]string{"vgcfgrestore"}, args.GetRaw()...)...)
+}
+
+func (list VGCfgRestoreOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGCfgRestoreOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGCfgRestoreOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGCfgRestoreOptions) ApplyToVGCfgRestoreOptions(new *VGCfgRestoreOptions) {
+	*new = *opts
+}
+
+func (opts *VGCfgRestoreOptions) ApplyToArgs(args Arguments) error {
+	if opts.VolumeGroupName == "" && (opts.List == nil || opts.BackupFile == "") {
+		return fmt.Errorf("VolumeGroupName is required for the restore of a volume group")
+	}
+
+	for _, arg := range []Argument{
+		opts.BackupFile,
+		opts.Force,
+		opts.List,
+		opts.VolumeGroupName,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (opt *List) ApplyToVGCfgRestoreOptions(opts *VGCfgRestoreOptions) {
+	opts.List = opt
+}
+
+// MetadataBackup is a backup or archive of the metadata of a volume group as listed by VGCfgRestore with List.
+type MetadataBackup struct {
+	File            string
+	VolumeGroupName VolumeGroupName
+	// Description is the description lvm2 gave the backup, e.g. Created *before* executing 'lvremove vg/lv'.
+	Description string
+	// Time is the time the backup was taken, in the local time zone.
+	Time time.Time
+}
+
+// metadataBackupTimeLayout is the layout of the backup time in the listing of vgcfgrestore.
+const metadataBackupTimeLayout = time.ANSIC
+
+// ListMetadataBackups lists the backups of the metadata of the volume group with VGCfgRestore, oldest first,
+// including the archives taken by lvm2 before every change of the volume group.
+func ListMetadataBackups(ctx context.Context, client Client, vg VolumeGroupName) ([]MetadataBackup, error) {
+	var listing strings.Builder
+	if err := client.VGCfgRestore(ctx, vg, &List{Writer: &listing}); err != nil {
+		return nil, err
+	}
+	return ParseMetadataBackups(strings.NewReader(listing.String()))
+}
+
+// ParseMetadataBackups parses the listing of vgcfgrestore --list.
+func ParseMetadataBackups(listing io.Reader) ([]MetadataBackup, error) {
+	var backups []MetadataBackup
+	scanner := bufio.NewScanner(listing)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if key == "File" {
+			backups = append(backups, MetadataBackup{File: value})
+			continue
+		}
+		if len(backups) == 0 {
+			continue
+		}
+		backup := &backups[len(backups)-1]
+		switch key {
+		case "VG name":
+			backup.VolumeGroupName = VolumeGroupName(value)
+		case "Description":
+			backup.Description = value
+		case "Backup Time":
+			t, err := time.ParseInLocation(metadataBackupTimeLayout, value, time.Local)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse backup time of %s: %w", backup.File, err)
+			}
+			backup.Time = t
+		}
+	}
+	return backups, scanner.Err()
+}
@@ -0,0 +1,107 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestVGCfgBackup(t *testing.T) {
+	t.Parallel()
+
+	args, err := (VGCfgBackupOptionsList{VolumeGroupName("vg"), BackupFile("/tmp/vg.backup")}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--file=/tmp/vg.backup", "vg", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+}
+
+func TestVGCfgRestore(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	if _, err := (VGCfgRestoreOptionsList{BackupFile("/tmp/vg.backup")}).AsArgs(); err == nil {
+		t.Fatal("expected error without volume group name")
+	}
+
+	args, err := (VGCfgRestoreOptionsList{VolumeGroupName("vg"), BackupFile("/tmp/vg.backup"), Force(true)}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--file=/tmp/vg.backup", "--force", "vg", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	listArgs, err := (VGCfgRestoreOptionsList{VolumeGroupName("vg"), &List{}}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    append([]string{"vgcfgrestore"}, listArgs.GetRaw()...),
+		Stdout: strings.Join([]string{
+			"   ",
+			"  File:\t\t/etc/lvm/archive/vg_00000-1234.vg",
+			"  VG name:    \tvg",
+			"  Description:\tCreated *before* executing 'lvcreate -L 8M -n lv vg'",
+			"  Backup Time:\tMon Oct 14 10:00:00 2024",
+			"",
+			"   ",
+			"  File:\t\t/etc/lvm/backup/vg",
+			"  VG name:    \tvg",
+			"  Description:\tCreated *after* executing 'lvcreate -L 8M -n lv vg'",
+			"  Backup Time:\tMon Oct 14 10:00:01 2024",
+			"",
+		}, "\n"),
+	}}))
+
+	backups, err := ListMetadataBackups(ctx, clnt, "vg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Fatalf("expected 2 backups, got %d", len(backups))
+	}
+	expected := MetadataBackup{
+		File:            "/etc/lvm/archive/vg_00000-1234.vg",
+		VolumeGroupName: "vg",
+		Description:     "Created *before* executing 'lvcreate -L 8M -n lv vg'",
+		Time:            time.Date(2024, time.October, 14, 10, 0, 0, 0, time.Local),
+	}
+	if !backups[0].Time.Equal(expected.Time) {
+		t.Fatalf("expected backup time %v, got %v", expected.Time, backups[0].Time)
+	}
+	backups[0].Time = expected.Time
+	if backups[0] != expected {
+		t.Fatalf("expected %+v, got %+v", expected, backups[0])
+	}
+	if backups[1].File != "/etc/lvm/backup/vg" {
+		t.Fatalf("expected the backup last, got %s", backups[1].File)
+	}
+
+	if _, err := ParseMetadataBackups(strings.NewReader("  File:\t\tvg\n  Backup Time:\tyesterday\n")); err == nil {
+		t.Fatal("expected error for invalid backup time")
+	}
+}
@@ -27,11 +27,29 @@ type (
 
 		MaximumLogicalVolumes
 		MaximumPhysicalVolumes
+		ResizeableVolumeGroup
+		RegenerateUUID
+		SystemID
+		RemoveSystemID
+		PhysicalExtentSize
 		AllocationPolicy
+		ActivationState
+		Refresh
+		LockType
+		LockStart
+		LockStop
+		ActivationMode
+		IgnoreActivationSkip
 		AutoActivation
+		Poll
+		Monitor
+		IgnoreMonitoring
 		Tags
 		DelTags
 
+		// Select changes the volume groups matching the select expression instead of a named volume group.
+		Select
+
 		CommonOptions
 	}
 	VGChangeOption interface {
@@ -51,7 +69,16 @@ func (c *client) VGChange(ctx context.Context, opts ...VGChangeOption) error {
 		return err
 	}
 
-	return c.RunLVM(ctx, append([]string{"vgchange"}, args.GetRaw()...)...)
+	err = c.RunLVM(ctx, append([]string{"vgchange"}, args.GetRaw()...)...)
+
+	options := VGChangeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGChangeOptions(&options)
+	}
+	if options.PhysicalExtentSize.Val > 0 && IsExtentSizeNotDivisible(err) {
+		return newExtentSizeChangeError(options.VolumeGroupName, options.PhysicalExtentSize, err)
+	}
+	return err
 }
 
 func (list VGChangeOptionsList) AsArgs() (Arguments, error) {
@@ -67,18 +94,47 @@ func (list VGChangeOptionsList) AsArgs() (Arguments, error) {
 }
 
 func (opts *VGChangeOptions) ApplyToArgs(args Arguments) error {
-	if opts.VolumeGroupName == "" {
+	if bool(opts.LockStart) && bool(opts.LockStop) {
+		return fmt.Errorf("LockStart and LockStop are mutually exclusive")
+	}
+	// the lockspaces of all shared volume groups are started or stopped without a volume group
+	if opts.VolumeGroupName == "" && opts.Select == "" && !bool(opts.LockStart) && !bool(opts.LockStop) {
 		return fmt.Errorf("VolumeGroupName is required for creation of a volume group")
 	}
+	if opts.SystemID != "" && bool(opts.RemoveSystemID) {
+		return fmt.Errorf("SystemID and RemoveSystemID are mutually exclusive")
+	}
+	if err := validateMonitoring(opts.Monitor, opts.IgnoreMonitoring); err != nil {
+		return err
+	}
+	if bool(opts.Refresh) && opts.ActivationState != "" {
+		return fmt.Errorf("Refresh and ActivationState are mutually exclusive")
+	}
 
 	for _, opt := range []Argument{
 		opts.VolumeGroupName,
 		opts.MaximumLogicalVolumes,
 		opts.MaximumPhysicalVolumes,
+		opts.ResizeableVolumeGroup,
+		opts.RegenerateUUID,
+		opts.SystemID,
+		opts.RemoveSystemID,
+		opts.PhysicalExtentSize,
 		opts.AllocationPolicy,
+		opts.ActivationState,
+		opts.Refresh,
+		opts.LockType,
+		opts.LockStart,
+		opts.LockStop,
+		opts.ActivationMode,
+		opts.IgnoreActivationSkip,
 		opts.AutoActivation,
+		opts.Poll,
+		opts.Monitor,
+		opts.IgnoreMonitoring,
 		opts.Tags,
 		opts.DelTags,
+		opts.Select,
 		opts.CommonOptions,
 	} {
 		if err := opt.ApplyToArgs(args); err != nil {
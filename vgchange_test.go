@@ -18,6 +18,7 @@ package lvm2go_test
 
 import (
 	"context"
+	"errors"
 	"slices"
 	"testing"
 
@@ -76,3 +77,39 @@ func TestVGChange(t *testing.T) {
 	}
 
 }
+
+func TestVGChangePhysicalExtentSize(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	for size, valid := range map[string]bool{
+		"8M":   true,
+		"384K": true,
+		"3K":   false,
+		"1000": false,
+	} {
+		if _, err := (VGChangeOptionsList{VolumeGroupName("vg"), PhysicalExtentSize(MustParseSize(size))}).AsArgs(); valid != (err == nil) {
+			t.Errorf("unexpected validation result for extent size %s: %v", size, err)
+		}
+	}
+
+	opts := VGChangeOptionsList{VolumeGroupName("vg"), PhysicalExtentSize(MustParseSize("8M"))}
+	args, err := opts.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command:  "lvm",
+		Args:     append([]string{"vgchange"}, args.GetRaw()...),
+		Stderr:   "  New size 24576 for lv segment length not an exact number of new extents.\n",
+		ExitCode: 5,
+	}}))
+
+	var changeErr *ExtentSizeChangeError
+	if err := clnt.VGChange(ctx, opts...); !errors.As(err, &changeErr) {
+		t.Fatalf("expected extent size change error, got %v", err)
+	}
+	if changeErr.VolumeGroup != "vg" || !slices.Equal(changeErr.Blocking, []string{"lv segment length"}) || !IsExtentSizeNotDivisible(changeErr) {
+		t.Fatalf("unexpected extent size change error %v", changeErr)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+)
+
+type (
+	// VGCheckOptions describe a consistency check of the metadata of a volume group.
+	// Without VolumeGroupName, all volume groups are checked.
+	VGCheckOptions struct {
+		VolumeGroupName
+		UpdateMetadata
+
+		CommonOptions
+	}
+	VGCheckOption interface {
+		ApplyToVGCheckOptions(opts *VGCheckOptions)
+	}
+	VGCheckOptionsList []VGCheckOption
+)
+
+// UpdateMetadata rewrites the metadata of a volume group on all of its physical volumes,
+// repairing inconsistent, outdated or damaged copies found by VGCheck.
+type UpdateMetadata bool
+
+func (opt UpdateMetadata) ApplyToVGCheckOptions(opts *VGCheckOptions) {
+	opts.UpdateMetadata = opt
+}
+
+func (opt UpdateMetadata) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--updatemetadata"})
+	}
+	return nil
+}
+
+// VGCheckResult is the result of VGCheck.
+type VGCheckResult struct {
+	// Consistent is set if vgck found no problems.
+	Consistent bool
+	// Problems are the messages of vgck about inconsistent metadata, without the WARNING prefix.
+	Problems []string
+}
+
+var (
+	_ ArgumentGenerator = VGCheckOptionsList{}
+	_ Argument          = (*VGCheckOptions)(nil)
+)
+
+func (c *client) VGCheck(ctx context.Context, opts ...VGCheckOption) (*VGCheckResult, error) {
+	args, err := VGCheckOptionsList(opts).AsArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.RunLVM(ctx, append([]string{"vgck"}, args.GetRaw()...)...)
+	if err == nil {
+		return &VGCheckResult{Consistent: true}, nil
+	}
+	stdErr, ok := AsLVMStdErr(err)
+	if !ok {
+		return nil, err
+	}
+	result := &VGCheckResult{}
+	for _, line := range stdErr.Lines(true) {
+		result.Problems = append(result.Problems, string(line))
+	}
+	return result, err
+}
+
+func (list VGCheckOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGCheckOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGCheckOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGCheckOptions) ApplyToVGCheckOptions(new *VGCheckOptions) {
+	*new = *opts
+}
+
+func (opts *VGCheckOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.UpdateMetadata,
+		opts.VolumeGroupName,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
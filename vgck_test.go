@@ -0,0 +1,99 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestVGCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := (VGCheckOptionsList{VolumeGroupName("vg"), UpdateMetadata(true)}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--updatemetadata", "vg", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command:  "lvm",
+		Args:     append([]string{"vgck"}, args.GetRaw()...),
+		Stderr:   "  WARNING: ignoring metadata seqno 2 on /dev/sdb for seqno 3 on /dev/sdc for VG vg.\n",
+		ExitCode: 5,
+	}}))
+	result, err := clnt.VGCheck(ctx, VolumeGroupName("vg"), UpdateMetadata(true))
+	if err == nil {
+		t.Fatal("expected error for inconsistent metadata")
+	}
+	if result == nil || result.Consistent || !slices.Equal(result.Problems, []string{"ignoring metadata seqno 2 on /dev/sdb for seqno 3 on /dev/sdc for VG vg."}) {
+		t.Fatalf("unexpected result %+v", result)
+	}
+}
+
+func TestPVCheck(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	if _, err := (PVCheckOptionsList{PVCheckDumpHeaders}).AsArgs(); err == nil {
+		t.Fatal("expected error without physical volume")
+	}
+	if _, err := (PVCheckOptionsList{PhysicalVolumeName("/dev/sdb"), PVCheckDumpHeaders, Repair(true)}).AsArgs(); err == nil {
+		t.Fatal("expected error when dumping and repairing")
+	}
+	args, err := (PVCheckOptionsList{PhysicalVolumeName("/dev/sdb"), Repair(true), BackupFile("/etc/lvm/backup/vg")}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--repair", "--file=/etc/lvm/backup/vg", "/dev/sdb", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	dumpArgs, err := (PVCheckOptionsList{PhysicalVolumeName("/dev/sdb"), PVCheckDumpHeaders}).AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    append([]string{"pvck"}, dumpArgs.GetRaw()...),
+		Stdout: "  label_header at 512\n" +
+			"  label_header.id LABELONE\n" +
+			"  label_header.crc 0x1b4ce1d4\n" +
+			"  pv_header.device_size 107374182400\n" +
+			"  CHECK: mda_header_1.crc is 0x0 expected 0x5a1b2c3d\n",
+		ExitCode: 5,
+	}}))
+	result, err := clnt.PVCheck(ctx, PhysicalVolumeName("/dev/sdb"), PVCheckDumpHeaders)
+	if err == nil {
+		t.Fatal("expected error for a failed check")
+	}
+	if result == nil || result.Healthy() {
+		t.Fatalf("expected problems, got %+v", result)
+	}
+	if result.Fields["label_header.id"] != "LABELONE" || result.Fields["pv_header.device_size"] != "107374182400" {
+		t.Fatalf("unexpected fields %v", result.Fields)
+	}
+	if !slices.Equal(result.Problems, []string{"mda_header_1.crc is 0x0 expected 0x5a1b2c3d"}) {
+		t.Fatalf("unexpected problems %v", result.Problems)
+	}
+}
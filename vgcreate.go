@@ -31,6 +31,7 @@ type (
 		MaximumLogicalVolumes
 		MaximumPhysicalVolumes
 
+		SystemID
 		AutoActivation
 		Force
 		Zero
@@ -40,6 +41,7 @@ type (
 		MetadataSize
 		AllocationPolicy
 		Shared
+		LockType
 
 		CommonOptions
 	}
@@ -84,11 +86,16 @@ func (opts *VGCreateOptions) ApplyToArgs(args Arguments) error {
 		return fmt.Errorf("PhysicalVolumeNames is required for creation of a volume group")
 	}
 
+	if opts.LockType != "" && opts.LockType != LockTypeNone && !bool(opts.Shared) {
+		return fmt.Errorf("LockType %s requires Shared", opts.LockType)
+	}
+
 	for _, opt := range []Argument{
 		opts.VolumeGroupName,
 		opts.PhysicalVolumeNames,
 		opts.MaximumLogicalVolumes,
 		opts.MaximumPhysicalVolumes,
+		opts.SystemID,
 		opts.Tags,
 		opts.Force,
 		opts.Zero,
@@ -99,6 +106,7 @@ func (opts *VGCreateOptions) ApplyToArgs(args Arguments) error {
 		opts.AllocationPolicy,
 		opts.AutoActivation,
 		opts.Shared,
+		opts.LockType,
 		opts.CommonOptions,
 	} {
 		if err := opt.ApplyToArgs(args); err != nil {
@@ -0,0 +1,88 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// VGExportOptions describe the export of a volume group, after which it is not used by the host anymore
+	// until it is imported again with VGImport, e.g. on another host after its disks were moved.
+	// All logical volumes of the volume group have to be inactive.
+	VGExportOptions struct {
+		VolumeGroupName
+		All
+		CommonOptions
+	}
+	VGExportOption interface {
+		ApplyToVGExportOptions(opts *VGExportOptions)
+	}
+	VGExportOptionsList []VGExportOption
+)
+
+var (
+	_ ArgumentGenerator = VGExportOptionsList{}
+	_ Argument          = (*VGExportOptions)(nil)
+)
+
+func (c *client) VGExport(ctx context.Context, opts ...VGExportOption) error {
+	args, err := VGExportOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgexport"}, args.GetRaw()...)...)
+}
+
+func (list VGExportOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGExportOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGExportOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGExportOptions) ApplyToVGExportOptions(new *VGExportOptions) {
+	*new = *opts
+}
+
+func (opts *VGExportOptions) ApplyToArgs(args Arguments) error {
+	if opts.VolumeGroupName == "" && !opts.All {
+		return fmt.Errorf("VolumeGroupName or All is required for the export of volume groups")
+	}
+	if opts.VolumeGroupName != "" && opts.All {
+		return fmt.Errorf("VolumeGroupName and All are mutually exclusive")
+	}
+
+	for _, arg := range []Argument{
+		opts.VolumeGroupName,
+		opts.All,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
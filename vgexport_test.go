@@ -0,0 +1,96 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestVGExportImportArgs(t *testing.T) {
+	t.Parallel()
+
+	args, err := VGImportOptionsList{VolumeGroupName("vg"), Force(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"vg", "--force", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+	if args, err = (VGExportOptionsList{All(true)}).AsArgs(); err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--all", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	if _, err := (VGExportOptionsList{}).AsArgs(); err == nil {
+		t.Fatal("expected error without volume group")
+	}
+	if _, err := (VGImportOptionsList{VolumeGroupName("vg"), All(true)}).AsArgs(); err == nil {
+		t.Fatal("expected error for volume group and all")
+	}
+}
+
+func TestVGExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	clnt := fake.NewClient()
+	if err := clnt.SetDevice("/dev/sdb", NewSize(101, UnitMiB)); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumesFrom("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.LVCreate(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), MustParseSize("8M")); err != nil {
+		t.Fatal(err)
+	}
+
+	exported := func() bool {
+		vg, err := clnt.VG(ctx, VolumeGroupName("vg"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return vg.Attr.IsExported()
+	}
+
+	if err := clnt.VGExport(ctx, VolumeGroupName("vg")); err == nil {
+		t.Fatal("expected error when exporting a volume group with active logical volumes")
+	}
+	if err := clnt.LVChange(ctx, VolumeGroupName("vg"), LogicalVolumeName("lv"), Deactivate); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGExport(ctx, VolumeGroupName("vg")); err != nil {
+		t.Fatal(err)
+	}
+	if !exported() {
+		t.Fatal("expected volume group to be exported")
+	}
+	if err := clnt.VGImport(ctx, All(true)); err != nil {
+		t.Fatal(err)
+	}
+	if exported() {
+		t.Fatal("expected volume group to be imported")
+	}
+	if err := clnt.VGImport(ctx, VolumeGroupName("vg")); err == nil {
+		t.Fatal("expected error when importing a volume group that is not exported")
+	}
+}
@@ -0,0 +1,89 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// VGImportOptions describe the import of an exported volume group.
+	// Force imports a volume group even if some of its physical volumes are missing.
+	VGImportOptions struct {
+		VolumeGroupName
+		All
+		Force
+		CommonOptions
+	}
+	VGImportOption interface {
+		ApplyToVGImportOptions(opts *VGImportOptions)
+	}
+	VGImportOptionsList []VGImportOption
+)
+
+var (
+	_ ArgumentGenerator = VGImportOptionsList{}
+	_ Argument          = (*VGImportOptions)(nil)
+)
+
+func (c *client) VGImport(ctx context.Context, opts ...VGImportOption) error {
+	args, err := VGImportOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgimport"}, args.GetRaw()...)...)
+}
+
+func (list VGImportOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGImportOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGImportOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGImportOptions) ApplyToVGImportOptions(new *VGImportOptions) {
+	*new = *opts
+}
+
+func (opts *VGImportOptions) ApplyToArgs(args Arguments) error {
+	if opts.VolumeGroupName == "" && !opts.All {
+		return fmt.Errorf("VolumeGroupName or All is required for the import of volume groups")
+	}
+	if opts.VolumeGroupName != "" && opts.All {
+		return fmt.Errorf("VolumeGroupName and All are mutually exclusive")
+	}
+
+	for _, arg := range []Argument{
+		opts.VolumeGroupName,
+		opts.All,
+		opts.Force,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
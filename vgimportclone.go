@@ -0,0 +1,119 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// VGImportCloneOptions describe the import of a volume group from duplicated physical volumes,
+	// e.g. from snapshots or clones of SAN LUNs. lvm2 gives the physical volumes and the volume group
+	// new UUIDs and renames the volume group, so that it can be used alongside the original.
+	VGImportCloneOptions struct {
+		PhysicalVolumeNames
+		BaseVolumeGroupName
+		Import
+
+		CommonOptions
+	}
+	VGImportCloneOption interface {
+		ApplyToVGImportCloneOptions(opts *VGImportCloneOptions)
+	}
+	VGImportCloneOptionsList []VGImportCloneOption
+)
+
+// BaseVolumeGroupName is the name of the volume group imported by VGImportClone. If it is already in use,
+// lvm2 appends a number to it. If empty, the name of the cloned volume group is used as base.
+type BaseVolumeGroupName VolumeGroupName
+
+func (opt BaseVolumeGroupName) ApplyToVGImportCloneOptions(opts *VGImportCloneOptions) {
+	opts.BaseVolumeGroupName = opt
+}
+
+func (opt BaseVolumeGroupName) ApplyToArgs(args Arguments) error {
+	if opt == "" {
+		return nil
+	}
+	args.AddOrReplace(fmt.Sprintf("--basevgname=%s", string(opt)))
+	return nil
+}
+
+// Import imports the volume group with VGImportClone if the cloned volume group was exported.
+// Otherwise, the imported volume group stays exported.
+type Import bool
+
+func (opt Import) ApplyToVGImportCloneOptions(opts *VGImportCloneOptions) {
+	opts.Import = opt
+}
+
+func (opt Import) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--import"})
+	}
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = VGImportCloneOptionsList{}
+	_ Argument          = (*VGImportCloneOptions)(nil)
+)
+
+func (c *client) VGImportClone(ctx context.Context, opts ...VGImportCloneOption) error {
+	args, err := VGImportCloneOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgimportclone"}, args.GetRaw()...)...)
+}
+
+func (list VGImportCloneOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGImportCloneOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGImportCloneOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGImportCloneOptions) ApplyToVGImportCloneOptions(new *VGImportCloneOptions) {
+	*new = *opts
+}
+
+func (opts *VGImportCloneOptions) ApplyToArgs(args Arguments) error {
+	if len(opts.PhysicalVolumeNames) == 0 {
+		return fmt.Errorf("cloned physical volumes are empty: %w", ErrPhysicalVolumeNameRequired)
+	}
+
+	for _, arg := range []Argument{
+		opts.BaseVolumeGroupName,
+		opts.Import,
+		opts.PhysicalVolumeNames,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+	"github.com/azalio/lvm2go/fake"
+)
+
+func TestVGImportClone(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	args, err := VGImportCloneOptionsList{PhysicalVolumesFrom("/dev/sdb", "/dev/sdc"), BaseVolumeGroupName("clone"), Import(true)}.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"--basevgname=clone", "--import", "/dev/sdb", "/dev/sdc", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+	if _, err := (VGImportCloneOptionsList{BaseVolumeGroupName("clone")}).AsArgs(); err == nil {
+		t.Fatal("expected error without physical volumes")
+	}
+
+	clnt := fake.NewClient()
+	for _, dev := range []string{"/dev/sdb", "/dev/sdc"} {
+		if err := clnt.SetDevice(dev, NewSize(101, UnitMiB)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("vg"), PhysicalVolumeName("/dev/sdb")); err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGCreate(ctx, VolumeGroupName("clone"), PhysicalVolumeName("/dev/sdc")); err != nil {
+		t.Fatal(err)
+	}
+	before, err := clnt.VG(ctx, VolumeGroupName("vg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := clnt.VGExport(ctx, VolumeGroupName("vg")); err != nil {
+		t.Fatal(err)
+	}
+
+	// the base name is taken, so a number is appended
+	if err := clnt.VGImportClone(ctx, PhysicalVolumeName("/dev/sdb"), BaseVolumeGroupName("clone"), Import(true)); err != nil {
+		t.Fatal(err)
+	}
+	after, err := clnt.VG(ctx, VolumeGroupName("clone1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.UUID == before.UUID || after.Attr.IsExported() {
+		t.Fatalf("expected imported clone with new UUID, got %s (%s)", after.UUID, after.Attr)
+	}
+}
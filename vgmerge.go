@@ -0,0 +1,135 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+type (
+	// VGMergeOptions describe a merge of the volume group Source into the volume group Destination.
+	// The logical volumes of Source have to be inactive and the extent sizes of both volume groups have to match.
+	VGMergeOptions struct {
+		Source      VolumeGroupName
+		Destination VolumeGroupName
+
+		Test
+		*List
+
+		CommonOptions
+	}
+	VGMergeOption interface {
+		ApplyToVGMergeOptions(opts *VGMergeOptions)
+	}
+	VGMergeOptionsList []VGMergeOption
+)
+
+// SetOldOrNew sets Source to the first and Destination to the second volume group name.
+func (opts *VGMergeOptions) SetOldOrNew(name VolumeGroupName) {
+	if opts.Source == "" {
+		opts.Source = name
+	} else {
+		opts.Destination = name
+	}
+}
+
+// List writes a listing to Writer. For VGMerge, it displays the merged destination volume group
+// like vgdisplay -v, combined with Test it previews a merge. For VGCfgRestore, it lists the available
+// metadata backups instead of restoring one. If Writer is nil, the output is written to the log.
+type List struct {
+	io.Writer
+}
+
+func (opt *List) ApplyToVGMergeOptions(opts *VGMergeOptions) {
+	opts.List = opt
+}
+
+func (opt *List) ApplyToArgs(args Arguments) error {
+	if opt != nil {
+		args.AddOrReplaceAll([]string{"--list"})
+	}
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = VGMergeOptionsList{}
+	_ Argument          = (*VGMergeOptions)(nil)
+)
+
+func (c *client) VGMerge(ctx context.Context, opts ...VGMergeOption) error {
+	options := VGMergeOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGMergeOptions(&options)
+	}
+	args, err := VGMergeOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	if options.List == nil || options.List.Writer == nil {
+		return c.RunLVM(ctx, append([]string{"vgmerge"}, args.GetRaw()...)...)
+	}
+	return c.RunLVMRaw(ctx, func(out io.Reader) error {
+		_, err := io.Copy(options.List.Writer, out)
+		return err
+	}, append([]string{"vgmerge"}, args.GetRaw()...)...)
+}
+
+func (list VGMergeOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGMergeOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGMergeOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGMergeOptions) ApplyToVGMergeOptions(new *VGMergeOptions) {
+	*new = *opts
+}
+
+func (opts *VGMergeOptions) ApplyToArgs(args Arguments) error {
+	if opts.Source == "" {
+		return fmt.Errorf("source is empty: %w", ErrVolumeGroupNameRequired)
+	}
+	if opts.Destination == "" {
+		return fmt.Errorf("destination is empty: %w", ErrVolumeGroupNameRequired)
+	}
+	if opts.Source == opts.Destination {
+		return fmt.Errorf("cannot merge volume group %s into itself", opts.Source)
+	}
+
+	// vgmerge expects the destination before the source
+	for _, arg := range []Argument{
+		opts.Destination,
+		opts.Source,
+		opts.Test,
+		opts.List,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
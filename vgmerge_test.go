@@ -0,0 +1,60 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestVGMerge(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	if _, err := (VGMergeOptionsList{VolumeGroupName("vg")}).AsArgs(); err == nil {
+		t.Fatal("expected error without destination")
+	}
+	if _, err := (VGMergeOptionsList{VolumeGroupName("vg"), VolumeGroupName("vg")}).AsArgs(); err == nil {
+		t.Fatal("expected error when merging a volume group into itself")
+	}
+
+	listing := &strings.Builder{}
+	opts := VGMergeOptionsList{VolumeGroupName("src"), VolumeGroupName("dst"), Test(true), &List{Writer: listing}}
+	args, err := opts.AsArgs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"dst", "src", "--test", "--list", "--yes"}; !slices.Equal(args.GetRaw(), expected) {
+		t.Fatalf("expected %v, got %v", expected, args.GetRaw())
+	}
+
+	clnt := NewClient(ReplayCommandRunner([]CommandRecording{{
+		Command: "lvm",
+		Args:    append([]string{"vgmerge"}, args.GetRaw()...),
+		Stdout:  "  --- Volume group ---\n  VG Name               dst\n",
+	}}))
+	if err := clnt.VGMerge(ctx, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(listing.String(), "VG Name               dst") {
+		t.Fatalf("expected listing of the merged volume group, got %q", listing.String())
+	}
+}
@@ -18,14 +18,25 @@ package lvm2go
 
 import (
 	"context"
+	"fmt"
+	"slices"
 )
 
 type (
+	// VGsOptions limit the report to volume groups matching the options.
+	// VolumeGroupName and VolumeGroupNames are passed as positional arguments,
+	// so that lvm itself limits the scope of the report to the named volume groups.
 	VGsOptions struct {
 		VolumeGroupName
+		VolumeGroupNames
 		Tags
 		Unit
 		Select
+		ByUUID
+		RetainRawJSON
+		ExtraColumns
+		ReportLocking
+		SortFields
 
 		ColumnOptions
 		CommonOptions
@@ -52,6 +63,18 @@ func (c *client) VGs(ctx context.Context, opts ...VGsOption) ([]*VolumeGroup, er
 	args := []string{
 		"vgs", "--reportformat", "json",
 	}
+	// the report locking of the client is applied first, so that it can be overridden per report
+	opts = append([]VGsOption{c.opts.ReportLocking}, opts...)
+	options := VGsOptions{}
+	for _, opt := range opts {
+		opt.ApplyToVGsOptions(&options)
+	}
+	typedFields := reportFields[VolumeGroup]()
+	strictColumns := bool(c.opts.StrictDecoding) && len(options.ColumnOptions) == 0
+	if strictColumns {
+		opts = append(slices.Clone(opts), typedFields)
+	}
+
 	argsFromOpts, err := VGsOptionsList(opts).AsArgs()
 	if err != nil {
 		return nil, err
@@ -59,8 +82,9 @@ func (c *client) VGs(ctx context.Context, opts ...VGsOption) ([]*VolumeGroup, er
 
 	err = c.RunLVMInto(ctx, res, append(args, argsFromOpts.GetRaw()...)...)
 
+	// names that are not found are skipped, the other names are still reported
 	if IsNotFound(err) {
-		return nil, nil
+		err = nil
 	}
 
 	if err != nil {
@@ -71,11 +95,33 @@ func (c *client) VGs(ctx context.Context, opts ...VGsOption) ([]*VolumeGroup, er
 		return nil, nil
 	}
 
-	if len(res.Report[0].VG) == 0 {
+	vgs := res.Report[0].VG
+
+	if len(vgs) == 0 {
 		return nil, nil
 	}
 
-	return res.Report[0].VG, nil
+	if c.opts.StrictDecoding {
+		for _, vg := range vgs {
+			if err := verifyReportFields(vg.raw, append(slices.Clone(typedFields), options.ExtraColumns...), strictColumns, bool(options.RetainRawJSON)); err != nil {
+				return nil, fmt.Errorf("failed to decode volume group %s strictly: %w", vg.Name, err)
+			}
+		}
+	}
+
+	for _, vg := range vgs {
+		if vg.ExtraFields, err = extraReportFields(vg.raw, typedFields); err != nil {
+			return nil, fmt.Errorf("failed to decode extra fields of volume group %s: %w", vg.Name, err)
+		}
+	}
+
+	if !options.RetainRawJSON {
+		for _, vg := range vgs {
+			vg.raw = nil
+		}
+	}
+
+	return vgs, nil
 }
 
 func (c *client) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error) {
@@ -102,14 +148,26 @@ func (c *client) VG(ctx context.Context, opts ...VGsOption) (*VolumeGroup, error
 	return vgs[0], nil
 }
 
+// InScope reports whether a volume group is within the scope of the names of the options.
+// It can be used by clients that filter reports themselves instead of passing the names to lvm.
+func (opts *VGsOptions) InScope(vg VolumeGroupName) bool {
+	if opts.VolumeGroupName == "" && len(opts.VolumeGroupNames) == 0 {
+		return true
+	}
+	return opts.VolumeGroupName == vg || slices.Contains(opts.VolumeGroupNames, vg)
+}
+
 func (opts *VGsOptions) ApplyToArgs(args Arguments) error {
 	for _, arg := range []Argument{
 		opts.VolumeGroupName,
+		opts.VolumeGroupNames,
 		opts.Tags,
 		opts.Unit,
+		opts.ReportLocking,
 		opts.CommonOptions,
-		opts.ColumnOptions,
-		opts.Select,
+		opts.ColumnOptions.withExtra(opts.ExtraColumns),
+		opts.SortFields,
+		opts.ByUUID.selectOn(VGUUID, opts.Select),
 	} {
 		if err := arg.ApplyToArgs(args); err != nil {
 			return err
@@ -0,0 +1,91 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+)
+
+type (
+	// VGScanOptions describe a scan of all devices for volume groups.
+	VGScanOptions struct {
+		Mknodes
+
+		CommonOptions
+	}
+	VGScanOption interface {
+		ApplyToVGScanOptions(opts *VGScanOptions)
+	}
+	VGScanOptionsList []VGScanOption
+)
+
+// Mknodes checks the device nodes of active logical volumes with VGScan and creates missing ones.
+type Mknodes bool
+
+func (opt Mknodes) ApplyToVGScanOptions(opts *VGScanOptions) {
+	opts.Mknodes = opt
+}
+
+func (opt Mknodes) ApplyToArgs(args Arguments) error {
+	if opt {
+		args.AddOrReplaceAll([]string{"--mknodes"})
+	}
+	return nil
+}
+
+var (
+	_ ArgumentGenerator = VGScanOptionsList{}
+	_ Argument          = (*VGScanOptions)(nil)
+)
+
+func (c *client) VGScan(ctx context.Context, opts ...VGScanOption) error {
+	args, err := VGScanOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgscan"}, args.GetRaw()...)...)
+}
+
+func (list VGScanOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGScanOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGScanOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGScanOptions) ApplyToVGScanOptions(new *VGScanOptions) {
+	*new = *opts
+}
+
+func (opts *VGScanOptions) ApplyToArgs(args Arguments) error {
+	for _, arg := range []Argument{
+		opts.Mknodes,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// VGSplitOptions describe a split of the physical volumes PhysicalVolumeNames, or of the physical volumes
+	// of the logical volume LogicalVolumeName, out of the volume group From into the volume group To.
+	// To is created if it does not exist. MaximumLogicalVolumes, MaximumPhysicalVolumes and AllocationPolicy
+	// only apply to a new volume group.
+	VGSplitOptions struct {
+		From VolumeGroupName
+		To   VolumeGroupName
+
+		PhysicalVolumeNames
+		LogicalVolumeName
+
+		MaximumLogicalVolumes
+		MaximumPhysicalVolumes
+		AllocationPolicy
+
+		CommonOptions
+	}
+	VGSplitOption interface {
+		ApplyToVGSplitOptions(opts *VGSplitOptions)
+	}
+	VGSplitOptionsList []VGSplitOption
+)
+
+// SetOldOrNew sets From to the first and To to the second volume group name.
+func (opts *VGSplitOptions) SetOldOrNew(name VolumeGroupName) {
+	if opts.From == "" {
+		opts.From = name
+	} else {
+		opts.To = name
+	}
+}
+
+var (
+	_ ArgumentGenerator = VGSplitOptionsList{}
+	_ Argument          = (*VGSplitOptions)(nil)
+)
+
+func (c *client) VGSplit(ctx context.Context, opts ...VGSplitOption) error {
+	args, err := VGSplitOptionsList(opts).AsArgs()
+	if err != nil {
+		return err
+	}
+
+	return c.RunLVM(ctx, append([]string{"vgsplit"}, args.GetRaw()...)...)
+}
+
+func (list VGSplitOptionsList) AsArgs() (Arguments, error) {
+	args := NewArgs(ArgsTypeGeneric)
+	options := VGSplitOptions{}
+	for _, opt := range list {
+		opt.ApplyToVGSplitOptions(&options)
+	}
+	if err := options.ApplyToArgs(args); err != nil {
+		return nil, err
+	}
+	return args, nil
+}
+
+func (opts *VGSplitOptions) ApplyToVGSplitOptions(new *VGSplitOptions) {
+	*new = *opts
+}
+
+func (opts *VGSplitOptions) ApplyToArgs(args Arguments) error {
+	if opts.From == "" {
+		return fmt.Errorf("from is empty: %w", ErrVolumeGroupNameRequired)
+	}
+	if opts.To == "" {
+		return fmt.Errorf("to is empty: %w", ErrVolumeGroupNameRequired)
+	}
+	if opts.From == opts.To {
+		return fmt.Errorf("cannot split volume group %s into itself", opts.From)
+	}
+	if len(opts.PhysicalVolumeNames) == 0 && opts.LogicalVolumeName == "" {
+		return fmt.Errorf("PhysicalVolumeNames or LogicalVolumeName must be specified")
+	} else if len(opts.PhysicalVolumeNames) > 0 && opts.LogicalVolumeName != "" {
+		return fmt.Errorf("PhysicalVolumeNames and LogicalVolumeName are mutually exclusive")
+	}
+
+	for _, arg := range []Argument{
+		opts.From,
+		opts.To,
+		opts.PhysicalVolumeNames,
+		opts.LogicalVolumeName,
+		opts.MaximumLogicalVolumes,
+		opts.MaximumPhysicalVolumes,
+		opts.AllocationPolicy,
+		opts.CommonOptions,
+	} {
+		if err := arg.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
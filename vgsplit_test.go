@@ -0,0 +1,70 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"slices"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestVGSplitArgs(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		opts     VGSplitOptionsList
+		expected []string
+		err      bool
+	}{
+		{
+			name:     "physical volumes",
+			opts:     VGSplitOptionsList{VolumeGroupName("vg"), VolumeGroupName("new"), PhysicalVolumeNames{"/dev/sdb", "/dev/sdc"}},
+			expected: []string{"vg", "new", "/dev/sdb", "/dev/sdc", "--yes"},
+		},
+		{
+			name:     "logical volume into new volume group",
+			opts:     VGSplitOptionsList{VolumeGroupName("vg"), VolumeGroupName("new"), LogicalVolumeName("lv"), MaximumLogicalVolumes(4)},
+			expected: []string{"vg", "new", "--name=lv", "--maxlogicalvolumes=4", "--yes"},
+		},
+		{name: "missing destination", opts: VGSplitOptionsList{VolumeGroupName("vg"), PhysicalVolumeName("/dev/sdb")}, err: true},
+		{name: "into itself", opts: VGSplitOptionsList{VolumeGroupName("vg"), VolumeGroupName("vg"), PhysicalVolumeName("/dev/sdb")}, err: true},
+		{name: "nothing to split", opts: VGSplitOptionsList{VolumeGroupName("vg"), VolumeGroupName("new")}, err: true},
+		{
+			name: "physical and logical volume",
+			opts: VGSplitOptionsList{VolumeGroupName("vg"), VolumeGroupName("new"), PhysicalVolumeName("/dev/sdb"), LogicalVolumeName("lv")},
+			err:  true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			args, err := tc.opts.AsArgs()
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got args %v", args.GetRaw())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(args.GetRaw(), tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, args.GetRaw())
+			}
+		})
+	}
+}
@@ -23,7 +23,6 @@ import (
 type VolumeGroup struct {
 	UUID     string          `json:"vg_uuid"`
 	Name     VolumeGroupName `json:"vg_name"`
-	SysID    string          `json:"vg_sysid"`
 	LockType string          `json:"vg_lock_type"`
 	LockArgs string          `json:"vg_lock_args"`
 	Attr     VGAttributes    `json:"vg_attr"`
@@ -49,6 +48,22 @@ type VolumeGroup struct {
 	MDAUsedCount     int64                    `json:"vg_mda_used_count"`
 	MDAFree          Size                     `json:"vg_mda_free"`
 	MDASize          Size                     `json:"vg_mda_size"`
+
+	// SysID is the system ID of the volume group under its original report field name, see SystemID.
+	SysID string `json:"vg_sysid"`
+	// SystemID is the system ID of the volume group, the host that owns it. See SetSystemIDSource.
+	SystemID string `json:"vg_systemid"`
+
+	// ExtraFields are the reported fields that are not covered by the struct, see ExtraColumns.
+	ExtraFields ExtraFields `json:"-"`
+
+	raw RawJSON
+}
+
+// RawJSON returns the raw JSON of the volume group as reported by lvm.
+// It is only retained by report calls with RetainRawJSON, otherwise it is nil.
+func (vg *VolumeGroup) RawJSON() RawJSON {
+	return vg.raw
 }
 
 func (vg *VolumeGroup) UnmarshalJSON(data []byte) error {
@@ -56,11 +71,13 @@ func (vg *VolumeGroup) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &raw); err != nil {
 		return err
 	}
+	vg.raw = rawJSON(data)
 
 	for key, fieldPtr := range map[string]*string{
 		"vg_uuid":              &vg.UUID,
 		"vg_name":              (*string)(&vg.Name),
 		"vg_sysid":             &vg.SysID,
+		"vg_systemid":          &vg.SystemID,
 		"vg_lock_type":         &vg.LockType,
 		"vg_lock_args":         &vg.LockArgs,
 		"vg_permissions":       &vg.Permissions,
@@ -71,7 +88,7 @@ func (vg *VolumeGroup) UnmarshalJSON(data []byte) error {
 		if val, ok := raw[key]; !ok {
 			continue
 		} else if err := json.Unmarshal(val, fieldPtr); err != nil {
-			return err
+			return newReportFieldError(key, val, err)
 		}
 	}
 
@@ -117,6 +134,42 @@ func (vg *VolumeGroup) UnmarshalJSON(data []byte) error {
 
 type VolumeGroupName string
 
+// VolumeGroupNames limit reports to the given volume groups.
+type VolumeGroupNames []VolumeGroupName
+
+func (opt VolumeGroupNames) ApplyToLVsOptions(opts *LVsOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToVGsOptions(opts *VGsOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToFullReportOptions(opts *FullReportOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToDrainNodeOptions(opts *DrainNodeOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToInterruptedOperationsOptions(opts *InterruptedOperationsOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToRecoverNodeOptions(opts *RecoverNodeOptions) {
+	opts.VolumeGroupNames = append(opts.VolumeGroupNames, opt...)
+}
+
+func (opt VolumeGroupNames) ApplyToArgs(args Arguments) error {
+	for _, name := range opt {
+		if err := name.ApplyToArgs(args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 var _ Argument = VolumeGroupName("")
 
 func (opt VolumeGroupName) ApplyToLVsOptions(opts *LVsOptions) {
@@ -129,12 +182,19 @@ func (opt VolumeGroupName) ApplyToLVChangeOptions(opts *LVChangeOptions) {
 	opts.VolumeGroupName = opt
 }
 
+func (opt VolumeGroupName) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.VolumeGroupName = opt
+}
+
 func (opt VolumeGroupName) ApplyToLVExtendOptions(opts *LVExtendOptions) {
 	opts.VolumeGroupName = opt
 }
 func (opt VolumeGroupName) ApplyToVGsOptions(opts *VGsOptions) {
 	opts.VolumeGroupName = opt
 }
+func (opt VolumeGroupName) ApplyToFullReportOptions(opts *FullReportOptions) {
+	opts.VolumeGroupName = opt
+}
 func (opt VolumeGroupName) ApplyToVGCreateOptions(opts *VGCreateOptions) {
 	opts.VolumeGroupName = opt
 }
@@ -150,6 +210,27 @@ func (opt VolumeGroupName) ApplyToVGExtendOptions(opts *VGExtendOptions) {
 func (opt VolumeGroupName) ApplyToVGRenameOptions(opts *VGRenameOptions) {
 	opts.SetOldOrNew(opt)
 }
+func (opt VolumeGroupName) ApplyToVGSplitOptions(opts *VGSplitOptions) {
+	opts.SetOldOrNew(opt)
+}
+func (opt VolumeGroupName) ApplyToVGMergeOptions(opts *VGMergeOptions) {
+	opts.SetOldOrNew(opt)
+}
+func (opt VolumeGroupName) ApplyToVGExportOptions(opts *VGExportOptions) {
+	opts.VolumeGroupName = opt
+}
+func (opt VolumeGroupName) ApplyToVGImportOptions(opts *VGImportOptions) {
+	opts.VolumeGroupName = opt
+}
+func (opt VolumeGroupName) ApplyToVGCfgBackupOptions(opts *VGCfgBackupOptions) {
+	opts.VolumeGroupName = opt
+}
+func (opt VolumeGroupName) ApplyToVGCfgRestoreOptions(opts *VGCfgRestoreOptions) {
+	opts.VolumeGroupName = opt
+}
+func (opt VolumeGroupName) ApplyToVGCheckOptions(opts *VGCheckOptions) {
+	opts.VolumeGroupName = opt
+}
 func (opt VolumeGroupName) ApplyToVGChangeOptions(opts *VGChangeOptions) {
 	opts.VolumeGroupName = opt
 }
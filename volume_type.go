@@ -52,3 +52,7 @@ func (opt Type) ApplyToArgs(args Arguments) error {
 func (opt Type) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Type = opt
 }
+
+func (opt Type) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Type = opt
+}
@@ -0,0 +1,97 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WrappedClient is a Client that decorates another Client, e.g. the client returned by NewLockingClient
+// or WithNoNsenter. By convention, every wrapper in lvm2go implements Unwrap so that callers and
+// other wrappers can introspect the decoration chain.
+//
+// Custom wrappers should implement Unwrap as well to participate in ClientImplements, ClientChain and BaseClient.
+type WrappedClient interface {
+	Client
+	// Unwrap returns the Client decorated by this Client.
+	Unwrap() Client
+}
+
+// UnwrapClient returns the Client decorated by client, or nil if client does not wrap another Client.
+func UnwrapClient(client Client) Client {
+	if wrapped, ok := client.(WrappedClient); ok {
+		return wrapped.Unwrap()
+	}
+	return nil
+}
+
+// ClientChain returns the decoration chain of client, starting with client itself and ending with
+// the innermost Client that does not wrap another Client.
+func ClientChain(client Client) []Client {
+	var chain []Client
+	for client != nil {
+		chain = append(chain, client)
+		client = UnwrapClient(client)
+	}
+	return chain
+}
+
+// BaseClient returns the innermost Client of the decoration chain of client.
+func BaseClient(client Client) Client {
+	chain := ClientChain(client)
+	if len(chain) == 0 {
+		return nil
+	}
+	return chain[len(chain)-1]
+}
+
+// ClientImplements walks the decoration chain of client and returns the first Client that implements T.
+// It can be used to detect wrappers or optional interfaces anywhere in the chain.
+//
+// The first implementation is the outermost one, which is the innermost Client for interfaces that the wrappers
+// do not implement, so calls through it bypass the context, locks and guards of the wrappers in front of it.
+// The wrappers of lvm2go therefore implement RawCommandRunner and forward raw commands with their context or lock,
+// custom wrappers should do the same for the interfaces they decorate.
+//
+// Example:
+//
+//	type lvmRunner interface {
+//		RunLVM(ctx context.Context, args ...string) error
+//	}
+//	if runner, ok := ClientImplements[lvmRunner](client); ok {
+//		err := runner.RunLVM(ctx, "vgscan")
+//	}
+func ClientImplements[T any](client Client) (T, bool) {
+	for _, c := range ClientChain(client) {
+		if t, ok := c.(T); ok {
+			return t, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// rawCommandRunner returns the RawCommandRunner of the decoration chain of client,
+// or an error wrapping errors.ErrUnsupported if no client of the chain runs raw commands.
+func rawCommandRunner(client Client) (RawCommandRunner, error) {
+	runner, ok := ClientImplements[RawCommandRunner](client)
+	if !ok {
+		return nil, fmt.Errorf("%w: client cannot run raw commands", errors.ErrUnsupported)
+	}
+	return runner, nil
+}
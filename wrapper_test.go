@@ -0,0 +1,82 @@
+/*
+ Copyright 2024 The lvm2go Authors.
+
+ Licensed under the Apache License, Version 2.0 (the "License");
+ you may not use this file except in compliance with the License.
+ You may obtain a copy of the License at
+
+     http://www.apache.org/licenses/LICENSE-2.0
+
+ Unless required by applicable law or agreed to in writing, software
+ distributed under the License is distributed on an "AS IS" BASIS,
+ WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ See the License for the specific language governing permissions and
+ limitations under the License.
+*/
+
+package lvm2go_test
+
+import (
+	"context"
+	"io"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+
+	. "github.com/azalio/lvm2go"
+)
+
+func TestClientChain(t *testing.T) {
+	t.Parallel()
+	base := NewClient()
+	noNsenter := WithNoNsenter(base)
+	locking := NewLockingClient(noNsenter)
+
+	chain := ClientChain(locking)
+	if len(chain) != 3 {
+		t.Fatalf("expected chain of length 3, got %d", len(chain))
+	}
+	if chain[0] != locking || chain[1] != noNsenter || chain[2] != base {
+		t.Fatalf("unexpected chain order: %v", chain)
+	}
+	if BaseClient(locking) != base {
+		t.Fatalf("expected base client to be the innermost client")
+	}
+	if UnwrapClient(base) != nil {
+		t.Fatalf("expected base client to not wrap another client")
+	}
+
+	type lvmRunner interface {
+		RunLVM(ctx context.Context, args ...string) error
+	}
+	if runner, ok := ClientImplements[lvmRunner](locking); !ok || runner.(Client) != base {
+		t.Fatalf("expected to find the base client as lvm runner in the chain")
+	}
+	if _, ok := ClientImplements[WrappedClient](base); ok {
+		t.Fatalf("expected base client to not implement WrappedClient")
+	}
+}
+
+func TestWrappersForwardRawCommands(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	var commands [][]string
+	base := NewClient(NsenterPolicyAlways, CommandRunner(func(ctx context.Context, cmd *exec.Cmd) (io.ReadCloser, error) {
+		commands = append(commands, cmd.Args)
+		return io.NopCloser(strings.NewReader("")), nil
+	}))
+	locking := NewLockingClient(WithNoNsenter(base))
+
+	runner, ok := ClientImplements[RawCommandRunner](locking)
+	if !ok || runner.(Client) != locking {
+		t.Fatalf("expected the outermost wrapper to run raw commands, got %T", runner)
+	}
+	if err := runner.RunRaw(ctx, NoOpRawOutputProcessor(), "dmsetup", "info"); err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(commands[0], []string{"dmsetup", "info"}) {
+		t.Fatalf("expected the raw command to run without nsenter, got %v", commands[0])
+	}
+}
@@ -31,6 +31,15 @@ func (opt Zero) ApplyToLVCreateOptions(opts *LVCreateOptions) {
 	opts.Zero = opt
 }
 
+func (opt Zero) ApplyToLVConvertOptions(opts *LVConvertOptions) {
+	opts.Zero = opt
+}
+
+// ApplyToLVChangeOptions toggles zeroing of newly provisioned blocks of a thin pool.
+func (opt Zero) ApplyToLVChangeOptions(opts *LVChangeOptions) {
+	opts.Zero = opt
+}
+
 func (opt Zero) ApplyToArgs(args Arguments) error {
 	if opt == "" {
 		return nil